@@ -49,6 +49,7 @@ type initResult struct {
 	EnableShellTool     bool
 	EnableFileReadTool  bool
 	EnableFileWriteTool bool
+	EnableHTTPFetchTool bool
 }
 
 func providerPresets() []providerPreset {
@@ -127,6 +128,7 @@ func runInit(_ *cobra.Command, _ []string) error {
 		EnableShellTool:     true,
 		EnableFileReadTool:  true,
 		EnableFileWriteTool: true,
+		EnableHTTPFetchTool: true,
 	}
 
 	// Form 1: Channel + Preset selection.
@@ -271,6 +273,9 @@ func buildToolGroup(r *initResult) *huh.Group {
 		huh.NewConfirm().
 			Title("Enable file write tool (write_file)?").
 			Value(&r.EnableFileWriteTool),
+		huh.NewConfirm().
+			Title("Enable web fetch tool (http_fetch)?").
+			Value(&r.EnableHTTPFetchTool),
 	).Title("Tool Modules")
 }
 
@@ -371,6 +376,9 @@ func generateYAML(r *initResult) []byte {
 	if r.EnableFileWriteTool {
 		modules["tool.file_write"] = map[string]interface{}{}
 	}
+	if r.EnableHTTPFetchTool {
+		modules["tool.http_fetch"] = map[string]interface{}{}
+	}
 
 	cfg := yamlConfig{
 		Version: "1",
@@ -9,15 +9,20 @@ import (
 	"github.com/flemzord/sclaw/internal/config"
 	"github.com/flemzord/sclaw/internal/core"
 	_ "github.com/flemzord/sclaw/internal/gateway"
+	"github.com/flemzord/sclaw/internal/security"
 	_ "github.com/flemzord/sclaw/modules/channel/telegram"
 	_ "github.com/flemzord/sclaw/modules/hook/metrics"
 	_ "github.com/flemzord/sclaw/modules/hook/tracing"
 	_ "github.com/flemzord/sclaw/modules/memory/sqlite"
 	_ "github.com/flemzord/sclaw/modules/provider/openai_compatible"
 	_ "github.com/flemzord/sclaw/modules/provider/openai_responses"
+	_ "github.com/flemzord/sclaw/modules/provider/router"
+	_ "github.com/flemzord/sclaw/modules/tool/code_interpreter"
 	_ "github.com/flemzord/sclaw/modules/tool/file_read"
 	_ "github.com/flemzord/sclaw/modules/tool/file_write"
+	_ "github.com/flemzord/sclaw/modules/tool/http_fetch"
 	_ "github.com/flemzord/sclaw/modules/tool/shell"
+	_ "github.com/flemzord/sclaw/modules/tool/web_search"
 	"github.com/flemzord/sclaw/pkg/app"
 	"github.com/spf13/cobra"
 )
@@ -39,6 +44,9 @@ func debugLogLevel(cmd *cobra.Command) slog.Level {
 }
 
 func main() {
+	// Sandboxed tool commands re-execute this binary as their init process.
+	security.RunSandboxInit()
+
 	if err := rootCmd().Execute(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
@@ -53,7 +61,7 @@ func rootCmd() *cobra.Command {
 		SilenceErrors: true,
 	}
 	root.PersistentFlags().Bool("debug", false, "Enable debug logging")
-	root.AddCommand(versionCmd(), startCmd(), configCmd(), initCmd(), serviceCmd())
+	root.AddCommand(versionCmd(), startCmd(), configCmd(), initCmd(), serviceCmd(), mcpCmd())
 	return root
 }
 
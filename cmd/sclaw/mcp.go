@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"os/signal"
+	"syscall"
+
+	"github.com/flemzord/sclaw/pkg/app"
+	"github.com/spf13/cobra"
+)
+
+func mcpCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "mcp",
+		Short: "Model Context Protocol server",
+	}
+	cmd.AddCommand(mcpServeCmd())
+	return cmd
+}
+
+func mcpServeCmd() *cobra.Command {
+	var cfgPath, agentID string
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Publish an agent's tools and memory as an MCP server on stdio",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+			defer cancel()
+
+			return app.ServeMCP(ctx, app.RunParams{
+				ConfigPath: cfgPath,
+				Version:    version,
+				Commit:     commit,
+				Date:       date,
+				LogLevel:   debugLogLevel(cmd),
+			}, agentID)
+		},
+	}
+	cmd.Flags().StringVarP(&cfgPath, "config", "c", "", "Path to configuration file")
+	cmd.Flags().StringVar(&agentID, "agent", "", "Agent to publish")
+	_ = cmd.MarkFlagRequired("agent")
+	return cmd
+}
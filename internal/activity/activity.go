@@ -0,0 +1,177 @@
+// Package activity broadcasts live events of the router and the components
+// it drives (agent resolution, tool calls, approvals, provider failover) to
+// observers such as the gateway event stream. Publishing never blocks: a
+// subscriber that falls behind loses events instead of slowing the router.
+package activity
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Type identifies the kind of an event.
+type Type string
+
+// Event types.
+const (
+	TypeMessageAccepted   Type = "message_accepted"   // inbound message passed the router's filters
+	TypeMessageDropped    Type = "message_dropped"    // inbound message dropped; Data["reason"] says why
+	TypeAgentResolved     Type = "agent_resolved"     // agent chosen for a new session; Data["rule"] says how
+	TypeToolCall          Type = "tool_call"          // tool call finished
+	TypeApprovalRequested Type = "approval_requested" // tool call awaiting user approval
+	TypeApprovalResolved  Type = "approval_resolved"  // approval answered or expired
+	TypeCompaction        Type = "compaction"         // session history trimmed
+	TypeProviderFailover  Type = "provider_failover"  // provider failed, next one tried
+)
+
+// Types lists every event type.
+var Types = []Type{
+	TypeMessageAccepted,
+	TypeMessageDropped,
+	TypeAgentResolved,
+	TypeToolCall,
+	TypeApprovalRequested,
+	TypeApprovalResolved,
+	TypeCompaction,
+	TypeProviderFailover,
+}
+
+// Event is a single activity event.
+type Event struct {
+	Time      time.Time      `json:"time"`
+	Type      Type           `json:"type"`
+	AgentID   string         `json:"agent_id,omitempty"`
+	SessionID string         `json:"session_id,omitempty"`
+	Channel   string         `json:"channel,omitempty"`
+	ChatID    string         `json:"chat_id,omitempty"`
+	Data      map[string]any `json:"data,omitempty"`
+}
+
+// Filter selects events. An empty field matches everything.
+type Filter struct {
+	Agents   []string
+	Sessions []string
+	Types    []Type
+}
+
+// Match reports whether e passes the filter.
+func (f Filter) Match(e Event) bool {
+	return (len(f.Agents) == 0 || slices.Contains(f.Agents, e.AgentID)) &&
+		(len(f.Sessions) == 0 || slices.Contains(f.Sessions, e.SessionID)) &&
+		(len(f.Types) == 0 || slices.Contains(f.Types, e.Type))
+}
+
+// Bus fans events out to subscribers. A nil *Bus is valid and discards
+// events, so that components can publish unconditionally.
+type Bus struct {
+	mu   sync.RWMutex
+	subs map[*Subscription]struct{}
+	now  func() time.Time
+}
+
+// NewBus creates a Bus with no subscribers.
+func NewBus() *Bus {
+	return &Bus{
+		subs: make(map[*Subscription]struct{}),
+		now:  time.Now,
+	}
+}
+
+// Publish delivers e to every subscriber whose filter matches. Missing
+// agent and session IDs are taken from ctx (see WithSession), and a zero
+// Time is set to now.
+func (b *Bus) Publish(ctx context.Context, e Event) {
+	if b == nil {
+		return
+	}
+	if e.Time.IsZero() {
+		e.Time = b.now()
+	}
+	if s, ok := ctx.Value(sessionKey{}).(session); ok {
+		if e.AgentID == "" {
+			e.AgentID = s.agentID
+		}
+		if e.SessionID == "" {
+			e.SessionID = s.sessionID
+		}
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for sub := range b.subs {
+		if !sub.filter.Match(e) {
+			continue
+		}
+		select {
+		case sub.ch <- e:
+		default:
+			sub.dropped.Add(1)
+		}
+	}
+}
+
+// Subscribe registers a subscriber receiving the events matching f on a
+// channel buffering up to buffer events. Callers must Close it when done.
+func (b *Bus) Subscribe(f Filter, buffer int) *Subscription {
+	sub := &Subscription{
+		bus:    b,
+		filter: f,
+		ch:     make(chan Event, max(buffer, 1)),
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subs[sub] = struct{}{}
+	return sub
+}
+
+// Subscribers returns the number of active subscriptions.
+func (b *Bus) Subscribers() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.subs)
+}
+
+// Subscription is a registration on a Bus.
+type Subscription struct {
+	bus     *Bus
+	filter  Filter
+	ch      chan Event
+	dropped atomic.Int64
+	once    sync.Once
+}
+
+// Events returns the channel events are delivered on. It is closed by Close.
+func (s *Subscription) Events() <-chan Event {
+	return s.ch
+}
+
+// Dropped returns the number of events lost because the buffer was full.
+func (s *Subscription) Dropped() int64 {
+	return s.dropped.Load()
+}
+
+// Close unregisters the subscription and closes its channel.
+func (s *Subscription) Close() {
+	s.once.Do(func() {
+		s.bus.mu.Lock()
+		delete(s.bus.subs, s)
+		close(s.ch)
+		s.bus.mu.Unlock()
+	})
+}
+
+type sessionKey struct{}
+
+type session struct {
+	agentID   string
+	sessionID string
+}
+
+// WithSession returns a context attributing the events published with it
+// to an agent and a session.
+func WithSession(ctx context.Context, agentID, sessionID string) context.Context {
+	return context.WithValue(ctx, sessionKey{}, session{agentID: agentID, sessionID: sessionID})
+}
@@ -0,0 +1,71 @@
+package activity
+
+import (
+	"context"
+	"testing"
+)
+
+func TestBus_PublishFiltered(t *testing.T) {
+	t.Parallel()
+
+	b := NewBus()
+	all := b.Subscribe(Filter{}, 10)
+	defer all.Close()
+	tools := b.Subscribe(Filter{Agents: []string{"main"}, Types: []Type{TypeToolCall}}, 10)
+	defer tools.Close()
+
+	ctx := WithSession(context.Background(), "main", "s1")
+	b.Publish(ctx, Event{Type: TypeToolCall, Data: map[string]any{"tool": "exec"}})
+	b.Publish(ctx, Event{Type: TypeCompaction})
+	b.Publish(context.Background(), Event{Type: TypeToolCall, AgentID: "other"})
+
+	if got := len(all.Events()); got != 3 {
+		t.Errorf("unfiltered subscriber got %d events, want 3", got)
+	}
+	if got := len(tools.Events()); got != 1 {
+		t.Fatalf("filtered subscriber got %d events, want 1", got)
+	}
+	e := <-tools.Events()
+	if e.AgentID != "main" || e.SessionID != "s1" || e.Time.IsZero() {
+		t.Errorf("event = %+v, want attributed to main/s1 with a time", e)
+	}
+}
+
+func TestBus_SlowSubscriberDrops(t *testing.T) {
+	t.Parallel()
+
+	b := NewBus()
+	sub := b.Subscribe(Filter{}, 1)
+	defer sub.Close()
+
+	for range 3 {
+		b.Publish(context.Background(), Event{Type: TypeMessageAccepted})
+	}
+	if sub.Dropped() != 2 {
+		t.Errorf("Dropped() = %d, want 2", sub.Dropped())
+	}
+}
+
+func TestBus_Close(t *testing.T) {
+	t.Parallel()
+
+	b := NewBus()
+	sub := b.Subscribe(Filter{}, 1)
+	sub.Close()
+	sub.Close() // idempotent
+
+	if b.Subscribers() != 0 {
+		t.Errorf("Subscribers() = %d, want 0", b.Subscribers())
+	}
+	if _, ok := <-sub.Events(); ok {
+		t.Error("Events() still open after Close")
+	}
+	b.Publish(context.Background(), Event{Type: TypeMessageAccepted}) // must not panic
+}
+
+func TestBus_NilDiscards(t *testing.T) {
+	t.Parallel()
+
+	var b *Bus
+	b.Publish(context.Background(), Event{Type: TypeMessageAccepted})
+}
@@ -29,6 +29,10 @@ type LoopConfig struct {
 	// ProviderName is the identifier of the provider module (e.g. "provider.openai_compatible").
 	// Propagated to Response.Provider for observability.
 	ProviderName string
+
+	// UsageObserver, when set, receives every provider request along with
+	// the usage reported for it (e.g. to calibrate token estimators).
+	UsageObserver UsageObserver
 }
 
 // withDefaults returns a copy with zero fields replaced by defaults.
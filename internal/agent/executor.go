@@ -6,6 +6,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/flemzord/sclaw/internal/activity"
 	"github.com/flemzord/sclaw/internal/provider"
 	"github.com/flemzord/sclaw/internal/security"
 	"github.com/flemzord/sclaw/internal/tool"
@@ -20,6 +21,9 @@ type ToolExecutorConfig struct {
 	Requester       tool.ApprovalRequester
 	ApprovalTimeout time.Duration
 	Env             tool.ExecutionEnv
+
+	// Activity, if non-nil, receives a tool_call event for every call.
+	Activity *activity.Bus
 }
 
 // ToolExecutor handles parallel tool execution with panic recovery.
@@ -31,6 +35,7 @@ type ToolExecutor struct {
 	requester       tool.ApprovalRequester
 	approvalTimeout time.Duration
 	env             tool.ExecutionEnv
+	activity        *activity.Bus
 }
 
 // NewToolExecutor creates a ToolExecutor from the given configuration.
@@ -43,6 +48,7 @@ func NewToolExecutor(cfg ToolExecutorConfig) *ToolExecutor {
 		requester:       cfg.Requester,
 		approvalTimeout: cfg.ApprovalTimeout,
 		env:             cfg.Env,
+		activity:        cfg.Activity,
 	}
 }
 
@@ -99,6 +105,16 @@ func (e *ToolExecutor) executeSingle(ctx context.Context, tc provider.ToolCall)
 				IsError: true,
 			}
 		}
+		e.activity.Publish(ctx, activity.Event{
+			Type: activity.TypeToolCall,
+			Data: map[string]any{
+				"tool":        record.Name,
+				"call_id":     record.ID,
+				"duration_ms": record.Duration.Milliseconds(),
+				"error":       record.Output.IsError,
+				"panicked":    record.Panicked,
+			},
+		})
 	}()
 
 	out, err := e.registry.Execute(
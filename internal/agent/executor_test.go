@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/flemzord/sclaw/internal/activity"
 	"github.com/flemzord/sclaw/internal/provider"
 	"github.com/flemzord/sclaw/internal/tool"
 )
@@ -267,3 +268,32 @@ func TestExecute_ParallelErrorIsolation(t *testing.T) {
 			results[2].Output.IsError, results[2].Output.Content)
 	}
 }
+
+func TestExecute_PublishesToolCalls(t *testing.T) {
+	t.Parallel()
+
+	bus := activity.NewBus()
+	sub := bus.Subscribe(activity.Filter{}, 4)
+	defer sub.Close()
+
+	reg := tool.NewRegistry()
+	if err := reg.Register(&mockTool{name: "echo", output: tool.Output{Content: "hello"}}); err != nil {
+		t.Fatal(err)
+	}
+	exec := NewToolExecutor(ToolExecutorConfig{
+		Registry:  reg,
+		PolicyCfg: tool.PolicyConfig{DM: tool.Policy{Default: tool.ApprovalAllow}},
+		PolicyCtx: tool.PolicyContextDM,
+		Activity:  bus,
+	})
+	ctx := activity.WithSession(context.Background(), "main", "s1")
+	exec.Execute(ctx, []provider.ToolCall{tc("c1", "echo")})
+
+	if len(sub.Events()) != 1 {
+		t.Fatalf("got %d events, want 1", len(sub.Events()))
+	}
+	e := <-sub.Events()
+	if e.Type != activity.TypeToolCall || e.SessionID != "s1" || e.Data["tool"] != "echo" || e.Data["error"] != false {
+		t.Errorf("event = %+v", e)
+	}
+}
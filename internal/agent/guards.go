@@ -60,6 +60,7 @@ func (t *tokenTracker) add(usage provider.TokenUsage) {
 	t.usage.PromptTokens += usage.PromptTokens
 	t.usage.CompletionTokens += usage.CompletionTokens
 	t.usage.TotalTokens += usage.TotalTokens
+	t.usage.CachedTokens += usage.CachedTokens
 }
 
 // exceeded reports whether the cumulative token usage has reached the budget.
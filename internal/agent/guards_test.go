@@ -46,7 +46,7 @@ func TestTokenTracker_Add(t *testing.T) {
 	tr := newTokenTracker(1000)
 
 	tr.add(provider.TokenUsage{PromptTokens: 100, CompletionTokens: 50, TotalTokens: 150})
-	tr.add(provider.TokenUsage{PromptTokens: 200, CompletionTokens: 100, TotalTokens: 300})
+	tr.add(provider.TokenUsage{PromptTokens: 200, CompletionTokens: 100, TotalTokens: 300, CachedTokens: 80})
 
 	got := tr.total()
 	if got.PromptTokens != 300 {
@@ -58,6 +58,9 @@ func TestTokenTracker_Add(t *testing.T) {
 	if got.TotalTokens != 450 {
 		t.Errorf("TotalTokens = %d, want 450", got.TotalTokens)
 	}
+	if got.CachedTokens != 80 {
+		t.Errorf("CachedTokens = %d, want 80", got.CachedTokens)
+	}
 }
 
 func TestTokenTracker_Exceeded(t *testing.T) {
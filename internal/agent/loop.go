@@ -59,13 +59,34 @@ func (l *Loop) AllowedDirs() []security.AllowedDir {
 	return l.executor.AllowedDirs()
 }
 
+// observeUsage reports provider-side usage to the configured observer.
+// model is the model the provider reported, if any.
+func (l *Loop) observeUsage(model string, req provider.CompletionRequest, usage provider.TokenUsage) {
+	if l.config.UsageObserver == nil || usage.PromptTokens <= 0 {
+		return
+	}
+	if model == "" {
+		model = l.provider.ModelName()
+	}
+	l.config.UsageObserver.ObserveUsage(model, req, usage)
+}
+
 // buildInitialMessages assembles the initial message history from the request.
 func buildInitialMessages(req Request) []provider.LLMMessage {
 	var messages []provider.LLMMessage
 	if req.SystemPrompt != "" {
+		// The stable system prompt closes the cacheable prefix (tools are
+		// sent ahead of it), so mark it as a cache breakpoint.
+		messages = append(messages, provider.LLMMessage{
+			Role:         provider.MessageRoleSystem,
+			Content:      req.SystemPrompt,
+			CacheControl: provider.CacheControlEphemeral,
+		})
+	}
+	if req.VolatileSystemPrompt != "" {
 		messages = append(messages, provider.LLMMessage{
 			Role:    provider.MessageRoleSystem,
-			Content: req.SystemPrompt,
+			Content: req.VolatileSystemPrompt,
 		})
 	}
 	return append(messages, req.Messages...)
@@ -113,9 +134,14 @@ func emitStreamEvent(ctx context.Context, ch chan<- StreamEvent, event StreamEve
 	}
 }
 
-// enrichResponse sets the Model and Provider fields from the loop's provider.
-func (l *Loop) enrichResponse(r *Response) {
-	r.Model = l.provider.ModelName()
+// enrichResponse sets the Model and Provider fields. model is the model
+// reported by the last provider response; when empty, the provider's
+// configured model name is used.
+func (l *Loop) enrichResponse(r *Response, model string) {
+	if model == "" {
+		model = l.provider.ModelName()
+	}
+	r.Model = model
 	r.Provider = l.config.ProviderName
 }
 
@@ -124,7 +150,9 @@ func (l *Loop) enrichResponse(r *Response) {
 // A context.WithTimeout is applied using l.config.Timeout. If the caller's
 // context already carries a shorter deadline, the shorter one takes effect.
 func (l *Loop) Run(ctx context.Context, req Request) (resp Response, err error) {
-	defer l.enrichResponse(&resp)
+	// servedBy tracks the model reported by the provider for the last call.
+	var servedBy string
+	defer func() { l.enrichResponse(&resp, servedBy) }()
 
 	ctx, cancel := context.WithTimeout(ctx, l.config.Timeout)
 	defer cancel()
@@ -151,10 +179,12 @@ func (l *Loop) Run(ctx context.Context, req Request) (resp Response, err error)
 		}
 
 		// Call provider.
-		resp, err := l.provider.Complete(ctx, provider.CompletionRequest{
+		creq := provider.CompletionRequest{
 			Messages: messages,
 			Tools:    req.Tools,
-		})
+			CacheKey: req.CacheKey,
+		}
+		resp, err := l.provider.Complete(ctx, creq)
 		if err != nil {
 			return Response{
 				ToolCalls:  allToolCalls,
@@ -164,7 +194,11 @@ func (l *Loop) Run(ctx context.Context, req Request) (resp Response, err error)
 			}, err
 		}
 
+		if resp.Model != "" {
+			servedBy = resp.Model
+		}
 		tracker.add(resp.Usage)
+		l.observeUsage(resp.Model, creq, resp.Usage)
 		if tracker.exceeded() {
 			return Response{
 				ToolCalls:  allToolCalls,
@@ -238,6 +272,7 @@ func (l *Loop) RunStream(ctx context.Context, req Request) (<-chan StreamEvent,
 		tracker := newTokenTracker(l.config.TokenBudget)
 		messages := buildInitialMessages(req)
 		var allToolCalls []ToolCallRecord
+		var servedBy string
 
 		for i := 0; i < l.config.MaxIterations; i++ {
 			if err := ctx.Err(); err != nil {
@@ -251,10 +286,12 @@ func (l *Loop) RunStream(ctx context.Context, req Request) (<-chan StreamEvent,
 				return
 			}
 
-			streamCh, err := l.provider.Stream(ctx, provider.CompletionRequest{
+			creq := provider.CompletionRequest{
 				Messages: messages,
 				Tools:    req.Tools,
-			})
+				CacheKey: req.CacheKey,
+			}
+			streamCh, err := l.provider.Stream(ctx, creq)
 			if err != nil {
 				emitStreamEvent(ctx, ch, StreamEvent{Type: StreamEventError, Err: err})
 				return
@@ -283,6 +320,9 @@ func (l *Loop) RunStream(ctx context.Context, req Request) (<-chan StreamEvent,
 				if chunk.Usage != nil {
 					usage = chunk.Usage
 				}
+				if chunk.Model != "" {
+					servedBy = chunk.Model
+				}
 			}
 
 			// Drain remaining chunks to prevent provider goroutine leak.
@@ -296,6 +336,7 @@ func (l *Loop) RunStream(ctx context.Context, req Request) (<-chan StreamEvent,
 
 			if usage != nil {
 				tracker.add(*usage)
+				l.observeUsage(servedBy, creq, *usage)
 				if !emitStreamEvent(ctx, ch, StreamEvent{Type: StreamEventUsage, Usage: usage}) {
 					return
 				}
@@ -315,7 +356,7 @@ func (l *Loop) RunStream(ctx context.Context, req Request) (<-chan StreamEvent,
 					Iterations: i + 1,
 					StopReason: StopReasonComplete,
 				}
-				l.enrichResponse(&final)
+				l.enrichResponse(&final, servedBy)
 				emitStreamEvent(ctx, ch, StreamEvent{Type: StreamEventDone, Final: &final})
 				return
 			}
@@ -146,6 +146,48 @@ func TestRun_SystemPrompt(t *testing.T) {
 	if msgs[0].Content != "You are a strict reviewer." {
 		t.Fatalf("unexpected system prompt content %q", msgs[0].Content)
 	}
+	if msgs[0].CacheControl != provider.CacheControlEphemeral {
+		t.Errorf("expected stable system prompt to carry a cache breakpoint, got %q", msgs[0].CacheControl)
+	}
+}
+
+func TestRun_VolatileSystemPromptAfterStablePrefix(t *testing.T) {
+	t.Parallel()
+
+	p := &mockProvider{
+		responses: []provider.CompletionResponse{
+			{Content: "ok", FinishReason: provider.FinishReasonStop},
+		},
+	}
+	executor := newLoopTestExecutor()
+	loop := NewLoop(p, executor, LoopConfig{MaxIterations: 5})
+
+	_, err := loop.Run(context.Background(), Request{
+		SystemPrompt:         "stable",
+		VolatileSystemPrompt: "volatile",
+		CacheKey:             "agent:bot",
+		Messages:             []provider.LLMMessage{userMsg("hello")},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	req := p.completeReqs[0]
+	if req.CacheKey != "agent:bot" {
+		t.Errorf("CacheKey = %q, want %q", req.CacheKey, "agent:bot")
+	}
+	if len(req.Messages) != 3 {
+		t.Fatalf("expected 3 messages, got %d", len(req.Messages))
+	}
+	if req.Messages[0].Content != "stable" || req.Messages[1].Content != "volatile" {
+		t.Fatalf("unexpected system message order: %q, %q", req.Messages[0].Content, req.Messages[1].Content)
+	}
+	if req.Messages[1].CacheControl != "" {
+		t.Errorf("volatile system message must not carry a cache breakpoint")
+	}
 }
 
 func TestRun_HistoryIncludesAssistantToolCallsAndToolError(t *testing.T) {
@@ -928,3 +970,93 @@ func TestRunStream_Timeout(t *testing.T) {
 		t.Error("expected StreamEventError with context.Canceled")
 	}
 }
+
+type recordingUsageObserver struct {
+	mu     sync.Mutex
+	models []string
+	usages []provider.TokenUsage
+}
+
+func (o *recordingUsageObserver) ObserveUsage(model string, _ provider.CompletionRequest, usage provider.TokenUsage) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.models = append(o.models, model)
+	o.usages = append(o.usages, usage)
+}
+
+func TestRun_UsageObserver(t *testing.T) {
+	t.Parallel()
+
+	p := &mockProvider{
+		responses: []provider.CompletionResponse{
+			{Content: "ok", FinishReason: provider.FinishReasonStop, Usage: provider.TokenUsage{PromptTokens: 42, TotalTokens: 50}, Model: "served-model"},
+		},
+	}
+	obs := &recordingUsageObserver{}
+	loop := NewLoop(p, newLoopTestExecutor(), LoopConfig{MaxIterations: 5, UsageObserver: obs})
+
+	if _, err := loop.Run(context.Background(), Request{Messages: []provider.LLMMessage{userMsg("hello")}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	obs.mu.Lock()
+	defer obs.mu.Unlock()
+	if len(obs.usages) != 1 || obs.usages[0].PromptTokens != 42 {
+		t.Errorf("observed usages = %+v, want one with 42 prompt tokens", obs.usages)
+	}
+	if len(obs.models) != 1 || obs.models[0] != "served-model" {
+		t.Errorf("observed models = %v, want [served-model]", obs.models)
+	}
+}
+
+// TestRun_ModelFromResponse: a serving model reported by the provider
+// (e.g. a routing provider) takes precedence over ModelName.
+func TestRun_ModelFromResponse(t *testing.T) {
+	t.Parallel()
+
+	p := &mockProvider{
+		responses: []provider.CompletionResponse{
+			{Content: "ok", FinishReason: provider.FinishReasonStop, Model: "routed-model"},
+		},
+	}
+	loop := NewLoop(p, newLoopTestExecutor(), LoopConfig{MaxIterations: 5})
+
+	resp, err := loop.Run(context.Background(), Request{Messages: []provider.LLMMessage{userMsg("hi")}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Model != "routed-model" {
+		t.Errorf("Model = %q, want routed-model", resp.Model)
+	}
+}
+
+func TestRunStream_ModelFromChunks(t *testing.T) {
+	t.Parallel()
+
+	p := &mockProvider{
+		streams: [][]provider.StreamChunk{
+			{
+				{Content: "ok", Model: "routed-model"},
+				{FinishReason: provider.FinishReasonStop, Model: "routed-model"},
+			},
+		},
+	}
+	loop := NewLoop(p, newLoopTestExecutor(), LoopConfig{MaxIterations: 5, Timeout: 10 * time.Second})
+
+	ch, err := loop.RunStream(context.Background(), Request{Messages: []provider.LLMMessage{userMsg("hi")}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var final *Response
+	for e := range ch {
+		if e.Type == StreamEventDone {
+			final = e.Final
+		}
+	}
+	if final == nil {
+		t.Fatal("expected StreamEventDone")
+	}
+	if final.Model != "routed-model" {
+		t.Errorf("Model = %q, want routed-model", final.Model)
+	}
+}
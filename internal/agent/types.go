@@ -64,6 +64,21 @@ type Request struct {
 	SystemPrompt string
 	Tools        []provider.ToolDefinition
 	Config       LoopConfig
+
+	// VolatileSystemPrompt holds per-turn system content (triggered skills,
+	// memory facts). It is sent as a separate system message after
+	// SystemPrompt so the stable prefix stays cacheable across turns.
+	VolatileSystemPrompt string
+
+	// CacheKey is forwarded to the provider as a prompt cache routing hint.
+	CacheKey string
+}
+
+// UsageObserver is notified of the token usage reported by the provider for
+// each completion request issued by the loop, along with the model that
+// served it.
+type UsageObserver interface {
+	ObserveUsage(model string, req provider.CompletionRequest, usage provider.TokenUsage)
 }
 
 // Response is the output of the agent loop.
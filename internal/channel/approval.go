@@ -0,0 +1,61 @@
+package channel
+
+import (
+	"context"
+	"time"
+
+	"github.com/flemzord/sclaw/pkg/message"
+)
+
+// ApprovalPrompt is a tool approval request presented to a user.
+type ApprovalPrompt struct {
+	// ID identifies the request; answers must carry it back.
+	ID string
+
+	// ToolName and Description describe the tool asking for approval.
+	ToolName    string
+	Description string
+
+	// Arguments is a display form of the tool arguments.
+	Arguments string
+
+	// Timeout is how long the user has to answer before the request is
+	// denied by default.
+	Timeout time.Duration
+
+	// OfferSession offers an "allow for this session" choice.
+	OfferSession bool
+
+	// Pattern, when non-empty, offers an "always allow" choice for calls
+	// whose arguments match it.
+	Pattern string
+
+	// Approvers, when non-empty, lists the only sender IDs allowed to
+	// answer. Channels may use it to reject other users early.
+	Approvers []string
+}
+
+// ApprovalAnswer is the JSON payload a channel sets as InboundMessage.Raw
+// when a user answers an ApprovalPrompt natively (e.g. with a button).
+// The router resolves it directly, without queueing it as a message.
+type ApprovalAnswer struct {
+	ApprovalID string `json:"approval_id"`
+	Approved   bool   `json:"approved"`
+	Scope      string `json:"scope,omitempty"`
+	Reason     string `json:"reason,omitempty"`
+}
+
+// ApprovalChannel is implemented by channels that can render approval
+// prompts natively (inline buttons, reactions, ...). Channels without it
+// receive a plain-text prompt answered with "approve <id>" / "deny <id>".
+type ApprovalChannel interface {
+	Channel
+
+	// SendApprovalPrompt presents prompt in the chat addressed by msg.
+	SendApprovalPrompt(ctx context.Context, msg message.OutboundMessage, prompt ApprovalPrompt) error
+
+	// CloseApprovalPrompt is called once the request is answered or has
+	// expired, with a short human-readable outcome. Channels use it to
+	// disable the prompt's controls and release any per-prompt state.
+	CloseApprovalPrompt(ctx context.Context, id, outcome string) error
+}
@@ -110,16 +110,19 @@ type RateLimitConfig struct {
 	MessagesPerMin  int `yaml:"messages_per_min"`
 	ToolCallsPerMin int `yaml:"tool_calls_per_min"`
 	TokensPerHour   int `yaml:"tokens_per_hour"`
+	NetworkPerMin   int `yaml:"network_per_min"`
 }
 
 // SandboxConfig holds sandbox settings.
 type SandboxConfig struct {
 	Enabled                bool     `yaml:"enabled"`
 	ScopesRequiringSandbox []string `yaml:"scopes_requiring_sandbox,omitempty"`
+	Backend                string   `yaml:"backend,omitempty"` // "docker" (default) or "linux"
 	Image                  string   `yaml:"image,omitempty"`
 	CPUShares              int      `yaml:"cpu_shares,omitempty"`
 	MemoryMB               int      `yaml:"memory_mb,omitempty"`
 	DiskMB                 int      `yaml:"disk_mb,omitempty"`
+	CgroupParent           string   `yaml:"cgroup_parent,omitempty"` // linux backend only
 }
 
 // URLFilterConfig holds URL filtering settings.
@@ -4,6 +4,7 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"path/filepath"
 	"slices"
 
 	"github.com/flemzord/sclaw/internal/core"
@@ -80,6 +81,19 @@ func validateSecurity(sec *SecurityConfig) []error {
 		}
 	}
 
+	switch sec.Sandbox.Backend {
+	case "", "docker", "linux":
+		// valid
+	default:
+		errs = append(errs, fmt.Errorf(
+			"config: security.sandbox.backend: unsupported value %q (supported: \"docker\", \"linux\")",
+			sec.Sandbox.Backend,
+		))
+	}
+	if sec.Sandbox.CgroupParent != "" && !filepath.IsAbs(sec.Sandbox.CgroupParent) {
+		errs = append(errs, fmt.Errorf("config: security.sandbox.cgroup_parent: must be an absolute path, got %q", sec.Sandbox.CgroupParent))
+	}
+
 	return errs
 }
 
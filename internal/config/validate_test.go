@@ -351,3 +351,38 @@ memory:
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
+
+func TestValidate_SandboxBackend(t *testing.T) {
+	id := t.Name() + ".mod"
+	registerStub(t, id)
+
+	tests := []struct {
+		name    string
+		sandbox SandboxConfig
+		want    string
+	}{
+		{"docker", SandboxConfig{Backend: "docker"}, ""},
+		{"linux with cgroup", SandboxConfig{Backend: "linux", CgroupParent: "/sys/fs/cgroup/sclaw"}, ""},
+		{"unknown backend", SandboxConfig{Backend: "firecracker"}, "security.sandbox.backend"},
+		{"relative cgroup", SandboxConfig{Backend: "linux", CgroupParent: "sclaw"}, "security.sandbox.cgroup_parent"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				Version:  "1",
+				Modules:  map[string]yaml.Node{id: {}},
+				Security: &SecurityConfig{Sandbox: tt.sandbox},
+			}
+			err := Validate(cfg)
+			if tt.want == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want mention of %s", err, tt.want)
+			}
+		})
+	}
+}
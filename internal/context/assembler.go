@@ -13,9 +13,15 @@ type AssemblyRequest struct {
 	// WindowSize is the provider's context window in tokens.
 	WindowSize int
 
-	// SystemParts are the components of the system prompt (SOUL.md, skills, etc.).
+	// SystemParts are the stable components of the system prompt (SOUL.md,
+	// always-on skills, workspace instructions). They form the cacheable
+	// prefix and should not change between turns.
 	SystemParts []string
 
+	// VolatileParts are per-turn system prompt components (triggered skills)
+	// placed after the stable prefix.
+	VolatileParts []string
+
 	// Tools are the tool definitions available to the model.
 	Tools []provider.ToolDefinition
 
@@ -31,6 +37,13 @@ type AssemblyResult struct {
 	// SystemPrompt is the fully assembled system prompt.
 	SystemPrompt string
 
+	// StablePrompt is the cacheable prefix of SystemPrompt (SystemParts only).
+	StablePrompt string
+
+	// VolatilePrompt is the remainder of SystemPrompt: volatile parts
+	// followed by memory facts.
+	VolatilePrompt string
+
 	// Messages is the (possibly compacted) conversation history.
 	Messages []provider.LLMMessage
 
@@ -68,6 +81,10 @@ func (a *ContextAssembler) SetCompactor(c *Compactor) {
 
 // Assemble builds the agent context from the given inputs.
 //
+// Sections are ordered for prompt caching: stable system parts first, then
+// volatile parts, then memory facts. Tool definitions are sent alongside the
+// stable prefix and are expected to be identical from turn to turn.
+//
 // The assembly process:
 //  1. Compute fixed costs (system prompt, tools, reserved)
 //  2. Trigger proactive compaction if history exceeds threshold
@@ -79,10 +96,13 @@ func (a *ContextAssembler) Assemble(ctx context.Context, req AssemblyRequest) (A
 		windowSize = a.config.MaxContextTokens
 	}
 
-	// Build system prompt from parts.
-	baseSystemPrompt := strings.Join(req.SystemParts, "\n\n")
-	systemPrompt := baseSystemPrompt
-	// Append memory facts to the system prompt, respecting MaxMemoryFacts.
+	// Build system prompt from parts: stable prefix first, volatile after.
+	stablePrompt := joinParts(req.SystemParts)
+	volatilePrompt := joinParts(req.VolatileParts)
+	baseSystemPrompt := joinParts([]string{stablePrompt, volatilePrompt})
+
+	// Append memory facts last, respecting MaxMemoryFacts: they change on
+	// every turn and would otherwise invalidate the cached prefix.
 	facts := req.MemoryFacts
 	if a.config.MaxMemoryFacts > 0 && len(facts) > a.config.MaxMemoryFacts {
 		facts = facts[:a.config.MaxMemoryFacts]
@@ -92,13 +112,9 @@ func (a *ContextAssembler) Assemble(ctx context.Context, req AssemblyRequest) (A
 		facts, memoryTokens = a.limitMemoryFactsByTokens(facts, a.config.MaxMemoryTokens)
 	}
 	if len(facts) > 0 {
-		memorySection := formatMemoryFacts(facts)
-		if systemPrompt == "" {
-			systemPrompt = memorySection
-		} else {
-			systemPrompt = systemPrompt + "\n\n" + memorySection
-		}
+		volatilePrompt = joinParts([]string{volatilePrompt, formatMemoryFacts(facts)})
 	}
+	systemPrompt := joinParts([]string{stablePrompt, volatilePrompt})
 
 	// Compute fixed costs.
 	systemTokens := a.estimator.Estimate(baseSystemPrompt)
@@ -137,14 +153,27 @@ func (a *ContextAssembler) Assemble(ctx context.Context, req AssemblyRequest) (A
 	budget.History = EstimateMessages(a.estimator, history)
 
 	return AssemblyResult{
-		SystemPrompt: systemPrompt,
-		Messages:     history,
-		Tools:        req.Tools,
-		Budget:       budget,
-		Compacted:    compacted,
+		SystemPrompt:   systemPrompt,
+		StablePrompt:   stablePrompt,
+		VolatilePrompt: volatilePrompt,
+		Messages:       history,
+		Tools:          req.Tools,
+		Budget:         budget,
+		Compacted:      compacted,
 	}, nil
 }
 
+// joinParts joins non-empty prompt parts with blank lines.
+func joinParts(parts []string) string {
+	nonEmpty := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if p != "" {
+			nonEmpty = append(nonEmpty, p)
+		}
+	}
+	return strings.Join(nonEmpty, "\n\n")
+}
+
 func (a *ContextAssembler) limitMemoryFactsByTokens(facts []string, maxTokens int) ([]string, int) {
 	if len(facts) == 0 {
 		return nil, 0
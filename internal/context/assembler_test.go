@@ -125,6 +125,40 @@ func TestContextAssembler_Assemble_WithMemoryFacts(t *testing.T) {
 	}
 }
 
+func TestContextAssembler_Assemble_StablePrefixBeforeVolatile(t *testing.T) {
+	t.Parallel()
+
+	estimator := ctxengine.NewCharEstimator(4.0)
+	assembler := ctxengine.NewContextAssembler(estimator, ctxengine.ContextConfig{MaxContextTokens: 10000})
+
+	req := ctxengine.AssemblyRequest{
+		SystemParts:   []string{"SOUL", "always-skill"},
+		VolatileParts: []string{"triggered-skill"},
+		MemoryFacts:   []string{"User likes Go"},
+	}
+
+	result, err := assembler.Assemble(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.StablePrompt != "SOUL\n\nalways-skill" {
+		t.Errorf("StablePrompt = %q", result.StablePrompt)
+	}
+	if strings.Contains(result.StablePrompt, "User likes Go") || strings.Contains(result.StablePrompt, "triggered-skill") {
+		t.Errorf("StablePrompt must not contain volatile content, got %q", result.StablePrompt)
+	}
+	if !strings.HasPrefix(result.VolatilePrompt, "triggered-skill") {
+		t.Errorf("VolatilePrompt should start with volatile parts, got %q", result.VolatilePrompt)
+	}
+	if !strings.HasPrefix(result.SystemPrompt, result.StablePrompt) {
+		t.Errorf("SystemPrompt should start with the stable prefix, got %q", result.SystemPrompt)
+	}
+	if !strings.HasSuffix(strings.TrimSpace(result.SystemPrompt), "- User likes Go") {
+		t.Errorf("memory facts should come last, got %q", result.SystemPrompt)
+	}
+}
+
 func TestContextAssembler_Assemble_MaxMemoryFacts(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,181 @@
+package ctxengine
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Pre-tokenization patterns used by the OpenAI BPE encodings. Go's regexp
+// does not support the `\s+(?!\S)` lookahead alternative, so it is removed
+// here and emulated in splitPieces.
+const (
+	cl100kPattern = `(?i:'s|'t|'re|'ve|'m|'ll|'d)|[^\r\n\p{L}\p{N}]?\p{L}+|\p{N}{1,3}| ?[^\s\p{L}\p{N}]+[\r\n]*|\s*[\r\n]+|\s+`
+
+	o200kPattern = `[^\r\n\p{L}\p{N}]?[\p{Lu}\p{Lt}\p{Lm}\p{Lo}\p{M}]*[\p{Ll}\p{Lm}\p{Lo}\p{M}]+(?i:'s|'t|'re|'ve|'m|'ll|'d)?` +
+		`|[^\r\n\p{L}\p{N}]?[\p{Lu}\p{Lt}\p{Lm}\p{Lo}\p{M}]+[\p{Ll}\p{Lm}\p{Lo}\p{M}]*(?i:'s|'t|'re|'ve|'m|'ll|'d)?` +
+		`|\p{N}{1,3}| ?[^\s\p{L}\p{N}]+[\r\n/]*|\s*[\r\n]+|\s+`
+)
+
+// maxPieceCacheEntries bounds the per-estimator cache of piece token counts.
+const maxPieceCacheEntries = 16384
+
+// BPEEstimator counts tokens with a byte-level BPE vocabulary in the
+// tiktoken format. It is safe for concurrent use.
+type BPEEstimator struct {
+	ranks   map[string]int
+	pattern *regexp.Regexp
+
+	mu    sync.Mutex
+	cache map[string]int
+}
+
+// NewBPEEstimator creates a BPEEstimator from mergeable ranks and a
+// pre-tokenization pattern.
+func NewBPEEstimator(ranks map[string]int, pattern *regexp.Regexp) *BPEEstimator {
+	return &BPEEstimator{
+		ranks:   ranks,
+		pattern: pattern,
+		cache:   make(map[string]int),
+	}
+}
+
+// Estimate returns the number of BPE tokens in text.
+func (e *BPEEstimator) Estimate(text string) int {
+	if text == "" {
+		return 0
+	}
+	total := 0
+	for _, piece := range splitPieces(e.pattern, text) {
+		total += e.countPiece(piece)
+	}
+	return total
+}
+
+// countPiece returns the token count of a single pre-tokenized piece.
+func (e *BPEEstimator) countPiece(piece string) int {
+	if _, ok := e.ranks[piece]; ok {
+		return 1
+	}
+
+	e.mu.Lock()
+	n, ok := e.cache[piece]
+	e.mu.Unlock()
+	if ok {
+		return n
+	}
+
+	n = len(bytePairMerge([]byte(piece), e.ranks))
+
+	e.mu.Lock()
+	if len(e.cache) >= maxPieceCacheEntries {
+		clear(e.cache)
+	}
+	e.cache[piece] = n
+	e.mu.Unlock()
+	return n
+}
+
+// bytePairMerge repeatedly merges the adjacent pair with the lowest rank
+// until no mergeable pair remains, and returns the resulting part boundaries.
+func bytePairMerge(piece []byte, ranks map[string]int) []int {
+	// bounds[i] is the start offset of part i; the final entry is len(piece).
+	bounds := make([]int, len(piece)+1)
+	for i := range bounds {
+		bounds[i] = i
+	}
+
+	for len(bounds) > 2 {
+		best, bestRank := -1, int(^uint(0)>>1)
+		for i := 0; i < len(bounds)-2; i++ {
+			if r, ok := ranks[string(piece[bounds[i]:bounds[i+2]])]; ok && r < bestRank {
+				best, bestRank = i, r
+			}
+		}
+		if best < 0 {
+			break
+		}
+		bounds = append(bounds[:best+1], bounds[best+2:]...)
+	}
+	return bounds[:len(bounds)-1]
+}
+
+// splitPieces pre-tokenizes text using pattern, emulating the `\s+(?!\S)`
+// alternative: a trailing whitespace run followed by a non-space character
+// gives its last character to the next piece.
+func splitPieces(pattern *regexp.Regexp, text string) []string {
+	var pieces []string
+	for pos := 0; pos < len(text); {
+		loc := pattern.FindStringIndex(text[pos:])
+		if loc == nil || loc[1] == 0 {
+			// Should not happen with the built-in patterns; consume one rune.
+			_, size := utf8.DecodeRuneInString(text[pos:])
+			pieces = append(pieces, text[pos:pos+size])
+			pos += size
+			continue
+		}
+		start, end := pos+loc[0], pos+loc[1]
+		if start > pos {
+			pieces = append(pieces, text[pos:start])
+		}
+		if end < len(text) && isSpaceRun(text[start:end]) {
+			last, size := utf8.DecodeLastRuneInString(text[start:end])
+			next, _ := utf8.DecodeRuneInString(text[end:])
+			if last != '\r' && last != '\n' && !unicode.IsSpace(next) && end-size > start {
+				end -= size
+			}
+		}
+		pieces = append(pieces, text[start:end])
+		pos = end
+	}
+	return pieces
+}
+
+// isSpaceRun reports whether s consists only of whitespace.
+func isSpaceRun(s string) bool {
+	for _, r := range s {
+		if !unicode.IsSpace(r) {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// LoadTiktokenRanks parses mergeable ranks in the tiktoken file format:
+// one base64-encoded token and its rank per line.
+func LoadTiktokenRanks(r io.Reader) (map[string]int, error) {
+	ranks := make(map[string]int)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		fields := bytes.Fields(scanner.Bytes())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("ctxengine: tiktoken line %d: expected 2 fields, got %d", line, len(fields))
+		}
+		token, err := base64.StdEncoding.DecodeString(string(fields[0]))
+		if err != nil {
+			return nil, fmt.Errorf("ctxengine: tiktoken line %d: %w", line, err)
+		}
+		rank, err := strconv.Atoi(string(fields[1]))
+		if err != nil {
+			return nil, fmt.Errorf("ctxengine: tiktoken line %d: %w", line, err)
+		}
+		ranks[string(token)] = rank
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("ctxengine: reading tiktoken ranks: %w", err)
+	}
+	return ranks, nil
+}
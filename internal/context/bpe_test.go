@@ -0,0 +1,113 @@
+package ctxengine
+
+import (
+	"encoding/base64"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestSplitPieces_CL100k(t *testing.T) {
+	t.Parallel()
+
+	re := regexp.MustCompile(cl100kPattern)
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"hello world", []string{"hello", " world"}},
+		{"  hello", []string{" ", " hello"}},
+		{"a  \n\nb", []string{"a", "  \n\n", "b"}},
+		{"1234567", []string{"123", "456", "7"}},
+		{"don't", []string{"don", "'t"}},
+		{"x   ", []string{"x", "   "}},
+		{"foo(bar);", []string{"foo", "(bar", ");"}},
+	}
+	for _, tt := range tests {
+		got := splitPieces(re, tt.in)
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("splitPieces(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+		if strings.Join(got, "") != tt.in {
+			t.Errorf("splitPieces(%q) lost content: %q", tt.in, got)
+		}
+	}
+}
+
+func TestSplitPieces_O200kCaseAware(t *testing.T) {
+	t.Parallel()
+
+	re := regexp.MustCompile(o200kPattern)
+	got := splitPieces(re, "HelloWorld path/to")
+	want := []string{"Hello", "World", " path", "/to"}
+	if !slices.Equal(got, want) {
+		t.Errorf("splitPieces = %q, want %q", got, want)
+	}
+}
+
+func testRanks() map[string]int {
+	ranks := make(map[string]int, 258)
+	for b := range 256 {
+		ranks[string([]byte{byte(b)})] = b
+	}
+	ranks["ab"] = 256
+	ranks["abc"] = 257
+	return ranks
+}
+
+func TestBPEEstimator_Merges(t *testing.T) {
+	t.Parallel()
+
+	est := NewBPEEstimator(testRanks(), regexp.MustCompile(cl100kPattern))
+	tests := []struct {
+		in   string
+		want int
+	}{
+		{"", 0},
+		{"abc", 1},
+		{"abd", 2},
+		{"abcabc", 2},
+		{"abc abd", 4}, // "abc" | " abd" → " ", "ab", "d"
+	}
+	for _, tt := range tests {
+		if got := est.Estimate(tt.in); got != tt.want {
+			t.Errorf("Estimate(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestBPEEstimator_NonLatinCountsBytes(t *testing.T) {
+	t.Parallel()
+
+	// Without merges, every UTF-8 byte is a token: CJK text costs far more
+	// than a chars-per-token heuristic would predict.
+	est := NewBPEEstimator(testRanks(), regexp.MustCompile(cl100kPattern))
+	if got := est.Estimate("日本語"); got != 9 {
+		t.Errorf("Estimate = %d, want 9", got)
+	}
+}
+
+func TestLoadTiktokenRanks(t *testing.T) {
+	t.Parallel()
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s 0\n", base64.StdEncoding.EncodeToString([]byte("a")))
+	fmt.Fprintf(&b, "%s 1\n\n", base64.StdEncoding.EncodeToString([]byte(" the")))
+
+	ranks, err := LoadTiktokenRanks(strings.NewReader(b.String()))
+	if err != nil {
+		t.Fatalf("LoadTiktokenRanks: %v", err)
+	}
+	if ranks["a"] != 0 || ranks[" the"] != 1 || len(ranks) != 2 {
+		t.Errorf("ranks = %v", ranks)
+	}
+
+	if _, err := LoadTiktokenRanks(strings.NewReader("!!notbase64 1\n")); err == nil {
+		t.Error("expected error for invalid base64")
+	}
+	if _, err := LoadTiktokenRanks(strings.NewReader("YQ==\n")); err == nil {
+		t.Error("expected error for missing rank")
+	}
+}
@@ -0,0 +1,144 @@
+package ctxengine
+
+import (
+	"math"
+	"sync"
+
+	"github.com/flemzord/sclaw/internal/provider"
+)
+
+// Calibration bounds: a single observation moves the correction ratio by
+// calibrationAlpha, and the ratio is clamped so that one bogus usage report
+// cannot make estimates useless.
+const (
+	calibrationAlpha    = 0.2
+	calibrationMinRatio = 0.5
+	calibrationMaxRatio = 3.0
+)
+
+// CalibratedEstimator wraps a TokenEstimator and corrects its estimates with
+// the prompt token counts reported by the provider. The correction ratio is
+// an exponential moving average of actual/estimated over observed requests.
+// It is safe for concurrent use.
+type CalibratedEstimator struct {
+	inner TokenEstimator
+
+	mu      sync.Mutex
+	ratio   float64
+	samples int
+}
+
+// NewCalibratedEstimator creates a CalibratedEstimator with a neutral ratio.
+func NewCalibratedEstimator(inner TokenEstimator) *CalibratedEstimator {
+	return &CalibratedEstimator{inner: inner, ratio: 1}
+}
+
+// Estimate returns the inner estimate scaled by the current ratio,
+// rounded up to avoid underestimation.
+func (c *CalibratedEstimator) Estimate(text string) int {
+	n := c.inner.Estimate(text)
+	if n == 0 {
+		return 0
+	}
+	return int(math.Ceil(float64(n) * c.Ratio()))
+}
+
+// Ratio returns the current correction ratio.
+func (c *CalibratedEstimator) Ratio() float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ratio
+}
+
+// Samples returns the number of observations used for calibration.
+func (c *CalibratedEstimator) Samples() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.samples
+}
+
+// Observe records that a prompt estimated at estimated tokens (by the inner
+// estimator) was billed as actual tokens. Non-positive values are ignored.
+func (c *CalibratedEstimator) Observe(estimated, actual int) {
+	if estimated <= 0 || actual <= 0 {
+		return
+	}
+	sample := min(max(float64(actual)/float64(estimated), calibrationMinRatio), calibrationMaxRatio)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.samples == 0 {
+		c.ratio = sample
+	} else {
+		c.ratio += calibrationAlpha * (sample - c.ratio)
+	}
+	c.samples++
+}
+
+// ObserveUsage calibrates against a completed provider request: the request
+// is re-estimated with the inner estimator and compared to usage.PromptTokens.
+func (c *CalibratedEstimator) ObserveUsage(req provider.CompletionRequest, usage provider.TokenUsage) {
+	estimated := EstimateMessages(c.inner, req.Messages) + EstimateToolDefinitions(c.inner, req.Tools)
+	c.Observe(estimated, usage.PromptTokens)
+}
+
+// EstimatorSet keeps one CalibratedEstimator per model, so that the usage
+// reported for one model never skews the estimates made for another. It is
+// safe for concurrent use.
+type EstimatorSet struct {
+	mu     sync.Mutex
+	models map[string]*CalibratedEstimator
+}
+
+// NewEstimatorSet creates an empty EstimatorSet.
+func NewEstimatorSet() *EstimatorSet {
+	return &EstimatorSet{models: make(map[string]*CalibratedEstimator)}
+}
+
+// For returns the estimator used to budget requests sent to p. Any target
+// of a provider.RoutingProvider may serve the next request, so behind one
+// the estimate is the largest among the targets' estimators.
+func (s *EstimatorSet) For(p provider.Provider) TokenEstimator {
+	rp, ok := p.(provider.RoutingProvider)
+	if !ok {
+		return s.get(p.ModelName(), func() TokenEstimator { return EstimatorForProvider(p) })
+	}
+	targets := rp.Targets()
+	ests := make(maxEstimator, len(targets))
+	for i, t := range targets {
+		ests[i] = s.For(t)
+	}
+	return ests
+}
+
+// ObserveUsage calibrates the estimator of the model that served req. A
+// model not yet budgeted through For gets an estimator inferred from its
+// name.
+func (s *EstimatorSet) ObserveUsage(model string, req provider.CompletionRequest, usage provider.TokenUsage) {
+	s.get(model, func() TokenEstimator { return EstimatorForModel(model) }).ObserveUsage(req, usage)
+}
+
+// get returns the estimator for model, creating it around inner on first
+// use.
+func (s *EstimatorSet) get(model string, inner func() TokenEstimator) *CalibratedEstimator {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if est, ok := s.models[model]; ok {
+		return est
+	}
+	est := NewCalibratedEstimator(inner())
+	s.models[model] = est
+	return est
+}
+
+// maxEstimator returns the largest estimate of several estimators.
+type maxEstimator []TokenEstimator
+
+// Estimate implements TokenEstimator.
+func (m maxEstimator) Estimate(text string) int {
+	n := 0
+	for _, e := range m {
+		n = max(n, e.Estimate(text))
+	}
+	return n
+}
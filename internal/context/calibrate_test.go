@@ -0,0 +1,182 @@
+package ctxengine_test
+
+import (
+	"errors"
+	"testing"
+
+	ctxengine "github.com/flemzord/sclaw/internal/context"
+	"github.com/flemzord/sclaw/internal/provider"
+	"github.com/flemzord/sclaw/internal/provider/providertest"
+)
+
+var (
+	_ ctxengine.TokenEstimator = (*ctxengine.CalibratedEstimator)(nil)
+	_ ctxengine.TokenEstimator = (*ctxengine.BPEEstimator)(nil)
+)
+
+func mockModel(name string) *providertest.MockProvider {
+	return &providertest.MockProvider{ModelNameFunc: func() string { return name }}
+}
+
+func TestCalibratedEstimator_NeutralByDefault(t *testing.T) {
+	t.Parallel()
+
+	est := ctxengine.NewCalibratedEstimator(ctxengine.NewCharEstimator(4.0))
+	if got := est.Estimate("12345678"); got != 3 {
+		t.Errorf("Estimate = %d, want 3", got)
+	}
+	if est.Ratio() != 1 {
+		t.Errorf("Ratio = %v, want 1", est.Ratio())
+	}
+}
+
+func TestCalibratedEstimator_Observe(t *testing.T) {
+	t.Parallel()
+
+	est := ctxengine.NewCalibratedEstimator(ctxengine.NewCharEstimator(4.0))
+
+	// First observation sets the ratio directly.
+	est.Observe(100, 150)
+	if est.Ratio() != 1.5 {
+		t.Fatalf("Ratio = %v, want 1.5", est.Ratio())
+	}
+	if got := est.Estimate("12345678"); got != 5 { // ceil(3 * 1.5)
+		t.Errorf("Estimate = %d, want 5", got)
+	}
+
+	// Subsequent observations move it by an exponential moving average.
+	est.Observe(100, 100)
+	if r := est.Ratio(); r <= 1 || r >= 1.5 {
+		t.Errorf("Ratio = %v, want between 1 and 1.5", r)
+	}
+
+	// Invalid observations are ignored.
+	before := est.Ratio()
+	est.Observe(0, 100)
+	est.Observe(100, 0)
+	if est.Ratio() != before || est.Samples() != 2 {
+		t.Errorf("invalid observations should be ignored: ratio=%v samples=%d", est.Ratio(), est.Samples())
+	}
+}
+
+func TestCalibratedEstimator_ClampsOutliers(t *testing.T) {
+	t.Parallel()
+
+	est := ctxengine.NewCalibratedEstimator(ctxengine.NewCharEstimator(4.0))
+	est.Observe(10, 10_000)
+	if est.Ratio() != 3 {
+		t.Errorf("Ratio = %v, want clamped to 3", est.Ratio())
+	}
+}
+
+func TestCalibratedEstimator_ObserveUsage(t *testing.T) {
+	t.Parallel()
+
+	inner := ctxengine.NewCharEstimator(4.0)
+	est := ctxengine.NewCalibratedEstimator(inner)
+	req := provider.CompletionRequest{
+		Messages: []provider.LLMMessage{{Role: provider.MessageRoleUser, Content: "hello there, how are you?"}},
+	}
+	estimated := ctxengine.EstimateMessages(inner, req.Messages)
+
+	est.ObserveUsage(req, provider.TokenUsage{PromptTokens: estimated * 2})
+	if est.Ratio() != 2 {
+		t.Errorf("Ratio = %v, want 2", est.Ratio())
+	}
+}
+
+func TestEncodingForModel(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"gpt-4o":               ctxengine.EncodingO200k,
+		"gpt-4o-mini":          ctxengine.EncodingO200k,
+		"openai/gpt-4.1":       ctxengine.EncodingO200k,
+		"o3-mini":              ctxengine.EncodingO200k,
+		"gpt-4-turbo":          ctxengine.EncodingCL100k,
+		"gpt-3.5-turbo":        ctxengine.EncodingCL100k,
+		"claude-sonnet-4":      "",
+		"llama3.1:70b":         "",
+		"text-embedding-3-big": ctxengine.EncodingCL100k,
+	}
+	for model, want := range tests {
+		if got := ctxengine.EncodingForModel(model); got != want {
+			t.Errorf("EncodingForModel(%q) = %q, want %q", model, got, want)
+		}
+	}
+}
+
+func TestNewEncodingEstimator_Unknown(t *testing.T) {
+	t.Parallel()
+
+	if _, err := ctxengine.NewEncodingEstimator("p50k_base"); !errors.Is(err, ctxengine.ErrEncodingUnavailable) {
+		t.Errorf("err = %v, want ErrEncodingUnavailable", err)
+	}
+	if ctxengine.IsKnownEncoding("p50k_base") {
+		t.Error("p50k_base should not be a known encoding")
+	}
+	if !ctxengine.IsKnownEncoding(ctxengine.EncodingO200k) {
+		t.Error("o200k_base should be a known encoding")
+	}
+}
+
+func TestEstimatorForProvider_AlwaysUsable(t *testing.T) {
+	t.Parallel()
+
+	p := &providertest.MockProvider{ModelNameFunc: func() string { return "gpt-4o" }}
+	est := ctxengine.EstimatorForProvider(p)
+	if est == nil {
+		t.Fatal("expected an estimator")
+	}
+	if est.Estimate("hello world") <= 0 {
+		t.Error("expected a positive estimate")
+	}
+}
+
+func TestEstimatorSet_CalibratesPerModel(t *testing.T) {
+	t.Parallel()
+
+	set := ctxengine.NewEstimatorSet()
+	a, b := mockModel("model-a"), mockModel("model-b")
+	text := "hello there, how are you doing today?"
+	before := set.For(b).Estimate(text)
+
+	req := provider.CompletionRequest{
+		Messages: []provider.LLMMessage{{Role: provider.MessageRoleUser, Content: text}},
+	}
+	estimated := ctxengine.EstimateMessages(ctxengine.EstimatorForModel("model-a"), req.Messages)
+	set.ObserveUsage("model-a", req, provider.TokenUsage{PromptTokens: estimated * 2})
+
+	if got := set.For(b).Estimate(text); got != before {
+		t.Errorf("model-b estimate = %d, want %d: usage of model-a must not calibrate it", got, before)
+	}
+	if got := set.For(a).Estimate(text); got <= before {
+		t.Errorf("model-a estimate = %d, want more than %d after calibration", got, before)
+	}
+}
+
+func TestEstimatorSet_RoutingProviderUsesLargestEstimate(t *testing.T) {
+	t.Parallel()
+
+	r, err := provider.NewRouter(provider.RouterConfig{
+		Targets: []provider.RouteTarget{
+			{Name: "small", Provider: mockModel("model-small")},
+			{Name: "big", Provider: mockModel("model-big")},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	set := ctxengine.NewEstimatorSet()
+	text := "hello there, how are you doing today?"
+	req := provider.CompletionRequest{
+		Messages: []provider.LLMMessage{{Role: provider.MessageRoleUser, Content: text}},
+	}
+	estimated := ctxengine.EstimateMessages(ctxengine.EstimatorForModel("model-big"), req.Messages)
+	set.ObserveUsage("model-big", req, provider.TokenUsage{PromptTokens: estimated * 3})
+
+	if got, want := set.For(r).Estimate(text), set.For(mockModel("model-big")).Estimate(text); got != want {
+		t.Errorf("router estimate = %d, want the larger target estimate %d", got, want)
+	}
+}
@@ -0,0 +1,144 @@
+package ctxengine
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/flemzord/sclaw/internal/context/vocab"
+	"github.com/flemzord/sclaw/internal/provider"
+)
+
+// Encoding names for the embedded BPE vocabularies.
+const (
+	EncodingCL100k = "cl100k_base"
+	EncodingO200k  = "o200k_base"
+)
+
+// ErrEncodingUnavailable indicates that an encoding is unknown or its
+// vocabulary is not embedded in the binary.
+var ErrEncodingUnavailable = errors.New("ctxengine: encoding unavailable")
+
+// encoding lazily loads an embedded vocabulary.
+type encoding struct {
+	pattern string
+
+	once      sync.Once
+	estimator *BPEEstimator
+	err       error
+}
+
+var encodings = map[string]*encoding{
+	EncodingCL100k: {pattern: cl100kPattern},
+	EncodingO200k:  {pattern: o200kPattern},
+}
+
+// load reads and parses the embedded vocabulary on first use.
+func (e *encoding) load(name string) (*BPEEstimator, error) {
+	e.once.Do(func() {
+		f, err := vocab.FS.Open("data/" + name + ".tiktoken.gz")
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				e.err = fmt.Errorf("%w: %s vocabulary is not embedded", ErrEncodingUnavailable, name)
+			} else {
+				e.err = fmt.Errorf("ctxengine: opening %s vocabulary: %w", name, err)
+			}
+			return
+		}
+		defer f.Close() //nolint:errcheck // read-only embedded file
+
+		zr, err := gzip.NewReader(f)
+		if err != nil {
+			e.err = fmt.Errorf("ctxengine: decompressing %s vocabulary: %w", name, err)
+			return
+		}
+		ranks, err := LoadTiktokenRanks(zr)
+		if err != nil {
+			e.err = err
+			return
+		}
+		e.estimator = NewBPEEstimator(ranks, regexp.MustCompile(e.pattern))
+	})
+	return e.estimator, e.err
+}
+
+// IsKnownEncoding reports whether name is one of the supported encodings,
+// regardless of whether its vocabulary is embedded.
+func IsKnownEncoding(name string) bool {
+	_, ok := encodings[name]
+	return ok
+}
+
+// NewEncodingEstimator returns the BPE estimator for a named encoding.
+// Estimators are shared: each vocabulary is loaded at most once per process.
+func NewEncodingEstimator(name string) (*BPEEstimator, error) {
+	enc, ok := encodings[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown encoding %q", ErrEncodingUnavailable, name)
+	}
+	return enc.load(name)
+}
+
+// EncodingForModel returns the encoding used by a model family, or "" when
+// the model is not known to use one of the embedded encodings.
+func EncodingForModel(model string) string {
+	m := strings.ToLower(model)
+	if i := strings.LastIndex(m, "/"); i >= 0 {
+		// Strip router prefixes such as "openai/gpt-4o".
+		m = m[i+1:]
+	}
+	switch {
+	case strings.HasPrefix(m, "gpt-4o"), strings.HasPrefix(m, "gpt-4.1"),
+		strings.HasPrefix(m, "gpt-4.5"), strings.HasPrefix(m, "gpt-5"),
+		strings.HasPrefix(m, "chatgpt-4o"), strings.HasPrefix(m, "o1"),
+		strings.HasPrefix(m, "o3"), strings.HasPrefix(m, "o4"),
+		strings.HasPrefix(m, "gpt-oss"):
+		return EncodingO200k
+	case strings.HasPrefix(m, "gpt-4"), strings.HasPrefix(m, "gpt-3.5"),
+		strings.HasPrefix(m, "text-embedding-3"), strings.HasPrefix(m, "text-embedding-ada-002"):
+		return EncodingCL100k
+	default:
+		return ""
+	}
+}
+
+// EstimatorForProvider selects the most accurate available estimator for a
+// provider: the encoding it names explicitly, then the encoding inferred
+// from its model name, then cl100k as a generic BPE approximation. When no
+// vocabulary is embedded it falls back to a CharEstimator.
+func EstimatorForProvider(p provider.Provider) TokenEstimator {
+	var tokenizer string
+	if tn, ok := p.(provider.TokenizerNamer); ok {
+		tokenizer = tn.TokenizerName()
+	}
+	return estimatorFor(tokenizer, p.ModelName())
+}
+
+// EstimatorForModel is EstimatorForProvider for a model known only by name.
+func EstimatorForModel(model string) TokenEstimator {
+	return estimatorFor("", model)
+}
+
+// estimatorFor returns the estimator for the first available encoding among
+// tokenizer, the encoding inferred from model and cl100k.
+func estimatorFor(tokenizer, model string) TokenEstimator {
+	var candidates []string
+	if tokenizer != "" {
+		candidates = append(candidates, tokenizer)
+	}
+	if name := EncodingForModel(model); name != "" {
+		candidates = append(candidates, name)
+	}
+	candidates = append(candidates, EncodingCL100k)
+
+	for _, name := range candidates {
+		if est, err := NewEncodingEstimator(name); err == nil {
+			return est
+		}
+	}
+	return NewCharEstimator(0)
+}
@@ -0,0 +1,37 @@
+package ctxengine
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNewEncodingEstimator_KnownCounts(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		encoding string
+		text     string
+		want     int
+	}{
+		{EncodingCL100k, "", 0},
+		{EncodingCL100k, "hello world", 2},
+		{EncodingCL100k, "Hello, world!", 4},
+		{EncodingCL100k, "tiktoken is great!", 6},
+		{EncodingO200k, "", 0},
+		{EncodingO200k, "hello world", 2},
+		{EncodingO200k, "Hello, world!", 4},
+		{EncodingO200k, "tiktoken is great!", 6},
+	}
+	for _, tt := range tests {
+		est, err := NewEncodingEstimator(tt.encoding)
+		if errors.Is(err, ErrEncodingUnavailable) {
+			t.Skipf("%s vocabulary not embedded; run go generate ./internal/context/vocab", tt.encoding)
+		}
+		if err != nil {
+			t.Fatalf("NewEncodingEstimator(%q): %v", tt.encoding, err)
+		}
+		if got := est.Estimate(tt.text); got != tt.want {
+			t.Errorf("%s: Estimate(%q) = %d, want %d", tt.encoding, tt.text, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,10 @@
+// Package vocab embeds the BPE vocabularies used by the context engine's
+// token estimators. Each vocabulary is a gzip-compressed tiktoken file named
+// data/<encoding>.tiktoken.gz, generated by `go generate` in this directory.
+//
+// A vocabulary missing from data/ is simply not embedded, and token
+// estimation for its encoding falls back to counting characters. The
+// novocab build tag leaves out every vocabulary, even those present.
+package vocab
+
+//go:generate go run gen.go
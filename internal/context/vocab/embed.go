@@ -0,0 +1,10 @@
+//go:build !novocab
+
+package vocab
+
+import "embed"
+
+// FS holds the vocabulary files found in data/ at build time.
+//
+//go:embed data
+var FS embed.FS
@@ -0,0 +1,8 @@
+//go:build novocab
+
+package vocab
+
+import "embed"
+
+// FS is empty: no vocabulary is embedded in novocab builds.
+var FS embed.FS
@@ -0,0 +1,65 @@
+//go:build ignore
+
+// gen downloads the OpenAI BPE vocabularies and stores them gzip-compressed
+// in data/ so that they are embedded by package vocab.
+package main
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+)
+
+var encodings = map[string]string{
+	"cl100k_base": "https://openaipublic.blob.core.windows.net/encodings/cl100k_base.tiktoken",
+	"o200k_base":  "https://openaipublic.blob.core.windows.net/encodings/o200k_base.tiktoken",
+}
+
+func main() {
+	for name, url := range encodings {
+		if err := fetch(name, url); err != nil {
+			fmt.Fprintf(os.Stderr, "vocab: %s: %v\n", name, err)
+			os.Exit(1)
+		}
+	}
+}
+
+func fetch(name, url string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close() //nolint:errcheck // best-effort close
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	f, err := os.Create("data/" + name + ".tiktoken.gz")
+	if err != nil {
+		return err
+	}
+	defer f.Close() //nolint:errcheck // closed explicitly below
+
+	zw, err := gzip.NewWriterLevel(f, gzip.BestCompression)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(zw, resp.Body); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	return f.Close()
+}
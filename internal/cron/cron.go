@@ -9,7 +9,9 @@ type Job interface {
 	// Name returns a unique identifier for this job (used for logging and dedup).
 	Name() string
 
-	// Schedule returns a 5-field cron expression (e.g., "*/5 * * * *").
+	// Schedule returns a 5-field cron expression (e.g., "*/5 * * * *"),
+	// optionally prefixed with CRON_TZ=<zone> (see WithTimezone), or
+	// "@at <RFC 3339 time>" for a job running once (see AtSchedule).
 	Schedule() string
 
 	// Run executes the job. Implementations should check ctx.Done() for
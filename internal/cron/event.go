@@ -0,0 +1,213 @@
+package cron
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"text/template"
+)
+
+// EventTrigger runs a prompt through an agent loop each time a gateway
+// webhook source receives an event, like a PromptJob run by an event rather
+// than by time.
+type EventTrigger struct {
+	Source  string // gateway webhook source, e.g. "github"
+	AgentID string
+	// Prompt is rendered with an EventData for each event. An event whose
+	// prompt renders empty is ignored.
+	Prompt  *template.Template
+	Tools   []string
+	Loop    PromptCronLoop
+	Output  *PromptCronOutput // nil = results are saved to disk only
+	DataDir string
+}
+
+// Validate checks the trigger's output and loop settings.
+func (t *EventTrigger) Validate() error {
+	if t.Source == "" || t.Prompt == nil {
+		return fmt.Errorf("event trigger: source and prompt are required")
+	}
+	if t.Output != nil {
+		if err := t.Output.validate(); err != nil {
+			return fmt.Errorf("event trigger %q: %w", t.Source, err)
+		}
+	}
+	return nil
+}
+
+// EventData is the data an EventTrigger prompt is rendered with.
+type EventData struct {
+	Source  string
+	Payload any    // decoded JSON body; nil if the body is not JSON
+	Body    string // raw body
+	Headers http.Header
+}
+
+// ParseEventPrompt parses the prompt template of an event trigger. Besides
+// the text/template builtins, it provides "json", which encodes a value as
+// JSON.
+func ParseEventPrompt(name, text string) (*template.Template, error) {
+	return template.New(name).Funcs(template.FuncMap{
+		"json": func(v any) (string, error) {
+			data, err := json.Marshal(v)
+			return string(data), err
+		},
+	}).Parse(text)
+}
+
+// EventRunnerConfig configures an EventRunner.
+type EventRunnerConfig struct {
+	Builder LoopBuilder
+	Sender  OutputSender // nil = no channel output
+	Logger  *slog.Logger
+}
+
+// EventRunner runs event triggers in the background. It implements the
+// gateway webhook handler interface and is registered for the source of
+// every trigger. Events of a source are run one at a time, in order.
+type EventRunner struct {
+	cfg EventRunnerConfig
+
+	mu       sync.Mutex
+	triggers map[string]*EventTrigger // keyed by source
+	locks    map[string]*sync.Mutex   // keyed by source
+	stopped  bool
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewEventRunner creates an EventRunner with no triggers.
+func NewEventRunner(cfg EventRunnerConfig) *EventRunner {
+	if cfg.Logger == nil {
+		cfg.Logger = slog.Default()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &EventRunner{
+		cfg:      cfg,
+		triggers: make(map[string]*EventTrigger),
+		locks:    make(map[string]*sync.Mutex),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+}
+
+// SetTriggers replaces the triggers and returns the sources that no longer
+// have one. Events already running finish with their previous trigger.
+func (r *EventRunner) SetTriggers(triggers []*EventTrigger) (removed []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	want := make(map[string]*EventTrigger, len(triggers))
+	for _, t := range triggers {
+		want[t.Source] = t
+		if _, ok := r.locks[t.Source]; !ok {
+			r.locks[t.Source] = &sync.Mutex{}
+		}
+	}
+	for source := range r.triggers {
+		if _, ok := want[source]; !ok {
+			removed = append(removed, source)
+		}
+	}
+	r.triggers = want
+	return removed
+}
+
+// HandleWebhook renders the prompt of the source's trigger and runs it in
+// the background. It returns once the run is queued, so that slow agent
+// runs do not time out the webhook sender.
+func (r *EventRunner) HandleWebhook(_ context.Context, source string, body []byte, headers http.Header) error {
+	r.mu.Lock()
+	trigger, ok := r.triggers[source]
+	lock := r.locks[source]
+	r.mu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("cron: no event trigger for source %q", source)
+	}
+
+	prompt, err := trigger.render(body, headers)
+	if err != nil {
+		return fmt.Errorf("cron: rendering prompt for source %q: %w", source, err)
+	}
+	if prompt == "" {
+		r.cfg.Logger.Debug("cron: event ignored, prompt rendered empty", "source", source)
+		return nil
+	}
+
+	job := &PromptJob{
+		Def: PromptCronDef{
+			Name:    "webhook-" + source,
+			Enabled: true,
+			Prompt:  prompt,
+			Tools:   trigger.Tools,
+			Loop:    trigger.Loop,
+			Output:  trigger.Output,
+		},
+		AgentID: trigger.AgentID,
+		Builder: r.cfg.Builder,
+		Sender:  r.cfg.Sender,
+		DataDir: trigger.DataDir,
+		Logger:  r.cfg.Logger,
+	}
+
+	// Add under mu so that Stop never waits while a run is being queued.
+	r.mu.Lock()
+	if r.stopped {
+		r.mu.Unlock()
+		return errors.New("cron: event runner stopped")
+	}
+	r.wg.Add(1)
+	r.mu.Unlock()
+
+	go func() {
+		defer r.wg.Done()
+		lock.Lock()
+		defer lock.Unlock()
+
+		if err := job.Run(r.ctx); err != nil {
+			r.cfg.Logger.Error("cron: event run failed", "source", source, "agent", trigger.AgentID, "error", err)
+		}
+	}()
+	return nil
+}
+
+// Stop cancels running events and waits for them to return. Events
+// received afterwards are refused.
+func (r *EventRunner) Stop() {
+	r.mu.Lock()
+	r.stopped = true
+	r.mu.Unlock()
+
+	r.cancel()
+	r.wg.Wait()
+}
+
+// render executes the trigger prompt for an event and trims the result.
+func (t *EventTrigger) render(body []byte, headers http.Header) (string, error) {
+	data := EventData{
+		Source:  t.Source,
+		Body:    string(body),
+		Headers: headers,
+	}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber() // keep ids and counts as written
+	var payload any
+	if dec.Decode(&payload) == nil {
+		data.Payload = payload
+	}
+
+	var buf strings.Builder
+	if err := t.Prompt.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
@@ -0,0 +1,129 @@
+package cron
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/flemzord/sclaw/internal/agent"
+)
+
+func mustEventPrompt(t *testing.T, text string) *EventTrigger {
+	t.Helper()
+	prompt, err := ParseEventPrompt("github", text)
+	if err != nil {
+		t.Fatalf("ParseEventPrompt() error = %v", err)
+	}
+	return &EventTrigger{Source: "github", AgentID: "main", Prompt: prompt}
+}
+
+func TestEventTrigger_Render(t *testing.T) {
+	t.Parallel()
+
+	trigger := mustEventPrompt(t, `{{if eq (.Headers.Get "X-GitHub-Event") "push"}}
+Push to {{.Payload.repository.full_name}} ({{len .Payload.commits}} commits, size {{.Payload.size}}): {{json .Payload.head_commit}}
+{{end}}`)
+
+	headers := http.Header{}
+	headers.Set("X-GitHub-Event", "push")
+	body := []byte(`{"repository":{"full_name":"acme/api"},"commits":[{},{}],"size":12345678,"head_commit":{"id":"abc"}}`)
+
+	got, err := trigger.render(body, headers)
+	if err != nil {
+		t.Fatalf("render() error = %v", err)
+	}
+	want := `Push to acme/api (2 commits, size 12345678): {"id":"abc"}`
+	if got != want {
+		t.Errorf("render() = %q, want %q", got, want)
+	}
+
+	headers.Set("X-GitHub-Event", "ping")
+	if got, err := trigger.render(body, headers); err != nil || got != "" {
+		t.Errorf("render(ping) = %q, %v, want empty", got, err)
+	}
+}
+
+func TestEventTrigger_RenderRawBody(t *testing.T) {
+	t.Parallel()
+
+	trigger := mustEventPrompt(t, `Event from {{.Source}}: {{.Body}}{{if .Payload}} (json){{end}}`)
+	got, err := trigger.render([]byte("state=on"), http.Header{})
+	if err != nil {
+		t.Fatalf("render() error = %v", err)
+	}
+	if want := "Event from github: state=on"; got != want {
+		t.Errorf("render() = %q, want %q", got, want)
+	}
+}
+
+func TestEventTrigger_Validate(t *testing.T) {
+	t.Parallel()
+
+	trigger := mustEventPrompt(t, "hi")
+	if err := trigger.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+	trigger.Output = &PromptCronOutput{Channel: "channel.telegram", ChatID: "1", Notify: "sometimes"}
+	if err := trigger.Validate(); err == nil {
+		t.Error("expected an error for an unknown notify policy")
+	}
+}
+
+func TestEventRunner_HandleWebhook(t *testing.T) {
+	dir := t.TempDir()
+	sender := &mockOutputSender{}
+	runner := NewEventRunner(EventRunnerConfig{
+		Builder: &mockLoopBuilder{resp: agent.Response{Content: "Deployed."}},
+		Sender:  sender,
+	})
+
+	trigger := mustEventPrompt(t, `{{with .Payload.ref}}Push to {{.}}{{end}}`)
+	trigger.DataDir = dir
+	trigger.Output = &PromptCronOutput{Channel: "channel.telegram", ChatID: "1"}
+	if removed := runner.SetTriggers([]*EventTrigger{trigger}); len(removed) != 0 {
+		t.Errorf("removed = %v, want none", removed)
+	}
+
+	ctx := context.Background()
+	if err := runner.HandleWebhook(ctx, "github", []byte(`{"ref":"main"}`), http.Header{}); err != nil {
+		t.Fatalf("HandleWebhook() error = %v", err)
+	}
+	if err := runner.HandleWebhook(ctx, "github", []byte(`{"zen":"ping"}`), http.Header{}); err != nil {
+		t.Fatalf("HandleWebhook(ignored) error = %v", err)
+	}
+	if err := runner.HandleWebhook(ctx, "gitlab", nil, http.Header{}); err == nil {
+		t.Error("expected an error for a source without trigger")
+	}
+
+	// The run happens in the background: wait for its history entry.
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		runs, total, err := LoadHistory(dir, "webhook-github", 0, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if total == 1 {
+			if runs[0].Content != "Deployed." || !runs[0].Notified {
+				t.Errorf("run = %+v", runs[0])
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("event did not run")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	runner.Stop()
+
+	if len(sender.calls) != 1 || sender.calls[0].text != "Deployed." {
+		t.Errorf("sent = %+v, want one output", sender.calls)
+	}
+
+	if err := runner.HandleWebhook(ctx, "github", []byte(`{"ref":"main"}`), http.Header{}); err == nil {
+		t.Error("expected an error after Stop")
+	}
+	if removed := runner.SetTriggers(nil); len(removed) != 1 || removed[0] != "github" {
+		t.Errorf("removed = %v, want [github]", removed)
+	}
+}
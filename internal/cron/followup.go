@@ -0,0 +1,69 @@
+package cron
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// PromptCronSession binds a prompt cron to a conversation. Instead of
+// running in a fresh loop, its prompt is submitted to that conversation as
+// if the user had sent it, so the agent answers there with the full history.
+type PromptCronSession struct {
+	Channel  string `json:"channel"` // e.g. "channel.telegram"
+	ChatID   string `json:"chat_id"`
+	ThreadID string `json:"thread_id,omitempty"`
+	ChatType string `json:"chat_type,omitempty"` // "dm" (default) or "group"
+	SenderID string `json:"sender_id"`           // user the message is sent on behalf of
+}
+
+// SessionSubmitter submits a message to a conversation as if it came from
+// a user. Defined here to avoid a circular dependency on the router package.
+type SessionSubmitter interface {
+	SubmitToSession(ctx context.Context, session PromptCronSession, id, text string) error
+}
+
+// submitFollowup submits the prompt of a session-bound definition to its
+// conversation. The agent's answer is delivered there by the router, so the
+// result only records the submission.
+func (j *PromptJob) submitFollowup(ctx context.Context, logger *slog.Logger) error {
+	now := time.Now()
+	text := "[Scheduled follow-up] " + j.Def.Prompt
+	result := PromptCronResult{
+		Name:       j.Def.Name,
+		RanAt:      now.UTC().Format(time.RFC3339),
+		StopReason: "followup",
+		Content:    text,
+	}
+
+	var err error
+	if j.Submitter == nil {
+		err = fmt.Errorf("prompt_cron: no session submitter for %q", j.Def.Name)
+	} else if err = j.Submitter.SubmitToSession(ctx, *j.Def.Session, fmt.Sprintf("%s-%d", j.Def.Name, now.Unix()), text); err != nil {
+		err = fmt.Errorf("prompt_cron: submitting follow-up %q: %w", j.Def.Name, err)
+	}
+	if err != nil {
+		result.Error = err.Error()
+	} else {
+		result.Notified = true
+	}
+
+	j.record(ctx, result, logger)
+
+	logger.Info("prompt_cron: follow-up submitted", "name", j.Def.Name, "agent", j.AgentID)
+	return err
+}
+
+// validate checks that the session identifies a conversation and a user.
+func (s *PromptCronSession) validate() error {
+	if s.Channel == "" || s.ChatID == "" || s.SenderID == "" {
+		return fmt.Errorf("session requires channel, chat_id and sender_id")
+	}
+	switch s.ChatType {
+	case "", "dm", "group":
+		return nil
+	default:
+		return fmt.Errorf("session: unknown chat_type %q", s.ChatType)
+	}
+}
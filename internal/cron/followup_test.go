@@ -0,0 +1,99 @@
+package cron
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// mockSubmitter records calls to SubmitToSession.
+type mockSubmitter struct {
+	sessions []PromptCronSession
+	texts    []string
+	err      error
+}
+
+func (m *mockSubmitter) SubmitToSession(_ context.Context, s PromptCronSession, _, text string) error {
+	m.sessions = append(m.sessions, s)
+	m.texts = append(m.texts, text)
+	return m.err
+}
+
+func TestPromptJob_Run_Followup(t *testing.T) {
+	dir := t.TempDir()
+	session := PromptCronSession{Channel: "channel.telegram", ChatID: "42", SenderID: "7"}
+	submitter := &mockSubmitter{}
+	builder := &mockLoopBuilder{}
+
+	j := &PromptJob{
+		Def: PromptCronDef{
+			Name:    "followup-1",
+			At:      "2026-03-14T09:00:00Z",
+			Enabled: true,
+			Prompt:  "Ask whether the invoice was sent.",
+			Session: &session,
+		},
+		AgentID:   "main",
+		Builder:   builder,
+		Submitter: submitter,
+		DataDir:   dir,
+	}
+	if err := j.Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if builder.builds != 0 {
+		t.Errorf("built %d loops, want none", builder.builds)
+	}
+	if len(submitter.sessions) != 1 || submitter.sessions[0] != session {
+		t.Fatalf("sessions = %+v", submitter.sessions)
+	}
+	if !strings.HasSuffix(submitter.texts[0], "Ask whether the invoice was sent.") {
+		t.Errorf("text = %q", submitter.texts[0])
+	}
+
+	result, err := LoadResult(dir, "followup-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.StopReason != "followup" || !result.Notified || result.Error != "" {
+		t.Errorf("result = %+v", result)
+	}
+
+	// One-shot: disabled on disk once fired.
+	def, err := LoadPromptCronDef(filepath.Join(CronsDir(dir), "followup-1.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if def.Enabled {
+		t.Error("follow-up still enabled after firing")
+	}
+}
+
+func TestPromptJob_Run_FollowupError(t *testing.T) {
+	dir := t.TempDir()
+	j := &PromptJob{
+		Def: PromptCronDef{
+			Name:     "followup-1",
+			Schedule: "0 9 * * *",
+			Enabled:  true,
+			Prompt:   "Check in.",
+			Session:  &PromptCronSession{Channel: "channel.telegram", ChatID: "42", SenderID: "7"},
+		},
+		AgentID:   "main",
+		Submitter: &mockSubmitter{err: errors.New("router stopped")},
+		DataDir:   dir,
+	}
+	if err := j.Run(context.Background()); err == nil || !strings.Contains(err.Error(), "router stopped") {
+		t.Fatalf("Run() error = %v, want router stopped", err)
+	}
+	result, err := LoadResult(dir, "followup-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Notified || result.Error == "" {
+		t.Errorf("result = %+v", result)
+	}
+}
@@ -0,0 +1,271 @@
+package cron
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// HistoryDir returns the cron run history directory under the given data
+// directory.
+func HistoryDir(dataDir string) string {
+	return filepath.Join(dataDir, "crons", "history")
+}
+
+// historyPath returns the run history file of a prompt cron.
+func historyPath(dataDir, name string) string {
+	return filepath.Join(HistoryDir(dataDir), name+".jsonl")
+}
+
+// historyMaxBytes caps the size of a run history file. Past it, the
+// history is trimmed to its newest runs, about half of the cap.
+const historyMaxBytes = 4 << 20
+
+// historyChunkSize is the size of the blocks read from the end of a
+// history file.
+const historyChunkSize = 32 << 10
+
+// AppendHistory appends a run result to the prompt cron's run history, one
+// JSON object per line, dropping the oldest runs once the file exceeds
+// historyMaxBytes.
+func AppendHistory(dataDir string, result PromptCronResult) error {
+	return appendHistory(dataDir, result, historyMaxBytes)
+}
+
+// appendHistory is AppendHistory with a configurable size cap.
+func appendHistory(dataDir string, result PromptCronResult, maxBytes int64) error {
+	if err := os.MkdirAll(HistoryDir(dataDir), 0o755); err != nil {
+		return fmt.Errorf("creating history dir: %w", err)
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("marshaling result: %w", err)
+	}
+
+	path := historyPath(dataDir, result.Name)
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("opening history: %w", err)
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("writing history: %w", err)
+	}
+	info, err := f.Stat()
+	if err := errors.Join(err, f.Close()); err != nil {
+		return fmt.Errorf("writing history: %w", err)
+	}
+	if info.Size() > maxBytes {
+		return trimHistory(path, maxBytes/2)
+	}
+	return nil
+}
+
+// trimHistory rewrites the history file at path with its newest lines
+// totaling at most keep bytes. The newest line is always kept.
+func trimHistory(path string, keep int64) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("opening history: %w", err)
+	}
+	var lines [][]byte
+	var size int64
+	err = scanLinesBackward(f, func(line []byte) bool {
+		size += int64(len(line)) + 1
+		if size > keep && len(lines) > 0 {
+			return false
+		}
+		lines = append(lines, slices.Clone(line))
+		return true
+	})
+	_ = f.Close()
+	if err != nil {
+		return err
+	}
+	slices.Reverse(lines)
+
+	var buf bytes.Buffer
+	for _, line := range lines {
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("trimming history: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("trimming history: %w", err)
+	}
+	return nil
+}
+
+// LoadHistory returns up to limit runs of a prompt cron, newest first,
+// skipping the offset newest ones, along with the total number of runs
+// recorded. A limit of zero or less returns every run after offset.
+// A cron that never ran has an empty history.
+func LoadHistory(dataDir, name string, offset, limit int) ([]PromptCronResult, int, error) {
+	f, err := openHistory(dataDir, name)
+	if f == nil {
+		return nil, 0, err
+	}
+	defer func() { _ = f.Close() }()
+
+	total, err := countLines(f)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Only the requested page is decoded, reading from the newest run.
+	offset = max(offset, 0)
+	var runs []PromptCronResult
+	skipped := 0
+	err = scanLinesBackward(f, func(line []byte) bool {
+		var result PromptCronResult
+		if json.Unmarshal(line, &result) != nil {
+			return true
+		}
+		if skipped < offset {
+			skipped++
+			return true
+		}
+		runs = append(runs, result)
+		return limit <= 0 || len(runs) < limit
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return runs, total, nil
+}
+
+// DeleteHistory removes the run history of a prompt cron, if any.
+func DeleteHistory(dataDir, name string) error {
+	err := os.Remove(historyPath(dataDir, name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// ConsecutiveFailures returns how many of the latest runs of a prompt cron
+// failed in a row.
+func ConsecutiveFailures(dataDir, name string) (int, error) {
+	f, err := openHistory(dataDir, name)
+	if f == nil {
+		return 0, err
+	}
+	defer func() { _ = f.Close() }()
+
+	n := 0
+	err = scanLinesBackward(f, func(line []byte) bool {
+		var result PromptCronResult
+		if json.Unmarshal(line, &result) != nil {
+			return true
+		}
+		if result.Error == "" {
+			return false
+		}
+		n++
+		return true
+	})
+	return n, err
+}
+
+// LastSuccessfulRun returns the latest run of a prompt cron that did not
+// fail, nil if none is recorded.
+func LastSuccessfulRun(dataDir, name string) (*PromptCronResult, error) {
+	f, err := openHistory(dataDir, name)
+	if f == nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	var found *PromptCronResult
+	err = scanLinesBackward(f, func(line []byte) bool {
+		var result PromptCronResult
+		if json.Unmarshal(line, &result) != nil || result.Error != "" {
+			return true
+		}
+		found = &result
+		return false
+	})
+	return found, err
+}
+
+// openHistory opens the run history of a prompt cron. It returns a nil
+// file and no error when the cron never ran.
+func openHistory(dataDir, name string) (*os.File, error) {
+	f, err := os.Open(historyPath(dataDir, name))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("opening history: %w", err)
+	}
+	return f, nil
+}
+
+// countLines returns the number of lines in f, counting a last line cut
+// short by a crash.
+func countLines(f *os.File) (int, error) {
+	n, last := 0, byte('\n')
+	buf := make([]byte, historyChunkSize)
+	for {
+		k, err := f.Read(buf)
+		if k > 0 {
+			n += bytes.Count(buf[:k], []byte{'\n'})
+			last = buf[k-1]
+		}
+		if err == io.EOF {
+			if last != '\n' {
+				n++
+			}
+			return n, nil
+		}
+		if err != nil {
+			return 0, fmt.Errorf("reading history: %w", err)
+		}
+	}
+}
+
+// scanLinesBackward calls fn with the non-empty lines of f, newest first,
+// until fn returns false. The line is only valid during the call.
+func scanLinesBackward(f *os.File, fn func(line []byte) bool) error {
+	info, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("reading history: %w", err)
+	}
+
+	var rest []byte // start of the line cut by the previous block
+	for off := info.Size(); off > 0; {
+		n := min(off, historyChunkSize)
+		off -= n
+		block := make([]byte, int(n)+len(rest))
+		if _, err := f.ReadAt(block[:n], off); err != nil {
+			return fmt.Errorf("reading history: %w", err)
+		}
+		copy(block[n:], rest)
+
+		for {
+			i := bytes.LastIndexByte(block, '\n')
+			if i < 0 {
+				break
+			}
+			if line := bytes.TrimSpace(block[i+1:]); len(line) > 0 && !fn(line) {
+				return nil
+			}
+			block = block[:i]
+		}
+		rest = block
+	}
+	if line := bytes.TrimSpace(rest); len(line) > 0 {
+		fn(line)
+	}
+	return nil
+}
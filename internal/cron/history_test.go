@@ -0,0 +1,135 @@
+package cron
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestHistory_AppendAndLoad(t *testing.T) {
+	dir := t.TempDir()
+
+	runs, total, err := LoadHistory(dir, "daily", 0, 10)
+	if err != nil || total != 0 || len(runs) != 0 {
+		t.Fatalf("empty history = %v, %d, %v", runs, total, err)
+	}
+
+	for _, ranAt := range []string{"2026-03-01T09:00:00Z", "2026-03-02T09:00:00Z", "2026-03-03T09:00:00Z"} {
+		if err := AppendHistory(dir, PromptCronResult{Name: "daily", RanAt: ranAt}); err != nil {
+			t.Fatalf("append: %v", err)
+		}
+	}
+
+	tests := []struct {
+		offset, limit int
+		want          []string
+	}{
+		{0, 0, []string{"2026-03-03T09:00:00Z", "2026-03-02T09:00:00Z", "2026-03-01T09:00:00Z"}},
+		{0, 2, []string{"2026-03-03T09:00:00Z", "2026-03-02T09:00:00Z"}},
+		{2, 2, []string{"2026-03-01T09:00:00Z"}},
+		{5, 2, nil},
+	}
+	for _, tt := range tests {
+		runs, total, err := LoadHistory(dir, "daily", tt.offset, tt.limit)
+		if err != nil {
+			t.Fatalf("load: %v", err)
+		}
+		if total != 3 {
+			t.Errorf("offset %d limit %d: total = %d, want 3", tt.offset, tt.limit, total)
+		}
+		var got []string
+		for _, r := range runs {
+			got = append(got, r.RanAt)
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("offset %d limit %d: got %v, want %v", tt.offset, tt.limit, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("offset %d limit %d: got %v, want %v", tt.offset, tt.limit, got, tt.want)
+				break
+			}
+		}
+	}
+
+	if err := DeleteHistory(dir, "daily"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if err := DeleteHistory(dir, "daily"); err != nil {
+		t.Errorf("deleting missing history: %v", err)
+	}
+}
+
+func TestConsecutiveFailures(t *testing.T) {
+	dir := t.TempDir()
+	for _, errText := range []string{"boom", "", "boom", "boom"} {
+		_ = AppendHistory(dir, PromptCronResult{Name: "flaky", Error: errText})
+	}
+
+	// A line cut short by a crash is skipped.
+	f, err := os.OpenFile(historyPath(dir, "flaky"), os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.WriteString(`{"name":"flaky","err`)
+	_ = f.Close()
+
+	n, err := ConsecutiveFailures(dir, "flaky")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("ConsecutiveFailures() = %d, want 2", n)
+	}
+}
+
+func TestHistory_Trim(t *testing.T) {
+	dir := t.TempDir()
+	for i := range 100 {
+		result := PromptCronResult{Name: "often", RanAt: fmt.Sprintf("run-%03d", i)}
+		if err := appendHistory(dir, result, 2048); err != nil {
+			t.Fatalf("append: %v", err)
+		}
+	}
+
+	info, err := os.Stat(historyPath(dir, "often"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size() > 2048 {
+		t.Errorf("history size = %d, want at most 2048", info.Size())
+	}
+	runs, total, err := LoadHistory(dir, "often", 0, 0)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if total == 100 || total != len(runs) {
+		t.Fatalf("total = %d, runs = %d: want a trimmed history", total, len(runs))
+	}
+	// The newest runs are kept, in order.
+	for i, r := range runs {
+		if want := fmt.Sprintf("run-%03d", 99-i); r.RanAt != want {
+			t.Fatalf("runs[%d] = %q, want %q", i, r.RanAt, want)
+		}
+	}
+}
+
+func TestHistory_LongLines(t *testing.T) {
+	dir := t.TempDir()
+	long := strings.Repeat("x", 3*historyChunkSize)
+	for _, content := range []string{"first", long, "last"} {
+		if err := AppendHistory(dir, PromptCronResult{Name: "report", Content: content}); err != nil {
+			t.Fatalf("append: %v", err)
+		}
+	}
+
+	runs, total, err := LoadHistory(dir, "report", 1, 2)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if total != 3 || len(runs) != 2 || runs[0].Content != long || runs[1].Content != "first" {
+		t.Errorf("LoadHistory(1, 2) = %d runs of %d total, want the long run then the first", len(runs), total)
+	}
+}
@@ -0,0 +1,176 @@
+package cron
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/flemzord/sclaw/internal/agent"
+	"github.com/flemzord/sclaw/internal/tool"
+)
+
+// Notify policies of a prompt cron output.
+const (
+	NotifyAlways  = "always"  // send every result (default)
+	NotifyChanged = "changed" // send when the result differs from the previous run
+	NotifyTool    = "tool"    // send only when the agent calls the notify tool
+	NotifyMatch   = "match"   // send when the JSON result matches Output.Match
+)
+
+// NotifyToolName is the name of the tool a prompt cron with the "tool"
+// notify policy calls to send a notification.
+const NotifyToolName = "notify"
+
+// notifyTool lets the agent of a prompt cron decide to notify the output
+// chat. The call itself is the signal: the job reads it from the response.
+type notifyTool struct{}
+
+// Compile-time interface check.
+var _ tool.Tool = notifyTool{}
+
+func (notifyTool) Name() string { return NotifyToolName }
+func (notifyTool) Description() string {
+	return "Notify the user with a message. Call it only when something needs their attention; " +
+		"if it is not called, nothing is sent."
+}
+func (notifyTool) Scopes() []tool.Scope              { return []tool.Scope{tool.ScopeReadOnly} }
+func (notifyTool) DefaultPolicy() tool.ApprovalLevel { return tool.ApprovalAllow }
+
+func (notifyTool) Schema() json.RawMessage {
+	return json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"message": {"type": "string", "description": "Message sent to the user."}
+		},
+		"required": ["message"],
+		"additionalProperties": false
+	}`)
+}
+
+type notifyArgs struct {
+	Message string `json:"message"`
+}
+
+func (notifyTool) Execute(_ context.Context, args json.RawMessage, _ tool.ExecutionEnv) (tool.Output, error) {
+	var a notifyArgs
+	if err := json.Unmarshal(args, &a); err != nil || strings.TrimSpace(a.Message) == "" {
+		return tool.Output{Content: "a non-empty message is required", IsError: true}, nil
+	}
+	return tool.Output{Content: "notification will be sent"}, nil
+}
+
+// notifyPolicy returns the notify policy of an output, defaulting to always.
+func (o *PromptCronOutput) notifyPolicy() string {
+	if o.Notify == "" {
+		return NotifyAlways
+	}
+	return o.Notify
+}
+
+// validate checks the notify policy and its match conditions.
+func (o *PromptCronOutput) validate() error {
+	switch o.notifyPolicy() {
+	case NotifyAlways, NotifyChanged, NotifyTool:
+		if len(o.Match) > 0 {
+			return fmt.Errorf("output.match requires notify %q", NotifyMatch)
+		}
+	case NotifyMatch:
+		if len(o.Match) == 0 {
+			return fmt.Errorf("notify %q requires output.match", NotifyMatch)
+		}
+		for field, pattern := range o.Match {
+			if _, err := regexp.Compile(pattern); err != nil {
+				return fmt.Errorf("output.match[%q]: invalid pattern: %w", field, err)
+			}
+		}
+	default:
+		return fmt.Errorf("unknown notify policy %q", o.Notify)
+	}
+	return nil
+}
+
+// notification returns the text to send for a response under the output's
+// notify policy, or "" when nothing should be sent. previous is the last
+// successful result before this run, nil if none.
+func (o *PromptCronOutput) notification(resp agent.Response, previous *PromptCronResult) string {
+	switch o.notifyPolicy() {
+	case NotifyChanged:
+		if previous != nil && strings.TrimSpace(previous.Content) == strings.TrimSpace(resp.Content) {
+			return ""
+		}
+	case NotifyTool:
+		var msgs []string
+		for _, call := range resp.ToolCalls {
+			var a notifyArgs
+			if call.Name != NotifyToolName || call.Output.IsError || json.Unmarshal(call.Arguments, &a) != nil {
+				continue
+			}
+			msgs = append(msgs, a.Message)
+		}
+		return strings.Join(msgs, "\n\n")
+	case NotifyMatch:
+		if !matchFields(o.Match, resp.Content) {
+			return ""
+		}
+	}
+	return resp.Content
+}
+
+// matchFields reports whether content is a JSON object, optionally inside a
+// Markdown code fence, whose top-level fields match every pattern. Non-string
+// fields are matched against their JSON encoding; a missing field never
+// matches.
+func matchFields(match map[string]string, content string) bool {
+	var fields map[string]json.RawMessage
+	if json.Unmarshal([]byte(stripCodeFence(content)), &fields) != nil {
+		return false
+	}
+	for field, pattern := range match {
+		raw, ok := fields[field]
+		if !ok {
+			return false
+		}
+		value := string(raw)
+		var s string
+		if json.Unmarshal(raw, &s) == nil {
+			value = s
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil || !re.MatchString(value) {
+			return false
+		}
+	}
+	return true
+}
+
+// stripCodeFence removes a Markdown code fence wrapping the whole content.
+func stripCodeFence(content string) string {
+	content = strings.TrimSpace(content)
+	if !strings.HasPrefix(content, "```") || !strings.HasSuffix(content, "```") || len(content) < 6 {
+		return content
+	}
+	content = strings.TrimSuffix(content[3:], "```")
+	if i := strings.IndexByte(content, '\n'); i >= 0 {
+		content = content[i+1:] // drop the language tag line
+	}
+	return strings.TrimSpace(content)
+}
+
+// previousRunContext formats the last successful result for the prompt, so
+// the agent can tell what changed since. latest is the most recent run; when
+// it failed, a note tells the agent so without replacing the baseline.
+func previousRunContext(lastSuccess, latest *PromptCronResult) string {
+	var failure string
+	if latest != nil && latest.Error != "" {
+		failure = fmt.Sprintf("\n\nThe latest run at %s failed: %s", latest.RanAt, latest.Error)
+	}
+	if lastSuccess == nil {
+		if failure != "" {
+			return "There is no previous successful result." + failure
+		}
+		return "This is the first run: there is no previous result."
+	}
+	return fmt.Sprintf("Result of the last successful run at %s:\n\n%s", lastSuccess.RanAt, lastSuccess.Content) + failure
+}
@@ -0,0 +1,210 @@
+package cron
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/flemzord/sclaw/internal/agent"
+	"github.com/flemzord/sclaw/internal/tool"
+)
+
+func TestPromptCronOutput_Notification(t *testing.T) {
+	t.Parallel()
+
+	previous := &PromptCronResult{Content: "all good"}
+	notifyCall := func(msg string) agent.ToolCallRecord {
+		args, _ := json.Marshal(notifyArgs{Message: msg})
+		return agent.ToolCallRecord{Name: NotifyToolName, Arguments: args}
+	}
+
+	tests := []struct {
+		name     string
+		out      PromptCronOutput
+		resp     agent.Response
+		previous *PromptCronResult
+		want     string
+	}{
+		{"always", PromptCronOutput{}, agent.Response{Content: "all good"}, previous, "all good"},
+		{"changed same", PromptCronOutput{Notify: NotifyChanged}, agent.Response{Content: " all good\n"}, previous, ""},
+		{"changed differs", PromptCronOutput{Notify: NotifyChanged}, agent.Response{Content: "api down"}, previous, "api down"},
+		{"changed first run", PromptCronOutput{Notify: NotifyChanged}, agent.Response{Content: "all good"}, nil, "all good"},
+		{"tool not called", PromptCronOutput{Notify: NotifyTool}, agent.Response{Content: "all good"}, nil, ""},
+		{
+			"tool called",
+			PromptCronOutput{Notify: NotifyTool},
+			agent.Response{Content: "done", ToolCalls: []agent.ToolCallRecord{notifyCall("api down"), {Name: "read_file"}}},
+			nil,
+			"api down",
+		},
+		{
+			"tool call failed",
+			PromptCronOutput{Notify: NotifyTool},
+			agent.Response{ToolCalls: []agent.ToolCallRecord{{Name: NotifyToolName, Arguments: json.RawMessage(`{}`), Output: tool.Output{IsError: true}}}},
+			nil,
+			"",
+		},
+		{
+			"match",
+			PromptCronOutput{Notify: NotifyMatch, Match: map[string]string{"status": "^down$", "incidents": "^[1-9]"}},
+			agent.Response{Content: "```json\n{\"status\": \"down\", \"incidents\": 2}\n```"},
+			nil,
+			"```json\n{\"status\": \"down\", \"incidents\": 2}\n```",
+		},
+		{"match fails", PromptCronOutput{Notify: NotifyMatch, Match: map[string]string{"status": "^down$"}}, agent.Response{Content: `{"status": "up"}`}, nil, ""},
+		{"match missing field", PromptCronOutput{Notify: NotifyMatch, Match: map[string]string{"status": ".*"}}, agent.Response{Content: `{}`}, nil, ""},
+		{"match not json", PromptCronOutput{Notify: NotifyMatch, Match: map[string]string{"status": ".*"}}, agent.Response{Content: "status: down"}, nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := tt.out.notification(tt.resp, tt.previous); got != tt.want {
+				t.Errorf("notification() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNotifyTool_Execute(t *testing.T) {
+	t.Parallel()
+
+	out, err := notifyTool{}.Execute(context.Background(), json.RawMessage(`{"message":"hi"}`), tool.ExecutionEnv{})
+	if err != nil || out.IsError {
+		t.Fatalf("Execute() = %+v, %v", out, err)
+	}
+	out, _ = notifyTool{}.Execute(context.Background(), json.RawMessage(`{"message":" "}`), tool.ExecutionEnv{})
+	if !out.IsError {
+		t.Error("expected an error for an empty message")
+	}
+}
+
+func TestPromptJob_Run_NotifyChanged(t *testing.T) {
+	dir := t.TempDir()
+	sender := &mockOutputSender{}
+	builder := &mockLoopBuilder{resp: agent.Response{Content: "all good"}}
+
+	j := &PromptJob{
+		Def: PromptCronDef{
+			Name:     "status",
+			Schedule: "*/15 * * * *",
+			Enabled:  true,
+			Prompt:   "Check the status page",
+			Output:   &PromptCronOutput{Channel: "channel.telegram", ChatID: "1", Notify: NotifyChanged},
+		},
+		AgentID: "main",
+		Builder: builder,
+		Sender:  sender,
+		DataDir: dir,
+	}
+
+	for range 2 {
+		if err := j.Run(context.Background()); err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+	}
+	if len(sender.calls) != 1 {
+		t.Fatalf("sent %d messages, want 1", len(sender.calls))
+	}
+
+	runs, _, err := LoadHistory(dir, "status", 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 2 || runs[0].Notified || !runs[1].Notified {
+		t.Errorf("runs = %+v, want only the first notified", runs)
+	}
+}
+
+func TestPromptJob_Run_NotifyChangedAfterFailure(t *testing.T) {
+	dir := t.TempDir()
+	sender := &mockOutputSender{}
+	builder := &mockLoopBuilder{resp: agent.Response{Content: "all good"}}
+
+	j := &PromptJob{
+		Def: PromptCronDef{
+			Name:     "status",
+			Schedule: "*/15 * * * *",
+			Enabled:  true,
+			Prompt:   "Check the status page",
+			Output:   &PromptCronOutput{Channel: "channel.telegram", ChatID: "1", Notify: NotifyChanged},
+		},
+		AgentID: "main",
+		Builder: builder,
+		Sender:  sender,
+		DataDir: dir,
+	}
+
+	// Success, transient failure, then the same result again.
+	for _, err := range []error{nil, errors.New("provider unavailable"), nil} {
+		builder.err = err
+		_ = j.Run(context.Background())
+	}
+	if len(sender.calls) != 1 {
+		t.Fatalf("sent %d messages, want 1: the last result is unchanged", len(sender.calls))
+	}
+
+	// A different result after the failure is still sent.
+	builder.err = errors.New("provider unavailable")
+	_ = j.Run(context.Background())
+	builder.err = nil
+	builder.resp = agent.Response{Content: "api down"}
+	_ = j.Run(context.Background())
+	if len(sender.calls) != 2 {
+		t.Errorf("sent %d messages, want 2", len(sender.calls))
+	}
+}
+
+func TestPromptJob_Run_NotifyTool(t *testing.T) {
+	builder := &mockLoopBuilder{resp: agent.Response{Content: "all good"}}
+	sender := &mockOutputSender{}
+
+	j := &PromptJob{
+		Def: PromptCronDef{
+			Name:     "status",
+			Schedule: "*/15 * * * *",
+			Enabled:  true,
+			Prompt:   "Check the status page",
+			Output:   &PromptCronOutput{Channel: "channel.telegram", ChatID: "1", Notify: NotifyTool},
+		},
+		AgentID: "main",
+		Builder: builder,
+		Sender:  sender,
+		DataDir: t.TempDir(),
+	}
+	if err := j.Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if len(builder.extraTools) != 1 || builder.extraTools[0].Name() != NotifyToolName {
+		t.Errorf("extra tools = %v, want the notify tool", builder.extraTools)
+	}
+	if len(sender.calls) != 0 {
+		t.Errorf("sent %v without a notify call", sender.calls)
+	}
+}
+
+func TestPreviousRunContext(t *testing.T) {
+	t.Parallel()
+
+	if got := previousRunContext(nil, nil); !strings.Contains(got, "first run") {
+		t.Errorf("previousRunContext(nil, nil) = %q", got)
+	}
+	ok := &PromptCronResult{RanAt: "2026-03-01T09:00:00Z", Content: "all good"}
+	got := previousRunContext(ok, ok)
+	if !strings.Contains(got, "2026-03-01T09:00:00Z") || !strings.Contains(got, "all good") || strings.Contains(got, "failed") {
+		t.Errorf("previousRunContext(ok, ok) = %q", got)
+	}
+
+	failed := &PromptCronResult{RanAt: "2026-03-02T09:00:00Z", Error: "provider down"}
+	got = previousRunContext(ok, failed)
+	if !strings.Contains(got, "all good") || !strings.Contains(got, "2026-03-02T09:00:00Z failed: provider down") {
+		t.Errorf("previousRunContext(ok, failed) = %q", got)
+	}
+	got = previousRunContext(nil, failed)
+	if strings.Contains(got, "first run") || !strings.Contains(got, "provider down") {
+		t.Errorf("previousRunContext(nil, failed) = %q", got)
+	}
+}
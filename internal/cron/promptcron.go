@@ -11,6 +11,7 @@ import (
 
 	"github.com/flemzord/sclaw/internal/agent"
 	"github.com/flemzord/sclaw/internal/provider"
+	"github.com/flemzord/sclaw/internal/tool"
 )
 
 // maxDefSize is the maximum allowed size for a prompt cron definition file.
@@ -18,14 +19,20 @@ const maxDefSize = 256 << 10 // 256 KiB
 
 // PromptCronDef is the on-disk JSON representation of a scheduled prompt.
 type PromptCronDef struct {
-	Name        string            `json:"name"`
-	Description string            `json:"description,omitempty"`
-	Schedule    string            `json:"schedule"`
-	Enabled     bool              `json:"enabled"`
-	Prompt      string            `json:"prompt"`
-	Tools       []string          `json:"tools,omitempty"`
-	Loop        PromptCronLoop    `json:"loop,omitempty"`
-	Output      *PromptCronOutput `json:"output,omitempty"`
+	Name        string             `json:"name"`
+	Description string             `json:"description,omitempty"`
+	Schedule    string             `json:"schedule,omitempty"`
+	At          string             `json:"at,omitempty"`       // one-shot run time; exclusive with Schedule
+	Timezone    string             `json:"timezone,omitempty"` // IANA zone of Schedule and At
+	Enabled     bool               `json:"enabled"`
+	Prompt      string             `json:"prompt,omitempty"`
+	Message     string             `json:"message,omitempty"` // sent verbatim instead of running Prompt
+	Tools       []string           `json:"tools,omitempty"`
+	Loop        PromptCronLoop     `json:"loop,omitempty"`
+	Retry       PromptCronRetry    `json:"retry,omitempty"`
+	CatchUp     bool               `json:"catch_up,omitempty"` // run once at startup if a run was missed
+	Output      *PromptCronOutput  `json:"output,omitempty"`
+	Session     *PromptCronSession `json:"session,omitempty"` // submit Prompt to this conversation
 }
 
 // PromptCronLoop configures the agent loop for a prompt cron.
@@ -34,10 +41,38 @@ type PromptCronLoop struct {
 	Timeout       string `json:"timeout,omitempty"`
 }
 
+// PromptCronRetry configures retries of runs failing with a transient
+// provider error, such as a rate limit. The whole prompt is run again.
+type PromptCronRetry struct {
+	MaxRetries int    `json:"max_retries,omitempty"`
+	Backoff    string `json:"backoff,omitempty"` // first delay, doubled on each retry; default 30s
+}
+
+// defaultRetryBackoff and maxRetryBackoff bound the delay between retries.
+const (
+	defaultRetryBackoff = 30 * time.Second
+	maxRetryBackoff     = 10 * time.Minute
+)
+
+// backoff returns the delay before the first retry.
+func (r PromptCronRetry) backoff() time.Duration {
+	if d, err := time.ParseDuration(r.Backoff); err == nil && d > 0 {
+		return d
+	}
+	return defaultRetryBackoff
+}
+
 // PromptCronOutput configures where to deliver the cron result.
 type PromptCronOutput struct {
 	Channel string `json:"channel"` // e.g. "channel.telegram"
 	ChatID  string `json:"chat_id"` // e.g. "123456789"
+	// Notify is the policy deciding whether a result is sent: "always"
+	// (default), "changed", "tool" or "match". Any policy but "always" gives
+	// the agent the previous result as context.
+	Notify string `json:"notify,omitempty"`
+	// Match maps top-level fields of a JSON result to regular expressions
+	// they must all match for the "match" policy to send it.
+	Match map[string]string `json:"match,omitempty"`
 }
 
 // PromptCronResult is the last-run result stored on disk.
@@ -51,12 +86,23 @@ type PromptCronResult struct {
 	TotalTokens int    `json:"total_tokens"`
 	Content     string `json:"content"`
 	Error       string `json:"error,omitempty"`
+	Attempts    int    `json:"attempts,omitempty"` // set when the run was retried
+	Notified    bool   `json:"notified,omitempty"` // a notification was sent to the output chat
+}
+
+// FailureAlert sends a message to a chat when a prompt cron fails several
+// runs in a row.
+type FailureAlert struct {
+	Channel string // e.g. "channel.telegram"
+	ChatID  string
+	After   int // consecutive failures before alerting
 }
 
 // LoopBuilder creates an agent.Loop for cron execution.
 // Defined here to avoid a circular dependency on the multiagent package.
 type LoopBuilder interface {
-	BuildCronLoop(agentID string, toolFilter []string, loopOverrides agent.LoopConfig) (*agent.Loop, string, error)
+	// extraTools are added to the loop regardless of toolFilter.
+	BuildCronLoop(agentID string, toolFilter []string, extraTools []tool.Tool, loopOverrides agent.LoopConfig) (*agent.Loop, string, error)
 }
 
 // OutputSender sends cron results to a channel.
@@ -69,10 +115,15 @@ type OutputSender interface {
 type PromptJob struct {
 	Def     PromptCronDef
 	AgentID string
-	Builder LoopBuilder
-	Sender  OutputSender // nil = no channel output
-	DataDir string
-	Logger  *slog.Logger
+	// Timezone is the agent's zone, used when Def names none. Empty means
+	// the server's local zone.
+	Timezone  string
+	Builder   LoopBuilder
+	Sender    OutputSender     // nil = no channel output
+	Submitter SessionSubmitter // nil = session-bound definitions fail
+	Alert     *FailureAlert    // nil = no failure alerts
+	DataDir   string
+	Logger    *slog.Logger
 }
 
 // Compile-time interface check.
@@ -85,7 +136,12 @@ func (j *PromptJob) Name() string {
 
 // Schedule implements Job.
 func (j *PromptJob) Schedule() string {
-	return j.Def.Schedule
+	spec, err := j.Def.ScheduleSpec(j.Timezone)
+	if err != nil {
+		// Reported by the scheduler when parsing the schedule.
+		return atPrefix + j.Def.At
+	}
+	return spec
 }
 
 // Run implements Job.
@@ -99,8 +155,19 @@ func (j *PromptJob) Run(ctx context.Context) error {
 		logger = slog.Default()
 	}
 
+	if j.Def.At != "" {
+		defer j.disable(logger)
+	}
+
 	logger.Info("prompt_cron: starting", "name", j.Def.Name, "agent", j.AgentID)
 
+	if j.Def.Message != "" {
+		return j.sendMessage(ctx, logger)
+	}
+	if j.Def.Session != nil {
+		return j.submitFollowup(ctx, logger)
+	}
+
 	// Build loop config from cron definition.
 	var loopCfg agent.LoopConfig
 	if j.Def.Loop.MaxIterations > 0 {
@@ -112,22 +179,20 @@ func (j *PromptJob) Run(ctx context.Context) error {
 		}
 	}
 
-	loop, systemPrompt, err := j.Builder.BuildCronLoop(j.AgentID, j.Def.Tools, loopCfg)
-	if err != nil {
-		return fmt.Errorf("prompt_cron: building loop for %q: %w", j.Def.Name, err)
-	}
-
-	// Build request with the prompt as a user message.
-	req := agent.Request{
-		Messages: []provider.LLMMessage{
-			{Role: provider.MessageRoleUser, Content: j.Def.Prompt},
-		},
-		SystemPrompt: systemPrompt,
-		Tools:        loop.ToolDefinitions(),
+	// Read the previous result before this run overwrites it. Changes are
+	// detected against the last successful run, so that a failure in
+	// between does not make an unchanged result look new.
+	var previous, lastSuccess *PromptCronResult
+	if j.Def.Output != nil && j.Def.Output.notifyPolicy() != NotifyAlways {
+		previous, _ = LoadResult(j.DataDir, j.Def.Name)
+		lastSuccess = previous
+		if previous != nil && previous.Error != "" {
+			lastSuccess, _ = LastSuccessfulRun(j.DataDir, j.Def.Name)
+		}
 	}
 
 	startTime := time.Now()
-	resp, runErr := loop.Run(ctx, req)
+	resp, attempts, runErr := j.runPrompt(ctx, loopCfg, lastSuccess, previous, logger)
 	duration := time.Since(startTime)
 
 	// Build result.
@@ -141,22 +206,26 @@ func (j *PromptJob) Run(ctx context.Context) error {
 		TotalTokens: resp.TotalUsage.TotalTokens,
 		Content:     resp.Content,
 	}
+	if attempts > 1 {
+		result.Attempts = attempts
+	}
 	if runErr != nil {
 		result.Error = runErr.Error()
 	}
 
-	// Write result to disk.
-	if saveErr := SaveResult(j.DataDir, result); saveErr != nil {
-		logger.Error("prompt_cron: failed to save result", "name", j.Def.Name, "error", saveErr)
-	}
-
-	// Send output to channel if configured.
-	if j.Def.Output != nil && j.Sender != nil && resp.Content != "" {
-		if sendErr := j.Sender.SendCronOutput(ctx, j.Def.Output.Channel, j.Def.Output.ChatID, resp.Content); sendErr != nil {
-			logger.Error("prompt_cron: failed to send output", "name", j.Def.Name, "error", sendErr)
+	// Send output to channel if configured and the notify policy allows it.
+	if j.Def.Output != nil && j.Sender != nil {
+		if text := j.Def.Output.notification(resp, lastSuccess); text != "" {
+			if sendErr := j.Sender.SendCronOutput(ctx, j.Def.Output.Channel, j.Def.Output.ChatID, text); sendErr != nil {
+				logger.Error("prompt_cron: failed to send output", "name", j.Def.Name, "error", sendErr)
+			} else {
+				result.Notified = true
+			}
 		}
 	}
 
+	j.record(ctx, result, logger)
+
 	logger.Info("prompt_cron: completed",
 		"name", j.Def.Name,
 		"agent", j.AgentID,
@@ -169,26 +238,202 @@ func (j *PromptJob) Run(ctx context.Context) error {
 	return runErr
 }
 
+// runPrompt runs the prompt through a fresh agent loop, retrying transient
+// provider errors as configured. lastSuccess is the baseline the agent
+// compares against and previous the latest run. It returns the response of
+// the last attempt and the number of attempts made.
+func (j *PromptJob) runPrompt(ctx context.Context, loopCfg agent.LoopConfig, lastSuccess, previous *PromptCronResult, logger *slog.Logger) (agent.Response, int, error) {
+	prompt := j.Def.Prompt
+	var extraTools []tool.Tool
+	if out := j.Def.Output; out != nil && out.notifyPolicy() != NotifyAlways {
+		prompt += "\n\n" + previousRunContext(lastSuccess, previous)
+		if out.notifyPolicy() == NotifyTool {
+			extraTools = []tool.Tool{notifyTool{}}
+		}
+	}
+
+	delay := j.Def.Retry.backoff()
+	for attempt := 1; ; attempt++ {
+		loop, systemPrompt, err := j.Builder.BuildCronLoop(j.AgentID, j.Def.Tools, extraTools, loopCfg)
+		if err != nil {
+			return agent.Response{}, attempt, fmt.Errorf("prompt_cron: building loop for %q: %w", j.Def.Name, err)
+		}
+
+		// Build request with the prompt as a user message.
+		req := agent.Request{
+			Messages: []provider.LLMMessage{
+				{Role: provider.MessageRoleUser, Content: prompt},
+			},
+			SystemPrompt: systemPrompt,
+			Tools:        loop.ToolDefinitions(),
+		}
+
+		resp, err := loop.Run(provider.WithAgentID(ctx, j.AgentID), req)
+		if err == nil || !provider.IsRetryable(err) || attempt > j.Def.Retry.MaxRetries {
+			return resp, attempt, err
+		}
+
+		logger.Warn("prompt_cron: transient failure, retrying",
+			"name", j.Def.Name, "attempt", attempt, "delay", delay, "error", err)
+		select {
+		case <-ctx.Done():
+			return resp, attempt, err
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, maxRetryBackoff)
+	}
+}
+
+// record stores a run result as the last result and in the run history,
+// and alerts when the run is the configured number of failures in a row.
+func (j *PromptJob) record(ctx context.Context, result PromptCronResult, logger *slog.Logger) {
+	if err := SaveResult(j.DataDir, result); err != nil {
+		logger.Error("prompt_cron: failed to save result", "name", j.Def.Name, "error", err)
+	}
+	if err := AppendHistory(j.DataDir, result); err != nil {
+		logger.Error("prompt_cron: failed to append history", "name", j.Def.Name, "error", err)
+		return
+	}
+
+	if result.Error == "" || j.Alert == nil || j.Alert.After <= 0 || j.Sender == nil {
+		return
+	}
+	failures, err := ConsecutiveFailures(j.DataDir, j.Def.Name)
+	if err != nil || failures != j.Alert.After {
+		return
+	}
+
+	text := fmt.Sprintf("Prompt cron %q of agent %q failed %d times in a row. Last error: %s",
+		j.Def.Name, j.AgentID, failures, result.Error)
+	if err := j.Sender.SendCronOutput(ctx, j.Alert.Channel, j.Alert.ChatID, text); err != nil {
+		logger.Error("prompt_cron: failed to send failure alert", "name", j.Def.Name, "error", err)
+	}
+}
+
+// sendMessage delivers a message definition to its output chat without
+// running the agent.
+func (j *PromptJob) sendMessage(ctx context.Context, logger *slog.Logger) error {
+	result := PromptCronResult{
+		Name:       j.Def.Name,
+		RanAt:      time.Now().UTC().Format(time.RFC3339),
+		StopReason: "message",
+		Content:    j.Def.Message,
+	}
+
+	var err error
+	if j.Sender == nil {
+		err = fmt.Errorf("prompt_cron: no output sender for %q", j.Def.Name)
+	} else if err = j.Sender.SendCronOutput(ctx, j.Def.Output.Channel, j.Def.Output.ChatID, j.Def.Message); err != nil {
+		err = fmt.Errorf("prompt_cron: sending message for %q: %w", j.Def.Name, err)
+	}
+	if err != nil {
+		result.Error = err.Error()
+	} else {
+		result.Notified = true
+	}
+
+	j.record(ctx, result, logger)
+
+	logger.Info("prompt_cron: message sent", "name", j.Def.Name, "agent", j.AgentID)
+	return err
+}
+
+// disable turns a one-shot definition off on disk once it has fired, so it
+// is not scheduled again on the next reload.
+func (j *PromptJob) disable(logger *slog.Logger) {
+	def := j.Def
+	def.Enabled = false
+	if err := SavePromptCronDef(CronsDir(j.DataDir), def); err != nil {
+		logger.Error("prompt_cron: failed to disable one-shot cron", "name", j.Def.Name, "error", err)
+	}
+}
+
+// ScheduleSpec returns the scheduler expression of the definition, evaluated
+// in its timezone or else in fallbackTZ (empty for the server's local zone).
+func (d *PromptCronDef) ScheduleSpec(fallbackTZ string) (string, error) {
+	tz := d.Timezone
+	if tz == "" {
+		tz = fallbackTZ
+	}
+	if d.At == "" {
+		return WithTimezone(d.Schedule, tz), nil
+	}
+
+	loc, err := LoadLocation(tz)
+	if err != nil {
+		return "", fmt.Errorf("invalid timezone %q: %w", tz, err)
+	}
+	at, err := ParseAt(d.At, loc)
+	if err != nil {
+		return "", err
+	}
+	return AtSchedule(at), nil
+}
+
 // Validate checks that the definition has all required fields and valid values.
 func (d *PromptCronDef) Validate() error {
 	if d.Name == "" {
 		return fmt.Errorf("prompt cron: name is required")
 	}
-	if d.Schedule == "" {
-		return fmt.Errorf("prompt cron %q: schedule is required", d.Name)
+	switch {
+	case d.Schedule == "" && d.At == "":
+		return fmt.Errorf("prompt cron %q: schedule or at is required", d.Name)
+	case d.Schedule != "" && d.At != "":
+		return fmt.Errorf("prompt cron %q: schedule and at are mutually exclusive", d.Name)
+	}
+	switch {
+	case d.Prompt == "" && d.Message == "":
+		return fmt.Errorf("prompt cron %q: prompt or message is required", d.Name)
+	case d.Prompt != "" && d.Message != "":
+		return fmt.Errorf("prompt cron %q: prompt and message are mutually exclusive", d.Name)
+	case d.Message != "" && d.Output == nil:
+		return fmt.Errorf("prompt cron %q: message requires output", d.Name)
+	}
+	loc, err := LoadLocation(d.Timezone)
+	if err != nil {
+		return fmt.Errorf("prompt cron %q: invalid timezone %q: %w", d.Name, d.Timezone, err)
 	}
-	if d.Prompt == "" {
-		return fmt.Errorf("prompt cron %q: prompt is required", d.Name)
+	if d.At != "" {
+		if _, err := ParseAt(d.At, loc); err != nil {
+			return fmt.Errorf("prompt cron %q: %w", d.Name, err)
+		}
+	} else if _, err := parseSchedule(WithTimezone(d.Schedule, d.Timezone)); err != nil {
+		return fmt.Errorf("prompt cron %q: invalid schedule %q: %w", d.Name, d.Schedule, err)
 	}
 	if d.Loop.Timeout != "" {
 		if _, err := time.ParseDuration(d.Loop.Timeout); err != nil {
 			return fmt.Errorf("prompt cron %q: invalid timeout %q: %w", d.Name, d.Loop.Timeout, err)
 		}
 	}
+	if d.Retry.MaxRetries < 0 {
+		return fmt.Errorf("prompt cron %q: retry.max_retries must not be negative", d.Name)
+	}
+	if d.Retry.Backoff != "" {
+		if b, err := time.ParseDuration(d.Retry.Backoff); err != nil || b <= 0 {
+			return fmt.Errorf("prompt cron %q: invalid retry backoff %q", d.Name, d.Retry.Backoff)
+		}
+	}
 	if d.Output != nil {
 		if d.Output.Channel == "" || d.Output.ChatID == "" {
 			return fmt.Errorf("prompt cron %q: output requires both channel and chat_id", d.Name)
 		}
+		if err := d.Output.validate(); err != nil {
+			return fmt.Errorf("prompt cron %q: %w", d.Name, err)
+		}
+		if d.Message != "" && d.Output.notifyPolicy() != NotifyAlways {
+			return fmt.Errorf("prompt cron %q: message crons always notify", d.Name)
+		}
+	}
+	if d.Session != nil {
+		switch {
+		case d.Prompt == "":
+			return fmt.Errorf("prompt cron %q: session requires prompt", d.Name)
+		case d.Output != nil:
+			return fmt.Errorf("prompt cron %q: session and output are mutually exclusive", d.Name)
+		}
+		if err := d.Session.validate(); err != nil {
+			return fmt.Errorf("prompt cron %q: %w", d.Name, err)
+		}
 	}
 	return nil
 }
@@ -220,6 +465,22 @@ func LoadPromptCronDef(path string) (*PromptCronDef, error) {
 	return &def, nil
 }
 
+// SavePromptCronDef writes a definition to dir as <name>.json, overwriting
+// any previous version.
+func SavePromptCronDef(dir string, def PromptCronDef) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("creating crons dir: %w", err)
+	}
+
+	data, err := json.MarshalIndent(def, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling prompt cron: %w", err)
+	}
+
+	path := filepath.Join(dir, def.Name+".json")
+	return os.WriteFile(path, data, 0o644)
+}
+
 // SaveResult writes the last-run result to disk, overwriting any previous result.
 func SaveResult(dataDir string, result PromptCronResult) error {
 	dir := ResultsDir(dataDir)
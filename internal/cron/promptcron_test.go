@@ -3,13 +3,16 @@ package cron
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/flemzord/sclaw/internal/agent"
 	"github.com/flemzord/sclaw/internal/provider"
+	"github.com/flemzord/sclaw/internal/tool"
 )
 
 func writeTestFile(t *testing.T, path string, data []byte) {
@@ -70,6 +73,96 @@ func TestPromptCronDef_Validate(t *testing.T) {
 			def:     PromptCronDef{Name: "test", Schedule: "* * * * *", Prompt: "hello", Output: &PromptCronOutput{Channel: "channel.telegram", ChatID: "123"}},
 			wantErr: false,
 		},
+		{
+			name:    "valid notify match",
+			def:     PromptCronDef{Name: "test", Schedule: "* * * * *", Prompt: "hello", Output: &PromptCronOutput{Channel: "channel.telegram", ChatID: "123", Notify: NotifyMatch, Match: map[string]string{"status": "^down$"}}},
+			wantErr: false,
+		},
+		{
+			name:    "unknown notify policy",
+			def:     PromptCronDef{Name: "test", Schedule: "* * * * *", Prompt: "hello", Output: &PromptCronOutput{Channel: "channel.telegram", ChatID: "123", Notify: "sometimes"}},
+			wantErr: true,
+		},
+		{
+			name:    "notify match without match",
+			def:     PromptCronDef{Name: "test", Schedule: "* * * * *", Prompt: "hello", Output: &PromptCronOutput{Channel: "channel.telegram", ChatID: "123", Notify: NotifyMatch}},
+			wantErr: true,
+		},
+		{
+			name:    "match with invalid pattern",
+			def:     PromptCronDef{Name: "test", Schedule: "* * * * *", Prompt: "hello", Output: &PromptCronOutput{Channel: "channel.telegram", ChatID: "123", Notify: NotifyMatch, Match: map[string]string{"status": "("}}},
+			wantErr: true,
+		},
+		{
+			name:    "message with notify changed",
+			def:     PromptCronDef{Name: "test", Schedule: "* * * * *", Message: "hi", Output: &PromptCronOutput{Channel: "channel.telegram", ChatID: "123", Notify: NotifyChanged}},
+			wantErr: true,
+		},
+		{
+			name:    "valid session",
+			def:     PromptCronDef{Name: "test", At: "2026-03-14 09:00", Prompt: "hello", Session: &PromptCronSession{Channel: "channel.telegram", ChatID: "1", SenderID: "2"}},
+			wantErr: false,
+		},
+		{
+			name:    "session without sender",
+			def:     PromptCronDef{Name: "test", At: "2026-03-14 09:00", Prompt: "hello", Session: &PromptCronSession{Channel: "channel.telegram", ChatID: "1"}},
+			wantErr: true,
+		},
+		{
+			name:    "session with message",
+			def:     PromptCronDef{Name: "test", At: "2026-03-14 09:00", Message: "hi", Output: &PromptCronOutput{Channel: "channel.telegram", ChatID: "1"}, Session: &PromptCronSession{Channel: "channel.telegram", ChatID: "1", SenderID: "2"}},
+			wantErr: true,
+		},
+		{
+			name:    "session and output",
+			def:     PromptCronDef{Name: "test", At: "2026-03-14 09:00", Prompt: "hello", Output: &PromptCronOutput{Channel: "channel.telegram", ChatID: "1"}, Session: &PromptCronSession{Channel: "channel.telegram", ChatID: "1", SenderID: "2"}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid schedule",
+			def:     PromptCronDef{Name: "test", Schedule: "every day", Prompt: "hello"},
+			wantErr: true,
+		},
+		{
+			name:    "valid timezone",
+			def:     PromptCronDef{Name: "test", Schedule: "0 9 * * *", Timezone: "UTC", Prompt: "hello"},
+			wantErr: false,
+		},
+		{
+			name:    "invalid timezone",
+			def:     PromptCronDef{Name: "test", Schedule: "0 9 * * *", Timezone: "Mars/Olympus", Prompt: "hello"},
+			wantErr: true,
+		},
+		{
+			name:    "valid at",
+			def:     PromptCronDef{Name: "test", At: "2026-03-14 09:00", Prompt: "hello"},
+			wantErr: false,
+		},
+		{
+			name:    "invalid at",
+			def:     PromptCronDef{Name: "test", At: "next week", Prompt: "hello"},
+			wantErr: true,
+		},
+		{
+			name:    "schedule and at",
+			def:     PromptCronDef{Name: "test", Schedule: "* * * * *", At: "2026-03-14 09:00", Prompt: "hello"},
+			wantErr: true,
+		},
+		{
+			name:    "valid message",
+			def:     PromptCronDef{Name: "test", At: "2026-03-14T09:00:00Z", Message: "hi", Output: &PromptCronOutput{Channel: "channel.telegram", ChatID: "123"}},
+			wantErr: false,
+		},
+		{
+			name:    "message without output",
+			def:     PromptCronDef{Name: "test", Schedule: "* * * * *", Message: "hi"},
+			wantErr: true,
+		},
+		{
+			name:    "prompt and message",
+			def:     PromptCronDef{Name: "test", Schedule: "* * * * *", Prompt: "hello", Message: "hi", Output: &PromptCronOutput{Channel: "channel.telegram", ChatID: "123"}},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -165,14 +258,25 @@ func TestPromptJob_Run_Disabled(t *testing.T) {
 type mockLoopBuilder struct {
 	resp agent.Response
 	err  error
+
+	// providerErrs are returned by the provider of successive loops, before
+	// it starts returning resp.
+	providerErrs []error
+	builds       int
+	extraTools   []tool.Tool
 }
 
-func (m *mockLoopBuilder) BuildCronLoop(_ string, _ []string, _ agent.LoopConfig) (*agent.Loop, string, error) {
+func (m *mockLoopBuilder) BuildCronLoop(_ string, _ []string, extraTools []tool.Tool, _ agent.LoopConfig) (*agent.Loop, string, error) {
+	m.builds++
+	m.extraTools = extraTools
 	if m.err != nil {
 		return nil, "", m.err
 	}
 	// Create a minimal loop with a mock provider that returns our canned response.
 	p := &mockProvider{resp: m.resp}
+	if len(m.providerErrs) > 0 {
+		p.err, m.providerErrs = m.providerErrs[0], m.providerErrs[1:]
+	}
 	loop := agent.NewLoop(p, nil, agent.LoopConfig{
 		MaxIterations: 1,
 		Timeout:       10 * time.Second,
@@ -183,9 +287,13 @@ func (m *mockLoopBuilder) BuildCronLoop(_ string, _ []string, _ agent.LoopConfig
 // mockProvider returns a canned response from Complete.
 type mockProvider struct {
 	resp agent.Response
+	err  error
 }
 
 func (p *mockProvider) Complete(_ context.Context, _ provider.CompletionRequest) (provider.CompletionResponse, error) {
+	if p.err != nil {
+		return provider.CompletionResponse{}, p.err
+	}
 	return provider.CompletionResponse{
 		Content: p.resp.Content,
 		Usage:   provider.TokenUsage{TotalTokens: p.resp.TotalUsage.TotalTokens},
@@ -279,6 +387,159 @@ func TestPromptJob_Run_Success(t *testing.T) {
 	}
 }
 
+func TestPromptJob_Schedule(t *testing.T) {
+	tests := []struct {
+		name     string
+		def      PromptCronDef
+		timezone string
+		want     string
+	}{
+		{"server zone", PromptCronDef{Schedule: "0 9 * * *"}, "", "0 9 * * *"},
+		{"agent zone", PromptCronDef{Schedule: "0 9 * * *"}, "Europe/Paris", "CRON_TZ=Europe/Paris 0 9 * * *"},
+		{"own zone", PromptCronDef{Schedule: "0 9 * * *", Timezone: "UTC"}, "Europe/Paris", "CRON_TZ=UTC 0 9 * * *"},
+		{"explicit prefix", PromptCronDef{Schedule: "TZ=UTC 0 9 * * *"}, "Europe/Paris", "TZ=UTC 0 9 * * *"},
+		{"at in zone", PromptCronDef{At: "2026-03-14 09:00", Timezone: "UTC"}, "", "@at 2026-03-14T09:00:00Z"},
+		{"at absolute", PromptCronDef{At: "2026-03-14T09:00:00+01:00"}, "UTC", "@at 2026-03-14T09:00:00+01:00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			j := &PromptJob{Def: tt.def, Timezone: tt.timezone}
+			if got := j.Schedule(); got != tt.want {
+				t.Errorf("Schedule() = %q, want %q", got, tt.want)
+			}
+			if _, err := parseSchedule(j.Schedule()); err != nil {
+				t.Errorf("parseSchedule(%q): %v", j.Schedule(), err)
+			}
+		})
+	}
+}
+
+func TestPromptJob_Run_OneShotMessage(t *testing.T) {
+	dir := t.TempDir()
+	sender := &mockOutputSender{}
+	def := PromptCronDef{
+		Name:    "reminder",
+		At:      "2026-03-14T09:00:00Z",
+		Enabled: true,
+		Message: "Reminder: call mom",
+		Output:  &PromptCronOutput{Channel: "channel.telegram", ChatID: "12345"},
+	}
+	if err := SavePromptCronDef(CronsDir(dir), def); err != nil {
+		t.Fatalf("saving def: %v", err)
+	}
+
+	j := &PromptJob{Def: def, AgentID: "main", Sender: sender, DataDir: dir}
+	if err := j.Run(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The message is sent verbatim, without building a loop.
+	if len(sender.calls) != 1 || sender.calls[0].text != "Reminder: call mom" {
+		t.Fatalf("send calls = %+v", sender.calls)
+	}
+
+	result, err := LoadResult(dir, "reminder")
+	if err != nil {
+		t.Fatalf("loading result: %v", err)
+	}
+	if result.StopReason != "message" || result.Error != "" {
+		t.Errorf("result = %+v", result)
+	}
+
+	// The definition is disabled on disk once it has fired.
+	saved, err := LoadPromptCronDef(filepath.Join(CronsDir(dir), "reminder.json"))
+	if err != nil {
+		t.Fatalf("loading def: %v", err)
+	}
+	if saved.Enabled {
+		t.Error("one-shot cron still enabled after firing")
+	}
+}
+
+func TestPromptJob_Run_RetriesTransientErrors(t *testing.T) {
+	dir := t.TempDir()
+	builder := &mockLoopBuilder{
+		resp:         agent.Response{Content: "done"},
+		providerErrs: []error{provider.ErrRateLimit, provider.ErrProviderDown},
+	}
+	j := &PromptJob{
+		Def: PromptCronDef{
+			Name: "retried", Schedule: "* * * * *", Enabled: true, Prompt: "go",
+			Retry: PromptCronRetry{MaxRetries: 2, Backoff: "1ms"},
+		},
+		AgentID: "main",
+		Builder: builder,
+		DataDir: dir,
+	}
+
+	if err := j.Run(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if builder.builds != 3 {
+		t.Errorf("builds = %d, want 3", builder.builds)
+	}
+	result, err := LoadResult(dir, "retried")
+	if err != nil {
+		t.Fatalf("loading result: %v", err)
+	}
+	if result.Attempts != 3 || result.Error != "" || result.Content != "done" {
+		t.Errorf("result = %+v", result)
+	}
+}
+
+func TestPromptJob_Run_NoRetryOnPermanentError(t *testing.T) {
+	builder := &mockLoopBuilder{providerErrs: []error{provider.ErrAuthentication}}
+	j := &PromptJob{
+		Def: PromptCronDef{
+			Name: "auth", Schedule: "* * * * *", Enabled: true, Prompt: "go",
+			Retry: PromptCronRetry{MaxRetries: 3, Backoff: "1ms"},
+		},
+		AgentID: "main",
+		Builder: builder,
+		DataDir: t.TempDir(),
+	}
+
+	if err := j.Run(context.Background()); !errors.Is(err, provider.ErrAuthentication) {
+		t.Fatalf("error = %v, want ErrAuthentication", err)
+	}
+	if builder.builds != 1 {
+		t.Errorf("builds = %d, want 1", builder.builds)
+	}
+}
+
+func TestPromptJob_Run_AlertsAfterConsecutiveFailures(t *testing.T) {
+	dir := t.TempDir()
+	sender := &mockOutputSender{}
+	j := &PromptJob{
+		Def:     PromptCronDef{Name: "flaky", Schedule: "* * * * *", Enabled: true, Prompt: "go"},
+		AgentID: "main",
+		Builder: &mockLoopBuilder{err: errors.New("no provider")},
+		Sender:  sender,
+		Alert:   &FailureAlert{Channel: "channel.telegram", ChatID: "ops", After: 2},
+		DataDir: dir,
+	}
+
+	for range 3 {
+		_ = j.Run(context.Background())
+	}
+
+	// Alerted once, when the second failure in a row was recorded.
+	if len(sender.calls) != 1 {
+		t.Fatalf("send calls = %+v, want one alert", sender.calls)
+	}
+	if call := sender.calls[0]; call.chatID != "ops" || !strings.Contains(call.text, "failed 2 times in a row") {
+		t.Errorf("alert = %+v", call)
+	}
+
+	runs, total, err := LoadHistory(dir, "flaky", 0, 0)
+	if err != nil {
+		t.Fatalf("loading history: %v", err)
+	}
+	if total != 3 || len(runs) != 3 || runs[0].Error == "" {
+		t.Errorf("history = %d runs (total %d): %+v", len(runs), total, runs)
+	}
+}
+
 func TestSaveResult(t *testing.T) {
 	dir := t.TempDir()
 	result := PromptCronResult{
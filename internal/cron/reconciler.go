@@ -0,0 +1,272 @@
+package cron
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultReconcileInterval is how often crons directories are polled for
+// changes when ReconcilerConfig.PollInterval is zero.
+const defaultReconcileInterval = 2 * time.Second
+
+// PromptCronAgent identifies an agent whose prompt crons are reconciled.
+type PromptCronAgent struct {
+	ID       string
+	DataDir  string
+	Timezone string        // zone of crons naming none; empty = server local
+	Alert    *FailureAlert // nil = no failure alerts
+}
+
+// ReconcilerConfig configures a Reconciler.
+type ReconcilerConfig struct {
+	Scheduler *Scheduler
+	Trigger   *Trigger // optional; kept in sync with the scheduled jobs
+	Builder   LoopBuilder
+	Sender    OutputSender     // nil = no channel output
+	Submitter SessionSubmitter // nil = follow-ups fail
+	Logger    *slog.Logger
+
+	// PollInterval is how often crons directories are checked for changes.
+	// Defaults to 2 seconds if zero.
+	PollInterval time.Duration
+}
+
+// Reconciler keeps the prompt jobs of a scheduler in sync with the
+// definitions in each agent's crons directory. It reconciles when asked and,
+// once started, whenever a directory changes on disk.
+type Reconciler struct {
+	cfg ReconcilerConfig
+
+	mu     sync.Mutex
+	agents []PromptCronAgent
+	jobs   map[string]*PromptJob // keyed by job name
+	stamps map[string]string     // crons dir → fingerprint at last reconcile
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewReconciler creates a Reconciler with no agents.
+func NewReconciler(cfg ReconcilerConfig) *Reconciler {
+	if cfg.Logger == nil {
+		cfg.Logger = slog.Default()
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = defaultReconcileInterval
+	}
+	return &Reconciler{
+		cfg:    cfg,
+		jobs:   make(map[string]*PromptJob),
+		stamps: make(map[string]string),
+	}
+}
+
+// SetAgents replaces the reconciled agents and reconciles. Prompt jobs of
+// agents no longer listed are removed.
+func (r *Reconciler) SetAgents(agents []PromptCronAgent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.agents = agents
+	clear(r.stamps)
+	r.reconcile()
+}
+
+// Reconcile rescans every crons directory and registers, replaces or
+// removes prompt jobs so that the scheduler matches the files on disk.
+// Invalid definitions are logged and left unscheduled.
+func (r *Reconciler) Reconcile() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reconcile()
+}
+
+// reconcile implements Reconcile. The caller holds mu.
+func (r *Reconciler) reconcile() {
+	want := make(map[string]*PromptJob)
+	for _, agent := range r.agents {
+		dir := CronsDir(agent.DataDir)
+		r.stamps[dir] = dirStamp(dir)
+
+		defs, errs := ScanPromptCrons(dir)
+		for _, e := range errs {
+			r.cfg.Logger.Error("cron: invalid prompt cron", "agent", agent.ID, "error", e)
+		}
+		for _, def := range defs {
+			job := &PromptJob{
+				Def:       def,
+				AgentID:   agent.ID,
+				Timezone:  agent.Timezone,
+				Builder:   r.cfg.Builder,
+				Sender:    r.cfg.Sender,
+				Submitter: r.cfg.Submitter,
+				Alert:     agent.Alert,
+				DataDir:   agent.DataDir,
+				Logger:    r.cfg.Logger,
+			}
+			want[job.Name()] = job
+		}
+	}
+
+	for name, old := range r.jobs {
+		if job, ok := want[name]; ok && sameJob(old, job) {
+			delete(want, name)
+			continue
+		}
+		r.cfg.Scheduler.RemoveJob(name)
+		if r.cfg.Trigger != nil {
+			r.cfg.Trigger.Unregister(old)
+		}
+		delete(r.jobs, name)
+		r.cfg.Logger.Debug("cron: prompt cron unscheduled", "job", name)
+	}
+
+	for name, job := range want {
+		if err := r.cfg.Scheduler.RegisterJob(job); err != nil {
+			r.cfg.Logger.Error("cron: registering prompt cron",
+				"agent", job.AgentID, "cron", job.Def.Name, "error", err)
+			continue
+		}
+		if r.cfg.Trigger != nil {
+			r.cfg.Trigger.Register(job)
+		}
+		r.jobs[name] = job
+		r.cfg.Logger.Debug("cron: prompt cron scheduled", "job", name)
+	}
+}
+
+// sameJob reports whether two prompt jobs would behave identically.
+func sameJob(a, b *PromptJob) bool {
+	return a.Timezone == b.Timezone && a.DataDir == b.DataDir &&
+		reflect.DeepEqual(a.Def, b.Def) && reflect.DeepEqual(a.Alert, b.Alert)
+}
+
+// Start runs the catch-up crons that missed a run while sclaw was down,
+// then polls the crons directories and reconciles when one changes, until
+// ctx is cancelled or Stop is called. The scheduler must be started first.
+// Safe to call once.
+func (r *Reconciler) Start(ctx context.Context) {
+	r.catchUp(time.Now())
+
+	ctx, cancel := context.WithCancel(ctx)
+	r.cancel = cancel
+	r.done = make(chan struct{})
+
+	go func() {
+		defer close(r.done)
+
+		ticker := time.NewTicker(r.cfg.PollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if r.changed() {
+					r.Reconcile()
+				}
+			}
+		}
+	}()
+}
+
+// Stop stops polling and waits for an in-progress reconcile to finish.
+// Safe to call before Start.
+func (r *Reconciler) Stop() {
+	if r.cancel == nil {
+		return
+	}
+	r.cancel()
+	<-r.done
+}
+
+// catchUp runs every catch-up cron that missed a run before now.
+func (r *Reconciler) catchUp(now time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for name, job := range r.jobs {
+		if !missedRun(job, now) {
+			continue
+		}
+		r.cfg.Logger.Info("cron: catching up missed run", "job", name)
+		r.cfg.Scheduler.RunNow(name)
+	}
+}
+
+// missedRun reports whether a catch-up cron should have run between its last
+// run and now. One-shot crons are disabled once they fire, so an enabled one
+// whose time has passed has missed it.
+func missedRun(job *PromptJob, now time.Time) bool {
+	if !job.Def.CatchUp || !job.Def.Enabled {
+		return false
+	}
+	schedule, err := parseSchedule(job.Schedule())
+	if err != nil {
+		return false
+	}
+
+	var last time.Time
+	if job.Def.At == "" {
+		result, err := LoadResult(job.DataDir, job.Def.Name)
+		if err != nil {
+			return false // never ran: nothing to catch up
+		}
+		if last, err = time.Parse(time.RFC3339, result.RanAt); err != nil {
+			return false
+		}
+	}
+
+	next := schedule.Next(last)
+	return !next.IsZero() && !next.After(now)
+}
+
+// changed reports whether any crons directory differs from the last
+// reconcile.
+func (r *Reconciler) changed() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, agent := range r.agents {
+		dir := CronsDir(agent.DataDir)
+		if dirStamp(dir) != r.stamps[dir] {
+			return true
+		}
+	}
+	return false
+}
+
+// dirStamp fingerprints the definition files of a crons directory by name,
+// size and modification time.
+func dirStamp(dir string) string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return ""
+	}
+
+	var b strings.Builder
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		b.WriteString(entry.Name())
+		b.WriteByte('\x00')
+		b.WriteString(info.ModTime().Format(time.RFC3339Nano))
+		b.WriteByte('\x00')
+		b.WriteString(strconv.FormatInt(info.Size(), 10))
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
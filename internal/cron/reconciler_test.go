@@ -0,0 +1,124 @@
+package cron
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func saveTestDef(t *testing.T, dir string, def PromptCronDef) {
+	t.Helper()
+	if err := SavePromptCronDef(dir, def); err != nil {
+		t.Fatalf("saving %s: %v", def.Name, err)
+	}
+}
+
+func TestReconciler_Reconcile(t *testing.T) {
+	t.Parallel()
+
+	dataDir := t.TempDir()
+	dir := CronsDir(dataDir)
+	s := NewScheduler(slog.Default())
+	trigger := NewTrigger()
+	r := NewReconciler(ReconcilerConfig{Scheduler: s, Trigger: trigger})
+
+	saveTestDef(t, dir, PromptCronDef{Name: "daily", Schedule: "0 9 * * *", Enabled: true, Prompt: "hi"})
+	r.SetAgents([]PromptCronAgent{{ID: "main", DataDir: dataDir}})
+
+	if got := s.JobNames(); !slices.Equal(got, []string{"prompt_cron:main:daily"}) {
+		t.Fatalf("JobNames() = %v", got)
+	}
+	if _, ok := trigger.Get("daily"); !ok {
+		t.Fatal("daily not registered with the trigger")
+	}
+
+	// Add one, change the other.
+	saveTestDef(t, dir, PromptCronDef{Name: "weekly", Schedule: "0 9 * * 1", Enabled: true, Prompt: "hi"})
+	saveTestDef(t, dir, PromptCronDef{Name: "daily", Schedule: "0 8 * * *", Enabled: true, Prompt: "hi"})
+	r.Reconcile()
+
+	got := s.JobNames()
+	slices.Sort(got)
+	if !slices.Equal(got, []string{"prompt_cron:main:daily", "prompt_cron:main:weekly"}) {
+		t.Fatalf("JobNames() = %v", got)
+	}
+	if info, _ := trigger.Get("daily"); info.Schedule != "0 8 * * *" {
+		t.Errorf("daily schedule = %q, want the updated one", info.Schedule)
+	}
+
+	// Remove one.
+	if err := os.Remove(filepath.Join(dir, "daily.json")); err != nil {
+		t.Fatal(err)
+	}
+	r.Reconcile()
+	if got := s.JobNames(); !slices.Equal(got, []string{"prompt_cron:main:weekly"}) {
+		t.Fatalf("JobNames() = %v", got)
+	}
+	if _, ok := trigger.Get("daily"); ok {
+		t.Error("deleted cron still registered with the trigger")
+	}
+
+	// Dropping the agent unschedules its crons.
+	r.SetAgents(nil)
+	if got := s.JobNames(); len(got) != 0 {
+		t.Errorf("JobNames() = %v, want none", got)
+	}
+}
+
+func TestReconciler_WatchesDirectory(t *testing.T) {
+	t.Parallel()
+
+	dataDir := t.TempDir()
+	s := NewScheduler(slog.Default())
+	r := NewReconciler(ReconcilerConfig{Scheduler: s, PollInterval: 20 * time.Millisecond})
+	r.SetAgents([]PromptCronAgent{{ID: "main", DataDir: dataDir}})
+
+	r.Start(context.Background())
+	defer r.Stop()
+
+	saveTestDef(t, CronsDir(dataDir), PromptCronDef{Name: "daily", Schedule: "0 9 * * *", Enabled: true, Prompt: "hi"})
+
+	deadline := time.Now().Add(3 * time.Second)
+	for len(s.JobNames()) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("new definition file was never scheduled")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestMissedRun(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 3, 14, 12, 0, 0, 0, time.UTC)
+	dataDir := t.TempDir()
+	if err := SaveResult(dataDir, PromptCronResult{Name: "daily", RanAt: "2026-03-13T09:00:00Z"}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		def  PromptCronDef
+		want bool
+	}{
+		{"missed daily run", PromptCronDef{Name: "daily", Schedule: "CRON_TZ=UTC 0 9 * * *", CatchUp: true, Enabled: true}, true},
+		{"catch-up off", PromptCronDef{Name: "daily", Schedule: "CRON_TZ=UTC 0 9 * * *", Enabled: true}, false},
+		{"disabled", PromptCronDef{Name: "daily", Schedule: "CRON_TZ=UTC 0 9 * * *", CatchUp: true}, false},
+		{"nothing missed", PromptCronDef{Name: "daily", Schedule: "CRON_TZ=UTC 0 9 * * 1", CatchUp: true, Enabled: true}, false},
+		{"never ran", PromptCronDef{Name: "other", Schedule: "CRON_TZ=UTC 0 9 * * *", CatchUp: true, Enabled: true}, false},
+		{"one-shot passed", PromptCronDef{Name: "remind", At: "2026-03-14T11:00:00Z", CatchUp: true, Enabled: true}, true},
+		{"one-shot ahead", PromptCronDef{Name: "remind", At: "2026-03-14T13:00:00Z", CatchUp: true, Enabled: true}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := &PromptJob{Def: tt.def, AgentID: "main", DataDir: dataDir}
+			if got := missedRun(job, now); got != tt.want {
+				t.Errorf("missedRun() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
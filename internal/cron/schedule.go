@@ -0,0 +1,90 @@
+package cron
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// atPrefix marks a one-shot schedule: "@at <RFC3339 time>".
+const atPrefix = "@at "
+
+// cronParser parses 5-field cron expressions. A CRON_TZ=<zone> or TZ=<zone>
+// prefix evaluates the expression in that zone.
+var cronParser = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow)
+
+// parseSchedule parses a job schedule: a cron expression, or "@at <time>"
+// for a job running once.
+func parseSchedule(spec string) (cron.Schedule, error) {
+	if at, ok := strings.CutPrefix(spec, atPrefix); ok {
+		t, err := time.Parse(time.RFC3339, strings.TrimSpace(at))
+		if err != nil {
+			return nil, fmt.Errorf("invalid time %q: %w", at, err)
+		}
+		return onceSchedule{at: t}, nil
+	}
+	return cronParser.Parse(spec)
+}
+
+// onceSchedule fires once at a fixed time.
+type onceSchedule struct {
+	at time.Time
+}
+
+// Next implements cron.Schedule. The zero time tells the scheduler the job
+// never runs again.
+func (s onceSchedule) Next(t time.Time) time.Time {
+	if t.Before(s.at) {
+		return s.at
+	}
+	return time.Time{}
+}
+
+// AtSchedule returns the schedule of a job running once at t.
+func AtSchedule(t time.Time) string {
+	return atPrefix + t.Format(time.RFC3339)
+}
+
+// WithTimezone evaluates a cron expression in the zone tz by prefixing it
+// with CRON_TZ. Expressions already naming a zone and empty zones are
+// returned unchanged.
+func WithTimezone(expr, tz string) string {
+	if tz == "" || strings.HasPrefix(expr, "CRON_TZ=") || strings.HasPrefix(expr, "TZ=") {
+		return expr
+	}
+	return "CRON_TZ=" + tz + " " + expr
+}
+
+// LoadLocation returns the IANA time zone name, or the server's local zone
+// when name is empty.
+func LoadLocation(name string) (*time.Location, error) {
+	if name == "" {
+		return time.Local, nil
+	}
+	return time.LoadLocation(name)
+}
+
+// atLayouts are the wall-clock layouts accepted by ParseAt besides RFC 3339.
+var atLayouts = []string{
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+}
+
+// ParseAt parses the time of a one-shot job: an RFC 3339 time, or a date
+// and wall-clock time such as "2026-03-14 09:00" in loc.
+func ParseAt(value string, loc *time.Location) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	for _, layout := range atLayouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q (want RFC 3339 or YYYY-MM-DD HH:MM)", value)
+}
@@ -0,0 +1,75 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSchedule_At(t *testing.T) {
+	at := time.Date(2026, 3, 14, 9, 0, 0, 0, time.UTC)
+	sched, err := parseSchedule(AtSchedule(at))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := sched.Next(at.Add(-time.Hour)); !got.Equal(at) {
+		t.Errorf("Next before = %v, want %v", got, at)
+	}
+	if got := sched.Next(at); !got.IsZero() {
+		t.Errorf("Next at = %v, want zero", got)
+	}
+	if got := sched.Next(at.Add(time.Hour)); !got.IsZero() {
+		t.Errorf("Next after = %v, want zero", got)
+	}
+
+	if _, err := parseSchedule("@at tomorrow"); err == nil {
+		t.Error("expected error for invalid at time")
+	}
+}
+
+func TestParseSchedule_Timezone(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skipf("tzdata unavailable: %v", err)
+	}
+	sched, err := parseSchedule(WithTimezone("0 9 * * *", "Asia/Tokyo"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := sched.Next(time.Date(2026, 3, 14, 0, 0, 0, 0, time.UTC))
+	want := time.Date(2026, 3, 15, 9, 0, 0, 0, tokyo)
+	if !got.Equal(want) {
+		t.Errorf("Next = %v, want %v", got, want)
+	}
+
+	if _, err := parseSchedule(WithTimezone("0 9 * * *", "Mars/Olympus")); err == nil {
+		t.Error("expected error for unknown timezone")
+	}
+}
+
+func TestParseAt(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"2026-03-14T09:00:00+01:00", time.Date(2026, 3, 14, 8, 0, 0, 0, time.UTC)},
+		{"2026-03-14T09:00", time.Date(2026, 3, 14, 9, 0, 0, 0, time.UTC)},
+		{"2026-03-14 09:00:30", time.Date(2026, 3, 14, 9, 0, 30, 0, time.UTC)},
+		{" 2026-03-14 09:00 ", time.Date(2026, 3, 14, 9, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := ParseAt(tt.in, time.UTC)
+		if err != nil {
+			t.Errorf("ParseAt(%q): %v", tt.in, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("ParseAt(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+
+	if _, err := ParseAt("14/03/2026", time.UTC); err == nil {
+		t.Error("expected error for unsupported layout")
+	}
+}
@@ -4,38 +4,47 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"slices"
 	"sync"
+	"time"
 
 	"github.com/robfig/cron/v3"
 )
 
 // Scheduler manages periodic job execution using cron expressions.
 // Each job is protected by a per-job mutex to prevent parallel execution
-// of the same job (uses TryLock — atomic, no race).
+// of the same job (uses TryLock — atomic, no race). Jobs can be registered
+// and removed before or after Start.
 type Scheduler struct {
-	mu     sync.Mutex
-	cron   *cron.Cron
-	jobs   []Job
-	names  map[string]struct{}
-	locks  map[string]*sync.Mutex
-	logger *slog.Logger
-	cancel context.CancelFunc
+	mu      sync.Mutex
+	cron    *cron.Cron
+	ctx     context.Context
+	jobs    []Job
+	names   map[string]struct{}
+	states  map[string]*jobState
+	entries map[string]cron.EntryID
+	logger  *slog.Logger
+	cancel  context.CancelFunc
+	manual  sync.WaitGroup // runs started by RunNow
 }
 
-// NewScheduler creates a scheduler. Jobs must be registered before Start().
+// NewScheduler creates a scheduler.
 func NewScheduler(logger *slog.Logger) *Scheduler {
 	if logger == nil {
 		logger = slog.Default()
 	}
 	return &Scheduler{
-		names:  make(map[string]struct{}),
-		locks:  make(map[string]*sync.Mutex),
-		logger: logger,
+		names:   make(map[string]struct{}),
+		states:  make(map[string]*jobState),
+		entries: make(map[string]cron.EntryID),
+		logger:  logger,
 	}
 }
 
-// RegisterJob adds a job to the scheduler. Must be called before Start().
-// Returns an error if a job with the same name is already registered.
+// RegisterJob adds a job to the scheduler. Once the scheduler is started,
+// the job is scheduled immediately and an invalid schedule is reported here
+// rather than by Start. Returns an error if a job with the same name is
+// already registered.
 func (s *Scheduler) RegisterJob(j Job) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -45,12 +54,63 @@ func (s *Scheduler) RegisterJob(j Job) error {
 		return fmt.Errorf("cron: duplicate job name %q", name)
 	}
 
+	// A job replaced while it runs keeps its state, so the run in progress
+	// still blocks overlapping runs of the new definition.
+	_, kept := s.states[name]
+	if !kept {
+		s.states[name] = &jobState{}
+	}
+	if s.cron != nil {
+		if err := s.schedule(j); err != nil {
+			if !kept {
+				delete(s.states, name)
+			}
+			return err
+		}
+	}
+
 	s.names[name] = struct{}{}
-	s.locks[name] = &sync.Mutex{}
 	s.jobs = append(s.jobs, j)
 	return nil
 }
 
+// RemoveJob unregisters a job by name. A run in progress is not
+// interrupted, and the job's state is kept until it ends so that a job
+// registered again under the same name does not overlap it. Returns false
+// if no job has that name.
+func (s *Scheduler) RemoveJob(name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.names[name]; !exists {
+		return false
+	}
+	if id, ok := s.entries[name]; ok {
+		s.cron.Remove(id)
+	}
+
+	delete(s.names, name)
+	if state := s.states[name]; state.lock.TryLock() {
+		state.lock.Unlock()
+		delete(s.states, name)
+	}
+	delete(s.entries, name)
+	s.jobs = slices.DeleteFunc(s.jobs, func(j Job) bool { return j.Name() == name })
+	return true
+}
+
+// JobNames returns the names of the registered jobs, in registration order.
+func (s *Scheduler) JobNames() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	names := make([]string, len(s.jobs))
+	for i, j := range s.jobs {
+		names[i] = j.Name()
+	}
+	return names
+}
+
 // Start initializes the cron scheduler and begins executing registered jobs.
 // Returns an error if any job has an invalid schedule expression.
 func (s *Scheduler) Start() error {
@@ -58,39 +118,17 @@ func (s *Scheduler) Start() error {
 	defer s.mu.Unlock()
 
 	ctx, cancel := context.WithCancel(context.Background())
+	s.ctx = ctx
 	s.cancel = cancel
 
-	parser := cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow)
-	s.cron = cron.New(cron.WithParser(parser))
-
-	for _, j := range s.jobs {
-		job := j // capture loop variable
-		lock := s.locks[job.Name()]
-
-		_, err := s.cron.AddFunc(job.Schedule(), func() {
-			// TryLock is atomic — no race between check and acquire.
-			// If the previous tick is still running, skip this one.
-			if !lock.TryLock() {
-				s.logger.Warn("cron: job still running, skipping tick",
-					"job", job.Name(),
-				)
-				return
-			}
-			defer lock.Unlock()
-
-			s.logger.Debug("cron: job started", "job", job.Name())
-			if err := job.Run(ctx); err != nil {
-				s.logger.Error("cron: job failed",
-					"job", job.Name(),
-					"error", err,
-				)
-			} else {
-				s.logger.Debug("cron: job completed", "job", job.Name())
-			}
-		})
-		if err != nil {
+	s.cron = cron.New(cron.WithParser(cronParser))
+
+	for _, job := range s.jobs {
+		if err := s.schedule(job); err != nil {
 			cancel()
-			return fmt.Errorf("cron: invalid schedule for job %q: %w", job.Name(), err)
+			s.cron = nil
+			clear(s.entries)
+			return err
 		}
 	}
 
@@ -99,17 +137,138 @@ func (s *Scheduler) Start() error {
 	return nil
 }
 
-// Stop gracefully shuts down the scheduler, waiting for in-flight jobs.
-func (s *Scheduler) Stop(_ context.Context) error {
+// schedule adds a registered job to the running cron. The caller holds mu.
+func (s *Scheduler) schedule(job Job) error {
+	schedule, err := parseSchedule(job.Schedule())
+	if err != nil {
+		return fmt.Errorf("cron: invalid schedule for job %q: %w", job.Name(), err)
+	}
+
+	s.entries[job.Name()] = s.cron.Schedule(schedule, s.runner(job))
+	return nil
+}
+
+// runner returns the function running a registered job on each tick. The
+// caller holds mu.
+func (s *Scheduler) runner(job Job) cron.FuncJob {
+	ctx := s.ctx
+	state := s.states[job.Name()]
+	return func() {
+		// TryLock is atomic — no race between check and acquire.
+		// If the previous tick is still running, skip this one.
+		if !state.lock.TryLock() {
+			state.skip()
+			s.logger.Warn("cron: job still running, skipping tick",
+				"job", job.Name(),
+			)
+			return
+		}
+		defer s.dropRemovedState(job.Name(), state)
+		defer state.lock.Unlock()
+
+		s.logger.Debug("cron: job started", "job", job.Name())
+		state.start(time.Now())
+		err := job.Run(ctx)
+		state.finish(time.Now(), err != nil)
+		if err != nil {
+			s.logger.Error("cron: job failed",
+				"job", job.Name(),
+				"error", err,
+			)
+		} else {
+			s.logger.Debug("cron: job completed", "job", job.Name())
+		}
+	}
+}
+
+// dropRemovedState forgets the state of a job removed while it ran.
+func (s *Scheduler) dropRemovedState(name string, state *jobState) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, registered := s.names[name]; !registered && s.states[name] == state {
+		delete(s.states, name)
+	}
+}
+
+// Status returns the live state of a registered job. Returns false if no
+// job has that name.
+func (s *Scheduler) Status(name string) (JobStatus, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, job := range s.jobs {
+		if job.Name() == name {
+			return s.status(job), true
+		}
+	}
+	return JobStatus{}, false
+}
+
+// Statuses returns the live state of every registered job, in registration
+// order.
+func (s *Scheduler) Statuses() []JobStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	statuses := make([]JobStatus, len(s.jobs))
+	for i, job := range s.jobs {
+		statuses[i] = s.status(job)
+	}
+	return statuses
+}
+
+// status builds the state of a registered job. The caller holds mu.
+func (s *Scheduler) status(job Job) JobStatus {
+	status := JobStatus{Name: job.Name(), Schedule: job.Schedule()}
+	if id, ok := s.entries[job.Name()]; ok {
+		entry := s.cron.Entry(id)
+		if !entry.Next.IsZero() {
+			next := entry.Next
+			status.NextRun = &next
+		}
+		if !entry.Prev.IsZero() {
+			prev := entry.Prev
+			status.PrevRun = &prev
+		}
+	}
+	s.states[job.Name()].fill(&status)
+	return status
+}
+
+// RunNow runs a registered job in the background, outside its schedule,
+// unless it is already running. Returns false if the scheduler is not
+// started or no job has that name.
+func (s *Scheduler) RunNow(name string) bool {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if s.cron == nil {
+		return false
+	}
+	for _, job := range s.jobs {
+		if job.Name() == name {
+			s.manual.Go(s.runner(job))
+			return true
+		}
+	}
+	return false
+}
+
+// Stop gracefully shuts down the scheduler, waiting for in-flight jobs.
+// Jobs may register or remove jobs while it waits.
+func (s *Scheduler) Stop(_ context.Context) error {
+	s.mu.Lock()
 	if s.cancel != nil {
 		s.cancel()
 	}
-	if s.cron != nil {
+	c := s.cron
+	s.mu.Unlock()
+
+	if c != nil {
 		// Wait for running jobs to complete.
-		<-s.cron.Stop().Done()
+		<-c.Stop().Done()
+		s.manual.Wait()
 		s.logger.Info("cron: scheduler stopped")
 	}
 	return nil
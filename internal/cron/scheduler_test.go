@@ -114,7 +114,7 @@ func TestScheduler_NoParallelExecution(t *testing.T) {
 	}
 
 	// Manually trigger the job multiple times concurrently to test TryLock.
-	lock := s.locks["slow"]
+	lock := &s.states["slow"].lock
 	var wg sync.WaitGroup
 	for range 10 {
 		wg.Add(1)
@@ -171,3 +171,284 @@ func TestScheduler_StopWithoutStart(t *testing.T) {
 		t.Fatalf("stop failed: %v", err)
 	}
 }
+
+func TestScheduler_RegisterJob_AfterStart(t *testing.T) {
+	t.Parallel()
+
+	s := NewScheduler(slog.Default())
+	if err := s.Start(); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	defer func() { _ = s.Stop(context.Background()) }()
+
+	ran := make(chan struct{}, 1)
+	err := s.RegisterJob(&simpleJob{
+		name:     "live",
+		schedule: AtSchedule(time.Now().Truncate(time.Second).Add(time.Second)),
+		runFunc: func(_ context.Context) error {
+			ran <- struct{}{}
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("register after start failed: %v", err)
+	}
+
+	select {
+	case <-ran:
+	case <-time.After(3 * time.Second):
+		t.Fatal("job registered after start never ran")
+	}
+
+	// Invalid schedules are reported on registration once started.
+	if err := s.RegisterJob(&simpleJob{name: "bad", schedule: "invalid"}); err == nil {
+		t.Fatal("expected error for invalid schedule")
+	}
+	if names := s.JobNames(); len(names) != 1 || names[0] != "live" {
+		t.Errorf("JobNames() = %v, want [live]", names)
+	}
+}
+
+func TestScheduler_RemoveJob(t *testing.T) {
+	t.Parallel()
+
+	s := NewScheduler(slog.Default())
+	job := &simpleJob{name: "removed", schedule: AtSchedule(time.Now().Truncate(time.Second).Add(time.Second))}
+	_ = s.RegisterJob(job)
+	if err := s.Start(); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	defer func() { _ = s.Stop(context.Background()) }()
+
+	if !s.RemoveJob("removed") {
+		t.Fatal("RemoveJob() = false, want true")
+	}
+	if s.RemoveJob("removed") {
+		t.Error("second RemoveJob() = true, want false")
+	}
+
+	time.Sleep(1200 * time.Millisecond)
+	job.mu.Lock()
+	calls := job.calls
+	job.mu.Unlock()
+	if calls != 0 {
+		t.Errorf("removed job ran %d times", calls)
+	}
+
+	// The name is free again.
+	if err := s.RegisterJob(&simpleJob{name: "removed", schedule: "* * * * *"}); err != nil {
+		t.Errorf("re-register failed: %v", err)
+	}
+}
+
+func TestScheduler_RunNow(t *testing.T) {
+	t.Parallel()
+
+	ran := make(chan struct{}, 1)
+	s := NewScheduler(slog.Default())
+	_ = s.RegisterJob(&simpleJob{
+		name:     "weekly",
+		schedule: "0 9 * * 1",
+		runFunc: func(_ context.Context) error {
+			ran <- struct{}{}
+			return nil
+		},
+	})
+
+	if s.RunNow("weekly") {
+		t.Fatal("RunNow() before Start = true, want false")
+	}
+	if err := s.Start(); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	defer func() { _ = s.Stop(context.Background()) }()
+
+	if s.RunNow("missing") {
+		t.Error("RunNow(missing) = true, want false")
+	}
+	if !s.RunNow("weekly") {
+		t.Fatal("RunNow() = false, want true")
+	}
+	select {
+	case <-ran:
+	case <-time.After(3 * time.Second):
+		t.Fatal("job never ran")
+	}
+}
+
+func TestScheduler_ReplaceWhileRunning(t *testing.T) {
+	t.Parallel()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	s := NewScheduler(slog.Default())
+	_ = s.RegisterJob(&simpleJob{
+		name:     "report",
+		schedule: "0 9 * * 1",
+		runFunc: func(_ context.Context) error {
+			close(started)
+			<-release
+			return nil
+		},
+	})
+	if err := s.Start(); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	defer func() { _ = s.Stop(context.Background()) }()
+
+	s.RunNow("report")
+	<-started
+
+	// Replace the definition while the old one runs.
+	s.RemoveJob("report")
+	replaced := &simpleJob{name: "report", schedule: "0 9 * * 1"}
+	if err := s.RegisterJob(replaced); err != nil {
+		t.Fatalf("re-register failed: %v", err)
+	}
+	s.RunNow("report")
+
+	time.Sleep(100 * time.Millisecond)
+	replaced.mu.Lock()
+	calls := replaced.calls
+	replaced.mu.Unlock()
+	if calls != 0 {
+		t.Fatalf("replaced job ran %d times while the old run was in progress", calls)
+	}
+	if status, _ := s.Status("report"); status.Skips != 1 || !status.Running {
+		t.Errorf("status = %+v, want running with 1 skip", status)
+	}
+
+	close(release)
+	time.Sleep(50 * time.Millisecond)
+	s.RunNow("report")
+	time.Sleep(100 * time.Millisecond)
+	replaced.mu.Lock()
+	calls = replaced.calls
+	replaced.mu.Unlock()
+	if calls != 1 {
+		t.Errorf("replaced job calls = %d after the old run ended, want 1", calls)
+	}
+}
+
+func TestScheduler_StopWaitsForRunNow(t *testing.T) {
+	t.Parallel()
+
+	var done atomic.Bool
+	started := make(chan struct{})
+	s := NewScheduler(slog.Default())
+	_ = s.RegisterJob(&simpleJob{
+		name:     "slow",
+		schedule: "0 9 * * 1",
+		runFunc: func(_ context.Context) error {
+			close(started)
+			time.Sleep(200 * time.Millisecond)
+			done.Store(true)
+			return nil
+		},
+	})
+	if err := s.Start(); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+
+	s.RunNow("slow")
+	<-started
+	if err := s.Stop(context.Background()); err != nil {
+		t.Fatalf("stop failed: %v", err)
+	}
+	if !done.Load() {
+		t.Error("Stop returned before the manual run ended")
+	}
+}
+
+func TestScheduler_Status(t *testing.T) {
+	t.Parallel()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	s := NewScheduler(slog.Default())
+	_ = s.RegisterJob(&simpleJob{
+		name:     "weekly",
+		schedule: "0 9 * * 1",
+		runFunc: func(_ context.Context) error {
+			started <- struct{}{}
+			<-release
+			return errors.New("boom")
+		},
+	})
+
+	if _, ok := s.Status("missing"); ok {
+		t.Error("Status(missing) ok = true, want false")
+	}
+	status, _ := s.Status("weekly")
+	if status.NextRun != nil || status.Running {
+		t.Errorf("Status() before Start = %+v, want no next run and not running", status)
+	}
+
+	if err := s.Start(); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	defer func() { _ = s.Stop(context.Background()) }()
+
+	status, _ = s.Status("weekly")
+	if status.NextRun == nil || !status.NextRun.After(time.Now()) {
+		t.Errorf("NextRun = %v, want a future time", status.NextRun)
+	}
+	if status.PrevRun != nil {
+		t.Errorf("PrevRun = %v, want nil", status.PrevRun)
+	}
+
+	s.RunNow("weekly")
+	<-started
+	status, _ = s.Status("weekly")
+	if !status.Running || status.RunningSince == nil {
+		t.Errorf("Status() during run = %+v, want running", status)
+	}
+
+	// A tick while the job runs is skipped.
+	s.RunNow("weekly")
+	deadline := time.Now().Add(3 * time.Second)
+	for status.Skips == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+		status, _ = s.Status("weekly")
+	}
+	if status.Skips != 1 {
+		t.Errorf("Skips = %d, want 1", status.Skips)
+	}
+
+	close(release)
+	for status.Runs == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+		status, _ = s.Status("weekly")
+	}
+	if status.Running || status.Runs != 1 || status.Failures != 1 || status.Durations.Count != 1 {
+		t.Errorf("Status() after run = %+v, want 1 failed run, not running", status)
+	}
+
+	statuses := s.Statuses()
+	if len(statuses) != 1 || statuses[0].Name != "weekly" || statuses[0].Schedule != "0 9 * * 1" {
+		t.Errorf("Statuses() = %+v", statuses)
+	}
+}
+
+func TestJobState_DurationWindow(t *testing.T) {
+	t.Parallel()
+
+	var st jobState
+	now := time.Now()
+	for i := range durationWindow + 5 {
+		st.start(now)
+		now = now.Add(time.Duration(i+1) * time.Second)
+		st.finish(now, false)
+	}
+
+	var status JobStatus
+	st.fill(&status)
+	d := status.Durations
+	if status.Runs != durationWindow+5 || d.Count != durationWindow {
+		t.Fatalf("Runs = %d, Count = %d, want %d and %d", status.Runs, d.Count, durationWindow+5, durationWindow)
+	}
+	// The window covers runs 6 to 25, lasting 6s to 25s.
+	if d.MinMs != 6000 || d.MaxMs != 25000 || d.LastMs != 25000 || d.AvgMs != 15500 {
+		t.Errorf("Durations = %+v", d)
+	}
+}
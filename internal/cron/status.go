@@ -0,0 +1,110 @@
+package cron
+
+import (
+	"sync"
+	"time"
+)
+
+// durationWindow is how many of the latest run durations a job's duration
+// statistics cover.
+const durationWindow = 20
+
+// JobStatus is the live state of a scheduled job.
+type JobStatus struct {
+	Name     string     `json:"name"`
+	Schedule string     `json:"schedule"`
+	NextRun  *time.Time `json:"next_run,omitempty"` // nil when not scheduled or never firing again
+	PrevRun  *time.Time `json:"prev_run,omitempty"` // last scheduled fire time, nil before the first
+	Running  bool       `json:"running"`
+	// RunningSince is when the run in progress started.
+	RunningSince *time.Time    `json:"running_since,omitempty"`
+	Runs         int           `json:"runs"`
+	Failures     int           `json:"failures"`
+	Skips        int           `json:"skips"` // ticks skipped because the job was still running
+	Durations    DurationStats `json:"durations"`
+}
+
+// DurationStats summarizes the durations of a job's latest runs.
+type DurationStats struct {
+	Count  int   `json:"count"` // runs covered, at most durationWindow
+	LastMs int64 `json:"last_ms"`
+	AvgMs  int64 `json:"avg_ms"`
+	MinMs  int64 `json:"min_ms"`
+	MaxMs  int64 `json:"max_ms"`
+}
+
+// jobState holds the lock preventing parallel runs of a job and the
+// statistics of its runs.
+type jobState struct {
+	lock sync.Mutex // held while the job runs
+
+	mu        sync.Mutex
+	running   bool
+	since     time.Time
+	runs      int
+	failures  int
+	skips     int
+	durations []time.Duration // latest runs, oldest first
+}
+
+// start records the start of a run.
+func (st *jobState) start(now time.Time) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.running = true
+	st.since = now
+}
+
+// finish records the end of a run started with start.
+func (st *jobState) finish(now time.Time, failed bool) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.running = false
+	st.runs++
+	if failed {
+		st.failures++
+	}
+	st.durations = append(st.durations, now.Sub(st.since))
+	if len(st.durations) > durationWindow {
+		st.durations = st.durations[len(st.durations)-durationWindow:]
+	}
+}
+
+// skip records a tick skipped because the job was still running.
+func (st *jobState) skip() {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.skips++
+}
+
+// fill copies the run statistics into status.
+func (st *jobState) fill(status *JobStatus) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	status.Running = st.running
+	if st.running {
+		since := st.since
+		status.RunningSince = &since
+	}
+	status.Runs = st.runs
+	status.Failures = st.failures
+	status.Skips = st.skips
+
+	d := &status.Durations
+	d.Count = len(st.durations)
+	if d.Count == 0 {
+		return
+	}
+	var total time.Duration
+	lowest, highest := st.durations[0], st.durations[0]
+	for _, dur := range st.durations {
+		total += dur
+		lowest = min(lowest, dur)
+		highest = max(highest, dur)
+	}
+	d.LastMs = st.durations[d.Count-1].Milliseconds()
+	d.AvgMs = (total / time.Duration(d.Count)).Milliseconds()
+	d.MinMs = lowest.Milliseconds()
+	d.MaxMs = highest.Milliseconds()
+}
@@ -13,8 +13,9 @@ import (
 // Trigger provides manual execution and introspection of prompt crons.
 // It is registered as a service ("cron.trigger") so the gateway can discover it.
 type Trigger struct {
-	mu   sync.RWMutex
-	jobs map[string]*PromptJob // keyed by PromptCronDef.Name
+	mu        sync.RWMutex
+	jobs      map[string]*PromptJob // keyed by PromptCronDef.Name
+	scheduler *Scheduler            // nil = no live status
 }
 
 // NewTrigger creates a Trigger.
@@ -32,14 +33,37 @@ func (t *Trigger) Register(job *PromptJob) {
 	t.jobs[job.Def.Name] = job
 }
 
+// Unregister removes a prompt job from the trigger registry, unless another
+// job has since been registered under its cron name.
+func (t *Trigger) Unregister(job *PromptJob) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.jobs[job.Def.Name] == job {
+		delete(t.jobs, job.Def.Name)
+	}
+}
+
+// SetScheduler sets the scheduler running the registered jobs, from which
+// List and Get report their live status.
+func (t *Trigger) SetScheduler(s *Scheduler) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.scheduler = s
+}
+
 // Info is a summary returned by List and Get.
 type Info struct {
 	Name        string            `json:"name"`
 	Description string            `json:"description,omitempty"`
-	Schedule    string            `json:"schedule"`
+	Schedule    string            `json:"schedule,omitempty"`
+	At          string            `json:"at,omitempty"`
+	Timezone    string            `json:"timezone,omitempty"`
 	Enabled     bool              `json:"enabled"`
 	AgentID     string            `json:"agent_id"`
 	LastResult  *PromptCronResult `json:"last_result,omitempty"`
+	// Status is the live state of the job; nil when it is not scheduled,
+	// e.g. disabled or already fired.
+	Status *JobStatus `json:"status,omitempty"`
 }
 
 // List returns all registered prompt crons sorted by name.
@@ -49,17 +73,7 @@ func (t *Trigger) List() []Info {
 
 	infos := make([]Info, 0, len(t.jobs))
 	for _, job := range t.jobs {
-		info := Info{
-			Name:        job.Def.Name,
-			Description: job.Def.Description,
-			Schedule:    job.Def.Schedule,
-			Enabled:     job.Def.Enabled,
-			AgentID:     job.AgentID,
-		}
-		if result, err := LoadResult(job.DataDir, job.Def.Name); err == nil {
-			info.LastResult = result
-		}
-		infos = append(infos, info)
+		infos = append(infos, t.info(job))
 	}
 
 	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
@@ -76,17 +90,43 @@ func (t *Trigger) Get(name string) (*Info, bool) {
 		return nil, false
 	}
 
-	info := &Info{
+	info := t.info(job)
+	return &info, true
+}
+
+// info builds the summary of a registered job. The caller holds mu.
+func (t *Trigger) info(job *PromptJob) Info {
+	info := Info{
 		Name:        job.Def.Name,
 		Description: job.Def.Description,
 		Schedule:    job.Def.Schedule,
+		At:          job.Def.At,
+		Timezone:    job.Def.Timezone,
 		Enabled:     job.Def.Enabled,
 		AgentID:     job.AgentID,
 	}
 	if result, err := LoadResult(job.DataDir, job.Def.Name); err == nil {
 		info.LastResult = result
 	}
-	return info, true
+	if t.scheduler != nil {
+		if status, ok := t.scheduler.Status(job.Name()); ok {
+			info.Status = &status
+		}
+	}
+	return info
+}
+
+// History returns a page of a prompt cron's runs, newest first, and the
+// total number of runs. See LoadHistory for offset and limit.
+func (t *Trigger) History(name string, offset, limit int) ([]PromptCronResult, int, error) {
+	t.mu.RLock()
+	job, ok := t.jobs[name]
+	t.mu.RUnlock()
+
+	if !ok {
+		return nil, 0, fmt.Errorf("prompt cron %q not found", name)
+	}
+	return LoadHistory(job.DataDir, name, offset, limit)
 }
 
 // Trigger runs a prompt cron by name. It blocks until the job completes.
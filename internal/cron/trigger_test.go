@@ -160,3 +160,37 @@ func TestLoadResult_Valid(t *testing.T) {
 		t.Errorf("DurationMs = %d, want 500", loaded.DurationMs)
 	}
 }
+
+func TestCronTrigger_Status(t *testing.T) {
+	t.Parallel()
+
+	job := &PromptJob{
+		Def:     PromptCronDef{Name: "daily", Schedule: "0 8 * * *", Enabled: true},
+		AgentID: "main",
+		DataDir: t.TempDir(),
+	}
+	ct := NewTrigger()
+	ct.Register(job)
+
+	if info, _ := ct.Get("daily"); info.Status != nil {
+		t.Errorf("Status without scheduler = %+v, want nil", info.Status)
+	}
+
+	s := NewScheduler(nil)
+	if err := s.RegisterJob(job); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = s.Stop(context.Background()) }()
+	ct.SetScheduler(s)
+
+	info, _ := ct.Get("daily")
+	if info.Status == nil || info.Status.NextRun == nil {
+		t.Fatalf("Status = %+v, want a next run", info.Status)
+	}
+	if infos := ct.List(); infos[0].Status == nil {
+		t.Error("List() status = nil, want set")
+	}
+}
@@ -166,12 +166,7 @@ func (g *Gateway) handleGetConfig() http.HandlerFunc {
 			return
 		}
 
-		// Delegate to centralized Redactor if available, fall back to local redaction.
-		if g.redactor != nil {
-			g.redactor.RedactMap(generic)
-		} else {
-			security.NewRedactor().RedactMap(generic)
-		}
+		g.secretRedactor().RedactMap(generic)
 
 		w.Header().Set("Content-Type", "application/json")
 		_ = json.NewEncoder(w).Encode(generic)
@@ -0,0 +1,352 @@
+package gateway
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/flemzord/sclaw/internal/config"
+	"github.com/flemzord/sclaw/internal/security"
+	"gopkg.in/yaml.v3"
+)
+
+// maxConfigFileSize caps the size of a config file sent to the gateway.
+const maxConfigFileSize = 1 << 20
+
+// secretRefPattern matches values that only point to a secret held
+// elsewhere: ${VAR} with no default or an empty one, or a 1Password
+// reference. They are shown as is in the config editor.
+var secretRefPattern = regexp.MustCompile(`^(\$\{[A-Za-z_][A-Za-z0-9_]*(:-)?\}|op://[^\s"'\x60,\]\}]+)$`)
+
+// configFileJSON is the raw config file with the hash used to detect
+// concurrent edits.
+type configFileJSON struct {
+	YAML     string `json:"yaml"`
+	BaseHash string `json:"base_hash"`
+}
+
+// configValidation is the JSON response for POST /api/config/validate.
+type configValidation struct {
+	Valid bool   `json:"valid"`
+	Error string `json:"error,omitempty"`
+}
+
+// handleGetConfigFile returns the config file before variable expansion,
+// with inline secrets redacted, and the hash of the file on disk.
+func (g *Gateway) handleGetConfigFile() http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		if g.configPath == "" {
+			http.Error(w, "config path not set", http.StatusServiceUnavailable)
+			return
+		}
+		raw, err := os.ReadFile(g.configPath)
+		if err != nil {
+			http.Error(w, "failed to read config", http.StatusInternalServerError)
+			return
+		}
+		redacted, err := redactConfigFile(raw, g.secretRedactor())
+		if err != nil {
+			// Never fall back to the raw file: it may hold secrets.
+			http.Error(w, "failed to parse config", http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, http.StatusOK, configFileJSON{YAML: string(redacted), BaseHash: configHash(raw)})
+	}
+}
+
+// handleValidateConfig checks a YAML config sent as the request body
+// without writing it.
+func (g *Gateway) handleValidateConfig() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		raw, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxConfigFileSize))
+		if err != nil {
+			http.Error(w, "failed to read body", http.StatusBadRequest)
+			return
+		}
+		if current, readErr := os.ReadFile(g.configPath); g.configPath != "" && readErr == nil {
+			if raw, err = restoreConfigSecrets(raw, current, g.secretRedactor()); err != nil {
+				writeJSON(w, http.StatusOK, configValidation{Error: err.Error()})
+				return
+			}
+		}
+		if _, err := validateConfig(raw); err != nil {
+			writeJSON(w, http.StatusOK, configValidation{Error: err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, configValidation{Valid: true})
+	}
+}
+
+// handlePutConfig validates a new config file, writes it and reloads.
+// Redacted values left untouched in the editor are restored from the file
+// on disk. It answers 409 when the file changed since base_hash was read,
+// and 422 when the new config is invalid, leaving the file untouched.
+func (g *Gateway) handlePutConfig() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if g.configPath == "" {
+			http.Error(w, "config path not set", http.StatusServiceUnavailable)
+			return
+		}
+		var req configFileJSON
+		// JSON escaping can double the size of the YAML text.
+		if !decodeJSONBody(w, r, &req, 2*maxConfigFileSize) {
+			return
+		}
+		if len(req.YAML) > maxConfigFileSize {
+			http.Error(w, "config too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		if req.BaseHash == "" {
+			http.Error(w, "missing base_hash", http.StatusBadRequest)
+			return
+		}
+
+		current, err := os.ReadFile(g.configPath)
+		if err != nil {
+			http.Error(w, "failed to read config", http.StatusInternalServerError)
+			return
+		}
+		if configHash(current) != req.BaseHash {
+			writeJSON(w, http.StatusConflict, map[string]string{"error": "config was modified since it was read"})
+			return
+		}
+
+		raw, err := restoreConfigSecrets([]byte(req.YAML), current, g.secretRedactor())
+		if err != nil {
+			writeJSON(w, http.StatusUnprocessableEntity, configValidation{Error: err.Error()})
+			return
+		}
+		cfg, err := validateConfig(raw)
+		if err != nil {
+			writeJSON(w, http.StatusUnprocessableEntity, configValidation{Error: err.Error()})
+			return
+		}
+		if err := writeConfigFile(g.configPath, raw); err != nil {
+			g.logger.Error("config write failed", "error", err)
+			http.Error(w, "failed to write config", http.StatusInternalServerError)
+			return
+		}
+		if g.auditLogger != nil {
+			g.auditLogger.Log(security.AuditEvent{
+				Type:   security.EventConfigChange,
+				Detail: "configuration edited via admin API",
+			})
+		}
+
+		resp := map[string]string{"status": "written", "base_hash": configHash(raw)}
+		if g.reloadHandler == nil {
+			resp["warning"] = "reload handler not available"
+			writeJSON(w, http.StatusOK, resp)
+			return
+		}
+		if err := g.reloadHandler.HandleReloadFromConfig(r.Context(), cfg); err != nil {
+			g.logger.Error("config reload failed", "error", err)
+			resp["warning"] = "written but reload failed: " + err.Error()
+			writeJSON(w, http.StatusOK, resp)
+			return
+		}
+		g.logger.Info("configuration edited and reloaded")
+		resp["status"] = "reloaded"
+		writeJSON(w, http.StatusOK, resp)
+	}
+}
+
+// validateConfig parses and validates raw YAML the way it is loaded at
+// startup.
+func validateConfig(raw []byte) (*config.Config, error) {
+	if len(raw) == 0 {
+		return nil, errors.New("config is empty")
+	}
+	cfg, err := config.LoadFromBytes(raw)
+	if err != nil {
+		return nil, err
+	}
+	if err := config.Validate(cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// secretRedactor returns the shared redactor, or one with the default
+// patterns when the security module is not loaded.
+func (g *Gateway) secretRedactor() *security.Redactor {
+	if g.redactor != nil {
+		return g.redactor
+	}
+	return security.NewRedactor()
+}
+
+// redactConfigFile replaces inline secrets in a YAML config with
+// security.RedactPlaceholder: string values under secret-looking keys, and
+// anything the redactor recognizes elsewhere. References to secrets held
+// elsewhere are kept. The file is returned unchanged when it holds no
+// secret, otherwise it is re-encoded, keeping comments.
+func redactConfigFile(raw []byte, r *security.Redactor) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+	if !redactNode(&doc, false, r) {
+		return raw, nil
+	}
+	return encodeConfigFile(&doc)
+}
+
+// redactNode redacts the scalars under n and reports whether any changed.
+func redactNode(n *yaml.Node, secret bool, r *security.Redactor) bool {
+	changed := false
+	switch n.Kind {
+	case yaml.ScalarNode:
+		if redacted := redactScalar(n, secret, r); redacted != n.Value {
+			n.Value, n.Tag, n.Style = redacted, "!!str", 0
+			changed = true
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			if redactNode(n.Content[i+1], security.IsSecretKey(n.Content[i].Value), r) {
+				changed = true
+			}
+		}
+	case yaml.DocumentNode, yaml.SequenceNode:
+		for _, c := range n.Content {
+			if redactNode(c, secret, r) {
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+// redactScalar returns the redacted form of a scalar's value.
+func redactScalar(n *yaml.Node, secret bool, r *security.Redactor) string {
+	if n.ShortTag() != "!!str" || n.Value == "" || secretRefPattern.MatchString(n.Value) {
+		return n.Value
+	}
+	if secret {
+		return security.RedactPlaceholder
+	}
+	return r.Redact(n.Value)
+}
+
+// restoreConfigSecrets puts back the secrets redacted by redactConfigFile.
+// A value still holding the placeholder is replaced by the value at the
+// same place in current, provided it redacts to the same text; otherwise
+// the secret cannot be recovered and an error names where it is.
+func restoreConfigSecrets(edited, current []byte, r *security.Redactor) ([]byte, error) {
+	if !bytes.Contains(edited, []byte(security.RedactPlaceholder)) {
+		return edited, nil
+	}
+	var doc, orig yaml.Node
+	if err := yaml.Unmarshal(edited, &doc); err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(current, &orig); err != nil {
+		return nil, fmt.Errorf("parsing current config: %w", err)
+	}
+	if err := restoreNode(&doc, &orig, "", false, r); err != nil {
+		return nil, err
+	}
+	return encodeConfigFile(&doc)
+}
+
+// restoreNode walks edited alongside orig, the node at the same path in
+// the current file, which may be nil.
+func restoreNode(edited, orig *yaml.Node, path string, secret bool, r *security.Redactor) error {
+	switch edited.Kind {
+	case yaml.ScalarNode:
+		if !strings.Contains(edited.Value, security.RedactPlaceholder) {
+			return nil
+		}
+		if orig == nil || orig.Kind != yaml.ScalarNode || redactScalar(orig, secret, r) != edited.Value {
+			return fmt.Errorf("redacted value at %s does not match the current config; enter the secret again", strings.TrimPrefix(path, "."))
+		}
+		edited.Value, edited.Tag, edited.Style = orig.Value, orig.Tag, orig.Style
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(edited.Content); i += 2 {
+			key := edited.Content[i].Value
+			if err := restoreNode(edited.Content[i+1], mappingValue(orig, key), path+"."+key, security.IsSecretKey(key), r); err != nil {
+				return err
+			}
+		}
+	case yaml.SequenceNode:
+		for i, c := range edited.Content {
+			var o *yaml.Node
+			if orig != nil && orig.Kind == yaml.SequenceNode && i < len(orig.Content) {
+				o = orig.Content[i]
+			}
+			if err := restoreNode(c, o, path+"["+strconv.Itoa(i)+"]", secret, r); err != nil {
+				return err
+			}
+		}
+	case yaml.DocumentNode:
+		for i, c := range edited.Content {
+			var o *yaml.Node
+			if orig != nil && orig.Kind == yaml.DocumentNode && i < len(orig.Content) {
+				o = orig.Content[i]
+			}
+			if err := restoreNode(c, o, path, secret, r); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// mappingValue returns the value for key in a mapping node, or nil.
+func mappingValue(n *yaml.Node, key string) *yaml.Node {
+	if n == nil || n.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return n.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// encodeConfigFile encodes a YAML document with the indentation used in
+// the documentation examples.
+func encodeConfigFile(doc *yaml.Node) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// configHash returns the SHA-256 hex digest of a config file.
+func configHash(raw []byte) string {
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:])
+}
+
+// writeConfigFile replaces the config file atomically, keeping its mode.
+func writeConfigFile(path string, data []byte) error {
+	mode := os.FileMode(0o600)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, mode); err != nil {
+		return fmt.Errorf("writing temp file: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("renaming temp file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,312 @@
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/flemzord/sclaw/internal/config"
+)
+
+const testConfigYAML = "version: \"1\"\nmodules:\n  gateway.http:\n    bind: \"127.0.0.1:0\"\n"
+
+type fakeReloader struct {
+	got *config.Config
+	err error
+}
+
+func (f *fakeReloader) HandleReloadFromConfig(_ context.Context, cfg *config.Config) error {
+	f.got = cfg
+	return f.err
+}
+
+func newConfigFileGateway(t *testing.T, content string) (*Gateway, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "sclaw.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o640); err != nil {
+		t.Fatal(err)
+	}
+	return &Gateway{configPath: path, logger: slog.Default()}, path
+}
+
+func putConfig(t *testing.T, g *Gateway, req configFileJSON) *httptest.ResponseRecorder {
+	t.Helper()
+	body, err := json.Marshal(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := httptest.NewRequestWithContext(context.Background(), http.MethodPut, "/api/config", bytes.NewReader(body))
+	rr := httptest.NewRecorder()
+	g.handlePutConfig().ServeHTTP(rr, r)
+	return rr
+}
+
+func TestConfigFile_Get(t *testing.T) {
+	t.Parallel()
+
+	g, _ := newConfigFileGateway(t, testConfigYAML)
+	r := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/api/config/raw", nil)
+	rr := httptest.NewRecorder()
+	g.handleGetConfigFile().ServeHTTP(rr, r)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rr.Code, http.StatusOK)
+	}
+	var got configFileJSON
+	if err := json.NewDecoder(rr.Body).Decode(&got); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if got.YAML != testConfigYAML {
+		t.Errorf("yaml = %q, want %q", got.YAML, testConfigYAML)
+	}
+	if got.BaseHash != configHash([]byte(testConfigYAML)) {
+		t.Errorf("base_hash = %q", got.BaseHash)
+	}
+}
+
+const secretConfigYAML = `version: "1"
+modules:
+  gateway.http:
+    bind: "127.0.0.1:0"
+    # inline secrets
+    auth:
+      bearer_token: "plain-literal-token"
+      api_key: ${SCLAW_API_KEY:-}
+      password: ${SCLAW_PASSWORD:-hunter2-default}
+    webhooks:
+      - url: "https://example.com/hook?key=sk-abcdefghijklmnopqrstuvwxyz"
+`
+
+func getConfigFile(t *testing.T, g *Gateway) configFileJSON {
+	t.Helper()
+	r := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/api/config/raw", nil)
+	rr := httptest.NewRecorder()
+	g.handleGetConfigFile().ServeHTTP(rr, r)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rr.Code, http.StatusOK)
+	}
+	var got configFileJSON
+	if err := json.NewDecoder(rr.Body).Decode(&got); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	return got
+}
+
+func TestConfigFile_Get_RedactsSecrets(t *testing.T) {
+	t.Parallel()
+
+	g, _ := newConfigFileGateway(t, secretConfigYAML)
+	got := getConfigFile(t, g)
+
+	for _, secret := range []string{"plain-literal-token", "sk-abcdefghijklmnopqrstuvwxyz", "hunter2-default"} {
+		if strings.Contains(got.YAML, secret) {
+			t.Errorf("yaml contains secret %q:\n%s", secret, got.YAML)
+		}
+	}
+	for _, kept := range []string{"${SCLAW_API_KEY:-}", "# inline secrets", "https://example.com/hook?key="} {
+		if !strings.Contains(got.YAML, kept) {
+			t.Errorf("yaml lost %q:\n%s", kept, got.YAML)
+		}
+	}
+	if got.BaseHash != configHash([]byte(secretConfigYAML)) {
+		t.Errorf("base_hash = %q, want the hash of the file on disk", got.BaseHash)
+	}
+}
+
+func TestConfigFile_Put_RestoresSecrets(t *testing.T) {
+	t.Parallel()
+
+	g, path := newConfigFileGateway(t, secretConfigYAML)
+	got := getConfigFile(t, g)
+
+	edited := strings.Replace(got.YAML, "127.0.0.1:0", "127.0.0.1:9000", 1)
+	rr := putConfig(t, g, configFileJSON{YAML: edited, BaseHash: got.BaseHash})
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"127.0.0.1:9000", "plain-literal-token", "sk-abcdefghijklmnopqrstuvwxyz", "${SCLAW_API_KEY:-}", "hunter2-default"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("file lost %q:\n%s", want, data)
+		}
+	}
+	if strings.Contains(string(data), "REDACTED") {
+		t.Errorf("file contains a placeholder:\n%s", data)
+	}
+}
+
+func TestConfigFile_Put_UnknownRedactedValue(t *testing.T) {
+	t.Parallel()
+
+	g, path := newConfigFileGateway(t, secretConfigYAML)
+	got := getConfigFile(t, g)
+
+	// A placeholder moved to a key that had no secret cannot be restored.
+	edited := strings.Replace(got.YAML, "bearer_token:", "other_token:", 1)
+	rr := putConfig(t, g, configFileJSON{YAML: edited, BaseHash: got.BaseHash})
+	if rr.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("status = %d, want %d: %s", rr.Code, http.StatusUnprocessableEntity, rr.Body.String())
+	}
+	if !strings.Contains(rr.Body.String(), "other_token") {
+		t.Errorf("error should name the key: %s", rr.Body.String())
+	}
+	if data, _ := os.ReadFile(path); string(data) != secretConfigYAML {
+		t.Error("config file was modified")
+	}
+}
+
+func TestConfigFile_Get_NoPath(t *testing.T) {
+	t.Parallel()
+
+	g := &Gateway{}
+	r := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/api/config/raw", nil)
+	rr := httptest.NewRecorder()
+	g.handleGetConfigFile().ServeHTTP(rr, r)
+
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want %d", rr.Code, http.StatusServiceUnavailable)
+	}
+}
+
+func TestConfigFile_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		body  string
+		valid bool
+	}{
+		{"valid", testConfigYAML, true},
+		{"empty", "", false},
+		{"bad version", "version: \"2\"\nmodules:\n  gateway.http: {}\n", false},
+		{"unknown module", "version: \"1\"\nmodules:\n  nope.module: {}\n", false},
+		{"not yaml", "version: [", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			g := &Gateway{}
+			r := httptest.NewRequestWithContext(context.Background(), http.MethodPost, "/api/config/validate", strings.NewReader(tt.body))
+			rr := httptest.NewRecorder()
+			g.handleValidateConfig().ServeHTTP(rr, r)
+
+			if rr.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d", rr.Code, http.StatusOK)
+			}
+			var got configValidation
+			if err := json.NewDecoder(rr.Body).Decode(&got); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if got.Valid != tt.valid {
+				t.Errorf("valid = %v, want %v (error %q)", got.Valid, tt.valid, got.Error)
+			}
+			if !got.Valid && got.Error == "" {
+				t.Error("expected an error message")
+			}
+		})
+	}
+}
+
+func TestConfigFile_Put(t *testing.T) {
+	t.Parallel()
+
+	g, path := newConfigFileGateway(t, testConfigYAML)
+	reloader := &fakeReloader{}
+	g.reloadHandler = reloader
+
+	updated := testConfigYAML + "    # edited\n"
+	rr := putConfig(t, g, configFileJSON{YAML: updated, BaseHash: configHash([]byte(testConfigYAML))})
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+
+	var resp map[string]string
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp["status"] != "reloaded" {
+		t.Errorf("status = %q, want reloaded", resp["status"])
+	}
+	if resp["base_hash"] != configHash([]byte(updated)) {
+		t.Errorf("base_hash = %q", resp["base_hash"])
+	}
+	if reloader.got == nil {
+		t.Error("reload handler was not called")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != updated {
+		t.Errorf("file = %q, want %q", data, updated)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0o640 {
+		t.Errorf("mode = %v, want 0640", info.Mode().Perm())
+	}
+}
+
+func TestConfigFile_Put_Conflict(t *testing.T) {
+	t.Parallel()
+
+	g, path := newConfigFileGateway(t, testConfigYAML)
+	rr := putConfig(t, g, configFileJSON{YAML: testConfigYAML, BaseHash: configHash([]byte("stale"))})
+	if rr.Code != http.StatusConflict {
+		t.Fatalf("status = %d, want %d", rr.Code, http.StatusConflict)
+	}
+	if data, _ := os.ReadFile(path); string(data) != testConfigYAML {
+		t.Error("config file was modified")
+	}
+}
+
+func TestConfigFile_Put_Invalid(t *testing.T) {
+	t.Parallel()
+
+	g, path := newConfigFileGateway(t, testConfigYAML)
+	reloader := &fakeReloader{}
+	g.reloadHandler = reloader
+
+	rr := putConfig(t, g, configFileJSON{YAML: "version: \"1\"\n", BaseHash: configHash([]byte(testConfigYAML))})
+	if rr.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("status = %d, want %d", rr.Code, http.StatusUnprocessableEntity)
+	}
+	var got configValidation
+	if err := json.NewDecoder(rr.Body).Decode(&got); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if got.Valid || got.Error == "" {
+		t.Errorf("validation = %+v, want an error", got)
+	}
+	if data, _ := os.ReadFile(path); string(data) != testConfigYAML {
+		t.Error("config file was modified")
+	}
+	if reloader.got != nil {
+		t.Error("reload handler should not be called")
+	}
+}
+
+func TestConfigFile_Put_MissingHash(t *testing.T) {
+	t.Parallel()
+
+	g, _ := newConfigFileGateway(t, testConfigYAML)
+	rr := putConfig(t, g, configFileJSON{YAML: testConfigYAML})
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", rr.Code, http.StatusBadRequest)
+	}
+}
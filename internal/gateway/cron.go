@@ -4,11 +4,20 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+	"strconv"
 
 	"github.com/flemzord/sclaw/internal/cron"
 	"github.com/go-chi/chi/v5"
 )
 
+// syncCrons reconciles prompt crons with their definition files, so that
+// files changed since the last poll are reflected immediately.
+func (g *Gateway) syncCrons() {
+	if g.cronReconciler != nil {
+		g.cronReconciler.Reconcile()
+	}
+}
+
 // handleListCrons returns all registered prompt crons as JSON.
 func (g *Gateway) handleListCrons() http.HandlerFunc {
 	return func(w http.ResponseWriter, _ *http.Request) {
@@ -17,6 +26,7 @@ func (g *Gateway) handleListCrons() http.HandlerFunc {
 			return
 		}
 
+		g.syncCrons()
 		infos := g.cronTrigger.List()
 		if infos == nil {
 			infos = []cron.Info{}
@@ -27,7 +37,20 @@ func (g *Gateway) handleListCrons() http.HandlerFunc {
 	}
 }
 
-// handleGetCron returns a single prompt cron definition with its last result.
+// defaultCronRunsLimit is the number of runs returned by GET
+// /api/crons/{name} when no limit is given.
+const defaultCronRunsLimit = 20
+
+// cronDetail is the JSON response for GET /api/crons/{name}.
+type cronDetail struct {
+	cron.Info
+	Runs      []cron.PromptCronResult `json:"runs"`
+	TotalRuns int                     `json:"total_runs"`
+}
+
+// handleGetCron returns a single prompt cron definition with its last result
+// and a page of its run history, newest first, selected by the limit and
+// offset query parameters.
 func (g *Gateway) handleGetCron() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		name := chi.URLParam(r, "name")
@@ -41,15 +64,49 @@ func (g *Gateway) handleGetCron() http.HandlerFunc {
 			return
 		}
 
+		limit, ok := queryInt(w, r, "limit", defaultCronRunsLimit, 1)
+		if !ok {
+			return
+		}
+		offset, ok := queryInt(w, r, "offset", 0, 0)
+		if !ok {
+			return
+		}
+
+		g.syncCrons()
 		info, ok := g.cronTrigger.Get(name)
 		if !ok {
 			http.Error(w, "cron not found", http.StatusNotFound)
 			return
 		}
 
+		runs, total, err := g.cronTrigger.History(name, offset, limit)
+		if err != nil {
+			http.Error(w, "failed to read cron history", http.StatusInternalServerError)
+			return
+		}
+		if runs == nil {
+			runs = []cron.PromptCronResult{}
+		}
+
 		w.Header().Set("Content-Type", "application/json")
-		_ = json.NewEncoder(w).Encode(info)
+		_ = json.NewEncoder(w).Encode(cronDetail{Info: *info, Runs: runs, TotalRuns: total})
+	}
+}
+
+// queryInt parses an integer query parameter of at least lowest, answering
+// 400 when it is malformed.
+func queryInt(w http.ResponseWriter, r *http.Request, key string, def, lowest int) (int, bool) {
+	raw := r.URL.Query().Get(key)
+	if raw == "" {
+		return def, true
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < lowest {
+		http.Error(w, "invalid "+key, http.StatusBadRequest)
+		return 0, false
 	}
+	return n, true
 }
 
 // triggerResponse is the JSON response for POST /api/crons/{name}/trigger.
@@ -73,6 +130,7 @@ func (g *Gateway) handleTriggerCron() http.HandlerFunc {
 			return
 		}
 
+		g.syncCrons()
 		info, ok := g.cronTrigger.Get(name)
 		if !ok {
 			http.Error(w, "cron not found", http.StatusNotFound)
@@ -99,3 +157,18 @@ func (g *Gateway) handleTriggerCron() http.HandlerFunc {
 		})
 	}
 }
+
+// handleListSchedulerJobs returns the live state of every scheduled job,
+// built-in jobs included: next and previous fire times, whether it is
+// running, run, failure and skip counts, and duration statistics.
+func (g *Gateway) handleListSchedulerJobs() http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		if g.cronScheduler == nil {
+			writeJSON(w, http.StatusOK, []cron.JobStatus{})
+			return
+		}
+
+		g.syncCrons()
+		writeJSON(w, http.StatusOK, g.cronScheduler.Statuses())
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/flemzord/sclaw/internal/cron"
@@ -33,6 +34,34 @@ func TestCron_ListCrons_NilTrigger(t *testing.T) {
 	}
 }
 
+func TestCron_ListCrons_ReconcilesFiles(t *testing.T) {
+	t.Parallel()
+
+	dataDir := t.TempDir()
+	ct := cron.NewTrigger()
+	cr := cron.NewReconciler(cron.ReconcilerConfig{Scheduler: cron.NewScheduler(slog.Default()), Trigger: ct})
+	cr.SetAgents([]cron.PromptCronAgent{{ID: "main", DataDir: dataDir}})
+
+	// Written after the last reconcile, e.g. by hand.
+	def := cron.PromptCronDef{Name: "fresh", Schedule: "0 7 * * *", Enabled: true, Prompt: "hi"}
+	if err := cron.SavePromptCronDef(cron.CronsDir(dataDir), def); err != nil {
+		t.Fatal(err)
+	}
+
+	g := &Gateway{cronTrigger: ct, cronReconciler: cr}
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/api/crons", nil)
+	rr := httptest.NewRecorder()
+	g.handleListCrons().ServeHTTP(rr, req)
+
+	var infos []cron.Info
+	if err := json.NewDecoder(rr.Body).Decode(&infos); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(infos) != 1 || infos[0].Name != "fresh" {
+		t.Errorf("infos = %+v, want the fresh cron", infos)
+	}
+}
+
 func TestCron_ListCrons_WithData(t *testing.T) {
 	t.Parallel()
 
@@ -99,6 +128,54 @@ func TestCron_GetCron_Found(t *testing.T) {
 	}
 }
 
+func TestCron_GetCron_History(t *testing.T) {
+	t.Parallel()
+
+	dataDir := t.TempDir()
+	for _, ranAt := range []string{"2026-03-01T09:00:00Z", "2026-03-02T09:00:00Z", "2026-03-03T09:00:00Z"} {
+		if err := cron.AppendHistory(dataDir, cron.PromptCronResult{Name: "my-cron", RanAt: ranAt}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ct := cron.NewTrigger()
+	ct.Register(&cron.PromptJob{
+		Def:     cron.PromptCronDef{Name: "my-cron", Schedule: "30 8 * * *", Enabled: true},
+		AgentID: "agent1",
+		DataDir: dataDir,
+	})
+
+	g := &Gateway{cronTrigger: ct}
+	r := chi.NewRouter()
+	r.Get("/api/crons/{name}", g.handleGetCron())
+
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/api/crons/my-cron?limit=1&offset=1", nil)
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rr.Code, http.StatusOK)
+	}
+	var detail cronDetail
+	if err := json.NewDecoder(rr.Body).Decode(&detail); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if detail.Name != "my-cron" || detail.TotalRuns != 3 {
+		t.Errorf("detail = %+v", detail)
+	}
+	if len(detail.Runs) != 1 || detail.Runs[0].RanAt != "2026-03-02T09:00:00Z" {
+		t.Errorf("runs = %+v, want the second newest", detail.Runs)
+	}
+
+	for _, query := range []string{"limit=0", "limit=x", "offset=-1"} {
+		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/api/crons/my-cron?"+query, nil)
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want %d", query, rr.Code, http.StatusBadRequest)
+		}
+	}
+}
+
 func TestCron_GetCron_NotFound(t *testing.T) {
 	t.Parallel()
 
@@ -193,3 +270,46 @@ func TestCron_TriggerCron_NilTrigger(t *testing.T) {
 		t.Errorf("status = %d, want %d", rr.Code, http.StatusServiceUnavailable)
 	}
 }
+
+func TestCron_ListSchedulerJobs_NilScheduler(t *testing.T) {
+	t.Parallel()
+
+	g := &Gateway{}
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/api/scheduler", nil)
+	rr := httptest.NewRecorder()
+	g.handleListSchedulerJobs().ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK || strings.TrimSpace(rr.Body.String()) != "[]" {
+		t.Errorf("response = %d %q, want 200 []", rr.Code, rr.Body.String())
+	}
+}
+
+func TestCron_ListSchedulerJobs(t *testing.T) {
+	t.Parallel()
+
+	s := cron.NewScheduler(slog.Default())
+	if err := s.RegisterJob(&cron.PromptJob{
+		Def:     cron.PromptCronDef{Name: "daily", Schedule: "0 7 * * *", Enabled: true},
+		AgentID: "main",
+		DataDir: t.TempDir(),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = s.Stop(context.Background()) }()
+
+	g := &Gateway{cronScheduler: s}
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/api/scheduler", nil)
+	rr := httptest.NewRecorder()
+	g.handleListSchedulerJobs().ServeHTTP(rr, req)
+
+	var statuses []cron.JobStatus
+	if err := json.NewDecoder(rr.Body).Decode(&statuses); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(statuses) != 1 || statuses[0].Name != "prompt_cron:main:daily" || statuses[0].NextRun == nil {
+		t.Errorf("statuses = %+v, want the daily job with a next run", statuses)
+	}
+}
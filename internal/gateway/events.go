@@ -0,0 +1,107 @@
+package gateway
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/flemzord/sclaw/internal/activity"
+)
+
+// eventsBuffer is the number of events buffered per stream client before
+// events are dropped.
+const eventsBuffer = 256
+
+// eventsKeepAlive is the interval of the comments that keep idle streams
+// open through proxies.
+var eventsKeepAlive = 15 * time.Second
+
+// handleEvents streams live activity events as server-sent events. The
+// agent, session and type query parameters, each a comma-separated list,
+// filter the stream. Events lost because the client was too slow are
+// reported in a "dropped" event.
+func (g *Gateway) handleEvents() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if g.activity == nil {
+			http.Error(w, "activity events not available", http.StatusServiceUnavailable)
+			return
+		}
+
+		filter := activity.Filter{
+			Agents:   queryList(r, "agent"),
+			Sessions: queryList(r, "session"),
+		}
+		for _, t := range queryList(r, "type") {
+			if !slices.Contains(activity.Types, activity.Type(t)) {
+				http.Error(w, "unknown event type "+t, http.StatusBadRequest)
+				return
+			}
+			filter.Types = append(filter.Types, activity.Type(t))
+		}
+
+		// The stream outlives the server write timeout.
+		rc := http.NewResponseController(w)
+		if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+			g.logger.Debug("gateway: cannot clear write deadline for event stream", "error", err)
+		}
+
+		sub := g.activity.Subscribe(filter, eventsBuffer)
+		defer sub.Close()
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("X-Accel-Buffering", "no")
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprint(w, ": connected\n\n")
+		if rc.Flush() != nil {
+			return
+		}
+
+		keepAlive := time.NewTicker(eventsKeepAlive)
+		defer keepAlive.Stop()
+
+		var reported int64
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case <-g.streamsDone:
+				return
+			case <-keepAlive.C:
+				_, _ = fmt.Fprint(w, ": keep-alive\n\n")
+			case e := <-sub.Events():
+				if dropped := sub.Dropped(); dropped > reported {
+					writeEvent(w, "dropped", map[string]int64{"count": dropped - reported})
+					reported = dropped
+				}
+				writeEvent(w, string(e.Type), e)
+			}
+			if rc.Flush() != nil {
+				return
+			}
+		}
+	}
+}
+
+// writeEvent writes a server-sent event with a JSON payload.
+func writeEvent(w http.ResponseWriter, name string, v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	_, _ = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", name, data)
+}
+
+// queryList returns the comma-separated values of a query parameter.
+func queryList(r *http.Request, key string) []string {
+	var values []string
+	for _, v := range strings.Split(r.URL.Query().Get(key), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
@@ -0,0 +1,101 @@
+package gateway
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/flemzord/sclaw/internal/activity"
+)
+
+func TestEvents_NilBus(t *testing.T) {
+	t.Parallel()
+
+	g := &Gateway{}
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/api/events", nil)
+	rr := httptest.NewRecorder()
+	g.handleEvents().ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want %d", rr.Code, http.StatusServiceUnavailable)
+	}
+}
+
+func TestEvents_UnknownType(t *testing.T) {
+	t.Parallel()
+
+	g := &Gateway{activity: activity.NewBus()}
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/api/events?type=bogus", nil)
+	rr := httptest.NewRecorder()
+	g.handleEvents().ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", rr.Code, http.StatusBadRequest)
+	}
+}
+
+func TestEvents_StreamsFilteredEvents(t *testing.T) {
+	t.Parallel()
+
+	bus := activity.NewBus()
+	g := &Gateway{activity: bus, logger: slog.Default()}
+	srv := httptest.NewServer(g.handleEvents())
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"?agent=main&type=tool_call,compaction", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Content-Type = %q, want text/event-stream", ct)
+	}
+	for bus.Subscribers() == 0 {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	bus.Publish(context.Background(), activity.Event{Type: activity.TypeToolCall, AgentID: "other"})
+	bus.Publish(context.Background(), activity.Event{Type: activity.TypeMessageAccepted, AgentID: "main"})
+	bus.Publish(context.Background(), activity.Event{
+		Type:    activity.TypeToolCall,
+		AgentID: "main",
+		Data:    map[string]any{"tool": "exec"},
+	})
+
+	scanner := bufio.NewScanner(resp.Body)
+	var name, data string
+	for scanner.Scan() {
+		line := scanner.Text()
+		if v, ok := strings.CutPrefix(line, "event: "); ok {
+			name = v
+		}
+		if v, ok := strings.CutPrefix(line, "data: "); ok {
+			data = v
+			break
+		}
+	}
+
+	if name != "tool_call" {
+		t.Fatalf("first event = %q, want tool_call", name)
+	}
+	var e activity.Event
+	if err := json.Unmarshal([]byte(data), &e); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if e.AgentID != "main" || e.Data["tool"] != "exec" {
+		t.Errorf("event = %+v, want the main exec call", e)
+	}
+}
@@ -8,9 +8,11 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/flemzord/sclaw/internal/activity"
 	"github.com/flemzord/sclaw/internal/config"
 	"github.com/flemzord/sclaw/internal/core"
 	"github.com/flemzord/sclaw/internal/cron"
+	"github.com/flemzord/sclaw/internal/mcp"
 	"github.com/flemzord/sclaw/internal/provider"
 	"github.com/flemzord/sclaw/internal/router"
 	"github.com/flemzord/sclaw/internal/security"
@@ -33,14 +35,24 @@ type Gateway struct {
 	dispatcher *WebhookDispatcher
 	startedAt  time.Time
 
+	// streamsDone is closed on shutdown to end event streams, which would
+	// otherwise hold the server open.
+	streamsDone chan struct{}
+
 	// Resolved lazily at Start() via service registry.
-	sessions      router.SessionStore
-	chain         *provider.Chain
-	redactor      *security.Redactor
-	auditLogger   *security.AuditLogger
-	rateLimiter   *security.RateLimiter
-	cronTrigger   *cron.Trigger
-	reloadHandler interface {
+	sessions         router.SessionStore
+	sessionAdmin     sessionEditor
+	chain            *provider.Chain
+	redactor         *security.Redactor
+	auditLogger      *security.AuditLogger
+	rateLimiter      *security.RateLimiter
+	cronTrigger      *cron.Trigger
+	cronReconciler   *cron.Reconciler
+	cronScheduler    *cron.Scheduler
+	workspaceHistory router.WorkspaceHistoryResolver
+	mcpPublisher     *mcp.Publisher
+	activity         *activity.Bus
+	reloadHandler    interface {
 		HandleReloadFromConfig(context.Context, *config.Config) error
 	}
 }
@@ -106,6 +118,11 @@ func (g *Gateway) Start() error {
 			g.sessions = store
 		}
 	}
+	if svc, ok := g.appCtx.GetService("router.router"); ok {
+		if editor, ok := svc.(sessionEditor); ok {
+			g.sessionAdmin = editor
+		}
+	}
 	if svc, ok := g.appCtx.GetService("provider.chain"); ok {
 		if chain, ok := svc.(*provider.Chain); ok {
 			g.chain = chain
@@ -131,6 +148,31 @@ func (g *Gateway) Start() error {
 			g.cronTrigger = ct
 		}
 	}
+	if svc, ok := g.appCtx.GetService("cron.reconciler"); ok {
+		if cr, ok := svc.(*cron.Reconciler); ok {
+			g.cronReconciler = cr
+		}
+	}
+	if svc, ok := g.appCtx.GetService("cron.scheduler"); ok {
+		if cs, ok := svc.(*cron.Scheduler); ok {
+			g.cronScheduler = cs
+		}
+	}
+	if svc, ok := g.appCtx.GetService("workspace.history"); ok {
+		if wh, ok := svc.(router.WorkspaceHistoryResolver); ok {
+			g.workspaceHistory = wh
+		}
+	}
+	if svc, ok := g.appCtx.GetService("mcp.publisher"); ok {
+		if p, ok := svc.(*mcp.Publisher); ok {
+			g.mcpPublisher = p
+		}
+	}
+	if svc, ok := g.appCtx.GetService("activity.bus"); ok {
+		if b, ok := svc.(*activity.Bus); ok {
+			g.activity = b
+		}
+	}
 	if svc, ok := g.appCtx.GetService("reload.handler"); ok {
 		if rh, ok := svc.(interface {
 			HandleReloadFromConfig(context.Context, *config.Config) error
@@ -157,6 +199,8 @@ func (g *Gateway) Start() error {
 		IdleTimeout:    120 * time.Second,
 		MaxHeaderBytes: 1 << 20, // 1 MiB
 	}
+	g.streamsDone = make(chan struct{})
+	g.server.RegisterOnShutdown(func() { close(g.streamsDone) })
 
 	var lc net.ListenConfig
 	ln, err := lc.Listen(context.Background(), "tcp", g.config.Bind)
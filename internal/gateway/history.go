@@ -0,0 +1,111 @@
+package gateway
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/flemzord/sclaw/internal/security"
+	"github.com/flemzord/sclaw/internal/snapshot"
+	"github.com/go-chi/chi/v5"
+)
+
+// conflictResponse is the JSON body returned when a revert conflicts with
+// later changes.
+type conflictResponse struct {
+	Error string   `json:"error"`
+	Paths []string `json:"paths"`
+}
+
+// historyStore resolves the snapshot store of the agent named in the URL,
+// writing an error response and returning nil when it is unavailable.
+func (g *Gateway) historyStore(w http.ResponseWriter, r *http.Request) *snapshot.Store {
+	if g.workspaceHistory == nil {
+		http.Error(w, "workspace history not available", http.StatusServiceUnavailable)
+		return nil
+	}
+	store := g.workspaceHistory.ResolveWorkspaceHistory(chi.URLParam(r, "id"))
+	if store == nil {
+		http.Error(w, "workspace history not enabled for this agent", http.StatusNotFound)
+		return nil
+	}
+	return store
+}
+
+// handleListWorkspaceHistory returns the recorded turns of an agent's
+// workspace, newest first. The optional session query parameter filters by
+// conversation and limit caps the number of turns.
+func (g *Gateway) handleListWorkspaceHistory() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		store := g.historyStore(w, r)
+		if store == nil {
+			return
+		}
+
+		turns, err := store.List(r.URL.Query().Get("session"))
+		if err != nil {
+			http.Error(w, "failed to list workspace history", http.StatusInternalServerError)
+			return
+		}
+		if raw := r.URL.Query().Get("limit"); raw != "" {
+			limit, err := strconv.Atoi(raw)
+			if err != nil || limit < 1 {
+				http.Error(w, "invalid limit", http.StatusBadRequest)
+				return
+			}
+			turns = turns[:min(limit, len(turns))]
+		}
+		writeJSON(w, http.StatusOK, turns)
+	}
+}
+
+// handleRevertWorkspaceTurn restores the files changed by one turn.
+// It answers 409 when files changed since the turn, unless force=true.
+func (g *Gateway) handleRevertWorkspaceTurn() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.ParseInt(chi.URLParam(r, "turn"), 10, 64)
+		if err != nil || id < 1 {
+			http.Error(w, "invalid turn id", http.StatusBadRequest)
+			return
+		}
+		store := g.historyStore(w, r)
+		if store == nil {
+			return
+		}
+
+		force := r.URL.Query().Get("force") == "true"
+		turn, err := store.Revert(id, force)
+		var conflict *snapshot.ConflictError
+		switch {
+		case errors.Is(err, snapshot.ErrTurnNotFound):
+			http.Error(w, "turn not found", http.StatusNotFound)
+			return
+		case errors.Is(err, snapshot.ErrAlreadyReverted):
+			writeJSON(w, http.StatusConflict, conflictResponse{Error: "turn already reverted"})
+			return
+		case errors.As(err, &conflict):
+			writeJSON(w, http.StatusConflict, conflictResponse{
+				Error: "files changed since the turn; retry with force=true to discard those changes",
+				Paths: conflict.Paths,
+			})
+			return
+		case err != nil:
+			g.logger.Error("workspace revert failed", "agent", chi.URLParam(r, "id"), "turn", id, "error", err)
+			http.Error(w, "failed to revert turn", http.StatusInternalServerError)
+			return
+		}
+
+		if g.auditLogger != nil {
+			g.auditLogger.Log(security.AuditEvent{
+				Type:   security.EventFileChange,
+				Detail: "reverted via admin API: " + turn.Summary(),
+				Metadata: map[string]string{
+					"action": "revert",
+					"agent":  chi.URLParam(r, "id"),
+					"turn":   strconv.FormatInt(turn.ID, 10),
+				},
+			})
+		}
+		writeJSON(w, http.StatusOK, turn)
+	}
+}
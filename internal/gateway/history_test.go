@@ -0,0 +1,132 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/flemzord/sclaw/internal/snapshot"
+	"github.com/go-chi/chi/v5"
+)
+
+// stubWorkspaceHistory serves one store for agent "main".
+type stubWorkspaceHistory struct {
+	store *snapshot.Store
+}
+
+func (s stubWorkspaceHistory) ResolveWorkspaceHistory(agentID string) *snapshot.Store {
+	if agentID != "main" {
+		return nil
+	}
+	return s.store
+}
+
+func newHistoryGateway(t *testing.T) (*Gateway, http.Handler, string) {
+	t.Helper()
+	workspace := t.TempDir()
+	store, err := snapshot.New(snapshot.Config{Workspace: workspace, Dir: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(workspace, "f.txt")
+	if err := os.WriteFile(path, []byte("v1"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	store.NewRecorder("slack:C1:", "edit f").Track("exec", func() {
+		if err := os.WriteFile(path, []byte("v2"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	g := &Gateway{logger: slog.Default(), workspaceHistory: stubWorkspaceHistory{store: store}}
+	r := chi.NewRouter()
+	r.Get("/api/agents/{id}/history", g.handleListWorkspaceHistory())
+	r.Post("/api/agents/{id}/history/{turn}/revert", g.handleRevertWorkspaceTurn())
+	return g, r, path
+}
+
+func TestWorkspaceHistory_List(t *testing.T) {
+	t.Parallel()
+
+	_, h, _ := newHistoryGateway(t)
+
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/api/agents/main/history", nil)
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rr.Code, http.StatusOK)
+	}
+	var turns []snapshot.Turn
+	if err := json.NewDecoder(rr.Body).Decode(&turns); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(turns) != 1 || turns[0].Session != "slack:C1:" || len(turns[0].Changes) != 1 {
+		t.Errorf("turns = %+v", turns)
+	}
+
+	req = httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/api/agents/other/history", nil)
+	rr = httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("unknown agent status = %d, want %d", rr.Code, http.StatusNotFound)
+	}
+}
+
+func TestWorkspaceHistory_Revert(t *testing.T) {
+	t.Parallel()
+
+	_, h, path := newHistoryGateway(t)
+
+	// A later edit makes the plain revert conflict.
+	if err := os.WriteFile(path, []byte("v3"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodPost, "/api/agents/main/history/1/revert", nil)
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	if rr.Code != http.StatusConflict {
+		t.Fatalf("status = %d, want %d", rr.Code, http.StatusConflict)
+	}
+	var conflict conflictResponse
+	if err := json.NewDecoder(rr.Body).Decode(&conflict); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(conflict.Paths) != 1 || conflict.Paths[0] != "f.txt" {
+		t.Errorf("conflict paths = %v", conflict.Paths)
+	}
+
+	req = httptest.NewRequestWithContext(context.Background(), http.MethodPost, "/api/agents/main/history/1/revert?force=true", nil)
+	rr = httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("forced status = %d, want %d: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+	if data, _ := os.ReadFile(path); string(data) != "v1" {
+		t.Errorf("f.txt = %q, want v1", data)
+	}
+
+	req = httptest.NewRequestWithContext(context.Background(), http.MethodPost, "/api/agents/main/history/9/revert", nil)
+	rr = httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("missing turn status = %d, want %d", rr.Code, http.StatusNotFound)
+	}
+}
+
+func TestWorkspaceHistory_Unavailable(t *testing.T) {
+	t.Parallel()
+
+	g := &Gateway{}
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/api/agents/main/history", nil)
+	rr := httptest.NewRecorder()
+	g.handleListWorkspaceHistory().ServeHTTP(rr, req)
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want %d", rr.Code, http.StatusServiceUnavailable)
+	}
+}
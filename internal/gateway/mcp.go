@@ -0,0 +1,25 @@
+package gateway
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// handleMCP serves the Streamable HTTP MCP endpoint of the agent named in
+// the URL. The MCP server is stateless and rebuilt for each request, so it
+// always reflects the current agent configuration.
+func (g *Gateway) handleMCP() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if g.mcpPublisher == nil {
+			http.Error(w, "mcp server not available", http.StatusServiceUnavailable)
+			return
+		}
+		h, err := g.mcpPublisher.Handler(chi.URLParam(r, "agent"))
+		if err != nil {
+			http.Error(w, "agent not found", http.StatusNotFound)
+			return
+		}
+		h.ServeHTTP(w, r)
+	}
+}
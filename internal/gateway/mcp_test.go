@@ -0,0 +1,69 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"testing"
+
+	"github.com/flemzord/sclaw/internal/mcp"
+	"github.com/flemzord/sclaw/internal/memory"
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
+	gomcp "github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestGateway_MCP(t *testing.T) {
+	t.Parallel()
+
+	addr := freeAddr(t)
+	g := newTestGateway(t, addr, AuthConfig{BearerToken: "test-token"})
+	g.mcpPublisher = mcp.NewPublisher(func(agentID string) (*mcp.ServedAgent, error) {
+		if agentID != "main" {
+			return nil, errors.New("agent not found")
+		}
+		return &mcp.ServedAgent{ID: "main", History: memory.NewInMemoryHistoryStore()}, nil
+	}, slog.Default())
+
+	if err := g.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer func() { _ = g.Stop(context.Background()) }()
+
+	connect := func(agent, token string) error {
+		c, err := client.NewStreamableHttpClient("http://"+addr+"/mcp/"+agent,
+			transport.WithHTTPHeaders(map[string]string{"Authorization": "Bearer " + token}))
+		if err != nil {
+			return err
+		}
+		defer func() { _ = c.Close() }()
+		ctx := context.Background()
+		if err := c.Start(ctx); err != nil {
+			return err
+		}
+		if _, err := c.Initialize(ctx, gomcp.InitializeRequest{}); err != nil {
+			return err
+		}
+		req := gomcp.ReadResourceRequest{}
+		req.Params.URI = "sclaw://history"
+		_, err = c.ReadResource(ctx, req)
+		return err
+	}
+
+	if err := connect("main", "test-token"); err != nil {
+		t.Errorf("authenticated client: %v", err)
+	}
+	if err := connect("main", "wrong"); err == nil {
+		t.Error("expected unauthenticated client to fail")
+	}
+	if err := connect("other", "test-token"); err == nil {
+		t.Error("expected unknown agent to fail")
+	}
+
+	resp := doGetWithBearer(t, "http://"+addr+"/mcp/other", "test-token")
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("unknown agent status = %d, want %d", resp.StatusCode, http.StatusNotFound)
+	}
+}
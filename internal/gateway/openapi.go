@@ -3,6 +3,7 @@ package gateway
 import (
 	"net/http"
 
+	"github.com/flemzord/sclaw/internal/activity"
 	"gopkg.in/yaml.v3"
 )
 
@@ -45,6 +46,7 @@ func buildOpenAPISpec() map[string]any {
 			modulePaths(),
 			configPaths(),
 			cronPaths(),
+			eventPaths(),
 			openapiPaths(),
 		),
 		"components": map[string]any{
@@ -148,11 +150,232 @@ func sessionPaths() map[string]any {
 				},
 			},
 		},
+		"/api/sessions/{id}/history": map[string]any{
+			"get": map[string]any{
+				"summary":     "Get the full history and summary of a session",
+				"operationId": "getSessionHistory",
+				"tags":        []string{"sessions"},
+				"parameters":  []map[string]any{sessionIDParam},
+				"responses": map[string]any{
+					"200": map[string]any{
+						"description": "The session history",
+						"content": map[string]any{
+							"application/json": map[string]any{
+								"schema": map[string]any{"$ref": "#/components/schemas/SessionHistory"},
+							},
+						},
+					},
+					"404": map[string]any{"description": "Session not found"},
+					"503": map[string]any{"description": "Session administration not available"},
+				},
+			},
+			"post": map[string]any{
+				"summary":     "Inject a user or system message, seen by the agent on the next turn",
+				"operationId": "injectSessionMessage",
+				"tags":        []string{"sessions"},
+				"parameters":  []map[string]any{sessionIDParam},
+				"requestBody": jsonBody(map[string]any{
+					"type":     "object",
+					"required": []string{"role", "content"},
+					"properties": map[string]any{
+						"role":    map[string]any{"type": "string", "enum": []string{"user", "system"}},
+						"content": map[string]any{"type": "string"},
+					},
+				}),
+				"responses": map[string]any{
+					"204": map[string]any{"description": "Message injected"},
+					"400": map[string]any{"description": "Invalid role or missing content"},
+					"404": map[string]any{"description": "Session not found"},
+					"503": map[string]any{"description": "Session administration not available"},
+				},
+			},
+		},
+		"/api/sessions/{id}/history/{index}": map[string]any{
+			"patch": map[string]any{
+				"summary":     "Replace the text of a history message",
+				"description": "Only the text is replaced: the arguments of a message's tool calls cannot be edited. Remove the message to drop a tool call and its results.",
+				"operationId": "editSessionMessage",
+				"tags":        []string{"sessions"},
+				"parameters":  []map[string]any{sessionIDParam, messageIndexParam},
+				"requestBody": jsonBody(map[string]any{
+					"type":       "object",
+					"required":   []string{"content"},
+					"properties": map[string]any{"content": map[string]any{"type": "string"}},
+				}),
+				"responses": map[string]any{
+					"204": map[string]any{"description": "Message edited"},
+					"404": map[string]any{"description": "Session or message not found"},
+					"501": map[string]any{"description": "History store does not support editing"},
+					"503": map[string]any{"description": "Session administration not available"},
+				},
+			},
+			"delete": map[string]any{
+				"summary":     "Remove a history message",
+				"description": "Removing a tool call or one of its results removes the call and all its results.",
+				"operationId": "deleteSessionMessage",
+				"tags":        []string{"sessions"},
+				"parameters":  []map[string]any{sessionIDParam, messageIndexParam},
+				"responses": map[string]any{
+					"204": map[string]any{"description": "Message removed"},
+					"404": map[string]any{"description": "Session or message not found"},
+					"501": map[string]any{"description": "History store does not support editing"},
+					"503": map[string]any{"description": "Session administration not available"},
+				},
+			},
+		},
+		"/api/sessions/{id}/summary": map[string]any{
+			"get": map[string]any{
+				"summary":     "Get the stored summary of a session",
+				"operationId": "getSessionSummary",
+				"tags":        []string{"sessions"},
+				"parameters":  []map[string]any{sessionIDParam},
+				"responses": map[string]any{
+					"200": map[string]any{
+						"description": "The summary, empty if none",
+						"content": map[string]any{
+							"application/json": map[string]any{
+								"schema": map[string]any{"$ref": "#/components/schemas/SessionSummary"},
+							},
+						},
+					},
+					"404": map[string]any{"description": "Session not found"},
+					"503": map[string]any{"description": "Session administration not available"},
+				},
+			},
+			"put": map[string]any{
+				"summary":     "Replace the stored summary of a session",
+				"operationId": "setSessionSummary",
+				"tags":        []string{"sessions"},
+				"parameters":  []map[string]any{sessionIDParam},
+				"requestBody": jsonBody(map[string]any{"$ref": "#/components/schemas/SessionSummary"}),
+				"responses": map[string]any{
+					"204": map[string]any{"description": "Summary replaced"},
+					"404": map[string]any{"description": "Session not found"},
+					"409": map[string]any{"description": "Session has no persistent history"},
+					"503": map[string]any{"description": "Session administration not available"},
+				},
+			},
+		},
+		"/api/sessions/{id}/agent": map[string]any{
+			"put": map[string]any{
+				"summary":     "Reassign a session to another agent, moving its persistent history",
+				"operationId": "reassignSession",
+				"tags":        []string{"sessions"},
+				"parameters":  []map[string]any{sessionIDParam},
+				"requestBody": jsonBody(map[string]any{
+					"type":       "object",
+					"required":   []string{"agent_id"},
+					"properties": map[string]any{"agent_id": map[string]any{"type": "string"}},
+				}),
+				"responses": map[string]any{
+					"204": map[string]any{"description": "Session reassigned"},
+					"400": map[string]any{"description": "Missing or unknown agent"},
+					"404": map[string]any{"description": "Session not found"},
+					"503": map[string]any{"description": "Session administration not available"},
+				},
+			},
+		},
+	}
+}
+
+// sessionIDParam and messageIndexParam are the path parameters of the
+// session administration endpoints.
+var (
+	sessionIDParam = map[string]any{
+		"name": "id", "in": "path", "required": true, "schema": map[string]any{"type": "string"}, "description": "Session ID",
+	}
+	messageIndexParam = map[string]any{
+		"name": "index", "in": "path", "required": true, "schema": map[string]any{"type": "integer", "minimum": 0}, "description": "Message index in the history",
+	}
+)
+
+// jsonBody describes a required JSON request body with the given schema.
+func jsonBody(schema map[string]any) map[string]any {
+	return map[string]any{
+		"required": true,
+		"content": map[string]any{
+			"application/json": map[string]any{"schema": schema},
+		},
 	}
 }
 
 func agentPaths() map[string]any {
 	return map[string]any{
+		"/api/agents/{id}/history": map[string]any{
+			"get": map[string]any{
+				"summary":     "List the workspace changes recorded for an agent, newest turn first",
+				"operationId": "listWorkspaceHistory",
+				"tags":        []string{"agents"},
+				"parameters": []map[string]any{
+					{"name": "id", "in": "path", "required": true, "schema": map[string]any{"type": "string"}, "description": "Agent ID"},
+					{"name": "session", "in": "query", "schema": map[string]any{"type": "string"}, "description": "Only turns of this conversation (channel:chat:thread)"},
+					{"name": "limit", "in": "query", "schema": map[string]any{"type": "integer", "minimum": 1}, "description": "Maximum number of turns"},
+				},
+				"responses": map[string]any{
+					"200": map[string]any{
+						"description": "Array of turns",
+						"content": map[string]any{
+							"application/json": map[string]any{
+								"schema": map[string]any{
+									"type":  "array",
+									"items": map[string]any{"$ref": "#/components/schemas/WorkspaceTurn"},
+								},
+							},
+						},
+					},
+					"404": map[string]any{"description": "Workspace history not enabled for this agent"},
+					"503": map[string]any{"description": "Workspace history not available"},
+				},
+			},
+		},
+		"/api/agents/{id}/history/{turn}/revert": map[string]any{
+			"post": map[string]any{
+				"summary":     "Restore the files changed by a turn",
+				"operationId": "revertWorkspaceTurn",
+				"tags":        []string{"agents"},
+				"parameters": []map[string]any{
+					{"name": "id", "in": "path", "required": true, "schema": map[string]any{"type": "string"}, "description": "Agent ID"},
+					{"name": "turn", "in": "path", "required": true, "schema": map[string]any{"type": "integer"}, "description": "Turn ID"},
+					{"name": "force", "in": "query", "schema": map[string]any{"type": "boolean"}, "description": "Revert even if files changed since the turn"},
+				},
+				"responses": map[string]any{
+					"200": map[string]any{
+						"description": "The reverted turn",
+						"content": map[string]any{
+							"application/json": map[string]any{
+								"schema": map[string]any{"$ref": "#/components/schemas/WorkspaceTurn"},
+							},
+						},
+					},
+					"404": map[string]any{"description": "Turn not found, or workspace history not enabled"},
+					"409": map[string]any{"description": "Turn already reverted, or files changed since the turn"},
+					"503": map[string]any{"description": "Workspace history not available"},
+				},
+			},
+		},
+		"/mcp/{agent}": map[string]any{
+			"post": map[string]any{
+				"summary":     "Model Context Protocol endpoint (Streamable HTTP, stateless) publishing the agent's tools and memory",
+				"operationId": "mcp",
+				"tags":        []string{"agents"},
+				"parameters": []map[string]any{
+					{"name": "agent", "in": "path", "required": true, "schema": map[string]any{"type": "string"}, "description": "Agent ID"},
+				},
+				"requestBody": map[string]any{
+					"required": true,
+					"content": map[string]any{
+						"application/json": map[string]any{
+							"schema": map[string]any{"type": "object", "description": "JSON-RPC 2.0 request"},
+						},
+					},
+				},
+				"responses": map[string]any{
+					"200": map[string]any{"description": "JSON-RPC 2.0 response"},
+					"404": map[string]any{"description": "Agent not found"},
+					"503": map[string]any{"description": "MCP server not available"},
+				},
+			},
+		},
 		"/api/agents": map[string]any{
 			"get": map[string]any{
 				"summary":     "List registered agents",
@@ -219,6 +442,79 @@ func configPaths() map[string]any {
 					},
 				},
 			},
+			"put": map[string]any{
+				"summary":     "Validate, write and reload the configuration file",
+				"operationId": "putConfig",
+				"tags":        []string{"config"},
+				"requestBody": jsonBody(map[string]any{"$ref": "#/components/schemas/ConfigFile"}),
+				"responses": map[string]any{
+					"200": map[string]any{
+						"description": "Configuration written, and reloaded unless a warning is set",
+						"content": map[string]any{
+							"application/json": map[string]any{
+								"schema": map[string]any{
+									"type": "object",
+									"properties": map[string]any{
+										"status":    map[string]any{"type": "string", "enum": []string{"written", "reloaded"}},
+										"base_hash": map[string]any{"type": "string"},
+										"warning":   map[string]any{"type": "string"},
+									},
+								},
+							},
+						},
+					},
+					"409": map[string]any{"description": "Configuration file changed since base_hash was read"},
+					"413": map[string]any{"description": "Configuration too large"},
+					"422": map[string]any{
+						"description": "Invalid configuration, or a redacted value that cannot be restored; the file is left untouched",
+						"content": map[string]any{
+							"application/json": map[string]any{
+								"schema": map[string]any{"$ref": "#/components/schemas/ConfigValidation"},
+							},
+						},
+					},
+				},
+			},
+		},
+		"/api/config/raw": map[string]any{
+			"get": map[string]any{
+				"summary":     "Get the configuration file with inline secrets redacted",
+				"operationId": "getConfigFile",
+				"tags":        []string{"config"},
+				"responses": map[string]any{
+					"200": map[string]any{
+						"description": "Unexpanded, redacted configuration file and the hash of the file on disk",
+						"content": map[string]any{
+							"application/json": map[string]any{
+								"schema": map[string]any{"$ref": "#/components/schemas/ConfigFile"},
+							},
+						},
+					},
+				},
+			},
+		},
+		"/api/config/validate": map[string]any{
+			"post": map[string]any{
+				"summary":     "Validate a configuration without applying it",
+				"operationId": "validateConfig",
+				"tags":        []string{"config"},
+				"requestBody": map[string]any{
+					"required": true,
+					"content": map[string]any{
+						"application/x-yaml": map[string]any{"schema": map[string]any{"type": "string"}},
+					},
+				},
+				"responses": map[string]any{
+					"200": map[string]any{
+						"description": "Validation result",
+						"content": map[string]any{
+							"application/json": map[string]any{
+								"schema": map[string]any{"$ref": "#/components/schemas/ConfigValidation"},
+							},
+						},
+					},
+				},
+			},
 		},
 		"/api/config/reload": map[string]any{
 			"post": map[string]any{
@@ -270,21 +566,24 @@ func cronPaths() map[string]any {
 		},
 		"/api/crons/{name}": map[string]any{
 			"get": map[string]any{
-				"summary":     "Get a prompt cron definition and its last result",
+				"summary":     "Get a prompt cron definition, its last result and run history",
 				"operationId": "getCron",
 				"tags":        []string{"crons"},
 				"parameters": []map[string]any{
 					{"name": "name", "in": "path", "required": true, "schema": map[string]any{"type": "string"}, "description": "Cron definition name"},
+					{"name": "limit", "in": "query", "schema": map[string]any{"type": "integer", "minimum": 1, "default": 20}, "description": "Maximum number of runs"},
+					{"name": "offset", "in": "query", "schema": map[string]any{"type": "integer", "minimum": 0, "default": 0}, "description": "Number of newest runs to skip"},
 				},
 				"responses": map[string]any{
 					"200": map[string]any{
-						"description": "Cron definition with last result",
+						"description": "Cron definition with last result and runs, newest first",
 						"content": map[string]any{
 							"application/json": map[string]any{
-								"schema": map[string]any{"$ref": "#/components/schemas/CronInfo"},
+								"schema": map[string]any{"$ref": "#/components/schemas/CronDetail"},
 							},
 						},
 					},
+					"400": map[string]any{"description": "Invalid limit or offset"},
 					"404": map[string]any{"description": "Cron not found"},
 				},
 			},
@@ -311,6 +610,70 @@ func cronPaths() map[string]any {
 				},
 			},
 		},
+		"/api/scheduler": map[string]any{
+			"get": map[string]any{
+				"summary":     "List the live state of all scheduled jobs",
+				"operationId": "listSchedulerJobs",
+				"tags":        []string{"crons"},
+				"responses": map[string]any{
+					"200": map[string]any{
+						"description": "Array of job states, built-in jobs included, in registration order",
+						"content": map[string]any{
+							"application/json": map[string]any{
+								"schema": map[string]any{
+									"type":  "array",
+									"items": map[string]any{"$ref": "#/components/schemas/JobStatus"},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func eventPaths() map[string]any {
+	types := make([]string, len(activity.Types))
+	for i, t := range activity.Types {
+		types[i] = string(t)
+	}
+	list := func(name, desc string, items map[string]any) map[string]any {
+		return map[string]any{
+			"name":        name,
+			"in":          "query",
+			"style":       "form",
+			"explode":     false,
+			"schema":      map[string]any{"type": "array", "items": items},
+			"description": desc,
+		}
+	}
+	return map[string]any{
+		"/api/events": map[string]any{
+			"get": map[string]any{
+				"summary":     "Stream live router activity as server-sent events",
+				"description": "Each event is named after its type and carries an ActivityEvent as JSON data. A \"dropped\" event reports events lost because the client read too slowly.",
+				"operationId": "streamEvents",
+				"tags":        []string{"monitoring"},
+				"parameters": []map[string]any{
+					list("agent", "Only events of these agents", map[string]any{"type": "string"}),
+					list("session", "Only events of these sessions", map[string]any{"type": "string"}),
+					list("type", "Only events of these types", map[string]any{"type": "string", "enum": types}),
+				},
+				"responses": map[string]any{
+					"200": map[string]any{
+						"description": "Event stream",
+						"content": map[string]any{
+							"text/event-stream": map[string]any{
+								"schema": map[string]any{"$ref": "#/components/schemas/ActivityEvent"},
+							},
+						},
+					},
+					"400": map[string]any{"description": "Unknown event type"},
+					"503": map[string]any{"description": "Activity events not available"},
+				},
+			},
+		},
 	}
 }
 
@@ -367,6 +730,53 @@ func componentSchemas() map[string]any {
 				"metadata":       map[string]any{"type": "object", "nullable": true},
 			},
 		},
+		"SessionHistory": map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"session_id": map[string]any{"type": "string"},
+				"persistent": map[string]any{
+					"type":        "boolean",
+					"description": "Whether messages come from the agent's history store; otherwise only the live, most recent messages are shown",
+				},
+				"summary": map[string]any{"type": "string"},
+				"messages": map[string]any{
+					"type": "array",
+					"items": map[string]any{
+						"type": "object",
+						"properties": map[string]any{
+							"index":         map[string]any{"type": "integer"},
+							"role":          map[string]any{"type": "string", "enum": []string{"system", "user", "assistant", "tool"}},
+							"content":       map[string]any{"type": "string"},
+							"content_parts": map[string]any{"type": "array", "items": map[string]any{"type": "object"}},
+							"name":          map[string]any{"type": "string"},
+							"tool_id":       map[string]any{"type": "string"},
+							"tool_calls":    map[string]any{"type": "array", "items": map[string]any{"type": "object"}},
+							"is_error":      map[string]any{"type": "boolean"},
+						},
+					},
+				},
+			},
+		},
+		"SessionSummary": map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"summary": map[string]any{"type": "string"},
+			},
+		},
+		"ConfigFile": map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"yaml":      map[string]any{"type": "string", "description": "Values reading ***REDACTED*** keep the secret from the file on disk"},
+				"base_hash": map[string]any{"type": "string", "description": "SHA-256 of the file the edit is based on"},
+			},
+		},
+		"ConfigValidation": map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"valid": map[string]any{"type": "boolean"},
+				"error": map[string]any{"type": "string"},
+			},
+		},
 		"Agent": map[string]any{
 			"type": "object",
 			"properties": map[string]any{
@@ -389,12 +799,75 @@ func componentSchemas() map[string]any {
 				"name":        map[string]any{"type": "string"},
 				"description": map[string]any{"type": "string"},
 				"schedule":    map[string]any{"type": "string", "example": "0 7 * * *"},
+				"at":          map[string]any{"type": "string", "example": "2026-03-14 09:00"},
+				"timezone":    map[string]any{"type": "string", "example": "Europe/Paris"},
 				"enabled":     map[string]any{"type": "boolean"},
 				"agent_id":    map[string]any{"type": "string"},
 				"last_result": map[string]any{
 					"nullable": true,
 					"$ref":     "#/components/schemas/CronResult",
 				},
+				"status": map[string]any{
+					"nullable":    true,
+					"$ref":        "#/components/schemas/JobStatus",
+					"description": "Live state of the job; absent when it is not scheduled",
+				},
+			},
+		},
+		"ActivityEvent": map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"time":       map[string]any{"type": "string", "format": "date-time"},
+				"type":       map[string]any{"type": "string", "example": "tool_call"},
+				"agent_id":   map[string]any{"type": "string"},
+				"session_id": map[string]any{"type": "string"},
+				"channel":    map[string]any{"type": "string"},
+				"chat_id":    map[string]any{"type": "string"},
+				"data": map[string]any{
+					"type":                 "object",
+					"additionalProperties": true,
+					"description":          "Type-specific fields, e.g. tool and duration_ms for tool_call",
+				},
+			},
+		},
+		"JobStatus": map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"name":          map[string]any{"type": "string", "example": "prompt_cron:main:daily-digest"},
+				"schedule":      map[string]any{"type": "string"},
+				"next_run":      map[string]any{"type": "string", "format": "date-time"},
+				"prev_run":      map[string]any{"type": "string", "format": "date-time"},
+				"running":       map[string]any{"type": "boolean"},
+				"running_since": map[string]any{"type": "string", "format": "date-time"},
+				"runs":          map[string]any{"type": "integer", "description": "Runs since startup"},
+				"failures":      map[string]any{"type": "integer", "description": "Failed runs since startup"},
+				"skips":         map[string]any{"type": "integer", "description": "Ticks skipped because the job was still running"},
+				"durations": map[string]any{
+					"type":        "object",
+					"description": "Durations of the latest runs (up to 20)",
+					"properties": map[string]any{
+						"count":   map[string]any{"type": "integer"},
+						"last_ms": map[string]any{"type": "integer", "format": "int64"},
+						"avg_ms":  map[string]any{"type": "integer", "format": "int64"},
+						"min_ms":  map[string]any{"type": "integer", "format": "int64"},
+						"max_ms":  map[string]any{"type": "integer", "format": "int64"},
+					},
+				},
+			},
+		},
+		"CronDetail": map[string]any{
+			"allOf": []map[string]any{
+				{"$ref": "#/components/schemas/CronInfo"},
+				{
+					"type": "object",
+					"properties": map[string]any{
+						"runs": map[string]any{
+							"type":  "array",
+							"items": map[string]any{"$ref": "#/components/schemas/CronResult"},
+						},
+						"total_runs": map[string]any{"type": "integer"},
+					},
+				},
 			},
 		},
 		"CronResult": map[string]any{
@@ -409,6 +882,8 @@ func componentSchemas() map[string]any {
 				"total_tokens": map[string]any{"type": "integer"},
 				"content":      map[string]any{"type": "string"},
 				"error":        map[string]any{"type": "string"},
+				"attempts":     map[string]any{"type": "integer", "description": "Attempts made, when the run was retried"},
+				"notified":     map[string]any{"type": "boolean", "description": "Whether a message was sent to the output chat"},
 			},
 		},
 		"TriggerResponse": map[string]any{
@@ -419,6 +894,30 @@ func componentSchemas() map[string]any {
 				"agent_id": map[string]any{"type": "string"},
 			},
 		},
+		"WorkspaceTurn": map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"id":          map[string]any{"type": "integer"},
+				"session":     map[string]any{"type": "string"},
+				"prompt":      map[string]any{"type": "string"},
+				"started_at":  map[string]any{"type": "string", "format": "date-time"},
+				"reverted_at": map[string]any{"type": "string", "format": "date-time", "nullable": true},
+				"changes": map[string]any{
+					"type": "array",
+					"items": map[string]any{
+						"type": "object",
+						"properties": map[string]any{
+							"path":   map[string]any{"type": "string"},
+							"op":     map[string]any{"type": "string", "enum": []string{"create", "modify", "delete"}},
+							"tool":   map[string]any{"type": "string"},
+							"before": map[string]any{"type": "string", "description": "SHA-256 of the content before the turn"},
+							"after":  map[string]any{"type": "string", "description": "SHA-256 of the content after the turn"},
+							"mode":   map[string]any{"type": "integer"},
+						},
+					},
+				},
+			},
+		},
 	}
 }
 
@@ -47,10 +47,22 @@ func TestOpenAPI_ServesValidYAML(t *testing.T) {
 	expectedPaths := []string{
 		"/health",
 		"/api/sessions",
+		"/api/sessions/{id}/history",
+		"/api/sessions/{id}/history/{index}",
+		"/api/sessions/{id}/summary",
+		"/api/sessions/{id}/agent",
+		"/api/config",
+		"/api/config/raw",
+		"/api/config/validate",
 		"/api/crons",
 		"/api/crons/{name}",
 		"/api/crons/{name}/trigger",
+		"/api/scheduler",
+		"/api/events",
+		"/api/agents/{id}/history",
+		"/api/agents/{id}/history/{turn}/revert",
 		"/api/openapi.yaml",
+		"/mcp/{agent}",
 	}
 	for _, p := range expectedPaths {
 		if _, ok := paths[p]; !ok {
@@ -70,7 +82,7 @@ func TestOpenAPI_ContainsCronSchemas(t *testing.T) {
 	body := rr.Body.String()
 
 	// Check that key schemas are present in the output.
-	for _, schema := range []string{"CronInfo", "CronResult", "TriggerResponse"} {
+	for _, schema := range []string{"CronInfo", "CronResult", "JobStatus", "TriggerResponse"} {
 		if !strings.Contains(body, schema) {
 			t.Errorf("schema %q not found in OpenAPI spec", schema)
 		}
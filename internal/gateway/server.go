@@ -23,21 +23,40 @@ func (g *Gateway) buildRouter() http.Handler {
 
 	// Admin endpoints — auth required. Not mounted if no auth configured.
 	if g.config.Auth.IsConfigured() {
+		// The admin console is public; it authenticates its API calls.
+		r.Handle(uiPath+"*", g.handleUI())
+		r.Get(uiPath[:len(uiPath)-1], http.RedirectHandler(uiPath, http.StatusMovedPermanently).ServeHTTP)
+
 		r.Group(func(r chi.Router) {
 			r.Use(authMiddleware(g.config.Auth, g.auditLogger, g.rateLimiter))
 			r.Get("/status", g.handleStatus())
 			r.Route("/api", func(r chi.Router) {
 				r.Get("/sessions", g.handleListSessions())
 				r.Delete("/sessions/{id}", g.handleDeleteSession())
+				r.Get("/sessions/{id}/history", g.handleGetSessionHistory())
+				r.Post("/sessions/{id}/history", g.handleInjectSessionMessage())
+				r.Patch("/sessions/{id}/history/{index}", g.handleEditSessionMessage())
+				r.Delete("/sessions/{id}/history/{index}", g.handleDeleteSessionMessage())
+				r.Get("/sessions/{id}/summary", g.handleGetSessionSummary())
+				r.Put("/sessions/{id}/summary", g.handleSetSessionSummary())
+				r.Put("/sessions/{id}/agent", g.handleReassignSession())
 				r.Get("/agents", g.handleListAgents())
+				r.Get("/agents/{id}/history", g.handleListWorkspaceHistory())
+				r.Post("/agents/{id}/history/{turn}/revert", g.handleRevertWorkspaceTurn())
 				r.Get("/modules", g.handleGetAllModules())
 				r.Get("/config", g.handleGetConfig())
+				r.Put("/config", g.handlePutConfig())
+				r.Get("/config/raw", g.handleGetConfigFile())
+				r.Post("/config/validate", g.handleValidateConfig())
 				r.Post("/config/reload", g.handleReloadConfig())
 				r.Get("/crons", g.handleListCrons())
 				r.Get("/crons/{name}", g.handleGetCron())
 				r.Post("/crons/{name}/trigger", g.handleTriggerCron())
+				r.Get("/scheduler", g.handleListSchedulerJobs())
+				r.Get("/events", g.handleEvents())
 				r.Get("/openapi.yaml", g.handleOpenAPI())
 			})
+			r.Handle("/mcp/{agent}", g.handleMCP())
 		})
 	} else {
 		g.logger.Warn("gateway: admin API disabled (no auth configured)")
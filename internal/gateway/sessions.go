@@ -0,0 +1,315 @@
+package gateway
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/flemzord/sclaw/internal/provider"
+	"github.com/flemzord/sclaw/internal/router"
+	"github.com/flemzord/sclaw/internal/security"
+	"github.com/go-chi/chi/v5"
+)
+
+// maxSessionBodySize caps the JSON bodies of the session endpoints.
+const maxSessionBodySize = 1 << 20
+
+// sessionEditor is the part of router.Router used to administer sessions.
+type sessionEditor interface {
+	SessionHistory(id string) (router.SessionHistory, error)
+	SetSessionSummary(id, summary string) error
+	EditSessionMessage(id string, index int, text string) error
+	DeleteSessionMessage(id string, index int) error
+	AppendSessionMessage(id string, msg provider.LLMMessage) error
+	ReassignSession(id, agentID string) error
+}
+
+var _ sessionEditor = (*router.Router)(nil)
+
+// historyMessageJSON is a serializable history message.
+type historyMessageJSON struct {
+	Index        int                    `json:"index"`
+	Role         provider.MessageRole   `json:"role"`
+	Content      string                 `json:"content"`
+	ContentParts []provider.ContentPart `json:"content_parts,omitempty"`
+	Name         string                 `json:"name,omitempty"`
+	ToolID       string                 `json:"tool_id,omitempty"`
+	ToolCalls    []provider.ToolCall    `json:"tool_calls,omitempty"`
+	IsError      bool                   `json:"is_error,omitempty"`
+}
+
+// sessionHistoryJSON is the JSON response for GET /api/sessions/{id}/history.
+type sessionHistoryJSON struct {
+	SessionID  string               `json:"session_id"`
+	Persistent bool                 `json:"persistent"`
+	Summary    string               `json:"summary"`
+	Messages   []historyMessageJSON `json:"messages"`
+}
+
+// summaryJSON is the body of the summary endpoints.
+type summaryJSON struct {
+	Summary string `json:"summary"`
+}
+
+// injectRequest is the body of POST /api/sessions/{id}/history.
+type injectRequest struct {
+	Role    provider.MessageRole `json:"role"`
+	Content string               `json:"content"`
+}
+
+// editRequest is the body of PATCH /api/sessions/{id}/history/{index}.
+type editRequest struct {
+	Content string `json:"content"`
+}
+
+// reassignRequest is the body of PUT /api/sessions/{id}/agent.
+type reassignRequest struct {
+	AgentID string `json:"agent_id"`
+}
+
+// handleGetSessionHistory returns the full history and summary of a session.
+func (g *Gateway) handleGetSessionHistory() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !g.requireSessionAdmin(w) {
+			return
+		}
+		id := chi.URLParam(r, "id")
+		h, err := g.sessionAdmin.SessionHistory(id)
+		if err != nil {
+			g.writeSessionError(w, id, err)
+			return
+		}
+		g.auditSession(id, "read_history", nil)
+
+		out := sessionHistoryJSON{
+			SessionID:  id,
+			Persistent: h.Persistent,
+			Summary:    h.Summary,
+			Messages:   make([]historyMessageJSON, len(h.Messages)),
+		}
+		for i, m := range h.Messages {
+			out.Messages[i] = historyMessageJSON{
+				Index:        i,
+				Role:         m.Role,
+				Content:      m.Content,
+				ContentParts: m.ContentParts,
+				Name:         m.Name,
+				ToolID:       m.ToolID,
+				ToolCalls:    m.ToolCalls,
+				IsError:      m.IsError,
+			}
+		}
+		writeJSON(w, http.StatusOK, out)
+	}
+}
+
+// handleInjectSessionMessage appends a user or system message to a session.
+// The agent sees it on the session's next turn.
+func (g *Gateway) handleInjectSessionMessage() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !g.requireSessionAdmin(w) {
+			return
+		}
+		var req injectRequest
+		if !decodeJSONBody(w, r, &req, maxSessionBodySize) {
+			return
+		}
+		if req.Role != provider.MessageRoleUser && req.Role != provider.MessageRoleSystem {
+			http.Error(w, "role must be user or system", http.StatusBadRequest)
+			return
+		}
+		if req.Content == "" {
+			http.Error(w, "missing content", http.StatusBadRequest)
+			return
+		}
+
+		id := chi.URLParam(r, "id")
+		msg := provider.LLMMessage{Role: req.Role, Content: req.Content}
+		if err := g.sessionAdmin.AppendSessionMessage(id, msg); err != nil {
+			g.writeSessionError(w, id, err)
+			return
+		}
+		g.auditSession(id, "inject_message", map[string]string{"role": string(req.Role)})
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// handleEditSessionMessage replaces the text of one history message.
+func (g *Gateway) handleEditSessionMessage() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !g.requireSessionAdmin(w) {
+			return
+		}
+		index, ok := messageIndex(w, r)
+		if !ok {
+			return
+		}
+		var req editRequest
+		if !decodeJSONBody(w, r, &req, maxSessionBodySize) {
+			return
+		}
+
+		id := chi.URLParam(r, "id")
+		if err := g.sessionAdmin.EditSessionMessage(id, index, req.Content); err != nil {
+			g.writeSessionError(w, id, err)
+			return
+		}
+		g.auditSession(id, "edit_message", map[string]string{"index": strconv.Itoa(index)})
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// handleDeleteSessionMessage removes one history message.
+func (g *Gateway) handleDeleteSessionMessage() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !g.requireSessionAdmin(w) {
+			return
+		}
+		index, ok := messageIndex(w, r)
+		if !ok {
+			return
+		}
+
+		id := chi.URLParam(r, "id")
+		if err := g.sessionAdmin.DeleteSessionMessage(id, index); err != nil {
+			g.writeSessionError(w, id, err)
+			return
+		}
+		g.auditSession(id, "delete_message", map[string]string{"index": strconv.Itoa(index)})
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// handleGetSessionSummary returns the stored summary of a session.
+func (g *Gateway) handleGetSessionSummary() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !g.requireSessionAdmin(w) {
+			return
+		}
+		id := chi.URLParam(r, "id")
+		h, err := g.sessionAdmin.SessionHistory(id)
+		if err != nil {
+			g.writeSessionError(w, id, err)
+			return
+		}
+		g.auditSession(id, "read_summary", nil)
+		writeJSON(w, http.StatusOK, summaryJSON{Summary: h.Summary})
+	}
+}
+
+// handleSetSessionSummary replaces the stored summary of a session.
+func (g *Gateway) handleSetSessionSummary() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !g.requireSessionAdmin(w) {
+			return
+		}
+		var req summaryJSON
+		if !decodeJSONBody(w, r, &req, maxSessionBodySize) {
+			return
+		}
+
+		id := chi.URLParam(r, "id")
+		if err := g.sessionAdmin.SetSessionSummary(id, req.Summary); err != nil {
+			g.writeSessionError(w, id, err)
+			return
+		}
+		g.auditSession(id, "set_summary", nil)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// handleReassignSession hands a session over to another agent.
+func (g *Gateway) handleReassignSession() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !g.requireSessionAdmin(w) {
+			return
+		}
+		var req reassignRequest
+		if !decodeJSONBody(w, r, &req, maxSessionBodySize) {
+			return
+		}
+		if req.AgentID == "" {
+			http.Error(w, "missing agent_id", http.StatusBadRequest)
+			return
+		}
+
+		id := chi.URLParam(r, "id")
+		if err := g.sessionAdmin.ReassignSession(id, req.AgentID); err != nil {
+			g.writeSessionError(w, id, err)
+			return
+		}
+		g.auditSession(id, "reassign", map[string]string{"agent": req.AgentID})
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// requireSessionAdmin writes a 503 response when sessions cannot be
+// administered.
+func (g *Gateway) requireSessionAdmin(w http.ResponseWriter) bool {
+	if g.sessionAdmin == nil {
+		http.Error(w, "session administration not available", http.StatusServiceUnavailable)
+		return false
+	}
+	return true
+}
+
+// writeSessionError maps a session administration error to a response.
+func (g *Gateway) writeSessionError(w http.ResponseWriter, id string, err error) {
+	switch {
+	case errors.Is(err, router.ErrSessionNotFound):
+		http.Error(w, "session not found", http.StatusNotFound)
+	case errors.Is(err, router.ErrMessageNotFound):
+		http.Error(w, "message not found", http.StatusNotFound)
+	case errors.Is(err, router.ErrUnknownAgent):
+		http.Error(w, "unknown agent", http.StatusBadRequest)
+	case errors.Is(err, router.ErrNoPersistentHistory):
+		http.Error(w, "session has no persistent history", http.StatusConflict)
+	case errors.Is(err, router.ErrHistoryNotEditable):
+		http.Error(w, "history store does not support editing", http.StatusNotImplemented)
+	default:
+		g.logger.Error("session administration failed", "session_id", id, "error", err)
+		http.Error(w, "session administration failed", http.StatusInternalServerError)
+	}
+}
+
+// auditSession records an administrative access to a session. Message
+// contents are never logged.
+func (g *Gateway) auditSession(id, action string, meta map[string]string) {
+	if g.auditLogger == nil {
+		return
+	}
+	if meta == nil {
+		meta = make(map[string]string, 1)
+	}
+	meta["action"] = action
+	g.auditLogger.Log(security.AuditEvent{
+		Type:      security.EventSessionAdmin,
+		SessionID: id,
+		Detail:    action + " via admin API",
+		Metadata:  meta,
+	})
+}
+
+// messageIndex parses the {index} URL parameter.
+func messageIndex(w http.ResponseWriter, r *http.Request) (int, bool) {
+	index, err := strconv.Atoi(chi.URLParam(r, "index"))
+	if err != nil || index < 0 {
+		http.Error(w, "invalid message index", http.StatusBadRequest)
+		return 0, false
+	}
+	return index, true
+}
+
+// decodeJSONBody decodes a JSON request body of at most limit bytes into v,
+// writing a 400 response on failure.
+func decodeJSONBody(w http.ResponseWriter, r *http.Request, v any, limit int64) bool {
+	r.Body = http.MaxBytesReader(w, r.Body, limit)
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		http.Error(w, "invalid JSON body", http.StatusBadRequest)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,237 @@
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/flemzord/sclaw/internal/provider"
+	"github.com/flemzord/sclaw/internal/router"
+	"github.com/flemzord/sclaw/internal/security"
+	"github.com/go-chi/chi/v5"
+)
+
+// stubSessionEditor administers a single session "s1" kept in memory.
+type stubSessionEditor struct {
+	history router.SessionHistory
+	agentID string
+}
+
+func (s *stubSessionEditor) SessionHistory(id string) (router.SessionHistory, error) {
+	if id != "s1" {
+		return router.SessionHistory{}, router.ErrSessionNotFound
+	}
+	return s.history, nil
+}
+
+func (s *stubSessionEditor) SetSessionSummary(id, summary string) error {
+	if id != "s1" {
+		return router.ErrSessionNotFound
+	}
+	s.history.Summary = summary
+	return nil
+}
+
+func (s *stubSessionEditor) EditSessionMessage(id string, index int, text string) error {
+	if id != "s1" {
+		return router.ErrSessionNotFound
+	}
+	if index >= len(s.history.Messages) {
+		return router.ErrMessageNotFound
+	}
+	s.history.Messages[index].Content = text
+	return nil
+}
+
+func (s *stubSessionEditor) DeleteSessionMessage(id string, index int) error {
+	if id != "s1" {
+		return router.ErrSessionNotFound
+	}
+	if index >= len(s.history.Messages) {
+		return router.ErrMessageNotFound
+	}
+	s.history.Messages = slices.Delete(s.history.Messages, index, index+1)
+	return nil
+}
+
+func (s *stubSessionEditor) AppendSessionMessage(id string, msg provider.LLMMessage) error {
+	if id != "s1" {
+		return router.ErrSessionNotFound
+	}
+	s.history.Messages = append(s.history.Messages, msg)
+	return nil
+}
+
+func (s *stubSessionEditor) ReassignSession(id, agentID string) error {
+	if id != "s1" {
+		return router.ErrSessionNotFound
+	}
+	if agentID != "other" {
+		return router.ErrUnknownAgent
+	}
+	s.agentID = agentID
+	return nil
+}
+
+func newSessionsGateway(t *testing.T) (*stubSessionEditor, *bytes.Buffer, http.Handler) {
+	t.Helper()
+	editor := &stubSessionEditor{history: router.SessionHistory{
+		Persistent: true,
+		Messages: []provider.LLMMessage{
+			{Role: provider.MessageRoleUser, Content: "my token is abc"},
+			{Role: provider.MessageRoleAssistant, Content: "noted"},
+		},
+	}}
+	var audit bytes.Buffer
+	g := &Gateway{
+		logger:       slog.Default(),
+		sessionAdmin: editor,
+		auditLogger:  security.NewAuditLogger(security.AuditLoggerConfig{Writer: &audit}),
+	}
+	r := chi.NewRouter()
+	r.Get("/api/sessions/{id}/history", g.handleGetSessionHistory())
+	r.Post("/api/sessions/{id}/history", g.handleInjectSessionMessage())
+	r.Patch("/api/sessions/{id}/history/{index}", g.handleEditSessionMessage())
+	r.Delete("/api/sessions/{id}/history/{index}", g.handleDeleteSessionMessage())
+	r.Get("/api/sessions/{id}/summary", g.handleGetSessionSummary())
+	r.Put("/api/sessions/{id}/summary", g.handleSetSessionSummary())
+	r.Put("/api/sessions/{id}/agent", g.handleReassignSession())
+	return editor, &audit, r
+}
+
+func doSessionRequest(t *testing.T, h http.Handler, method, path, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequestWithContext(context.Background(), method, path, strings.NewReader(body))
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestSessions_GetHistory(t *testing.T) {
+	t.Parallel()
+
+	_, audit, h := newSessionsGateway(t)
+
+	rr := doSessionRequest(t, h, http.MethodGet, "/api/sessions/s1/history", "")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rr.Code, http.StatusOK)
+	}
+	var out sessionHistoryJSON
+	if err := json.NewDecoder(rr.Body).Decode(&out); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if out.SessionID != "s1" || !out.Persistent || len(out.Messages) != 2 || out.Messages[1].Index != 1 {
+		t.Errorf("history = %+v", out)
+	}
+	if !strings.Contains(audit.String(), `"action":"read_history"`) {
+		t.Errorf("audit log = %q, want a read_history event", audit.String())
+	}
+
+	rr = doSessionRequest(t, h, http.MethodGet, "/api/sessions/missing/history", "")
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("unknown session status = %d, want %d", rr.Code, http.StatusNotFound)
+	}
+}
+
+func TestSessions_EditMessages(t *testing.T) {
+	t.Parallel()
+
+	editor, audit, h := newSessionsGateway(t)
+
+	rr := doSessionRequest(t, h, http.MethodPatch, "/api/sessions/s1/history/0", `{"content":"my token is [removed]"}`)
+	if rr.Code != http.StatusNoContent {
+		t.Fatalf("edit status = %d, want %d", rr.Code, http.StatusNoContent)
+	}
+	rr = doSessionRequest(t, h, http.MethodDelete, "/api/sessions/s1/history/1", "")
+	if rr.Code != http.StatusNoContent {
+		t.Fatalf("delete status = %d, want %d", rr.Code, http.StatusNoContent)
+	}
+	rr = doSessionRequest(t, h, http.MethodPost, "/api/sessions/s1/history", `{"role":"system","content":"be brief"}`)
+	if rr.Code != http.StatusNoContent {
+		t.Fatalf("inject status = %d, want %d", rr.Code, http.StatusNoContent)
+	}
+
+	msgs := editor.history.Messages
+	if len(msgs) != 2 || msgs[0].Content != "my token is [removed]" || msgs[1].Role != provider.MessageRoleSystem {
+		t.Errorf("messages = %+v", msgs)
+	}
+
+	log := audit.String()
+	for _, action := range []string{"edit_message", "delete_message", "inject_message"} {
+		if !strings.Contains(log, `"action":"`+action+`"`) {
+			t.Errorf("audit log missing %s: %q", action, log)
+		}
+	}
+	if strings.Contains(log, "[removed]") {
+		t.Error("audit log contains message content")
+	}
+}
+
+func TestSessions_Errors(t *testing.T) {
+	t.Parallel()
+
+	_, _, h := newSessionsGateway(t)
+
+	tests := []struct {
+		name, method, path, body string
+		want                     int
+	}{
+		{"message out of range", http.MethodDelete, "/api/sessions/s1/history/5", "", http.StatusNotFound},
+		{"invalid index", http.MethodDelete, "/api/sessions/s1/history/x", "", http.StatusBadRequest},
+		{"assistant injection", http.MethodPost, "/api/sessions/s1/history", `{"role":"assistant","content":"x"}`, http.StatusBadRequest},
+		{"malformed body", http.MethodPatch, "/api/sessions/s1/history/0", `{"text":"x"}`, http.StatusBadRequest},
+		{"unknown agent", http.MethodPut, "/api/sessions/s1/agent", `{"agent_id":"nobody"}`, http.StatusBadRequest},
+		{"missing agent", http.MethodPut, "/api/sessions/s1/agent", `{}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := doSessionRequest(t, h, tt.method, tt.path, tt.body)
+			if rr.Code != tt.want {
+				t.Errorf("status = %d, want %d", rr.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestSessions_SummaryAndReassign(t *testing.T) {
+	t.Parallel()
+
+	editor, _, h := newSessionsGateway(t)
+
+	rr := doSessionRequest(t, h, http.MethodPut, "/api/sessions/s1/summary", `{"summary":"short"}`)
+	if rr.Code != http.StatusNoContent {
+		t.Fatalf("set summary status = %d, want %d", rr.Code, http.StatusNoContent)
+	}
+	rr = doSessionRequest(t, h, http.MethodGet, "/api/sessions/s1/summary", "")
+	var summary summaryJSON
+	if err := json.NewDecoder(rr.Body).Decode(&summary); err != nil || summary.Summary != "short" {
+		t.Errorf("summary = %+v, %v, want short", summary, err)
+	}
+
+	rr = doSessionRequest(t, h, http.MethodPut, "/api/sessions/s1/agent", `{"agent_id":"other"}`)
+	if rr.Code != http.StatusNoContent {
+		t.Fatalf("reassign status = %d, want %d", rr.Code, http.StatusNoContent)
+	}
+	if editor.agentID != "other" {
+		t.Errorf("agent = %q, want other", editor.agentID)
+	}
+}
+
+func TestSessions_Unavailable(t *testing.T) {
+	t.Parallel()
+
+	g := &Gateway{}
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/api/sessions/s1/history", nil)
+	rr := httptest.NewRecorder()
+	g.handleGetSessionHistory().ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want %d", rr.Code, http.StatusServiceUnavailable)
+	}
+}
@@ -0,0 +1,37 @@
+package gateway
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+)
+
+// uiFiles holds the admin console, a static single-page app that only
+// talks to the admin API.
+//
+//go:embed ui
+var uiFiles embed.FS
+
+// uiPath is where the admin console is served.
+const uiPath = "/ui/"
+
+// handleUI serves the admin console. The assets hold no data, so they are
+// public; the console asks for credentials and sends them with every API
+// request.
+func (g *Gateway) handleUI() http.Handler {
+	assets, err := fs.Sub(uiFiles, "ui")
+	if err != nil {
+		// Should never happen: the directory is embedded at build time.
+		panic("gateway: missing embedded ui: " + err.Error())
+	}
+	files := http.StripPrefix(uiPath, http.FileServerFS(assets))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h := w.Header()
+		h.Set("Content-Security-Policy", "default-src 'self'; img-src 'self' data:; frame-ancestors 'none'")
+		h.Set("X-Content-Type-Options", "nosniff")
+		h.Set("X-Frame-Options", "DENY")
+		h.Set("Referrer-Policy", "no-referrer")
+		files.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,399 @@
+// Admin console for the sclaw gateway. It only uses the gateway's HTTP API;
+// credentials are kept in sessionStorage and sent with every request.
+"use strict";
+
+const $ = (id) => document.getElementById(id);
+
+const state = {
+  auth: sessionStorage.getItem("sclaw.auth") || "",
+  tab: "overview",
+  session: null,
+  configHash: "",
+};
+
+// el builds an element. Text is always set through textContent.
+function el(tag, props = {}, ...children) {
+  const node = document.createElement(tag);
+  for (const [key, value] of Object.entries(props)) {
+    if (key === "class") node.className = value;
+    else if (key.startsWith("on")) node.addEventListener(key.slice(2), value);
+    else node[key] = value;
+  }
+  for (const child of children) {
+    node.append(child instanceof Node ? child : document.createTextNode(String(child ?? "")));
+  }
+  return node;
+}
+
+function showError(message) {
+  const box = $("error");
+  box.textContent = message;
+  box.hidden = !message;
+}
+
+async function api(method, path, body) {
+  const headers = { Authorization: state.auth };
+  let payload;
+  if (typeof body === "string") {
+    payload = body;
+    headers["Content-Type"] = "application/x-yaml";
+  } else if (body !== undefined) {
+    payload = JSON.stringify(body);
+    headers["Content-Type"] = "application/json";
+  }
+  const resp = await fetch(path, { method, headers, body: payload });
+  if (resp.status === 401) {
+    signOut();
+    throw new Error("Invalid credentials.");
+  }
+  const text = await resp.text();
+  let data = text;
+  if ((resp.headers.get("Content-Type") || "").includes("application/json") && text) {
+    data = JSON.parse(text);
+  }
+  if (!resp.ok) {
+    const message = typeof data === "object" && data.error ? data.error : text.trim();
+    const err = new Error(message || resp.statusText);
+    err.status = resp.status;
+    err.data = data;
+    throw err;
+  }
+  return data;
+}
+
+// run reports the failure of an action in the error banner.
+async function run(action) {
+  showError("");
+  try {
+    await action();
+  } catch (err) {
+    showError(err.message);
+  }
+}
+
+function formatTime(value) {
+  if (!value) return "–";
+  const date = new Date(value);
+  return isNaN(date) ? value : date.toLocaleString();
+}
+
+function formatDuration(seconds) {
+  const d = Math.floor(seconds / 86400);
+  const h = Math.floor((seconds % 86400) / 3600);
+  const m = Math.floor((seconds % 3600) / 60);
+  if (d) return `${d}d ${h}h`;
+  if (h) return `${h}h ${m}m`;
+  return `${m}m ${seconds % 60}s`;
+}
+
+// --- Authentication ---
+
+function signIn(event) {
+  event.preventDefault();
+  const form = event.target;
+  const token = form.token.value.trim();
+  if (token) {
+    state.auth = `Bearer ${token}`;
+  } else if (form.user.value) {
+    state.auth = `Basic ${btoa(`${form.user.value}:${form.pass.value}`)}`;
+  } else {
+    showError("Enter a token or a user and password.");
+    return;
+  }
+  form.reset();
+  run(async () => {
+    await api("GET", "/status");
+    sessionStorage.setItem("sclaw.auth", state.auth);
+    showConsole();
+  });
+}
+
+function signOut() {
+  state.auth = "";
+  sessionStorage.removeItem("sclaw.auth");
+  for (const section of document.querySelectorAll("main > section")) section.hidden = true;
+  $("login").hidden = false;
+  $("tabs").hidden = true;
+  $("logout").hidden = true;
+}
+
+function showConsole() {
+  $("login").hidden = true;
+  $("tabs").hidden = false;
+  $("logout").hidden = false;
+  selectTab(state.tab);
+}
+
+function selectTab(tab) {
+  state.tab = tab;
+  for (const button of document.querySelectorAll("#tabs button")) {
+    button.classList.toggle("active", button.dataset.tab === tab);
+  }
+  for (const name of ["overview", "sessions", "crons", "config"]) {
+    $(name).hidden = name !== tab;
+  }
+  const loaders = { overview: loadOverview, sessions: loadSessions, crons: loadCrons, config: loadConfig };
+  run(loaders[tab]);
+}
+
+// --- Overview ---
+
+async function loadOverview() {
+  const health = await fetch("/health").then((r) => r.json());
+  const status = await api("GET", "/status");
+
+  const healthBox = $("health-status");
+  healthBox.textContent = health.status;
+  healthBox.className = `value ${health.status === "ok" ? "ok" : "bad"}`;
+  $("uptime").textContent = formatDuration(status.uptime_seconds);
+  $("session-count").textContent = status.sessions;
+  $("message-count").textContent = `${status.metrics.messages} / ${status.metrics.completions}`;
+
+  const providers = status.providers || [];
+  $("no-providers").hidden = providers.length > 0;
+  $("providers").replaceChildren(
+    ...providers.map((p) => {
+      const stateClass = p.state === "healthy" ? "ok" : p.state === "dead" ? "bad" : "warn";
+      return el(
+        "tr",
+        {},
+        el("td", {}, p.name),
+        el("td", {}, p.role),
+        el("td", { class: stateClass }, p.state),
+        el("td", {}, p.failures),
+        el("td", {}, p.current_backoff ? `${Math.round(p.current_backoff / 1e9)}s` : "–"),
+      );
+    }),
+  );
+}
+
+// --- Sessions ---
+
+async function loadSessions() {
+  const [sessions, agents] = await Promise.all([api("GET", "/api/sessions"), api("GET", "/api/agents")]);
+
+  const ids = new Set(agents.map((a) => a.name));
+  sessions.forEach((s) => s.agent_id && ids.add(s.agent_id));
+  $("agent-ids").replaceChildren(...[...ids].sort().map((id) => el("option", { value: id })));
+
+  sessions.sort((a, b) => b.last_active_at.localeCompare(a.last_active_at));
+  $("session-list").replaceChildren(
+    ...sessions.map((s) =>
+      el(
+        "tr",
+        {
+          class: `selectable${state.session && state.session.id === s.id ? " selected" : ""}`,
+          onclick: (event) => run(() => openSession(s, event.currentTarget)),
+        },
+        el("td", {}, [s.channel, s.chat_id, s.thread_id].filter(Boolean).join(" / ")),
+        el("td", {}, s.agent_id || "–"),
+        el("td", {}, s.history_len),
+        el("td", {}, formatTime(s.last_active_at)),
+      ),
+    ),
+  );
+  if (state.session && !sessions.some((s) => s.id === state.session.id)) {
+    state.session = null;
+    $("session-detail").hidden = true;
+  }
+}
+
+async function openSession(session, row) {
+  state.session = session;
+  for (const other of $("session-list").children) other.classList.toggle("selected", other === row);
+  await loadHistory();
+  $("session-detail").hidden = false;
+}
+
+function sessionPath(suffix = "") {
+  return `/api/sessions/${encodeURIComponent(state.session.id)}${suffix}`;
+}
+
+async function loadHistory() {
+  const s = state.session;
+  const history = await api("GET", sessionPath("/history"));
+
+  $("session-title").textContent = [s.channel, s.chat_id, s.thread_id].filter(Boolean).join(" / ");
+  $("session-agent").value = s.agent_id || "";
+  $("session-persistence").textContent = history.persistent
+    ? "Full history from the agent's history store."
+    : "Live history only: this agent has no history store, older messages are not kept.";
+  $("session-summary").value = history.summary;
+  $("session-summary").disabled = !history.persistent;
+  $("save-summary").disabled = !history.persistent;
+  $("history").replaceChildren(...history.messages.map(historyItem));
+}
+
+function historyItem(msg) {
+  const text = msg.content || (msg.content_parts || []).filter((p) => p.type === "text").map((p) => p.text).join("\n");
+  const body = el("pre", {}, text);
+  if (msg.tool_calls && msg.tool_calls.length) {
+    body.append(el("span", { class: "muted" }, `\n→ ${msg.tool_calls.map((c) => c.name).join(", ")}`));
+  }
+  const item = el("li", {});
+
+  const edit = () => {
+    const area = el("textarea", { rows: Math.min(12, text.split("\n").length + 1), value: text });
+    const save = el("button", {
+      onclick: () =>
+        run(async () => {
+          await api("PATCH", sessionPath(`/history/${msg.index}`), { content: area.value });
+          await loadHistory();
+        }),
+    }, "Save");
+    const cancel = el("button", { onclick: () => item.replaceWith(historyItem(msg)) }, "Cancel");
+    body.replaceWith(el("div", {}, area, el("div", { class: "toolbar" }, save, cancel)));
+  };
+  const remove = () =>
+    run(async () => {
+      if (!confirm(`Remove message #${msg.index}?`)) return;
+      await api("DELETE", sessionPath(`/history/${msg.index}`));
+      await loadHistory();
+    });
+
+  const images = (msg.content_parts || []).filter((p) => p.type !== "text").length;
+  item.append(
+    el(
+      "div",
+      { class: "meta" },
+      el("strong", {}, `#${msg.index} ${msg.role}`),
+      msg.name ? el("span", {}, msg.name) : "",
+      images ? el("span", {}, `${images} attachment(s)`) : "",
+      msg.is_error ? el("span", { class: "bad" }, "error") : "",
+      el(
+        "span",
+        { class: "actions" },
+        el("button", { class: "small", onclick: edit }, "Edit"),
+        el("button", { class: "small danger", onclick: remove }, "Remove"),
+      ),
+    ),
+    body,
+  );
+  return item;
+}
+
+function saveSummary() {
+  run(async () => {
+    await api("PUT", sessionPath("/summary"), { summary: $("session-summary").value });
+  });
+}
+
+function reassign() {
+  run(async () => {
+    const agentID = $("session-agent").value.trim();
+    await api("PUT", sessionPath("/agent"), { agent_id: agentID });
+    state.session.agent_id = agentID;
+    await loadSessions();
+    await loadHistory();
+  });
+}
+
+function deleteSession() {
+  run(async () => {
+    if (!confirm("Delete this session? Its persistent history is kept.")) return;
+    await api("DELETE", sessionPath());
+    state.session = null;
+    $("session-detail").hidden = true;
+    await loadSessions();
+  });
+}
+
+function inject(event) {
+  event.preventDefault();
+  const form = event.target;
+  run(async () => {
+    await api("POST", sessionPath("/history"), { role: form.role.value, content: form.content.value });
+    form.content.value = "";
+    await loadHistory();
+  });
+}
+
+// --- Crons ---
+
+async function loadCrons() {
+  const crons = await api("GET", "/api/crons");
+  $("cron-list").replaceChildren(
+    ...crons.map((c) => {
+      const last = c.last_result;
+      const result = !last
+        ? el("span", { class: "muted" }, "never ran")
+        : last.error
+          ? el("span", { class: "bad", title: last.error }, `failed ${formatTime(last.ran_at)}`)
+          : el("span", { class: "ok", title: last.content }, `ok ${formatTime(last.ran_at)}`);
+      const running = c.status && c.status.running;
+      return el(
+        "tr",
+        {},
+        el("td", {}, c.name, c.enabled ? "" : el("span", { class: "muted" }, " (disabled)")),
+        el("td", {}, c.schedule || c.at || "–"),
+        el("td", {}, c.agent_id),
+        el("td", {}, running ? "running" : formatTime(c.status && c.status.next_run)),
+        el("td", {}, result),
+        el("td", {}, el("button", { class: "small", onclick: () => trigger(c.name) }, "Run now")),
+      );
+    }),
+  );
+}
+
+function trigger(name) {
+  run(async () => {
+    await api("POST", `/api/crons/${encodeURIComponent(name)}/trigger`);
+    await loadCrons();
+  });
+}
+
+// --- Config ---
+
+async function loadConfig() {
+  const file = await api("GET", "/api/config/raw");
+  $("config-editor").value = file.yaml;
+  state.configHash = file.base_hash;
+  setConfigResult("", "");
+}
+
+function setConfigResult(message, kind) {
+  const box = $("config-result");
+  box.textContent = message;
+  box.className = kind;
+}
+
+function validateConfig() {
+  run(async () => {
+    const result = await api("POST", "/api/config/validate", $("config-editor").value);
+    setConfigResult(result.valid ? "Configuration is valid." : result.error, result.valid ? "ok" : "bad");
+  });
+}
+
+function saveConfig() {
+  run(async () => {
+    try {
+      const result = await api("PUT", "/api/config", { yaml: $("config-editor").value, base_hash: state.configHash });
+      state.configHash = result.base_hash;
+      if (result.warning) setConfigResult(result.warning, "warn");
+      else setConfigResult("Saved and reloaded.", "ok");
+    } catch (err) {
+      if (err.status === 422) setConfigResult(err.message, "bad");
+      else if (err.status === 409) setConfigResult("The file changed on disk; discard your changes to load it.", "bad");
+      else throw err;
+    }
+  });
+}
+
+// --- Wiring ---
+
+$("login-form").addEventListener("submit", signIn);
+$("logout").addEventListener("click", signOut);
+for (const button of document.querySelectorAll("#tabs button")) {
+  button.addEventListener("click", () => selectTab(button.dataset.tab));
+}
+$("refresh-sessions").addEventListener("click", () => run(loadSessions));
+$("save-summary").addEventListener("click", saveSummary);
+$("reassign").addEventListener("click", reassign);
+$("delete-session").addEventListener("click", deleteSession);
+$("inject-form").addEventListener("submit", inject);
+$("refresh-crons").addEventListener("click", () => run(loadCrons));
+$("reload-config-file").addEventListener("click", () => run(loadConfig));
+$("validate-config").addEventListener("click", validateConfig);
+$("save-config").addEventListener("click", saveConfig);
+
+if (state.auth) showConsole();
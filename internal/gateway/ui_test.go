@@ -0,0 +1,67 @@
+package gateway
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestUI_ServesAssets(t *testing.T) {
+	t.Parallel()
+
+	g := &Gateway{}
+	h := g.handleUI()
+
+	tests := []struct {
+		path        string
+		contentType string
+		contains    string
+	}{
+		{"/ui/", "text/html", "<title>sclaw admin</title>"},
+		{"/ui/app.js", "javascript", "/api/sessions"},
+		{"/ui/style.css", "text/css", "--accent"},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, tt.path, nil)
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Errorf("%s: status = %d, want %d", tt.path, rr.Code, http.StatusOK)
+			continue
+		}
+		if ct := rr.Header().Get("Content-Type"); !strings.Contains(ct, tt.contentType) {
+			t.Errorf("%s: Content-Type = %q, want %q", tt.path, ct, tt.contentType)
+		}
+		if !strings.Contains(rr.Body.String(), tt.contains) {
+			t.Errorf("%s: body does not contain %q", tt.path, tt.contains)
+		}
+		if csp := rr.Header().Get("Content-Security-Policy"); !strings.Contains(csp, "default-src 'self'") {
+			t.Errorf("%s: Content-Security-Policy = %q", tt.path, csp)
+		}
+	}
+}
+
+func TestUI_MountedOnlyWithAuth(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		name string
+		auth AuthConfig
+		want int
+	}{
+		{"no auth", AuthConfig{}, http.StatusNotFound},
+		{"bearer", AuthConfig{BearerToken: "secret"}, http.StatusOK},
+	} {
+		g := newTestGateway(t, "127.0.0.1:0", tt.auth)
+		req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/ui/", nil)
+		rr := httptest.NewRecorder()
+		g.buildRouter().ServeHTTP(rr, req)
+
+		if rr.Code != tt.want {
+			t.Errorf("%s: status = %d, want %d", tt.name, rr.Code, tt.want)
+		}
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"crypto/sha256"
 	"crypto/subtle"
 	"encoding/hex"
+	"errors"
+	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
@@ -21,9 +23,15 @@ type WebhookHandler interface {
 	HandleWebhook(ctx context.Context, source string, body []byte, headers http.Header) error
 }
 
+// ErrWebhookSecretRequired is returned by RegisterSigned when no HMAC secret
+// is given or configured for the source.
+var ErrWebhookSecretRequired = errors.New("gateway: webhook secret required")
+
 type webhookEntry struct {
 	handler WebhookHandler
 	secret  string
+	// signed rejects every request while no secret is set.
+	signed bool
 }
 
 // WebhookDispatcher routes incoming webhooks to registered handlers with HMAC validation.
@@ -54,6 +62,41 @@ func (d *WebhookDispatcher) Register(source string, h WebhookHandler, secret str
 	d.handlers[source] = webhookEntry{handler: h, secret: secret}
 }
 
+// RegisterSigned is Register for handlers that must never run on an
+// unsigned request. It fails when neither secret nor a secret configured
+// via ConfigureSecret is set, and the source then keeps rejecting requests
+// if its secret is ever cleared.
+func (d *WebhookDispatcher) RegisterSigned(source string, h WebhookHandler, secret string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if secret == "" {
+		secret = d.handlers[source].secret
+	}
+	if secret == "" {
+		return fmt.Errorf("%w: %s", ErrWebhookSecretRequired, source)
+	}
+	d.handlers[source] = webhookEntry{handler: h, secret: secret, signed: true}
+	return nil
+}
+
+// Unregister removes the handler of a source. A secret configured for the
+// source is kept for the next handler registered.
+func (d *WebhookDispatcher) Unregister(source string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	entry, ok := d.handlers[source]
+	if !ok {
+		return
+	}
+	if entry.secret == "" {
+		delete(d.handlers, source)
+		return
+	}
+	entry.handler = nil
+	entry.signed = false
+	d.handlers[source] = entry
+}
+
 // ConfigureSecret pre-configures an HMAC secret for a source from the gateway config.
 // The actual handler is registered later by the module that owns the webhook.
 func (d *WebhookDispatcher) ConfigureSecret(source, secret string) {
@@ -107,15 +150,25 @@ func (d *WebhookDispatcher) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	entry, ok := d.handlers[source]
 	d.mu.RUnlock()
 
-	if !ok {
+	if !ok || entry.handler == nil {
 		d.logger.Warn("webhook received for unregistered source", "source", source)
 		http.Error(w, "unknown webhook source", http.StatusNotFound)
 		return
 	}
 
-	// Validate HMAC if secret is configured.
+	if entry.signed && entry.secret == "" {
+		d.logger.Warn("webhook rejected: source requires a secret", "source", source)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// Validate HMAC if secret is configured. GitHub sends the same
+	// signature format under its own header name.
 	if entry.secret != "" {
 		sig := r.Header.Get("X-Signature-256")
+		if sig == "" {
+			sig = r.Header.Get("X-Hub-Signature-256")
+		}
 		if !validateHMAC(body, sig, entry.secret) {
 			http.Error(w, "invalid signature", http.StatusUnauthorized)
 			return
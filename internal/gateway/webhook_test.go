@@ -94,6 +94,97 @@ func TestWebhookDispatcher_UnregisteredSource(t *testing.T) {
 	}
 }
 
+func TestWebhookDispatcher_GitHubSignatureHeader(t *testing.T) {
+	t.Parallel()
+
+	handler := &mockWebhookHandler{}
+	d := NewWebhookDispatcher(testLogger())
+	d.Register("github", handler, "my-secret")
+
+	r := chi.NewRouter()
+	r.Post("/webhooks/{source}", d.ServeHTTP)
+
+	body := []byte(`{"ref":"refs/heads/main"}`)
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodPost, "/webhooks/github", bytes.NewReader(body))
+	req.Header.Set("X-Hub-Signature-256", signPayload(body, "my-secret"))
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK || !handler.called {
+		t.Errorf("status = %d, called = %v, want 200 and called", rr.Code, handler.called)
+	}
+}
+
+func TestWebhookDispatcher_Unregister(t *testing.T) {
+	t.Parallel()
+
+	d := NewWebhookDispatcher(testLogger())
+	d.ConfigureSecret("github", "my-secret")
+	d.Register("github", &mockWebhookHandler{}, "")
+	d.Unregister("github")
+
+	r := chi.NewRouter()
+	r.Post("/webhooks/{source}", d.ServeHTTP)
+
+	body := []byte(`{}`)
+	post := func() int {
+		req := httptest.NewRequestWithContext(context.Background(), http.MethodPost, "/webhooks/github", bytes.NewReader(body))
+		req.Header.Set("X-Signature-256", signPayload(body, "my-secret"))
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+		return rr.Code
+	}
+
+	if code := post(); code != http.StatusNotFound {
+		t.Errorf("after Unregister: status = %d, want %d", code, http.StatusNotFound)
+	}
+
+	// The configured secret survives for the next handler.
+	handler := &mockWebhookHandler{}
+	d.Register("github", handler, "")
+	if code := post(); code != http.StatusOK || !handler.called {
+		t.Errorf("after Register: status = %d, called = %v", code, handler.called)
+	}
+}
+
+func TestWebhookDispatcher_RegisterSigned(t *testing.T) {
+	t.Parallel()
+
+	d := NewWebhookDispatcher(testLogger())
+	r := chi.NewRouter()
+	r.Post("/webhooks/{source}", d.ServeHTTP)
+	post := func(source string) int {
+		req := httptest.NewRequestWithContext(context.Background(), http.MethodPost, "/webhooks/"+source, bytes.NewReader([]byte(`{}`)))
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+		return rr.Code
+	}
+
+	// Without a secret the handler is refused and the source stays unknown.
+	unsigned := &mockWebhookHandler{}
+	if err := d.RegisterSigned("ops", unsigned, ""); !errors.Is(err, ErrWebhookSecretRequired) {
+		t.Fatalf("RegisterSigned() error = %v, want ErrWebhookSecretRequired", err)
+	}
+	if code := post("ops"); code != http.StatusNotFound || unsigned.called {
+		t.Errorf("unsigned source: status = %d, called = %v, want 404", code, unsigned.called)
+	}
+
+	// With one, unsigned requests are rejected.
+	signed := &mockWebhookHandler{}
+	if err := d.RegisterSigned("github", signed, "my-secret"); err != nil {
+		t.Fatalf("RegisterSigned() error = %v", err)
+	}
+	if code := post("github"); code != http.StatusUnauthorized || signed.called {
+		t.Errorf("unsigned request: status = %d, called = %v, want 401", code, signed.called)
+	}
+
+	// Clearing the secret afterwards does not open the source.
+	d.ConfigureSecret("github", "")
+	if code := post("github"); code != http.StatusUnauthorized || signed.called {
+		t.Errorf("after clearing the secret: status = %d, called = %v, want 401", code, signed.called)
+	}
+}
+
 func TestWebhookDispatcher_InvalidHMAC(t *testing.T) {
 	t.Parallel()
 
@@ -1,9 +1,12 @@
 package mcp
 
 import (
+	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"net/url"
 	"sync"
 	"time"
 
@@ -16,16 +19,38 @@ import (
 // and completing the initialize handshake.
 const connectTimeout = 30 * time.Second
 
-// Client wraps an MCP client connection and caches the server's tool list.
+const (
+	// minReconnectDelay is the first delay before reconnecting to a server
+	// whose connection was lost; it doubles after each failed attempt.
+	minReconnectDelay = time.Second
+
+	// maxReconnectDelay caps the delay between reconnection attempts.
+	maxReconnectDelay = 5 * time.Minute
+)
+
+// errServerExited reports that a stdio server process ended.
+var errServerExited = errors.New("server process exited")
+
+// Client wraps an MCP client connection and caches the server's tools,
+// prompts and resource capability. A lost connection is re-established in
+// the background with exponential backoff.
 type Client struct {
 	name      string
 	serverCfg ServerConfig
 	logger    *slog.Logger
 
-	mu        sync.Mutex
-	inner     *mcpclient.Client
-	tools     []mcp.Tool
-	connected bool
+	// minDelay is the first reconnection delay. Replaced in tests.
+	minDelay time.Duration
+
+	mu           sync.Mutex
+	inner        *mcpclient.Client
+	tools        []mcp.Tool
+	prompts      []mcp.Prompt
+	hasResources bool
+	connected    bool
+	reconnecting bool
+	closed       bool
+	stop         chan struct{}
 }
 
 // NewClient creates a new MCP client wrapper for the given server configuration.
@@ -35,44 +60,71 @@ func NewClient(name string, cfg ServerConfig, logger *slog.Logger) *Client {
 		name:      name,
 		serverCfg: cfg,
 		logger:    logger,
+		minDelay:  minReconnectDelay,
+		stop:      make(chan struct{}),
+	}
+}
+
+// Name returns the server name from mcp.json.
+func (c *Client) Name() string { return c.name }
+
+// Config returns the server configuration.
+func (c *Client) Config() ServerConfig { return c.serverCfg }
+
+// dial creates the client for the configured transport.
+func (c *Client) dial() (*mcpclient.Client, error) {
+	if c.serverCfg.IsStdio() {
+		return mcpclient.NewStdioMCPClient(
+			c.serverCfg.Command,
+			c.serverCfg.Env,
+			c.serverCfg.Args...,
+		)
+	}
+
+	opts := []transport.StreamableHTTPCOption{
+		transport.WithHTTPTimeout(connectTimeout),
+		// Keep a stream open so the server can notify list changes.
+		transport.WithContinuousListening(),
+	}
+	if len(c.serverCfg.Headers) > 0 {
+		opts = append(opts, transport.WithHTTPHeaders(c.serverCfg.Headers))
 	}
+	return mcpclient.NewStreamableHttpClient(c.serverCfg.URL, opts...)
 }
 
 // Connect establishes the connection, performs the MCP handshake, and caches
-// the server's tool list. It is idempotent — subsequent calls are no-ops.
+// the server's tools and prompts. It is idempotent — subsequent calls are
+// no-ops while connected.
 func (c *Client) Connect(ctx context.Context) error {
 	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	if c.connected {
+	closed, connected := c.closed, c.connected
+	c.mu.Unlock()
+	if closed {
+		return fmt.Errorf("mcp: client %q is closed", c.name)
+	}
+	if connected {
 		return nil
 	}
 
 	ctx, cancel := context.WithTimeout(ctx, connectTimeout)
 	defer cancel()
 
-	var (
-		inner *mcpclient.Client
-		err   error
-	)
-
-	if c.serverCfg.IsStdio() {
-		inner, err = mcpclient.NewStdioMCPClient(
-			c.serverCfg.Command,
-			c.serverCfg.Env,
-			c.serverCfg.Args...,
-		)
-	} else {
-		var opts []transport.StreamableHTTPCOption
-		if len(c.serverCfg.Headers) > 0 {
-			opts = append(opts, transport.WithHTTPHeaders(c.serverCfg.Headers))
-		}
-		opts = append(opts, transport.WithHTTPTimeout(connectTimeout))
-		inner, err = mcpclient.NewStreamableHttpClient(c.serverCfg.URL, opts...)
-	}
+	inner, err := c.dial()
 	if err != nil {
 		return fmt.Errorf("mcp: creating client %q: %w", c.name, err)
 	}
+	// Start wires the notification handlers. It outlives this call: the
+	// HTTP transport keeps listening for notifications with its context.
+	if err := inner.Start(context.Background()); err != nil {
+		_ = inner.Close()
+		return fmt.Errorf("mcp: starting client %q: %w", c.name, err)
+	}
+	inner.OnNotification(func(n mcp.JSONRPCNotification) {
+		c.handleNotification(inner, n)
+	})
+	inner.OnConnectionLost(func(err error) {
+		c.connectionLost(inner, err)
+	})
 
 	// Initialize handshake.
 	initReq := mcp.InitializeRequest{}
@@ -83,10 +135,12 @@ func (c *Client) Connect(ctx context.Context) error {
 	}
 	initReq.Params.Capabilities = mcp.ClientCapabilities{}
 
-	if _, err := inner.Initialize(ctx, initReq); err != nil {
+	initResult, err := inner.Initialize(ctx, initReq)
+	if err != nil {
 		_ = inner.Close()
 		return fmt.Errorf("mcp: initializing %q: %w", c.name, err)
 	}
+	caps := initResult.Capabilities
 
 	// List available tools.
 	toolsResult, err := inner.ListTools(ctx, mcp.ListToolsRequest{})
@@ -95,47 +149,298 @@ func (c *Client) Connect(ctx context.Context) error {
 		return fmt.Errorf("mcp: listing tools for %q: %w", c.name, err)
 	}
 
+	// Prompts are optional: a server failing to list them keeps its tools.
+	var prompts []mcp.Prompt
+	if caps.Prompts != nil {
+		if res, err := inner.ListPrompts(ctx, mcp.ListPromptsRequest{}); err == nil {
+			prompts = res.Prompts
+		} else {
+			c.logger.Warn("mcp: listing prompts failed", "server", c.name, "error", err)
+		}
+	}
+
+	c.mu.Lock()
+	if c.closed || c.connected {
+		// Closed, or connected by a concurrent call, during the handshake.
+		closed := c.closed
+		c.mu.Unlock()
+		_ = inner.Close()
+		if closed {
+			return fmt.Errorf("mcp: client %q is closed", c.name)
+		}
+		return nil
+	}
 	c.inner = inner
 	c.tools = toolsResult.Tools
+	c.prompts = prompts
+	c.hasResources = caps.Resources != nil
 	c.connected = true
+	c.mu.Unlock()
+
+	if stderr, ok := mcpclient.GetStderr(inner); ok {
+		go c.watchStderr(inner, bufio.NewScanner(stderr))
+	}
 
 	c.logger.Info("mcp: connected",
 		"server", c.name,
-		"tools", len(c.tools),
+		"tools", len(toolsResult.Tools),
+		"prompts", len(prompts),
+		"resources", caps.Resources != nil,
 	)
 
 	return nil
 }
 
-// Tools returns the cached list of MCP tools. Returns nil if not connected.
+// watchStderr logs what a stdio server writes to stderr, and notices its
+// exit when the stream ends. Reading stderr also keeps a chatty server from
+// blocking on a full pipe.
+func (c *Client) watchStderr(inner *mcpclient.Client, lines *bufio.Scanner) {
+	for lines.Scan() {
+		c.logger.Debug("mcp: server stderr", "server", c.name, "line", lines.Text())
+	}
+	c.connectionLost(inner, errServerExited)
+}
+
+// handleNotification refreshes the cached lists when the server reports a
+// change.
+func (c *Client) handleNotification(inner *mcpclient.Client, n mcp.JSONRPCNotification) {
+	switch n.Method {
+	case mcp.MethodNotificationToolsListChanged, mcp.MethodNotificationPromptsListChanged:
+	default:
+		return
+	}
+	// Refresh outside of the transport's notification callback, which
+	// must not block on requests to the same server.
+	go func() {
+		if err := c.refresh(inner, n.Method); err != nil {
+			c.logger.Warn("mcp: refreshing lists failed", "server", c.name, "error", err)
+		}
+	}()
+}
+
+// refresh reloads the list named by a list_changed notification.
+func (c *Client) refresh(inner *mcpclient.Client, method string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), connectTimeout)
+	defer cancel()
+
+	switch method {
+	case mcp.MethodNotificationToolsListChanged:
+		res, err := inner.ListTools(ctx, mcp.ListToolsRequest{})
+		if err != nil {
+			return err
+		}
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if c.inner == inner {
+			c.tools = res.Tools
+			c.logger.Info("mcp: tool list changed", "server", c.name, "tools", len(res.Tools))
+		}
+	case mcp.MethodNotificationPromptsListChanged:
+		res, err := inner.ListPrompts(ctx, mcp.ListPromptsRequest{})
+		if err != nil {
+			return err
+		}
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if c.inner == inner {
+			c.prompts = res.Prompts
+		}
+	}
+	return nil
+}
+
+// connectionLost drops the connection inner, if still current, and starts
+// reconnecting.
+func (c *Client) connectionLost(inner *mcpclient.Client, cause error) {
+	c.mu.Lock()
+	if c.inner != inner || c.closed {
+		c.mu.Unlock()
+		return
+	}
+	c.inner = nil
+	c.connected = false
+	c.mu.Unlock()
+
+	_ = inner.Close()
+	c.logger.Warn("mcp: connection lost", "server", c.name, "error", cause)
+	c.Reconnect()
+}
+
+// Reconnect starts reconnecting in the background unless the client is
+// connected, closed or already reconnecting.
+func (c *Client) Reconnect() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.connected || c.closed || c.reconnecting {
+		return
+	}
+	c.reconnecting = true
+	go c.reconnectLoop()
+}
+
+// reconnectLoop retries Connect with exponential backoff until it succeeds
+// or the client is closed.
+func (c *Client) reconnectLoop() {
+	defer func() {
+		c.mu.Lock()
+		c.reconnecting = false
+		c.mu.Unlock()
+	}()
+
+	delay := c.minDelay
+	for attempt := 1; ; attempt++ {
+		timer := time.NewTimer(delay)
+		select {
+		case <-c.stop:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		err := c.Connect(context.Background())
+		if err == nil {
+			c.logger.Info("mcp: reconnected", "server", c.name, "attempt", attempt)
+			return
+		}
+		c.mu.Lock()
+		closed := c.closed
+		c.mu.Unlock()
+		if closed {
+			return
+		}
+		c.logger.Debug("mcp: reconnect failed", "server", c.name, "attempt", attempt, "error", err)
+		delay = min(delay*2, maxReconnectDelay)
+	}
+}
+
+// session returns the current connection.
+func (c *Client) session() (*mcpclient.Client, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	switch {
+	case c.inner != nil:
+		return c.inner, nil
+	case c.closed:
+		return nil, fmt.Errorf("mcp: client %q is closed", c.name)
+	case c.reconnecting:
+		return nil, fmt.Errorf("mcp: server %q is unavailable, reconnecting", c.name)
+	default:
+		return nil, fmt.Errorf("mcp: client %q not connected", c.name)
+	}
+}
+
+// checkError starts reconnecting when err shows that the connection inner
+// is broken.
+func (c *Client) checkError(inner *mcpclient.Client, err error) {
+	if isConnectionError(err) {
+		c.connectionLost(inner, err)
+	}
+}
+
+// isConnectionError reports whether err means the connection is unusable,
+// as opposed to a failed request or a cancelled context.
+func isConnectionError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var urlErr *url.Error
+	return errors.Is(err, transport.ErrTransportClosed) ||
+		errors.Is(err, transport.ErrSessionTerminated) ||
+		errors.As(err, &urlErr)
+}
+
+// Tools returns the cached list of MCP tools. Returns nil if never connected.
 func (c *Client) Tools() []mcp.Tool {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	return c.tools
 }
 
-// CallTool invokes a tool on the connected MCP server.
-func (c *Client) CallTool(ctx context.Context, name string, args map[string]any) (*mcp.CallToolResult, error) {
+// Prompts returns the cached list of MCP prompts.
+func (c *Client) Prompts() []mcp.Prompt {
 	c.mu.Lock()
-	inner := c.inner
-	c.mu.Unlock()
+	defer c.mu.Unlock()
+	return c.prompts
+}
 
-	if inner == nil {
-		return nil, fmt.Errorf("mcp: client %q not connected", c.name)
+// HasResources reports whether the server offers resources.
+func (c *Client) HasResources() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.hasResources
+}
+
+// CallTool invokes a tool on the connected MCP server.
+func (c *Client) CallTool(ctx context.Context, name string, args map[string]any) (*mcp.CallToolResult, error) {
+	inner, err := c.session()
+	if err != nil {
+		return nil, err
 	}
 
 	req := mcp.CallToolRequest{}
 	req.Params.Name = name
 	req.Params.Arguments = args
 
-	return inner.CallTool(ctx, req)
+	res, err := inner.CallTool(ctx, req)
+	c.checkError(inner, err)
+	return res, err
 }
 
-// Close shuts down the MCP client connection.
+// ListResources returns the server's resources and resource templates.
+func (c *Client) ListResources(ctx context.Context) ([]mcp.Resource, []mcp.ResourceTemplate, error) {
+	inner, err := c.session()
+	if err != nil {
+		return nil, nil, err
+	}
+	res, err := inner.ListResources(ctx, mcp.ListResourcesRequest{})
+	if err != nil {
+		c.checkError(inner, err)
+		return nil, nil, err
+	}
+	// Templates are optional; servers without any may reject the request.
+	var templates []mcp.ResourceTemplate
+	if tpl, err := inner.ListResourceTemplates(ctx, mcp.ListResourceTemplatesRequest{}); err == nil {
+		templates = tpl.ResourceTemplates
+	}
+	return res.Resources, templates, nil
+}
+
+// ReadResource reads the resource with the given URI.
+func (c *Client) ReadResource(ctx context.Context, uri string) (*mcp.ReadResourceResult, error) {
+	inner, err := c.session()
+	if err != nil {
+		return nil, err
+	}
+	req := mcp.ReadResourceRequest{}
+	req.Params.URI = uri
+	res, err := inner.ReadResource(ctx, req)
+	c.checkError(inner, err)
+	return res, err
+}
+
+// GetPrompt renders the prompt with the given name and arguments.
+func (c *Client) GetPrompt(ctx context.Context, name string, args map[string]string) (*mcp.GetPromptResult, error) {
+	inner, err := c.session()
+	if err != nil {
+		return nil, err
+	}
+	req := mcp.GetPromptRequest{}
+	req.Params.Name = name
+	req.Params.Arguments = args
+	res, err := inner.GetPrompt(ctx, req)
+	c.checkError(inner, err)
+	return res, err
+}
+
+// Close shuts down the MCP client connection and stops reconnecting.
 func (c *Client) Close() error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	if !c.closed {
+		c.closed = true
+		close(c.stop)
+	}
 	if c.inner == nil {
 		return nil
 	}
@@ -144,5 +449,6 @@ func (c *Client) Close() error {
 	c.inner = nil
 	c.connected = false
 	c.tools = nil
+	c.prompts = nil
 	return err
 }
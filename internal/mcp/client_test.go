@@ -0,0 +1,290 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/flemzord/sclaw/internal/tool"
+	gomcp "github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// testServerEnv makes the test binary run as a stdio MCP server.
+const testServerEnv = "SCLAW_MCP_TEST_SERVER"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(testServerEnv) == "1" {
+		runTestServer()
+		return
+	}
+	os.Exit(m.Run())
+}
+
+// runTestServer serves tools, a resource and a prompt over stdio.
+func runTestServer() {
+	s := server.NewMCPServer("test", "1.0.0",
+		server.WithToolCapabilities(true),
+		server.WithResourceCapabilities(false, true),
+		server.WithPromptCapabilities(true),
+	)
+	textResult := func(text string) *gomcp.CallToolResult {
+		return &gomcp.CallToolResult{Content: []gomcp.Content{gomcp.NewTextContent(text)}}
+	}
+	s.AddTool(gomcp.NewTool("echo", gomcp.WithString("text")),
+		func(_ context.Context, req gomcp.CallToolRequest) (*gomcp.CallToolResult, error) {
+			return textResult(req.GetString("text", "")), nil
+		})
+	s.AddTool(gomcp.NewTool("hidden"),
+		func(context.Context, gomcp.CallToolRequest) (*gomcp.CallToolResult, error) {
+			return textResult("hidden"), nil
+		})
+	s.AddTool(gomcp.NewTool("crash"),
+		func(context.Context, gomcp.CallToolRequest) (*gomcp.CallToolResult, error) {
+			os.Exit(3)
+			return nil, nil
+		})
+	s.AddTool(gomcp.NewTool("add_tool"),
+		func(context.Context, gomcp.CallToolRequest) (*gomcp.CallToolResult, error) {
+			s.AddTool(gomcp.NewTool("new_tool"),
+				func(context.Context, gomcp.CallToolRequest) (*gomcp.CallToolResult, error) {
+					return textResult("new"), nil
+				})
+			return textResult("added"), nil
+		})
+	s.AddResource(gomcp.NewResource("memo://greeting", "greeting", gomcp.WithMIMEType("text/plain")),
+		func(context.Context, gomcp.ReadResourceRequest) ([]gomcp.ResourceContents, error) {
+			return []gomcp.ResourceContents{
+				gomcp.TextResourceContents{URI: "memo://greeting", MIMEType: "text/plain", Text: "hello"},
+			}, nil
+		})
+	s.AddPrompt(gomcp.NewPrompt("review",
+		gomcp.WithPromptDescription("Review code."),
+		gomcp.WithArgument("lang", gomcp.RequiredArgument()),
+	), func(_ context.Context, req gomcp.GetPromptRequest) (*gomcp.GetPromptResult, error) {
+		return gomcp.NewGetPromptResult("review", []gomcp.PromptMessage{
+			gomcp.NewPromptMessage(gomcp.RoleUser, gomcp.NewTextContent("Review this "+req.Params.Arguments["lang"]+" code.")),
+		}), nil
+	})
+	if err := server.ServeStdio(s); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// writeTestConfig writes an mcp.json that runs the test server.
+func writeTestConfig(t *testing.T, srv ServerConfig) string {
+	t.Helper()
+	srv.Command = os.Args[0]
+	srv.Env = []string{testServerEnv + "=1"}
+	data, err := json.Marshal(Config{Servers: map[string]ServerConfig{"test": srv}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "mcp.json"), data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func toolNames(tools []tool.Tool) []string {
+	names := make([]string, len(tools))
+	for i, t := range tools {
+		names[i] = t.Name()
+	}
+	slices.Sort(names)
+	return names
+}
+
+func findTool(tools []tool.Tool, name string) tool.Tool {
+	for _, t := range tools {
+		if t.Name() == name {
+			return t
+		}
+	}
+	return nil
+}
+
+func execute(t *testing.T, tl tool.Tool, args string) (tool.Output, error) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return tl.Execute(ctx, json.RawMessage(args), tool.ExecutionEnv{})
+}
+
+// waitFor polls cond until it holds or the deadline passes.
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(15 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+func TestResolver_StdioServer(t *testing.T) {
+	dir := writeTestConfig(t, ServerConfig{Policy: "ask", Tools: []string{"echo", "crash", "add_*", "new_*"}})
+	r := NewResolver(slog.Default())
+	defer func() { _ = r.Close() }()
+	ctx := context.Background()
+
+	tools := r.ResolveTools(ctx, "agent1", dir)
+	want := []string{"mcp_test_add_tool", "mcp_test_crash", "mcp_test_echo", "mcp_test_get_prompt", "mcp_test_read_resource"}
+	if got := toolNames(tools); !slices.Equal(got, want) {
+		t.Fatalf("tools = %v, want %v", got, want)
+	}
+	for _, tl := range tools {
+		if tl.DefaultPolicy() != tool.ApprovalAsk {
+			t.Errorf("%s policy = %q, want ask", tl.Name(), tl.DefaultPolicy())
+		}
+	}
+
+	out, err := execute(t, findTool(tools, "mcp_test_echo"), `{"text":"hi"}`)
+	if err != nil || out.Content != "hi" {
+		t.Fatalf("echo = %+v, %v", out, err)
+	}
+
+	t.Run("resources", func(t *testing.T) {
+		rt := findTool(tools, "mcp_test_read_resource")
+		out, err := execute(t, rt, `{}`)
+		if err != nil || !strings.Contains(out.Content, "memo://greeting (greeting) [text/plain]") {
+			t.Fatalf("list = %+v, %v", out, err)
+		}
+		out, err = execute(t, rt, `{"uri":"memo://greeting"}`)
+		if err != nil || out.Content != "hello" {
+			t.Fatalf("read = %+v, %v", out, err)
+		}
+	})
+
+	t.Run("prompts", func(t *testing.T) {
+		skills := r.ResolveSkills(ctx, "agent1", dir)
+		if len(skills) != 1 || skills[0].Meta.Name != "review" || skills[0].Tool != "mcp_test_get_prompt" {
+			t.Fatalf("skills = %+v", skills)
+		}
+		if !strings.Contains(skills[0].Meta.Description, "lang (required)") {
+			t.Errorf("description = %q", skills[0].Meta.Description)
+		}
+		out, err := execute(t, findTool(tools, "mcp_test_get_prompt"), `{"name":"review","arguments":{"lang":"Go"}}`)
+		if err != nil || out.Content != "[user]\nReview this Go code." {
+			t.Fatalf("prompt = %+v, %v", out, err)
+		}
+	})
+
+	t.Run("list changed", func(t *testing.T) {
+		if _, err := execute(t, findTool(tools, "mcp_test_add_tool"), `{}`); err != nil {
+			t.Fatal(err)
+		}
+		waitFor(t, "new tool", func() bool {
+			return findTool(r.ResolveTools(ctx, "agent1", dir), "mcp_test_new_tool") != nil
+		})
+	})
+
+	t.Run("reconnect", func(t *testing.T) {
+		if _, err := execute(t, findTool(tools, "mcp_test_crash"), `{}`); err == nil {
+			t.Fatal("expected error from crashed server")
+		}
+		waitFor(t, "reconnection", func() bool {
+			echo := findTool(r.ResolveTools(ctx, "agent1", dir), "mcp_test_echo")
+			if echo == nil {
+				return false
+			}
+			out, err := execute(t, echo, `{"text":"back"}`)
+			return err == nil && out.Content == "back"
+		})
+	})
+}
+
+func TestResolver_DenyServerHidesTools(t *testing.T) {
+	dir := writeTestConfig(t, ServerConfig{Policy: "deny"})
+	r := NewResolver(slog.Default())
+	defer func() { _ = r.Close() }()
+	ctx := context.Background()
+
+	if tools := r.ResolveTools(ctx, "agent1", dir); len(tools) != 0 {
+		t.Errorf("tools = %v, want none", toolNames(tools))
+	}
+	if skills := r.ResolveSkills(ctx, "agent1", dir); len(skills) != 0 {
+		t.Errorf("skills = %+v, want none", skills)
+	}
+}
+
+func TestResolver_UnavailableServerRetries(t *testing.T) {
+	dir := t.TempDir()
+	data := `{"mcpServers": {"gone": {"command": "/nonexistent/mcp-server"}}}`
+	if err := os.WriteFile(filepath.Join(dir, "mcp.json"), []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	r := NewResolver(slog.Default())
+	defer func() { _ = r.Close() }()
+
+	if tools := r.ResolveTools(context.Background(), "agent1", dir); len(tools) != 0 {
+		t.Fatalf("tools = %v", toolNames(tools))
+	}
+	clients := r.agents["agent1"].clients
+	if len(clients) != 1 {
+		t.Fatalf("clients = %d, want the unavailable server kept", len(clients))
+	}
+	_, err := clients[0].CallTool(context.Background(), "x", nil)
+	if err == nil || !strings.Contains(err.Error(), "reconnecting") {
+		t.Errorf("CallTool error = %v", err)
+	}
+}
+
+func TestServerConfig_AllowsTool(t *testing.T) {
+	cfg := ServerConfig{Tools: []string{"read_*", "list"}}
+	for name, want := range map[string]bool{
+		"read_file": true,
+		"list":      true,
+		"list_all":  false,
+		"write":     false,
+	} {
+		if got := cfg.AllowsTool(name); got != want {
+			t.Errorf("AllowsTool(%q) = %v, want %v", name, got, want)
+		}
+	}
+	if !(ServerConfig{}).AllowsTool("anything") {
+		t.Error("empty allowlist should allow every tool")
+	}
+}
+
+func TestServerConfig_ApprovalLevel(t *testing.T) {
+	if got := (ServerConfig{}).ApprovalLevel(); got != tool.ApprovalAllow {
+		t.Errorf("default = %q", got)
+	}
+	if got := (ServerConfig{Policy: "deny"}).ApprovalLevel(); got != tool.ApprovalDeny {
+		t.Errorf("deny = %q", got)
+	}
+	if got := NewTool("fs", gomcp.Tool{Name: "x"}, NewClient("fs", ServerConfig{Policy: "ask"}, slog.Default())).DefaultPolicy(); got != tool.ApprovalAsk {
+		t.Errorf("tool policy = %q", got)
+	}
+	floor, ok := NewTool("fs", gomcp.Tool{Name: "x"}, NewClient("fs", ServerConfig{Policy: "ask"}, slog.Default())).(tool.PolicyFloor)
+	if !ok || floor.PolicyFloor() != tool.ApprovalAsk {
+		t.Errorf("tool floor = %v, %v", floor, ok)
+	}
+}
+
+func TestIsConnectionError(t *testing.T) {
+	if isConnectionError(nil) || isConnectionError(context.Canceled) || isConnectionError(fmt.Errorf("tool failed")) {
+		t.Error("request errors must not count as connection errors")
+	}
+}
+
+func TestFormatResourceContents_Blob(t *testing.T) {
+	got := formatResourceContents([]gomcp.ResourceContents{
+		gomcp.TextResourceContents{Text: "a"},
+		gomcp.BlobResourceContents{MIMEType: "image/png", Blob: "iVBORw0KGgo="},
+	})
+	if got != "a\n[binary image/png, 8 bytes]" {
+		t.Errorf("got %q", got)
+	}
+}
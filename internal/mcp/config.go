@@ -1,6 +1,7 @@
 // Package mcp provides per-agent MCP (Model Context Protocol) server integration.
 // Each agent can define an mcp.json in its DataDir to connect to external MCP
-// servers, exposing their tools as native sclaw tools.
+// servers, exposing their tools as native sclaw tools, their resources through
+// a read tool and their prompts as skills.
 package mcp
 
 import (
@@ -8,9 +9,12 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
 	"regexp"
 	"strings"
+
+	"github.com/flemzord/sclaw/internal/tool"
 )
 
 // Config represents the top-level mcp.json configuration.
@@ -33,6 +37,15 @@ type ServerConfig struct {
 	URL string `json:"url,omitempty"`
 	// Headers are HTTP headers sent with every request.
 	Headers map[string]string `json:"headers,omitempty"`
+
+	// Policy is the minimum approval level of the server's tools: "allow",
+	// "ask", or "deny". The agent's policy can only make it stricter: "ask"
+	// tools always need an approval and "deny" servers expose no tool.
+	// Defaults to "allow".
+	Policy string `json:"policy,omitempty"`
+	// Tools, if non-empty, lists the server tools exposed to the agent.
+	// Entries are tool names or glob patterns such as "get_*".
+	Tools []string `json:"tools,omitempty"`
 }
 
 // IsStdio returns true if this server uses stdio transport.
@@ -41,6 +54,45 @@ func (s ServerConfig) IsStdio() bool { return s.Command != "" }
 // IsHTTP returns true if this server uses HTTP transport.
 func (s ServerConfig) IsHTTP() bool { return s.URL != "" }
 
+// ApprovalLevel returns the minimum approval level of the server's tools.
+func (s ServerConfig) ApprovalLevel() tool.ApprovalLevel {
+	if s.Policy == "" {
+		return tool.ApprovalAllow
+	}
+	return tool.ApprovalLevel(s.Policy)
+}
+
+// AllowsTool reports whether the server tool with the given name is exposed.
+func (s ServerConfig) AllowsTool(name string) bool {
+	if len(s.Tools) == 0 {
+		return true
+	}
+	for _, pattern := range s.Tools {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// validate checks a server entry.
+func (s ServerConfig) validate(name string) error {
+	if (s.Command != "") == (s.URL != "") {
+		return fmt.Errorf("mcp: server %q must have exactly one of 'command' or 'url'", name)
+	}
+	switch s.Policy {
+	case "", string(tool.ApprovalAllow), string(tool.ApprovalAsk), string(tool.ApprovalDeny):
+	default:
+		return fmt.Errorf("mcp: server %q has invalid policy %q (must be allow, ask, or deny)", name, s.Policy)
+	}
+	for _, pattern := range s.Tools {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("mcp: server %q has invalid tool pattern %q", name, pattern)
+		}
+	}
+	return nil
+}
+
 // envVarPattern matches ${VAR} and ${VAR:-default} patterns.
 var envVarPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(?::-((?:[^}\\]|\\.)*))?}`)
 
@@ -87,10 +139,8 @@ func LoadConfig(dataDir string) (*Config, error) {
 
 	// Validate each server entry.
 	for name, srv := range cfg.Servers {
-		hasCommand := srv.Command != ""
-		hasURL := srv.URL != ""
-		if hasCommand == hasURL {
-			return nil, fmt.Errorf("mcp: server %q must have exactly one of 'command' or 'url'", name)
+		if err := srv.validate(name); err != nil {
+			return nil, err
 		}
 	}
 
@@ -224,3 +224,38 @@ func TestExpandEnvVars(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadConfig_PolicyAndTools(t *testing.T) {
+	tests := []struct {
+		name    string
+		server  string
+		wantErr bool
+	}{
+		{"valid", `{"command": "x", "policy": "ask", "tools": ["read_*", "list"]}`, false},
+		{"invalid policy", `{"command": "x", "policy": "maybe"}`, true},
+		{"invalid pattern", `{"command": "x", "tools": ["[read"]}`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			data := `{"mcpServers": {"srv": ` + tt.server + `}}`
+			if err := os.WriteFile(filepath.Join(dir, "mcp.json"), []byte(data), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			cfg, err := LoadConfig(dir)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			srv := cfg.Servers["srv"]
+			if srv.Policy != "ask" || len(srv.Tools) != 2 {
+				t.Errorf("server = %+v", srv)
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/flemzord/sclaw/internal/tool"
+	"github.com/flemzord/sclaw/internal/workspace"
+	gomcp "github.com/mark3labs/mcp-go/mcp"
+)
+
+// getPromptTool renders the prompts of an MCP server.
+type getPromptTool struct {
+	serverName string
+	client     *Client
+}
+
+// NewPromptTool creates the mcp_{server}_get_prompt tool for a server that
+// offers prompts.
+func NewPromptTool(serverName string, client *Client) tool.Tool {
+	return &getPromptTool{serverName: serverName, client: client}
+}
+
+func (t *getPromptTool) Name() string { return ToolName(t.serverName, "get_prompt") }
+
+func (t *getPromptTool) Description() string {
+	return fmt.Sprintf("Get a prompt from the MCP server %s, filled in with its arguments. "+
+		"The prompts are listed as skills.", t.serverName)
+}
+
+func (t *getPromptTool) Scopes() []tool.Scope { return []tool.Scope{tool.ScopeNetwork} }
+
+func (t *getPromptTool) DefaultPolicy() tool.ApprovalLevel { return serverPolicy(t.client) }
+
+func (t *getPromptTool) PolicyFloor() tool.ApprovalLevel { return serverPolicy(t.client) }
+
+func (t *getPromptTool) Schema() json.RawMessage {
+	return json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"name": {"type": "string", "description": "Name of the prompt."},
+			"arguments": {"type": "object", "additionalProperties": {"type": "string"}, "description": "Prompt arguments."}
+		},
+		"required": ["name"]
+	}`)
+}
+
+type getPromptArgs struct {
+	Name      string            `json:"name"`
+	Arguments map[string]string `json:"arguments,omitempty"`
+}
+
+func (t *getPromptTool) Execute(ctx context.Context, args json.RawMessage, _ tool.ExecutionEnv) (tool.Output, error) {
+	var a getPromptArgs
+	if err := json.Unmarshal(args, &a); err != nil {
+		return tool.Output{Content: fmt.Sprintf("invalid arguments: %v", err), IsError: true}, nil
+	}
+	if a.Name == "" {
+		return tool.Output{Content: "name is required", IsError: true}, nil
+	}
+
+	result, err := t.client.GetPrompt(ctx, a.Name, a.Arguments)
+	if err != nil {
+		return tool.Output{}, fmt.Errorf("mcp: getting prompt %s from %s: %w", a.Name, t.serverName, err)
+	}
+	return tool.Output{Content: formatPrompt(result)}, nil
+}
+
+// formatPrompt renders the prompt messages as role-labelled text.
+func formatPrompt(result *gomcp.GetPromptResult) string {
+	var parts []string
+	for _, m := range result.Messages {
+		var text string
+		switch c := m.Content.(type) {
+		case gomcp.TextContent:
+			text = c.Text
+		case gomcp.EmbeddedResource:
+			text = formatResourceContents([]gomcp.ResourceContents{c.Resource})
+		default:
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("[%s]\n%s", m.Role, text))
+	}
+	if len(parts) == 0 {
+		return "(empty prompt)"
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// PromptSkills returns the server's prompts as skills used through its
+// get_prompt tool.
+func PromptSkills(serverName string, prompts []gomcp.Prompt) []workspace.Skill {
+	if len(prompts) == 0 {
+		return nil
+	}
+	toolName := ToolName(serverName, "get_prompt")
+	skills := make([]workspace.Skill, 0, len(prompts))
+	for _, p := range prompts {
+		skills = append(skills, workspace.Skill{
+			Meta: workspace.SkillMeta{
+				Name:          p.Name,
+				Description:   promptDescription(serverName, p),
+				ToolsRequired: []string{toolName},
+				Trigger:       workspace.TriggerAlways,
+			},
+			Tool: toolName,
+		})
+	}
+	return skills
+}
+
+// promptDescription describes a prompt and its arguments.
+func promptDescription(serverName string, p gomcp.Prompt) string {
+	desc := p.Description
+	if desc == "" {
+		desc = fmt.Sprintf("Prompt %s from MCP server %s.", p.Name, serverName)
+	}
+	if len(p.Arguments) == 0 {
+		return desc
+	}
+	args := make([]string, len(p.Arguments))
+	for i, arg := range p.Arguments {
+		args[i] = arg.Name
+		if arg.Required {
+			args[i] += " (required)"
+		}
+		if arg.Description != "" {
+			args[i] += ": " + arg.Description
+		}
+	}
+	return desc + " Arguments: " + strings.Join(args, "; ")
+}
+
+// Compile-time checks that getPromptTool implements tool.Tool and
+// tool.PolicyFloor.
+var (
+	_ tool.Tool        = (*getPromptTool)(nil)
+	_ tool.PolicyFloor = (*getPromptTool)(nil)
+)
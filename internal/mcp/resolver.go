@@ -6,17 +6,19 @@ import (
 	"sync"
 
 	"github.com/flemzord/sclaw/internal/tool"
+	"github.com/flemzord/sclaw/internal/workspace"
 )
 
-// resolvedAgent holds cached MCP clients and tools for a single agent.
+// resolvedAgent holds the MCP clients of a single agent.
 // A nil value for clients indicates the agent has no mcp.json.
 type resolvedAgent struct {
 	clients []*Client
-	tools   []tool.Tool
 }
 
-// Resolver manages per-agent MCP client lifecycles and caches resolved tools.
-// It lazily connects to MCP servers on first access and caches the result.
+// Resolver manages per-agent MCP client lifecycles. It lazily connects to
+// MCP servers on first access and keeps the clients; the tools and skills
+// are built from their current lists on every call, so list changes and
+// reconnections take effect on the next message.
 type Resolver struct {
 	logger *slog.Logger
 
@@ -32,11 +34,62 @@ func NewResolver(logger *slog.Logger) *Resolver {
 	}
 }
 
-// ResolveTools returns the MCP tools available for the given agent.
-// Results are cached per agentID. Returns nil if the agent has no mcp.json.
-// Failed server connections are logged and skipped — the agent continues
-// with whatever tools could be resolved.
+// ResolveTools returns the MCP tools available for the given agent: the
+// server tools allowed by mcp.json, plus a read_resource tool for servers
+// with resources and a get_prompt tool for servers with prompts.
+// Returns nil if the agent has no mcp.json. Servers with the "deny" policy
+// and servers that are unavailable contribute no tools, the latter until
+// they reconnect.
 func (r *Resolver) ResolveTools(ctx context.Context, agentID, dataDir string) []tool.Tool {
+	var tools []tool.Tool
+	for _, c := range r.clients(ctx, agentID, dataDir) {
+		cfg := c.Config()
+		if cfg.ApprovalLevel() == tool.ApprovalDeny {
+			// Never shown to the model, so no agent policy can allow them.
+			continue
+		}
+		seen := make(map[string]bool)
+		for _, mt := range c.Tools() {
+			if !cfg.AllowsTool(mt.Name) {
+				continue
+			}
+			t := NewTool(c.Name(), mt, c)
+			seen[t.Name()] = true
+			tools = append(tools, t)
+		}
+		// Server tools win over the generated tools on name clashes.
+		if c.HasResources() {
+			if t := NewResourceTool(c.Name(), c); !seen[t.Name()] {
+				tools = append(tools, t)
+			}
+		}
+		if len(c.Prompts()) > 0 {
+			if t := NewPromptTool(c.Name(), c); !seen[t.Name()] {
+				tools = append(tools, t)
+			}
+		}
+	}
+	return tools
+}
+
+// ResolveSkills returns the prompts of the agent's MCP servers as skills.
+// Servers with the "deny" policy contribute no skills, since their
+// get_prompt tool is not resolved.
+func (r *Resolver) ResolveSkills(ctx context.Context, agentID, dataDir string) []workspace.Skill {
+	var skills []workspace.Skill
+	for _, c := range r.clients(ctx, agentID, dataDir) {
+		if c.Config().ApprovalLevel() == tool.ApprovalDeny {
+			continue
+		}
+		skills = append(skills, PromptSkills(c.Name(), c.Prompts())...)
+	}
+	return skills
+}
+
+// clients returns the MCP clients of the given agent, connecting them on
+// first access. Results are cached per agentID. Failed server connections
+// are logged and retried in the background.
+func (r *Resolver) clients(ctx context.Context, agentID, dataDir string) []*Client {
 	if dataDir == "" {
 		return nil
 	}
@@ -45,7 +98,7 @@ func (r *Resolver) ResolveTools(ctx context.Context, agentID, dataDir string) []
 	r.mu.RLock()
 	if cached, ok := r.agents[agentID]; ok {
 		r.mu.RUnlock()
-		return cached.tools
+		return cached.clients
 	}
 	r.mu.RUnlock()
 
@@ -54,7 +107,7 @@ func (r *Resolver) ResolveTools(ctx context.Context, agentID, dataDir string) []
 	defer r.mu.Unlock()
 
 	if cached, ok := r.agents[agentID]; ok {
-		return cached.tools
+		return cached.clients
 	}
 
 	cfg, err := LoadConfig(dataDir)
@@ -74,34 +127,25 @@ func (r *Resolver) ResolveTools(ctx context.Context, agentID, dataDir string) []
 
 	var (
 		clients []*Client
-		tools   []tool.Tool
+		tools   int
 	)
-
 	for name, serverCfg := range cfg.Servers {
 		c := NewClient(name, serverCfg, r.logger)
 		if err := c.Connect(ctx); err != nil {
-			r.logger.Warn("mcp: failed to connect to server",
+			r.logger.Warn("mcp: failed to connect to server, retrying in background",
 				"agent", agentID, "server", name, "error", err)
-			continue
+			c.Reconnect()
 		}
-
 		clients = append(clients, c)
-		for _, mcpTool := range c.Tools() {
-			tools = append(tools, NewTool(name, mcpTool, c))
-		}
+		tools += len(c.Tools())
 	}
 
-	r.agents[agentID] = &resolvedAgent{
-		clients: clients,
-		tools:   tools,
-	}
+	r.agents[agentID] = &resolvedAgent{clients: clients}
 
-	if len(tools) > 0 {
-		r.logger.Info("mcp: resolved tools for agent",
-			"agent", agentID, "tools", len(tools), "servers", len(clients))
-	}
+	r.logger.Info("mcp: resolved servers for agent",
+		"agent", agentID, "servers", len(clients), "tools", tools)
 
-	return tools
+	return clients
 }
 
 // InvalidateAgent closes all MCP clients for the given agent and removes
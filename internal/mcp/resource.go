@@ -0,0 +1,149 @@
+package mcp
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/flemzord/sclaw/internal/tool"
+	gomcp "github.com/mark3labs/mcp-go/mcp"
+)
+
+// readResourceTool lists and reads the resources of an MCP server.
+type readResourceTool struct {
+	serverName string
+	client     *Client
+}
+
+// NewResourceTool creates the mcp_{server}_read_resource tool for a server
+// that offers resources.
+func NewResourceTool(serverName string, client *Client) tool.Tool {
+	return &readResourceTool{serverName: serverName, client: client}
+}
+
+func (t *readResourceTool) Name() string { return ToolName(t.serverName, "read_resource") }
+
+func (t *readResourceTool) Description() string {
+	return fmt.Sprintf("Read a resource from the MCP server %s. "+
+		"Call without uri to list the available resources and URI templates.", t.serverName)
+}
+
+func (t *readResourceTool) Scopes() []tool.Scope { return []tool.Scope{tool.ScopeNetwork} }
+
+func (t *readResourceTool) DefaultPolicy() tool.ApprovalLevel { return serverPolicy(t.client) }
+
+func (t *readResourceTool) PolicyFloor() tool.ApprovalLevel { return serverPolicy(t.client) }
+
+func (t *readResourceTool) Schema() json.RawMessage {
+	return json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"uri": {"type": "string", "description": "URI of the resource to read. Omit to list resources."}
+		}
+	}`)
+}
+
+type readResourceArgs struct {
+	URI string `json:"uri,omitempty"`
+}
+
+func (t *readResourceTool) Execute(ctx context.Context, args json.RawMessage, _ tool.ExecutionEnv) (tool.Output, error) {
+	var a readResourceArgs
+	if len(args) > 0 {
+		if err := json.Unmarshal(args, &a); err != nil {
+			return tool.Output{Content: fmt.Sprintf("invalid arguments: %v", err), IsError: true}, nil
+		}
+	}
+
+	if a.URI == "" {
+		resources, templates, err := t.client.ListResources(ctx)
+		if err != nil {
+			return tool.Output{}, fmt.Errorf("mcp: listing resources of %s: %w", t.serverName, err)
+		}
+		return tool.Output{Content: formatResourceList(resources, templates)}, nil
+	}
+
+	result, err := t.client.ReadResource(ctx, a.URI)
+	if err != nil {
+		return tool.Output{}, fmt.Errorf("mcp: reading %s from %s: %w", a.URI, t.serverName, err)
+	}
+	return tool.Output{Content: formatResourceContents(result.Contents)}, nil
+}
+
+// formatResourceList renders resources and templates one per line.
+func formatResourceList(resources []gomcp.Resource, templates []gomcp.ResourceTemplate) string {
+	if len(resources) == 0 && len(templates) == 0 {
+		return "no resources"
+	}
+	var b strings.Builder
+	if len(resources) > 0 {
+		b.WriteString("Resources:\n")
+		for _, r := range resources {
+			writeResourceLine(&b, r.URI, r.Name, r.Description, r.MIMEType)
+		}
+	}
+	if len(templates) > 0 {
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString("URI templates:\n")
+		for _, tpl := range templates {
+			var uri string
+			if tpl.URITemplate != nil && tpl.URITemplate.Template != nil {
+				uri = tpl.URITemplate.Raw()
+			}
+			writeResourceLine(&b, uri, tpl.Name, tpl.Description, tpl.MIMEType)
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func writeResourceLine(b *strings.Builder, uri, name, desc, mimeType string) {
+	fmt.Fprintf(b, "- %s", uri)
+	if name != "" && name != uri {
+		fmt.Fprintf(b, " (%s)", name)
+	}
+	if mimeType != "" {
+		fmt.Fprintf(b, " [%s]", mimeType)
+	}
+	if desc != "" {
+		fmt.Fprintf(b, ": %s", desc)
+	}
+	b.WriteString("\n")
+}
+
+// formatResourceContents returns the text of the contents; binary contents
+// are summarised.
+func formatResourceContents(contents []gomcp.ResourceContents) string {
+	parts := make([]string, 0, len(contents))
+	for _, c := range contents {
+		switch rc := c.(type) {
+		case gomcp.TextResourceContents:
+			parts = append(parts, rc.Text)
+		case gomcp.BlobResourceContents:
+			parts = append(parts, describeBlob(rc.MIMEType, rc.Blob))
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// describeBlob summarises base64-encoded binary content.
+func describeBlob(mimeType, blob string) string {
+	if mimeType == "" {
+		mimeType = "application/octet-stream"
+	}
+	size := base64.StdEncoding.DecodedLen(len(blob))
+	if data, err := base64.StdEncoding.DecodeString(blob); err == nil {
+		size = len(data)
+	}
+	return fmt.Sprintf("[binary %s, %d bytes]", mimeType, size)
+}
+
+// Compile-time checks that readResourceTool implements tool.Tool and
+// tool.PolicyFloor.
+var (
+	_ tool.Tool        = (*readResourceTool)(nil)
+	_ tool.PolicyFloor = (*readResourceTool)(nil)
+)
@@ -0,0 +1,262 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/flemzord/sclaw/internal/memory"
+	"github.com/flemzord/sclaw/internal/provider"
+	"github.com/flemzord/sclaw/internal/tool"
+	gomcp "github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Resource URIs published by the server.
+const (
+	historyURI         = "sclaw://history"
+	historyTemplate    = "sclaw://history/{session}"
+	memorySearchPrefix = "sclaw://memory/search/"
+	memoryTemplate     = memorySearchPrefix + "{query}"
+)
+
+// memorySearchLimit caps the facts returned by a memory search.
+const memorySearchLimit = 20
+
+// ServedAgent is an agent published as an MCP server.
+type ServedAgent struct {
+	ID string
+
+	// Tools are executed through the registry, so the policy, rate limits,
+	// sandbox and audit log apply as for chat messages.
+	Tools           *tool.Registry
+	Policy          tool.PolicyConfig
+	Requester       tool.ApprovalRequester // nil refuses tools needing approval
+	ApprovalTimeout time.Duration
+
+	// Env returns the execution environment of a call to the named tool.
+	Env func(toolName string) tool.ExecutionEnv
+
+	Facts   memory.Store        // nil when memory is disabled
+	History memory.HistoryStore // nil when memory is disabled
+}
+
+// AgentResolver returns the agent with the given ID.
+type AgentResolver func(agentID string) (*ServedAgent, error)
+
+// Publisher exposes agents as MCP servers.
+type Publisher struct {
+	resolve AgentResolver
+	logger  *slog.Logger
+}
+
+// NewPublisher creates a publisher resolving agents with resolve.
+func NewPublisher(resolve AgentResolver, logger *slog.Logger) *Publisher {
+	return &Publisher{resolve: resolve, logger: logger}
+}
+
+// Server builds an MCP server publishing the agent's tools and memory.
+func (p *Publisher) Server(agentID string) (*server.MCPServer, error) {
+	agent, err := p.resolve(agentID)
+	if err != nil {
+		return nil, err
+	}
+	return p.newServer(agent), nil
+}
+
+// Handler returns a Streamable HTTP handler for the agent. The server is
+// stateless, so a handler built per request always reflects the current
+// configuration.
+func (p *Publisher) Handler(agentID string) (http.Handler, error) {
+	s, err := p.Server(agentID)
+	if err != nil {
+		return nil, err
+	}
+	return server.NewStreamableHTTPServer(s, server.WithStateLess(true)), nil
+}
+
+func (p *Publisher) newServer(agent *ServedAgent) *server.MCPServer {
+	s := server.NewMCPServer("sclaw", "1.0.0",
+		server.WithToolCapabilities(false),
+		server.WithResourceCapabilities(false, false),
+	)
+
+	if agent.Tools != nil {
+		for _, name := range agent.Tools.Names() {
+			t, err := agent.Tools.Get(name)
+			if err != nil {
+				continue
+			}
+			s.AddTool(gomcp.NewToolWithRawSchema(name, t.Description(), t.Schema()), p.callTool(agent, name))
+		}
+	}
+
+	if agent.History != nil {
+		if lister, ok := agent.History.(memory.SessionLister); ok {
+			s.AddResource(gomcp.NewResource(historyURI, "conversations",
+				gomcp.WithResourceDescription("Conversations of the agent, most recent first."),
+				gomcp.WithMIMEType("text/plain"),
+			), func(context.Context, gomcp.ReadResourceRequest) ([]gomcp.ResourceContents, error) {
+				sessions, err := lister.Sessions()
+				if err != nil {
+					return nil, fmt.Errorf("listing conversations: %w", err)
+				}
+				return textContents(historyURI, formatSessions(sessions)), nil
+			})
+		}
+		s.AddResourceTemplate(gomcp.NewResourceTemplate(historyTemplate, "conversation",
+			gomcp.WithTemplateDescription("Messages of a conversation."),
+			gomcp.WithTemplateMIMEType("text/plain"),
+		), func(_ context.Context, req gomcp.ReadResourceRequest) ([]gomcp.ResourceContents, error) {
+			session, err := uriParam(req.Params.URI, historyURI+"/")
+			if err != nil {
+				return nil, err
+			}
+			msgs, err := agent.History.GetAll(session)
+			if err != nil {
+				return nil, fmt.Errorf("reading conversation %s: %w", session, err)
+			}
+			return textContents(req.Params.URI, formatMessages(msgs)), nil
+		})
+	}
+
+	if agent.Facts != nil {
+		s.AddResourceTemplate(gomcp.NewResourceTemplate(memoryTemplate, "memory search",
+			gomcp.WithTemplateDescription("Facts remembered by the agent matching a query."),
+			gomcp.WithTemplateMIMEType("text/plain"),
+		), func(ctx context.Context, req gomcp.ReadResourceRequest) ([]gomcp.ResourceContents, error) {
+			query, err := uriParam(req.Params.URI, memorySearchPrefix)
+			if err != nil {
+				return nil, err
+			}
+			facts, err := agent.Facts.Search(ctx, query, memorySearchLimit)
+			if err != nil {
+				return nil, fmt.Errorf("searching memory: %w", err)
+			}
+			return textContents(req.Params.URI, formatFacts(facts)), nil
+		})
+	}
+
+	return s
+}
+
+// callTool executes a tool call through the agent's registry.
+func (p *Publisher) callTool(agent *ServedAgent, name string) server.ToolHandlerFunc {
+	return func(ctx context.Context, req gomcp.CallToolRequest) (*gomcp.CallToolResult, error) {
+		args := json.RawMessage(`{}`)
+		if req.Params.Arguments != nil {
+			data, err := json.Marshal(req.Params.Arguments)
+			if err != nil {
+				return gomcp.NewToolResultError(fmt.Sprintf("invalid arguments: %v", err)), nil
+			}
+			args = data
+		}
+
+		env := tool.ExecutionEnv{}
+		if agent.Env != nil {
+			env = agent.Env(name)
+		}
+		if env.SessionID == "" {
+			env.SessionID = "mcp:" + agent.ID
+		}
+		if env.SenderID == "" {
+			env.SenderID = "mcp"
+		}
+
+		// MCP clients act on behalf of the agent's owner, like a direct message.
+		out, err := agent.Tools.Execute(ctx, name, args, agent.Policy, tool.PolicyContextDM, nil,
+			agent.Requester, agent.ApprovalTimeout, env)
+		if err != nil {
+			p.logger.Info("mcp server: tool call failed", "agent", agent.ID, "tool", name, "error", err)
+			return gomcp.NewToolResultError(err.Error()), nil
+		}
+		result := gomcp.NewToolResultText(out.Content)
+		result.IsError = out.IsError
+		return result, nil
+	}
+}
+
+// escapeSegment escapes a URI template parameter. Session IDs contain
+// characters such as ':' that a template variable does not match unescaped.
+func escapeSegment(param string) string {
+	return strings.ReplaceAll(url.QueryEscape(param), "+", "%20")
+}
+
+// uriParam extracts the unescaped parameter following prefix.
+func uriParam(uri, prefix string) (string, error) {
+	param, ok := strings.CutPrefix(uri, prefix)
+	if !ok || param == "" {
+		return "", fmt.Errorf("invalid resource URI %q", uri)
+	}
+	unescaped, err := url.PathUnescape(param)
+	if err != nil {
+		return "", fmt.Errorf("invalid resource URI %q: %w", uri, err)
+	}
+	return unescaped, nil
+}
+
+func textContents(uri, text string) []gomcp.ResourceContents {
+	return []gomcp.ResourceContents{
+		gomcp.TextResourceContents{URI: uri, MIMEType: "text/plain", Text: text},
+	}
+}
+
+// formatSessions lists conversations with their resource URI.
+func formatSessions(sessions []memory.SessionInfo) string {
+	if len(sessions) == 0 {
+		return "no conversations"
+	}
+	var b strings.Builder
+	for _, s := range sessions {
+		fmt.Fprintf(&b, "- %s (%s): %d messages, last %s\n",
+			historyURI+"/"+escapeSegment(s.ID), s.ID, s.Messages, s.UpdatedAt.UTC().Format(time.RFC3339))
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// formatMessages renders messages as role-labelled text.
+func formatMessages(msgs []provider.LLMMessage) string {
+	if len(msgs) == 0 {
+		return "no messages"
+	}
+	parts := make([]string, 0, len(msgs))
+	for _, m := range msgs {
+		var b strings.Builder
+		fmt.Fprintf(&b, "[%s]", m.Role)
+		if m.Name != "" {
+			fmt.Fprintf(&b, " %s", m.Name)
+		}
+		if text := m.TextForDisplay(); text != "" {
+			b.WriteString("\n" + text)
+		}
+		for _, call := range m.ToolCalls {
+			fmt.Fprintf(&b, "\n-> %s %s", call.Name, call.Arguments)
+		}
+		parts = append(parts, b.String())
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// formatFacts lists facts one per line.
+func formatFacts(facts []memory.Fact) string {
+	if len(facts) == 0 {
+		return "no matching facts"
+	}
+	var b strings.Builder
+	for _, f := range facts {
+		fmt.Fprintf(&b, "- %s", f.Content)
+		if len(f.Tags) > 0 {
+			fmt.Fprintf(&b, " [%s]", strings.Join(f.Tags, ", "))
+		}
+		if !f.CreatedAt.IsZero() {
+			fmt.Fprintf(&b, " (%s)", f.CreatedAt.UTC().Format(time.DateOnly))
+		}
+		b.WriteString("\n")
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
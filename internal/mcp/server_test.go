@@ -0,0 +1,227 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/flemzord/sclaw/internal/memory"
+	"github.com/flemzord/sclaw/internal/provider"
+	"github.com/flemzord/sclaw/internal/tool"
+	"github.com/mark3labs/mcp-go/client"
+	gomcp "github.com/mark3labs/mcp-go/mcp"
+)
+
+// stubTool echoes its arguments.
+type stubTool struct {
+	name   string
+	policy tool.ApprovalLevel
+}
+
+func (t stubTool) Name() string                      { return t.name }
+func (t stubTool) Description() string               { return "stub " + t.name }
+func (t stubTool) Scopes() []tool.Scope              { return []tool.Scope{tool.ScopeReadOnly} }
+func (t stubTool) DefaultPolicy() tool.ApprovalLevel { return t.policy }
+func (t stubTool) Schema() json.RawMessage {
+	return json.RawMessage(`{"type":"object","properties":{"text":{"type":"string"}}}`)
+}
+
+func (t stubTool) Execute(_ context.Context, args json.RawMessage, env tool.ExecutionEnv) (tool.Output, error) {
+	return tool.Output{Content: t.name + " " + string(args) + " " + env.SessionID}, nil
+}
+
+// approveAll approves every request.
+type approveAll struct{ calls int }
+
+func (a *approveAll) RequestApproval(context.Context, tool.ApprovalRequest) (tool.ApprovalResponse, error) {
+	a.calls++
+	return tool.ApprovalResponse{Approved: true}, nil
+}
+
+func newServedAgent(t *testing.T) *ServedAgent {
+	t.Helper()
+	reg := tool.NewRegistry()
+	for _, st := range []stubTool{
+		{name: "echo", policy: tool.ApprovalAllow},
+		{name: "risky", policy: tool.ApprovalAsk},
+		{name: "forbidden", policy: tool.ApprovalDeny},
+	} {
+		if err := reg.Register(st); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	history := memory.NewInMemoryHistoryStore()
+	_ = history.Append("telegram:42:", provider.LLMMessage{Role: provider.MessageRoleUser, Content: "hello"})
+	_ = history.Append("telegram:42:", provider.LLMMessage{Role: provider.MessageRoleAssistant, Content: "hi there"})
+
+	facts := memory.NewInMemoryStore()
+	_ = facts.Index(context.Background(), memory.Fact{ID: "1", Content: "The user likes green tea", Tags: []string{"preference"}})
+
+	return &ServedAgent{ID: "main", Tools: reg, History: history, Facts: facts}
+}
+
+func connectInProcess(t *testing.T, p *Publisher, agentID string) *client.Client {
+	t.Helper()
+	s, err := p.Server(agentID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := client.NewInProcessClient(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = c.Close() })
+	ctx := context.Background()
+	if err := c.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Initialize(ctx, gomcp.InitializeRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func callTool(t *testing.T, c *client.Client, name string, args map[string]any) (string, bool) {
+	t.Helper()
+	req := gomcp.CallToolRequest{}
+	req.Params.Name = name
+	req.Params.Arguments = args
+	res, err := c.CallTool(context.Background(), req)
+	if err != nil {
+		t.Fatalf("CallTool(%s): %v", name, err)
+	}
+	var texts []string
+	for _, content := range res.Content {
+		if tc, ok := content.(gomcp.TextContent); ok {
+			texts = append(texts, tc.Text)
+		}
+	}
+	return strings.Join(texts, "\n"), res.IsError
+}
+
+func readResource(t *testing.T, c *client.Client, uri string) string {
+	t.Helper()
+	req := gomcp.ReadResourceRequest{}
+	req.Params.URI = uri
+	res, err := c.ReadResource(context.Background(), req)
+	if err != nil {
+		t.Fatalf("ReadResource(%s): %v", uri, err)
+	}
+	return formatResourceContents(res.Contents)
+}
+
+func TestPublisher_Tools(t *testing.T) {
+	agent := newServedAgent(t)
+	p := NewPublisher(func(string) (*ServedAgent, error) { return agent, nil }, slog.Default())
+	c := connectInProcess(t, p, "main")
+
+	list, err := c.ListTools(context.Background(), gomcp.ListToolsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Tools) != 3 {
+		t.Fatalf("tools = %d, want 3", len(list.Tools))
+	}
+
+	text, isErr := callTool(t, c, "echo", map[string]any{"text": "hi"})
+	if isErr || text != `echo {"text":"hi"} mcp:main` {
+		t.Errorf("echo = %q (error %v)", text, isErr)
+	}
+
+	if text, isErr := callTool(t, c, "forbidden", nil); !isErr || !strings.Contains(text, "denied") {
+		t.Errorf("forbidden = %q (error %v)", text, isErr)
+	}
+
+	t.Run("ask without requester is refused", func(t *testing.T) {
+		text, isErr := callTool(t, c, "risky", nil)
+		if !isErr || !strings.Contains(text, "no approval requester") {
+			t.Errorf("risky = %q (error %v)", text, isErr)
+		}
+	})
+
+	t.Run("ask routed to requester", func(t *testing.T) {
+		approver := &approveAll{}
+		agent.Requester = approver
+		agent.ApprovalTimeout = time.Second
+		c := connectInProcess(t, p, "main")
+		if text, isErr := callTool(t, c, "risky", nil); isErr || approver.calls != 1 {
+			t.Errorf("risky = %q (error %v), approvals %d", text, isErr, approver.calls)
+		}
+	})
+
+	t.Run("policy config applies", func(t *testing.T) {
+		agent.Policy = tool.PolicyConfig{DM: tool.Policy{Deny: []string{"echo"}}}
+		c := connectInProcess(t, p, "main")
+		if _, isErr := callTool(t, c, "echo", nil); !isErr {
+			t.Error("echo should be denied by policy")
+		}
+	})
+}
+
+func TestPublisher_Resources(t *testing.T) {
+	agent := newServedAgent(t)
+	p := NewPublisher(func(string) (*ServedAgent, error) { return agent, nil }, slog.Default())
+	c := connectInProcess(t, p, "main")
+
+	list := readResource(t, c, "sclaw://history")
+	if !strings.Contains(list, "sclaw://history/telegram%3A42%3A (telegram:42:): 2 messages") {
+		t.Errorf("history list = %q", list)
+	}
+
+	conv := readResource(t, c, "sclaw://history/telegram%3A42%3A")
+	if conv != "[user]\nhello\n\n[assistant]\nhi there" {
+		t.Errorf("conversation = %q", conv)
+	}
+
+	facts := readResource(t, c, "sclaw://memory/search/"+escapeSegment("green tea"))
+	if !strings.Contains(facts, "The user likes green tea [preference]") {
+		t.Errorf("facts = %q", facts)
+	}
+}
+
+func TestPublisher_NoMemory(t *testing.T) {
+	agent := newServedAgent(t)
+	agent.History, agent.Facts = nil, nil
+	p := NewPublisher(func(string) (*ServedAgent, error) { return agent, nil }, slog.Default())
+	c := connectInProcess(t, p, "main")
+
+	res, err := c.ListResources(context.Background(), gomcp.ListResourcesRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Resources) != 0 {
+		t.Errorf("resources = %v", res.Resources)
+	}
+}
+
+func TestPublisher_Handler(t *testing.T) {
+	agent := newServedAgent(t)
+	p := NewPublisher(func(string) (*ServedAgent, error) { return agent, nil }, slog.Default())
+	h, err := p.Handler("main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	c, err := client.NewStreamableHttpClient(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = c.Close() }()
+	ctx := context.Background()
+	if err := c.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Initialize(ctx, gomcp.InitializeRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	if text, isErr := callTool(t, c, "echo", map[string]any{"text": "http"}); isErr || !strings.HasPrefix(text, "echo") {
+		t.Errorf("echo = %q (error %v)", text, isErr)
+	}
+}
@@ -28,6 +28,7 @@ type mcpTool struct {
 	mcpToolName    string
 	description    string
 	schema         json.RawMessage
+	policy         tool.ApprovalLevel
 	client         *Client
 }
 
@@ -45,6 +46,7 @@ func NewTool(serverName string, mt gomcp.Tool, client *Client) tool.Tool {
 		mcpToolName:    mt.Name,
 		description:    desc,
 		schema:         schema,
+		policy:         serverPolicy(client),
 		client:         client,
 	}
 }
@@ -53,7 +55,11 @@ func (t *mcpTool) Name() string                      { return t.namespacedName }
 func (t *mcpTool) Description() string               { return t.description }
 func (t *mcpTool) Schema() json.RawMessage           { return t.schema }
 func (t *mcpTool) Scopes() []tool.Scope              { return []tool.Scope{tool.ScopeNetwork} }
-func (t *mcpTool) DefaultPolicy() tool.ApprovalLevel { return tool.ApprovalAllow }
+func (t *mcpTool) DefaultPolicy() tool.ApprovalLevel { return t.policy }
+
+// PolicyFloor implements tool.PolicyFloor: the agent's policy cannot make a
+// tool looser than its server's policy.
+func (t *mcpTool) PolicyFloor() tool.ApprovalLevel { return t.policy }
 
 // Execute calls the MCP tool and formats the result as tool.Output.
 func (t *mcpTool) Execute(ctx context.Context, args json.RawMessage, _ tool.ExecutionEnv) (tool.Output, error) {
@@ -76,6 +82,15 @@ func (t *mcpTool) Execute(ctx context.Context, args json.RawMessage, _ tool.Exec
 	}, nil
 }
 
+// serverPolicy returns the approval level configured for the client's
+// server.
+func serverPolicy(client *Client) tool.ApprovalLevel {
+	if client == nil {
+		return tool.ApprovalAllow
+	}
+	return client.Config().ApprovalLevel()
+}
+
 // formatResult extracts text content from a CallToolResult.
 func formatResult(result *gomcp.CallToolResult) string {
 	if result == nil {
@@ -106,5 +121,8 @@ func buildSchema(t gomcp.Tool) json.RawMessage {
 	return data
 }
 
-// Compile-time check that mcpTool implements tool.Tool.
-var _ tool.Tool = (*mcpTool)(nil)
+// Compile-time checks that mcpTool implements tool.Tool and tool.PolicyFloor.
+var (
+	_ tool.Tool        = (*mcpTool)(nil)
+	_ tool.PolicyFloor = (*mcpTool)(nil)
+)
@@ -42,3 +42,25 @@ type HistoryStore interface {
 	// Len returns the number of messages stored for a session.
 	Len(sessionID string) (int, error)
 }
+
+// SessionInfo summarises the stored history of one session.
+type SessionInfo struct {
+	ID        string
+	Messages  int
+	UpdatedAt time.Time // time of the last message; zero if unknown
+}
+
+// SessionLister is implemented by HistoryStores that can enumerate their
+// sessions.
+type SessionLister interface {
+	// Sessions returns the sessions with stored messages, most recently
+	// updated first.
+	Sessions() ([]SessionInfo, error)
+}
+
+// HistoryEditor is implemented by HistoryStores whose stored messages can be
+// rewritten, e.g. to remove a secret from a conversation.
+type HistoryEditor interface {
+	// Replace overwrites all messages of a session, keeping its summary.
+	Replace(sessionID string, msgs []provider.LLMMessage) error
+}
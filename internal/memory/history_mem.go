@@ -1,6 +1,8 @@
 package memory
 
 import (
+	"slices"
+	"strings"
 	"sync"
 
 	"github.com/flemzord/sclaw/internal/provider"
@@ -124,3 +126,34 @@ func (s *InMemoryHistoryStore) Len(sessionID string) (int, error) {
 	}
 	return len(sd.messages), nil
 }
+
+// Compile-time interface check.
+var _ SessionLister = (*InMemoryHistoryStore)(nil)
+
+// Sessions returns the sessions with stored messages, sorted by ID since
+// the in-memory store keeps no timestamps.
+func (s *InMemoryHistoryStore) Sessions() ([]SessionInfo, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var result []SessionInfo
+	for id, sd := range s.sessions {
+		if len(sd.messages) > 0 {
+			result = append(result, SessionInfo{ID: id, Messages: len(sd.messages)})
+		}
+	}
+	slices.SortFunc(result, func(a, b SessionInfo) int { return strings.Compare(a.ID, b.ID) })
+	return result, nil
+}
+
+// Compile-time interface check.
+var _ HistoryEditor = (*InMemoryHistoryStore)(nil)
+
+// Replace overwrites all messages of a session, keeping its summary.
+func (s *InMemoryHistoryStore) Replace(sessionID string, msgs []provider.LLMMessage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sd := s.getOrCreate(sessionID)
+	sd.messages = slices.Clone(msgs)
+	return nil
+}
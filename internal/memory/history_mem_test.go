@@ -305,3 +305,44 @@ func TestInMemoryHistoryStore_Concurrent(t *testing.T) {
 		t.Fatalf("Len = %d, want 1000", length)
 	}
 }
+
+func TestInMemoryHistoryStore_Sessions(t *testing.T) {
+	t.Parallel()
+
+	store := memory.NewInMemoryHistoryStore()
+	_ = store.Append("b", testMsg("1"))
+	_ = store.Append("a", testMsg("1"))
+	_ = store.Append("a", testMsg("2"))
+	_ = store.SetSummary("summary-only", "x")
+
+	sessions, err := store.Sessions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sessions) != 2 || sessions[0].ID != "a" || sessions[0].Messages != 2 || sessions[1].ID != "b" {
+		t.Errorf("Sessions() = %+v", sessions)
+	}
+}
+
+func TestInMemoryHistoryStore_Replace(t *testing.T) {
+	t.Parallel()
+
+	store := memory.NewInMemoryHistoryStore()
+	_ = store.Append("s1", testMsg("1"))
+	_ = store.Append("s1", testMsg("2"))
+	_ = store.SetSummary("s1", "kept")
+
+	replacement := []provider.LLMMessage{testMsg("edited")}
+	if err := store.Replace("s1", replacement); err != nil {
+		t.Fatal(err)
+	}
+	replacement[0].Content = "mutated"
+
+	msgs, _ := store.GetAll("s1")
+	if len(msgs) != 1 || msgs[0].Content != "edited" {
+		t.Errorf("GetAll() = %+v, want the edited message", msgs)
+	}
+	if summary, _ := store.GetSummary("s1"); summary != "kept" {
+		t.Errorf("GetSummary() = %q, want kept", summary)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"slices"
 	"time"
 
+	"github.com/flemzord/sclaw/internal/tool"
 	"gopkg.in/yaml.v3"
 )
 
@@ -30,7 +31,12 @@ type AgentConfig struct {
 	Memory        MemoryConfig      `yaml:"memory"`
 	Routing       RoutingConfig     `yaml:"routing"`
 	Loop          LoopOverrides     `yaml:"loop"`
+	Approval      ApprovalConfig    `yaml:"approval"`
+	Policy        tool.PolicyConfig `yaml:"policy"`
 	Cron          CronConfig        `yaml:"cron"`
+	Snapshots     SnapshotConfig    `yaml:"snapshots"`
+	Webhooks      []WebhookTrigger  `yaml:"webhooks"`
+	Timezone      string            `yaml:"timezone"` // IANA zone of the agent's user; empty = server local
 }
 
 // IsStreamingEnabled returns whether streaming is enabled for this agent.
@@ -44,6 +50,43 @@ type CronConfig struct {
 	SessionCleanup   SessionCleanupCron   `yaml:"session_cleanup"`
 	MemoryExtraction MemoryExtractionCron `yaml:"memory_extraction"`
 	MemoryCompaction MemoryCompactionCron `yaml:"memory_compaction"`
+	Alert            *CronAlert           `yaml:"alert"`
+}
+
+// CronAlert configures the chat alerted when a prompt cron of the agent fails
+// several runs in a row.
+type CronAlert struct {
+	Channel string `yaml:"channel"` // e.g. "channel.telegram"
+	ChatID  string `yaml:"chat_id"`
+	After   int    `yaml:"after"`
+}
+
+// AfterOrDefault returns the number of consecutive failures before alerting,
+// defaulting to 3.
+func (c CronAlert) AfterOrDefault() int {
+	if c.After > 0 {
+		return c.After
+	}
+	return 3
+}
+
+// WebhookTrigger runs the agent on each event a gateway webhook source
+// receives, with a prompt rendered from the event by a Go template.
+type WebhookTrigger struct {
+	Source string   `yaml:"source"` // e.g. "github", posted to /webhooks/github
+	Secret string   `yaml:"secret"` // HMAC secret, required: events run the agent unattended
+	Prompt string   `yaml:"prompt"`
+	Tools  []string `yaml:"tools"`
+	// Timeout bounds each run, e.g. "3m". Empty uses the agent loop default.
+	Timeout string         `yaml:"timeout"`
+	Output  *WebhookOutput `yaml:"output"`
+}
+
+// WebhookOutput identifies the chat a webhook trigger result is sent to.
+type WebhookOutput struct {
+	Channel string `yaml:"channel"`
+	ChatID  string `yaml:"chat_id"`
+	Notify  string `yaml:"notify"` // see prompt cron notify policies
 }
 
 // SessionCleanupCron configures the session cleanup job.
@@ -126,16 +169,106 @@ type LoopOverrides struct {
 	LoopThreshold int    `yaml:"loop_threshold"`
 }
 
+// ApprovalConfig controls how tool approval prompts behave for an agent.
+type ApprovalConfig struct {
+	// Timeout is how long a user has to answer before the call is denied.
+	Timeout string `yaml:"timeout"`
+
+	// Remember enables the "allow for this session" and "always allow"
+	// choices. Defaults to true.
+	Remember *bool `yaml:"remember"`
+
+	// ReadOnlyCommands are the commands "always allow" may generalize to
+	// any arguments (e.g. "ls *"). Other commands are remembered exactly.
+	// Defaults to tool.DefaultReadOnlyCommands.
+	ReadOnlyCommands []string `yaml:"read_only_commands"`
+
+	// Chat receives the approval prompts of calls made outside a chat, such
+	// as through the MCP server. Without it, such calls are refused.
+	Chat *ApprovalChat `yaml:"chat"`
+}
+
+// ApprovalChat identifies the chat approval prompts are sent to.
+type ApprovalChat struct {
+	Channel string `yaml:"channel"`
+	ChatID  string `yaml:"chat_id"`
+}
+
+// TimeoutOrDefault parses Timeout as a time.Duration, defaulting to 2m.
+func (c ApprovalConfig) TimeoutOrDefault() time.Duration {
+	if c.Timeout != "" {
+		if d, err := time.ParseDuration(c.Timeout); err == nil && d > 0 {
+			return d
+		}
+	}
+	return 2 * time.Minute
+}
+
+// IsRememberEnabled returns whether approvals may be remembered.
+// Defaults to true when not explicitly set.
+func (c ApprovalConfig) IsRememberEnabled() bool {
+	return c.Remember == nil || *c.Remember
+}
+
+// SnapshotConfig controls the workspace snapshots behind /undo and the
+// workspace_history tool.
+type SnapshotConfig struct {
+	// Enabled records the files changed by mutating tool calls.
+	// Defaults to true.
+	Enabled *bool `yaml:"enabled"`
+
+	// MaxTurns is how many turns with changes are kept. Defaults to 100.
+	MaxTurns int `yaml:"max_turns"`
+
+	// MaxFileSize is the size in bytes above which files are not tracked.
+	// Defaults to 1 MiB.
+	MaxFileSize int64 `yaml:"max_file_size"`
+
+	// MaxFiles is the workspace size, in files, above which changes are not
+	// recorded at all. Defaults to 10000.
+	MaxFiles int `yaml:"max_files"`
+}
+
+// IsEnabled returns whether snapshots are enabled.
+// Defaults to true when not explicitly set.
+func (c SnapshotConfig) IsEnabled() bool {
+	return c.Enabled == nil || *c.Enabled
+}
+
 // ParseAgents decodes the raw YAML nodes for the "agents:" section into typed configs.
 // It also returns the keys in declaration order (YAML map iteration order).
 func ParseAgents(nodes map[string]yaml.Node) (map[string]AgentConfig, []string, error) {
 	agents := make(map[string]AgentConfig, len(nodes))
 	order := make([]string, 0, len(nodes))
+	webhookSources := make(map[string]string) // source → agent
 	for id, node := range nodes {
 		var cfg AgentConfig
 		if err := node.Decode(&cfg); err != nil {
 			return nil, nil, fmt.Errorf("multiagent: parsing agent %q: %w", id, err)
 		}
+		if err := tool.ValidatePolicyConfig(cfg.Policy); err != nil {
+			return nil, nil, fmt.Errorf("multiagent: agent %q: %w", id, err)
+		}
+		if chat := cfg.Approval.Chat; chat != nil && (chat.Channel == "" || chat.ChatID == "") {
+			return nil, nil, fmt.Errorf("multiagent: agent %q: approval.chat requires channel and chat_id", id)
+		}
+		if alert := cfg.Cron.Alert; alert != nil && (alert.Channel == "" || alert.ChatID == "") {
+			return nil, nil, fmt.Errorf("multiagent: agent %q: cron.alert requires channel and chat_id", id)
+		}
+		if cfg.Timezone != "" {
+			if _, err := time.LoadLocation(cfg.Timezone); err != nil {
+				return nil, nil, fmt.Errorf("multiagent: agent %q: invalid timezone %q: %w", id, cfg.Timezone, err)
+			}
+		}
+		for i, wh := range cfg.Webhooks {
+			if err := wh.validate(); err != nil {
+				return nil, nil, fmt.Errorf("multiagent: agent %q: webhooks[%d]: %w", id, i, err)
+			}
+			if other, ok := webhookSources[wh.Source]; ok {
+				return nil, nil, fmt.Errorf("multiagent: agent %q: webhook source %q is already used by agent %q", id, wh.Source, other)
+			}
+			webhookSources[wh.Source] = id
+		}
 		agents[id] = cfg
 		order = append(order, id)
 	}
@@ -144,6 +277,26 @@ func ParseAgents(nodes map[string]yaml.Node) (map[string]AgentConfig, []string,
 	return agents, order, nil
 }
 
+// validate checks the fields a webhook trigger requires.
+func (w WebhookTrigger) validate() error {
+	switch {
+	case w.Source == "":
+		return fmt.Errorf("source is required")
+	case w.Secret == "":
+		return fmt.Errorf("secret is required")
+	case w.Prompt == "":
+		return fmt.Errorf("prompt is required")
+	case w.Output != nil && (w.Output.Channel == "" || w.Output.ChatID == ""):
+		return fmt.Errorf("output requires channel and chat_id")
+	}
+	if w.Timeout != "" {
+		if d, err := time.ParseDuration(w.Timeout); err != nil || d <= 0 {
+			return fmt.Errorf("invalid timeout %q", w.Timeout)
+		}
+	}
+	return nil
+}
+
 // ResolveDefaults fills zero-valued fields with computed defaults.
 // Must be called after ParseAgents and before NewRegistry.
 func ResolveDefaults(agents map[string]AgentConfig, dataDir string) {
@@ -3,7 +3,9 @@ package multiagent
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -110,6 +112,27 @@ func TestMemoryConfig_IsEnabled_ExplicitFalse(t *testing.T) {
 	}
 }
 
+func TestApprovalConfig_Defaults(t *testing.T) {
+	t.Parallel()
+
+	var ac ApprovalConfig
+	if got := ac.TimeoutOrDefault(); got != 2*time.Minute {
+		t.Errorf("TimeoutOrDefault() = %v, want 2m", got)
+	}
+	if !ac.IsRememberEnabled() {
+		t.Error("IsRememberEnabled() = false, want true (nil Remember should default to true)")
+	}
+
+	v := false
+	ac = ApprovalConfig{Timeout: "30s", Remember: &v}
+	if got := ac.TimeoutOrDefault(); got != 30*time.Second {
+		t.Errorf("TimeoutOrDefault() = %v, want 30s", got)
+	}
+	if ac.IsRememberEnabled() {
+		t.Error("IsRememberEnabled() = true, want false")
+	}
+}
+
 func TestAgentConfig_IsStreamingEnabled_DefaultTrue(t *testing.T) {
 	t.Parallel()
 
@@ -167,6 +190,177 @@ routing:
 	}
 }
 
+func TestParseAgents_WithPolicyRules(t *testing.T) {
+	t.Parallel()
+
+	nodes := mustYAMLNodes(t, map[string]string{
+		"bot": `
+policy:
+  group:
+    default: ask
+    rules:
+      - tools: [exec]
+        args:
+          command: '^git (status|log)'
+        action: allow
+      - tools: [exec]
+        action: ask
+        approvers: ["42"]
+`,
+	})
+
+	agents, _, err := ParseAgents(nodes)
+	if err != nil {
+		t.Fatalf("ParseAgents() error = %v", err)
+	}
+	rules := agents["bot"].Policy.Group.Rules
+	if len(rules) != 2 || rules[0].Args["command"] != "^git (status|log)" || rules[1].Approvers[0] != "42" {
+		t.Errorf("Group.Rules = %+v", rules)
+	}
+}
+
+func TestParseAgents_InvalidPolicyRule(t *testing.T) {
+	t.Parallel()
+
+	nodes := mustYAMLNodes(t, map[string]string{
+		"bot": `
+policy:
+  dm:
+    rules:
+      - tools: [write_file]
+        args_not:
+          path: '^(docs/'
+        action: deny
+`,
+	})
+
+	_, _, err := ParseAgents(nodes)
+	if err == nil || !strings.Contains(err.Error(), "rule #1") {
+		t.Fatalf("ParseAgents() error = %v, want rule #1 error", err)
+	}
+}
+
+func TestParseAgents_IncompleteApprovalChat(t *testing.T) {
+	t.Parallel()
+
+	nodes := mustYAMLNodes(t, map[string]string{
+		"bot": `
+approval:
+  chat:
+    channel: channel.telegram
+`,
+	})
+
+	_, _, err := ParseAgents(nodes)
+	if err == nil || !strings.Contains(err.Error(), "approval.chat") {
+		t.Fatalf("ParseAgents() error = %v, want approval.chat error", err)
+	}
+}
+
+func TestParseAgents_Timezone(t *testing.T) {
+	t.Parallel()
+
+	agents, _, err := ParseAgents(mustYAMLNodes(t, map[string]string{
+		"bot": `timezone: UTC`,
+	}))
+	if err != nil {
+		t.Fatalf("ParseAgents() error = %v", err)
+	}
+	if got := agents["bot"].Timezone; got != "UTC" {
+		t.Errorf("Timezone = %q, want UTC", got)
+	}
+
+	_, _, err = ParseAgents(mustYAMLNodes(t, map[string]string{
+		"bot": `timezone: Mars/Olympus`,
+	}))
+	if err == nil || !strings.Contains(err.Error(), "invalid timezone") {
+		t.Fatalf("ParseAgents() error = %v, want invalid timezone error", err)
+	}
+}
+
+func TestParseAgents_CronAlert(t *testing.T) {
+	t.Parallel()
+
+	agents, _, err := ParseAgents(mustYAMLNodes(t, map[string]string{
+		"bot": `
+cron:
+  alert:
+    channel: channel.telegram
+    chat_id: "42"
+`,
+	}))
+	if err != nil {
+		t.Fatalf("ParseAgents() error = %v", err)
+	}
+	if got := agents["bot"].Cron.Alert.AfterOrDefault(); got != 3 {
+		t.Errorf("AfterOrDefault() = %d, want 3", got)
+	}
+
+	_, _, err = ParseAgents(mustYAMLNodes(t, map[string]string{
+		"bot": `
+cron:
+  alert:
+    after: 2
+`,
+	}))
+	if err == nil || !strings.Contains(err.Error(), "cron.alert") {
+		t.Fatalf("ParseAgents() error = %v, want cron.alert error", err)
+	}
+}
+
+func TestParseAgents_Webhooks(t *testing.T) {
+	t.Parallel()
+
+	agents, _, err := ParseAgents(mustYAMLNodes(t, map[string]string{
+		"ops": `
+webhooks:
+  - source: github
+    secret: s3cret
+    prompt: "Push to {{.Payload.ref}}"
+    timeout: 2m
+    output:
+      channel: channel.telegram
+      chat_id: "42"
+      notify: changed
+`,
+	}))
+	if err != nil {
+		t.Fatalf("ParseAgents() error = %v", err)
+	}
+	wh := agents["ops"].Webhooks
+	if len(wh) != 1 || wh[0].Source != "github" || wh[0].Output.Notify != "changed" {
+		t.Errorf("Webhooks = %+v", wh)
+	}
+
+	tests := []struct {
+		name  string
+		nodes map[string]string
+		want  string
+	}{
+		{"missing secret", map[string]string{"ops": "webhooks: [{source: github, prompt: hi}]"}, "secret is required"},
+		{"missing prompt", map[string]string{"ops": "webhooks: [{source: github, secret: s}]"}, "prompt is required"},
+		{"bad timeout", map[string]string{"ops": "webhooks: [{source: github, secret: s, prompt: hi, timeout: soon}]"}, "invalid timeout"},
+		{"output without chat", map[string]string{"ops": "webhooks: [{source: github, secret: s, prompt: hi, output: {channel: channel.telegram}}]"}, "chat_id"},
+		{
+			"duplicate source",
+			map[string]string{
+				"a": "webhooks: [{source: github, secret: s, prompt: hi}]",
+				"b": "webhooks: [{source: github, secret: s, prompt: hi}]",
+			},
+			"already used",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, _, err := ParseAgents(mustYAMLNodes(t, tt.nodes))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ParseAgents() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
 func TestParseAgents_WithThreadRouting(t *testing.T) {
 	t.Parallel()
 
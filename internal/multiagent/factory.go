@@ -7,17 +7,21 @@ import (
 	"io/fs"
 	"log/slog"
 	"path/filepath"
+	"reflect"
 	"slices"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/flemzord/sclaw/internal/activity"
 	"github.com/flemzord/sclaw/internal/agent"
+	ctxengine "github.com/flemzord/sclaw/internal/context"
 	"github.com/flemzord/sclaw/internal/mcp"
 	"github.com/flemzord/sclaw/internal/memory"
 	"github.com/flemzord/sclaw/internal/provider"
 	"github.com/flemzord/sclaw/internal/router"
 	"github.com/flemzord/sclaw/internal/security"
+	"github.com/flemzord/sclaw/internal/snapshot"
 	"github.com/flemzord/sclaw/internal/subagent"
 	"github.com/flemzord/sclaw/internal/tool"
 	"github.com/flemzord/sclaw/internal/workspace"
@@ -44,6 +48,12 @@ type FactoryConfig struct {
 	// URLFilter, if non-nil, restricts which URLs network tools can access.
 	URLFilter *security.URLFilter
 
+	// SandboxPolicy and Sandbox are wired into each per-session tool
+	// registry so that tools in the policy's scopes start their
+	// subprocesses in the sandbox.
+	SandboxPolicy security.SandboxPolicy
+	Sandbox       security.Sandbox
+
 	// SanitizedEnv, if non-nil, provides a pre-sanitized set of environment
 	// variables passed to tools that spawn subprocesses.
 	SanitizedEnv []string
@@ -57,6 +67,10 @@ type FactoryConfig struct {
 	// GlobalSkillsDir is the path to the global skills directory.
 	// Skills in this directory are available to all agents by default.
 	GlobalSkillsDir string
+
+	// Activity, if non-nil, receives agent resolution decisions and the
+	// tool calls of session loops.
+	Activity *activity.Bus
 }
 
 // Factory resolves the agent for a session and creates an agent.Loop
@@ -71,10 +85,24 @@ type Factory struct {
 	subAgentMgr *subagent.Manager
 	mcpResolver *mcp.Resolver
 
+	// estimators holds one calibrated token estimator per model, fed with
+	// the provider usage reported to every loop.
+	estimators *ctxengine.EstimatorSet
+
+	// approvalRequester builds the requester that prompts users for tool
+	// approvals; nil means "ask" tools are denied.
+	approvalRequester func(msg message.InboundMessage) tool.ApprovalRequester
+
+	// notifier builds the notifier tools use to message the chat a call
+	// came from after it returned; nil disables such notifications.
+	notifier func(msg message.InboundMessage) tool.Notifier
+
 	mu         sync.RWMutex
 	stores     map[string]memory.HistoryStore
 	factStores map[string]memory.Store
 	souls      map[string]workspace.SoulProvider
+	approvals  map[string]*tool.ApprovalMemory
+	snapshots  map[string]*snapshot.Store
 	dbs        []*sql.DB
 }
 
@@ -84,6 +112,8 @@ var (
 	_ router.HistoryResolver = (*Factory)(nil)
 	_ router.SoulResolver    = (*Factory)(nil)
 	_ router.SkillResolver   = (*Factory)(nil)
+	_ router.ContextResolver = (*Factory)(nil)
+	_ router.AgentChecker    = (*Factory)(nil)
 )
 
 // NewFactory creates a Factory from the given configuration.
@@ -95,9 +125,12 @@ func NewFactory(cfg FactoryConfig) *Factory {
 	f := &Factory{
 		cfg:         cfg,
 		mcpResolver: mcp.NewResolver(logger),
+		estimators:  ctxengine.NewEstimatorSet(),
 		stores:      make(map[string]memory.HistoryStore),
 		factStores:  make(map[string]memory.Store),
 		souls:       make(map[string]workspace.SoulProvider),
+		approvals:   make(map[string]*tool.ApprovalMemory),
+		snapshots:   make(map[string]*snapshot.Store),
 	}
 	f.registry.Store(cfg.Registry)
 	return f
@@ -111,6 +144,27 @@ func (f *Factory) SetSubAgentManager(mgr *subagent.Manager) {
 	f.subAgentMgr = mgr
 }
 
+// SetApprovalRequester sets the function that builds the approval requester
+// for an inbound message. Like SetSubAgentManager, it breaks a circular
+// dependency: the router that prompts users is created after the factory.
+func (f *Factory) SetApprovalRequester(fn func(msg message.InboundMessage) tool.ApprovalRequester) {
+	f.approvalRequester = fn
+}
+
+// SetNotifier sets the function that builds the chat notifier for an
+// inbound message, for the same reason as SetApprovalRequester.
+func (f *Factory) SetNotifier(fn func(msg message.InboundMessage) tool.Notifier) {
+	f.notifier = fn
+}
+
+// buildNotifier returns the notifier for the chat msg came from, or nil.
+func (f *Factory) buildNotifier(msg message.InboundMessage) tool.Notifier {
+	if f.notifier == nil {
+		return nil
+	}
+	return f.notifier(msg)
+}
+
 // currentRegistry returns the current immutable Registry.
 // Uses atomic load for lock-free access on the hot path (ForSession).
 func (f *Factory) currentRegistry() *Registry {
@@ -122,18 +176,32 @@ func (f *Factory) GlobalTools() *tool.Registry {
 	return f.cfg.GlobalTools
 }
 
+// HasAgent reports whether an agent with the given ID is configured.
+func (f *Factory) HasAgent(agentID string) bool {
+	_, ok := f.currentRegistry().AgentConfig(agentID)
+	return ok
+}
+
 // ForSession resolves the agent for the session and builds an agent.Loop.
 // If the session has no AgentID yet, it is resolved from the inbound message
 // and stored back on the session.
 func (f *Factory) ForSession(session *router.Session, msg message.InboundMessage) (*agent.Loop, error) {
 	agentID := session.AgentID
 	if agentID == "" {
-		resolved, err := f.currentRegistry().Resolve(msg)
+		resolved, rule, err := f.currentRegistry().ResolveRule(msg)
 		if err != nil {
 			return nil, fmt.Errorf("multiagent: resolving agent for session %s: %w", session.ID, err)
 		}
 		agentID = resolved
 		session.AgentID = agentID
+		f.cfg.Activity.Publish(context.Background(), activity.Event{
+			Type:      activity.TypeAgentResolved,
+			AgentID:   agentID,
+			SessionID: session.ID,
+			Channel:   msg.Channel,
+			ChatID:    msg.Chat.ID,
+			Data:      map[string]any{"rule": rule, "sender_id": msg.Sender.ID},
+		})
 	}
 
 	agentCfg, ok := f.currentRegistry().AgentConfig(agentID)
@@ -154,16 +222,12 @@ func (f *Factory) ForSession(session *router.Session, msg message.InboundMessage
 	toolReg := f.buildToolRegistry(agentCfg)
 
 	// Inject per-agent MCP tools from mcp.json.
-	mcpTools := f.mcpResolver.ResolveTools(context.Background(), agentID, agentCfg.DataDir)
-	if len(mcpTools) > 0 {
+	if mcpTools := f.mcpTools(agentID, agentCfg); len(mcpTools) > 0 {
 		// Clone if we got the shared global registry to avoid mutating it.
 		if toolReg == f.cfg.GlobalTools {
 			toolReg = f.cfg.GlobalTools.Clone()
 		}
 		for _, t := range mcpTools {
-			if len(agentCfg.Tools) > 0 && !slices.Contains(agentCfg.Tools, t.Name()) {
-				continue
-			}
 			_ = toolReg.Register(t)
 		}
 	}
@@ -179,31 +243,28 @@ func (f *Factory) ForSession(session *router.Session, msg message.InboundMessage
 		}
 	}
 
-	// Wire audit logger and rate limiter into the tool registry so that
-	// every tool call is recorded and rate-limited per-session.
+	// Wire audit logger, rate limiter and sandbox into the tool registry so
+	// that every tool call is recorded, rate-limited per-session and
+	// isolated as the sandbox policy requires.
 	if f.cfg.AuditLogger != nil {
 		toolReg.SetAuditLogger(f.cfg.AuditLogger)
 	}
 	if f.cfg.RateLimiter != nil {
 		toolReg.SetRateLimiter(f.cfg.RateLimiter)
 	}
+	toolReg.SetSandbox(f.cfg.SandboxPolicy, f.cfg.Sandbox)
 
 	// Build path filter for allowed directories outside workspace.
-	var pathFilter *security.PathFilter
-	if len(agentCfg.AllowedDirs) > 0 {
-		dirs := make([]security.AllowedDir, len(agentCfg.AllowedDirs))
-		for i, d := range agentCfg.AllowedDirs {
-			dirs[i] = security.AllowedDir{
-				Path: d.Path,
-				Mode: security.PathAccessMode(d.Mode),
-			}
-		}
-		pathFilter = security.NewPathFilter(security.PathFilterConfig{AllowedDirs: dirs})
-	}
+	pathFilter := buildPathFilter(agentCfg)
 
 	// Build executor.
 	executor := agent.NewToolExecutor(agent.ToolExecutorConfig{
-		Registry: toolReg,
+		Registry:        toolReg,
+		PolicyCfg:       agentCfg.Policy,
+		PolicyCtx:       policyContextFor(msg),
+		Requester:       f.buildApprovalRequester(agentID, agentCfg, session.ID, msg),
+		ApprovalTimeout: agentCfg.Approval.TimeoutOrDefault(),
+		Activity:        f.cfg.Activity,
 		Env: tool.ExecutionEnv{
 			Workspace:    agentCfg.Workspace,
 			DataDir:      agentCfg.DataDir,
@@ -211,6 +272,16 @@ func (f *Factory) ForSession(session *router.Session, msg message.InboundMessage
 			URLFilter:    f.cfg.URLFilter,
 			PathFilter:   pathFilter,
 			SessionID:    session.ID,
+			SenderID:     msg.Sender.ID,
+			Snapshots:    f.snapshotRecorder(agentID, session.Key.String(), msg.TextContent()),
+			Notifier:     f.buildNotifier(msg),
+			Origin: tool.Origin{
+				Channel:  msg.Channel,
+				ChatID:   msg.Chat.ID,
+				ThreadID: msg.ThreadID,
+				ChatType: msg.Chat.Type,
+			},
+			Timezone: agentCfg.Timezone,
 		},
 	})
 
@@ -220,6 +291,133 @@ func (f *Factory) ForSession(session *router.Session, msg message.InboundMessage
 	return agent.NewLoop(p, executor, loopCfg), nil
 }
 
+// buildApprovalRequester returns the requester prompting the user who sent
+// msg, wrapped with the agent's approval memory when remembering is enabled.
+func (f *Factory) buildApprovalRequester(agentID string, cfg AgentConfig, sessionID string, msg message.InboundMessage) tool.ApprovalRequester {
+	if f.approvalRequester == nil {
+		return nil
+	}
+	requester := f.approvalRequester(msg)
+	if !cfg.Approval.IsRememberEnabled() {
+		return requester
+	}
+	return &tool.RememberingRequester{
+		Next:      requester,
+		Memory:    f.resolveApprovalMemory(agentID, cfg),
+		SessionID: sessionID,
+
+		ReadOnlyCommands: cfg.Approval.ReadOnlyCommands,
+	}
+}
+
+// EndSession drops the session-scoped approvals of an ended session. It is
+// called by the router when a session is pruned or deleted.
+func (f *Factory) EndSession(agentID, sessionID string) {
+	f.mu.Lock()
+	m := f.approvals[agentID]
+	f.mu.Unlock()
+	if m != nil {
+		m.ForgetSession(sessionID)
+	}
+}
+
+// resolveApprovalMemory returns the approval memory for an agent, loading
+// persisted grants from its data directory on first use.
+func (f *Factory) resolveApprovalMemory(agentID string, cfg AgentConfig) *tool.ApprovalMemory {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if m, ok := f.approvals[agentID]; ok {
+		return m
+	}
+
+	var path string
+	if cfg.DataDir != "" {
+		path = filepath.Join(cfg.DataDir, "approvals.json")
+	}
+	m, err := tool.NewApprovalMemory(path)
+	if err != nil {
+		if f.cfg.Logger != nil {
+			f.cfg.Logger.Error("multiagent: failed to load approvals, starting empty",
+				"agent", agentID, "path", path, "error", err)
+		}
+		// Keep the broken file untouched: remember in memory only.
+		m, _ = tool.NewApprovalMemory("")
+	}
+	f.approvals[agentID] = m
+	return m
+}
+
+// CronSnapshotSession is the session under which workspace changes made by
+// cron jobs are recorded.
+const CronSnapshotSession = "cron"
+
+// snapshotRecorder starts a snapshot turn for session, or returns nil when
+// snapshots are disabled or unavailable for the agent.
+func (f *Factory) snapshotRecorder(agentID, session, prompt string) *snapshot.Recorder {
+	store := f.ResolveWorkspaceHistory(agentID)
+	if store == nil {
+		return nil
+	}
+	return store.NewRecorder(session, prompt)
+}
+
+// ResolveWorkspaceHistory returns the snapshot store of an agent, opening it
+// in the agent's data directory on first use. It returns nil when snapshots
+// are disabled or the store cannot be opened.
+func (f *Factory) ResolveWorkspaceHistory(agentID string) *snapshot.Store {
+	cfg, ok := f.currentRegistry().AgentConfig(agentID)
+	if !ok || !cfg.Snapshots.IsEnabled() || cfg.Workspace == "" || cfg.DataDir == "" {
+		return nil
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if s, ok := f.snapshots[agentID]; ok {
+		return s
+	}
+	s, err := snapshot.New(snapshot.Config{
+		Workspace:   cfg.Workspace,
+		Dir:         filepath.Join(cfg.DataDir, "snapshots"),
+		MaxTurns:    cfg.Snapshots.MaxTurns,
+		MaxFileSize: cfg.Snapshots.MaxFileSize,
+		MaxFiles:    cfg.Snapshots.MaxFiles,
+		Logger:      f.cfg.Logger,
+	})
+	if err != nil && f.cfg.Logger != nil {
+		f.cfg.Logger.Error("multiagent: workspace snapshots disabled",
+			"agent", agentID, "error", err)
+	}
+	// A nil store is cached too, so a broken directory is reported once.
+	f.snapshots[agentID] = s
+	return s
+}
+
+// buildPathFilter returns the filter granting access to the agent's allowed
+// directories outside its workspace, or nil when it has none.
+func buildPathFilter(cfg AgentConfig) *security.PathFilter {
+	if len(cfg.AllowedDirs) == 0 {
+		return nil
+	}
+	dirs := make([]security.AllowedDir, len(cfg.AllowedDirs))
+	for i, d := range cfg.AllowedDirs {
+		dirs[i] = security.AllowedDir{
+			Path: d.Path,
+			Mode: security.PathAccessMode(d.Mode),
+		}
+	}
+	return security.NewPathFilter(security.PathFilterConfig{AllowedDirs: dirs})
+}
+
+// policyContextFor maps the chat type of msg to a tool policy context.
+func policyContextFor(msg message.InboundMessage) tool.PolicyContext {
+	if msg.Chat.Type == message.ChatDM {
+		return tool.PolicyContextDM
+	}
+	return tool.PolicyContextGroup
+}
+
 // buildToolRegistry returns a filtered tool registry when the agent specifies
 // a tool allowlist, or the global registry otherwise.
 func (f *Factory) buildToolRegistry(cfg AgentConfig) *tool.Registry {
@@ -243,6 +441,7 @@ func (f *Factory) buildLoopConfig(cfg AgentConfig) agent.LoopConfig {
 		TokenBudget:   cfg.Loop.TokenBudget,
 		LoopThreshold: cfg.Loop.LoopThreshold,
 		ProviderName:  f.providerName(),
+		UsageObserver: f.estimators,
 	}
 	if cfg.Loop.Timeout != "" {
 		if d, err := time.ParseDuration(cfg.Loop.Timeout); err == nil {
@@ -252,6 +451,18 @@ func (f *Factory) buildLoopConfig(cfg AgentConfig) agent.LoopConfig {
 	return lc
 }
 
+// ResolveContext implements router.ContextResolver. The estimator is a BPE
+// estimator matching the model of the provider serving the agent when
+// available, calibrated over time with the prompt token counts that model
+// reports. All agents use the default provider for now.
+func (f *Factory) ResolveContext(_ string) (ctxengine.TokenEstimator, int) {
+	p := f.cfg.DefaultProvider
+	if p == nil {
+		return nil, 0
+	}
+	return f.estimators.For(p), p.ContextWindowSize()
+}
+
 // providerName returns the identifier of the default provider module.
 func (f *Factory) providerName() string {
 	if f.cfg.DefaultProviderName != "" {
@@ -376,6 +587,35 @@ func (f *Factory) ResolveSoul(agentID string) (string, error) {
 // agent's data directory, filters excluded global skills, activates based
 // on trigger rules and available tools, then formats for the system prompt.
 func (f *Factory) ResolveSkills(agentID, userMessage string) (string, error) {
+	active, err := f.activeSkills(agentID, userMessage)
+	if err != nil {
+		return "", err
+	}
+	return workspace.FormatSkillsForPrompt(active), nil
+}
+
+// ResolveSkillSections is like ResolveSkills but splits the active skills
+// into an always-on section, which is identical from turn to turn and can
+// be part of the cached prompt prefix, and a triggered section that depends
+// on the user message.
+func (f *Factory) ResolveSkillSections(agentID, userMessage string) (stable, volatile string, err error) {
+	active, err := f.activeSkills(agentID, userMessage)
+	if err != nil {
+		return "", "", err
+	}
+	var always, triggered []workspace.Skill
+	for _, s := range active {
+		if s.Meta.Trigger == workspace.TriggerAlways {
+			always = append(always, s)
+		} else {
+			triggered = append(triggered, s)
+		}
+	}
+	return workspace.FormatSkillsForPrompt(always), workspace.FormatSkillsForPrompt(triggered), nil
+}
+
+// activeSkills loads and activates the skills for the given agent.
+func (f *Factory) activeSkills(agentID, userMessage string) ([]workspace.Skill, error) {
 	logger := f.cfg.Logger
 	if logger == nil {
 		logger = slog.Default()
@@ -383,19 +623,19 @@ func (f *Factory) ResolveSkills(agentID, userMessage string) (string, error) {
 
 	agentCfg, ok := f.currentRegistry().AgentConfig(agentID)
 	if !ok {
-		return "", nil
+		return nil, nil
 	}
 
 	// Load builtin skills from embedded FS.
 	builtinSkills, err := workspace.LoadSkillsFromFS(f.cfg.BuiltinSkillsFS, workspace.BuiltinPathPrefix)
 	if err != nil {
-		return "", fmt.Errorf("multiagent: loading builtin skills: %w", err)
+		return nil, fmt.Errorf("multiagent: loading builtin skills: %w", err)
 	}
 
 	// Load global filesystem skills.
 	globalSkills, err := workspace.LoadSkillsFromDir(f.cfg.GlobalSkillsDir)
 	if err != nil {
-		return "", fmt.Errorf("multiagent: loading global skills: %w", err)
+		return nil, fmt.Errorf("multiagent: loading global skills: %w", err)
 	}
 
 	// Merge builtin + global (global overrides builtin by name).
@@ -408,7 +648,7 @@ func (f *Factory) ResolveSkills(agentID, userMessage string) (string, error) {
 	agentSkillsDir := filepath.Join(agentCfg.DataDir, "skills")
 	agentSkills, err := workspace.LoadSkillsFromDir(agentSkillsDir)
 	if err != nil {
-		return "", fmt.Errorf("multiagent: loading agent skills for %q: %w", agentID, err)
+		return nil, fmt.Errorf("multiagent: loading agent skills for %q: %w", agentID, err)
 	}
 
 	logger.Debug("skills loaded",
@@ -421,16 +661,23 @@ func (f *Factory) ResolveSkills(agentID, userMessage string) (string, error) {
 		"agent_dir", agentSkillsDir,
 	)
 
-	// Final merge: merged (builtin+global) + per-agent.
-	allSkills := slices.Concat(merged, agentSkills)
+	// MCP prompts are exposed as skills rendered by their server's
+	// get_prompt tool.
+	mcpSkills := f.mcpResolver.ResolveSkills(context.Background(), agentID, agentCfg.DataDir)
+
+	// Final merge: merged (builtin+global) + per-agent + MCP prompts.
+	allSkills := slices.Concat(merged, agentSkills, mcpSkills)
 	if len(allSkills) == 0 {
 		logger.Debug("no skills found for agent", "agent_id", agentID)
-		return "", nil
+		return nil, nil
 	}
 
 	// Get available tool names.
 	toolReg := f.buildToolRegistry(agentCfg)
 	toolNames := toolReg.Names()
+	for _, t := range f.mcpTools(agentID, agentCfg) {
+		toolNames = append(toolNames, t.Name())
+	}
 
 	// Activate skills based on trigger rules and available tools.
 	active := workspace.NewSkillActivator().Activate(workspace.ActivateRequest{
@@ -451,13 +698,24 @@ func (f *Factory) ResolveSkills(agentID, userMessage string) (string, error) {
 		"active_names", activeNames,
 	)
 
-	return workspace.FormatSkillsForPrompt(active), nil
+	return active, nil
+}
+
+// mcpTools returns the agent's MCP tools, filtered by its tool allowlist.
+func (f *Factory) mcpTools(agentID string, agentCfg AgentConfig) []tool.Tool {
+	tools := f.mcpResolver.ResolveTools(context.Background(), agentID, agentCfg.DataDir)
+	if len(agentCfg.Tools) == 0 {
+		return tools
+	}
+	return slices.DeleteFunc(tools, func(t tool.Tool) bool {
+		return !slices.Contains(agentCfg.Tools, t.Name())
+	})
 }
 
 // ForCronJob builds an agent.Loop for cron execution with allow-all policy.
 // Unlike ForSession, it does not require a router.Session and uses a permissive
 // policy (all tools auto-approved) since cron jobs are system-initiated.
-func (f *Factory) ForCronJob(agentID string, toolFilter []string, loopOverrides agent.LoopConfig) (*agent.Loop, string, error) {
+func (f *Factory) ForCronJob(agentID string, toolFilter []string, extraTools []tool.Tool, loopOverrides agent.LoopConfig) (*agent.Loop, string, error) {
 	agentCfg, ok := f.currentRegistry().AgentConfig(agentID)
 	if !ok {
 		return nil, "", fmt.Errorf("%w: %q", ErrAgentNotFound, agentID)
@@ -469,15 +727,12 @@ func (f *Factory) ForCronJob(agentID string, toolFilter []string, loopOverrides
 	toolReg := f.buildToolRegistry(agentCfg)
 
 	// Inject per-agent MCP tools from mcp.json.
-	mcpTools := f.mcpResolver.ResolveTools(context.Background(), agentID, agentCfg.DataDir)
-	if len(mcpTools) > 0 {
+	if mcpTools := f.mcpTools(agentID, agentCfg); len(mcpTools) > 0 {
+		// Clone if we got the shared global registry to avoid mutating it.
 		if toolReg == f.cfg.GlobalTools {
 			toolReg = f.cfg.GlobalTools.Clone()
 		}
 		for _, t := range mcpTools {
-			if len(agentCfg.Tools) > 0 && !slices.Contains(agentCfg.Tools, t.Name()) {
-				continue
-			}
 			_ = toolReg.Register(t)
 		}
 	}
@@ -493,23 +748,24 @@ func (f *Factory) ForCronJob(agentID string, toolFilter []string, loopOverrides
 		toolReg = filtered
 	}
 
+	// Add the tools the cron itself provides, such as notify.
+	if len(extraTools) > 0 {
+		if toolReg == f.cfg.GlobalTools {
+			toolReg = f.cfg.GlobalTools.Clone()
+		}
+		for _, t := range extraTools {
+			_ = toolReg.Register(t)
+		}
+	}
+
 	// Wire audit logger but NOT rate limiter (cron = system, not user).
 	if f.cfg.AuditLogger != nil {
 		toolReg.SetAuditLogger(f.cfg.AuditLogger)
 	}
+	toolReg.SetSandbox(f.cfg.SandboxPolicy, f.cfg.Sandbox)
 
 	// Build path filter for allowed directories outside workspace.
-	var pathFilter *security.PathFilter
-	if len(agentCfg.AllowedDirs) > 0 {
-		dirs := make([]security.AllowedDir, len(agentCfg.AllowedDirs))
-		for i, d := range agentCfg.AllowedDirs {
-			dirs[i] = security.AllowedDir{
-				Path: d.Path,
-				Mode: security.PathAccessMode(d.Mode),
-			}
-		}
-		pathFilter = security.NewPathFilter(security.PathFilterConfig{AllowedDirs: dirs})
-	}
+	pathFilter := buildPathFilter(agentCfg)
 
 	// Build executor with allow-all policy (cron jobs need no user approval).
 	executor := agent.NewToolExecutor(agent.ToolExecutorConfig{
@@ -524,6 +780,8 @@ func (f *Factory) ForCronJob(agentID string, toolFilter []string, loopOverrides
 			SanitizedEnv: f.cfg.SanitizedEnv,
 			URLFilter:    f.cfg.URLFilter,
 			PathFilter:   pathFilter,
+			Snapshots:    f.snapshotRecorder(agentID, CronSnapshotSession, ""),
+			Timezone:     agentCfg.Timezone,
 		},
 	})
 
@@ -545,9 +803,74 @@ func (f *Factory) ForCronJob(agentID string, toolFilter []string, loopOverrides
 	return agent.NewLoop(p, executor, loopCfg), systemPrompt, nil
 }
 
+// MCPSnapshotSession is the session under which workspace changes made
+// through the MCP server are recorded.
+const MCPSnapshotSession = "mcp"
+
+// ForMCP returns the agent published through the MCP server. Tool calls
+// follow the agent's policy as in a direct message; "ask" tools are prompted
+// in the chat named by approval.chat and refused without one.
+func (f *Factory) ForMCP(agentID string) (*mcp.ServedAgent, error) {
+	agentCfg, ok := f.currentRegistry().AgentConfig(agentID)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrAgentNotFound, agentID)
+	}
+
+	toolReg := f.buildToolRegistry(agentCfg)
+	if mcpTools := f.mcpTools(agentID, agentCfg); len(mcpTools) > 0 {
+		// Clone if we got the shared global registry to avoid mutating it.
+		if toolReg == f.cfg.GlobalTools {
+			toolReg = f.cfg.GlobalTools.Clone()
+		}
+		for _, t := range mcpTools {
+			_ = toolReg.Register(t)
+		}
+	}
+	if f.cfg.AuditLogger != nil {
+		toolReg.SetAuditLogger(f.cfg.AuditLogger)
+	}
+	if f.cfg.RateLimiter != nil {
+		toolReg.SetRateLimiter(f.cfg.RateLimiter)
+	}
+	toolReg.SetSandbox(f.cfg.SandboxPolicy, f.cfg.Sandbox)
+
+	sessionID := "mcp:" + agentID
+	var requester tool.ApprovalRequester
+	if chat := agentCfg.Approval.Chat; chat != nil {
+		requester = f.buildApprovalRequester(agentID, agentCfg, sessionID, message.InboundMessage{
+			Channel: chat.Channel,
+			Chat:    message.Chat{ID: chat.ChatID, Type: message.ChatDM},
+		})
+	}
+
+	pathFilter := buildPathFilter(agentCfg)
+	return &mcp.ServedAgent{
+		ID:              agentID,
+		Tools:           toolReg,
+		Policy:          agentCfg.Policy,
+		Requester:       requester,
+		ApprovalTimeout: agentCfg.Approval.TimeoutOrDefault(),
+		Env: func(toolName string) tool.ExecutionEnv {
+			return tool.ExecutionEnv{
+				Workspace:    agentCfg.Workspace,
+				DataDir:      agentCfg.DataDir,
+				SanitizedEnv: f.cfg.SanitizedEnv,
+				URLFilter:    f.cfg.URLFilter,
+				PathFilter:   pathFilter,
+				SessionID:    sessionID,
+				SenderID:     "mcp",
+				Snapshots:    f.snapshotRecorder(agentID, MCPSnapshotSession, "mcp: "+toolName),
+				Timezone:     agentCfg.Timezone,
+			}
+		},
+		Facts:   f.ResolveFactStore(agentID),
+		History: f.ResolveHistory(agentID),
+	}, nil
+}
+
 // BuildCronLoop implements cron.LoopBuilder.
-func (f *Factory) BuildCronLoop(agentID string, toolFilter []string, loopOverrides agent.LoopConfig) (*agent.Loop, string, error) {
-	return f.ForCronJob(agentID, toolFilter, loopOverrides)
+func (f *Factory) BuildCronLoop(agentID string, toolFilter []string, extraTools []tool.Tool, loopOverrides agent.LoopConfig) (*agent.Loop, string, error) {
+	return f.ForCronJob(agentID, toolFilter, extraTools, loopOverrides)
 }
 
 // Reload atomically swaps the Registry and selectively invalidates caches
@@ -586,6 +909,28 @@ func (f *Factory) Reload(newRegistry *Registry) {
 		}
 	}
 
+	// Invalidate approval memories for deleted agents or agents whose
+	// DataDir changed (which changes the approvals file path).
+	for agentID := range f.approvals {
+		oldCfg, oldOK := old.AgentConfig(agentID)
+		newCfg, newOK := newRegistry.AgentConfig(agentID)
+		if !newOK || (oldOK && oldCfg.DataDir != newCfg.DataDir) {
+			delete(f.approvals, agentID)
+		}
+	}
+
+	// Invalidate snapshot stores for deleted agents or agents whose
+	// workspace, data directory or snapshot settings changed.
+	for agentID := range f.snapshots {
+		oldCfg, oldOK := old.AgentConfig(agentID)
+		newCfg, newOK := newRegistry.AgentConfig(agentID)
+		if !newOK || (oldOK && (oldCfg.DataDir != newCfg.DataDir ||
+			oldCfg.Workspace != newCfg.Workspace ||
+			!reflect.DeepEqual(oldCfg.Snapshots, newCfg.Snapshots))) {
+			delete(f.snapshots, agentID)
+		}
+	}
+
 	// Invalidate stores cache: remove entries for deleted agents, agents
 	// whose DataDir changed, or agents whose memory enabled state changed.
 	for agentID := range f.stores {
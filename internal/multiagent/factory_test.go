@@ -583,6 +583,72 @@ func TestFactory_ResolveFactStore_UnknownAgent(t *testing.T) {
 	}
 }
 
+func TestFactory_ForMCP(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	agents := map[string]AgentConfig{
+		"bot": {
+			DataDir: filepath.Join(tmpDir, "agents", "bot"),
+			Tools:   []string{"read_file"},
+			Routing: RoutingConfig{Default: true},
+		},
+		"chat": {
+			DataDir:  filepath.Join(tmpDir, "agents", "chat"),
+			Approval: ApprovalConfig{Chat: &ApprovalChat{Channel: "telegram", ChatID: "42"}},
+		},
+	}
+	ResolveDefaults(agents, tmpDir)
+	if err := EnsureDirectories(agents); err != nil {
+		t.Fatalf("EnsureDirectories: %v", err)
+	}
+	reg, err := NewRegistry(agents, []string{"bot", "chat"})
+	if err != nil {
+		t.Fatalf("NewRegistry: %v", err)
+	}
+
+	factory := NewFactory(FactoryConfig{
+		Registry:    reg,
+		GlobalTools: newGlobalTools(t, "read_file", "exec"),
+		Logger:      slog.Default(),
+	})
+	defer func() { _ = factory.Close() }()
+
+	var prompted message.InboundMessage
+	factory.SetApprovalRequester(func(msg message.InboundMessage) tool.ApprovalRequester {
+		prompted = msg
+		return nil
+	})
+
+	served, err := factory.ForMCP("bot")
+	if err != nil {
+		t.Fatalf("ForMCP: %v", err)
+	}
+	if names := served.Tools.Names(); len(names) != 1 || names[0] != "read_file" {
+		t.Errorf("tools = %v, want [read_file]", names)
+	}
+	if served.Requester != nil {
+		t.Error("expected no requester without approval.chat")
+	}
+	if served.History == nil || served.Facts == nil {
+		t.Error("expected memory stores")
+	}
+	if env := served.Env("read_file"); env.SessionID != "mcp:bot" || env.DataDir == "" {
+		t.Errorf("env = %+v", env)
+	}
+
+	if _, err := factory.ForMCP("chat"); err != nil {
+		t.Fatalf("ForMCP(chat): %v", err)
+	}
+	if prompted.Channel != "telegram" || prompted.Chat.ID != "42" {
+		t.Errorf("approval chat = %+v", prompted)
+	}
+
+	if _, err := factory.ForMCP("ghost"); !errors.Is(err, ErrAgentNotFound) {
+		t.Errorf("ForMCP(ghost) error = %v, want ErrAgentNotFound", err)
+	}
+}
+
 func TestFactory_Close(t *testing.T) {
 	t.Parallel()
 
@@ -849,6 +915,45 @@ func TestFactory_ResolveSkills_MergesGlobalAndPerAgent(t *testing.T) {
 	}
 }
 
+func TestFactory_ResolveSkillSections_SplitsAlwaysAndTriggered(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	globalDir := filepath.Join(tmpDir, "skills")
+	agentDataDir := filepath.Join(tmpDir, "agents", "bot")
+
+	writeSkillFile(t, globalDir, "always-skill")
+	content := "---\nname: deploy-skill\ntrigger: auto\nkeywords: [deploy]\n---\nDeploy body.\n"
+	if err := os.WriteFile(filepath.Join(globalDir, "deploy-skill.md"), []byte(content), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	reg, err := NewRegistry(map[string]AgentConfig{
+		"bot": {DataDir: agentDataDir, Routing: RoutingConfig{Default: true}},
+	}, []string{"bot"})
+	if err != nil {
+		t.Fatalf("NewRegistry: %v", err)
+	}
+
+	factory := NewFactory(FactoryConfig{
+		Registry:        reg,
+		GlobalTools:     newGlobalTools(t, "search"),
+		Logger:          slog.Default(),
+		GlobalSkillsDir: globalDir,
+	})
+
+	stable, volatile, err := factory.ResolveSkillSections("bot", "please deploy")
+	if err != nil {
+		t.Fatalf("ResolveSkillSections: %v", err)
+	}
+	if !strings.Contains(stable, "<name>always-skill</name>") || strings.Contains(stable, "deploy-skill") {
+		t.Errorf("stable section:\n%s", stable)
+	}
+	if !strings.Contains(volatile, "<name>deploy-skill</name>") || strings.Contains(volatile, "always-skill") {
+		t.Errorf("volatile section:\n%s", volatile)
+	}
+}
+
 func TestFactory_ResolveSkills_ExcludeSkillsFiltersGlobal(t *testing.T) {
 	t.Parallel()
 
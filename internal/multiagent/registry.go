@@ -85,29 +85,45 @@ func NewRegistry(agents map[string]AgentConfig, order []string) (*Registry, erro
 // Resolve returns the agent ID that should handle the given inbound message.
 // It follows the cascade: user -> thread -> group -> channel -> default -> ErrNoMatchingAgent.
 func (r *Registry) Resolve(msg message.InboundMessage) (string, error) {
+	id, _, err := r.ResolveRule(msg)
+	return id, err
+}
+
+// Routing rules reported by ResolveRule.
+const (
+	RuleUser    = "user"
+	RuleThread  = "thread"
+	RuleGroup   = "group"
+	RuleChannel = "channel"
+	RuleDefault = "default"
+)
+
+// ResolveRule is like Resolve but also returns the routing rule that
+// selected the agent.
+func (r *Registry) ResolveRule(msg message.InboundMessage) (id, rule string, err error) {
 	// Priority 1: user filter
 	if indices, ok := r.userIndex[msg.Sender.ID]; ok && len(indices) > 0 {
-		return r.agents[indices[0]].ID, nil
+		return r.agents[indices[0]].ID, RuleUser, nil
 	}
 	// Priority 2: thread/topic filter
 	if msg.ThreadID != "" {
 		if indices, ok := r.threadIndex[threadRouteKey{ChatID: msg.Chat.ID, ThreadID: msg.ThreadID}]; ok && len(indices) > 0 {
-			return r.agents[indices[0]].ID, nil
+			return r.agents[indices[0]].ID, RuleThread, nil
 		}
 	}
 	// Priority 3: group filter
 	if indices, ok := r.groupIndex[msg.Chat.ID]; ok && len(indices) > 0 {
-		return r.agents[indices[0]].ID, nil
+		return r.agents[indices[0]].ID, RuleGroup, nil
 	}
 	// Priority 4: channel filter
 	if indices, ok := r.channelIndex[msg.Channel]; ok && len(indices) > 0 {
-		return r.agents[indices[0]].ID, nil
+		return r.agents[indices[0]].ID, RuleChannel, nil
 	}
 	// Priority 5: default
 	if r.defaultAgent != "" {
-		return r.defaultAgent, nil
+		return r.defaultAgent, RuleDefault, nil
 	}
-	return "", ErrNoMatchingAgent
+	return "", "", ErrNoMatchingAgent
 }
 
 // AgentConfig returns the configuration for the agent with the given ID.
@@ -380,3 +380,32 @@ func TestRegistry_AgentIDs(t *testing.T) {
 		}
 	}
 }
+
+func TestRegistry_ResolveRule(t *testing.T) {
+	t.Parallel()
+
+	agents := map[string]AgentConfig{
+		"support": {Routing: RoutingConfig{Users: []string{"user1"}}},
+		"ops":     {Routing: RoutingConfig{Channels: []string{"slack"}}},
+		"main":    {Routing: RoutingConfig{Default: true}},
+	}
+	reg, err := NewRegistry(agents, []string{"support", "ops", "main"})
+	if err != nil {
+		t.Fatalf("NewRegistry() error = %v", err)
+	}
+
+	tests := []struct {
+		msg      message.InboundMessage
+		id, rule string
+	}{
+		{message.InboundMessage{Channel: "telegram", Sender: message.Sender{ID: "user1"}}, "support", RuleUser},
+		{message.InboundMessage{Channel: "slack", Sender: message.Sender{ID: "user2"}}, "ops", RuleChannel},
+		{message.InboundMessage{Channel: "telegram", Sender: message.Sender{ID: "user2"}}, "main", RuleDefault},
+	}
+	for _, tt := range tests {
+		id, rule, err := reg.ResolveRule(tt.msg)
+		if err != nil || id != tt.id || rule != tt.rule {
+			t.Errorf("ResolveRule(%s/%s) = %q, %q, %v; want %q, %q", tt.msg.Channel, tt.msg.Sender.ID, id, rule, err, tt.id, tt.rule)
+		}
+	}
+}
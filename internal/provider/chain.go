@@ -9,6 +9,8 @@ import (
 	"log/slog"
 	"sync"
 	"time"
+
+	"github.com/flemzord/sclaw/internal/activity"
 )
 
 // nopHandler is a slog.Handler that discards all log records.
@@ -102,12 +104,19 @@ func WithLogger(l *slog.Logger) ChainOption {
 	return func(c *Chain) { c.logger = l }
 }
 
+// WithActivity publishes a provider_failover event each time a provider
+// fails and the next one is tried.
+func WithActivity(b *activity.Bus) ChainOption {
+	return func(c *Chain) { c.activity = b }
+}
+
 // Chain orchestrates failover across multiple providers.
 // It is NOT itself a Provider — it adds role-based routing and
 // health-aware failover on top.
 type Chain struct {
-	entries []chainEntry
-	logger  *slog.Logger
+	entries  []chainEntry
+	logger   *slog.Logger
+	activity *activity.Bus
 
 	mu     sync.Mutex
 	cancel context.CancelFunc
@@ -242,6 +251,10 @@ func (pc *Chain) Complete(ctx context.Context, role Role, req CompletionRequest)
 			"provider", e.Name,
 			"error", err,
 		)
+		pc.activity.Publish(ctx, activity.Event{
+			Type: activity.TypeProviderFailover,
+			Data: map[string]any{"provider": e.Name, "role": string(role), "error": err.Error()},
+		})
 	}
 
 	if lastErr != nil {
@@ -299,6 +312,10 @@ func (pc *Chain) Stream(ctx context.Context, role Role, req CompletionRequest) (
 			"provider", e.Name,
 			"error", err,
 		)
+		pc.activity.Publish(ctx, activity.Event{
+			Type: activity.TypeProviderFailover,
+			Data: map[string]any{"provider": e.Name, "role": string(role), "error": err.Error()},
+		})
 	}
 
 	if lastErr != nil {
@@ -11,6 +11,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/flemzord/sclaw/internal/activity"
 	"github.com/flemzord/sclaw/internal/provider"
 	"github.com/flemzord/sclaw/internal/provider/providertest"
 )
@@ -1293,3 +1294,29 @@ func TestChain_HealthReport_WithFailures(t *testing.T) {
 		t.Errorf("report[1].State = %q, want %q", report[1].State, "healthy")
 	}
 }
+
+func TestProviderChain_FailoverActivity(t *testing.T) {
+	t.Parallel()
+
+	bus := activity.NewBus()
+	sub := bus.Subscribe(activity.Filter{}, 4)
+	defer sub.Close()
+
+	chain, err := provider.NewChain([]provider.ChainEntry{
+		{Name: "p1", Provider: failProvider(provider.ErrProviderDown), Role: provider.RolePrimary},
+		{Name: "p2", Provider: okProvider("p2"), Role: provider.RolePrimary},
+	}, provider.WithActivity(bus))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := chain.Complete(context.Background(), provider.RolePrimary, provider.CompletionRequest{}); err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	if len(sub.Events()) != 1 {
+		t.Fatalf("got %d events, want 1", len(sub.Events()))
+	}
+	if e := <-sub.Events(); e.Type != activity.TypeProviderFailover || e.Data["provider"] != "p1" {
+		t.Errorf("event = %+v, want a failover from p1", e)
+	}
+}
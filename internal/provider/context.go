@@ -0,0 +1,18 @@
+package provider
+
+import "context"
+
+// agentIDKey is the context key carrying the ID of the agent issuing a request.
+type agentIDKey struct{}
+
+// WithAgentID returns a context carrying the ID of the agent on whose behalf
+// provider requests are made. Providers may use it for routing decisions.
+func WithAgentID(ctx context.Context, agentID string) context.Context {
+	return context.WithValue(ctx, agentIDKey{}, agentID)
+}
+
+// AgentIDFromContext returns the agent ID set by WithAgentID, or "".
+func AgentIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(agentIDKey{}).(string)
+	return id
+}
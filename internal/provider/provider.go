@@ -28,3 +28,17 @@ type Provider interface {
 type HealthChecker interface {
 	HealthCheck(ctx context.Context) error
 }
+
+// TokenizerNamer is an optional interface that providers may implement to
+// name the BPE encoding used by their model (e.g. "cl100k_base"), so that
+// token estimation does not have to infer it from the model name.
+type TokenizerNamer interface {
+	TokenizerName() string
+}
+
+// RoutingProvider is an optional interface for providers that dispatch each
+// request to one of several other providers. Targets returns every provider
+// that may serve a request.
+type RoutingProvider interface {
+	Targets() []Provider
+}
@@ -0,0 +1,462 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// ErrInvalidRoute indicates a Router configuration error.
+var ErrInvalidRoute = errors.New("provider: invalid route")
+
+// RouteTarget is a provider the Router can dispatch requests to.
+type RouteTarget struct {
+	Name     string
+	Provider Provider
+
+	// Role marks the target's purpose. The classifier uses the RoleInternal
+	// target unless RouteClassifier.Target names one explicitly.
+	Role Role
+}
+
+// RouteRule selects a target when all of its non-zero conditions match.
+// Message length and keywords are evaluated against the last user message.
+type RouteRule struct {
+	Name   string
+	Target string
+
+	// HasImages, when set, matches requests with (true) or without (false)
+	// image content in the last user message.
+	HasImages *bool
+
+	// MinTools / MaxTools bound the number of tool definitions offered.
+	MinTools int
+	MaxTools *int
+
+	// MinChars / MaxChars bound the length of the last user message.
+	MinChars int
+	MaxChars *int
+
+	// Keywords match case-insensitively anywhere in the last user message.
+	Keywords []string
+
+	// Agents restricts the rule to requests made on behalf of these agents
+	// (see WithAgentID).
+	Agents []string
+
+	// TimeWindow restricts the rule to a local time range "HH:MM-HH:MM".
+	// Windows ending before they start wrap around midnight.
+	TimeWindow string
+
+	window *timeWindow
+}
+
+// RouteClassifier asks a small model to label the request when no rule
+// matches, then maps the label to a target.
+type RouteClassifier struct {
+	// Target is the provider used for classification. Defaults to the
+	// target with RoleInternal.
+	Target string
+
+	// Labels maps classifier labels (e.g. "easy", "hard") to target names.
+	Labels map[string]string
+
+	// Prompt overrides the default classification instructions.
+	Prompt string
+
+	// Timeout bounds the classification call. Defaults to 10s.
+	Timeout time.Duration
+}
+
+// RouterConfig configures a Router.
+type RouterConfig struct {
+	Targets    []RouteTarget
+	Default    string
+	Rules      []RouteRule
+	Classifier *RouteClassifier
+	Logger     *slog.Logger
+}
+
+// defaultClassifierTimeout bounds classification calls when unset.
+const defaultClassifierTimeout = 10 * time.Second
+
+// classifierInputLimit caps the bytes of the user message sent to the
+// classifier to keep the call cheap. The cut never splits a character.
+const classifierInputLimit = 2000
+
+// Router is a Provider that dispatches each request to one of several
+// providers, chosen by ordered rules or, failing that, by a classifier call.
+// It records the model that served the request in CompletionResponse.Model
+// and StreamChunk.Model.
+type Router struct {
+	targets    map[string]RouteTarget
+	order      []string
+	def        string
+	rules      []RouteRule
+	classifier *RouteClassifier
+	logger     *slog.Logger
+	now        func() time.Time
+
+	// lastClass memoizes the most recent classification so that the
+	// iterations of a single agent turn (same user message) classify once.
+	mu        sync.Mutex
+	lastText  string
+	lastClass [2]string // target, label
+}
+
+// Compile-time interface checks.
+var (
+	_ Provider        = (*Router)(nil)
+	_ HealthChecker   = (*Router)(nil)
+	_ TokenizerNamer  = (*Router)(nil)
+	_ RoutingProvider = (*Router)(nil)
+)
+
+// NewRouter validates cfg and creates a Router.
+func NewRouter(cfg RouterConfig) (*Router, error) {
+	if len(cfg.Targets) == 0 {
+		return nil, ErrNoProvider
+	}
+
+	r := &Router{
+		targets: make(map[string]RouteTarget, len(cfg.Targets)),
+		def:     cfg.Default,
+		logger:  cfg.Logger,
+		now:     time.Now,
+	}
+	if r.logger == nil {
+		r.logger = slog.New(nopHandler{})
+	}
+
+	var internal string
+	for _, t := range cfg.Targets {
+		if t.Name == "" || t.Provider == nil {
+			return nil, fmt.Errorf("%w: target requires a name and a provider", ErrInvalidRoute)
+		}
+		if _, dup := r.targets[t.Name]; dup {
+			return nil, fmt.Errorf("%w: duplicate target %q", ErrInvalidRoute, t.Name)
+		}
+		r.targets[t.Name] = t
+		r.order = append(r.order, t.Name)
+		if t.Role == RoleInternal && internal == "" {
+			internal = t.Name
+		}
+	}
+
+	if r.def == "" {
+		r.def = r.order[0]
+	}
+	if _, ok := r.targets[r.def]; !ok {
+		return nil, fmt.Errorf("%w: default target %q not found", ErrInvalidRoute, r.def)
+	}
+
+	r.rules = make([]RouteRule, len(cfg.Rules))
+	for i, rule := range cfg.Rules {
+		if _, ok := r.targets[rule.Target]; !ok {
+			return nil, fmt.Errorf("%w: rule #%d targets unknown provider %q", ErrInvalidRoute, i+1, rule.Target)
+		}
+		if rule.TimeWindow != "" {
+			w, err := parseTimeWindow(rule.TimeWindow)
+			if err != nil {
+				return nil, fmt.Errorf("%w: rule #%d: %w", ErrInvalidRoute, i+1, err)
+			}
+			rule.window = &w
+		}
+		r.rules[i] = rule
+	}
+
+	if c := cfg.Classifier; c != nil {
+		cls := *c
+		if cls.Target == "" {
+			cls.Target = internal
+		}
+		if _, ok := r.targets[cls.Target]; !ok {
+			return nil, fmt.Errorf("%w: classifier target %q not found (set one or mark a target as internal)", ErrInvalidRoute, cls.Target)
+		}
+		if len(cls.Labels) == 0 {
+			return nil, fmt.Errorf("%w: classifier requires at least one label", ErrInvalidRoute)
+		}
+		labels := make(map[string]string, len(cls.Labels))
+		for label, target := range cls.Labels {
+			if _, ok := r.targets[target]; !ok {
+				return nil, fmt.Errorf("%w: classifier label %q targets unknown provider %q", ErrInvalidRoute, label, target)
+			}
+			labels[strings.ToLower(label)] = target
+		}
+		cls.Labels = labels
+		if cls.Timeout <= 0 {
+			cls.Timeout = defaultClassifierTimeout
+		}
+		r.classifier = &cls
+	}
+
+	return r, nil
+}
+
+// Select returns the name of the target that should serve req and a short
+// human-readable reason for the choice.
+func (r *Router) Select(ctx context.Context, req CompletionRequest) (target, reason string) {
+	last := lastUserMessage(req.Messages)
+	text := strings.ToLower(last.TextForDisplay())
+	agentID := AgentIDFromContext(ctx)
+	now := r.now()
+
+	for i := range r.rules {
+		rule := &r.rules[i]
+		if rule.matches(last, text, len(req.Tools), agentID, now) {
+			name := rule.Name
+			if name == "" {
+				name = fmt.Sprintf("#%d", i+1)
+			}
+			return rule.Target, "rule " + name
+		}
+	}
+
+	if r.classifier != nil && text != "" {
+		target, label, err := r.classify(ctx, last.TextForDisplay())
+		if err == nil {
+			return target, "classifier " + label
+		}
+		r.logger.Warn("provider router: classification failed, using default",
+			"default", r.def, "error", err)
+	}
+
+	return r.def, "default"
+}
+
+// Complete implements Provider.
+func (r *Router) Complete(ctx context.Context, req CompletionRequest) (CompletionResponse, error) {
+	t := r.pick(ctx, req)
+	resp, err := t.Provider.Complete(ctx, req)
+	if err != nil {
+		return resp, err
+	}
+	if resp.Model == "" {
+		resp.Model = t.Provider.ModelName()
+	}
+	return resp, nil
+}
+
+// Stream implements Provider. Chunks are forwarded unchanged except that
+// Model is stamped with the serving model.
+func (r *Router) Stream(ctx context.Context, req CompletionRequest) (<-chan StreamChunk, error) {
+	t := r.pick(ctx, req)
+	src, err := t.Provider.Stream(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	model := t.Provider.ModelName()
+	out := make(chan StreamChunk, cap(src))
+	go func() {
+		defer close(out)
+		for chunk := range src {
+			if chunk.Model == "" {
+				chunk.Model = model
+			}
+			select {
+			case out <- chunk:
+			case <-ctx.Done():
+				// Drain to release the provider goroutine.
+				for range src { //nolint:revive // intentional drain
+				}
+				return
+			}
+		}
+	}()
+	return out, nil
+}
+
+// ContextWindowSize implements Provider. It returns the smallest window
+// among targets, since any of them may serve the next request.
+func (r *Router) ContextWindowSize() int {
+	size := 0
+	for _, name := range r.order {
+		if w := r.targets[name].Provider.ContextWindowSize(); size == 0 || (w > 0 && w < size) {
+			size = w
+		}
+	}
+	return size
+}
+
+// ModelName implements Provider. It returns the default target's model;
+// the model that actually served a request is reported per response.
+func (r *Router) ModelName() string {
+	return r.targets[r.def].Provider.ModelName()
+}
+
+// TokenizerName implements TokenizerNamer for the default target.
+func (r *Router) TokenizerName() string {
+	if tn, ok := r.targets[r.def].Provider.(TokenizerNamer); ok {
+		return tn.TokenizerName()
+	}
+	return ""
+}
+
+// Targets implements RoutingProvider.
+func (r *Router) Targets() []Provider {
+	providers := make([]Provider, len(r.order))
+	for i, name := range r.order {
+		providers[i] = r.targets[name].Provider
+	}
+	return providers
+}
+
+// HealthCheck implements HealthChecker. The router is healthy when its
+// default target is: health-checkable targets other than the default are
+// not probed.
+func (r *Router) HealthCheck(ctx context.Context) error {
+	if hc, ok := r.targets[r.def].Provider.(HealthChecker); ok {
+		return hc.HealthCheck(ctx)
+	}
+	return nil
+}
+
+// pick selects and logs the target for req.
+func (r *Router) pick(ctx context.Context, req CompletionRequest) RouteTarget {
+	name, reason := r.Select(ctx, req)
+	t := r.targets[name]
+	r.logger.Debug("provider router: routed request",
+		"target", name, "model", t.Provider.ModelName(), "reason", reason)
+	return t
+}
+
+// classify asks the classifier target for a label and maps it to a target.
+func (r *Router) classify(ctx context.Context, text string) (target, label string, err error) {
+	r.mu.Lock()
+	if r.lastText != "" && r.lastText == text {
+		target, label = r.lastClass[0], r.lastClass[1]
+		r.mu.Unlock()
+		return target, label, nil
+	}
+	r.mu.Unlock()
+
+	c := r.classifier
+	ctx, cancel := context.WithTimeout(ctx, c.Timeout)
+	defer cancel()
+
+	key := text
+
+	if len(text) > classifierInputLimit {
+		cut := classifierInputLimit
+		for cut > 0 && !utf8.RuneStart(text[cut]) {
+			cut--
+		}
+		text = text[:cut]
+	}
+
+	prompt := c.Prompt
+	if prompt == "" {
+		labels := slices.Sorted(maps.Keys(c.Labels))
+		prompt = "Classify the difficulty of the user's request for an AI assistant. " +
+			"Answer with exactly one word among: " + strings.Join(labels, ", ") + "."
+	}
+
+	resp, err := r.targets[c.Target].Provider.Complete(ctx, CompletionRequest{
+		Messages: []LLMMessage{
+			{Role: MessageRoleSystem, Content: prompt},
+			{Role: MessageRoleUser, Content: text},
+		},
+		MaxTokens: 5,
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	answer := strings.ToLower(strings.TrimSpace(resp.Content))
+	answer = strings.Trim(strings.SplitN(answer, " ", 2)[0], ".,:;!\"'`")
+	target, ok := c.Labels[answer]
+	if !ok {
+		return "", "", fmt.Errorf("provider router: unknown classifier label %q", answer)
+	}
+
+	r.mu.Lock()
+	r.lastText, r.lastClass = key, [2]string{target, answer}
+	r.mu.Unlock()
+	return target, answer, nil
+}
+
+// matches reports whether every condition set on the rule holds.
+func (rule *RouteRule) matches(last LLMMessage, lowerText string, toolCount int, agentID string, now time.Time) bool {
+	if rule.HasImages != nil && last.HasImages() != *rule.HasImages {
+		return false
+	}
+	if toolCount < rule.MinTools || (rule.MaxTools != nil && toolCount > *rule.MaxTools) {
+		return false
+	}
+	n := len([]rune(lowerText))
+	if n < rule.MinChars || (rule.MaxChars != nil && n > *rule.MaxChars) {
+		return false
+	}
+	if len(rule.Keywords) > 0 && !containsAny(lowerText, rule.Keywords) {
+		return false
+	}
+	if len(rule.Agents) > 0 && !slices.Contains(rule.Agents, agentID) {
+		return false
+	}
+	if rule.window != nil && !rule.window.contains(now) {
+		return false
+	}
+	return true
+}
+
+// lastUserMessage returns the most recent user message, or a zero message.
+func lastUserMessage(msgs []LLMMessage) LLMMessage {
+	for i := len(msgs) - 1; i >= 0; i-- {
+		if msgs[i].Role == MessageRoleUser {
+			return msgs[i]
+		}
+	}
+	return LLMMessage{}
+}
+
+func containsAny(lowerText string, keywords []string) bool {
+	for _, kw := range keywords {
+		if kw != "" && strings.Contains(lowerText, strings.ToLower(kw)) {
+			return true
+		}
+	}
+	return false
+}
+
+// timeWindow is a daily local time range in minutes since midnight.
+type timeWindow struct {
+	from, to int
+}
+
+// parseTimeWindow parses "HH:MM-HH:MM".
+func parseTimeWindow(s string) (timeWindow, error) {
+	fromStr, toStr, ok := strings.Cut(s, "-")
+	if !ok {
+		return timeWindow{}, fmt.Errorf("time window %q: expected HH:MM-HH:MM", s)
+	}
+	from, err := time.Parse("15:04", strings.TrimSpace(fromStr))
+	if err != nil {
+		return timeWindow{}, fmt.Errorf("time window %q: %w", s, err)
+	}
+	to, err := time.Parse("15:04", strings.TrimSpace(toStr))
+	if err != nil {
+		return timeWindow{}, fmt.Errorf("time window %q: %w", s, err)
+	}
+	return timeWindow{
+		from: from.Hour()*60 + from.Minute(),
+		to:   to.Hour()*60 + to.Minute(),
+	}, nil
+}
+
+// contains reports whether t falls in the window (start inclusive, end
+// exclusive), wrapping around midnight when to < from.
+func (w timeWindow) contains(t time.Time) bool {
+	m := t.Hour()*60 + t.Minute()
+	if w.from <= w.to {
+		return m >= w.from && m < w.to
+	}
+	return m >= w.from || m < w.to
+}
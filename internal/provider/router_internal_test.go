@@ -0,0 +1,71 @@
+package provider
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestTimeWindow_Contains(t *testing.T) {
+	t.Parallel()
+
+	at := func(h, m int) time.Time { return time.Date(2026, 1, 1, h, m, 0, 0, time.Local) }
+	tests := []struct {
+		window string
+		t      time.Time
+		want   bool
+	}{
+		{"09:00-17:00", at(9, 0), true},
+		{"09:00-17:00", at(16, 59), true},
+		{"09:00-17:00", at(17, 0), false},
+		{"09:00-17:00", at(8, 59), false},
+		{"22:00-06:00", at(23, 30), true},
+		{"22:00-06:00", at(5, 59), true},
+		{"22:00-06:00", at(12, 0), false},
+	}
+	for _, tt := range tests {
+		w, err := parseTimeWindow(tt.window)
+		if err != nil {
+			t.Fatalf("parseTimeWindow(%q): %v", tt.window, err)
+		}
+		if got := w.contains(tt.t); got != tt.want {
+			t.Errorf("%s contains %s = %v, want %v", tt.window, tt.t.Format("15:04"), got, tt.want)
+		}
+	}
+}
+
+func TestRouter_TimeWindowRule(t *testing.T) {
+	t.Parallel()
+
+	p := stubProvider{}
+	r, err := NewRouter(RouterConfig{
+		Targets: []RouteTarget{{Name: "day", Provider: p}, {Name: "night", Provider: p}},
+		Rules:   []RouteRule{{Name: "night", Target: "night", TimeWindow: "22:00-06:00"}},
+	})
+	if err != nil {
+		t.Fatalf("NewRouter: %v", err)
+	}
+
+	r.now = func() time.Time { return time.Date(2026, 1, 1, 23, 0, 0, 0, time.Local) }
+	if target, _ := r.Select(context.Background(), CompletionRequest{}); target != "night" {
+		t.Errorf("Select at 23:00 = %q, want night", target)
+	}
+	r.now = func() time.Time { return time.Date(2026, 1, 1, 12, 0, 0, 0, time.Local) }
+	if target, _ := r.Select(context.Background(), CompletionRequest{}); target != "day" {
+		t.Errorf("Select at 12:00 = %q, want day", target)
+	}
+}
+
+// stubProvider is a minimal Provider for internal tests.
+type stubProvider struct{}
+
+func (stubProvider) Complete(context.Context, CompletionRequest) (CompletionResponse, error) {
+	return CompletionResponse{}, nil
+}
+
+func (stubProvider) Stream(context.Context, CompletionRequest) (<-chan StreamChunk, error) {
+	return nil, nil
+}
+
+func (stubProvider) ContextWindowSize() int { return 0 }
+func (stubProvider) ModelName() string      { return "stub" }
@@ -0,0 +1,325 @@
+package provider_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/flemzord/sclaw/internal/provider"
+	"github.com/flemzord/sclaw/internal/provider/providertest"
+)
+
+// routeMock returns a provider that answers with its own model name.
+func routeMock(model string) *providertest.MockProvider {
+	return &providertest.MockProvider{
+		CompleteFunc: func(_ context.Context, _ provider.CompletionRequest) (provider.CompletionResponse, error) {
+			return provider.CompletionResponse{Content: "from " + model}, nil
+		},
+		StreamFunc: func(_ context.Context, _ provider.CompletionRequest) (<-chan provider.StreamChunk, error) {
+			ch := make(chan provider.StreamChunk, 2)
+			ch <- provider.StreamChunk{Content: "from " + model}
+			ch <- provider.StreamChunk{FinishReason: provider.FinishReasonStop}
+			close(ch)
+			return ch, nil
+		},
+		ContextWindowSizeFunc: func() int { return 128000 },
+		ModelNameFunc:         func() string { return model },
+		HealthCheckFunc:       func(_ context.Context) error { return nil },
+	}
+}
+
+func userRequest(text string) provider.CompletionRequest {
+	return provider.CompletionRequest{
+		Messages: []provider.LLMMessage{{Role: provider.MessageRoleUser, Content: text}},
+	}
+}
+
+func intPtr(n int) *int { return &n }
+
+func TestRouter_Select_Rules(t *testing.T) {
+	t.Parallel()
+
+	yes := true
+	r, err := provider.NewRouter(provider.RouterConfig{
+		Targets: []provider.RouteTarget{
+			{Name: "cheap", Provider: routeMock("small")},
+			{Name: "vision", Provider: routeMock("vision")},
+			{Name: "tools", Provider: routeMock("tools")},
+			{Name: "code", Provider: routeMock("code")},
+			{Name: "ops", Provider: routeMock("ops")},
+			{Name: "long", Provider: routeMock("long")},
+		},
+		Rules: []provider.RouteRule{
+			{Name: "images", Target: "vision", HasImages: &yes},
+			{Target: "tools", MinTools: 3},
+			{Name: "code", Target: "code", Keywords: []string{"Refactor", "stack trace"}},
+			{Name: "ops", Target: "ops", Agents: []string{"ops-bot"}},
+			{Name: "long", Target: "long", MinChars: 50},
+			{Name: "short", Target: "cheap", MaxChars: intPtr(10)},
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewRouter: %v", err)
+	}
+
+	tools := make([]provider.ToolDefinition, 3)
+	imageReq := provider.CompletionRequest{Messages: []provider.LLMMessage{{
+		Role: provider.MessageRoleUser,
+		ContentParts: []provider.ContentPart{
+			{Type: provider.ContentPartText, Text: "what is this?"},
+			{Type: provider.ContentPartImageURL, ImageURL: &provider.ImageURL{URL: "https://x/img.png"}},
+		},
+	}}}
+
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		req        provider.CompletionRequest
+		wantTarget string
+		wantReason string
+	}{
+		{"images", context.Background(), imageReq, "vision", "rule images"},
+		{"unnamed rule", context.Background(), provider.CompletionRequest{Messages: userRequest("hello there friend").Messages, Tools: tools}, "tools", "rule #2"},
+		{"keyword case-insensitive", context.Background(), userRequest("please REFACTOR this function"), "code", "rule code"},
+		{"agent", provider.WithAgentID(context.Background(), "ops-bot"), userRequest("restart the service"), "ops", "rule ops"},
+		{"agent mismatch", provider.WithAgentID(context.Background(), "other"), userRequest("restart the service"), "cheap", "default"},
+		{"long", context.Background(), userRequest(strings.Repeat("a", 60)), "long", "rule long"},
+		{"short", context.Background(), userRequest("hi"), "cheap", "rule short"},
+		{"no match", context.Background(), userRequest("medium sized message"), "cheap", "default"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			target, reason := r.Select(tt.ctx, tt.req)
+			if target != tt.wantTarget || reason != tt.wantReason {
+				t.Errorf("Select = (%q, %q), want (%q, %q)", target, reason, tt.wantTarget, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestRouter_Select_Classifier(t *testing.T) {
+	t.Parallel()
+
+	classifier := routeMock("tiny")
+	classifier.CompleteFunc = func(_ context.Context, req provider.CompletionRequest) (provider.CompletionResponse, error) {
+		if req.MaxTokens == 0 || req.Messages[0].Role != provider.MessageRoleSystem {
+			t.Errorf("unexpected classifier request: %+v", req)
+		}
+		if strings.Contains(req.Messages[1].Content, "prove") {
+			return provider.CompletionResponse{Content: " Hard."}, nil
+		}
+		return provider.CompletionResponse{Content: "easy"}, nil
+	}
+
+	r, err := provider.NewRouter(provider.RouterConfig{
+		Targets: []provider.RouteTarget{
+			{Name: "small", Provider: routeMock("small")},
+			{Name: "big", Provider: routeMock("big")},
+			{Name: "internal", Provider: classifier, Role: provider.RoleInternal},
+		},
+		Classifier: &provider.RouteClassifier{
+			Labels: map[string]string{"easy": "small", "HARD": "big"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewRouter: %v", err)
+	}
+
+	ctx := context.Background()
+	if target, reason := r.Select(ctx, userRequest("prove the Riemann hypothesis")); target != "big" || reason != "classifier hard" {
+		t.Errorf("Select = (%q, %q), want (big, classifier hard)", target, reason)
+	}
+	// Same message again (e.g. next tool-loop iteration): memoized.
+	r.Select(ctx, userRequest("prove the Riemann hypothesis"))
+	if classifier.CompleteCalls != 1 {
+		t.Errorf("classifier calls = %d, want 1", classifier.CompleteCalls)
+	}
+	if target, _ := r.Select(ctx, userRequest("what time is it")); target != "small" {
+		t.Errorf("Select = %q, want small", target)
+	}
+}
+
+func TestRouter_Select_ClassifierTruncatesOnRuneBoundary(t *testing.T) {
+	t.Parallel()
+
+	var got string
+	classifier := routeMock("tiny")
+	classifier.CompleteFunc = func(_ context.Context, req provider.CompletionRequest) (provider.CompletionResponse, error) {
+		got = req.Messages[1].Content
+		return provider.CompletionResponse{Content: "easy"}, nil
+	}
+
+	r, err := provider.NewRouter(provider.RouterConfig{
+		Targets: []provider.RouteTarget{
+			{Name: "small", Provider: routeMock("small")},
+			{Name: "internal", Provider: classifier, Role: provider.RoleInternal},
+		},
+		Classifier: &provider.RouteClassifier{Labels: map[string]string{"easy": "small"}},
+	})
+	if err != nil {
+		t.Fatalf("NewRouter: %v", err)
+	}
+
+	// The odd prefix puts the byte limit in the middle of a two-byte rune.
+	text := "a" + strings.Repeat("é", 2000)
+	r.Select(context.Background(), userRequest(text))
+	if got == "" || len(got) >= len(text) || !utf8.ValidString(got) || !strings.HasPrefix(text, got) {
+		t.Errorf("classifier input = %d bytes, valid UTF-8 %v", len(got), utf8.ValidString(got))
+	}
+}
+
+func TestRouter_Select_ClassifierFailureUsesDefault(t *testing.T) {
+	t.Parallel()
+
+	classifier := routeMock("tiny")
+	classifier.CompleteFunc = func(_ context.Context, _ provider.CompletionRequest) (provider.CompletionResponse, error) {
+		return provider.CompletionResponse{}, errors.New("boom")
+	}
+	unknown := routeMock("tiny")
+	unknown.CompleteFunc = func(_ context.Context, _ provider.CompletionRequest) (provider.CompletionResponse, error) {
+		return provider.CompletionResponse{Content: "maybe"}, nil
+	}
+
+	for name, cls := range map[string]*providertest.MockProvider{"error": classifier, "unknown label": unknown} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			r, err := provider.NewRouter(provider.RouterConfig{
+				Targets: []provider.RouteTarget{
+					{Name: "small", Provider: routeMock("small")},
+					{Name: "big", Provider: routeMock("big")},
+					{Name: "cls", Provider: cls},
+				},
+				Default:    "big",
+				Classifier: &provider.RouteClassifier{Target: "cls", Labels: map[string]string{"easy": "small"}},
+			})
+			if err != nil {
+				t.Fatalf("NewRouter: %v", err)
+			}
+			if target, reason := r.Select(context.Background(), userRequest("hello")); target != "big" || reason != "default" {
+				t.Errorf("Select = (%q, %q), want (big, default)", target, reason)
+			}
+		})
+	}
+}
+
+func TestRouter_CompleteRecordsModel(t *testing.T) {
+	t.Parallel()
+
+	r, err := provider.NewRouter(provider.RouterConfig{
+		Targets: []provider.RouteTarget{
+			{Name: "small", Provider: routeMock("small-model")},
+			{Name: "big", Provider: routeMock("big-model")},
+		},
+		Rules: []provider.RouteRule{{Target: "big", Keywords: []string{"think"}}},
+	})
+	if err != nil {
+		t.Fatalf("NewRouter: %v", err)
+	}
+
+	resp, err := r.Complete(context.Background(), userRequest("think hard"))
+	if err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	if resp.Model != "big-model" || resp.Content != "from big-model" {
+		t.Errorf("resp = %+v, want big-model", resp)
+	}
+	if r.ModelName() != "small-model" {
+		t.Errorf("ModelName = %q, want default target model", r.ModelName())
+	}
+}
+
+func TestRouter_StreamRecordsModel(t *testing.T) {
+	t.Parallel()
+
+	r, err := provider.NewRouter(provider.RouterConfig{
+		Targets: []provider.RouteTarget{
+			{Name: "small", Provider: routeMock("small-model")},
+			{Name: "big", Provider: routeMock("big-model")},
+		},
+		Default: "big",
+	})
+	if err != nil {
+		t.Fatalf("NewRouter: %v", err)
+	}
+
+	ch, err := r.Stream(context.Background(), userRequest("hello"))
+	if err != nil {
+		t.Fatalf("Stream: %v", err)
+	}
+	var n int
+	for chunk := range ch {
+		n++
+		if chunk.Model != "big-model" {
+			t.Errorf("chunk.Model = %q, want big-model", chunk.Model)
+		}
+	}
+	if n != 2 {
+		t.Errorf("chunks = %d, want 2", n)
+	}
+}
+
+func TestRouter_ContextWindowSizeIsMinimum(t *testing.T) {
+	t.Parallel()
+
+	small := routeMock("small")
+	small.ContextWindowSizeFunc = func() int { return 8000 }
+	r, err := provider.NewRouter(provider.RouterConfig{
+		Targets: []provider.RouteTarget{
+			{Name: "big", Provider: routeMock("big")},
+			{Name: "small", Provider: small},
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewRouter: %v", err)
+	}
+	if got := r.ContextWindowSize(); got != 8000 {
+		t.Errorf("ContextWindowSize = %d, want 8000", got)
+	}
+}
+
+func TestNewRouter_Validation(t *testing.T) {
+	t.Parallel()
+
+	a := provider.RouteTarget{Name: "a", Provider: routeMock("a")}
+	tests := []struct {
+		name string
+		cfg  provider.RouterConfig
+	}{
+		{"duplicate target", provider.RouterConfig{Targets: []provider.RouteTarget{a, a}}},
+		{"missing provider", provider.RouterConfig{Targets: []provider.RouteTarget{{Name: "x"}}}},
+		{"unknown default", provider.RouterConfig{Targets: []provider.RouteTarget{a}, Default: "b"}},
+		{"unknown rule target", provider.RouterConfig{
+			Targets: []provider.RouteTarget{a},
+			Rules:   []provider.RouteRule{{Target: "b"}},
+		}},
+		{"bad time window", provider.RouterConfig{
+			Targets: []provider.RouteTarget{a},
+			Rules:   []provider.RouteRule{{Target: "a", TimeWindow: "9h-17h"}},
+		}},
+		{"classifier without internal target", provider.RouterConfig{
+			Targets:    []provider.RouteTarget{a},
+			Classifier: &provider.RouteClassifier{Labels: map[string]string{"easy": "a"}},
+		}},
+		{"classifier unknown label target", provider.RouterConfig{
+			Targets:    []provider.RouteTarget{a},
+			Classifier: &provider.RouteClassifier{Target: "a", Labels: map[string]string{"easy": "b"}},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if _, err := provider.NewRouter(tt.cfg); !errors.Is(err, provider.ErrInvalidRoute) {
+				t.Errorf("NewRouter error = %v, want ErrInvalidRoute", err)
+			}
+		})
+	}
+
+	if _, err := provider.NewRouter(provider.RouterConfig{}); !errors.Is(err, provider.ErrNoProvider) {
+		t.Errorf("empty config error = %v, want ErrNoProvider", err)
+	}
+}
@@ -59,6 +59,17 @@ type ImageURL struct {
 	Detail string `json:"detail,omitempty"` // "auto", "low", "high"
 }
 
+// CacheControl is a provider-agnostic prompt caching hint. A message carrying
+// a non-empty CacheControl marks the end of a stable prefix that providers
+// supporting explicit cache breakpoints may cache. Providers with automatic
+// prefix caching ignore it.
+type CacheControl string
+
+// CacheControl constants for prompt caching hints.
+const (
+	CacheControlEphemeral CacheControl = "ephemeral"
+)
+
 // LLMMessage represents a single message in a conversation.
 // When ContentParts is non-nil it is the source of truth for message content;
 // Content remains empty. Text-only messages use Content alone (ContentParts nil).
@@ -70,6 +81,7 @@ type LLMMessage struct {
 	ToolID       string        `json:"tool_id,omitempty"`
 	ToolCalls    []ToolCall    `json:"tool_calls,omitempty"`
 	IsError      bool          `json:"is_error,omitempty"`
+	CacheControl CacheControl  `json:"cache_control,omitempty"`
 }
 
 // TextForDisplay returns the text representation of the message content.
@@ -124,6 +136,10 @@ type CompletionRequest struct {
 	Temperature *float64         `json:"temperature,omitempty"`
 	TopP        *float64         `json:"top_p,omitempty"`
 	Stop        []string         `json:"stop,omitempty"`
+
+	// CacheKey groups requests sharing the same stable prefix so that
+	// providers routing by cache key can reuse cached prompt tokens.
+	CacheKey string `json:"cache_key,omitempty"`
 }
 
 // CompletionResponse is the output of a Provider.Complete call.
@@ -132,6 +148,10 @@ type CompletionResponse struct {
 	ToolCalls    []ToolCall   `json:"tool_calls,omitempty"`
 	FinishReason FinishReason `json:"finish_reason"`
 	Usage        TokenUsage   `json:"usage"`
+
+	// Model is the model that served the request when it may differ from
+	// Provider.ModelName (e.g. behind a routing provider). Empty otherwise.
+	Model string `json:"model,omitempty"`
 }
 
 // StreamChunk represents one piece of a streaming completion response.
@@ -140,6 +160,7 @@ type StreamChunk struct {
 	ToolCalls    []ToolCall   `json:"tool_calls,omitempty"`
 	FinishReason FinishReason `json:"finish_reason,omitempty"`
 	Usage        *TokenUsage  `json:"usage,omitempty"`
+	Model        string       `json:"model,omitempty"`
 	Err          error        `json:"-"`
 }
 
@@ -148,4 +169,8 @@ type TokenUsage struct {
 	PromptTokens     int `json:"prompt_tokens"`
 	CompletionTokens int `json:"completion_tokens"`
 	TotalTokens      int `json:"total_tokens"`
+
+	// CachedTokens is the portion of PromptTokens served from the
+	// provider's prompt cache.
+	CachedTokens int `json:"cached_tokens,omitempty"`
 }
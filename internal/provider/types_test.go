@@ -60,6 +60,9 @@ func TestLLMMessageOmitempty(t *testing.T) {
 	if _, ok := raw["is_error"]; ok {
 		t.Error("expected is_error to be omitted when false")
 	}
+	if _, ok := raw["cache_control"]; ok {
+		t.Error("expected cache_control to be omitted when empty")
+	}
 }
 
 func TestToolCallArgumentsRawMessage(t *testing.T) {
@@ -111,7 +114,7 @@ func TestCompletionRequestOmitempty(t *testing.T) {
 		t.Fatalf("unmarshal raw: %v", err)
 	}
 
-	for _, key := range []string{"tools", "max_tokens", "temperature", "top_p", "stop"} {
+	for _, key := range []string{"tools", "max_tokens", "temperature", "top_p", "stop", "cache_key"} {
 		if _, ok := raw[key]; ok {
 			t.Errorf("expected %s to be omitted when zero/nil", key)
 		}
@@ -354,3 +357,33 @@ func TestFinishReasonConstants(t *testing.T) {
 		}
 	}
 }
+
+func TestTokenUsageCachedTokensRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	usage := TokenUsage{PromptTokens: 100, CompletionTokens: 20, TotalTokens: 120, CachedTokens: 80}
+	data, err := json.Marshal(usage)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+
+	var got TokenUsage
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if got != usage {
+		t.Errorf("round-trip = %+v, want %+v", got, usage)
+	}
+
+	data, err = json.Marshal(TokenUsage{PromptTokens: 1})
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatalf("unmarshal raw: %v", err)
+	}
+	if _, ok := raw["cached_tokens"]; ok {
+		t.Error("expected cached_tokens to be omitted when zero")
+	}
+}
@@ -0,0 +1,296 @@
+package router
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/flemzord/sclaw/internal/memory"
+	"github.com/flemzord/sclaw/internal/provider"
+)
+
+// AgentChecker is optionally implemented by an AgentFactory to validate the
+// target of a session reassignment.
+type AgentChecker interface {
+	HasAgent(agentID string) bool
+}
+
+// SessionHistory is the conversation of a session as seen by administrators.
+type SessionHistory struct {
+	Messages []provider.LLMMessage
+	Summary  string
+
+	// Persistent reports whether Messages come from the agent's history
+	// store. Otherwise they are the live session's history, which only
+	// keeps the most recent messages.
+	Persistent bool
+}
+
+// SessionHistory returns the full history of the session with the given ID.
+// Message indexes used by the other administration methods refer to it.
+func (r *Router) SessionHistory(id string) (SessionHistory, error) {
+	var h SessionHistory
+	err := r.withSession(id, func(session *Session, store memory.HistoryStore) error {
+		if store == nil {
+			h.Messages = slices.Clone(session.History)
+			return nil
+		}
+		key := persistenceKey(session.Key)
+		msgs, err := store.GetAll(key)
+		if err != nil {
+			return fmt.Errorf("router: reading history: %w", err)
+		}
+		summary, err := store.GetSummary(key)
+		if err != nil {
+			return fmt.Errorf("router: reading summary: %w", err)
+		}
+		h = SessionHistory{Messages: msgs, Summary: summary, Persistent: true}
+		return nil
+	})
+	return h, err
+}
+
+// SetSessionSummary replaces the stored summary of a session.
+func (r *Router) SetSessionSummary(id, summary string) error {
+	return r.withSession(id, func(session *Session, store memory.HistoryStore) error {
+		if store == nil {
+			return ErrNoPersistentHistory
+		}
+		if err := store.SetSummary(persistenceKey(session.Key), summary); err != nil {
+			return fmt.Errorf("router: writing summary: %w", err)
+		}
+		return nil
+	})
+}
+
+// EditSessionMessage replaces the text of the message at index. Images
+// attached to the message and the arguments of its tool calls are kept:
+// a tool call can only be removed, with DeleteSessionMessage.
+func (r *Router) EditSessionMessage(id string, index int, text string) error {
+	return r.rewriteHistory(id, func(msgs []provider.LLMMessage) ([]provider.LLMMessage, error) {
+		if index < 0 || index >= len(msgs) {
+			return nil, ErrMessageNotFound
+		}
+		msgs[index] = withText(msgs[index], text)
+		return msgs, nil
+	})
+}
+
+// DeleteSessionMessage removes the message at index from the history. A
+// tool call and its results are removed together, whichever is at index:
+// providers reject a history where one is missing.
+func (r *Router) DeleteSessionMessage(id string, index int) error {
+	return r.rewriteHistory(id, func(msgs []provider.LLMMessage) ([]provider.LLMMessage, error) {
+		if index < 0 || index >= len(msgs) {
+			return nil, ErrMessageNotFound
+		}
+		return deleteMessage(msgs, index), nil
+	})
+}
+
+// deleteMessage removes the message at index, with the rest of its tool
+// exchange if it is part of one.
+func deleteMessage(msgs []provider.LLMMessage, index int) []provider.LLMMessage {
+	call := index
+	if msgs[index].Role == provider.MessageRoleTool {
+		call = toolCallIndex(msgs, index)
+	}
+	if call < 0 || len(msgs[call].ToolCalls) == 0 {
+		return slices.Delete(msgs, index, index+1)
+	}
+
+	ids := make(map[string]bool, len(msgs[call].ToolCalls))
+	for _, tc := range msgs[call].ToolCalls {
+		ids[tc.ID] = true
+	}
+	kept := msgs[:0]
+	for i, m := range msgs {
+		if i == call || (i > call && m.Role == provider.MessageRoleTool && ids[m.ToolID]) {
+			continue
+		}
+		kept = append(kept, m)
+	}
+	return kept
+}
+
+// toolCallIndex returns the index of the assistant message that made the
+// call answered by the tool message at index, or -1 if it is not in msgs.
+func toolCallIndex(msgs []provider.LLMMessage, index int) int {
+	for i := index - 1; i >= 0; i-- {
+		if slices.ContainsFunc(msgs[i].ToolCalls, func(tc provider.ToolCall) bool {
+			return tc.ID == msgs[index].ToolID
+		}) {
+			return i
+		}
+	}
+	return -1
+}
+
+// AppendSessionMessage adds msg to the end of the history. The agent sees it
+// on the session's next turn.
+func (r *Router) AppendSessionMessage(id string, msg provider.LLMMessage) error {
+	return r.withSession(id, func(session *Session, store memory.HistoryStore) error {
+		if store != nil {
+			if err := store.Append(persistenceKey(session.Key), msg); err != nil {
+				return fmt.Errorf("router: appending message: %w", err)
+			}
+		}
+		session.History = recentMessages(append(session.History, msg), r.pipeline.cfg.MaxHistoryLen)
+		return nil
+	})
+}
+
+// ReassignSession hands the session over to another agent. Its persistent
+// history moves to the new agent's store, so the conversation continues
+// where it left off.
+func (r *Router) ReassignSession(id, agentID string) error {
+	if checker, ok := r.config.AgentFactory.(AgentChecker); ok && !checker.HasAgent(agentID) {
+		return fmt.Errorf("%w: %q", ErrUnknownAgent, agentID)
+	}
+	return r.withSession(id, func(session *Session, from memory.HistoryStore) error {
+		if session.AgentID == agentID {
+			return nil
+		}
+		to := r.historyStore(agentID)
+		if to != nil && to != from {
+			if err := moveHistory(persistenceKey(session.Key), session.History, from, to); err != nil {
+				return err
+			}
+		}
+		session.AgentID = agentID
+		return nil
+	})
+}
+
+// moveHistory copies the messages and summary of a session to another
+// store, then purges them from the previous one. Without a previous store,
+// the live history is copied.
+func moveHistory(key string, live []provider.LLMMessage, from, to memory.HistoryStore) error {
+	msgs, summary := live, ""
+	if from != nil {
+		var err error
+		if msgs, err = from.GetAll(key); err != nil {
+			return fmt.Errorf("router: reading history: %w", err)
+		}
+		if summary, err = from.GetSummary(key); err != nil {
+			return fmt.Errorf("router: reading summary: %w", err)
+		}
+	}
+
+	if err := to.Purge(key); err != nil {
+		return fmt.Errorf("router: clearing target history: %w", err)
+	}
+	for _, msg := range msgs {
+		if err := to.Append(key, msg); err != nil {
+			return fmt.Errorf("router: copying history: %w", err)
+		}
+	}
+	if summary != "" {
+		if err := to.SetSummary(key, summary); err != nil {
+			return fmt.Errorf("router: copying summary: %w", err)
+		}
+	}
+
+	if from != nil {
+		if err := from.Purge(key); err != nil {
+			return fmt.Errorf("router: purging previous history: %w", err)
+		}
+	}
+	return nil
+}
+
+// rewriteHistory applies edit to the full history of a session and stores
+// the result, refreshing the live history from it.
+func (r *Router) rewriteHistory(id string, edit func([]provider.LLMMessage) ([]provider.LLMMessage, error)) error {
+	return r.withSession(id, func(session *Session, store memory.HistoryStore) error {
+		if store == nil {
+			msgs, err := edit(slices.Clone(session.History))
+			if err != nil {
+				return err
+			}
+			session.History = msgs
+			return nil
+		}
+
+		editor, ok := store.(memory.HistoryEditor)
+		if !ok {
+			return ErrHistoryNotEditable
+		}
+		key := persistenceKey(session.Key)
+		msgs, err := store.GetAll(key)
+		if err != nil {
+			return fmt.Errorf("router: reading history: %w", err)
+		}
+		if msgs, err = edit(msgs); err != nil {
+			return err
+		}
+		if err := editor.Replace(key, msgs); err != nil {
+			return fmt.Errorf("router: writing history: %w", err)
+		}
+		session.History = slices.Clone(recentMessages(msgs, r.pipeline.cfg.MaxHistoryLen))
+		return nil
+	})
+}
+
+// withSession runs fn on the session with the given ID and the history
+// store of its agent (nil if none). It holds the session's lane lock, so
+// fn never overlaps a turn in progress.
+func (r *Router) withSession(id string, fn func(*Session, memory.HistoryStore) error) error {
+	var (
+		key   SessionKey
+		found bool
+	)
+	r.store.Range(func(k SessionKey, s *Session) bool {
+		if s.ID == id {
+			key, found = k, true
+			return false
+		}
+		return true
+	})
+	if !found {
+		return ErrSessionNotFound
+	}
+
+	r.laneLock.Acquire(key)
+	defer r.laneLock.Release(key)
+
+	// The session may have been reset while waiting for the lock.
+	session := r.store.Get(key)
+	if session == nil || session.ID != id {
+		return ErrSessionNotFound
+	}
+	return fn(session, r.historyStore(session.AgentID))
+}
+
+// historyStore returns the persistent history store of an agent, or nil.
+func (r *Router) historyStore(agentID string) memory.HistoryStore {
+	if r.config.HistoryResolver == nil || agentID == "" {
+		return nil
+	}
+	return r.config.HistoryResolver.ResolveHistory(agentID)
+}
+
+// recentMessages returns the last n messages of msgs.
+func recentMessages(msgs []provider.LLMMessage, n int) []provider.LLMMessage {
+	if len(msgs) <= n {
+		return msgs
+	}
+	return msgs[len(msgs)-n:]
+}
+
+// withText returns msg with its text replaced. For multimodal messages the
+// text parts are replaced and the other parts kept.
+func withText(msg provider.LLMMessage, text string) provider.LLMMessage {
+	if len(msg.ContentParts) == 0 {
+		msg.Content = text
+		return msg
+	}
+	parts := []provider.ContentPart{{Type: provider.ContentPartText, Text: text}}
+	for _, p := range msg.ContentParts {
+		if p.Type != provider.ContentPartText {
+			parts = append(parts, p)
+		}
+	}
+	msg.Content = ""
+	msg.ContentParts = parts
+	return msg
+}
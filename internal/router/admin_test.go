@@ -0,0 +1,258 @@
+package router
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/flemzord/sclaw/internal/memory"
+	"github.com/flemzord/sclaw/internal/provider"
+)
+
+// agentHistoryResolver resolves a separate history store per agent.
+type agentHistoryResolver map[string]memory.HistoryStore
+
+func (r agentHistoryResolver) ResolveHistory(agentID string) memory.HistoryStore {
+	return r[agentID]
+}
+
+// checkingAgentFactory is a noopAgentFactory that knows its agents.
+type checkingAgentFactory struct {
+	noopAgentFactory
+	agents []string
+}
+
+func (f *checkingAgentFactory) HasAgent(agentID string) bool {
+	return slices.Contains(f.agents, agentID)
+}
+
+func newAdminTestRouter(t *testing.T, resolver HistoryResolver) (*Router, *Session) {
+	t.Helper()
+
+	r, err := NewRouter(Config{
+		AgentFactory:    &checkingAgentFactory{agents: []string{"main", "other", "ephemeral"}},
+		ResponseSender:  &noopResponseSender{},
+		HistoryResolver: resolver,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	session, _ := r.store.GetOrCreate(SessionKeyFromMessage(newTestMessage("1")))
+	session.AgentID = "main"
+	return r, session
+}
+
+func textMessages(texts ...string) []provider.LLMMessage {
+	msgs := make([]provider.LLMMessage, len(texts))
+	for i, text := range texts {
+		msgs[i] = provider.LLMMessage{Role: provider.MessageRoleUser, Content: text}
+	}
+	return msgs
+}
+
+func contents(msgs []provider.LLMMessage) []string {
+	texts := make([]string, len(msgs))
+	for i, m := range msgs {
+		texts[i] = m.TextForDisplay()
+	}
+	return texts
+}
+
+func TestRouter_EditPersistentHistory(t *testing.T) {
+	t.Parallel()
+
+	store := memory.NewInMemoryHistoryStore()
+	r, session := newAdminTestRouter(t, agentHistoryResolver{"main": store})
+	key := persistenceKey(session.Key)
+	for _, m := range textMessages("hi", "my key is sk-123", "thanks") {
+		_ = store.Append(key, m)
+	}
+	session.History = textMessages("my key is sk-123", "thanks")
+
+	if err := r.EditSessionMessage(session.ID, 1, "my key is [removed]"); err != nil {
+		t.Fatalf("EditSessionMessage() error = %v", err)
+	}
+	if err := r.DeleteSessionMessage(session.ID, 2); err != nil {
+		t.Fatalf("DeleteSessionMessage() error = %v", err)
+	}
+	if err := r.AppendSessionMessage(session.ID, provider.LLMMessage{Role: provider.MessageRoleSystem, Content: "note"}); err != nil {
+		t.Fatalf("AppendSessionMessage() error = %v", err)
+	}
+	if err := r.SetSessionSummary(session.ID, "greetings"); err != nil {
+		t.Fatalf("SetSessionSummary() error = %v", err)
+	}
+
+	h, err := r.SessionHistory(session.ID)
+	if err != nil {
+		t.Fatalf("SessionHistory() error = %v", err)
+	}
+	want := []string{"hi", "my key is [removed]", "note"}
+	if !h.Persistent || h.Summary != "greetings" || !slices.Equal(contents(h.Messages), want) {
+		t.Errorf("SessionHistory() = %+v, want persistent %v with summary", h, want)
+	}
+	if got := contents(session.History); !slices.Equal(got, want) {
+		t.Errorf("live history = %v, want %v", got, want)
+	}
+
+	if err := r.DeleteSessionMessage(session.ID, 3); !errors.Is(err, ErrMessageNotFound) {
+		t.Errorf("DeleteSessionMessage(3) error = %v, want ErrMessageNotFound", err)
+	}
+	if _, err := r.SessionHistory("missing"); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("SessionHistory(missing) error = %v, want ErrSessionNotFound", err)
+	}
+}
+
+func TestRouter_DeleteToolExchange(t *testing.T) {
+	t.Parallel()
+
+	exchange := func() []provider.LLMMessage {
+		return []provider.LLMMessage{
+			{Role: provider.MessageRoleUser, Content: "weather?"},
+			{Role: provider.MessageRoleAssistant, ToolCalls: []provider.ToolCall{{ID: "c1", Name: "weather"}, {ID: "c2", Name: "time"}}},
+			{Role: provider.MessageRoleTool, ToolID: "c1", Content: "sunny"},
+			{Role: provider.MessageRoleTool, ToolID: "c2", Content: "noon"},
+			{Role: provider.MessageRoleAssistant, Content: "sunny at noon"},
+		}
+	}
+	tests := []struct {
+		name  string
+		index int
+	}{
+		{"assistant call removes its results", 1},
+		{"tool result removes the call and other results", 2},
+		{"last tool result", 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			store := memory.NewInMemoryHistoryStore()
+			r, session := newAdminTestRouter(t, agentHistoryResolver{"main": store})
+			for _, m := range exchange() {
+				_ = store.Append(persistenceKey(session.Key), m)
+			}
+			session.History = exchange()
+
+			if err := r.DeleteSessionMessage(session.ID, tt.index); err != nil {
+				t.Fatalf("DeleteSessionMessage() error = %v", err)
+			}
+			want := []string{"weather?", "sunny at noon"}
+			h, _ := r.SessionHistory(session.ID)
+			if got := contents(h.Messages); !slices.Equal(got, want) {
+				t.Errorf("stored history = %v, want %v", got, want)
+			}
+			if got := contents(session.History); !slices.Equal(got, want) {
+				t.Errorf("live history = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestDeleteMessage_OrphanToolResult(t *testing.T) {
+	t.Parallel()
+
+	// The call fell out of the history window: only the result goes.
+	msgs := []provider.LLMMessage{
+		{Role: provider.MessageRoleTool, ToolID: "c1", Content: "sunny"},
+		{Role: provider.MessageRoleAssistant, Content: "it is sunny"},
+	}
+	if got := contents(deleteMessage(msgs, 0)); !slices.Equal(got, []string{"it is sunny"}) {
+		t.Errorf("deleteMessage() = %v", got)
+	}
+}
+
+func TestRouter_EditLiveHistory(t *testing.T) {
+	t.Parallel()
+
+	r, session := newAdminTestRouter(t, nil)
+	session.History = []provider.LLMMessage{{
+		Role: provider.MessageRoleUser,
+		ContentParts: []provider.ContentPart{
+			{Type: provider.ContentPartImageURL, ImageURL: &provider.ImageURL{URL: "data:image/png;base64,AA"}},
+			{Type: provider.ContentPartText, Text: "secret"},
+		},
+	}}
+
+	if err := r.EditSessionMessage(session.ID, 0, "redacted"); err != nil {
+		t.Fatalf("EditSessionMessage() error = %v", err)
+	}
+	parts := session.History[0].ContentParts
+	if len(parts) != 2 || parts[0].Text != "redacted" || parts[1].Type != provider.ContentPartImageURL {
+		t.Errorf("ContentParts = %+v, want the new text and the image", parts)
+	}
+
+	h, err := r.SessionHistory(session.ID)
+	if err != nil || h.Persistent || len(h.Messages) != 1 {
+		t.Errorf("SessionHistory() = %+v, %v, want the live message", h, err)
+	}
+	if err := r.SetSessionSummary(session.ID, "x"); !errors.Is(err, ErrNoPersistentHistory) {
+		t.Errorf("SetSessionSummary() error = %v, want ErrNoPersistentHistory", err)
+	}
+}
+
+func TestRouter_ReassignSession(t *testing.T) {
+	t.Parallel()
+
+	mainStore := memory.NewInMemoryHistoryStore()
+	otherStore := memory.NewInMemoryHistoryStore()
+	r, session := newAdminTestRouter(t, agentHistoryResolver{"main": mainStore, "other": otherStore})
+	key := persistenceKey(session.Key)
+	for _, m := range textMessages("one", "two") {
+		_ = mainStore.Append(key, m)
+	}
+	_ = mainStore.SetSummary(key, "summary")
+
+	if err := r.ReassignSession(session.ID, "nobody"); !errors.Is(err, ErrUnknownAgent) {
+		t.Fatalf("ReassignSession(nobody) error = %v, want ErrUnknownAgent", err)
+	}
+	if err := r.ReassignSession(session.ID, "other"); err != nil {
+		t.Fatalf("ReassignSession() error = %v", err)
+	}
+
+	if session.AgentID != "other" {
+		t.Errorf("AgentID = %q, want other", session.AgentID)
+	}
+	if msgs, _ := otherStore.GetAll(key); !slices.Equal(contents(msgs), []string{"one", "two"}) {
+		t.Errorf("target history = %v, want the moved messages", contents(msgs))
+	}
+	if summary, _ := otherStore.GetSummary(key); summary != "summary" {
+		t.Errorf("target summary = %q, want summary", summary)
+	}
+	if n, _ := mainStore.Len(key); n != 0 {
+		t.Errorf("previous store keeps %d messages, want 0", n)
+	}
+
+	// An agent without a store keeps the live history only.
+	if err := r.ReassignSession(session.ID, "ephemeral"); err != nil {
+		t.Fatalf("ReassignSession(ephemeral) error = %v", err)
+	}
+	if session.AgentID != "ephemeral" {
+		t.Errorf("AgentID = %q, want ephemeral", session.AgentID)
+	}
+}
+
+func TestRouter_AdminWaitsForSessionLock(t *testing.T) {
+	t.Parallel()
+
+	r, session := newAdminTestRouter(t, nil)
+	r.laneLock.Acquire(session.Key)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- r.AppendSessionMessage(session.ID, provider.LLMMessage{Role: provider.MessageRoleUser, Content: "x"})
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("AppendSessionMessage() returned while the session was locked")
+	case <-time.After(20 * time.Millisecond):
+	}
+	r.laneLock.Release(session.Key)
+	if err := <-done; err != nil {
+		t.Fatalf("AppendSessionMessage() error = %v", err)
+	}
+	if len(session.History) != 1 {
+		t.Errorf("history length = %d, want 1", len(session.History))
+	}
+}
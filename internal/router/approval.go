@@ -2,6 +2,7 @@ package router
 
 import (
 	"encoding/json"
+	"slices"
 	"strings"
 	"sync"
 
@@ -13,12 +14,29 @@ type approvalRawPayload struct {
 	ApprovalID string `json:"approval_id"`
 	Approved   *bool  `json:"approved"`
 	Reason     string `json:"reason,omitempty"`
+	Scope      string `json:"scope,omitempty"`
 }
 
-// pendingEntry tracks a pending tool approval and its session.
+// pendingEntry tracks a pending tool approval and its session. Exactly one
+// of approval (Register) or ch (Await) is set.
 type pendingEntry struct {
 	approval   *tool.PendingApproval
+	ch         chan tool.ApprovalResponse
 	sessionKey SessionKey
+	approvers  []string // empty: anyone in the session may answer
+}
+
+// respond delivers response to whoever is waiting on the entry.
+func (e *pendingEntry) respond(response tool.ApprovalResponse) bool {
+	if e.approval != nil {
+		return e.approval.Respond(response)
+	}
+	select {
+	case e.ch <- response:
+		return true
+	default:
+		return false
+	}
 }
 
 // ApprovalManager handles tool approval responses that bypass the lane lock.
@@ -44,6 +62,22 @@ func (am *ApprovalManager) Register(id string, approval *tool.PendingApproval, s
 	}
 }
 
+// Await registers a pending approval and returns the channel on which its
+// response will be delivered. When approvers is non-empty, only those
+// sender IDs may answer. Callers must Remove the entry if they stop
+// waiting before a response arrives.
+func (am *ApprovalManager) Await(id string, sessionKey SessionKey, approvers []string) <-chan tool.ApprovalResponse {
+	ch := make(chan tool.ApprovalResponse, 1)
+	am.mu.Lock()
+	defer am.mu.Unlock()
+	am.pending[id] = &pendingEntry{
+		ch:         ch,
+		sessionKey: sessionKey,
+		approvers:  approvers,
+	}
+	return ch
+}
+
 // Resolve sends an approval response to the pending approval flow.
 // Returns true if the approval was found and resolved.
 // This bypasses the lane lock entirely — approval responses go directly to the agent.
@@ -59,7 +93,25 @@ func (am *ApprovalManager) Resolve(id string, response tool.ApprovalResponse) bo
 		return false
 	}
 
-	return entry.approval.Respond(response)
+	return entry.respond(response)
+}
+
+// ResolveFrom is like Resolve but only accepts a response coming from the
+// session that the approval was requested in, so that a user cannot answer
+// another chat's approval by guessing its ID, and from one of the entry's
+// approvers when it has any.
+func (am *ApprovalManager) ResolveFrom(id string, sessionKey SessionKey, senderID string, response tool.ApprovalResponse) bool {
+	am.mu.Lock()
+	entry, ok := am.pending[id]
+	if !ok || entry.sessionKey != sessionKey ||
+		(len(entry.approvers) > 0 && !slices.Contains(entry.approvers, senderID)) {
+		am.mu.Unlock()
+		return false
+	}
+	delete(am.pending, id)
+	am.mu.Unlock()
+
+	return entry.respond(response)
 }
 
 // Remove cleans up a pending approval entry (e.g., after timeout).
@@ -95,6 +147,7 @@ func approvalResponseFromRaw(raw json.RawMessage) (string, tool.ApprovalResponse
 	return payload.ApprovalID, tool.ApprovalResponse{
 		Approved: *payload.Approved,
 		Reason:   payload.Reason,
+		Scope:    parseApprovalScope(payload.Scope),
 	}, true
 }
 
@@ -106,7 +159,11 @@ func approvalResponseFromText(text string) (string, tool.ApprovalResponse, bool)
 
 	switch strings.ToLower(parts[0]) {
 	case "approve":
-		return parts[1], tool.ApprovalResponse{Approved: true}, true
+		resp := tool.ApprovalResponse{Approved: true}
+		if len(parts) > 2 {
+			resp.Scope = parseApprovalScope(parts[2])
+		}
+		return parts[1], resp, true
 	case "deny", "reject":
 		reason := ""
 		if len(parts) > 2 {
@@ -120,3 +177,14 @@ func approvalResponseFromText(text string) (string, tool.ApprovalResponse, bool)
 		return "", tool.ApprovalResponse{}, false
 	}
 }
+
+// parseApprovalScope maps a user-supplied scope to a tool.ApprovalScope.
+// Unknown values fall back to a one-off approval.
+func parseApprovalScope(s string) tool.ApprovalScope {
+	switch scope := tool.ApprovalScope(strings.ToLower(s)); scope {
+	case tool.ApprovalScopeSession, tool.ApprovalScopeAlways:
+		return scope
+	default:
+		return tool.ApprovalScopeOnce
+	}
+}
@@ -0,0 +1,202 @@
+package router
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/flemzord/sclaw/internal/activity"
+	"github.com/flemzord/sclaw/internal/channel"
+	"github.com/flemzord/sclaw/internal/tool"
+	"github.com/flemzord/sclaw/pkg/message"
+)
+
+// maxPromptArgsLen caps the tool arguments shown in an approval prompt.
+const maxPromptArgsLen = 1000
+
+// closePromptTimeout bounds the best-effort update of a closed prompt.
+const closePromptTimeout = 5 * time.Second
+
+// chatApprover is the tool.ApprovalRequester for tool calls made while
+// handling one inbound message. It prompts the chat the message came from
+// and waits for the answer to be resolved through the ApprovalManager.
+type chatApprover struct {
+	manager  *ApprovalManager
+	sender   ResponseSender
+	lookup   ChannelLookup
+	inbound  message.InboundMessage
+	logger   *slog.Logger
+	activity *activity.Bus
+}
+
+// ApprovalRequester returns the tool.ApprovalRequester for tool calls made
+// while handling msg. Prompts use the channel's native controls when it
+// implements channel.ApprovalChannel, and plain text otherwise.
+func (r *Router) ApprovalRequester(msg message.InboundMessage) tool.ApprovalRequester {
+	return &chatApprover{
+		manager:  r.approvalManager,
+		sender:   r.config.ResponseSender,
+		lookup:   r.config.ChannelLookup,
+		inbound:  msg,
+		logger:   r.logger,
+		activity: r.config.Activity,
+	}
+}
+
+// RequestApproval implements tool.ApprovalRequester. It blocks until the
+// user answers or ctx ends (the caller's timeout denies by default).
+func (a *chatApprover) RequestApproval(ctx context.Context, req tool.ApprovalRequest) (tool.ApprovalResponse, error) {
+	answers := a.manager.Await(req.ID, SessionKeyFromMessage(a.inbound), req.Approvers)
+	defer a.manager.Remove(req.ID)
+
+	out := message.OutboundMessage{
+		Channel:   a.inbound.Channel,
+		Chat:      a.inbound.Chat,
+		ThreadID:  a.inbound.ThreadID,
+		ReplyToID: a.inbound.ID,
+	}
+	prompt := channel.ApprovalPrompt{
+		ID:           req.ID,
+		ToolName:     req.ToolName,
+		Description:  req.Description,
+		Arguments:    formatPromptArgs(req.Arguments),
+		Timeout:      req.Timeout,
+		OfferSession: slices.Contains(req.Scopes, tool.ApprovalScopeSession),
+		Approvers:    req.Approvers,
+	}
+	if slices.Contains(req.Scopes, tool.ApprovalScopeAlways) {
+		prompt.Pattern = req.Pattern
+	}
+
+	native := a.approvalChannel()
+	var err error
+	if native != nil {
+		err = native.SendApprovalPrompt(ctx, out, prompt)
+	} else {
+		out.Blocks = []message.ContentBlock{message.NewTextBlock(textApprovalPrompt(prompt))}
+		err = a.sender.Send(ctx, out)
+	}
+	if err != nil {
+		return tool.ApprovalResponse{}, fmt.Errorf("sending approval prompt: %w", err)
+	}
+	a.publish(ctx, activity.TypeApprovalRequested, req, nil)
+
+	select {
+	case resp := <-answers:
+		a.closePrompt(native, req.ID, approvalOutcome(resp))
+		a.publish(ctx, activity.TypeApprovalResolved, req, map[string]any{
+			"approved": resp.Approved,
+			"scope":    string(resp.Scope),
+		})
+		return resp, nil
+	case <-ctx.Done():
+		a.closePrompt(native, req.ID, "⌛ Expired — denied")
+		a.publish(ctx, activity.TypeApprovalResolved, req, map[string]any{"approved": false, "expired": true})
+		return tool.ApprovalResponse{}, ctx.Err()
+	}
+}
+
+// publish reports an approval event, with data added to the request fields.
+func (a *chatApprover) publish(ctx context.Context, typ activity.Type, req tool.ApprovalRequest, data map[string]any) {
+	if data == nil {
+		data = make(map[string]any, 2)
+	}
+	data["approval_id"] = req.ID
+	data["tool"] = req.ToolName
+	a.activity.Publish(ctx, activity.Event{
+		Type:    typ,
+		Channel: a.inbound.Channel,
+		ChatID:  a.inbound.Chat.ID,
+		Data:    data,
+	})
+}
+
+// approvalChannel returns the inbound channel when it renders prompts natively.
+func (a *chatApprover) approvalChannel() channel.ApprovalChannel {
+	if a.lookup == nil {
+		return nil
+	}
+	ch, ok := a.lookup.Get(a.inbound.Channel)
+	if !ok {
+		return nil
+	}
+	ac, _ := ch.(channel.ApprovalChannel)
+	return ac
+}
+
+// closePrompt lets a native channel update the prompt once it is settled.
+// It uses a fresh context because the request context may be done.
+func (a *chatApprover) closePrompt(native channel.ApprovalChannel, id, outcome string) {
+	if native == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), closePromptTimeout)
+	defer cancel()
+	if err := native.CloseApprovalPrompt(ctx, id, outcome); err != nil {
+		a.logger.Warn("router: failed to close approval prompt", "approval_id", id, "error", err)
+	}
+}
+
+// approvalOutcome summarizes a user's answer for the closed prompt.
+func approvalOutcome(resp tool.ApprovalResponse) string {
+	if !resp.Approved {
+		if resp.Reason != "" {
+			return "❌ Denied: " + resp.Reason
+		}
+		return "❌ Denied"
+	}
+	switch resp.Scope {
+	case tool.ApprovalScopeSession:
+		return "✅ Allowed for this session"
+	case tool.ApprovalScopeAlways:
+		return "✅ Always allowed"
+	default:
+		return "✅ Allowed once"
+	}
+}
+
+// textApprovalPrompt renders a prompt for channels without native controls.
+func textApprovalPrompt(p channel.ApprovalPrompt) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "🔐 Approval required: %s\n", p.ToolName)
+	if p.Arguments != "" {
+		fmt.Fprintf(&sb, "%s\n", p.Arguments)
+	}
+	fmt.Fprintf(&sb, "\nReply \"approve %s\" or \"deny %s [reason]\".", p.ID, p.ID)
+	switch {
+	case p.OfferSession && p.Pattern != "":
+		fmt.Fprintf(&sb, "\nAdd \"session\" to allow this tool for the session, or \"always\" to always allow %q.", p.Pattern)
+	case p.OfferSession:
+		sb.WriteString("\nAdd \"session\" to allow this tool for the session.")
+	}
+	if len(p.Approvers) > 0 {
+		fmt.Fprintf(&sb, "\nOnly %s can answer.", strings.Join(p.Approvers, ", "))
+	}
+	if p.Timeout > 0 {
+		fmt.Fprintf(&sb, "\nDenied automatically in %s.", p.Timeout)
+	}
+	return sb.String()
+}
+
+// formatPromptArgs renders tool arguments for display, truncated.
+func formatPromptArgs(args json.RawMessage) string {
+	var buf bytes.Buffer
+	text := string(args)
+	if err := json.Indent(&buf, args, "", "  "); err == nil {
+		text = buf.String()
+	}
+	if len(text) > maxPromptArgsLen {
+		cut := maxPromptArgsLen
+		for cut > 0 && !utf8.RuneStart(text[cut]) {
+			cut--
+		}
+		text = text[:cut] + "…"
+	}
+	return text
+}
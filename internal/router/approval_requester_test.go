@@ -0,0 +1,236 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/flemzord/sclaw/internal/channel"
+	"github.com/flemzord/sclaw/internal/channel/channeltest"
+	"github.com/flemzord/sclaw/internal/tool"
+	"github.com/flemzord/sclaw/pkg/message"
+)
+
+// fakeApprovalChannel records native approval prompts.
+type fakeApprovalChannel struct {
+	*channeltest.MockChannel
+
+	mu       sync.Mutex
+	prompts  []channel.ApprovalPrompt
+	outcomes map[string]string
+}
+
+func (c *fakeApprovalChannel) SendApprovalPrompt(_ context.Context, _ message.OutboundMessage, p channel.ApprovalPrompt) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.prompts = append(c.prompts, p)
+	return nil
+}
+
+func (c *fakeApprovalChannel) CloseApprovalPrompt(_ context.Context, id, outcome string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.outcomes == nil {
+		c.outcomes = make(map[string]string)
+	}
+	c.outcomes[id] = outcome
+	return nil
+}
+
+func (c *fakeApprovalChannel) promptCount() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.prompts)
+}
+
+func newApprovalTestRouter(t *testing.T, sender ResponseSender, lookup ChannelLookup) *Router {
+	t.Helper()
+	r, err := NewRouter(Config{
+		AgentFactory:   &noopAgentFactory{},
+		ResponseSender: sender,
+		ChannelLookup:  lookup,
+	})
+	if err != nil {
+		t.Fatalf("NewRouter: %v", err)
+	}
+	return r
+}
+
+type approvalResult struct {
+	resp tool.ApprovalResponse
+	err  error
+}
+
+func requestApprovalAsync(ctx context.Context, req tool.ApprovalRequester, ar tool.ApprovalRequest) <-chan approvalResult {
+	done := make(chan approvalResult, 1)
+	go func() {
+		resp, err := req.RequestApproval(ctx, ar)
+		done <- approvalResult{resp: resp, err: err}
+	}()
+	return done
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for condition")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestChatApprover_TextPrompt(t *testing.T) {
+	t.Parallel()
+
+	sender := &testResponseSender{}
+	r := newApprovalTestRouter(t, sender, nil)
+	inbound := newTestMessage("m1")
+
+	done := requestApprovalAsync(context.Background(), r.ApprovalRequester(inbound), tool.ApprovalRequest{
+		ID:        "apv-1",
+		ToolName:  "exec",
+		Arguments: json.RawMessage(`{"command":"ls -la"}`),
+		Timeout:   time.Minute,
+		Scopes:    []tool.ApprovalScope{tool.ApprovalScopeSession, tool.ApprovalScopeAlways},
+		Pattern:   "ls *",
+	})
+
+	waitFor(t, func() bool { return len(sender.sentMessages()) == 1 })
+	out := sender.sentMessages()[0]
+	if out.Chat.ID != inbound.Chat.ID || out.ReplyToID != inbound.ID {
+		t.Errorf("prompt addressed to chat %q reply %q", out.Chat.ID, out.ReplyToID)
+	}
+	text := out.TextContent()
+	for _, want := range []string{"exec", `"approve apv-1"`, `"ls *"`, "1m0s"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("prompt %q missing %q", text, want)
+		}
+	}
+
+	answer := newTestMessage("m2")
+	answer.Blocks = []message.ContentBlock{message.NewTextBlock("approve apv-1 session")}
+	if err := r.Submit(answer); err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+
+	got := <-done
+	if got.err != nil || !got.resp.Approved || got.resp.Scope != tool.ApprovalScopeSession {
+		t.Errorf("RequestApproval = (%+v, %v), want session approval", got.resp, got.err)
+	}
+}
+
+func TestChatApprover_NativePrompt(t *testing.T) {
+	t.Parallel()
+
+	native := &fakeApprovalChannel{MockChannel: channeltest.NewMockChannel("slack", nil)}
+	lookup := &testChannelLookup{channels: map[string]channel.Channel{"slack": native}}
+	r := newApprovalTestRouter(t, &testResponseSender{}, lookup)
+	inbound := newTestMessage("m1")
+
+	done := requestApprovalAsync(context.Background(), r.ApprovalRequester(inbound), tool.ApprovalRequest{
+		ID:       "apv-2",
+		ToolName: "exec",
+		Scopes:   []tool.ApprovalScope{tool.ApprovalScopeSession},
+		Pattern:  "ls *",
+	})
+	waitFor(t, func() bool { return native.promptCount() == 1 })
+
+	if p := native.prompts[0]; !p.OfferSession || p.Pattern != "" {
+		t.Errorf("prompt = %+v, want session offered and no pattern", p)
+	}
+
+	// An answer from another chat must not resolve the approval.
+	other := SessionKey{Channel: "slack", ChatID: "C999"}
+	if r.approvalManager.ResolveFrom("apv-2", other, "user-1", tool.ApprovalResponse{Approved: true}) {
+		t.Fatal("approval resolved from another session")
+	}
+
+	raw, _ := json.Marshal(channel.ApprovalAnswer{ApprovalID: "apv-2", Approved: false, Reason: "nope"})
+	answer := newTestMessage("cb-1")
+	answer.Blocks = nil
+	answer.Raw = raw
+	if err := r.Submit(answer); err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+
+	got := <-done
+	if got.err != nil || got.resp.Approved {
+		t.Errorf("RequestApproval = (%+v, %v), want denial", got.resp, got.err)
+	}
+	native.mu.Lock()
+	defer native.mu.Unlock()
+	if outcome := native.outcomes["apv-2"]; outcome != "❌ Denied: nope" {
+		t.Errorf("outcome = %q", outcome)
+	}
+}
+
+func TestChatApprover_Approvers(t *testing.T) {
+	t.Parallel()
+
+	sender := &testResponseSender{}
+	r := newApprovalTestRouter(t, sender, nil)
+
+	done := requestApprovalAsync(context.Background(), r.ApprovalRequester(newTestMessage("m1")), tool.ApprovalRequest{
+		ID:        "apv-4",
+		ToolName:  "exec",
+		Approvers: []string{"admin"},
+	})
+	waitFor(t, func() bool { return len(sender.sentMessages()) == 1 })
+	if text := sender.sentMessages()[0].TextContent(); !strings.Contains(text, "Only admin can answer") {
+		t.Errorf("prompt %q does not name the approver", text)
+	}
+
+	key := SessionKeyFromMessage(newTestMessage("m2"))
+	if r.approvalManager.ResolveFrom("apv-4", key, "user-1", tool.ApprovalResponse{Approved: true}) {
+		t.Fatal("approval resolved by a non-approver")
+	}
+	if !r.approvalManager.ResolveFrom("apv-4", key, "admin", tool.ApprovalResponse{Approved: true}) {
+		t.Fatal("approver could not resolve the approval")
+	}
+	if got := <-done; got.err != nil || !got.resp.Approved {
+		t.Errorf("RequestApproval = (%+v, %v), want approval", got.resp, got.err)
+	}
+}
+
+func TestChatApprover_Timeout(t *testing.T) {
+	t.Parallel()
+
+	native := &fakeApprovalChannel{MockChannel: channeltest.NewMockChannel("slack", nil)}
+	lookup := &testChannelLookup{channels: map[string]channel.Channel{"slack": native}}
+	r := newApprovalTestRouter(t, &testResponseSender{}, lookup)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := r.ApprovalRequester(newTestMessage("m1")).RequestApproval(ctx, tool.ApprovalRequest{ID: "apv-3", ToolName: "exec"})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want deadline exceeded", err)
+	}
+
+	native.mu.Lock()
+	defer native.mu.Unlock()
+	if !strings.Contains(native.outcomes["apv-3"], "Expired") {
+		t.Errorf("outcome = %q, want expiry", native.outcomes["apv-3"])
+	}
+	if r.approvalManager.Resolve("apv-3", tool.ApprovalResponse{Approved: true}) {
+		t.Error("expired approval still pending")
+	}
+}
+
+func TestFormatPromptArgs_Truncates(t *testing.T) {
+	t.Parallel()
+
+	args, _ := json.Marshal(map[string]string{"command": strings.Repeat("é", maxPromptArgsLen)})
+	got := formatPromptArgs(args)
+	if len(got) > maxPromptArgsLen+len("…") {
+		t.Errorf("len = %d, want <= %d", len(got), maxPromptArgsLen+len("…"))
+	}
+	if !strings.HasSuffix(got, "…") {
+		t.Error("truncated args should end with an ellipsis")
+	}
+}
@@ -223,3 +223,25 @@ func TestApprovalManager_IsApprovalResponse_Invalid(t *testing.T) {
 		t.Error("expected IsApprovalResponse to return false for invalid payload")
 	}
 }
+
+func TestApprovalManager_IsApprovalResponse_Scope(t *testing.T) {
+	t.Parallel()
+
+	am := NewApprovalManager()
+	tests := []struct {
+		text string
+		want tool.ApprovalScope
+	}{
+		{"approve a1", tool.ApprovalScopeOnce},
+		{"approve a1 session", tool.ApprovalScopeSession},
+		{"approve a1 ALWAYS", tool.ApprovalScopeAlways},
+		{"approve a1 forever", tool.ApprovalScopeOnce},
+	}
+	for _, tt := range tests {
+		msg := message.InboundMessage{Blocks: []message.ContentBlock{message.NewTextBlock(tt.text)}}
+		_, resp, ok := am.IsApprovalResponse(msg)
+		if !ok || !resp.Approved || resp.Scope != tt.want {
+			t.Errorf("%q: resp = %+v, ok = %v, want scope %q", tt.text, resp, ok, tt.want)
+		}
+	}
+}
@@ -6,9 +6,11 @@ import (
 
 	"github.com/flemzord/sclaw/internal/agent"
 	"github.com/flemzord/sclaw/internal/channel"
+	ctxengine "github.com/flemzord/sclaw/internal/context"
 	"github.com/flemzord/sclaw/internal/hook"
 	"github.com/flemzord/sclaw/internal/memory"
 	"github.com/flemzord/sclaw/internal/provider"
+	"github.com/flemzord/sclaw/internal/snapshot"
 	"github.com/flemzord/sclaw/pkg/message"
 )
 
@@ -21,12 +23,25 @@ type HistoryResolver interface {
 	ResolveHistory(agentID string) memory.HistoryStore
 }
 
+// WorkspaceHistoryResolver returns the workspace snapshot store of an agent,
+// used by the /undo command. Returns nil if snapshots are disabled.
+type WorkspaceHistoryResolver interface {
+	ResolveWorkspaceHistory(agentID string) *snapshot.Store
+}
+
 // SoulResolver returns the system prompt for a given agent.
 // Returns the default prompt if the agent has no SOUL.md.
 type SoulResolver interface {
 	ResolveSoul(agentID string) (string, error)
 }
 
+// ContextResolver returns the token estimator and the context window of the
+// provider serving a given agent, used to fit each request in the window.
+// A nil estimator or a non-positive window means the request is not fitted.
+type ContextResolver interface {
+	ResolveContext(agentID string) (ctxengine.TokenEstimator, int)
+}
+
 // SkillResolver returns formatted skill sections for a given agent.
 // The result is a markdown string ready to append to the system prompt.
 // Returns an empty string if no skills are active.
@@ -34,11 +49,19 @@ type SkillResolver interface {
 	ResolveSkills(agentID, userMessage string) (string, error)
 }
 
+// SkillSectionResolver is optionally implemented by a SkillResolver to split
+// the skill catalog into a stable section (always-on skills) that belongs to
+// the cacheable prompt prefix and a volatile section (triggered skills) that
+// is placed after it.
+type SkillSectionResolver interface {
+	ResolveSkillSections(agentID, userMessage string) (stable, volatile string, err error)
+}
+
 // persistenceKey derives a stable key from a SessionKey for history persistence.
 // The key survives session recreation (new UUID) because it is based on the
 // immutable channel/chat/thread triple.
 func persistenceKey(key SessionKey) string {
-	return key.Channel + ":" + key.ChatID + ":" + key.ThreadID
+	return key.String()
 }
 
 // ResponseSender delivers outbound messages to a channel.
@@ -106,15 +129,49 @@ func messageToLLM(msg message.InboundMessage) provider.LLMMessage {
 	}
 }
 
-// buildOutbound creates an outbound text response preserving thread/reply context.
+// buildOutbound creates an outbound response preserving thread/reply context:
+// the reply text followed by the media produced by tools.
 func buildOutbound(original message.InboundMessage, resp agent.Response) message.OutboundMessage {
 	out := message.NewTextMessage(original.Chat, resp.Content)
 	out.Channel = original.Channel
 	out.ThreadID = original.ThreadID
 	out.ReplyToID = original.ID
+	if media := toolMedia(resp); len(media) > 0 {
+		if resp.Content == "" {
+			out.Blocks = nil
+		}
+		out.Blocks = append(out.Blocks, media...)
+	}
 	return out
 }
 
+// buildMediaOutbound creates a message carrying only the media produced by
+// tools, for replies whose text was already streamed. ok is false when
+// there is no media.
+func buildMediaOutbound(original message.InboundMessage, resp agent.Response) (out message.OutboundMessage, ok bool) {
+	media := toolMedia(resp)
+	if len(media) == 0 {
+		return message.OutboundMessage{}, false
+	}
+	return message.OutboundMessage{
+		Channel:   original.Channel,
+		Chat:      original.Chat,
+		ThreadID:  original.ThreadID,
+		ReplyToID: original.ID,
+		Blocks:    media,
+	}, true
+}
+
+// toolMedia collects the media blocks of the tool calls in resp, in call
+// order.
+func toolMedia(resp agent.Response) []message.ContentBlock {
+	var blocks []message.ContentBlock
+	for _, rec := range resp.ToolCalls {
+		blocks = append(blocks, rec.Output.Blocks...)
+	}
+	return blocks
+}
+
 // sessionViewAdapter provides a read-only view of a Session for use by hooks.
 // It exists to decouple hook implementations from the internal Session type.
 type sessionViewAdapter struct {
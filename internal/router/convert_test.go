@@ -5,6 +5,7 @@ import (
 
 	"github.com/flemzord/sclaw/internal/agent"
 	"github.com/flemzord/sclaw/internal/provider"
+	"github.com/flemzord/sclaw/internal/tool"
 	"github.com/flemzord/sclaw/pkg/message"
 )
 
@@ -153,6 +154,38 @@ func TestBuildOutbound(t *testing.T) {
 	}
 }
 
+func TestBuildOutbound_ToolMedia(t *testing.T) {
+	t.Parallel()
+
+	original := message.InboundMessage{ID: "msg-1", Channel: "telegram", Chat: message.Chat{ID: "42"}}
+	chart := message.NewImageBlock("file:///ws/chart.png", "image/png")
+	resp := agent.Response{
+		Content: "Here is the chart.",
+		ToolCalls: []agent.ToolCallRecord{
+			{Name: "read_file"},
+			{Name: "code_interpreter", Output: tool.Output{Blocks: []message.ContentBlock{chart}}},
+		},
+	}
+
+	out := buildOutbound(original, resp)
+	if len(out.Blocks) != 2 || out.Blocks[0].Text != "Here is the chart." || out.Blocks[1].URL != chart.URL {
+		t.Errorf("Blocks = %+v", out.Blocks)
+	}
+
+	resp.Content = ""
+	if out := buildOutbound(original, resp); len(out.Blocks) != 1 || out.Blocks[0].URL != chart.URL {
+		t.Errorf("Blocks without text = %+v", out.Blocks)
+	}
+
+	media, ok := buildMediaOutbound(original, resp)
+	if !ok || len(media.Blocks) != 1 || media.ReplyToID != "msg-1" || media.Chat.ID != "42" {
+		t.Errorf("media outbound = %+v, %v", media, ok)
+	}
+	if _, ok := buildMediaOutbound(original, agent.Response{Content: "text only"}); ok {
+		t.Error("buildMediaOutbound without media returned ok")
+	}
+}
+
 // m-30: Verify sessionViewAdapter.GetMetadata returns the value for a key.
 func TestSessionViewAdapter_GetMetadata(t *testing.T) {
 	t.Parallel()
@@ -22,4 +22,23 @@ var (
 	// ErrNoResponseSender indicates no response sender has been configured.
 	// The router cannot deliver outbound messages without one.
 	ErrNoResponseSender = errors.New("router: no response sender configured")
+
+	// ErrSessionNotFound indicates no active session has the given ID.
+	ErrSessionNotFound = errors.New("router: session not found")
+
+	// ErrMessageNotFound indicates a history index outside the session's
+	// messages.
+	ErrMessageNotFound = errors.New("router: message not found")
+
+	// ErrNoPersistentHistory indicates the session's agent has no history
+	// store, so there is no summary to read or write.
+	ErrNoPersistentHistory = errors.New("router: session has no persistent history")
+
+	// ErrHistoryNotEditable indicates the agent's history store cannot
+	// rewrite stored messages.
+	ErrHistoryNotEditable = errors.New("router: history store does not support editing")
+
+	// ErrUnknownAgent indicates a session was reassigned to an agent that
+	// is not configured.
+	ErrUnknownAgent = errors.New("router: unknown agent")
 )
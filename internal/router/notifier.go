@@ -0,0 +1,30 @@
+package router
+
+import (
+	"context"
+
+	"github.com/flemzord/sclaw/internal/tool"
+	"github.com/flemzord/sclaw/pkg/message"
+)
+
+// chatNotifier is the tool.Notifier for tool calls made while handling one
+// inbound message. It posts to the chat and thread the message came from.
+type chatNotifier struct {
+	sender  ResponseSender
+	inbound message.InboundMessage
+}
+
+// Notifier returns the tool.Notifier for tool calls made while handling msg.
+func (r *Router) Notifier(msg message.InboundMessage) tool.Notifier {
+	return &chatNotifier{sender: r.config.ResponseSender, inbound: msg}
+}
+
+// Notify implements tool.Notifier.
+func (n *chatNotifier) Notify(ctx context.Context, text string) error {
+	return n.sender.Send(ctx, message.OutboundMessage{
+		Channel:  n.inbound.Channel,
+		Chat:     n.inbound.Chat,
+		ThreadID: n.inbound.ThreadID,
+		Blocks:   []message.ContentBlock{message.NewTextBlock(text)},
+	})
+}
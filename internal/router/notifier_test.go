@@ -0,0 +1,37 @@
+package router
+
+import (
+	"context"
+	"testing"
+
+	"github.com/flemzord/sclaw/pkg/message"
+)
+
+func TestNotifier_PostsToOriginChat(t *testing.T) {
+	t.Parallel()
+
+	sender := &testResponseSender{}
+	r := newApprovalTestRouter(t, sender, nil)
+
+	inbound := message.InboundMessage{
+		ID:       "m1",
+		Channel:  "slack",
+		Chat:     message.Chat{ID: "C1"},
+		ThreadID: "T1",
+	}
+	if err := r.Notifier(inbound).Notify(context.Background(), "job done"); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+
+	sent := sender.sentMessages()
+	if len(sent) != 1 {
+		t.Fatalf("sent %d messages, want 1", len(sent))
+	}
+	got := sent[0]
+	if got.Channel != "slack" || got.Chat.ID != "C1" || got.ThreadID != "T1" || got.ReplyToID != "" {
+		t.Errorf("sent to %+v", got)
+	}
+	if got.TextContent() != "job done" {
+		t.Errorf("text = %q, want %q", got.TextContent(), "job done")
+	}
+}
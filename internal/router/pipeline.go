@@ -2,15 +2,20 @@ package router
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"strconv"
 	"strings"
 
+	"github.com/flemzord/sclaw/internal/activity"
 	"github.com/flemzord/sclaw/internal/agent"
 	"github.com/flemzord/sclaw/internal/channel"
+	ctxengine "github.com/flemzord/sclaw/internal/context"
 	"github.com/flemzord/sclaw/internal/hook"
 	"github.com/flemzord/sclaw/internal/provider"
 	"github.com/flemzord/sclaw/internal/security"
+	"github.com/flemzord/sclaw/internal/snapshot"
 	"github.com/flemzord/sclaw/internal/workspace"
 	"github.com/flemzord/sclaw/pkg/message"
 )
@@ -63,6 +68,20 @@ type PipelineConfig struct {
 	// SkillResolver, if non-nil, provides per-agent skill sections that are
 	// appended to the system prompt. Nil means no skills (backward compatible).
 	SkillResolver SkillResolver
+
+	// ContextResolver, if non-nil, provides the token estimator used to
+	// leave out of each request the oldest history that does not fit the
+	// context window. Nil means history is only capped by MaxHistoryLen.
+	ContextResolver ContextResolver
+
+	// WorkspaceHistory, if non-nil, provides the snapshot stores reverted by
+	// the /undo command. Nil means /undo replies that it is unavailable.
+	WorkspaceHistory WorkspaceHistoryResolver
+
+	// Activity, if non-nil, receives message, history trim and approval
+	// events. Agent loops run with a context attributing their events to
+	// the session.
+	Activity *activity.Bus
 }
 
 // PipelineResult contains the outcome of pipeline execution.
@@ -107,6 +126,7 @@ func (p *Pipeline) Execute(ctx context.Context, env envelope) PipelineResult {
 			"channel", env.Key.Channel,
 			"chat_id", env.Key.ChatID,
 		)
+		p.publishMessage(ctx, env, nil, activity.TypeMessageDropped, "max_sessions")
 		p.sendError(ctx, env.Message, "Too many active sessions. Please try again later.")
 		return PipelineResult{Skipped: true}
 	}
@@ -137,8 +157,10 @@ func (p *Pipeline) Execute(ctx context.Context, env envelope) PipelineResult {
 		logger.Debug("pipeline: message filtered by group policy",
 			"sender", env.Message.Sender.ID,
 		)
+		p.publishMessage(ctx, env, session, activity.TypeMessageDropped, "group_policy")
 		return PipelineResult{Session: session, Skipped: true}
 	}
+	p.publishMessage(ctx, env, session, activity.TypeMessageAccepted, "")
 
 	// Step 4b: Command interception — handle /new before any processing.
 	if strings.TrimSpace(env.Message.TextContent()) == "/new" {
@@ -146,6 +168,15 @@ func (p *Pipeline) Execute(ctx context.Context, env envelope) PipelineResult {
 		return PipelineResult{Session: session, Skipped: true}
 	}
 
+	// Step 4c: /undo reverts the workspace changes of the last turn. It takes
+	// the lane lock so that it waits for a turn in progress to finish.
+	if strings.TrimSpace(env.Message.TextContent()) == "/undo" {
+		p.cfg.LaneLock.Acquire(env.Key)
+		defer p.cfg.LaneLock.Release(env.Key)
+		p.handleUndoCommand(ctx, env, session, logger)
+		return PipelineResult{Session: session, Skipped: true}
+	}
+
 	// Step 5: Lane lock acquire (step 15 releases via defer).
 	// C-13 fix: Lane lock is acquired BEFORE hook before_process so that
 	// the session pointer is protected by the lane lock when hooks access it.
@@ -168,6 +199,7 @@ func (p *Pipeline) Execute(ctx context.Context, env envelope) PipelineResult {
 			logger.Warn("pipeline: hook before_process error", "error", err)
 		}
 		if action == hook.ActionDrop {
+			p.publishMessage(ctx, env, session, activity.TypeMessageDropped, "hook")
 			return PipelineResult{Session: session, Skipped: true}
 		}
 	}
@@ -181,6 +213,7 @@ func (p *Pipeline) Execute(ctx context.Context, env envelope) PipelineResult {
 		p.sendError(ctx, env.Message, "Failed to initialize agent.")
 		return PipelineResult{Session: session, Error: err}
 	}
+	ctx = activity.WithSession(ctx, session.AgentID, session.ID)
 
 	// Step 7b: History restore — if the session was just created and a
 	// persistent store is available, restore previous history from SQLite.
@@ -214,9 +247,7 @@ func (p *Pipeline) Execute(ctx context.Context, env envelope) PipelineResult {
 	session.History = append(session.History, llmMsg)
 
 	// Trim history to MaxHistoryLen to prevent unbounded growth.
-	if limit := p.cfg.MaxHistoryLen; len(session.History) > limit {
-		session.History = session.History[len(session.History)-limit:]
-	}
+	p.trimHistory(ctx, session)
 
 	// Step 8b: Persist user message to SQLite (write-behind, non-fatal).
 	if p.cfg.HistoryResolver != nil && session.AgentID != "" {
@@ -241,13 +272,19 @@ func (p *Pipeline) Execute(ctx context.Context, env envelope) PipelineResult {
 	}
 
 	// Step 9a: Skill resolution — append active skills to the system prompt.
+	// When the resolver can split its catalog, always-on skills stay in the
+	// stable (cacheable) prefix and triggered skills go after it.
+	var volatilePrompt string
 	if p.cfg.SkillResolver != nil && session.AgentID != "" {
-		if skillSection, err := p.cfg.SkillResolver.ResolveSkills(session.AgentID, env.Message.TextContent()); err == nil && skillSection != "" {
-			systemPrompt += "\n\n" + skillSection
-		} else if err != nil {
+		stable, volatile, err := p.resolveSkills(session.AgentID, env.Message.TextContent())
+		if err != nil {
 			logger.Warn("pipeline: failed to resolve skills",
 				"session_id", session.ID, "agent_id", session.AgentID, "error", err)
 		}
+		if stable != "" {
+			systemPrompt += "\n\n" + stable
+		}
+		volatilePrompt = volatile
 	}
 
 	// Step 9c: Workspace context — tell the LLM which directory it operates in
@@ -275,11 +312,17 @@ func (p *Pipeline) Execute(ctx context.Context, env envelope) PipelineResult {
 	}
 
 	req := agent.Request{
-		Messages:     session.History,
-		SystemPrompt: systemPrompt,
-		Tools:        loop.ToolDefinitions(),
+		Messages:             session.History,
+		SystemPrompt:         systemPrompt,
+		VolatileSystemPrompt: volatilePrompt,
+		Tools:                loop.ToolDefinitions(),
+		CacheKey:             session.AgentID,
 	}
 
+	// Step 9e: Context window — leave out the oldest history that does not
+	// fit next to the system prompt and tools.
+	req.Messages = p.fitHistory(ctx, session, req, logger)
+
 	// Step 9b: Typing indicator — show "typing..." while agent processes.
 	var cancelTyping context.CancelFunc
 	if p.cfg.ChannelLookup != nil {
@@ -293,6 +336,8 @@ func (p *Pipeline) Execute(ctx context.Context, env envelope) PipelineResult {
 	}
 
 	// Step 10: Agent loop — run synchronously or stream depending on config.
+	// The agent ID lets routing providers apply per-agent rules.
+	ctx = provider.WithAgentID(ctx, session.AgentID)
 	if session.StreamingEnabled && p.cfg.StreamSender != nil {
 		return p.executeStreaming(ctx, env, session, loop, req, cancelTyping, hookMeta, logger)
 	}
@@ -336,6 +381,79 @@ func (p *Pipeline) Execute(ctx context.Context, env envelope) PipelineResult {
 	return p.finalize(ctx, env, session, resp, hookMeta, logger)
 }
 
+// trimHistory drops the oldest messages of the session beyond MaxHistoryLen.
+func (p *Pipeline) trimHistory(ctx context.Context, session *Session) {
+	limit := p.cfg.MaxHistoryLen
+	if len(session.History) <= limit {
+		return
+	}
+	dropped := len(session.History) - limit
+	session.History = session.History[dropped:]
+	p.cfg.Activity.Publish(ctx, activity.Event{
+		Type: activity.TypeCompaction,
+		Data: map[string]any{"dropped": dropped, "kept": limit},
+	})
+}
+
+// fitHistory returns the most recent part of the session history that fits
+// the context window of the agent's provider, as estimated by its token
+// estimator. The session history itself is left untouched.
+func (p *Pipeline) fitHistory(ctx context.Context, session *Session, req agent.Request, logger *slog.Logger) []provider.LLMMessage {
+	if p.cfg.ContextResolver == nil || session.AgentID == "" {
+		return req.Messages
+	}
+	est, window := p.cfg.ContextResolver.ResolveContext(session.AgentID)
+	if est == nil || window <= 0 {
+		return req.Messages
+	}
+	res, err := ctxengine.NewContextAssembler(est, ctxengine.ContextConfig{}).Assemble(ctx, ctxengine.AssemblyRequest{
+		WindowSize:    window,
+		SystemParts:   []string{req.SystemPrompt},
+		VolatileParts: []string{req.VolatileSystemPrompt},
+		Tools:         req.Tools,
+		History:       req.Messages,
+	})
+	if err != nil {
+		// Only compaction can fail, and no compactor is attached.
+		return req.Messages
+	}
+	if dropped := len(req.Messages) - len(res.Messages); dropped > 0 {
+		logger.Debug("pipeline: history trimmed to fit the context window",
+			"session_id", session.ID, "dropped", dropped, "history_tokens", res.Budget.History)
+	}
+	return res.Messages
+}
+
+// publishMessage reports whether an inbound message was accepted or, with
+// a reason, dropped. session is nil when none could be created.
+func (p *Pipeline) publishMessage(ctx context.Context, env envelope, session *Session, typ activity.Type, reason string) {
+	e := activity.Event{
+		Type:    typ,
+		Channel: env.Key.Channel,
+		ChatID:  env.Key.ChatID,
+		Data:    map[string]any{"sender_id": env.Message.Sender.ID},
+	}
+	if session != nil {
+		e.AgentID = session.AgentID
+		e.SessionID = session.ID
+	}
+	if reason != "" {
+		e.Data["reason"] = reason
+	}
+	p.cfg.Activity.Publish(ctx, e)
+}
+
+// resolveSkills returns the stable and volatile skill sections for an agent.
+// Resolvers that cannot split their catalog return everything as stable so
+// the prompt layout matches the historical ordering.
+func (p *Pipeline) resolveSkills(agentID, userMessage string) (stable, volatile string, err error) {
+	if sr, ok := p.cfg.SkillResolver.(SkillSectionResolver); ok {
+		return sr.ResolveSkillSections(agentID, userMessage)
+	}
+	section, err := p.cfg.SkillResolver.ResolveSkills(agentID, userMessage)
+	return section, "", err
+}
+
 // finalize handles Steps 13–14 (persistence, hooks, pruning) after a response
 // has been delivered, regardless of whether it was sent synchronously or streamed.
 func (p *Pipeline) finalize(
@@ -357,9 +475,7 @@ func (p *Pipeline) finalize(
 	p.cfg.Store.Touch(env.Key)
 
 	// m-60: Trim history after appending assistant message.
-	if limit := p.cfg.MaxHistoryLen; len(session.History) > limit {
-		session.History = session.History[len(session.History)-limit:]
-	}
+	p.trimHistory(ctx, session)
 
 	// Step 13b: Persist assistant message to SQLite (write-behind, non-fatal).
 	if p.cfg.HistoryResolver != nil && session.AgentID != "" {
@@ -554,7 +670,7 @@ func (p *Pipeline) executeStreaming(
 		}
 		// If hook modified the content, send a corrective message.
 		if resp.Content != originalContent {
-			corrective := buildOutbound(env.Message, resp)
+			corrective := buildOutbound(env.Message, agent.Response{Content: resp.Content})
 			if err := p.cfg.ResponseSender.Send(ctx, corrective); err != nil {
 				logger.Warn("pipeline: failed to send hook-corrected message",
 					"error", err, "session_id", session.ID)
@@ -562,6 +678,14 @@ func (p *Pipeline) executeStreaming(
 		}
 	}
 
+	// The stream carried the text only: deliver tool media afterwards.
+	if media, ok := buildMediaOutbound(env.Message, resp); ok {
+		if err := p.cfg.ResponseSender.Send(ctx, media); err != nil {
+			logger.Warn("pipeline: failed to send tool media",
+				"error", err, "session_id", session.ID)
+		}
+	}
+
 	return p.finalize(ctx, env, session, resp, hookMeta, logger)
 }
 
@@ -643,6 +767,61 @@ func (p *Pipeline) handleNewCommand(ctx context.Context, env envelope, session *
 	}
 }
 
+// handleUndoCommand reverts the file changes made in the workspace during
+// the conversation's most recent turn and reports what was restored.
+func (p *Pipeline) handleUndoCommand(ctx context.Context, env envelope, session *Session, logger *slog.Logger) {
+	// A session restored after a restart has no agent yet.
+	if session.AgentID == "" {
+		if _, err := p.cfg.AgentFactory.ForSession(session, env.Message); err != nil {
+			logger.Error("pipeline: /undo failed to resolve agent", "error", err, "session_id", session.ID)
+		}
+	}
+
+	var store *snapshot.Store
+	if p.cfg.WorkspaceHistory != nil && session.AgentID != "" {
+		store = p.cfg.WorkspaceHistory.ResolveWorkspaceHistory(session.AgentID)
+	}
+
+	text := "Workspace history is not enabled for this agent."
+	if store != nil {
+		t, err := store.Undo(env.Key.String())
+		var conflict *snapshot.ConflictError
+		switch {
+		case errors.Is(err, snapshot.ErrNothingToUndo):
+			text = "Nothing to undo."
+		case errors.As(err, &conflict):
+			text = fmt.Sprintf("Cannot undo turn #%d, files changed since then: %s. "+
+				"Ask me to revert it with workspace_history and force to discard those changes.",
+				t.ID, strings.Join(conflict.Paths, ", "))
+		case err != nil:
+			logger.Error("pipeline: /undo failed", "error", err, "session_id", session.ID, "agent_id", session.AgentID)
+			text = "Failed to undo the last changes."
+		default:
+			logger.Info("pipeline: /undo reverted turn",
+				"session_id", session.ID, "agent_id", session.AgentID, "turn", t.ID)
+			if p.cfg.AuditLogger != nil {
+				p.cfg.AuditLogger.Log(security.AuditEvent{
+					Type:      security.EventFileChange,
+					SessionID: session.ID,
+					SenderID:  env.Message.Sender.ID,
+					Channel:   env.Key.Channel,
+					ChatID:    env.Key.ChatID,
+					Detail:    t.Summary(),
+					Metadata:  map[string]string{"action": "undo", "turn": strconv.FormatInt(t.ID, 10)},
+				})
+			}
+			text = "Reverted " + t.Summary()
+		}
+	}
+
+	reply := message.NewTextMessage(env.Message.Chat, text)
+	reply.Channel = env.Message.Channel
+	reply.ThreadID = env.Message.ThreadID
+	if err := p.cfg.ResponseSender.Send(ctx, reply); err != nil {
+		logger.Error("pipeline: /undo failed to send reply", "error", err)
+	}
+}
+
 // sendError sends a user-friendly error message via ResponseSender. Never panics.
 func (p *Pipeline) sendError(ctx context.Context, original message.InboundMessage, text string) {
 	errMsg := message.NewTextMessage(original.Chat, text)
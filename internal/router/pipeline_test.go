@@ -4,18 +4,24 @@ import (
 	"context"
 	"errors"
 	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/flemzord/sclaw/internal/activity"
 	"github.com/flemzord/sclaw/internal/agent"
 	"github.com/flemzord/sclaw/internal/channel"
 	"github.com/flemzord/sclaw/internal/channel/channeltest"
+	ctxengine "github.com/flemzord/sclaw/internal/context"
 	"github.com/flemzord/sclaw/internal/hook"
 	"github.com/flemzord/sclaw/internal/memory"
 	"github.com/flemzord/sclaw/internal/provider"
 	"github.com/flemzord/sclaw/internal/provider/providertest"
+	"github.com/flemzord/sclaw/internal/snapshot"
 	"github.com/flemzord/sclaw/pkg/message"
 )
 
@@ -1320,6 +1326,142 @@ func TestPipeline_SkillResolver_InjectsSkills(t *testing.T) {
 	}
 }
 
+// testContextResolver implements ContextResolver.
+type testContextResolver struct {
+	window int
+}
+
+func (r *testContextResolver) ResolveContext(_ string) (ctxengine.TokenEstimator, int) {
+	return ctxengine.NewCharEstimator(4), r.window
+}
+
+func TestPipeline_ContextResolver_FitsHistory(t *testing.T) {
+	t.Parallel()
+
+	var captured provider.CompletionRequest
+	mockProv := &providertest.MockProvider{
+		CompleteFunc: func(_ context.Context, req provider.CompletionRequest) (provider.CompletionResponse, error) {
+			captured = req
+			return provider.CompletionResponse{Content: "OK", FinishReason: provider.FinishReasonStop}, nil
+		},
+		ContextWindowSizeFunc: func() int { return 4096 },
+		ModelNameFunc:         func() string { return "test-model" },
+	}
+	loop := agent.NewLoop(mockProv, nil, agent.LoopConfig{})
+
+	// Ten old messages of ~1000 tokens each cannot all fit in 4096 tokens
+	// with 1024 reserved for the reply.
+	store := NewInMemorySessionStore()
+	env := testEnvelope()
+	session, _ := store.GetOrCreate(env.Key)
+	for range 10 {
+		session.History = append(session.History, provider.LLMMessage{
+			Role:    provider.MessageRoleUser,
+			Content: strings.Repeat("x", 4000),
+		})
+	}
+
+	pipeline := NewPipeline(PipelineConfig{
+		Store:           store,
+		LaneLock:        NewLaneLock(),
+		GroupPolicy:     GroupPolicy{Mode: GroupPolicyAllowAll},
+		ApprovalManager: NewApprovalManager(),
+		AgentFactory: &agentIDSettingFactory{
+			inner:   &testAgentFactory{loop: loop},
+			agentID: "bot",
+		},
+		ResponseSender:  &testResponseSender{},
+		Logger:          slog.Default(),
+		ContextResolver: &testContextResolver{window: 4096},
+	})
+
+	result := pipeline.Execute(context.Background(), env)
+	if result.Error != nil {
+		t.Fatalf("unexpected error: %v", result.Error)
+	}
+
+	var history []provider.LLMMessage
+	for _, m := range captured.Messages {
+		if m.Role != provider.MessageRoleSystem {
+			history = append(history, m)
+		}
+	}
+	if len(history) == 0 || len(history) >= 11 {
+		t.Fatalf("sent %d history messages, want the oldest left out", len(history))
+	}
+	if last := history[len(history)-1]; last.Content != "Hello" {
+		t.Errorf("last message = %q, want the new user message", last.Content)
+	}
+	if len(session.History) != 12 {
+		t.Errorf("session history has %d messages, want all 12 kept", len(session.History))
+	}
+}
+
+// testSectionSkillResolver implements SkillSectionResolver.
+type testSectionSkillResolver struct {
+	testSkillResolver
+	stable   string
+	volatile string
+}
+
+func (r *testSectionSkillResolver) ResolveSkillSections(_, _ string) (string, string, error) {
+	return r.stable, r.volatile, nil
+}
+
+func TestPipeline_SkillResolver_SplitsStableAndVolatile(t *testing.T) {
+	t.Parallel()
+
+	var captured provider.CompletionRequest
+	mockProv := &providertest.MockProvider{
+		CompleteFunc: func(_ context.Context, req provider.CompletionRequest) (provider.CompletionResponse, error) {
+			captured = req
+			return provider.CompletionResponse{
+				Content:      "OK",
+				FinishReason: provider.FinishReasonStop,
+			}, nil
+		},
+		ContextWindowSizeFunc: func() int { return 4096 },
+		ModelNameFunc:         func() string { return "test-model" },
+	}
+	loop := agent.NewLoop(mockProv, nil, agent.LoopConfig{})
+
+	pipeline := NewPipeline(PipelineConfig{
+		Store:           NewInMemorySessionStore(),
+		LaneLock:        NewLaneLock(),
+		GroupPolicy:     GroupPolicy{Mode: GroupPolicyAllowAll},
+		ApprovalManager: NewApprovalManager(),
+		AgentFactory: &agentIDSettingFactory{
+			inner:   &testAgentFactory{loop: loop},
+			agentID: "bot",
+		},
+		ResponseSender: &testResponseSender{},
+		Logger:         slog.Default(),
+		SkillResolver:  &testSectionSkillResolver{stable: "ALWAYS-SKILL", volatile: "TRIGGERED-SKILL"},
+	})
+
+	result := pipeline.Execute(context.Background(), testEnvelope())
+	if result.Error != nil {
+		t.Fatalf("unexpected error: %v", result.Error)
+	}
+	if len(captured.Messages) < 3 {
+		t.Fatalf("expected stable, volatile and user messages, got %d", len(captured.Messages))
+	}
+
+	stable, volatile := captured.Messages[0], captured.Messages[1]
+	if !strings.Contains(stable.Content, "ALWAYS-SKILL") || strings.Contains(stable.Content, "TRIGGERED-SKILL") {
+		t.Errorf("stable system prompt = %q", stable.Content)
+	}
+	if stable.CacheControl != provider.CacheControlEphemeral {
+		t.Errorf("stable system prompt should be a cache breakpoint")
+	}
+	if volatile.Role != provider.MessageRoleSystem || volatile.Content != "TRIGGERED-SKILL" {
+		t.Errorf("volatile system message = %+v", volatile)
+	}
+	if captured.CacheKey != "bot" {
+		t.Errorf("CacheKey = %q, want %q", captured.CacheKey, "bot")
+	}
+}
+
 func TestPipeline_SkillResolver_Nil(t *testing.T) {
 	t.Parallel()
 
@@ -1403,3 +1545,143 @@ func TestPipeline_Streaming_HooksInvoked(t *testing.T) {
 		}
 	}
 }
+
+// testWorkspaceHistory resolves every agent to the same snapshot store.
+type testWorkspaceHistory struct {
+	store *snapshot.Store
+}
+
+func (r *testWorkspaceHistory) ResolveWorkspaceHistory(string) *snapshot.Store {
+	return r.store
+}
+
+func TestPipeline_UndoCommand(t *testing.T) {
+	t.Parallel()
+
+	workspace := t.TempDir()
+	snapshots, err := snapshot.New(snapshot.Config{Workspace: workspace, Dir: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(workspace, "notes.txt")
+	if err := os.WriteFile(path, []byte("original"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	env := testEnvelope()
+	env.Message.Blocks = []message.ContentBlock{message.NewTextBlock("/undo")}
+
+	// A previous turn in this conversation changed the file.
+	snapshots.NewRecorder(env.Key.String(), "rewrite my notes").Track("write_file", func() {
+		if err := os.WriteFile(path, []byte("mangled"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	sender := &testResponseSender{}
+	pipeline := NewPipeline(PipelineConfig{
+		Store:           NewInMemorySessionStore(),
+		LaneLock:        NewLaneLock(),
+		GroupPolicy:     GroupPolicy{Mode: GroupPolicyAllowAll},
+		ApprovalManager: NewApprovalManager(),
+		AgentFactory: &agentIDSettingFactory{
+			inner:   &testAgentFactory{err: errors.New("agent must not run")},
+			agentID: "main",
+		},
+		ResponseSender:   sender,
+		Logger:           slog.Default(),
+		WorkspaceHistory: &testWorkspaceHistory{store: snapshots},
+	})
+
+	result := pipeline.Execute(context.Background(), env)
+	if !result.Skipped {
+		t.Error("expected /undo to skip the agent")
+	}
+	if data, _ := os.ReadFile(path); string(data) != "original" {
+		t.Errorf("notes.txt = %q, want original", data)
+	}
+
+	// A second /undo has nothing left to revert.
+	pipeline.Execute(context.Background(), env)
+
+	sent := sender.sentMessages()
+	if len(sent) != 2 {
+		t.Fatalf("sent %d messages, want 2", len(sent))
+	}
+	if got := sent[0].TextContent(); !strings.Contains(got, "Reverted turn #1") || !strings.Contains(got, "modified notes.txt") {
+		t.Errorf("first reply = %q", got)
+	}
+	if got := sent[1].TextContent(); got != "Nothing to undo." {
+		t.Errorf("second reply = %q", got)
+	}
+}
+
+func TestPipeline_UndoCommand_Unavailable(t *testing.T) {
+	t.Parallel()
+
+	env := testEnvelope()
+	env.Message.Blocks = []message.ContentBlock{message.NewTextBlock("/undo")}
+
+	sender := &testResponseSender{}
+	pipeline := NewPipeline(PipelineConfig{
+		Store:           NewInMemorySessionStore(),
+		LaneLock:        NewLaneLock(),
+		GroupPolicy:     GroupPolicy{Mode: GroupPolicyAllowAll},
+		ApprovalManager: NewApprovalManager(),
+		AgentFactory:    &testAgentFactory{err: errors.New("agent must not run")},
+		ResponseSender:  sender,
+		Logger:          slog.Default(),
+	})
+
+	pipeline.Execute(context.Background(), env)
+	sent := sender.sentMessages()
+	if len(sent) != 1 || !strings.Contains(sent[0].TextContent(), "not enabled") {
+		t.Errorf("sent = %+v", sent)
+	}
+}
+
+func TestPipeline_ActivityEvents(t *testing.T) {
+	t.Parallel()
+
+	bus := activity.NewBus()
+	sub := bus.Subscribe(activity.Filter{}, 16)
+	defer sub.Close()
+
+	loop := agent.NewLoop(newTestMockProvider("Response"), nil, agent.LoopConfig{})
+	pipeline := NewPipeline(PipelineConfig{
+		Store:           NewInMemorySessionStore(),
+		LaneLock:        NewLaneLock(),
+		GroupPolicy:     GroupPolicy{Mode: GroupPolicyRequireMention},
+		ApprovalManager: NewApprovalManager(),
+		AgentFactory:    &testAgentFactory{loop: loop},
+		ResponseSender:  &testResponseSender{},
+		Logger:          slog.Default(),
+		MaxHistoryLen:   1,
+		Activity:        bus,
+	})
+
+	group := message.InboundMessage{
+		ID:      "msg-2",
+		Channel: "slack",
+		Sender:  message.Sender{ID: "user-2"},
+		Chat:    message.Chat{ID: "G456", Type: message.ChatGroup},
+		Blocks:  []message.ContentBlock{message.NewTextBlock("Hello group")},
+	}
+	pipeline.Execute(context.Background(), envelope{Message: group, Key: SessionKeyFromMessage(group)})
+	pipeline.Execute(context.Background(), testEnvelope())
+
+	var got []string
+	for len(sub.Events()) > 0 {
+		e := <-sub.Events()
+		entry := string(e.Type)
+		if reason, ok := e.Data["reason"]; ok {
+			entry += ":" + reason.(string)
+		}
+		got = append(got, entry)
+	}
+	// The assistant reply pushes the user message out of the history.
+	want := []string{"message_dropped:group_policy", "message_accepted", "compaction"}
+	if !slices.Equal(got, want) {
+		t.Errorf("events = %v, want %v", got, want)
+	}
+}
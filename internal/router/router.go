@@ -7,6 +7,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/flemzord/sclaw/internal/activity"
 	"github.com/flemzord/sclaw/internal/hook"
 	"github.com/flemzord/sclaw/internal/security"
 	"github.com/flemzord/sclaw/pkg/message"
@@ -57,6 +58,26 @@ type Config struct {
 	// SkillResolver, if non-nil, provides per-agent skill sections appended
 	// to the system prompt. Nil means no skills (backward compatible).
 	SkillResolver SkillResolver
+
+	// ContextResolver, if non-nil, provides per-agent token estimators used
+	// to drop the oldest history that does not fit the context window. Nil
+	// means history is only capped by message count.
+	ContextResolver ContextResolver
+
+	// WorkspaceHistory, if non-nil, provides the snapshot stores the /undo
+	// command reverts. Nil means /undo is unavailable.
+	WorkspaceHistory WorkspaceHistoryResolver
+
+	// AuditLogger, if non-nil, records session creation and /undo.
+	AuditLogger *security.AuditLogger
+
+	// OnSessionEnd, if non-nil, is called when a session is pruned or
+	// deleted, so per-session state kept elsewhere can be released.
+	OnSessionEnd func(agentID, sessionID string)
+
+	// Activity, if non-nil, receives live events: messages accepted or
+	// dropped, history trims and tool approvals.
+	Activity *activity.Bus
 }
 
 // withDefaults returns a copy of the config with zero values replaced by defaults.
@@ -113,25 +134,32 @@ func NewRouter(cfg Config) (*Router, error) {
 	if cfg.MaxSessions > 0 {
 		store.SetMaxSessions(cfg.MaxSessions)
 	}
+	if cfg.OnSessionEnd != nil {
+		store.SetOnRemove(func(s *Session) { cfg.OnSessionEnd(s.AgentID, s.ID) })
+	}
 	laneLock := NewLaneLock()
 	approvalMgr := NewApprovalManager()
 	pruner := newLazyPruner(store, laneLock, cfg.MaxIdle)
 
 	pipeline := NewPipeline(PipelineConfig{
-		Store:           store,
-		LaneLock:        laneLock,
-		GroupPolicy:     cfg.GroupPolicy,
-		ApprovalManager: approvalMgr,
-		AgentFactory:    cfg.AgentFactory,
-		ResponseSender:  cfg.ResponseSender,
-		ChannelLookup:   cfg.ChannelLookup,
-		StreamSender:    cfg.StreamSender,
-		Pruner:          pruner,
-		Logger:          cfg.Logger,
-		HookPipeline:    cfg.HookPipeline,
-		HistoryResolver: cfg.HistoryResolver,
-		SoulResolver:    cfg.SoulResolver,
-		SkillResolver:   cfg.SkillResolver,
+		Store:            store,
+		LaneLock:         laneLock,
+		GroupPolicy:      cfg.GroupPolicy,
+		ApprovalManager:  approvalMgr,
+		AgentFactory:     cfg.AgentFactory,
+		ResponseSender:   cfg.ResponseSender,
+		ChannelLookup:    cfg.ChannelLookup,
+		StreamSender:     cfg.StreamSender,
+		Pruner:           pruner,
+		Logger:           cfg.Logger,
+		HookPipeline:     cfg.HookPipeline,
+		HistoryResolver:  cfg.HistoryResolver,
+		SoulResolver:     cfg.SoulResolver,
+		SkillResolver:    cfg.SkillResolver,
+		ContextResolver:  cfg.ContextResolver,
+		WorkspaceHistory: cfg.WorkspaceHistory,
+		AuditLogger:      cfg.AuditLogger,
+		Activity:         cfg.Activity,
 	})
 
 	return &Router{
@@ -179,7 +207,7 @@ func (r *Router) Submit(msg message.InboundMessage) error {
 
 	// Approval bypass: resolve directly without entering inbox or lane lock.
 	if id, resp, ok := r.approvalManager.IsApprovalResponse(msg); ok {
-		if r.approvalManager.Resolve(id, resp) {
+		if r.approvalManager.ResolveFrom(id, SessionKeyFromMessage(msg), msg.Sender.ID, resp) {
 			r.logger.Info("router: approval resolved", "approval_id", id)
 			return nil
 		}
@@ -193,12 +221,14 @@ func (r *Router) Submit(msg message.InboundMessage) error {
 				"size", len(msg.Raw),
 				"channel", msg.Channel,
 			)
+			r.publishDropped(msg, "too_large")
 			return err
 		}
 		if err := security.ValidateJSONDepth(msg.Raw, 0); err != nil {
 			r.logger.Warn("router: message JSON too deep, rejected",
 				"channel", msg.Channel,
 			)
+			r.publishDropped(msg, "too_deep")
 			return err
 		}
 	}
@@ -214,6 +244,7 @@ func (r *Router) Submit(msg message.InboundMessage) error {
 				"chat_id", msg.Chat.ID,
 				"session_id", sessionID,
 			)
+			r.publishDropped(msg, "rate_limit")
 			return err
 		}
 	}
@@ -228,10 +259,21 @@ func (r *Router) Submit(msg message.InboundMessage) error {
 			"channel", key.Channel,
 			"chat_id", key.ChatID,
 		)
+		r.publishDropped(msg, "inbox_full")
 		return ErrInboxFull
 	}
 }
 
+// publishDropped reports a message refused before reaching the pipeline.
+func (r *Router) publishDropped(msg message.InboundMessage, reason string) {
+	r.config.Activity.Publish(context.Background(), activity.Event{
+		Type:    activity.TypeMessageDropped,
+		Channel: msg.Channel,
+		ChatID:  msg.Chat.ID,
+		Data:    map[string]any{"reason": reason, "sender_id": msg.Sender.ID},
+	})
+}
+
 // Stop gracefully shuts down the router: closes inbox, drains workers, cancels context.
 func (r *Router) Stop(_ context.Context) {
 	r.stopOnce.Do(func() {
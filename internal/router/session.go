@@ -15,6 +15,12 @@ type SessionKey struct {
 	ThreadID string
 }
 
+// String returns the key as "channel:chat:thread". It is stable across
+// restarts and is used to persist per-conversation state.
+func (k SessionKey) String() string {
+	return k.Channel + ":" + k.ChatID + ":" + k.ThreadID
+}
+
 // SessionKeyFromMessage derives a SessionKey from an inbound message.
 // Messages in the same channel/chat/thread share a session.
 func SessionKeyFromMessage(msg message.InboundMessage) SessionKey {
@@ -20,6 +20,9 @@ type InMemorySessionStore struct {
 	// Zero means unlimited.
 	maxSessions int
 
+	// onRemove, if non-nil, is called for each deleted or pruned session.
+	onRemove func(*Session)
+
 	// now is injectable for testing. Defaults to time.Now.
 	now func() time.Time
 }
@@ -40,6 +43,15 @@ func (s *InMemorySessionStore) SetMaxSessions(limit int) {
 	s.maxSessions = limit
 }
 
+// SetOnRemove registers fn to be called, with the store locked, for each
+// session removed by Delete, Prune or PruneByAgent. fn must not call back
+// into the store.
+func (s *InMemorySessionStore) SetOnRemove(fn func(*Session)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onRemove = fn
+}
+
 // GetOrCreate returns the existing session for the key, or creates a new
 // one if none exists. The bool return is true when a new session was created.
 // If maxSessions > 0 and the limit is reached, no new session is created
@@ -99,7 +111,9 @@ func (s *InMemorySessionStore) Touch(key SessionKey) {
 func (s *InMemorySessionStore) Delete(key SessionKey) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	delete(s.sessions, key)
+	if sess, ok := s.sessions[key]; ok {
+		s.removeLocked(key, sess)
+	}
 }
 
 // Prune removes sessions whose idle time exceeds maxIdle and returns the
@@ -113,7 +127,7 @@ func (s *InMemorySessionStore) Prune(maxIdle time.Duration) int {
 	pruned := 0
 	for key, sess := range s.sessions {
 		if now.Sub(sess.LastActiveAt) > maxIdle {
-			delete(s.sessions, key)
+			s.removeLocked(key, sess)
 			pruned++
 		}
 	}
@@ -133,13 +147,21 @@ func (s *InMemorySessionStore) PruneByAgent(agentID string, maxIdle time.Duratio
 			continue
 		}
 		if now.Sub(sess.LastActiveAt) > maxIdle {
-			delete(s.sessions, key)
+			s.removeLocked(key, sess)
 			pruned++
 		}
 	}
 	return pruned
 }
 
+// removeLocked deletes a session and notifies onRemove. Callers hold s.mu.
+func (s *InMemorySessionStore) removeLocked(key SessionKey, sess *Session) {
+	delete(s.sessions, key)
+	if s.onRemove != nil {
+		s.onRemove(sess)
+	}
+}
+
 // Len returns the number of active sessions.
 func (s *InMemorySessionStore) Len() int {
 	s.mu.RLock()
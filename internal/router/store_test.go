@@ -198,6 +198,25 @@ func TestInMemoryStore_Prune_NoneExpired(t *testing.T) {
 	}
 }
 
+func TestInMemoryStore_OnRemove(t *testing.T) {
+	t.Parallel()
+
+	store, ft := newTestStore()
+	var removed []string
+	store.SetOnRemove(func(s *Session) { removed = append(removed, s.Key.ChatID) })
+
+	store.GetOrCreate(SessionKey{Channel: "slack", ChatID: "deleted"})
+	store.GetOrCreate(SessionKey{Channel: "slack", ChatID: "pruned"})
+	store.Delete(SessionKey{Channel: "slack", ChatID: "deleted"})
+	store.Delete(SessionKey{Channel: "slack", ChatID: "missing"})
+	ft.Advance(10 * time.Minute)
+	store.Prune(5 * time.Minute)
+
+	if len(removed) != 2 || removed[0] != "deleted" || removed[1] != "pruned" {
+		t.Errorf("removed = %v, want [deleted pruned]", removed)
+	}
+}
+
 func TestInMemoryStore_Concurrent(t *testing.T) {
 	t.Parallel()
 
@@ -22,7 +22,9 @@ const (
 	EventConfigChange  EventType = "config_change"
 	EventSessionCreate EventType = "session_create"
 	EventSessionDelete EventType = "session_delete"
+	EventSessionAdmin  EventType = "session_admin"
 	EventRateLimit     EventType = "rate_limit"
+	EventFileChange    EventType = "file_change"
 )
 
 // AuditEvent is a single audit log entry.
@@ -104,7 +104,7 @@ func TestAuditLogger_AllEventTypes(t *testing.T) {
 	types := []EventType{
 		EventMessage, EventToolCall, EventToolResult, EventApproval,
 		EventAuthSuccess, EventAuthFailure, EventConfigChange,
-		EventSessionCreate, EventSessionDelete, EventRateLimit,
+		EventSessionCreate, EventSessionDelete, EventSessionAdmin, EventRateLimit,
 	}
 
 	var buf bytes.Buffer
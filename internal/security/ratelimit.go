@@ -15,6 +15,7 @@ type RateLimitConfig struct {
 	MessagesPerMin  int `yaml:"messages_per_min"`
 	ToolCallsPerMin int `yaml:"tool_calls_per_min"`
 	TokensPerHour   int `yaml:"tokens_per_hour"`
+	NetworkPerMin   int `yaml:"network_per_min"`
 }
 
 // rateLimitConfigDefaults returns a config with sensible defaults.
@@ -24,6 +25,7 @@ func rateLimitConfigDefaults() RateLimitConfig {
 		MessagesPerMin:  200,
 		ToolCallsPerMin: 500,
 		TokensPerHour:   0, // 0 = unlimited
+		NetworkPerMin:   60,
 	}
 }
 
@@ -62,6 +64,9 @@ func NewRateLimiter(cfg RateLimitConfig) *RateLimiter {
 	if cfg.ToolCallsPerMin <= 0 {
 		cfg.ToolCallsPerMin = defaults.ToolCallsPerMin
 	}
+	if cfg.NetworkPerMin <= 0 {
+		cfg.NetworkPerMin = defaults.NetworkPerMin
+	}
 
 	templates := map[string]bucketTemplate{
 		"message": {
@@ -72,6 +77,11 @@ func NewRateLimiter(cfg RateLimitConfig) *RateLimiter {
 			window: time.Minute,
 			limit:  cfg.ToolCallsPerMin,
 		},
+		// Outbound requests made by network tools (web search, fetches).
+		"network": {
+			window: time.Minute,
+			limit:  cfg.NetworkPerMin,
+		},
 	}
 
 	if cfg.TokensPerHour > 0 {
@@ -116,7 +126,7 @@ func (rl *RateLimiter) getOrCreateBucket(sessionID, kind string) *bucket {
 
 // Allow checks whether an event of the given kind is allowed for the session.
 // Returns nil if allowed, ErrRateLimited if the limit is exceeded.
-// kind must be one of: "message", "tool_call", "token".
+// kind must be one of: "message", "tool_call", "network", "token".
 func (rl *RateLimiter) Allow(sessionID, kind string) error {
 	rl.mu.Lock()
 	defer rl.mu.Unlock()
@@ -77,6 +77,26 @@ func TestRateLimiter_ToolCallBucket(t *testing.T) {
 	}
 }
 
+func TestRateLimiter_NetworkBucket(t *testing.T) {
+	t.Parallel()
+
+	rl := NewRateLimiter(RateLimitConfig{NetworkPerMin: 2})
+
+	for range 2 {
+		if err := rl.Allow("sess1", "network"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if err := rl.Allow("sess1", "network"); !errors.Is(err, ErrRateLimited) {
+		t.Fatal("expected rate limit for network")
+	}
+	// Network requests are counted apart from tool calls.
+	if err := rl.Allow("sess1", "tool_call"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
 func TestRateLimiter_TokenBucket(t *testing.T) {
 	t.Parallel()
 
@@ -116,6 +136,9 @@ func TestRateLimiter_Defaults(t *testing.T) {
 	if rl.config.ToolCallsPerMin != 500 {
 		t.Errorf("default ToolCallsPerMin = %d, want 500", rl.config.ToolCallsPerMin)
 	}
+	if rl.config.NetworkPerMin != 60 {
+		t.Errorf("default NetworkPerMin = %d, want 60", rl.config.NetworkPerMin)
+	}
 }
 
 func TestRateLimiter_ConcurrentAccess(t *testing.T) {
@@ -84,12 +84,17 @@ func (r *Redactor) Redact(s string) string {
 	return s
 }
 
+// IsSecretKey reports whether a config key likely holds a secret.
+func IsSecretKey(key string) bool {
+	return secretKeyPattern.MatchString(key)
+}
+
 // RedactMap walks a map and replaces values whose keys match common secret
 // key names (secret, token, password, key, api_key, credential).
 // This is used for config display endpoints.
 func (r *Redactor) RedactMap(m map[string]any) {
 	for k, v := range m {
-		if secretKeyPattern.MatchString(k) {
+		if IsSecretKey(k) {
 			if s, ok := v.(string); ok && s != "" {
 				m[k] = RedactPlaceholder
 				continue
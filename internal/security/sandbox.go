@@ -2,7 +2,11 @@ package security
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"os"
 	"os/exec"
 	"path/filepath"
 	"slices"
@@ -11,6 +15,87 @@ import (
 	"time"
 )
 
+// Sandbox backends.
+const (
+	SandboxBackendDocker = "docker"
+	SandboxBackendLinux  = "linux"
+)
+
+// ErrSandboxUnavailable is returned when a command requires a sandbox that
+// cannot be used on this host. Commands are never run unsandboxed instead.
+var ErrSandboxUnavailable = errors.New("sandbox: unavailable")
+
+// SandboxMount exposes a host directory inside the sandbox at the same path.
+type SandboxMount struct {
+	Path     string
+	ReadOnly bool
+}
+
+// SandboxSpec describes a shell command to run in a sandbox.
+type SandboxSpec struct {
+	// Command is passed to sh -c.
+	Command string
+
+	// Dir is the workspace: mounted read-write and used as working directory.
+	Dir string
+
+	// Env is the complete environment of the command.
+	Env []string
+
+	// Mounts are additional host directories made visible to the command.
+	Mounts []SandboxMount
+
+	// Interactive keeps the command's stdin connected, for long-lived
+	// processes driven through pipes.
+	Interactive bool
+
+	// Limits, if non-nil, overrides the sandbox's resource limits for this
+	// command. Zero fields keep the sandbox's value; Timeout is left to the
+	// caller's context.
+	Limits *ResourceLimits
+}
+
+// Sandbox prepares commands that run isolated from the host.
+type Sandbox interface {
+	// Command returns a command that runs spec inside the sandbox. The
+	// caller starts it, waits for it and then calls release.
+	Command(ctx context.Context, spec SandboxSpec) (cmd *exec.Cmd, release func(), err error)
+}
+
+// SandboxOptions configures NewSandbox.
+type SandboxOptions struct {
+	// Backend selects the isolation mechanism: "docker" (default) or
+	// "linux" (namespaces, Landlock and seccomp; no daemon required).
+	Backend string
+
+	Policy SandboxPolicy
+	Limits ResourceLimits
+
+	// Image is the Docker image. Ignored by the linux backend.
+	Image string
+
+	// CgroupParent is a delegated cgroup v2 directory under which the linux
+	// backend creates one child cgroup per command to enforce Limits.
+	// Empty disables cgroup limits. Ignored by the docker backend.
+	CgroupParent string
+}
+
+// NewSandbox returns the sandbox backend selected by opts.
+func NewSandbox(opts SandboxOptions) (Sandbox, error) {
+	switch opts.Backend {
+	case "", SandboxBackendDocker:
+		return NewSandboxExecutor(opts.Policy, opts.Limits, opts.Image), nil
+	case SandboxBackendLinux:
+		sb, err := NewNamespaceSandbox(opts.Limits, opts.CgroupParent)
+		if err != nil {
+			return nil, err
+		}
+		return sb, nil
+	default:
+		return nil, fmt.Errorf("sandbox: unknown backend %q", opts.Backend)
+	}
+}
+
 // SandboxPolicy defines when and how tools should be sandboxed.
 type SandboxPolicy struct {
 	// Enabled activates sandboxing. When false, no sandboxing occurs.
@@ -46,13 +131,15 @@ func (p SandboxPolicy) ShouldSandbox(scopes []string) bool {
 
 // ResourceLimits defines resource constraints for sandboxed execution.
 type ResourceLimits struct {
-	// CPUShares is the relative CPU weight (Docker --cpu-shares).
+	// CPUShares is the relative CPU weight (Docker --cpu-shares, mapped to
+	// cpu.weight by the linux backend).
 	CPUShares int `yaml:"cpu_shares"`
 
-	// MemoryMB is the memory limit in megabytes (Docker --memory).
+	// MemoryMB is the memory limit in megabytes (Docker --memory, cgroup
+	// memory.max).
 	MemoryMB int `yaml:"memory_mb"`
 
-	// DiskMB is the disk size limit in megabytes (Docker --storage-opt size).
+	// DiskMB is the size limit of the writable /tmp in megabytes.
 	DiskMB int `yaml:"disk_mb"`
 
 	// Timeout is the maximum execution duration.
@@ -69,6 +156,44 @@ func resourceLimitsDefaults() ResourceLimits {
 	}
 }
 
+// override returns l with the non-zero fields of o, if o is non-nil.
+func (l ResourceLimits) override(o *ResourceLimits) ResourceLimits {
+	if o == nil {
+		return l
+	}
+	if o.CPUShares > 0 {
+		l.CPUShares = o.CPUShares
+	}
+	if o.MemoryMB > 0 {
+		l.MemoryMB = o.MemoryMB
+	}
+	if o.DiskMB > 0 {
+		l.DiskMB = o.DiskMB
+	}
+	if o.Timeout > 0 {
+		l.Timeout = o.Timeout
+	}
+	return l
+}
+
+// withDefaults replaces zero-value limits with defaults.
+func (l ResourceLimits) withDefaults() ResourceLimits {
+	defaults := resourceLimitsDefaults()
+	if l.CPUShares <= 0 {
+		l.CPUShares = defaults.CPUShares
+	}
+	if l.MemoryMB <= 0 {
+		l.MemoryMB = defaults.MemoryMB
+	}
+	if l.DiskMB <= 0 {
+		l.DiskMB = defaults.DiskMB
+	}
+	if l.Timeout <= 0 {
+		l.Timeout = defaults.Timeout
+	}
+	return l
+}
+
 // SandboxExecutor wraps command execution in a Docker container when
 // sandboxing is required. If Docker is not available, it returns an error
 // rather than running unsandboxed (fail-closed).
@@ -81,19 +206,7 @@ type SandboxExecutor struct {
 // NewSandboxExecutor creates a sandbox executor with the given policy and limits.
 // Zero-value limits are replaced with defaults.
 func NewSandboxExecutor(policy SandboxPolicy, limits ResourceLimits, image string) *SandboxExecutor {
-	defaults := resourceLimitsDefaults()
-	if limits.CPUShares <= 0 {
-		limits.CPUShares = defaults.CPUShares
-	}
-	if limits.MemoryMB <= 0 {
-		limits.MemoryMB = defaults.MemoryMB
-	}
-	if limits.DiskMB <= 0 {
-		limits.DiskMB = defaults.DiskMB
-	}
-	if limits.Timeout <= 0 {
-		limits.Timeout = defaults.Timeout
-	}
+	limits = limits.withDefaults()
 	if image == "" {
 		image = "alpine:3.19"
 	}
@@ -105,7 +218,8 @@ func NewSandboxExecutor(policy SandboxPolicy, limits ResourceLimits, image strin
 	}
 }
 
-// Execute runs a command in a sandboxed Docker container.
+// Execute runs a command in a sandboxed Docker container with the workspace
+// mounted read-only at /workspace.
 // Returns the combined stdout/stderr output or an error.
 func (s *SandboxExecutor) Execute(ctx context.Context, command string, workdir string, env []string) ([]byte, error) {
 	if !s.policy.Enabled {
@@ -119,26 +233,15 @@ func (s *SandboxExecutor) Execute(ctx context.Context, command string, workdir s
 		defer cancel()
 	}
 
-	if workdir != "" {
-		cleaned := filepath.Clean(workdir)
-		if strings.Contains(cleaned, ":") {
-			return nil, fmt.Errorf("sandbox: workdir contains invalid character: %q", workdir)
-		}
+	if err := checkDockerPath(workdir); err != nil {
+		return nil, err
 	}
 
-	args := []string{
-		"run", "--rm",
-		"--read-only",
-		"--network=none",
-		"--cap-drop", "ALL",
-		"--security-opt", "no-new-privileges:true",
-		"--user", "65534:65534",
-		"--pids-limit", "256",
-		"--cpu-shares", strconv.Itoa(s.limits.CPUShares),
-		"--memory", strconv.Itoa(s.limits.MemoryMB) + "m",
-		"--tmpfs", "/tmp:rw,noexec,nosuid,size=" + strconv.Itoa(s.limits.DiskMB) + "m",
+	name, err := containerName()
+	if err != nil {
+		return nil, err
 	}
-
+	args := s.baseArgs(name, "65534:65534", s.limits)
 	if workdir != "" {
 		args = append(args, "-v", workdir+":/workspace:ro", "-w", "/workspace")
 	}
@@ -148,10 +251,125 @@ func (s *SandboxExecutor) Execute(ctx context.Context, command string, workdir s
 	}
 
 	args = append(args, s.image, "sh", "-c", command)
+	return dockerCommand(ctx, name, args).CombinedOutput()
+}
+
+// Command implements Sandbox. The workspace and mounts keep their host
+// paths so that paths seen by the agent stay valid inside the container,
+// and the command runs with the caller's UID so files it creates in the
+// workspace stay owned by the host user.
+func (s *SandboxExecutor) Command(ctx context.Context, spec SandboxSpec) (*exec.Cmd, func(), error) {
+	if !IsDockerAvailable() {
+		return nil, nil, fmt.Errorf("%w: docker not found on PATH", ErrSandboxUnavailable)
+	}
+	name, err := containerName()
+	if err != nil {
+		return nil, nil, err
+	}
+	args, err := s.commandArgs(name, spec, os.Getuid(), os.Getgid())
+	if err != nil {
+		return nil, nil, err
+	}
+	return dockerCommand(ctx, name, args), func() {}, nil
+}
+
+// dockerKillTimeout bounds the `docker kill` run when a command is
+// cancelled.
+const dockerKillTimeout = 10 * time.Second
+
+// dockerWaitDelay is how long Wait waits for the docker CLI's output to
+// close after the container was killed.
+const dockerWaitDelay = 5 * time.Second
 
+// dockerCommand returns a docker command running the container name.
+// Killing the docker CLI does not stop the container, so cancelling ctx
+// kills the container itself before the CLI.
+func dockerCommand(ctx context.Context, name string, args []string) *exec.Cmd {
 	//nolint:gosec // args are constructed programmatically from validated input.
 	cmd := exec.CommandContext(ctx, "docker", args...)
-	return cmd.CombinedOutput()
+	cmd.Cancel = func() error {
+		killContainer(name)
+		return cmd.Process.Kill()
+	}
+	cmd.WaitDelay = dockerWaitDelay
+	return cmd
+}
+
+// killContainer kills the container name, ignoring errors: the container
+// may not have started yet or may already be gone.
+func killContainer(name string) {
+	ctx, cancel := context.WithTimeout(context.Background(), dockerKillTimeout)
+	defer cancel()
+	_ = exec.CommandContext(ctx, "docker", "kill", name).Run() //nolint:gosec // name is generated by containerName.
+}
+
+// containerName returns a unique name for a sandbox container.
+func containerName() (string, error) {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("sandbox: generating container name: %w", err)
+	}
+	return "sclaw-sandbox-" + hex.EncodeToString(b[:]), nil
+}
+
+// commandArgs builds the docker arguments for Command.
+func (s *SandboxExecutor) commandArgs(name string, spec SandboxSpec, uid, gid int) ([]string, error) {
+	user := "65534:65534"
+	if uid > 0 {
+		user = strconv.Itoa(uid) + ":" + strconv.Itoa(gid)
+	}
+	args := s.baseArgs(name, user, s.limits.override(spec.Limits))
+	if spec.Interactive {
+		args = append(args, "--interactive")
+	}
+
+	if spec.Dir != "" {
+		if err := checkDockerPath(spec.Dir); err != nil {
+			return nil, err
+		}
+		args = append(args, "-v", spec.Dir+":"+spec.Dir+":rw", "-w", spec.Dir)
+	}
+	for _, m := range spec.Mounts {
+		if err := checkDockerPath(m.Path); err != nil {
+			return nil, err
+		}
+		mode := "rw"
+		if m.ReadOnly {
+			mode = "ro"
+		}
+		args = append(args, "-v", m.Path+":"+m.Path+":"+mode)
+	}
+	for _, e := range spec.Env {
+		args = append(args, "-e", e)
+	}
+	return append(args, s.image, "sh", "-c", spec.Command), nil
+}
+
+// baseArgs returns the hardening and resource flags shared by every
+// container.
+func (s *SandboxExecutor) baseArgs(name, user string, limits ResourceLimits) []string {
+	return []string{
+		"run", "--rm",
+		"--name", name,
+		"--read-only",
+		"--network=none",
+		"--cap-drop", "ALL",
+		"--security-opt", "no-new-privileges:true",
+		"--user", user,
+		"--pids-limit", "256",
+		"--cpu-shares", strconv.Itoa(limits.CPUShares),
+		"--memory", strconv.Itoa(limits.MemoryMB) + "m",
+		"--tmpfs", "/tmp:rw,noexec,nosuid,size=" + strconv.Itoa(limits.DiskMB) + "m",
+	}
+}
+
+// checkDockerPath rejects host paths that would be misparsed by -v: a ":"
+// would allow volume injection like "/src:/host:rw".
+func checkDockerPath(p string) error {
+	if p != "" && strings.Contains(filepath.Clean(p), ":") {
+		return fmt.Errorf("sandbox: workdir contains invalid character: %q", p)
+	}
+	return nil
 }
 
 // IsDockerAvailable checks if the docker CLI is available on PATH.
@@ -0,0 +1,448 @@
+//go:build linux
+
+package security
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// sandboxInitArg makes the sclaw binary act as the init process of a
+// namespace sandbox. See RunSandboxInit.
+const sandboxInitArg = "__sclaw_sandbox_init"
+
+// sandboxInitExitCode is returned when the sandbox could not be set up,
+// before the command itself started.
+const sandboxInitExitCode = 125
+
+// sandboxSystemDirs are the host directories mounted read-only in every
+// namespace sandbox so that the shell and common tools can run.
+var sandboxSystemDirs = []string{"/bin", "/sbin", "/usr", "/lib", "/lib32", "/lib64", "/libx32", "/etc"}
+
+// sandboxDevices are the device nodes bound into the sandbox /dev.
+var sandboxDevices = []string{"null", "zero", "full", "random", "urandom", "tty"}
+
+// sandboxCgroupControllers are the cgroup v2 controllers limits rely on.
+var sandboxCgroupControllers = []string{"cpu", "memory", "pids"}
+
+// sandboxPidsMax bounds the number of processes, like Docker's --pids-limit.
+const sandboxPidsMax = 256
+
+// NamespaceSandbox runs commands in fresh user, mount, PID, network, IPC and
+// UTS namespaces without a container runtime. The sandbox sees a read-only
+// view of the system directories, a private /tmp, the workspace read-write
+// and the extra mounts with their own mode. Landlock (when the kernel
+// supports it) and a seccomp filter restrict the command further, and
+// cgroup v2 enforces the resource limits when a cgroup parent is set.
+//
+// Commands are started by re-executing the current binary, which must call
+// RunSandboxInit first thing in main.
+type NamespaceSandbox struct {
+	limits       ResourceLimits
+	cgroupParent string
+	exe          string
+}
+
+// NewNamespaceSandbox creates a namespace sandbox. Zero-value limits are
+// replaced with defaults. cgroupParent, when set, must be a cgroup v2
+// directory delegated to the current user.
+func NewNamespaceSandbox(limits ResourceLimits, cgroupParent string) (*NamespaceSandbox, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("%w: locating executable: %w", ErrSandboxUnavailable, err)
+	}
+	if cgroupParent != "" {
+		if err := enableCgroupControllers(cgroupParent); err != nil {
+			return nil, fmt.Errorf("%w: cgroup parent %s: %w", ErrSandboxUnavailable, cgroupParent, err)
+		}
+	}
+	return &NamespaceSandbox{
+		limits:       limits.withDefaults(),
+		cgroupParent: cgroupParent,
+		exe:          exe,
+	}, nil
+}
+
+// sandboxInitSpec is passed from Command to the sandbox init process.
+type sandboxInitSpec struct {
+	Command string         `json:"command"`
+	Dir     string         `json:"dir"`
+	Root    string         `json:"root"`
+	Mounts  []SandboxMount `json:"mounts"`
+	TmpMB   int            `json:"tmp_mb"`
+}
+
+// Command implements Sandbox.
+func (s *NamespaceSandbox) Command(ctx context.Context, spec SandboxSpec) (*exec.Cmd, func(), error) {
+	if !filepath.IsAbs(spec.Dir) {
+		return nil, nil, fmt.Errorf("sandbox: workspace must be an absolute path: %q", spec.Dir)
+	}
+	dir, err := filepath.EvalSymlinks(spec.Dir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("sandbox: workspace: %w", err)
+	}
+	mounts := make([]SandboxMount, 0, len(spec.Mounts))
+	for _, m := range spec.Mounts {
+		resolved, err := filepath.EvalSymlinks(m.Path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("sandbox: mount: %w", err)
+		}
+		mounts = append(mounts, SandboxMount{Path: resolved, ReadOnly: m.ReadOnly})
+	}
+
+	// The new root is assembled on a tmpfs mounted over this directory in
+	// the sandbox's mount namespace; on the host it stays empty.
+	limits := s.limits.override(spec.Limits)
+	root, err := os.MkdirTemp("", "sclaw-sandbox-")
+	if err != nil {
+		return nil, nil, fmt.Errorf("sandbox: creating root: %w", err)
+	}
+	payload, err := json.Marshal(sandboxInitSpec{
+		Command: spec.Command,
+		Dir:     dir,
+		Root:    root,
+		Mounts:  mounts,
+		TmpMB:   limits.DiskMB,
+	})
+	if err != nil {
+		_ = os.Remove(root)
+		return nil, nil, fmt.Errorf("sandbox: encoding spec: %w", err)
+	}
+
+	//nolint:gosec // the executable is the current binary; the payload is data.
+	cmd := exec.CommandContext(ctx, s.exe, sandboxInitArg, string(payload))
+	cmd.Args[0] = "sclaw-sandbox"
+	// A nil Env would inherit the host environment.
+	cmd.Env = append([]string{}, spec.Env...)
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Cloneflags: syscall.CLONE_NEWUSER | syscall.CLONE_NEWNS | syscall.CLONE_NEWPID |
+			syscall.CLONE_NEWNET | syscall.CLONE_NEWIPC | syscall.CLONE_NEWUTS,
+		UidMappings:                []syscall.SysProcIDMap{{ContainerID: 0, HostID: os.Getuid(), Size: 1}},
+		GidMappings:                []syscall.SysProcIDMap{{ContainerID: 0, HostID: os.Getgid(), Size: 1}},
+		GidMappingsEnableSetgroups: false,
+		Pdeathsig:                  syscall.SIGKILL,
+	}
+
+	release := func() { _ = os.Remove(root) }
+	if s.cgroupParent != "" {
+		cg, fd, err := s.newCgroup(limits)
+		if err != nil {
+			release()
+			return nil, nil, err
+		}
+		cmd.SysProcAttr.UseCgroupFD = true
+		cmd.SysProcAttr.CgroupFD = int(fd.Fd())
+		release = func() {
+			_ = fd.Close()
+			removeCgroup(cg)
+			_ = os.Remove(root)
+		}
+	}
+	return cmd, release, nil
+}
+
+// newCgroup creates a child cgroup carrying the resource limits.
+func (s *NamespaceSandbox) newCgroup(limits ResourceLimits) (string, *os.File, error) {
+	dir, err := os.MkdirTemp(s.cgroupParent, "sandbox-")
+	if err != nil {
+		return "", nil, fmt.Errorf("sandbox: creating cgroup: %w", err)
+	}
+	values := map[string]string{
+		"memory.max": strconv.Itoa(limits.MemoryMB << 20),
+		"cpu.weight": strconv.Itoa(cpuSharesToWeight(limits.CPUShares)),
+		"pids.max":   strconv.Itoa(sandboxPidsMax),
+	}
+	for file, value := range values {
+		if err := os.WriteFile(filepath.Join(dir, file), []byte(value), 0o600); err != nil {
+			removeCgroup(dir)
+			return "", nil, fmt.Errorf("sandbox: setting %s: %w", file, err)
+		}
+	}
+	// Not every kernel accounts swap; memory.max alone still applies.
+	_ = os.WriteFile(filepath.Join(dir, "memory.swap.max"), []byte("0"), 0o600)
+
+	fd, err := os.Open(dir)
+	if err != nil {
+		removeCgroup(dir)
+		return "", nil, fmt.Errorf("sandbox: opening cgroup: %w", err)
+	}
+	return dir, fd, nil
+}
+
+// removeCgroup removes a sandbox cgroup. The kernel may need a moment to
+// notice that the last process has exited.
+func removeCgroup(dir string) {
+	for range 10 {
+		if err := os.Remove(dir); err == nil || errors.Is(err, os.ErrNotExist) {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// enableCgroupControllers makes sure the controllers limits rely on are
+// enabled for the children of parent.
+func enableCgroupControllers(parent string) error {
+	data, err := os.ReadFile(filepath.Join(parent, "cgroup.subtree_control"))
+	if err != nil {
+		return err
+	}
+	enabled := strings.Fields(string(data))
+	var missing []string
+	for _, c := range sandboxCgroupControllers {
+		if !slices.Contains(enabled, c) {
+			missing = append(missing, "+"+c)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	return os.WriteFile(filepath.Join(parent, "cgroup.subtree_control"), []byte(strings.Join(missing, " ")), 0o600)
+}
+
+// cpuSharesToWeight converts Docker CPU shares (2-262144) to a cgroup v2
+// cpu.weight (1-10000), using the same mapping as runc.
+func cpuSharesToWeight(shares int) int {
+	shares = min(max(shares, 2), 262144)
+	return 1 + ((shares-2)*9999)/262142
+}
+
+// RunSandboxInit turns the process into the init of a namespace sandbox
+// when it was started as one by NamespaceSandbox, and never returns in that
+// case. Otherwise it returns immediately. Call it first thing in main.
+func RunSandboxInit() {
+	// The init runs as PID 1 of its own PID namespace; refusing anything
+	// else keeps the hidden argument from doing anything on the host.
+	if len(os.Args) != 3 || os.Args[1] != sandboxInitArg || os.Getpid() != 1 {
+		return
+	}
+	// Landlock, seccomp and no_new_privs apply to the calling thread, which
+	// must be the one that executes the command.
+	runtime.LockOSThread()
+
+	var spec sandboxInitSpec
+	err := json.Unmarshal([]byte(os.Args[2]), &spec)
+	if err == nil {
+		err = sandboxInit(spec)
+	}
+	fmt.Fprintf(os.Stderr, "sandbox: %v\n", err)
+	os.Exit(sandboxInitExitCode)
+}
+
+// sandboxInit builds the sandbox filesystem, drops privileges and executes
+// the command. It only returns on error.
+func sandboxInit(spec sandboxInitSpec) error {
+	// Keep every mount below private to the sandbox.
+	if err := unix.Mount("", "/", "", unix.MS_REC|unix.MS_PRIVATE, ""); err != nil {
+		return fmt.Errorf("making mounts private: %w", err)
+	}
+	root := spec.Root
+	if err := unix.Mount("tmpfs", root, "tmpfs", unix.MS_NOSUID|unix.MS_NODEV, "mode=0755,size=16m"); err != nil {
+		return fmt.Errorf("mounting root: %w", err)
+	}
+
+	for _, dir := range sandboxSystemDirs {
+		if err := mountSystemDir(root, dir); err != nil {
+			return err
+		}
+	}
+	if err := mountDevices(root); err != nil {
+		return err
+	}
+	// A fresh /proc only shows the sandbox's processes. It cannot be
+	// mounted when the host masks parts of its own /proc (e.g. inside a
+	// container); commands then run without /proc.
+	if err := os.Mkdir(filepath.Join(root, "proc"), 0o555); err != nil {
+		return err
+	}
+	_ = unix.Mount("proc", filepath.Join(root, "proc"), "proc", unix.MS_NOSUID|unix.MS_NODEV|unix.MS_NOEXEC, "")
+
+	tmp := filepath.Join(root, "tmp")
+	if err := os.Mkdir(tmp, 0o755); err != nil {
+		return err
+	}
+	if err := unix.Mount("tmpfs", tmp, "tmpfs", unix.MS_NOSUID|unix.MS_NODEV,
+		"mode=1777,size="+strconv.Itoa(spec.TmpMB)+"m"); err != nil {
+		return fmt.Errorf("mounting /tmp: %w", err)
+	}
+
+	// Mount parents before children so nested directories stay visible.
+	mounts := append([]SandboxMount{{Path: spec.Dir}}, spec.Mounts...)
+	slices.SortStableFunc(mounts, func(a, b SandboxMount) int { return len(a.Path) - len(b.Path) })
+	for _, m := range mounts {
+		if err := bindMount(root, m.Path, m.ReadOnly); err != nil {
+			return err
+		}
+	}
+
+	if err := pivotRoot(root); err != nil {
+		return err
+	}
+	_ = unix.Sethostname([]byte("sandbox"))
+	if err := os.Chdir(spec.Dir); err != nil {
+		return err
+	}
+
+	if err := dropPrivileges(); err != nil {
+		return err
+	}
+	writable := []string{spec.Dir, "/tmp", "/dev"}
+	for _, m := range spec.Mounts {
+		if !m.ReadOnly {
+			writable = append(writable, m.Path)
+		}
+	}
+	if err := applyLandlock(writable); err != nil {
+		return fmt.Errorf("landlock: %w", err)
+	}
+	if err := applySeccomp(); err != nil {
+		return fmt.Errorf("seccomp: %w", err)
+	}
+
+	return unix.Exec("/bin/sh", []string{"sh", "-c", spec.Command}, os.Environ())
+}
+
+// mountSystemDir exposes a host system directory read-only. Symlinks (as on
+// merged-/usr systems) are recreated instead.
+func mountSystemDir(root, dir string) error {
+	fi, err := os.Lstat(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if fi.Mode()&os.ModeSymlink != 0 {
+		target, err := os.Readlink(dir)
+		if err != nil {
+			return err
+		}
+		return os.Symlink(target, filepath.Join(root, dir))
+	}
+	return bindMount(root, dir, true)
+}
+
+// mountDevices populates /dev with the harmless device nodes. Device nodes
+// cannot be created in a user namespace, so the host ones are bound.
+func mountDevices(root string) error {
+	dev := filepath.Join(root, "dev")
+	if err := os.Mkdir(dev, 0o755); err != nil {
+		return err
+	}
+	for _, name := range sandboxDevices {
+		if _, err := os.Stat("/dev/" + name); err != nil {
+			continue
+		}
+		if err := bindMount(root, "/dev/"+name, false); err != nil {
+			return err
+		}
+	}
+	for name, target := range map[string]string{
+		"fd": "/proc/self/fd", "stdin": "/proc/self/fd/0", "stdout": "/proc/self/fd/1", "stderr": "/proc/self/fd/2",
+	} {
+		if err := os.Symlink(target, filepath.Join(dev, name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// bindMount mounts the host path src at the same path below root.
+func bindMount(root, src string, readOnly bool) error {
+	fi, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	target := filepath.Join(root, src)
+	if fi.IsDir() {
+		err = os.MkdirAll(target, 0o755)
+	} else if err = os.MkdirAll(filepath.Dir(target), 0o755); err == nil {
+		var f *os.File
+		if f, err = os.OpenFile(target, os.O_CREATE|os.O_WRONLY, 0o644); err == nil {
+			err = f.Close()
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("creating mount point for %s: %w", src, err)
+	}
+
+	if err := unix.Mount(src, target, "", unix.MS_BIND|unix.MS_REC, ""); err != nil {
+		return fmt.Errorf("mounting %s: %w", src, err)
+	}
+	if !readOnly {
+		return nil
+	}
+	// A remount in a user namespace must keep the flags the host mount is
+	// locked with.
+	var st unix.Statfs_t
+	if err := unix.Statfs(src, &st); err != nil {
+		return err
+	}
+	flags := uintptr(unix.MS_BIND | unix.MS_REMOUNT | unix.MS_RDONLY | unix.MS_NOSUID | unix.MS_NODEV)
+	for stFlag, msFlag := range map[int64]uintptr{
+		unix.ST_NOEXEC:     unix.MS_NOEXEC,
+		unix.ST_NOATIME:    unix.MS_NOATIME,
+		unix.ST_NODIRATIME: unix.MS_NODIRATIME,
+		unix.ST_RELATIME:   unix.MS_RELATIME,
+	} {
+		if int64(st.Flags)&stFlag != 0 {
+			flags |= msFlag
+		}
+	}
+	if err := unix.Mount("", target, "", flags, ""); err != nil {
+		return fmt.Errorf("remounting %s read-only: %w", src, err)
+	}
+	return nil
+}
+
+// pivotRoot makes root the filesystem root and detaches the host's.
+func pivotRoot(root string) error {
+	if err := os.Chdir(root); err != nil {
+		return err
+	}
+	if err := unix.PivotRoot(".", "."); err != nil {
+		return fmt.Errorf("pivot_root: %w", err)
+	}
+	if err := unix.Unmount(".", unix.MNT_DETACH); err != nil {
+		return fmt.Errorf("detaching host root: %w", err)
+	}
+	if err := os.Chdir("/"); err != nil {
+		return err
+	}
+	// Mount points are in place; the root itself no longer needs writes.
+	if err := unix.Mount("", "/", "", unix.MS_REMOUNT|unix.MS_RDONLY|unix.MS_NOSUID|unix.MS_NODEV, ""); err != nil {
+		return fmt.Errorf("remounting root read-only: %w", err)
+	}
+	return nil
+}
+
+// dropPrivileges sets no_new_privs and empties the capability bounding set,
+// so that the command, although UID 0 in its namespace, runs without
+// capabilities.
+func dropPrivileges() error {
+	if err := unix.Prctl(unix.PR_SET_NO_NEW_PRIVS, 1, 0, 0, 0); err != nil {
+		return fmt.Errorf("no_new_privs: %w", err)
+	}
+	_ = unix.Prctl(unix.PR_CAP_AMBIENT, unix.PR_CAP_AMBIENT_CLEAR_ALL, 0, 0, 0)
+	for c := uintptr(0); ; c++ {
+		if err := unix.Prctl(unix.PR_CAPBSET_DROP, c, 0, 0, 0); err != nil {
+			if errors.Is(err, unix.EINVAL) {
+				return nil // past the last capability
+			}
+			return fmt.Errorf("dropping capabilities: %w", err)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+//go:build linux
+
+package security
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestMain lets the test binary act as the sandbox init when re-executed
+// by NamespaceSandbox.
+func TestMain(m *testing.M) {
+	RunSandboxInit()
+	os.Exit(m.Run())
+}
+
+// runSandboxed runs command in a namespace sandbox and returns its output.
+func runSandboxed(t *testing.T, sb *NamespaceSandbox, spec SandboxSpec) (string, error) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cmd, release, err := sb.Command(ctx, spec)
+	if err != nil {
+		t.Fatalf("Command() error: %v", err)
+	}
+	defer release()
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	err = cmd.Run()
+	return out.String(), err
+}
+
+// newTestNamespaceSandbox skips the test when the host does not allow
+// unprivileged user namespaces.
+func newTestNamespaceSandbox(t *testing.T) *NamespaceSandbox {
+	t.Helper()
+	if _, err := os.Stat("/bin/sh"); err != nil {
+		t.Skip("no /bin/sh")
+	}
+	sb, err := NewNamespaceSandbox(ResourceLimits{}, "")
+	if err != nil {
+		t.Fatalf("NewNamespaceSandbox() error: %v", err)
+	}
+	if out, err := runSandboxed(t, sb, SandboxSpec{Command: "true", Dir: t.TempDir()}); err != nil {
+		t.Skipf("namespace sandbox unavailable: %v: %s", err, out)
+	}
+	return sb
+}
+
+func TestNamespaceSandbox_Isolation(t *testing.T) {
+	t.Parallel()
+
+	sb := newTestNamespaceSandbox(t)
+	workspace := t.TempDir()
+	readOnly := t.TempDir()
+	readWrite := t.TempDir()
+	if err := os.WriteFile(filepath.Join(readOnly, "in.txt"), []byte("ro-data"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	script := strings.Join([]string{
+		"echo ws > out.txt",
+		"cat " + filepath.Join(readOnly, "in.txt"),
+		"echo; echo rw > " + filepath.Join(readWrite, "out.txt"),
+		"if echo x > " + filepath.Join(readOnly, "x.txt") + " 2>/dev/null; then echo RO-WRITABLE; fi",
+		"if touch /etc/sclaw-test 2>/dev/null; then echo ETC-WRITABLE; fi",
+		"if command -v unshare >/dev/null && unshare -U true 2>/dev/null; then echo UNSHARE-ALLOWED; fi",
+		"hostname",
+		"echo uid=$(id -u) secret=$SECRET",
+	}, "\n")
+	out, err := runSandboxed(t, sb, SandboxSpec{
+		Command: script,
+		Dir:     workspace,
+		Env:     []string{"PATH=/usr/bin:/bin"},
+		Mounts: []SandboxMount{
+			{Path: readOnly, ReadOnly: true},
+			{Path: readWrite},
+		},
+	})
+	if err != nil {
+		t.Fatalf("run error: %v: %s", err, out)
+	}
+
+	for _, want := range []string{"ro-data", "sandbox", "uid=0 secret="} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output %q missing %q", out, want)
+		}
+	}
+	for _, unwanted := range []string{"RO-WRITABLE", "ETC-WRITABLE", "UNSHARE-ALLOWED"} {
+		if strings.Contains(out, unwanted) {
+			t.Errorf("output %q: %s", out, unwanted)
+		}
+	}
+	if data, err := os.ReadFile(filepath.Join(workspace, "out.txt")); err != nil || string(data) != "ws\n" {
+		t.Errorf("workspace write = %q, %v", data, err)
+	}
+	if data, err := os.ReadFile(filepath.Join(readWrite, "out.txt")); err != nil || string(data) != "rw\n" {
+		t.Errorf("rw mount write = %q, %v", data, err)
+	}
+}
+
+func TestNamespaceSandbox_HidesHostFiles(t *testing.T) {
+	t.Parallel()
+
+	sb := newTestNamespaceSandbox(t)
+	secret := t.TempDir()
+	if err := os.WriteFile(filepath.Join(secret, "key"), []byte("s3cret"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := runSandboxed(t, sb, SandboxSpec{
+		Command: "cat " + filepath.Join(secret, "key"),
+		Dir:     t.TempDir(),
+	})
+	if err == nil || strings.Contains(out, "s3cret") {
+		t.Errorf("host file readable from the sandbox: %q, %v", out, err)
+	}
+}
+
+func TestNamespaceSandbox_RequiresAbsoluteDir(t *testing.T) {
+	t.Parallel()
+
+	sb, err := NewNamespaceSandbox(ResourceLimits{}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := sb.Command(context.Background(), SandboxSpec{Command: "true", Dir: "relative"}); err == nil {
+		t.Error("expected an error for a relative workspace")
+	}
+}
+
+func TestCPUSharesToWeight(t *testing.T) {
+	t.Parallel()
+
+	tests := map[int]int{0: 1, 2: 1, 1024: 39, 262144: 10000, 1 << 30: 10000}
+	for shares, want := range tests {
+		if got := cpuSharesToWeight(shares); got != want {
+			t.Errorf("cpuSharesToWeight(%d) = %d, want %d", shares, got, want)
+		}
+	}
+}
+
+func TestSeccompProgram_Assembles(t *testing.T) {
+	t.Parallel()
+
+	prog, err := assembleBPF(seccompProgram())
+	if err != nil {
+		t.Fatalf("assembleBPF() error: %v", err)
+	}
+	if len(prog) == 0 || len(prog) > 4096 {
+		t.Errorf("program length = %d", len(prog))
+	}
+}
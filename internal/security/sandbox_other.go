@@ -0,0 +1,25 @@
+//go:build !linux
+
+package security
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+)
+
+// NamespaceSandbox is only available on Linux.
+type NamespaceSandbox struct{}
+
+// NewNamespaceSandbox reports that namespace sandboxes need Linux.
+func NewNamespaceSandbox(ResourceLimits, string) (*NamespaceSandbox, error) {
+	return nil, fmt.Errorf("%w: the linux backend requires Linux", ErrSandboxUnavailable)
+}
+
+// Command implements Sandbox.
+func (s *NamespaceSandbox) Command(context.Context, SandboxSpec) (*exec.Cmd, func(), error) {
+	return nil, nil, fmt.Errorf("%w: the linux backend requires Linux", ErrSandboxUnavailable)
+}
+
+// RunSandboxInit does nothing outside Linux.
+func RunSandboxInit() {}
@@ -0,0 +1,134 @@
+//go:build linux
+
+package security
+
+import (
+	"fmt"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// seccompDeniedSyscalls fail with EPERM in the sandbox: they reconfigure
+// mounts, namespaces or the kernel, or inspect other processes.
+var seccompDeniedSyscalls = []uint32{
+	unix.SYS_MOUNT, unix.SYS_UMOUNT2, unix.SYS_PIVOT_ROOT, unix.SYS_CHROOT,
+	unix.SYS_FSOPEN, unix.SYS_FSCONFIG, unix.SYS_FSMOUNT, unix.SYS_FSPICK,
+	unix.SYS_MOVE_MOUNT, unix.SYS_OPEN_TREE, unix.SYS_MOUNT_SETATTR,
+	unix.SYS_UNSHARE, unix.SYS_SETNS,
+	unix.SYS_PTRACE, unix.SYS_PROCESS_VM_READV, unix.SYS_PROCESS_VM_WRITEV,
+	unix.SYS_KEXEC_LOAD, unix.SYS_REBOOT,
+	unix.SYS_INIT_MODULE, unix.SYS_FINIT_MODULE, unix.SYS_DELETE_MODULE,
+	unix.SYS_BPF, unix.SYS_PERF_EVENT_OPEN, unix.SYS_USERFAULTFD,
+	unix.SYS_KEYCTL, unix.SYS_ADD_KEY, unix.SYS_REQUEST_KEY,
+	unix.SYS_SWAPON, unix.SYS_SWAPOFF, unix.SYS_ACCT,
+	unix.SYS_OPEN_BY_HANDLE_AT, unix.SYS_NAME_TO_HANDLE_AT,
+	unix.SYS_SETTIMEOFDAY, unix.SYS_CLOCK_SETTIME, unix.SYS_CLOCK_ADJTIME,
+}
+
+// seccompNamespaceFlags are the clone flags that create namespaces.
+const seccompNamespaceFlags = unix.CLONE_NEWNS | unix.CLONE_NEWCGROUP | unix.CLONE_NEWUTS |
+	unix.CLONE_NEWIPC | unix.CLONE_NEWUSER | unix.CLONE_NEWPID | unix.CLONE_NEWNET
+
+// Offsets into struct seccomp_data.
+const (
+	seccompDataNr   = 0
+	seccompDataArch = 4
+	seccompDataArg0 = 16 // low 32 bits on little-endian architectures
+)
+
+// bpfInsn is a classic BPF instruction whose jumps name labels.
+type bpfInsn struct {
+	label  string
+	code   uint16
+	k      uint32
+	jt, jf string
+}
+
+// applySeccomp installs the syscall deny-list on the calling thread. The
+// filter needs per-architecture syscall numbers; on architectures it does
+// not know, the sandbox relies on namespaces and Landlock alone.
+func applySeccomp() error {
+	if seccompAuditArch == 0 {
+		return nil
+	}
+	prog, err := assembleBPF(seccompProgram())
+	if err != nil {
+		return err
+	}
+	fprog := unix.SockFprog{Len: uint16(len(prog)), Filter: &prog[0]}
+	return unix.Prctl(unix.PR_SET_SECCOMP, unix.SECCOMP_MODE_FILTER, uintptr(unsafe.Pointer(&fprog)), 0, 0)
+}
+
+// seccompProgram returns the filter: foreign architectures are killed,
+// namespace-creating clones and denied syscalls fail, clone3 (whose flags
+// cannot be inspected) reports ENOSYS so libc falls back to clone, and
+// everything else is allowed.
+func seccompProgram() []bpfInsn {
+	const (
+		ld   = unix.BPF_LD | unix.BPF_W | unix.BPF_ABS
+		jeq  = unix.BPF_JMP | unix.BPF_JEQ | unix.BPF_K
+		jge  = unix.BPF_JMP | unix.BPF_JGE | unix.BPF_K
+		jset = unix.BPF_JMP | unix.BPF_JSET | unix.BPF_K
+		ret  = unix.BPF_RET | unix.BPF_K
+	)
+	prog := []bpfInsn{
+		{code: ld, k: seccompDataArch},
+		{code: jeq, k: seccompAuditArch, jf: "kill"},
+		{code: ld, k: seccompDataNr},
+	}
+	if seccompSyscallLimit != 0 {
+		// Syscalls of other ABIs sharing the architecture (x32).
+		prog = append(prog, bpfInsn{code: jge, k: seccompSyscallLimit, jt: "deny"})
+	}
+	prog = append(prog,
+		bpfInsn{code: jeq, k: unix.SYS_CLONE3, jt: "enosys"},
+		bpfInsn{code: jeq, k: unix.SYS_CLONE, jt: "clone"},
+	)
+	for _, nr := range seccompDeniedSyscalls {
+		prog = append(prog, bpfInsn{code: jeq, k: nr, jt: "deny"})
+	}
+	return append(prog,
+		bpfInsn{code: ret, k: unix.SECCOMP_RET_ALLOW},
+		bpfInsn{label: "clone", code: ld, k: seccompDataArg0},
+		bpfInsn{code: jset, k: seccompNamespaceFlags, jt: "deny"},
+		bpfInsn{code: ret, k: unix.SECCOMP_RET_ALLOW},
+		bpfInsn{label: "deny", code: ret, k: unix.SECCOMP_RET_ERRNO | uint32(unix.EPERM)},
+		bpfInsn{label: "enosys", code: ret, k: unix.SECCOMP_RET_ERRNO | uint32(unix.ENOSYS)},
+		bpfInsn{label: "kill", code: ret, k: unix.SECCOMP_RET_KILL_PROCESS},
+	)
+}
+
+// assembleBPF resolves label jumps into relative offsets.
+func assembleBPF(insns []bpfInsn) ([]unix.SockFilter, error) {
+	labels := make(map[string]int)
+	for i, in := range insns {
+		if in.label != "" {
+			labels[in.label] = i
+		}
+	}
+	offset := func(from int, label string) (uint8, error) {
+		if label == "" {
+			return 0, nil
+		}
+		to, ok := labels[label]
+		if !ok || to <= from || to-from-1 > 255 {
+			return 0, fmt.Errorf("bad jump from %d to %q", from, label)
+		}
+		return uint8(to - from - 1), nil
+	}
+
+	prog := make([]unix.SockFilter, len(insns))
+	for i, in := range insns {
+		jt, err := offset(i, in.jt)
+		if err != nil {
+			return nil, err
+		}
+		jf, err := offset(i, in.jf)
+		if err != nil {
+			return nil, err
+		}
+		prog[i] = unix.SockFilter{Code: in.code, Jt: jt, Jf: jf, K: in.k}
+	}
+	return prog, nil
+}
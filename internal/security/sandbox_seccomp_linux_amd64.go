@@ -0,0 +1,12 @@
+//go:build linux && amd64
+
+package security
+
+import "golang.org/x/sys/unix"
+
+const (
+	seccompAuditArch = unix.AUDIT_ARCH_X86_64
+
+	// seccompSyscallLimit rejects x32 syscalls, numbered from 0x40000000.
+	seccompSyscallLimit = 0x40000000
+)
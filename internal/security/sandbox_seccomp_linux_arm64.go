@@ -0,0 +1,10 @@
+//go:build linux && arm64
+
+package security
+
+import "golang.org/x/sys/unix"
+
+const (
+	seccompAuditArch    = unix.AUDIT_ARCH_AARCH64
+	seccompSyscallLimit = 0
+)
@@ -0,0 +1,9 @@
+//go:build linux && !amd64 && !arm64
+
+package security
+
+// The seccomp filter is not built for this architecture.
+const (
+	seccompAuditArch    = 0
+	seccompSyscallLimit = 0
+)
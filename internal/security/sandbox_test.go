@@ -2,6 +2,10 @@ package security
 
 import (
 	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -241,3 +245,113 @@ func TestSandboxExecutor_DockerArgs_HardeningFlags(t *testing.T) {
 		}
 	}
 }
+
+func TestSandboxExecutor_CommandArgs(t *testing.T) {
+	t.Parallel()
+
+	se := NewSandboxExecutor(SandboxPolicy{Enabled: true}, ResourceLimits{}, "")
+	args, err := se.commandArgs("sclaw-sandbox-test", SandboxSpec{
+		Command: "make test",
+		Dir:     "/home/u/ws",
+		Env:     []string{"A=1"},
+		Mounts:  []SandboxMount{{Path: "/srv/docs", ReadOnly: true}, {Path: "/srv/out"}},
+	}, 1000, 1000)
+	if err != nil {
+		t.Fatalf("commandArgs() error: %v", err)
+	}
+	joined := strings.Join(args, " ")
+	for _, want := range []string{
+		"--network=none", "--cap-drop ALL", "--user 1000:1000",
+		"-v /home/u/ws:/home/u/ws:rw -w /home/u/ws",
+		"-v /srv/docs:/srv/docs:ro", "-v /srv/out:/srv/out:rw",
+		"-e A=1", "alpine:3.19 sh -c make test", "--name sclaw-sandbox-test",
+	} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("args %q missing %q", joined, want)
+		}
+	}
+
+	if args, _ := se.commandArgs("sclaw-sandbox-test", SandboxSpec{Dir: "/ws"}, 0, 0); !strings.Contains(strings.Join(args, " "), "--user 65534:65534") {
+		t.Error("root callers must not run the container as root")
+	}
+	if _, err := se.commandArgs("sclaw-sandbox-test", SandboxSpec{Dir: "/ws", Mounts: []SandboxMount{{Path: "/a:/etc"}}}, 1000, 1000); err == nil {
+		t.Error("expected an error for a mount path with a colon")
+	}
+
+	args, _ = se.commandArgs("sclaw-sandbox-test", SandboxSpec{Dir: "/ws", Interactive: true, Limits: &ResourceLimits{MemoryMB: 1024}}, 1000, 1000)
+	joined = strings.Join(args, " ")
+	for _, want := range []string{"--interactive", "--memory 1024m", "--cpu-shares 512"} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("args %q missing %q", joined, want)
+		}
+	}
+}
+
+func TestNewSandbox_Backends(t *testing.T) {
+	t.Parallel()
+
+	if sb, err := NewSandbox(SandboxOptions{}); err != nil {
+		t.Errorf("default backend error: %v", err)
+	} else if _, ok := sb.(*SandboxExecutor); !ok {
+		t.Errorf("default backend = %T, want *SandboxExecutor", sb)
+	}
+	if _, err := NewSandbox(SandboxOptions{Backend: "vm"}); err == nil {
+		t.Error("expected an error for an unknown backend")
+	}
+}
+
+func TestSandboxExecutor_CancelKillsContainer(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake docker is a shell script")
+	}
+
+	// A fake docker CLI logging its arguments; "run" blocks like an
+	// attached container.
+	dir := t.TempDir()
+	log := filepath.Join(dir, "docker.log")
+	script := "#!/bin/sh\necho \"$@\" >> " + log + "\n[ \"$1\" = run ] && exec sleep 30\nexit 0\n"
+	if err := os.WriteFile(filepath.Join(dir, "docker"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	se := NewSandboxExecutor(SandboxPolicy{Enabled: true}, ResourceLimits{}, "")
+	ctx, cancel := context.WithCancel(context.Background())
+	cmd, release, err := se.Command(ctx, SandboxSpec{Command: "sleep 60", Dir: dir})
+	if err != nil {
+		t.Fatalf("Command() error: %v", err)
+	}
+	defer release()
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	// Wait for the container to be "running" before cancelling.
+	for deadline := time.Now().Add(5 * time.Second); ; {
+		if data, _ := os.ReadFile(log); len(data) > 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("fake docker did not start")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+	_ = cmd.Wait()
+
+	data, err := os.ReadFile(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("docker calls = %q, want run then kill", lines)
+	}
+	run := strings.Fields(lines[0])
+	i := slices.Index(run, "--name")
+	if i < 0 || i+1 >= len(run) {
+		t.Fatalf("run %q has no --name", lines[0])
+	}
+	if want := "kill " + run[i+1]; lines[1] != want {
+		t.Errorf("second call = %q, want %q", lines[1], want)
+	}
+}
@@ -0,0 +1,129 @@
+package snapshot
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// maxPromptLen bounds the message excerpt stored with a turn.
+const maxPromptLen = 120
+
+// Recorder collects the changes of one turn. The turn is persisted when the
+// first change is recorded; a turn without changes leaves no trace.
+// A Recorder is safe for concurrent use by parallel tool calls.
+type Recorder struct {
+	store *Store
+
+	mu   sync.Mutex
+	turn Turn
+}
+
+// NewRecorder starts a turn for session. prompt is the message that started
+// it; an excerpt is kept to help identify the turn.
+func (s *Store) NewRecorder(session, prompt string) *Recorder {
+	return &Recorder{
+		store: s,
+		turn: Turn{
+			Session:   session,
+			Prompt:    excerpt(prompt),
+			StartedAt: s.cfg.Now().UTC(),
+		},
+	}
+}
+
+// Store returns the store the turn is recorded in.
+func (r *Recorder) Store() *Store {
+	return r.store
+}
+
+// Track runs fn, which may modify the workspace, and records the files it
+// created, modified or deleted under toolName. fn always runs: when the
+// workspace cannot be scanned, the call is simply not recorded.
+func (r *Recorder) Track(toolName string, fn func()) {
+	before, err := r.store.scan(true)
+	if err != nil {
+		r.logScanError(toolName, err)
+		fn()
+		return
+	}
+	r.store.pin(before)
+	defer r.store.unpin(before)
+
+	fn()
+
+	after, err := r.store.scan(false)
+	if err != nil {
+		r.logScanError(toolName, err)
+		return
+	}
+	if changes := diff(before, after); len(changes) > 0 {
+		r.record(toolName, changes)
+	}
+}
+
+// record merges changes into the turn and persists it. A file changed
+// twice in the same turn keeps its original content; a file created then
+// deleted within the turn is dropped.
+func (r *Recorder) record(toolName string, changes map[string]Change) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for path, c := range changes {
+		i := slices.IndexFunc(r.turn.Changes, func(e Change) bool { return e.Path == path })
+		if i < 0 {
+			c.Tool = toolName
+			r.turn.Changes = append(r.turn.Changes, c)
+			continue
+		}
+		prev := &r.turn.Changes[i]
+		prev.After = c.After
+		switch {
+		case prev.Before == "" && prev.After == "":
+			r.turn.Changes = slices.Delete(r.turn.Changes, i, i+1)
+		case prev.Before == "":
+			prev.Op = OpCreate
+		case prev.After == "":
+			prev.Op = OpDelete
+		default:
+			prev.Op = OpModify
+		}
+	}
+	slices.SortFunc(r.turn.Changes, func(a, b Change) int { return strings.Compare(a.Path, b.Path) })
+
+	if len(r.turn.Changes) == 0 && r.turn.ID == 0 {
+		return
+	}
+	if err := r.store.save(&r.turn); err != nil {
+		r.store.logger.Warn("snapshot: failed to save turn",
+			"session", r.turn.Session, "tool", toolName, "error", err)
+	}
+}
+
+func (r *Recorder) logScanError(toolName string, err error) {
+	if errors.Is(err, errTooManyFiles) {
+		r.store.logger.Warn("snapshot: workspace too large, changes not recorded",
+			"workspace", r.store.cfg.Workspace, "max_files", r.store.cfg.MaxFiles, "tool", toolName)
+		return
+	}
+	r.store.logger.Warn("snapshot: workspace scan failed, changes not recorded",
+		"workspace", r.store.cfg.Workspace, "tool", toolName, "error", err)
+}
+
+// excerpt returns the first line of s, cut to maxPromptLen bytes.
+func excerpt(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		s = s[:i]
+	}
+	if len(s) <= maxPromptLen {
+		return s
+	}
+	i := maxPromptLen
+	for i > 0 && !utf8.RuneStart(s[i]) {
+		i--
+	}
+	return s[:i] + "…"
+}
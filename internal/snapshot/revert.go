@@ -0,0 +1,137 @@
+package snapshot
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ConflictError lists the files changed since a turn, which prevent
+// reverting it. It matches ErrConflict.
+type ConflictError struct {
+	Paths []string
+}
+
+func (e *ConflictError) Error() string {
+	return ErrConflict.Error() + ": " + strings.Join(e.Paths, ", ")
+}
+
+func (e *ConflictError) Unwrap() error { return ErrConflict }
+
+// Undo reverts the most recent turn of session that has not been reverted.
+// It returns ErrNothingToUndo when there is none.
+func (s *Store) Undo(session string) (Turn, error) {
+	s.mu.Lock()
+	turns, err := s.listLocked(session)
+	s.mu.Unlock()
+	if err != nil {
+		return Turn{}, err
+	}
+	for _, t := range turns {
+		if !t.Reverted() && len(t.Changes) > 0 {
+			return s.Revert(t.ID, false)
+		}
+	}
+	return Turn{}, ErrNothingToUndo
+}
+
+// Revert restores the files changed by turn id to their content before the
+// turn. Unless force is set, it refuses with a *ConflictError when a file has
+// changed since the turn, so that later work is not silently discarded.
+func (s *Store) Revert(id int64, force bool) (Turn, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, err := s.readTurn(id)
+	if err != nil {
+		return Turn{}, err
+	}
+	if t.Reverted() {
+		return t, fmt.Errorf("%w: %d", ErrAlreadyReverted, id)
+	}
+
+	if !force {
+		var conflicts []string
+		for _, c := range t.Changes {
+			path, err := s.resolve(c.Path)
+			if err != nil {
+				return Turn{}, err
+			}
+			current, err := hashFile(path)
+			if err != nil || current != c.After {
+				conflicts = append(conflicts, c.Path)
+			}
+		}
+		if len(conflicts) > 0 {
+			return t, &ConflictError{Paths: conflicts}
+		}
+	}
+
+	var errs []error
+	for _, c := range t.Changes {
+		if err := s.restore(c); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", c.Path, err))
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return t, fmt.Errorf("snapshot: reverting turn %d: %w", id, err)
+	}
+
+	now := s.cfg.Now().UTC()
+	t.RevertedAt = &now
+	if err := s.writeTurn(t); err != nil {
+		return t, err
+	}
+	return t, nil
+}
+
+// restore puts one file back in its state before the turn.
+func (s *Store) restore(c Change) error {
+	path, err := s.resolve(c.Path)
+	if err != nil {
+		return err
+	}
+	if c.Before == "" {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+
+	data, err := s.readBlob(c.Before)
+	if err != nil {
+		return err
+	}
+	mode := c.Mode
+	if mode == 0 {
+		mode = 0o644
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp := path + ".sclaw-undo"
+	if err := os.WriteFile(tmp, data, mode); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp, mode); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// resolve maps a recorded relative path to an absolute path inside the
+// workspace.
+func (s *Store) resolve(rel string) (string, error) {
+	path := filepath.Join(s.cfg.Workspace, filepath.FromSlash(rel))
+	if !strings.HasPrefix(path, s.cfg.Workspace+string(filepath.Separator)) {
+		return "", fmt.Errorf("snapshot: path %q escapes the workspace", rel)
+	}
+	return path, nil
+}
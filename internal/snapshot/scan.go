@@ -0,0 +1,257 @@
+package snapshot
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// errTooManyFiles is returned by scan when the workspace holds more than
+// MaxFiles files.
+var errTooManyFiles = errors.New("snapshot: too many files to track")
+
+// skippedDirs are version control directories that are never tracked.
+var skippedDirs = map[string]bool{".git": true, ".hg": true, ".svn": true}
+
+// fileState is the scanned state of a regular file.
+type fileState struct {
+	size    int64
+	modTime time.Time
+	mode    os.FileMode
+	hash    string
+}
+
+// scan returns the state of every tracked file in the workspace, keyed by
+// slash-separated relative path. Files are hashed only when their size or
+// modification time changed since the previous scan. When keep is set, the
+// content of every file is stored as a blob so that it can be restored.
+func (s *Store) scan(keep bool) (map[string]fileState, error) {
+	root := s.cfg.Workspace
+	files := make(map[string]fileState)
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == root {
+				return err
+			}
+			return nil // unreadable entries are not tracked
+		}
+		if d.IsDir() {
+			if path != root && (skippedDirs[d.Name()] || path == s.cfg.Dir) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil || info.Size() > s.cfg.MaxFileSize {
+			return nil
+		}
+		if len(files) == s.cfg.MaxFiles {
+			return errTooManyFiles
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+		st, err := s.fileState(rel, path, info, keep)
+		if err != nil {
+			s.logger.Warn("snapshot: cannot read file", "path", path, "error", err)
+			return nil
+		}
+		files[rel] = st
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// fileState returns the state of one file, reusing the cached hash when the
+// file looks unchanged.
+func (s *Store) fileState(rel, path string, info fs.FileInfo, keep bool) (fileState, error) {
+	s.mu.Lock()
+	cached, ok := s.files[rel]
+	s.mu.Unlock()
+	if ok && cached.size == info.Size() && cached.modTime.Equal(info.ModTime()) && cached.mode == info.Mode().Perm() {
+		if !keep || s.hasBlob(cached.hash) {
+			return cached, nil
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fileState{}, err
+	}
+	sum := sha256.Sum256(data)
+	st := fileState{
+		size:    info.Size(),
+		modTime: info.ModTime(),
+		mode:    info.Mode().Perm(),
+		hash:    hex.EncodeToString(sum[:]),
+	}
+	if keep {
+		if err := s.putBlob(st.hash, data); err != nil {
+			return fileState{}, err
+		}
+	}
+
+	s.mu.Lock()
+	s.files[rel] = st
+	s.mu.Unlock()
+	return st, nil
+}
+
+// diff returns the changes between two scans, keyed by path.
+func diff(before, after map[string]fileState) map[string]Change {
+	changes := make(map[string]Change)
+	for path, b := range before {
+		a, ok := after[path]
+		switch {
+		case !ok:
+			changes[path] = Change{Path: path, Op: OpDelete, Before: b.hash, Mode: b.mode}
+		case a.hash != b.hash:
+			changes[path] = Change{Path: path, Op: OpModify, Before: b.hash, After: a.hash, Mode: b.mode}
+		}
+	}
+	for path, a := range after {
+		if _, ok := before[path]; !ok {
+			changes[path] = Change{Path: path, Op: OpCreate, After: a.hash}
+		}
+	}
+	return changes
+}
+
+// hashFile returns the content hash of the file at path, or "" if it does
+// not exist.
+func hashFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func (s *Store) blobPath(hash string) string {
+	return filepath.Join(blobsDir(s.cfg.Dir), hash[:2], hash)
+}
+
+func (s *Store) hasBlob(hash string) bool {
+	_, err := os.Stat(s.blobPath(hash))
+	return err == nil
+}
+
+// putBlob stores data under its hash unless it is already present.
+func (s *Store) putBlob(hash string, data []byte) error {
+	path := s.blobPath(hash)
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("snapshot: creating blob directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), hash+".tmp*")
+	if err != nil {
+		return fmt.Errorf("snapshot: writing blob: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("snapshot: writing blob: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("snapshot: writing blob: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("snapshot: writing blob: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) readBlob(hash string) ([]byte, error) {
+	data, err := os.ReadFile(s.blobPath(hash))
+	if err != nil {
+		return nil, fmt.Errorf("snapshot: reading blob %s: %w", hash, err)
+	}
+	return data, nil
+}
+
+func (s *Store) collectGarbage() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.collectGarbageLocked()
+}
+
+// collectGarbageLocked removes blobs referenced neither by a turn nor by
+// the scan cache. Callers hold s.mu.
+func (s *Store) collectGarbageLocked() error {
+	live := make(map[string]bool)
+	for _, st := range s.files {
+		live[st.hash] = true
+	}
+	for hash := range s.pinned {
+		live[hash] = true
+	}
+	ids, err := s.turnIDs()
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		t, err := s.readTurn(id)
+		if err != nil {
+			// Keep every blob rather than lose content an unreadable turn
+			// may still reference.
+			return err
+		}
+		for _, c := range t.Changes {
+			live[c.Before] = true
+			live[c.After] = true
+		}
+	}
+
+	return filepath.WalkDir(blobsDir(s.cfg.Dir), func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || strings.Contains(d.Name(), ".tmp") {
+			return nil
+		}
+		if !live[d.Name()] {
+			_ = os.Remove(path)
+		}
+		return nil
+	})
+}
+
+// pin keeps the blobs of a scan alive until unpin is called, so that
+// garbage collection cannot remove content a running tool call may still
+// record.
+func (s *Store) pin(files map[string]fileState) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, st := range files {
+		s.pinned[st.hash]++
+	}
+}
+
+func (s *Store) unpin(files map[string]fileState) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, st := range files {
+		if s.pinned[st.hash]--; s.pinned[st.hash] <= 0 {
+			delete(s.pinned, st.hash)
+		}
+	}
+}
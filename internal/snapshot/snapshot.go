@@ -0,0 +1,329 @@
+// Package snapshot records the workspace files changed by agent tool calls
+// so that they can be listed and reverted turn by turn.
+//
+// A turn groups the changes made while the agent answers one inbound
+// message. Before a mutating tool runs, the workspace is scanned and the
+// content of every file is stored once in a content-addressed blob store;
+// after it runs, the workspace is scanned again and each created, modified
+// or deleted file is recorded in the turn with its previous content. Turns
+// are persisted as JSON files next to the blobs, and the oldest are pruned.
+package snapshot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Defaults applied by New.
+const (
+	DefaultMaxTurns    = 100
+	DefaultMaxFileSize = 1 << 20 // 1 MiB
+	DefaultMaxFiles    = 10000
+)
+
+// Errors returned by Store.
+var (
+	ErrTurnNotFound    = errors.New("snapshot: turn not found")
+	ErrAlreadyReverted = errors.New("snapshot: turn already reverted")
+	ErrNothingToUndo   = errors.New("snapshot: nothing to undo")
+	ErrConflict        = errors.New("snapshot: files changed since the turn")
+)
+
+// Operations recorded for a changed file.
+const (
+	OpCreate = "create"
+	OpModify = "modify"
+	OpDelete = "delete"
+)
+
+// Config configures a Store.
+type Config struct {
+	// Workspace is the directory whose files are tracked.
+	Workspace string
+
+	// Dir is where turns and blobs are stored. It must not be inside
+	// Workspace.
+	Dir string
+
+	// MaxTurns is how many turns are kept. Defaults to 100.
+	MaxTurns int
+
+	// MaxFileSize is the size above which files are not tracked.
+	// Defaults to 1 MiB.
+	MaxFileSize int64
+
+	// MaxFiles is the number of files above which the workspace is not
+	// tracked at all. Defaults to 10000.
+	MaxFiles int
+
+	// Logger receives warnings about failed scans. Defaults to slog.Default.
+	Logger *slog.Logger
+
+	// Now overrides time.Now for testing.
+	Now func() time.Time
+}
+
+// Change is a file created, modified or deleted during a turn.
+type Change struct {
+	// Path is the file path relative to the workspace, with forward slashes.
+	Path string `json:"path"`
+
+	// Op is "create", "modify" or "delete".
+	Op string `json:"op"`
+
+	// Tool is the tool that first changed the file in the turn.
+	Tool string `json:"tool"`
+
+	// Before and After are the content hashes of the file before and after
+	// the turn. An empty hash means the file did not exist.
+	Before string `json:"before,omitempty"`
+	After  string `json:"after,omitempty"`
+
+	// Mode is the file permission before the turn.
+	Mode os.FileMode `json:"mode,omitempty"`
+}
+
+// Turn is the set of file changes made while answering one message.
+type Turn struct {
+	ID         int64      `json:"id"`
+	Session    string     `json:"session"`
+	Prompt     string     `json:"prompt,omitempty"`
+	StartedAt  time.Time  `json:"started_at"`
+	RevertedAt *time.Time `json:"reverted_at,omitempty"`
+	Changes    []Change   `json:"changes"`
+}
+
+// Reverted reports whether the turn has been reverted.
+func (t Turn) Reverted() bool {
+	return t.RevertedAt != nil
+}
+
+// Store persists turns and file contents for one workspace. It is safe for
+// concurrent use.
+type Store struct {
+	cfg    Config
+	logger *slog.Logger
+
+	mu     sync.Mutex
+	nextID int64
+	files  map[string]fileState // scan cache, keyed by relative path
+	pinned map[string]int       // blob hash → running calls needing it
+}
+
+// New opens the store in cfg.Dir, creating it if needed, and removes blobs
+// no longer referenced by any turn.
+func New(cfg Config) (*Store, error) {
+	if cfg.Workspace == "" || cfg.Dir == "" {
+		return nil, errors.New("snapshot: workspace and dir are required")
+	}
+	if cfg.MaxTurns <= 0 {
+		cfg.MaxTurns = DefaultMaxTurns
+	}
+	if cfg.MaxFileSize <= 0 {
+		cfg.MaxFileSize = DefaultMaxFileSize
+	}
+	if cfg.MaxFiles <= 0 {
+		cfg.MaxFiles = DefaultMaxFiles
+	}
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+	logger := cfg.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if ws, err := filepath.EvalSymlinks(cfg.Workspace); err == nil {
+		cfg.Workspace = ws
+	}
+
+	for _, dir := range []string{turnsDir(cfg.Dir), blobsDir(cfg.Dir)} {
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			return nil, fmt.Errorf("snapshot: creating %s: %w", dir, err)
+		}
+	}
+
+	s := &Store{
+		cfg:    cfg,
+		logger: logger,
+		files:  make(map[string]fileState),
+		pinned: make(map[string]int),
+	}
+	ids, err := s.turnIDs()
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) > 0 {
+		s.nextID = ids[len(ids)-1]
+	}
+	if err := s.collectGarbage(); err != nil {
+		logger.Warn("snapshot: garbage collection failed", "dir", cfg.Dir, "error", err)
+	}
+	return s, nil
+}
+
+// Workspace returns the tracked directory.
+func (s *Store) Workspace() string {
+	return s.cfg.Workspace
+}
+
+// List returns the turns recorded for session, newest first. An empty
+// session lists every turn.
+func (s *Store) List(session string) ([]Turn, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.listLocked(session)
+}
+
+func (s *Store) listLocked(session string) ([]Turn, error) {
+	ids, err := s.turnIDs()
+	if err != nil {
+		return nil, err
+	}
+	turns := make([]Turn, 0, len(ids))
+	for _, id := range slices.Backward(ids) {
+		t, err := s.readTurn(id)
+		if err != nil {
+			s.logger.Warn("snapshot: skipping unreadable turn", "id", id, "error", err)
+			continue
+		}
+		if session == "" || t.Session == session {
+			turns = append(turns, t)
+		}
+	}
+	return turns, nil
+}
+
+// Get returns the turn with the given ID.
+func (s *Store) Get(id int64) (Turn, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.readTurn(id)
+}
+
+// save assigns an ID to t if it has none, persists it and prunes the
+// oldest turns.
+func (s *Store) save(t *Turn) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if t.ID == 0 {
+		s.nextID++
+		t.ID = s.nextID
+	}
+	if err := s.writeTurn(*t); err != nil {
+		return err
+	}
+	return s.pruneLocked()
+}
+
+// pruneLocked removes the oldest turns beyond MaxTurns and the blobs only
+// they referenced. Callers hold s.mu.
+func (s *Store) pruneLocked() error {
+	ids, err := s.turnIDs()
+	if err != nil {
+		return err
+	}
+	if len(ids) <= s.cfg.MaxTurns {
+		return nil
+	}
+	for _, id := range ids[:len(ids)-s.cfg.MaxTurns] {
+		if err := os.Remove(turnPath(s.cfg.Dir, id)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("snapshot: pruning turn %d: %w", id, err)
+		}
+	}
+	return s.collectGarbageLocked()
+}
+
+// turnIDs returns the persisted turn IDs in ascending order.
+func (s *Store) turnIDs() ([]int64, error) {
+	entries, err := os.ReadDir(turnsDir(s.cfg.Dir))
+	if err != nil {
+		return nil, fmt.Errorf("snapshot: listing turns: %w", err)
+	}
+	ids := make([]int64, 0, len(entries))
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), ".json")
+		if !ok {
+			continue
+		}
+		if id, err := strconv.ParseInt(name, 10, 64); err == nil && id > 0 {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+	return ids, nil
+}
+
+func (s *Store) readTurn(id int64) (Turn, error) {
+	data, err := os.ReadFile(turnPath(s.cfg.Dir, id))
+	if errors.Is(err, os.ErrNotExist) {
+		return Turn{}, fmt.Errorf("%w: %d", ErrTurnNotFound, id)
+	}
+	if err != nil {
+		return Turn{}, fmt.Errorf("snapshot: reading turn %d: %w", id, err)
+	}
+	var t Turn
+	if err := json.Unmarshal(data, &t); err != nil {
+		return Turn{}, fmt.Errorf("snapshot: parsing turn %d: %w", id, err)
+	}
+	return t, nil
+}
+
+// writeTurn persists t atomically.
+func (s *Store) writeTurn(t Turn) error {
+	data, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := turnPath(s.cfg.Dir, t.ID)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("snapshot: writing turn %d: %w", t.ID, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("snapshot: writing turn %d: %w", t.ID, err)
+	}
+	return nil
+}
+
+func turnsDir(dir string) string { return filepath.Join(dir, "turns") }
+
+func blobsDir(dir string) string { return filepath.Join(dir, "blobs") }
+
+func turnPath(dir string, id int64) string {
+	return filepath.Join(turnsDir(dir), fmt.Sprintf("%08d.json", id))
+}
+
+// Summary describes the turn in one line, e.g.
+// `turn #3 "fix the build": modified main.go, created util.go`.
+func (t Turn) Summary() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "turn #%d", t.ID)
+	if t.Prompt != "" {
+		fmt.Fprintf(&sb, " %q", t.Prompt)
+	}
+	if len(t.Changes) == 0 {
+		sb.WriteString(": no file changes")
+		return sb.String()
+	}
+	sb.WriteString(":")
+	for i, c := range t.Changes {
+		if i > 0 {
+			sb.WriteString(",")
+		}
+		fmt.Fprintf(&sb, " %s %s", opVerb[c.Op], c.Path)
+	}
+	return sb.String()
+}
+
+var opVerb = map[string]string{OpCreate: "created", OpModify: "modified", OpDelete: "deleted"}
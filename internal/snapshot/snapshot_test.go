@@ -0,0 +1,264 @@
+package snapshot
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func newTestStore(t *testing.T, cfg Config) (*Store, string) {
+	t.Helper()
+	ws := t.TempDir()
+	cfg.Workspace = ws
+	cfg.Dir = t.TempDir()
+	s, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return s, s.Workspace()
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestRecorder_TrackAndUndo(t *testing.T) {
+	t.Parallel()
+
+	s, ws := newTestStore(t, Config{})
+	writeFile(t, filepath.Join(ws, "keep.txt"), "unchanged")
+	writeFile(t, filepath.Join(ws, "edit.txt"), "v1")
+	writeFile(t, filepath.Join(ws, "gone.txt"), "bye")
+
+	rec := s.NewRecorder("chat-1", "please refactor\nthe whole thing")
+	rec.Track("exec", func() {
+		writeFile(t, filepath.Join(ws, "edit.txt"), "v2")
+		writeFile(t, filepath.Join(ws, "sub", "new.txt"), "hello")
+		if err := os.Remove(filepath.Join(ws, "gone.txt")); err != nil {
+			t.Fatal(err)
+		}
+	})
+	rec.Track("write_file", func() {
+		writeFile(t, filepath.Join(ws, "edit.txt"), "v3")
+	})
+
+	turns, err := s.List("chat-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(turns) != 1 {
+		t.Fatalf("got %d turns, want 1", len(turns))
+	}
+	turn := turns[0]
+	if turn.Prompt != "please refactor" {
+		t.Errorf("Prompt = %q", turn.Prompt)
+	}
+	want := map[string]string{"edit.txt": OpModify, "gone.txt": OpDelete, "sub/new.txt": OpCreate}
+	if len(turn.Changes) != len(want) {
+		t.Fatalf("changes = %+v", turn.Changes)
+	}
+	for _, c := range turn.Changes {
+		if want[c.Path] != c.Op {
+			t.Errorf("%s: op = %q, want %q", c.Path, c.Op, want[c.Path])
+		}
+		if c.Path == "edit.txt" && c.Tool != "exec" {
+			t.Errorf("edit.txt tool = %q, want first tool exec", c.Tool)
+		}
+	}
+
+	reverted, err := s.Undo("chat-1")
+	if err != nil {
+		t.Fatalf("Undo: %v", err)
+	}
+	if !reverted.Reverted() {
+		t.Error("turn not marked reverted")
+	}
+	if got := readFile(t, filepath.Join(ws, "edit.txt")); got != "v1" {
+		t.Errorf("edit.txt = %q, want v1", got)
+	}
+	if got := readFile(t, filepath.Join(ws, "gone.txt")); got != "bye" {
+		t.Errorf("gone.txt = %q, want bye", got)
+	}
+	if _, err := os.Stat(filepath.Join(ws, "sub", "new.txt")); !os.IsNotExist(err) {
+		t.Errorf("sub/new.txt still exists: %v", err)
+	}
+	if got := readFile(t, filepath.Join(ws, "keep.txt")); got != "unchanged" {
+		t.Errorf("keep.txt = %q", got)
+	}
+
+	if _, err := s.Undo("chat-1"); !errors.Is(err, ErrNothingToUndo) {
+		t.Errorf("second Undo error = %v, want ErrNothingToUndo", err)
+	}
+}
+
+func TestRecorder_NoChangesLeavesNoTurn(t *testing.T) {
+	t.Parallel()
+
+	s, ws := newTestStore(t, Config{})
+	writeFile(t, filepath.Join(ws, "a.txt"), "a")
+
+	s.NewRecorder("chat", "look around").Track("exec", func() {})
+
+	turns, err := s.List("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(turns) != 0 {
+		t.Errorf("got %d turns, want 0", len(turns))
+	}
+}
+
+func TestRecorder_CreatedThenDeleted(t *testing.T) {
+	t.Parallel()
+
+	s, ws := newTestStore(t, Config{})
+	rec := s.NewRecorder("chat", "")
+	rec.Track("exec", func() { writeFile(t, filepath.Join(ws, "tmp.txt"), "x") })
+	rec.Track("exec", func() { _ = os.Remove(filepath.Join(ws, "tmp.txt")) })
+
+	turns, _ := s.List("chat")
+	if len(turns) != 1 || len(turns[0].Changes) != 0 {
+		t.Errorf("turns = %+v, want one turn without changes", turns)
+	}
+	if _, err := s.Undo("chat"); !errors.Is(err, ErrNothingToUndo) {
+		t.Errorf("Undo error = %v, want ErrNothingToUndo", err)
+	}
+}
+
+func TestStore_RevertConflict(t *testing.T) {
+	t.Parallel()
+
+	s, ws := newTestStore(t, Config{})
+	path := filepath.Join(ws, "f.txt")
+	writeFile(t, path, "v1")
+
+	s.NewRecorder("a", "").Track("exec", func() { writeFile(t, path, "v2") })
+	turns, _ := s.List("a")
+	id := turns[0].ID
+
+	// A later change outside the turn.
+	writeFile(t, path, "v3")
+
+	if _, err := s.Revert(id, false); !errors.Is(err, ErrConflict) {
+		t.Fatalf("Revert error = %v, want ErrConflict", err)
+	}
+	if got := readFile(t, path); got != "v3" {
+		t.Errorf("file changed despite conflict: %q", got)
+	}
+
+	if _, err := s.Revert(id, true); err != nil {
+		t.Fatalf("forced Revert: %v", err)
+	}
+	if got := readFile(t, path); got != "v1" {
+		t.Errorf("file = %q, want v1", got)
+	}
+	if _, err := s.Revert(id, true); !errors.Is(err, ErrAlreadyReverted) {
+		t.Errorf("Revert error = %v, want ErrAlreadyReverted", err)
+	}
+}
+
+func TestStore_SessionsAreSeparate(t *testing.T) {
+	t.Parallel()
+
+	s, ws := newTestStore(t, Config{})
+	s.NewRecorder("a", "").Track("exec", func() { writeFile(t, filepath.Join(ws, "a.txt"), "a") })
+	s.NewRecorder("b", "").Track("exec", func() { writeFile(t, filepath.Join(ws, "b.txt"), "b") })
+
+	if _, err := s.Undo("a"); err != nil {
+		t.Fatalf("Undo: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(ws, "a.txt")); !os.IsNotExist(err) {
+		t.Error("a.txt not removed")
+	}
+	if _, err := os.Stat(filepath.Join(ws, "b.txt")); err != nil {
+		t.Error("b.txt removed by another session's undo")
+	}
+}
+
+func TestStore_PruneAndReopen(t *testing.T) {
+	t.Parallel()
+
+	ws := t.TempDir()
+	dir := t.TempDir()
+	s, err := New(Config{Workspace: ws, Dir: dir, MaxTurns: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, content := range []string{"1", "2", "3"} {
+		s.NewRecorder("chat", "").Track("exec", func() {
+			writeFile(t, filepath.Join(ws, "f.txt"), content)
+		})
+	}
+
+	turns, _ := s.List("")
+	if len(turns) != 2 || turns[0].ID != 3 || turns[1].ID != 2 {
+		t.Fatalf("turns = %+v, want IDs 3 and 2", turns)
+	}
+
+	// Reopening continues the ID sequence and keeps the blobs turns need.
+	s2, err := New(Config{Workspace: ws, Dir: dir, MaxTurns: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s2.NewRecorder("chat", "").Track("exec", func() {
+		writeFile(t, filepath.Join(ws, "f.txt"), "4")
+	})
+	turns, _ = s2.List("")
+	if turns[0].ID != 4 {
+		t.Errorf("new turn ID = %d, want 4", turns[0].ID)
+	}
+	if _, err := s2.Undo("chat"); err != nil {
+		t.Fatalf("Undo: %v", err)
+	}
+	if _, err := s2.Undo("chat"); err != nil {
+		t.Fatalf("second Undo: %v", err)
+	}
+	if got := readFile(t, filepath.Join(ws, "f.txt")); got != "2" {
+		t.Errorf("f.txt = %q, want 2", got)
+	}
+}
+
+func TestScan_SkipsLargeFilesAndVCS(t *testing.T) {
+	t.Parallel()
+
+	s, ws := newTestStore(t, Config{MaxFileSize: 4})
+	s.NewRecorder("chat", "").Track("exec", func() {
+		writeFile(t, filepath.Join(ws, "big.bin"), "too large")
+		writeFile(t, filepath.Join(ws, ".git", "HEAD"), "ref")
+		writeFile(t, filepath.Join(ws, "ok"), "ok")
+	})
+	turns, _ := s.List("")
+	if len(turns) != 1 || len(turns[0].Changes) != 1 || turns[0].Changes[0].Path != "ok" {
+		t.Errorf("turns = %+v, want only ok", turns)
+	}
+}
+
+func TestScan_TooManyFiles(t *testing.T) {
+	t.Parallel()
+
+	s, ws := newTestStore(t, Config{MaxFiles: 1})
+	writeFile(t, filepath.Join(ws, "a"), "a")
+	writeFile(t, filepath.Join(ws, "b"), "b")
+
+	ran := false
+	s.NewRecorder("chat", "").Track("exec", func() { ran = true })
+	if !ran {
+		t.Error("tool did not run when the workspace is too large")
+	}
+}
@@ -3,6 +3,7 @@ package tool
 import (
 	"context"
 	"encoding/json"
+	"time"
 )
 
 // ApprovalRequest is sent to an ApprovalRequester when a tool needs user confirmation.
@@ -21,8 +22,41 @@ type ApprovalRequest struct {
 
 	// Context is the policy context (dm or group) where the request originates.
 	Context PolicyContext
+
+	// Timeout is how long the user has to answer before the request is
+	// denied by default. Zero when the caller sets no deadline.
+	Timeout time.Duration
+
+	// Scopes lists the scopes the user may choose beyond ApprovalScopeOnce.
+	// Empty means only one-off approvals are offered.
+	Scopes []ApprovalScope
+
+	// Pattern is the argument pattern an ApprovalScopeAlways answer will
+	// remember (see SuggestApprovalPattern).
+	Pattern string
+
+	// Approvers, when non-empty, lists the only user IDs allowed to answer
+	// (set by policy rules). Such requests are never answered from memory.
+	Approvers []string
 }
 
+// ApprovalScope is how long an approval remains valid.
+type ApprovalScope string
+
+// ApprovalScope values offered to users when approving a tool call.
+const (
+	// ApprovalScopeOnce approves this call only. It is the zero value.
+	ApprovalScopeOnce ApprovalScope = ""
+
+	// ApprovalScopeSession approves every call to the tool for the rest of
+	// the session.
+	ApprovalScopeSession ApprovalScope = "session"
+
+	// ApprovalScopeAlways approves, for this agent and across restarts,
+	// every call to the tool whose arguments match ApprovalRequest.Pattern.
+	ApprovalScopeAlways ApprovalScope = "always"
+)
+
 // ApprovalResponse is the result of an approval request.
 type ApprovalResponse struct {
 	// Approved indicates whether the user approved the tool execution.
@@ -30,6 +64,10 @@ type ApprovalResponse struct {
 
 	// Reason is an optional explanation for the decision.
 	Reason string
+
+	// Scope extends an approval beyond this call. Ignored when Approved is
+	// false.
+	Scope ApprovalScope
 }
 
 // ApprovalRequester handles requesting approval from a user.
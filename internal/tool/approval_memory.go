@@ -0,0 +1,320 @@
+package tool
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ApprovalGrant is a persisted approval for calls to Tool whose arguments
+// match Pattern.
+type ApprovalGrant struct {
+	Tool      string    `json:"tool"`
+	Pattern   string    `json:"pattern"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// approvalFile is the on-disk format of an ApprovalMemory.
+type approvalFile struct {
+	Grants []ApprovalGrant `json:"grants"`
+}
+
+// ApprovalMemory remembers approvals granted with ApprovalScopeSession
+// (in memory, per session) and ApprovalScopeAlways (persisted to a JSON
+// file). One memory is kept per agent. It is safe for concurrent use.
+type ApprovalMemory struct {
+	path string
+
+	mu       sync.Mutex
+	grants   []ApprovalGrant
+	sessions map[string]map[string]bool // session ID → tool names
+}
+
+// NewApprovalMemory loads the grants persisted at path. A missing file is
+// not an error. An empty path keeps "always" grants in memory only.
+func NewApprovalMemory(path string) (*ApprovalMemory, error) {
+	m := &ApprovalMemory{
+		path:     path,
+		sessions: make(map[string]map[string]bool),
+	}
+	if path == "" {
+		return m, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading approvals: %w", err)
+	}
+	var f approvalFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("parsing approvals %s: %w", path, err)
+	}
+	m.grants = f.Grants
+	return m, nil
+}
+
+// Allowed reports whether a call is covered by a remembered approval, and
+// by which scope.
+func (m *ApprovalMemory) Allowed(sessionID, toolName string, args json.RawMessage) (ApprovalScope, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.sessions[sessionID][toolName] {
+		return ApprovalScopeSession, true
+	}
+
+	field, subject := approvalSubject(args)
+	for _, g := range m.grants {
+		if g.Tool == toolName && matchApprovalPattern(g.Pattern, field, subject) {
+			return ApprovalScopeAlways, true
+		}
+	}
+	return ApprovalScopeOnce, false
+}
+
+// Remember records an approval with the given scope. ApprovalScopeOnce is a
+// no-op; ApprovalScopeAlways requires req.Pattern and persists the grant.
+func (m *ApprovalMemory) Remember(sessionID string, req ApprovalRequest, scope ApprovalScope) error {
+	switch scope {
+	case ApprovalScopeOnce:
+		return nil
+
+	case ApprovalScopeSession:
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		if m.sessions[sessionID] == nil {
+			m.sessions[sessionID] = make(map[string]bool)
+		}
+		m.sessions[sessionID][req.ToolName] = true
+		return nil
+
+	case ApprovalScopeAlways:
+		if req.Pattern == "" {
+			return fmt.Errorf("tool %s: arguments cannot be remembered as a pattern", req.ToolName)
+		}
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		for _, g := range m.grants {
+			if g.Tool == req.ToolName && g.Pattern == req.Pattern {
+				return nil
+			}
+		}
+		m.grants = append(m.grants, ApprovalGrant{
+			Tool:      req.ToolName,
+			Pattern:   req.Pattern,
+			CreatedAt: time.Now().UTC(),
+		})
+		return m.saveLocked()
+
+	default:
+		return fmt.Errorf("unknown approval scope %q", scope)
+	}
+}
+
+// ForgetSession drops the approvals granted for the rest of a session. It
+// is called when the session ends.
+func (m *ApprovalMemory) ForgetSession(sessionID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.sessions, sessionID)
+}
+
+// Grants returns a copy of the persisted grants.
+func (m *ApprovalMemory) Grants() []ApprovalGrant {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make([]ApprovalGrant, len(m.grants))
+	copy(out, m.grants)
+	return out
+}
+
+// saveLocked writes the grants to disk atomically. Callers hold m.mu.
+func (m *ApprovalMemory) saveLocked() error {
+	if m.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(approvalFile{Grants: m.grants}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(m.path), 0o700); err != nil {
+		return fmt.Errorf("creating approvals directory: %w", err)
+	}
+	tmp := m.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("writing approvals: %w", err)
+	}
+	if err := os.Rename(tmp, m.path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("writing approvals: %w", err)
+	}
+	return nil
+}
+
+// RememberingRequester wraps an ApprovalRequester with an ApprovalMemory:
+// calls covered by a remembered approval are approved without prompting,
+// other requests offer the session and (when the arguments generalize
+// safely) always scopes, and approvals with a scope are recorded.
+type RememberingRequester struct {
+	Next      ApprovalRequester
+	Memory    *ApprovalMemory
+	SessionID string
+
+	// ReadOnlyCommands are the commands "always allow" may generalize to
+	// any arguments. Nil means DefaultReadOnlyCommands.
+	ReadOnlyCommands []string
+}
+
+// RequestApproval implements ApprovalRequester.
+func (r *RememberingRequester) RequestApproval(ctx context.Context, req ApprovalRequest) (ApprovalResponse, error) {
+	// Only the designated approvers may answer: a grant given by anyone
+	// else must not stand in for them.
+	if len(req.Approvers) > 0 {
+		return r.Next.RequestApproval(ctx, req)
+	}
+	if scope, ok := r.Memory.Allowed(r.SessionID, req.ToolName, req.Arguments); ok {
+		return ApprovalResponse{Approved: true, Reason: "remembered (" + string(scope) + ")", Scope: scope}, nil
+	}
+
+	req.Scopes = []ApprovalScope{ApprovalScopeSession}
+	if req.Pattern = SuggestApprovalPattern(req.Arguments, r.ReadOnlyCommands); req.Pattern != "" {
+		req.Scopes = append(req.Scopes, ApprovalScopeAlways)
+	}
+	resp, err := r.Next.RequestApproval(ctx, req)
+	if err != nil || !resp.Approved {
+		return resp, err
+	}
+	if !slices.Contains(req.Scopes, resp.Scope) {
+		// Never extend an approval further than what was offered.
+		resp.Scope = ApprovalScopeOnce
+	}
+	if err := r.Memory.Remember(r.SessionID, req, resp.Scope); err != nil {
+		// The call itself was approved; only the memory failed.
+		resp.Reason = strings.TrimSpace(resp.Reason + " (not remembered: " + err.Error() + ")")
+	}
+	return resp, nil
+}
+
+// subjectFields are the argument fields, in order of preference, that
+// identify what a tool call acts on.
+var subjectFields = []string{"command", "cmd", "path", "url"}
+
+// approvalSubject returns the argument field and value that approval
+// patterns are matched against. Arguments without a known field are
+// matched on their compact JSON form.
+func approvalSubject(args json.RawMessage) (field, value string) {
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(args, &obj); err == nil {
+		for _, f := range subjectFields {
+			var s string
+			if raw, ok := obj[f]; ok && json.Unmarshal(raw, &s) == nil {
+				return f, strings.TrimSpace(s)
+			}
+		}
+	}
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, args); err != nil {
+		return "", string(args)
+	}
+	return "", buf.String()
+}
+
+// DefaultReadOnlyCommands are the commands whose "always allow" pattern
+// covers any arguments, used when no list is configured.
+var DefaultReadOnlyCommands = []string{
+	"ls", "cat", "pwd", "head", "tail", "wc", "grep",
+	"git status", "git log", "git diff", "git show",
+}
+
+// SuggestApprovalPattern returns the pattern an "always allow" answer
+// should remember for args. Commands starting with one of readOnly (nil
+// means DefaultReadOnlyCommands) are generalized to that command followed
+// by " *" (e.g. "ls *"); any other value is remembered exactly. It returns
+// "" for commands containing shell control characters, which would let a
+// pattern match chained commands, and for exact values containing '*',
+// which a pattern would read as a wildcard.
+func SuggestApprovalPattern(args json.RawMessage, readOnly []string) string {
+	field, subject := approvalSubject(args)
+	if subject == "" {
+		return ""
+	}
+	if isCommandField(field) {
+		if hasShellControl(subject) {
+			return ""
+		}
+		if readOnly == nil {
+			readOnly = DefaultReadOnlyCommands
+		}
+		for _, cmd := range readOnly {
+			if subject == cmd || strings.HasPrefix(subject, cmd+" ") {
+				return cmd + " *"
+			}
+		}
+	}
+	if strings.Contains(subject, "*") {
+		return ""
+	}
+	return subject
+}
+
+// matchApprovalPattern matches subject against a pattern where '*' matches
+// any sequence of characters. A trailing " *" also matches the bare prefix
+// ("ls *" matches "ls"). Commands with shell control characters never match.
+func matchApprovalPattern(pattern, field, subject string) bool {
+	if isCommandField(field) && hasShellControl(subject) {
+		return false
+	}
+	if prefix, ok := strings.CutSuffix(pattern, " *"); ok && subject == prefix {
+		return true
+	}
+	return wildcardMatch(pattern, subject)
+}
+
+// wildcardMatch reports whether s matches p, where '*' in p matches any
+// (possibly empty) sequence of characters.
+func wildcardMatch(p, s string) bool {
+	star, match := -1, 0
+	i, j := 0, 0
+	for j < len(s) {
+		switch {
+		case i < len(p) && p[i] == '*':
+			star, match = i, j
+			i++
+		case i < len(p) && p[i] == s[j]:
+			i++
+			j++
+		case star >= 0:
+			i = star + 1
+			match++
+			j = match
+		default:
+			return false
+		}
+	}
+	for i < len(p) && p[i] == '*' {
+		i++
+	}
+	return i == len(p)
+}
+
+// isCommandField reports whether an argument field holds a shell command.
+func isCommandField(field string) bool {
+	return field == "command" || field == "cmd"
+}
+
+// hasShellControl reports whether s contains characters that chain,
+// redirect or substitute shell commands.
+func hasShellControl(s string) bool {
+	return strings.ContainsAny(s, ";&|`$<>(){}\n\r\\")
+}
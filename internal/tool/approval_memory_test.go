@@ -0,0 +1,194 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+)
+
+func TestSuggestApprovalPattern(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		args     string
+		readOnly []string
+		want     string
+	}{
+		{`{"command":"ls -la src"}`, nil, "ls *"},
+		{`{"command":"pwd"}`, nil, "pwd *"},
+		{`{"command":"git status -s"}`, nil, "git status *"},
+		{`{"command":"git push origin main"}`, nil, "git push origin main"},
+		{`{"command":"rm -rf build"}`, nil, "rm -rf build"},
+		{`{"command":"rm *.tmp"}`, nil, ""},
+		{`{"command":"lsof -i"}`, nil, "lsof -i"},
+		{`{"command":"make test"}`, []string{"make"}, "make *"},
+		{`{"command":"ls -la"}`, []string{"make"}, "ls -la"},
+		{`{"command":"ls; rm -rf /"}`, nil, ""},
+		{`{"command":"cat $(whoami)"}`, nil, ""},
+		{`{"path":"notes/todo.md"}`, nil, "notes/todo.md"},
+		{`{"url":"https://example.com"}`, nil, "https://example.com"},
+		{`{"b": 1, "a": 2}`, nil, `{"b":1,"a":2}`},
+	}
+	for _, tt := range tests {
+		if got := SuggestApprovalPattern(json.RawMessage(tt.args), tt.readOnly); got != tt.want {
+			t.Errorf("SuggestApprovalPattern(%s, %v) = %q, want %q", tt.args, tt.readOnly, got, tt.want)
+		}
+	}
+}
+
+func TestMatchApprovalPattern(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		pattern, field, subject string
+		want                    bool
+	}{
+		{"ls *", "command", "ls -la", true},
+		{"ls *", "command", "ls", true},
+		{"ls *", "command", "lsof", false},
+		{"ls *", "command", "ls && rm -rf /", false},
+		{"ls *", "command", "ls | sh", false},
+		{"git status", "command", "git status", true},
+		{"git status", "command", "git status -s", false},
+		{"docs/*.md", "path", "docs/a/b.md", true},
+		{"docs/*.md", "path", "docs/a.txt", false},
+		{`{"q":"*"}`, "", `{"q":"a;b"}`, true},
+	}
+	for _, tt := range tests {
+		if got := matchApprovalPattern(tt.pattern, tt.field, tt.subject); got != tt.want {
+			t.Errorf("match(%q, %q) = %v, want %v", tt.pattern, tt.subject, got, tt.want)
+		}
+	}
+}
+
+func TestApprovalMemory_SessionScope(t *testing.T) {
+	t.Parallel()
+
+	m, err := NewApprovalMemory("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := ApprovalRequest{ToolName: "exec", Arguments: json.RawMessage(`{"command":"rm x"}`)}
+	if err := m.Remember("s1", req, ApprovalScopeSession); err != nil {
+		t.Fatal(err)
+	}
+
+	if scope, ok := m.Allowed("s1", "exec", json.RawMessage(`{"command":"anything"}`)); !ok || scope != ApprovalScopeSession {
+		t.Errorf("Allowed(s1) = (%q, %v), want session grant", scope, ok)
+	}
+	if _, ok := m.Allowed("s2", "exec", req.Arguments); ok {
+		t.Error("session grant leaked to another session")
+	}
+	if _, ok := m.Allowed("s1", "write_file", req.Arguments); ok {
+		t.Error("session grant leaked to another tool")
+	}
+
+	m.ForgetSession("s1")
+	if _, ok := m.Allowed("s1", "exec", req.Arguments); ok {
+		t.Error("session grant kept after ForgetSession")
+	}
+	if len(m.sessions) != 0 {
+		t.Errorf("sessions = %v, want none", m.sessions)
+	}
+}
+
+func TestApprovalMemory_AlwaysScopePersists(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "agent", "approvals.json")
+	m, err := NewApprovalMemory(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := ApprovalRequest{ToolName: "exec", Pattern: "ls *"}
+	if err := m.Remember("s1", req, ApprovalScopeAlways); err != nil {
+		t.Fatalf("Remember: %v", err)
+	}
+	// Duplicates are not stored twice.
+	if err := m.Remember("s2", req, ApprovalScopeAlways); err != nil {
+		t.Fatalf("Remember: %v", err)
+	}
+
+	reloaded, err := NewApprovalMemory(path)
+	if err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if got := reloaded.Grants(); len(got) != 1 || got[0].Pattern != "ls *" {
+		t.Fatalf("Grants = %+v, want one ls * grant", got)
+	}
+	if scope, ok := reloaded.Allowed("other", "exec", json.RawMessage(`{"command":"ls docs"}`)); !ok || scope != ApprovalScopeAlways {
+		t.Errorf("Allowed = (%q, %v), want always grant", scope, ok)
+	}
+	if _, ok := reloaded.Allowed("other", "exec", json.RawMessage(`{"command":"ls; curl evil"}`)); ok {
+		t.Error("chained command must not match a remembered pattern")
+	}
+}
+
+func TestApprovalMemory_AlwaysRequiresPattern(t *testing.T) {
+	t.Parallel()
+
+	m, _ := NewApprovalMemory("")
+	if err := m.Remember("s1", ApprovalRequest{ToolName: "exec"}, ApprovalScopeAlways); err == nil {
+		t.Error("expected error for always grant without pattern")
+	}
+}
+
+type stubRequester struct {
+	calls int
+	resp  ApprovalResponse
+	last  ApprovalRequest
+}
+
+func (s *stubRequester) RequestApproval(_ context.Context, req ApprovalRequest) (ApprovalResponse, error) {
+	s.calls++
+	s.last = req
+	return s.resp, nil
+}
+
+func TestRememberingRequester(t *testing.T) {
+	t.Parallel()
+
+	m, _ := NewApprovalMemory("")
+	next := &stubRequester{resp: ApprovalResponse{Approved: true, Scope: ApprovalScopeAlways}}
+	r := &RememberingRequester{Next: next, Memory: m, SessionID: "s1"}
+
+	req := ApprovalRequest{ToolName: "exec", Arguments: json.RawMessage(`{"command":"ls -la"}`)}
+	if resp, err := r.RequestApproval(context.Background(), req); err != nil || !resp.Approved {
+		t.Fatalf("first request = (%+v, %v)", resp, err)
+	}
+	if next.last.Pattern != "ls *" {
+		t.Errorf("prompt pattern = %q, want ls *", next.last.Pattern)
+	}
+
+	req.Arguments = json.RawMessage(`{"command":"ls src"}`)
+	resp, err := r.RequestApproval(context.Background(), req)
+	if err != nil || !resp.Approved || resp.Scope != ApprovalScopeAlways {
+		t.Fatalf("remembered request = (%+v, %v)", resp, err)
+	}
+	if next.calls != 1 {
+		t.Errorf("prompts = %d, want 1", next.calls)
+	}
+}
+
+func TestRememberingRequester_ScopeNotOffered(t *testing.T) {
+	t.Parallel()
+
+	m, _ := NewApprovalMemory("")
+	next := &stubRequester{resp: ApprovalResponse{Approved: true, Scope: ApprovalScopeAlways}}
+	r := &RememberingRequester{Next: next, Memory: m, SessionID: "s1"}
+
+	// Chained commands cannot be generalized: "always" is not offered and
+	// an "always" answer only approves this call.
+	req := ApprovalRequest{ToolName: "exec", Arguments: json.RawMessage(`{"command":"ls && make"}`)}
+	resp, err := r.RequestApproval(context.Background(), req)
+	if err != nil || !resp.Approved || resp.Scope != ApprovalScopeOnce {
+		t.Fatalf("resp = (%+v, %v), want one-off approval", resp, err)
+	}
+	if len(next.last.Scopes) != 1 || next.last.Scopes[0] != ApprovalScopeSession {
+		t.Errorf("offered scopes = %v, want [session]", next.last.Scopes)
+	}
+	if len(m.Grants()) != 0 {
+		t.Error("no grant should be persisted")
+	}
+}
@@ -0,0 +1,317 @@
+package builtin
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/flemzord/sclaw/internal/tool"
+	"github.com/flemzord/sclaw/pkg/message"
+)
+
+const (
+	// maxAttachmentSize is the largest file send_attachment accepts (50 MiB,
+	// the Telegram upload limit).
+	maxAttachmentSize = 50 << 20
+
+	// attachmentRetention is how long generated attachments are kept in the
+	// data directory; they only need to outlive the delivery of the reply.
+	attachmentRetention = 24 * time.Hour
+)
+
+// Attachment kinds accepted by send_attachment.
+const (
+	attachImage = "image"
+	attachAudio = "audio"
+	attachVoice = "voice"
+	attachFile  = "file"
+)
+
+// photoTypes are the image formats channels display as photos; other
+// images are sent as files.
+var photoTypes = map[string]bool{
+	"image/png":  true,
+	"image/jpeg": true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+type sendAttachmentTool struct{}
+
+func (t *sendAttachmentTool) Name() string { return "send_attachment" }
+
+func (t *sendAttachmentTool) Description() string {
+	return "Send a file to the user along with your reply: an image, an audio clip or a document. " +
+		"Give either the path of an existing file, or content and a filename to send generated content."
+}
+
+func (t *sendAttachmentTool) Scopes() []tool.Scope {
+	return []tool.Scope{tool.ScopeReadOnly}
+}
+
+func (t *sendAttachmentTool) DefaultPolicy() tool.ApprovalLevel {
+	return tool.ApprovalAllow
+}
+
+func (t *sendAttachmentTool) Schema() json.RawMessage {
+	return json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"path": {"type": "string", "description": "File to send (relative to workspace, or absolute within workspace/data directory/allowed directories)."},
+			"content": {"type": "string", "description": "Content to send instead of a file, with filename."},
+			"encoding": {"type": "string", "enum": ["text", "base64"], "description": "Encoding of content (default: text)."},
+			"filename": {"type": "string", "description": "File name of the generated content (required with content)."},
+			"type": {"type": "string", "enum": ["image", "audio", "voice", "file"], "description": "How to send it (default: from the file type)."},
+			"caption": {"type": "string", "description": "Optional caption shown with the attachment."}
+		}
+	}`)
+}
+
+type sendAttachmentArgs struct {
+	Path     string `json:"path,omitempty"`
+	Content  string `json:"content,omitempty"`
+	Encoding string `json:"encoding,omitempty"`
+	Filename string `json:"filename,omitempty"`
+	Type     string `json:"type,omitempty"`
+	Caption  string `json:"caption,omitempty"`
+}
+
+func (t *sendAttachmentTool) Execute(_ context.Context, args json.RawMessage, env tool.ExecutionEnv) (tool.Output, error) {
+	var a sendAttachmentArgs
+	if err := json.Unmarshal(args, &a); err != nil {
+		return tool.Output{Content: fmt.Sprintf("invalid arguments: %v", err), IsError: true}, nil
+	}
+	switch a.Type {
+	case "", attachImage, attachAudio, attachVoice, attachFile:
+	default:
+		return tool.Output{Content: fmt.Sprintf("invalid type %q (must be image, audio, voice or file)", a.Type), IsError: true}, nil
+	}
+
+	var (
+		path string
+		size int64
+		err  error
+	)
+	switch {
+	case a.Path != "" && a.Content != "":
+		return tool.Output{Content: "give either path or content, not both", IsError: true}, nil
+	case a.Path != "":
+		path, size, err = attachmentFromFile(env, a.Path)
+	case a.Content != "":
+		path, size, err = attachmentFromContent(env, a.Content, a.Encoding, a.Filename)
+	default:
+		return tool.Output{Content: "path or content is required", IsError: true}, nil
+	}
+	if err != nil {
+		return tool.Output{Content: err.Error(), IsError: true}, nil
+	}
+
+	name := filepath.Base(path)
+	mimeType := detectMIME(path)
+	kind := a.Type
+	if kind == "" {
+		kind = attachmentKind(mimeType)
+	}
+
+	url := "file://" + path
+	var block message.ContentBlock
+	switch kind {
+	case attachImage:
+		block = message.NewImageBlock(url, mimeType)
+	case attachAudio, attachVoice:
+		block = message.NewAudioBlock(url, mimeType, kind == attachVoice)
+	default:
+		block = message.NewFileBlock(url, mimeType, name)
+	}
+	block.FileName = name
+	block.Caption = a.Caption
+
+	return tool.Output{
+		Content: fmt.Sprintf("attached %s as %s (%s, %d bytes); it will be sent with your reply", name, kind, mimeType, size),
+		Blocks:  []message.ContentBlock{block},
+	}, nil
+}
+
+// attachmentFromFile resolves an existing file to send.
+func attachmentFromFile(env tool.ExecutionEnv, path string) (string, int64, error) {
+	resolved, err := SafePathForRead(env.Workspace, env.DataDir, path, env.PathFilter)
+	if err != nil {
+		return "", 0, fmt.Errorf("path error: %w", err)
+	}
+	info, err := os.Stat(resolved)
+	if err != nil {
+		return "", 0, fmt.Errorf("stat error: %w", err)
+	}
+	if !info.Mode().IsRegular() {
+		return "", 0, fmt.Errorf("%s is not a regular file", path)
+	}
+	if info.Size() > maxAttachmentSize {
+		return "", 0, fmt.Errorf("file too large: %d bytes (max %d)", info.Size(), maxAttachmentSize)
+	}
+	abs, err := filepath.Abs(resolved)
+	if err != nil {
+		return "", 0, err
+	}
+	return abs, info.Size(), nil
+}
+
+// attachmentFromContent writes generated content to the attachments
+// directory of the data directory and returns its path.
+func attachmentFromContent(env tool.ExecutionEnv, content, encoding, filename string) (string, int64, error) {
+	name := filepath.Base(filename)
+	if filename == "" || name != filename || name == "." || name == ".." {
+		return "", 0, fmt.Errorf("filename must be a plain file name, got %q", filename)
+	}
+
+	var data []byte
+	switch encoding {
+	case "", "text":
+		data = []byte(content)
+	case "base64":
+		decoded, err := base64.StdEncoding.DecodeString(content)
+		if err != nil {
+			return "", 0, fmt.Errorf("invalid base64 content: %w", err)
+		}
+		data = decoded
+	default:
+		return "", 0, fmt.Errorf("invalid encoding %q (must be text or base64)", encoding)
+	}
+	if len(data) > maxAttachmentSize {
+		return "", 0, fmt.Errorf("content too large: %d bytes (max %d)", len(data), maxAttachmentSize)
+	}
+
+	dataDir := env.DataDir
+	if dataDir == "" {
+		dataDir = filepath.Join(os.TempDir(), "sclaw")
+	}
+	dir, err := filepath.Abs(filepath.Join(dataDir, "attachments"))
+	if err != nil {
+		return "", 0, err
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", 0, fmt.Errorf("creating attachment directory: %w", err)
+	}
+	pruneAttachments(dir)
+
+	// Each attachment gets its own directory so that it is uploaded under
+	// the requested name.
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return "", 0, err
+	}
+	sub := filepath.Join(dir, hex.EncodeToString(id))
+	if err := os.Mkdir(sub, 0o700); err != nil {
+		return "", 0, fmt.Errorf("creating attachment directory: %w", err)
+	}
+	path := filepath.Join(sub, name)
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return "", 0, fmt.Errorf("write error: %w", err)
+	}
+	return path, int64(len(data)), nil
+}
+
+// pruneAttachments removes generated attachments older than
+// attachmentRetention.
+func pruneAttachments(dir string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	cutoff := time.Now().Add(-attachmentRetention)
+	for _, e := range entries {
+		if info, err := e.Info(); err == nil && info.ModTime().Before(cutoff) {
+			_ = os.RemoveAll(filepath.Join(dir, e.Name()))
+		}
+	}
+}
+
+// detectMIME returns the media type of a file, from its extension or else
+// from its first bytes.
+func detectMIME(path string) string {
+	if t := mime.TypeByExtension(filepath.Ext(path)); t != "" {
+		mediaType, _, err := mime.ParseMediaType(t)
+		if err == nil {
+			return mediaType
+		}
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return "application/octet-stream"
+	}
+	defer func() { _ = f.Close() }()
+	head := make([]byte, 512)
+	n, _ := f.Read(head)
+	mediaType, _, _ := mime.ParseMediaType(http.DetectContentType(head[:n]))
+	return mediaType
+}
+
+// attachmentKind picks how to send a file of the given media type.
+func attachmentKind(mimeType string) string {
+	switch {
+	case photoTypes[mimeType]:
+		return attachImage
+	case strings.HasPrefix(mimeType, "audio/"):
+		return attachAudio
+	default:
+		return attachFile
+	}
+}
+
+type sendLocationTool struct{}
+
+func (t *sendLocationTool) Name() string { return "send_location" }
+
+func (t *sendLocationTool) Description() string {
+	return "Send a location (a map pin) to the user along with your reply."
+}
+
+func (t *sendLocationTool) Scopes() []tool.Scope {
+	return []tool.Scope{tool.ScopeReadOnly}
+}
+
+func (t *sendLocationTool) DefaultPolicy() tool.ApprovalLevel {
+	return tool.ApprovalAllow
+}
+
+func (t *sendLocationTool) Schema() json.RawMessage {
+	return json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"latitude": {"type": "number", "description": "Latitude in degrees (-90 to 90)."},
+			"longitude": {"type": "number", "description": "Longitude in degrees (-180 to 180)."}
+		},
+		"required": ["latitude", "longitude"]
+	}`)
+}
+
+type sendLocationArgs struct {
+	Latitude  *float64 `json:"latitude"`
+	Longitude *float64 `json:"longitude"`
+}
+
+func (t *sendLocationTool) Execute(_ context.Context, args json.RawMessage, _ tool.ExecutionEnv) (tool.Output, error) {
+	var a sendLocationArgs
+	if err := json.Unmarshal(args, &a); err != nil {
+		return tool.Output{Content: fmt.Sprintf("invalid arguments: %v", err), IsError: true}, nil
+	}
+	if a.Latitude == nil || a.Longitude == nil {
+		return tool.Output{Content: "latitude and longitude are required", IsError: true}, nil
+	}
+	lat, lon := *a.Latitude, *a.Longitude
+	if lat < -90 || lat > 90 || lon < -180 || lon > 180 {
+		return tool.Output{Content: fmt.Sprintf("invalid coordinates %g, %g", lat, lon), IsError: true}, nil
+	}
+	return tool.Output{
+		Content: fmt.Sprintf("attached location %g, %g; it will be sent with your reply", lat, lon),
+		Blocks:  []message.ContentBlock{message.NewLocationBlock(lat, lon)},
+	}, nil
+}
@@ -0,0 +1,204 @@
+package builtin
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/flemzord/sclaw/internal/tool"
+	"github.com/flemzord/sclaw/pkg/message"
+)
+
+func runAttachment(t *testing.T, tl tool.Tool, env tool.ExecutionEnv, args any) tool.Output {
+	t.Helper()
+	raw, err := json.Marshal(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := tl.Execute(context.Background(), raw, env)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return out
+}
+
+func TestSendAttachmentTool_WorkspaceFile(t *testing.T) {
+	t.Parallel()
+
+	workspace := t.TempDir()
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+	if err := os.MkdirAll(filepath.Join(workspace, "out"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(workspace, "out", "chart.png"), png, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(workspace, "report.pdf"), []byte("%PDF-1.4"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(workspace, "note.ogg"), []byte("OggS"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	env := tool.ExecutionEnv{Workspace: workspace}
+	st := &sendAttachmentTool{}
+
+	tests := []struct {
+		name     string
+		args     sendAttachmentArgs
+		wantType message.BlockType
+		wantMIME string
+		wantFile string
+		voice    bool
+	}{
+		{"image", sendAttachmentArgs{Path: "out/chart.png", Caption: "Sales"}, message.BlockImage, "image/png", "out/chart.png", false},
+		{"document", sendAttachmentArgs{Path: "report.pdf"}, message.BlockFile, "application/pdf", "report.pdf", false},
+		{"forced file", sendAttachmentArgs{Path: "out/chart.png", Type: "file"}, message.BlockFile, "image/png", "out/chart.png", false},
+		{"voice", sendAttachmentArgs{Path: "note.ogg", Type: "voice"}, message.BlockAudio, "", "note.ogg", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			out := runAttachment(t, st, env, tt.args)
+			if out.IsError {
+				t.Fatalf("unexpected tool error: %s", out.Content)
+			}
+			if len(out.Blocks) != 1 {
+				t.Fatalf("blocks = %d, want 1", len(out.Blocks))
+			}
+			b := out.Blocks[0]
+			// The type of .ogg files depends on the system MIME tables.
+			if b.Type != tt.wantType || (tt.wantMIME != "" && b.MIMEType != tt.wantMIME) || b.IsVoice != tt.voice {
+				t.Errorf("block = %+v", b)
+			}
+			if want := "file://" + filepath.Join(workspace, tt.wantFile); b.URL != want {
+				t.Errorf("URL = %q, want %q", b.URL, want)
+			}
+			if b.Caption != tt.args.Caption {
+				t.Errorf("Caption = %q", b.Caption)
+			}
+		})
+	}
+}
+
+func TestSendAttachmentTool_GeneratedContent(t *testing.T) {
+	t.Parallel()
+
+	dataDir := t.TempDir()
+	env := tool.ExecutionEnv{Workspace: t.TempDir(), DataDir: dataDir}
+	st := &sendAttachmentTool{}
+
+	out := runAttachment(t, st, env, sendAttachmentArgs{Content: "a,b\n1,2\n", Filename: "data.csv"})
+	if out.IsError {
+		t.Fatalf("unexpected tool error: %s", out.Content)
+	}
+	b := out.Blocks[0]
+	if b.Type != message.BlockFile || b.FileName != "data.csv" {
+		t.Errorf("block = %+v", b)
+	}
+	path := strings.TrimPrefix(b.URL, "file://")
+	if !strings.HasPrefix(path, filepath.Join(dataDir, "attachments")) || filepath.Base(path) != "data.csv" {
+		t.Errorf("path = %q", path)
+	}
+	if data, err := os.ReadFile(path); err != nil || string(data) != "a,b\n1,2\n" {
+		t.Errorf("content = %q, %v", data, err)
+	}
+
+	out = runAttachment(t, st, env, sendAttachmentArgs{Content: "iVBORw0KGgo=", Encoding: "base64", Filename: "dot.png"})
+	if out.IsError || out.Blocks[0].Type != message.BlockImage {
+		t.Fatalf("base64 image = %+v", out)
+	}
+	data, _ := os.ReadFile(strings.TrimPrefix(out.Blocks[0].URL, "file://"))
+	if string(data) != "\x89PNG\r\n\x1a\n" {
+		t.Errorf("decoded = %q", data)
+	}
+}
+
+func TestSendAttachmentTool_PrunesOldAttachments(t *testing.T) {
+	t.Parallel()
+
+	dataDir := t.TempDir()
+	stale := filepath.Join(dataDir, "attachments", "old")
+	if err := os.MkdirAll(stale, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	past := time.Now().Add(-2 * attachmentRetention)
+	if err := os.Chtimes(stale, past, past); err != nil {
+		t.Fatal(err)
+	}
+
+	env := tool.ExecutionEnv{Workspace: t.TempDir(), DataDir: dataDir}
+	out := runAttachment(t, &sendAttachmentTool{}, env, sendAttachmentArgs{Content: "x", Filename: "x.txt"})
+	if out.IsError {
+		t.Fatal(out.Content)
+	}
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Errorf("stale attachment not removed: %v", err)
+	}
+}
+
+func TestSendAttachmentTool_Errors(t *testing.T) {
+	t.Parallel()
+
+	workspace := t.TempDir()
+	if err := os.Mkdir(filepath.Join(workspace, "dir"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	env := tool.ExecutionEnv{Workspace: workspace, DataDir: t.TempDir()}
+	st := &sendAttachmentTool{}
+
+	tests := []struct {
+		name string
+		args sendAttachmentArgs
+	}{
+		{"nothing", sendAttachmentArgs{}},
+		{"both", sendAttachmentArgs{Path: "a.txt", Content: "x", Filename: "a.txt"}},
+		{"missing file", sendAttachmentArgs{Path: "missing.txt"}},
+		{"directory", sendAttachmentArgs{Path: "dir"}},
+		{"outside workspace", sendAttachmentArgs{Path: "../../etc/passwd"}},
+		{"content without filename", sendAttachmentArgs{Content: "x"}},
+		{"filename with path", sendAttachmentArgs{Content: "x", Filename: "../x.txt"}},
+		{"bad base64", sendAttachmentArgs{Content: "!!", Encoding: "base64", Filename: "x.bin"}},
+		{"bad encoding", sendAttachmentArgs{Content: "x", Encoding: "hex", Filename: "x.bin"}},
+		{"bad type", sendAttachmentArgs{Content: "x", Filename: "x.txt", Type: "video"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			out := runAttachment(t, st, env, tt.args)
+			if !out.IsError {
+				t.Errorf("expected error, got %q", out.Content)
+			}
+			if len(out.Blocks) != 0 {
+				t.Errorf("blocks = %d, want 0", len(out.Blocks))
+			}
+		})
+	}
+}
+
+func TestSendLocationTool(t *testing.T) {
+	t.Parallel()
+
+	lt := &sendLocationTool{}
+	out := runAttachment(t, lt, tool.ExecutionEnv{}, map[string]float64{"latitude": 48.8584, "longitude": 2.2945})
+	if out.IsError {
+		t.Fatalf("unexpected tool error: %s", out.Content)
+	}
+	b := out.Blocks[0]
+	if b.Type != message.BlockLocation || *b.Lat != 48.8584 || *b.Lon != 2.2945 {
+		t.Errorf("block = %+v", b)
+	}
+
+	for _, args := range []map[string]float64{
+		{"latitude": 48.8},
+		{"latitude": 91, "longitude": 0},
+		{"latitude": 0, "longitude": -181},
+	} {
+		if out := runAttachment(t, lt, tool.ExecutionEnv{}, args); !out.IsError {
+			t.Errorf("%v: expected error", args)
+		}
+	}
+}
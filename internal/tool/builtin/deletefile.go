@@ -0,0 +1,107 @@
+package builtin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+
+	"github.com/flemzord/sclaw/internal/security"
+	"github.com/flemzord/sclaw/internal/tool"
+	"github.com/flemzord/sclaw/internal/tool/safepath"
+)
+
+type deleteFileTool struct{}
+
+func (t *deleteFileTool) Name() string { return "delete_file" }
+
+func (t *deleteFileTool) Description() string {
+	return "Delete a file, symbolic link, or empty directory in the workspace or read-write allowed directories."
+}
+
+func (t *deleteFileTool) Scopes() []tool.Scope {
+	return []tool.Scope{tool.ScopeReadWrite}
+}
+
+func (t *deleteFileTool) DefaultPolicy() tool.ApprovalLevel {
+	return tool.ApprovalAsk
+}
+
+func (t *deleteFileTool) Schema() json.RawMessage {
+	return json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"path": {"type": "string", "description": "Path to delete (relative to workspace, or absolute within workspace/read-write allowed directories)."}
+		},
+		"required": ["path"]
+	}`)
+}
+
+type deleteFileArgs struct {
+	Path string `json:"path"`
+}
+
+func (t *deleteFileTool) Execute(_ context.Context, args json.RawMessage, env tool.ExecutionEnv) (tool.Output, error) {
+	var a deleteFileArgs
+	if err := json.Unmarshal(args, &a); err != nil {
+		return tool.Output{Content: fmt.Sprintf("invalid arguments: %v", err), IsError: true}, nil
+	}
+	cleaned := filepath.Clean(a.Path)
+	if a.Path == "" || cleaned == "." || cleaned == ".." || cleaned == string(filepath.Separator) {
+		return tool.Output{Content: fmt.Sprintf("refusing to delete %q", a.Path), IsError: true}, nil
+	}
+
+	// Resolve the parent only, so that a symbolic link is deleted itself
+	// rather than its target.
+	parent, err := safepath.ForModify(env.Workspace, filepath.Dir(cleaned), env.PathFilter)
+	if err != nil {
+		return tool.Output{Content: fmt.Sprintf("path error: %v", err), IsError: true}, nil
+	}
+	target := filepath.Join(parent, filepath.Base(cleaned))
+	if err := security.ValidatePath(target); err != nil {
+		return tool.Output{Content: fmt.Sprintf("path error: %v", err), IsError: true}, nil
+	}
+	if isProtectedRoot(target, env) {
+		return tool.Output{Content: fmt.Sprintf("refusing to delete %s: it is the workspace or an allowed directory", a.Path), IsError: true}, nil
+	}
+
+	info, err := os.Lstat(target)
+	if err != nil {
+		return tool.Output{Content: fmt.Sprintf("stat error: %v", err), IsError: true}, nil
+	}
+	if err := os.Remove(target); err != nil {
+		if info.IsDir() && (errors.Is(err, syscall.ENOTEMPTY) || errors.Is(err, syscall.EEXIST)) {
+			return tool.Output{Content: fmt.Sprintf("directory %s is not empty", a.Path), IsError: true}, nil
+		}
+		return tool.Output{Content: fmt.Sprintf("delete error: %v", err), IsError: true}, nil
+	}
+
+	kind := "file"
+	switch {
+	case info.Mode()&os.ModeSymlink != 0:
+		kind = "symbolic link"
+	case info.IsDir():
+		kind = "directory"
+	}
+	env.RecordFileChange(t.Name(), "delete", target, kind)
+	return tool.Output{Content: fmt.Sprintf("deleted %s %s", kind, a.Path)}, nil
+}
+
+// isProtectedRoot reports whether path is the workspace or an allowed
+// directory itself.
+func isProtectedRoot(path string, env tool.ExecutionEnv) bool {
+	if ws, err := filepath.EvalSymlinks(env.Workspace); err == nil && ws == path {
+		return true
+	}
+	if env.PathFilter != nil {
+		for _, d := range env.PathFilter.Dirs() {
+			if d.Path == path {
+				return true
+			}
+		}
+	}
+	return false
+}
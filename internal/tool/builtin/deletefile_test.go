@@ -0,0 +1,106 @@
+package builtin
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/flemzord/sclaw/internal/security"
+	"github.com/flemzord/sclaw/internal/tool"
+)
+
+func TestDeleteFileTool(t *testing.T) {
+	t.Parallel()
+
+	dt := &deleteFileTool{}
+	if dt.DefaultPolicy() != tool.ApprovalAsk {
+		t.Errorf("DefaultPolicy() = %q, want %q", dt.DefaultPolicy(), tool.ApprovalAsk)
+	}
+
+	run := func(t *testing.T, env tool.ExecutionEnv, path string) tool.Output {
+		t.Helper()
+		args, _ := json.Marshal(deleteFileArgs{Path: path})
+		out, err := dt.Execute(context.Background(), args, env)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return out
+	}
+
+	t.Run("file", func(t *testing.T) {
+		t.Parallel()
+		workspace := t.TempDir()
+		mustWrite(t, filepath.Join(workspace, "sub", "f.txt"), "x")
+
+		var got []security.AuditEvent
+		audit := security.NewAuditLogger(security.AuditLoggerConfig{
+			OnEvent: func(e security.AuditEvent) { got = append(got, e) },
+		})
+		out := run(t, tool.ExecutionEnv{Workspace: workspace, AuditLogger: audit}, "sub/f.txt")
+		if out.IsError {
+			t.Fatalf("unexpected error output: %s", out.Content)
+		}
+		if _, err := os.Stat(filepath.Join(workspace, "sub", "f.txt")); !os.IsNotExist(err) {
+			t.Errorf("file still exists: %v", err)
+		}
+		if len(got) != 1 || got[0].Type != security.EventFileChange || got[0].Metadata["action"] != "delete" {
+			t.Errorf("unexpected audit events: %+v", got)
+		}
+	})
+
+	t.Run("symlink removes link only", func(t *testing.T) {
+		t.Parallel()
+		workspace := t.TempDir()
+		target := filepath.Join(workspace, "target.txt")
+		mustWrite(t, target, "keep")
+		if err := os.Symlink(target, filepath.Join(workspace, "link")); err != nil {
+			t.Fatal(err)
+		}
+		out := run(t, tool.ExecutionEnv{Workspace: workspace}, "link")
+		if out.IsError || !strings.Contains(out.Content, "symbolic link") {
+			t.Fatalf("got %+v", out)
+		}
+		if _, err := os.Stat(target); err != nil {
+			t.Errorf("link target was removed: %v", err)
+		}
+	})
+
+	t.Run("non-empty directory", func(t *testing.T) {
+		t.Parallel()
+		workspace := t.TempDir()
+		mustWrite(t, filepath.Join(workspace, "dir", "f.txt"), "x")
+		out := run(t, tool.ExecutionEnv{Workspace: workspace}, "dir")
+		if !out.IsError || !strings.Contains(out.Content, "not empty") {
+			t.Errorf("got %+v", out)
+		}
+	})
+
+	t.Run("workspace root", func(t *testing.T) {
+		t.Parallel()
+		workspace := t.TempDir()
+		for _, p := range []string{".", "", workspace} {
+			if out := run(t, tool.ExecutionEnv{Workspace: workspace}, p); !out.IsError {
+				t.Errorf("delete %q: expected error, got %q", p, out.Content)
+			}
+		}
+		if _, err := os.Stat(workspace); err != nil {
+			t.Errorf("workspace removed: %v", err)
+		}
+	})
+
+	t.Run("outside workspace", func(t *testing.T) {
+		t.Parallel()
+		workspace := t.TempDir()
+		outside := filepath.Join(t.TempDir(), "f.txt")
+		mustWrite(t, outside, "x")
+		if out := run(t, tool.ExecutionEnv{Workspace: workspace}, outside); !out.IsError {
+			t.Errorf("expected error, got %q", out.Content)
+		}
+		if _, err := os.Stat(outside); err != nil {
+			t.Errorf("file outside workspace removed: %v", err)
+		}
+	})
+}
@@ -0,0 +1,153 @@
+package builtin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/flemzord/sclaw/internal/tool"
+	"github.com/flemzord/sclaw/internal/tool/safepath"
+	"github.com/flemzord/sclaw/internal/tool/textdiff"
+)
+
+// maxDiffOutput bounds the diff returned by edit_file.
+const maxDiffOutput = 16 << 10
+
+type editFileTool struct{}
+
+func (t *editFileTool) Name() string { return "edit_file" }
+
+func (t *editFileTool) Description() string {
+	return "Edit a file in place by replacing an exact string or a range of lines. Returns a unified diff of the change."
+}
+
+func (t *editFileTool) Scopes() []tool.Scope {
+	return []tool.Scope{tool.ScopeReadWrite}
+}
+
+func (t *editFileTool) DefaultPolicy() tool.ApprovalLevel {
+	return tool.ApprovalAllow
+}
+
+func (t *editFileTool) Schema() json.RawMessage {
+	return json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"path": {"type": "string", "description": "File path (relative to workspace, or absolute within workspace/read-write allowed directories)."},
+			"old_string": {"type": "string", "description": "Exact text to replace. Must match exactly once unless replace_all is set."},
+			"new_string": {"type": "string", "description": "Replacement text. Empty deletes the matched text or lines."},
+			"replace_all": {"type": "boolean", "description": "Replace every occurrence of old_string."},
+			"start_line": {"type": "integer", "description": "First line to replace (1-based), instead of old_string."},
+			"end_line": {"type": "integer", "description": "Last line to replace (inclusive). Defaults to start_line."}
+		},
+		"required": ["path", "new_string"]
+	}`)
+}
+
+type editFileArgs struct {
+	Path       string `json:"path"`
+	OldString  string `json:"old_string,omitempty"`
+	NewString  string `json:"new_string"`
+	ReplaceAll bool   `json:"replace_all,omitempty"`
+	StartLine  int    `json:"start_line,omitempty"`
+	EndLine    int    `json:"end_line,omitempty"`
+}
+
+func (t *editFileTool) Execute(_ context.Context, args json.RawMessage, env tool.ExecutionEnv) (tool.Output, error) {
+	var a editFileArgs
+	if err := json.Unmarshal(args, &a); err != nil {
+		return tool.Output{Content: fmt.Sprintf("invalid arguments: %v", err), IsError: true}, nil
+	}
+	if a.OldString != "" && a.StartLine != 0 {
+		return tool.Output{Content: "use either old_string or start_line/end_line, not both", IsError: true}, nil
+	}
+	if a.OldString == "" && a.StartLine == 0 {
+		return tool.Output{Content: "old_string or start_line is required", IsError: true}, nil
+	}
+
+	resolved, err := safepath.ForModify(env.Workspace, a.Path, env.PathFilter)
+	if err != nil {
+		return tool.Output{Content: fmt.Sprintf("path error: %v", err), IsError: true}, nil
+	}
+	info, err := os.Stat(resolved)
+	if err != nil {
+		return tool.Output{Content: fmt.Sprintf("stat error: %v", err), IsError: true}, nil
+	}
+	if !info.Mode().IsRegular() {
+		return tool.Output{Content: "path is not a regular file", IsError: true}, nil
+	}
+	if info.Size() > maxWriteSize {
+		return tool.Output{Content: fmt.Sprintf("file too large: %d bytes (max %d)", info.Size(), maxWriteSize), IsError: true}, nil
+	}
+	data, err := os.ReadFile(resolved)
+	if err != nil {
+		return tool.Output{Content: fmt.Sprintf("read error: %v", err), IsError: true}, nil
+	}
+	before := string(data)
+
+	var after string
+	if a.OldString != "" {
+		after, err = replaceString(before, a.OldString, a.NewString, a.ReplaceAll)
+	} else {
+		after, err = replaceLines(before, a.StartLine, a.EndLine, a.NewString)
+	}
+	if err != nil {
+		return tool.Output{Content: err.Error(), IsError: true}, nil
+	}
+	if after == before {
+		return tool.Output{Content: "no changes: the replacement is identical to the original"}, nil
+	}
+	if len(after) > maxWriteSize {
+		return tool.Output{Content: fmt.Sprintf("result too large: %d bytes (max %d)", len(after), maxWriteSize), IsError: true}, nil
+	}
+
+	if err := os.WriteFile(resolved, []byte(after), info.Mode().Perm()); err != nil {
+		return tool.Output{Content: fmt.Sprintf("write error: %v", err), IsError: true}, nil
+	}
+
+	added, removed := textdiff.Stats(before, after)
+	env.RecordFileChange(t.Name(), "edit", resolved, fmt.Sprintf("+%d -%d", added, removed))
+
+	diff := textdiff.Unified("a/"+a.Path, "b/"+a.Path, before, after)
+	if len(diff) > maxDiffOutput {
+		diff = truncateLine(diff, maxDiffOutput) + "\n[diff truncated]"
+	}
+	return tool.Output{Content: fmt.Sprintf("edited %s (+%d -%d)\n\n%s", a.Path, added, removed, diff)}, nil
+}
+
+// replaceString replaces old with repl, which must match exactly once
+// unless all is set.
+func replaceString(content, old, repl string, all bool) (string, error) {
+	n := strings.Count(content, old)
+	switch {
+	case n == 0:
+		return "", fmt.Errorf("old_string not found in file")
+	case n > 1 && !all:
+		return "", fmt.Errorf("old_string matches %d times; include more surrounding text to make it unique, or set replace_all", n)
+	case all:
+		return strings.ReplaceAll(content, old, repl), nil
+	default:
+		return strings.Replace(content, old, repl, 1), nil
+	}
+}
+
+// replaceLines replaces lines start..end (1-based, inclusive) with repl.
+// A line terminator is added to repl when the replaced lines had one.
+func replaceLines(content string, start, end int, repl string) (string, error) {
+	lines := strings.SplitAfter(content, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if end == 0 {
+		end = start
+	}
+	if start < 1 || end < start || end > len(lines) {
+		return "", fmt.Errorf("invalid line range %d-%d: file has %d lines", start, end, len(lines))
+	}
+	if repl != "" && !strings.HasSuffix(repl, "\n") && strings.HasSuffix(lines[end-1], "\n") {
+		repl += "\n"
+	}
+	return strings.Join(lines[:start-1], "") + repl + strings.Join(lines[end:], ""), nil
+}
@@ -0,0 +1,176 @@
+package builtin
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/flemzord/sclaw/internal/security"
+	"github.com/flemzord/sclaw/internal/tool"
+)
+
+func TestEditFileTool(t *testing.T) {
+	t.Parallel()
+
+	et := &editFileTool{}
+
+	tests := []struct {
+		name    string
+		content string
+		args    editFileArgs
+		want    string
+		wantErr string
+	}{
+		{
+			name:    "replace unique string",
+			content: "alpha\nbeta\ngamma\n",
+			args:    editFileArgs{OldString: "beta", NewString: "BETA"},
+			want:    "alpha\nBETA\ngamma\n",
+		},
+		{
+			name:    "ambiguous string",
+			content: "x\nx\n",
+			args:    editFileArgs{OldString: "x", NewString: "y"},
+			wantErr: "matches 2 times",
+		},
+		{
+			name:    "replace all",
+			content: "x\nx\n",
+			args:    editFileArgs{OldString: "x", NewString: "y", ReplaceAll: true},
+			want:    "y\ny\n",
+		},
+		{
+			name:    "string not found",
+			content: "alpha\n",
+			args:    editFileArgs{OldString: "zeta", NewString: "eta"},
+			wantErr: "not found",
+		},
+		{
+			name:    "line range",
+			content: "one\ntwo\nthree\nfour\n",
+			args:    editFileArgs{StartLine: 2, EndLine: 3, NewString: "middle"},
+			want:    "one\nmiddle\nfour\n",
+		},
+		{
+			name:    "delete single line",
+			content: "one\ntwo\nthree\n",
+			args:    editFileArgs{StartLine: 2, NewString: ""},
+			want:    "one\nthree\n",
+		},
+		{
+			name:    "line range out of bounds",
+			content: "one\n",
+			args:    editFileArgs{StartLine: 2, NewString: "x"},
+			wantErr: "invalid line range",
+		},
+		{
+			name:    "both modes",
+			content: "one\n",
+			args:    editFileArgs{OldString: "one", StartLine: 1, NewString: "x"},
+			wantErr: "not both",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			workspace := t.TempDir()
+			mustWrite(t, filepath.Join(workspace, "f.txt"), tt.content)
+
+			tt.args.Path = "f.txt"
+			args, _ := json.Marshal(tt.args)
+			out, err := et.Execute(context.Background(), args, tool.ExecutionEnv{Workspace: workspace})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			got, _ := os.ReadFile(filepath.Join(workspace, "f.txt"))
+			if tt.wantErr != "" {
+				if !out.IsError || !strings.Contains(out.Content, tt.wantErr) {
+					t.Errorf("got %+v, want error containing %q", out, tt.wantErr)
+				}
+				if string(got) != tt.content {
+					t.Errorf("file modified on error: %q", got)
+				}
+				return
+			}
+			if out.IsError {
+				t.Fatalf("unexpected error output: %s", out.Content)
+			}
+			if string(got) != tt.want {
+				t.Errorf("file = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEditFileTool_DiffAndAudit(t *testing.T) {
+	t.Parallel()
+
+	workspace := t.TempDir()
+	path := filepath.Join(workspace, "f.txt")
+	mustWrite(t, path, "alpha\nbeta\ngamma\n")
+
+	var (
+		mu     sync.Mutex
+		events []security.AuditEvent
+	)
+	audit := security.NewAuditLogger(security.AuditLoggerConfig{
+		OnEvent: func(e security.AuditEvent) {
+			mu.Lock()
+			defer mu.Unlock()
+			events = append(events, e)
+		},
+	})
+	env := tool.ExecutionEnv{Workspace: workspace, SessionID: "s1", AuditLogger: audit}
+
+	args, _ := json.Marshal(editFileArgs{Path: "f.txt", OldString: "beta", NewString: "BETA"})
+	out, err := (&editFileTool{}).Execute(context.Background(), args, env)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{"(+1 -1)", "--- a/f.txt", "+++ b/f.txt", "-beta", "+BETA", " alpha"} {
+		if !strings.Contains(out.Content, want) {
+			t.Errorf("output missing %q:\n%s", want, out.Content)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(events) != 1 {
+		t.Fatalf("got %d audit events, want 1", len(events))
+	}
+	e := events[0]
+	if e.Type != security.EventFileChange || e.ToolName != "edit_file" || e.SessionID != "s1" {
+		t.Errorf("unexpected event: %+v", e)
+	}
+	if e.Metadata["action"] != "edit" || e.Metadata["path"] != path {
+		t.Errorf("unexpected metadata: %v", e.Metadata)
+	}
+}
+
+func TestEditFileTool_ReadOnlyDir(t *testing.T) {
+	t.Parallel()
+
+	workspace := t.TempDir()
+	allowedRO := t.TempDir()
+	mustWrite(t, filepath.Join(allowedRO, "f.txt"), "alpha\n")
+
+	filter := security.NewPathFilter(security.PathFilterConfig{
+		AllowedDirs: []security.AllowedDir{{Path: allowedRO, Mode: security.PathAccessRO}},
+	})
+	env := tool.ExecutionEnv{Workspace: workspace, PathFilter: filter}
+
+	args, _ := json.Marshal(editFileArgs{Path: filepath.Join(allowedRO, "f.txt"), OldString: "alpha", NewString: "beta"})
+	out, err := (&editFileTool{}).Execute(context.Background(), args, env)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !out.IsError {
+		t.Errorf("expected error editing a read-only directory, got %q", out.Content)
+	}
+}
@@ -5,7 +5,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"os/exec"
 	"time"
 
 	"github.com/flemzord/sclaw/internal/tool"
@@ -71,18 +70,18 @@ func (t *execTool) Execute(ctx context.Context, args json.RawMessage, env tool.E
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
-	cmd := exec.CommandContext(ctx, "sh", "-c", a.Command)
-	cmd.Dir = env.Workspace
-	if env.SanitizedEnv != nil {
-		cmd.Env = env.SanitizedEnv
+	cmd, release, err := tool.ShellCommand(ctx, env, a.Command)
+	if err != nil {
+		return tool.Output{Content: fmt.Sprintf("cannot start command: %v", err), IsError: true}, nil
 	}
+	defer release()
 
 	stdout := &limitedWriter{max: maxOutputSize}
 	stderr := &limitedWriter{max: maxOutputSize}
 	cmd.Stdout = stdout
 	cmd.Stderr = stderr
 
-	err := cmd.Run()
+	err = cmd.Run()
 
 	var output string
 	if stdout.Len() > 0 {
@@ -0,0 +1,95 @@
+package builtin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/flemzord/sclaw/internal/tool"
+)
+
+type globTool struct{}
+
+func (t *globTool) Name() string { return "glob" }
+
+func (t *globTool) Description() string {
+	return "Find files whose path matches a glob pattern, such as \"**/*.md\" or \"notes/2024-*.txt\"."
+}
+
+func (t *globTool) Scopes() []tool.Scope {
+	return []tool.Scope{tool.ScopeReadOnly}
+}
+
+func (t *globTool) DefaultPolicy() tool.ApprovalLevel {
+	return tool.ApprovalAllow
+}
+
+func (t *globTool) Schema() json.RawMessage {
+	return json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"pattern": {"type": "string", "description": "Glob pattern relative to path. \"*\" matches within a directory, \"**\" matches any number of directories."},
+			"path": {"type": "string", "description": "Directory to search (default: the workspace root)."}
+		},
+		"required": ["pattern"]
+	}`)
+}
+
+type globArgs struct {
+	Pattern string `json:"pattern"`
+	Path    string `json:"path,omitempty"`
+}
+
+func (t *globTool) Execute(_ context.Context, args json.RawMessage, env tool.ExecutionEnv) (tool.Output, error) {
+	var a globArgs
+	if err := json.Unmarshal(args, &a); err != nil {
+		return tool.Output{Content: fmt.Sprintf("invalid arguments: %v", err), IsError: true}, nil
+	}
+	pattern := strings.TrimPrefix(a.Pattern, "./")
+	if pattern == "" {
+		return tool.Output{Content: "pattern is required", IsError: true}, nil
+	}
+	if err := validateGlob(pattern); err != nil {
+		return tool.Output{Content: err.Error(), IsError: true}, nil
+	}
+	if a.Path == "" {
+		a.Path = "."
+	}
+
+	resolved, err := SafePathForRead(env.Workspace, env.DataDir, a.Path, env.PathFilter)
+	if err != nil {
+		return tool.Output{Content: fmt.Sprintf("path error: %v", err), IsError: true}, nil
+	}
+	if info, err := os.Stat(resolved); err != nil || !info.IsDir() {
+		return tool.Output{Content: fmt.Sprintf("%s is not a directory", a.Path), IsError: true}, nil
+	}
+
+	var matches []string
+	truncated := false
+	err = walkTree(resolved, func(rel string, d fs.DirEntry) error {
+		if d.IsDir() || !matchGlob(pattern, rel) {
+			return nil
+		}
+		if len(matches) == maxWalkResults {
+			truncated = true
+			return fs.SkipAll
+		}
+		matches = append(matches, displayPath(a.Path, rel))
+		return nil
+	})
+	if err != nil {
+		return tool.Output{Content: fmt.Sprintf("search error: %v", err), IsError: true}, nil
+	}
+
+	if len(matches) == 0 {
+		return tool.Output{Content: fmt.Sprintf("no files match %q", a.Pattern)}, nil
+	}
+	out := strings.Join(matches, "\n")
+	if truncated {
+		out += fmt.Sprintf("\n[results truncated at %d files]", maxWalkResults)
+	}
+	return tool.Output{Content: out}, nil
+}
@@ -0,0 +1,93 @@
+package builtin
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/flemzord/sclaw/internal/tool"
+)
+
+func TestMatchGlob(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		pattern, name string
+		want          bool
+	}{
+		{"*.go", "main.go", true},
+		{"*.go", "pkg/main.go", false},
+		{"**/*.go", "main.go", true},
+		{"**/*.go", "a/b/c/main.go", true},
+		{"src/**", "src/a/b.txt", true},
+		{"src/**/test_*.py", "src/test_a.py", true},
+		{"src/**/test_*.py", "src/x/y/test_a.py", true},
+		{"src/**/test_*.py", "lib/test_a.py", false},
+		{"docs/*.md", "docs/index.md", true},
+		{"docs/*.md", "docs/guide/index.md", false},
+	}
+	for _, tt := range tests {
+		if got := matchGlob(tt.pattern, tt.name); got != tt.want {
+			t.Errorf("matchGlob(%q, %q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestGlobTool(t *testing.T) {
+	t.Parallel()
+
+	workspace := t.TempDir()
+	mustWrite(t, filepath.Join(workspace, "main.go"), "package main")
+	mustWrite(t, filepath.Join(workspace, "pkg", "util.go"), "package pkg")
+	mustWrite(t, filepath.Join(workspace, "pkg", "README.md"), "# pkg")
+
+	gt := &globTool{}
+	env := tool.ExecutionEnv{Workspace: workspace}
+
+	run := func(t *testing.T, a globArgs) tool.Output {
+		t.Helper()
+		args, _ := json.Marshal(a)
+		out, err := gt.Execute(context.Background(), args, env)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return out
+	}
+
+	t.Run("recursive pattern", func(t *testing.T) {
+		t.Parallel()
+		out := run(t, globArgs{Pattern: "**/*.go"})
+		if !strings.Contains(out.Content, "main.go") || !strings.Contains(out.Content, "pkg/util.go") {
+			t.Errorf("got %q", out.Content)
+		}
+		if strings.Contains(out.Content, "README.md") {
+			t.Errorf("unexpected match in %q", out.Content)
+		}
+	})
+
+	t.Run("within subdirectory", func(t *testing.T) {
+		t.Parallel()
+		out := run(t, globArgs{Pattern: "*.md", Path: "pkg"})
+		if !strings.Contains(out.Content, "pkg/README.md") {
+			t.Errorf("got %q", out.Content)
+		}
+	})
+
+	t.Run("no match", func(t *testing.T) {
+		t.Parallel()
+		out := run(t, globArgs{Pattern: "*.rs"})
+		if out.IsError || !strings.Contains(out.Content, "no files match") {
+			t.Errorf("got %+v", out)
+		}
+	})
+
+	t.Run("invalid pattern", func(t *testing.T) {
+		t.Parallel()
+		out := run(t, globArgs{Pattern: "[a"})
+		if !out.IsError {
+			t.Errorf("expected error, got %q", out.Content)
+		}
+	})
+}
@@ -0,0 +1,177 @@
+package builtin
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/flemzord/sclaw/internal/tool"
+)
+
+const (
+	defaultGrepResults = 100
+	maxGrepLineLength  = 200
+)
+
+type grepTool struct{}
+
+func (t *grepTool) Name() string { return "grep" }
+
+func (t *grepTool) Description() string {
+	return "Search file contents for a regular expression. Returns matching lines as path:line: text."
+}
+
+func (t *grepTool) Scopes() []tool.Scope {
+	return []tool.Scope{tool.ScopeReadOnly}
+}
+
+func (t *grepTool) DefaultPolicy() tool.ApprovalLevel {
+	return tool.ApprovalAllow
+}
+
+func (t *grepTool) Schema() json.RawMessage {
+	return json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"pattern": {"type": "string", "description": "Regular expression (RE2 syntax)."},
+			"path": {"type": "string", "description": "File or directory to search (default: the workspace root)."},
+			"include": {"type": "string", "description": "Only search files whose name matches this glob, e.g. \"*.md\"."},
+			"ignore_case": {"type": "boolean", "description": "Match case-insensitively."},
+			"max_results": {"type": "integer", "description": "Maximum matching lines (default 100, max 1000)."}
+		},
+		"required": ["pattern"]
+	}`)
+}
+
+type grepArgs struct {
+	Pattern    string `json:"pattern"`
+	Path       string `json:"path,omitempty"`
+	Include    string `json:"include,omitempty"`
+	IgnoreCase bool   `json:"ignore_case,omitempty"`
+	MaxResults int    `json:"max_results,omitempty"`
+}
+
+func (t *grepTool) Execute(ctx context.Context, args json.RawMessage, env tool.ExecutionEnv) (tool.Output, error) {
+	var a grepArgs
+	if err := json.Unmarshal(args, &a); err != nil {
+		return tool.Output{Content: fmt.Sprintf("invalid arguments: %v", err), IsError: true}, nil
+	}
+	if a.Pattern == "" {
+		return tool.Output{Content: "pattern is required", IsError: true}, nil
+	}
+	expr := a.Pattern
+	if a.IgnoreCase {
+		expr = "(?i)" + expr
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return tool.Output{Content: fmt.Sprintf("invalid pattern: %v", err), IsError: true}, nil
+	}
+	if a.Include != "" {
+		if _, err := path.Match(a.Include, ""); err != nil {
+			return tool.Output{Content: fmt.Sprintf("invalid include pattern %q: %v", a.Include, err), IsError: true}, nil
+		}
+	}
+	limit := defaultGrepResults
+	if a.MaxResults > 0 {
+		limit = min(a.MaxResults, maxWalkResults)
+	}
+	if a.Path == "" {
+		a.Path = "."
+	}
+
+	resolved, err := SafePathForRead(env.Workspace, env.DataDir, a.Path, env.PathFilter)
+	if err != nil {
+		return tool.Output{Content: fmt.Sprintf("path error: %v", err), IsError: true}, nil
+	}
+	info, err := os.Stat(resolved)
+	if err != nil {
+		return tool.Output{Content: fmt.Sprintf("stat error: %v", err), IsError: true}, nil
+	}
+
+	var out []string
+	truncated := false
+	search := func(file, display string) error {
+		for _, m := range grepFile(file, re) {
+			if len(out) == limit {
+				truncated = true
+				return fs.SkipAll
+			}
+			out = append(out, display+":"+m)
+		}
+		return ctx.Err()
+	}
+
+	if !info.IsDir() {
+		err = search(resolved, filepath.ToSlash(a.Path))
+	} else {
+		err = walkTree(resolved, func(rel string, d fs.DirEntry) error {
+			if !d.Type().IsRegular() {
+				return nil
+			}
+			if a.Include != "" {
+				if ok, _ := path.Match(a.Include, d.Name()); !ok {
+					return nil
+				}
+			}
+			return search(filepath.Join(resolved, filepath.FromSlash(rel)), displayPath(a.Path, rel))
+		})
+	}
+	if err != nil && err != fs.SkipAll {
+		return tool.Output{Content: fmt.Sprintf("search error: %v", err), IsError: true}, nil
+	}
+
+	if len(out) == 0 {
+		return tool.Output{Content: fmt.Sprintf("no matches for %q", a.Pattern)}, nil
+	}
+	result := strings.Join(out, "\n")
+	if truncated {
+		result += fmt.Sprintf("\n[results truncated at %d matches]", limit)
+	}
+	return tool.Output{Content: result}, nil
+}
+
+// grepFile returns the matching lines of a text file as "line: text".
+// Binary files and files over maxFileSize are skipped.
+func grepFile(file string, re *regexp.Regexp) []string {
+	info, err := os.Stat(file)
+	if err != nil || info.Size() > maxFileSize {
+		return nil
+	}
+	data, err := os.ReadFile(file)
+	if err != nil || bytes.IndexByte(data[:min(len(data), 8000)], 0) >= 0 {
+		return nil
+	}
+
+	var matches []string
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	sc.Buffer(make([]byte, 0, 64*1024), maxFileSize)
+	for n := 1; sc.Scan(); n++ {
+		line := sc.Text()
+		if !re.MatchString(line) {
+			continue
+		}
+		line = strings.TrimSpace(line)
+		if len(line) > maxGrepLineLength {
+			line = truncateLine(line, maxGrepLineLength)
+		}
+		matches = append(matches, fmt.Sprintf("%d: %s", n, line))
+	}
+	return matches
+}
+
+// truncateLine cuts s to about limit bytes on a rune boundary.
+func truncateLine(s string, limit int) string {
+	for limit > 0 && limit < len(s) && s[limit]&0xC0 == 0x80 {
+		limit--
+	}
+	return s[:limit] + "…"
+}
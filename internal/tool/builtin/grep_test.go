@@ -0,0 +1,71 @@
+package builtin
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/flemzord/sclaw/internal/tool"
+)
+
+func TestGrepTool(t *testing.T) {
+	t.Parallel()
+
+	workspace := t.TempDir()
+	mustWrite(t, filepath.Join(workspace, "main.go"), "package main\n\nfunc main() {\n\tTODO()\n}\n")
+	mustWrite(t, filepath.Join(workspace, "notes", "todo.md"), "# Notes\n- todo: write docs\n")
+	mustWrite(t, filepath.Join(workspace, "blob.bin"), "TODO\x00binary")
+
+	gt := &grepTool{}
+	env := tool.ExecutionEnv{Workspace: workspace}
+
+	run := func(t *testing.T, a grepArgs) tool.Output {
+		t.Helper()
+		args, _ := json.Marshal(a)
+		out, err := gt.Execute(context.Background(), args, env)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return out
+	}
+
+	t.Run("case sensitive", func(t *testing.T) {
+		t.Parallel()
+		out := run(t, grepArgs{Pattern: "TODO"})
+		if !strings.Contains(out.Content, "main.go:4:") {
+			t.Errorf("got %q", out.Content)
+		}
+		if strings.Contains(out.Content, "todo.md") || strings.Contains(out.Content, "blob.bin") {
+			t.Errorf("unexpected match in %q", out.Content)
+		}
+	})
+
+	t.Run("ignore case with include", func(t *testing.T) {
+		t.Parallel()
+		out := run(t, grepArgs{Pattern: "todo", IgnoreCase: true, Include: "*.md"})
+		if !strings.Contains(out.Content, "notes/todo.md:2:") {
+			t.Errorf("got %q", out.Content)
+		}
+		if strings.Contains(out.Content, "main.go") {
+			t.Errorf("include filter ignored: %q", out.Content)
+		}
+	})
+
+	t.Run("single file", func(t *testing.T) {
+		t.Parallel()
+		out := run(t, grepArgs{Pattern: "^func", Path: "main.go"})
+		if !strings.Contains(out.Content, "main.go:3:") {
+			t.Errorf("got %q", out.Content)
+		}
+	})
+
+	t.Run("invalid regexp", func(t *testing.T) {
+		t.Parallel()
+		out := run(t, grepArgs{Pattern: "("})
+		if !out.IsError {
+			t.Errorf("expected error, got %q", out.Content)
+		}
+	})
+}
@@ -0,0 +1,122 @@
+package builtin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/flemzord/sclaw/internal/snapshot"
+	"github.com/flemzord/sclaw/internal/tool"
+)
+
+// defaultHistoryLimit is the number of turns workspace_history lists by default.
+const defaultHistoryLimit = 10
+
+type workspaceHistoryTool struct{}
+
+func (t *workspaceHistoryTool) Name() string { return "workspace_history" }
+
+func (t *workspaceHistoryTool) Description() string {
+	return "List the workspace file changes made in previous turns, or revert the changes of one turn."
+}
+
+func (t *workspaceHistoryTool) Scopes() []tool.Scope {
+	return []tool.Scope{tool.ScopeReadWrite}
+}
+
+func (t *workspaceHistoryTool) DefaultPolicy() tool.ApprovalLevel {
+	return tool.ApprovalAllow
+}
+
+func (t *workspaceHistoryTool) Schema() json.RawMessage {
+	return json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"action": {"type": "string", "enum": ["list", "revert"], "description": "list recent turns (default) or revert one turn."},
+			"turn": {"type": "integer", "description": "Turn number to revert (required for revert)."},
+			"force": {"type": "boolean", "description": "Revert even if files changed since the turn, discarding those later changes."},
+			"limit": {"type": "integer", "description": "Number of turns to list (default 10)."}
+		}
+	}`)
+}
+
+type workspaceHistoryArgs struct {
+	Action string `json:"action,omitempty"`
+	Turn   int64  `json:"turn,omitempty"`
+	Force  bool   `json:"force,omitempty"`
+	Limit  int    `json:"limit,omitempty"`
+}
+
+func (t *workspaceHistoryTool) Execute(_ context.Context, args json.RawMessage, env tool.ExecutionEnv) (tool.Output, error) {
+	var a workspaceHistoryArgs
+	if len(args) > 0 {
+		if err := json.Unmarshal(args, &a); err != nil {
+			return tool.Output{Content: fmt.Sprintf("invalid arguments: %v", err), IsError: true}, nil
+		}
+	}
+	if env.Snapshots == nil {
+		return tool.Output{Content: "workspace history is disabled for this agent", IsError: true}, nil
+	}
+	store := env.Snapshots.Store()
+
+	switch a.Action {
+	case "", "list":
+		turns, err := store.List("")
+		if err != nil {
+			return tool.Output{Content: fmt.Sprintf("history error: %v", err), IsError: true}, nil
+		}
+		limit := a.Limit
+		if limit <= 0 {
+			limit = defaultHistoryLimit
+		}
+		return tool.Output{Content: formatTurns(turns[:min(limit, len(turns))], len(turns))}, nil
+
+	case "revert":
+		if a.Turn <= 0 {
+			return tool.Output{Content: "turn is required for revert", IsError: true}, nil
+		}
+		turn, err := store.Revert(a.Turn, a.Force)
+		switch {
+		case errors.Is(err, snapshot.ErrConflict):
+			return tool.Output{Content: fmt.Sprintf("%v\nSet force to revert anyway and discard those later changes.", err), IsError: true}, nil
+		case err != nil:
+			return tool.Output{Content: fmt.Sprintf("revert error: %v", err), IsError: true}, nil
+		}
+		return tool.Output{Content: "reverted " + turn.Summary()}, nil
+
+	default:
+		return tool.Output{Content: fmt.Sprintf("unknown action %q (must be list or revert)", a.Action), IsError: true}, nil
+	}
+}
+
+// formatTurns renders turns, newest first, with one line per changed file.
+func formatTurns(turns []snapshot.Turn, total int) string {
+	if len(turns) == 0 {
+		return "no recorded workspace changes"
+	}
+	var sb strings.Builder
+	for i, t := range turns {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		fmt.Fprintf(&sb, "#%d  %s", t.ID, t.StartedAt.Format("2006-01-02 15:04 MST"))
+		if t.Prompt != "" {
+			fmt.Fprintf(&sb, "  %q", t.Prompt)
+		}
+		if t.Reverted() {
+			sb.WriteString("  [reverted]")
+		}
+		sb.WriteString("\n")
+		for _, c := range t.Changes {
+			fmt.Fprintf(&sb, "  %s %s (%s)\n", opLetter[c.Op], c.Path, c.Tool)
+		}
+	}
+	if total > len(turns) {
+		fmt.Fprintf(&sb, "\n[%d older turns not shown]", total-len(turns))
+	}
+	return sb.String()
+}
+
+var opLetter = map[string]string{snapshot.OpCreate: "A", snapshot.OpModify: "M", snapshot.OpDelete: "D"}
@@ -0,0 +1,71 @@
+package builtin
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/flemzord/sclaw/internal/snapshot"
+	"github.com/flemzord/sclaw/internal/tool"
+)
+
+func TestWorkspaceHistoryTool(t *testing.T) {
+	t.Parallel()
+
+	workspace := t.TempDir()
+	store, err := snapshot.New(snapshot.Config{Workspace: workspace, Dir: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(workspace, "a.txt")
+	mustWrite(t, path, "before")
+	store.NewRecorder("chat", "change a").Track("write_file", func() { mustWrite(t, path, "after") })
+
+	ht := &workspaceHistoryTool{}
+	env := tool.ExecutionEnv{Workspace: workspace, Snapshots: store.NewRecorder("chat", "undo it")}
+
+	run := func(a workspaceHistoryArgs) tool.Output {
+		t.Helper()
+		args, _ := json.Marshal(a)
+		out, err := ht.Execute(context.Background(), args, env)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return out
+	}
+
+	out := run(workspaceHistoryArgs{})
+	if !strings.Contains(out.Content, "#1") || !strings.Contains(out.Content, "M a.txt (write_file)") {
+		t.Errorf("list output = %q", out.Content)
+	}
+
+	out = run(workspaceHistoryArgs{Action: "revert", Turn: 1})
+	if out.IsError || !strings.Contains(out.Content, "reverted turn #1") {
+		t.Fatalf("revert output = %+v", out)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "before" {
+		t.Errorf("a.txt = %q, want before", data)
+	}
+	if out := run(workspaceHistoryArgs{}); !strings.Contains(out.Content, "[reverted]") {
+		t.Errorf("list after revert = %q", out.Content)
+	}
+
+	if out := run(workspaceHistoryArgs{Action: "revert"}); !out.IsError {
+		t.Errorf("revert without turn: %+v", out)
+	}
+}
+
+func TestWorkspaceHistoryTool_Disabled(t *testing.T) {
+	t.Parallel()
+
+	out, err := (&workspaceHistoryTool{}).Execute(context.Background(), json.RawMessage(`{}`), tool.ExecutionEnv{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !out.IsError || !strings.Contains(out.Content, "disabled") {
+		t.Errorf("got %+v", out)
+	}
+}
@@ -0,0 +1,107 @@
+package builtin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/flemzord/sclaw/internal/tool"
+)
+
+type listDirTool struct{}
+
+func (t *listDirTool) Name() string { return "list_dir" }
+
+func (t *listDirTool) Description() string {
+	return "List the entries of a directory in the workspace, data directory, or allowed directories. Directories end with \"/\"."
+}
+
+func (t *listDirTool) Scopes() []tool.Scope {
+	return []tool.Scope{tool.ScopeReadOnly}
+}
+
+func (t *listDirTool) DefaultPolicy() tool.ApprovalLevel {
+	return tool.ApprovalAllow
+}
+
+func (t *listDirTool) Schema() json.RawMessage {
+	return json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"path": {"type": "string", "description": "Directory path (default: the workspace root)."},
+			"recursive": {"type": "boolean", "description": "List subdirectories too."}
+		}
+	}`)
+}
+
+type listDirArgs struct {
+	Path      string `json:"path,omitempty"`
+	Recursive bool   `json:"recursive,omitempty"`
+}
+
+func (t *listDirTool) Execute(_ context.Context, args json.RawMessage, env tool.ExecutionEnv) (tool.Output, error) {
+	var a listDirArgs
+	if err := json.Unmarshal(args, &a); err != nil {
+		return tool.Output{Content: fmt.Sprintf("invalid arguments: %v", err), IsError: true}, nil
+	}
+	if a.Path == "" {
+		a.Path = "."
+	}
+
+	resolved, err := SafePathForRead(env.Workspace, env.DataDir, a.Path, env.PathFilter)
+	if err != nil {
+		return tool.Output{Content: fmt.Sprintf("path error: %v", err), IsError: true}, nil
+	}
+	info, err := os.Stat(resolved)
+	if err != nil {
+		return tool.Output{Content: fmt.Sprintf("stat error: %v", err), IsError: true}, nil
+	}
+	if !info.IsDir() {
+		return tool.Output{Content: "path is a file, not a directory", IsError: true}, nil
+	}
+
+	var lines []string
+	truncated := false
+	err = walkTree(resolved, func(rel string, d fs.DirEntry) error {
+		if len(lines) == maxWalkResults {
+			truncated = true
+			return fs.SkipAll
+		}
+		lines = append(lines, formatEntry(rel, d))
+		if d.IsDir() && !a.Recursive {
+			return fs.SkipDir
+		}
+		return nil
+	})
+	if err != nil {
+		return tool.Output{Content: fmt.Sprintf("list error: %v", err), IsError: true}, nil
+	}
+
+	if len(lines) == 0 {
+		return tool.Output{Content: "(empty directory)"}, nil
+	}
+	out := strings.Join(lines, "\n")
+	if truncated {
+		out += fmt.Sprintf("\n[listing truncated at %d entries]", maxWalkResults)
+	}
+	return tool.Output{Content: out}, nil
+}
+
+// formatEntry renders one listing line: directories end with "/",
+// symbolic links with "@", and files show their size.
+func formatEntry(rel string, d fs.DirEntry) string {
+	switch {
+	case d.Type()&fs.ModeSymlink != 0:
+		return rel + "@"
+	case d.IsDir():
+		return rel + "/"
+	}
+	info, err := d.Info()
+	if err != nil {
+		return rel
+	}
+	return fmt.Sprintf("%s (%s)", rel, humanSize(info.Size()))
+}
@@ -0,0 +1,91 @@
+package builtin
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/flemzord/sclaw/internal/tool"
+)
+
+func TestListDirTool(t *testing.T) {
+	t.Parallel()
+
+	workspace := t.TempDir()
+	mustWrite(t, filepath.Join(workspace, "a.txt"), "hello")
+	mustWrite(t, filepath.Join(workspace, "sub", "b.go"), "package b")
+	mustWrite(t, filepath.Join(workspace, ".git", "HEAD"), "ref")
+	if err := os.MkdirAll(filepath.Join(workspace, "empty"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	lt := &listDirTool{}
+	env := tool.ExecutionEnv{Workspace: workspace}
+
+	run := func(t *testing.T, a listDirArgs) tool.Output {
+		t.Helper()
+		args, _ := json.Marshal(a)
+		out, err := lt.Execute(context.Background(), args, env)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return out
+	}
+
+	t.Run("top level", func(t *testing.T) {
+		t.Parallel()
+		out := run(t, listDirArgs{})
+		if out.IsError {
+			t.Fatalf("unexpected error output: %s", out.Content)
+		}
+		for _, want := range []string{"a.txt (5 B)", "sub/", "empty/"} {
+			if !strings.Contains(out.Content, want) {
+				t.Errorf("output missing %q:\n%s", want, out.Content)
+			}
+		}
+		if strings.Contains(out.Content, "b.go") {
+			t.Errorf("non-recursive listing included nested file:\n%s", out.Content)
+		}
+	})
+
+	t.Run("recursive skips vcs dirs", func(t *testing.T) {
+		t.Parallel()
+		out := run(t, listDirArgs{Recursive: true})
+		if !strings.Contains(out.Content, "sub/b.go") {
+			t.Errorf("recursive listing missing sub/b.go:\n%s", out.Content)
+		}
+		if strings.Contains(out.Content, ".git") {
+			t.Errorf("recursive listing included .git:\n%s", out.Content)
+		}
+	})
+
+	t.Run("empty directory", func(t *testing.T) {
+		t.Parallel()
+		out := run(t, listDirArgs{Path: "empty"})
+		if !strings.Contains(out.Content, "(empty directory)") {
+			t.Errorf("got %q", out.Content)
+		}
+	})
+
+	t.Run("outside workspace", func(t *testing.T) {
+		t.Parallel()
+		out := run(t, listDirArgs{Path: "../"})
+		if !out.IsError {
+			t.Errorf("expected error for path outside workspace, got %q", out.Content)
+		}
+	})
+}
+
+// mustWrite creates path and its parent directories with the given content.
+func mustWrite(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
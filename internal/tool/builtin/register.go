@@ -22,5 +22,13 @@ func All() []tool.Tool {
 		&execTool{},
 		&readFileTool{},
 		&writeFileTool{},
+		&listDirTool{},
+		&globTool{},
+		&grepTool{},
+		&editFileTool{},
+		&deleteFileTool{},
+		&workspaceHistoryTool{},
+		&sendAttachmentTool{},
+		&sendLocationTool{},
 	}
 }
@@ -1,5 +1,6 @@
-// Package builtin provides the built-in tools (exec, read_file, write_file)
-// that ship with every sclaw agent.
+// Package builtin provides the built-in tools (exec, read_file, write_file,
+// list_dir, glob, grep, edit_file, delete_file, workspace_history,
+// send_attachment, send_location) that ship with every sclaw agent.
 package builtin
 
 import (
@@ -65,6 +65,7 @@ func (t *writeFileTool) Execute(_ context.Context, args json.RawMessage, env too
 	if err := os.WriteFile(resolved, []byte(a.Content), 0o644); err != nil {
 		return tool.Output{Content: fmt.Sprintf("write error: %v", err), IsError: true}, nil
 	}
+	env.RecordFileChange("write_file", "write", resolved, fmt.Sprintf("%d bytes", len(a.Content)))
 
 	return tool.Output{Content: fmt.Sprintf("wrote %d bytes to %s", len(a.Content), a.Path)}, nil
 }
@@ -0,0 +1,55 @@
+package tool
+
+import (
+	"context"
+	"os/exec"
+
+	"github.com/flemzord/sclaw/internal/security"
+)
+
+// CommandOptions adjusts how ShellCommandWith prepares a command.
+type CommandOptions struct {
+	// Interactive keeps the command's stdin connected inside the sandbox,
+	// for long-lived processes driven through pipes.
+	Interactive bool
+
+	// Limits, if non-nil, overrides the sandbox resource limits.
+	Limits *security.ResourceLimits
+}
+
+// ShellCommand prepares `sh -c command` in the workspace with the sanitized
+// environment. When env.Sandbox is set the command runs inside it, with the
+// workspace writable and the PathFilter directories mounted per their mode.
+// The caller runs the command and then calls release.
+func ShellCommand(ctx context.Context, env ExecutionEnv, command string) (cmd *exec.Cmd, release func(), err error) {
+	return ShellCommandWith(ctx, env, command, CommandOptions{})
+}
+
+// ShellCommandWith is ShellCommand with options.
+func ShellCommandWith(ctx context.Context, env ExecutionEnv, command string, opts CommandOptions) (cmd *exec.Cmd, release func(), err error) {
+	if env.Sandbox == nil {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+		cmd.Dir = env.Workspace
+		if env.SanitizedEnv != nil {
+			cmd.Env = env.SanitizedEnv
+		}
+		return cmd, func() {}, nil
+	}
+
+	spec := security.SandboxSpec{
+		Command:     command,
+		Dir:         env.Workspace,
+		Env:         env.SanitizedEnv,
+		Interactive: opts.Interactive,
+		Limits:      opts.Limits,
+	}
+	if env.PathFilter != nil {
+		for _, d := range env.PathFilter.Dirs() {
+			spec.Mounts = append(spec.Mounts, security.SandboxMount{
+				Path:     d.Path,
+				ReadOnly: d.Mode != security.PathAccessRW,
+			})
+		}
+	}
+	return env.Sandbox.Command(ctx, spec)
+}
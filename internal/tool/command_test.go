@@ -0,0 +1,162 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/flemzord/sclaw/internal/security"
+)
+
+// recordingSandbox records the specs it is asked to run and runs them on
+// the host.
+type recordingSandbox struct {
+	specs []security.SandboxSpec
+}
+
+func (s *recordingSandbox) Command(ctx context.Context, spec security.SandboxSpec) (*exec.Cmd, func(), error) {
+	s.specs = append(s.specs, spec)
+	cmd := exec.CommandContext(ctx, "sh", "-c", spec.Command)
+	cmd.Dir = spec.Dir
+	cmd.Env = spec.Env
+	return cmd, func() {}, nil
+}
+
+// envTool records the environment it was executed with.
+type envTool struct {
+	name   string
+	scopes []Scope
+	got    *ExecutionEnv
+}
+
+func (t envTool) Name() string                 { return t.name }
+func (t envTool) Description() string          { return "env tool" }
+func (t envTool) Schema() json.RawMessage      { return json.RawMessage(`{}`) }
+func (t envTool) Scopes() []Scope              { return t.scopes }
+func (t envTool) DefaultPolicy() ApprovalLevel { return ApprovalAllow }
+func (t envTool) Execute(_ context.Context, _ json.RawMessage, env ExecutionEnv) (Output, error) {
+	*t.got = env
+	return Output{Content: "ok"}, nil
+}
+
+func TestRegistryExecute_SandboxByScope(t *testing.T) {
+	t.Parallel()
+
+	sb := &recordingSandbox{}
+	var execEnv, readEnv ExecutionEnv
+	r := NewRegistry()
+	_ = r.Register(envTool{name: "exec", scopes: []Scope{ScopeExec}, got: &execEnv})
+	_ = r.Register(envTool{name: "read_file", scopes: []Scope{ScopeReadOnly}, got: &readEnv})
+	r.SetSandbox(security.SandboxPolicy{Enabled: true}, sb)
+
+	for _, name := range []string{"exec", "read_file"} {
+		if _, err := r.Execute(context.Background(), name, json.RawMessage(`{}`),
+			PolicyConfig{}, PolicyContextDM, nil, nil, time.Second, ExecutionEnv{}); err != nil {
+			t.Fatalf("execute %s: %v", name, err)
+		}
+	}
+	if execEnv.Sandbox != sb {
+		t.Error("exec tool did not receive the sandbox")
+	}
+	if readEnv.Sandbox != nil {
+		t.Error("read-only tool received the sandbox")
+	}
+
+	// Clones keep the sandbox.
+	execEnv = ExecutionEnv{}
+	if _, err := r.Clone().Execute(context.Background(), "exec", json.RawMessage(`{}`),
+		PolicyConfig{}, PolicyContextDM, nil, nil, time.Second, ExecutionEnv{}); err != nil {
+		t.Fatalf("execute clone: %v", err)
+	}
+	if execEnv.Sandbox != sb {
+		t.Error("cloned registry lost the sandbox")
+	}
+}
+
+func TestRegistryExecute_SandboxUnavailableFailsClosed(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	r := NewRegistry()
+	_ = r.Register(registryTestTool{name: "exec", scopes: []Scope{ScopeExec}, executeCalls: &calls})
+	r.SetSandbox(security.SandboxPolicy{Enabled: true}, nil)
+
+	_, err := r.Execute(context.Background(), "exec", json.RawMessage(`{}`),
+		PolicyConfig{}, PolicyContextDM, nil, nil, time.Second, ExecutionEnv{})
+	if !errors.Is(err, security.ErrSandboxUnavailable) {
+		t.Fatalf("err = %v, want ErrSandboxUnavailable", err)
+	}
+	if calls != 0 {
+		t.Error("tool ran without its sandbox")
+	}
+}
+
+func TestShellCommand(t *testing.T) {
+	t.Parallel()
+
+	workspace := t.TempDir()
+	ro, rw := t.TempDir(), t.TempDir()
+	filter := security.NewPathFilter(security.PathFilterConfig{AllowedDirs: []security.AllowedDir{
+		{Path: ro, Mode: security.PathAccessRO},
+		{Path: rw, Mode: security.PathAccessRW},
+	}})
+
+	t.Run("host", func(t *testing.T) {
+		t.Parallel()
+		cmd, release, err := ShellCommand(context.Background(), ExecutionEnv{Workspace: workspace, SanitizedEnv: []string{"A=1"}}, "echo $A")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer release()
+		out, err := cmd.Output()
+		if err != nil || strings.TrimSpace(string(out)) != "1" || cmd.Dir != workspace {
+			t.Errorf("output = %q, %v (dir %q)", out, err, cmd.Dir)
+		}
+	})
+
+	t.Run("sandboxed", func(t *testing.T) {
+		t.Parallel()
+		sb := &recordingSandbox{}
+		env := ExecutionEnv{Workspace: workspace, SanitizedEnv: []string{"A=1"}, PathFilter: filter, Sandbox: sb}
+		cmd, release, err := ShellCommand(context.Background(), env, "echo $A")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer release()
+		if out, err := cmd.Output(); err != nil || strings.TrimSpace(string(out)) != "1" {
+			t.Errorf("output = %q, %v", out, err)
+		}
+
+		if len(sb.specs) != 1 {
+			t.Fatalf("sandbox specs = %d, want 1", len(sb.specs))
+		}
+		spec := sb.specs[0]
+		if spec.Dir != workspace || spec.Command != "echo $A" || len(spec.Mounts) != 2 {
+			t.Fatalf("spec = %+v", spec)
+		}
+		for _, m := range spec.Mounts {
+			if m.ReadOnly != (m.Path == ro) {
+				t.Errorf("mount %+v has the wrong mode", m)
+			}
+		}
+	})
+
+	t.Run("options", func(t *testing.T) {
+		t.Parallel()
+		sb := &recordingSandbox{}
+		limits := &security.ResourceLimits{MemoryMB: 512}
+		_, release, err := ShellCommandWith(context.Background(), ExecutionEnv{Workspace: workspace, Sandbox: sb}, "cat",
+			CommandOptions{Interactive: true, Limits: limits})
+		if err != nil {
+			t.Fatal(err)
+		}
+		release()
+		if spec := sb.specs[0]; !spec.Interactive || spec.Limits != limits {
+			t.Errorf("spec = %+v", spec)
+		}
+	})
+}
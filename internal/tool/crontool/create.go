@@ -30,14 +30,19 @@ func (t *createTool) Schema() json.RawMessage {
 		"properties": {
 			"name":        {"type": "string", "description": "Unique name for the cron (alphanumeric, hyphens, underscores)."},
 			"description": {"type": "string", "description": "Human-readable description."},
-			"schedule":    {"type": "string", "description": "5-field cron expression (e.g. '0 9 * * *')."},
+			"schedule":    {"type": "string", "description": "5-field cron expression (e.g. '0 9 * * *'). Exclusive with at."},
+			"at":          {"type": "string", "description": "Run once at this time (RFC 3339 or 'YYYY-MM-DD HH:MM'), then disable. Exclusive with schedule."},
+			"timezone":    {"type": "string", "description": "IANA time zone of schedule and at (e.g. 'Europe/Paris'). Defaults to the agent's timezone."},
 			"enabled":     {"type": "boolean", "description": "Whether the cron is active."},
 			"prompt":      {"type": "string", "description": "The prompt to execute."},
+			"message":     {"type": "string", "description": "Text sent verbatim to output instead of running a prompt."},
 			"tools":       {"type": "array", "items": {"type": "string"}, "description": "Optional tool filter."},
 			"loop":        {"type": "object", "properties": {"max_iterations": {"type": "integer"}, "timeout": {"type": "string"}}, "description": "Optional loop config overrides."},
-			"output":      {"type": "object", "properties": {"channel": {"type": "string"}, "chat_id": {"type": "string"}}, "description": "Optional output destination."}
+			"retry":       {"type": "object", "properties": {"max_retries": {"type": "integer"}, "backoff": {"type": "string"}}, "description": "Optional retries on transient provider errors (backoff doubles from e.g. '30s')."},
+			"catch_up":    {"type": "boolean", "description": "Run once at startup if a run was missed while sclaw was down."},
+			"output":      {"type": "object", "properties": {"channel": {"type": "string"}, "chat_id": {"type": "string"}, "notify": {"type": "string", "enum": ["always", "changed", "tool", "match"]}, "match": {"type": "object", "additionalProperties": {"type": "string"}}}, "description": "Optional output destination. notify: always (default), changed, tool (send only what you pass to the notify tool) or match (send when the JSON result fields match the match regexes)."}
 		},
-		"required": ["name", "schedule", "prompt"],
+		"required": ["name"],
 		"additionalProperties": false
 	}`)
 }
@@ -52,6 +57,12 @@ func (t *createTool) Execute(_ context.Context, args json.RawMessage, env tool.E
 		return tool.Output{Content: fmt.Sprintf("invalid cron name: %q (must be alphanumeric, hyphens, underscores, max %d chars)", def.Name, maxCronNameLen), IsError: true}, nil
 	}
 
+	// Session-bound crons speak as a user in a chat: only schedule_followup
+	// creates them, bound to the conversation it is called from.
+	if def.Session != nil {
+		return tool.Output{Content: "session is not supported: use schedule_followup to follow up in this conversation", IsError: true}, nil
+	}
+
 	if err := def.Validate(); err != nil {
 		return tool.Output{Content: fmt.Sprintf("validation error: %v", err), IsError: true}, nil
 	}
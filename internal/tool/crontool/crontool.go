@@ -1,6 +1,7 @@
 // Package crontool provides CRUD tools for managing prompt cron definitions.
 // The agent can list, get, create, update, and delete scheduled prompts
-// stored as JSON files in the crons directory.
+// stored as JSON files in the crons directory, set one-shot reminders, and
+// schedule follow-ups in the current conversation.
 package crontool
 
 import (
@@ -26,6 +27,8 @@ func RegisterAll(registry *tool.Registry, deps Deps) error {
 		newCreateTool(deps),
 		newUpdateTool(deps),
 		newDeleteTool(deps),
+		newReminderTool(deps),
+		newFollowupTool(deps),
 	}
 
 	for _, t := range tools {
@@ -63,9 +63,10 @@ func (t *deleteTool) Execute(_ context.Context, args json.RawMessage, env tool.E
 		return tool.Output{Content: fmt.Sprintf("failed to delete cron %q: %v", a.Name, err), IsError: true}, nil
 	}
 
-	// Also remove the result file if it exists.
+	// Also remove the result and run history if they exist.
 	resultPath := filepath.Join(cron.ResultsDir(env.DataDir), a.Name+".json")
 	_ = os.Remove(resultPath) // best-effort
+	_ = cron.DeleteHistory(env.DataDir, a.Name)
 
 	// Trigger reload.
 	if t.deps.ReloadFn != nil {
@@ -0,0 +1,115 @@
+package crontool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/flemzord/sclaw/internal/cron"
+	"github.com/flemzord/sclaw/internal/tool"
+)
+
+type followupTool struct {
+	deps Deps
+	now  func() time.Time
+}
+
+func newFollowupTool(deps Deps) tool.Tool { return &followupTool{deps: deps, now: time.Now} }
+
+func (t *followupTool) Name() string { return "schedule_followup" }
+func (t *followupTool) Description() string {
+	return "Schedule a follow-up in the current conversation: at the given time, the prompt is sent to you here " +
+		"as a message from the user, and you answer with the full conversation history. " +
+		"Use it to check back later, e.g. to make sure the user sent the invoice. Times are in the user's timezone."
+}
+func (t *followupTool) Scopes() []tool.Scope { return []tool.Scope{tool.ScopeReadWrite} }
+func (t *followupTool) DefaultPolicy() tool.ApprovalLevel {
+	return tool.ApprovalAllow
+}
+
+func (t *followupTool) Schema() json.RawMessage {
+	return json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"when":     {"type": "string", "description": "When to follow up: a delay ('in 2h', '3d'), a time ('18:30'), a day and time ('friday 9am'), or a date ('2026-03-14 09:00')."},
+			"prompt":   {"type": "string", "description": "Instruction for your future self, e.g. 'Ask whether the invoice was sent to ACME.'"},
+			"timezone": {"type": "string", "description": "Optional IANA time zone overriding the user's (e.g. 'America/New_York')."}
+		},
+		"required": ["when", "prompt"],
+		"additionalProperties": false
+	}`)
+}
+
+type followupArgs struct {
+	When     string `json:"when"`
+	Prompt   string `json:"prompt"`
+	Timezone string `json:"timezone"`
+}
+
+func (t *followupTool) Execute(_ context.Context, args json.RawMessage, env tool.ExecutionEnv) (tool.Output, error) {
+	var a followupArgs
+	if err := json.Unmarshal(args, &a); err != nil {
+		return tool.Output{Content: fmt.Sprintf("invalid arguments: %v", err), IsError: true}, nil
+	}
+	a.Prompt = strings.TrimSpace(a.Prompt)
+	if a.Prompt == "" {
+		return tool.Output{Content: "prompt is required", IsError: true}, nil
+	}
+	if env.Origin.Channel == "" || env.Origin.ChatID == "" || env.SenderID == "" {
+		return tool.Output{Content: "follow-ups can only be scheduled from a chat", IsError: true}, nil
+	}
+
+	tz := a.Timezone
+	if tz == "" {
+		tz = env.Timezone
+	}
+	loc, err := cron.LoadLocation(tz)
+	if err != nil {
+		return tool.Output{Content: fmt.Sprintf("invalid timezone %q: %v", tz, err), IsError: true}, nil
+	}
+
+	now := t.now().In(loc)
+	at, err := parseWhen(a.When, now)
+	if err != nil {
+		return tool.Output{Content: err.Error(), IsError: true}, nil
+	}
+	at = at.In(loc)
+	if !at.After(now) {
+		return tool.Output{Content: fmt.Sprintf("%s is in the past", at.Format(time.RFC3339)), IsError: true}, nil
+	}
+
+	dir := cron.CronsDir(env.DataDir)
+	def := cron.PromptCronDef{
+		Name:        oneShotName(dir, "followup", at),
+		Description: "Follow-up: " + a.Prompt,
+		At:          at.Format(time.RFC3339),
+		Timezone:    tz,
+		Enabled:     true,
+		CatchUp:     true, // follow up even if sclaw was down at that time
+		Prompt:      a.Prompt,
+		Session: &cron.PromptCronSession{
+			Channel:  env.Origin.Channel,
+			ChatID:   env.Origin.ChatID,
+			ThreadID: env.Origin.ThreadID,
+			ChatType: string(env.Origin.ChatType),
+			SenderID: env.SenderID,
+		},
+	}
+	if err := def.Validate(); err != nil {
+		return tool.Output{Content: fmt.Sprintf("validation error: %v", err), IsError: true}, nil
+	}
+	if err := cron.SavePromptCronDef(dir, def); err != nil {
+		return tool.Output{Content: fmt.Sprintf("failed to save follow-up: %v", err), IsError: true}, nil
+	}
+
+	if t.deps.ReloadFn != nil {
+		if err := t.deps.ReloadFn(); err != nil {
+			return tool.Output{Content: fmt.Sprintf("follow-up saved but reload failed: %v", err), IsError: true}, nil
+		}
+	}
+
+	return tool.Output{Content: fmt.Sprintf("follow-up %q scheduled for %s (in %s); cancel it with cron_delete",
+		def.Name, at.Format("Mon 2 Jan 2006 15:04 MST"), at.Sub(now).Round(time.Minute))}, nil
+}
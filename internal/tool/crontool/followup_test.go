@@ -0,0 +1,67 @@
+package crontool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/flemzord/sclaw/internal/cron"
+	"github.com/flemzord/sclaw/internal/tool"
+	"github.com/flemzord/sclaw/pkg/message"
+)
+
+func TestFollowup_CreatesSessionBoundCron(t *testing.T) {
+	env, dataDir := testEnv(t)
+	env.Origin = tool.Origin{Channel: "channel.telegram", ChatID: "42", ThreadID: "9", ChatType: message.ChatGroup}
+	env.SenderID = "7"
+	env.Timezone = "UTC"
+
+	reloadCalled := 0
+	ft := newFollowupTool(Deps{ReloadFn: func() error { reloadCalled++; return nil }}).(*followupTool)
+	ft.now = func() time.Time { return time.Date(2026, 3, 11, 10, 0, 0, 0, time.UTC) } // a Wednesday
+
+	args, _ := json.Marshal(map[string]any{"when": "friday 9am", "prompt": "Ask whether the invoice was sent."})
+	out, err := ft.Execute(context.Background(), args, env)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.IsError {
+		t.Fatalf("unexpected tool error: %s", out.Content)
+	}
+	if reloadCalled != 1 {
+		t.Errorf("reload called %d times, want 1", reloadCalled)
+	}
+
+	want := time.Date(2026, 3, 13, 9, 0, 0, 0, time.UTC)
+	name := fmt.Sprintf("followup-%d", want.Unix())
+	def, err := cron.LoadPromptCronDef(filepath.Join(cron.CronsDir(dataDir), name+".json"))
+	if err != nil {
+		t.Fatalf("loading follow-up: %v", err)
+	}
+	if def.At != "2026-03-13T09:00:00Z" || !def.Enabled || !def.CatchUp || def.Output != nil {
+		t.Errorf("def = %+v", def)
+	}
+	wantSession := cron.PromptCronSession{Channel: "channel.telegram", ChatID: "42", ThreadID: "9", ChatType: "group", SenderID: "7"}
+	if def.Session == nil || *def.Session != wantSession {
+		t.Errorf("session = %+v, want %+v", def.Session, wantSession)
+	}
+	if def.Prompt != "Ask whether the invoice was sent." {
+		t.Errorf("prompt = %q", def.Prompt)
+	}
+}
+
+func TestFollowup_RequiresChat(t *testing.T) {
+	env, _ := testEnv(t)
+
+	args, _ := json.Marshal(map[string]any{"when": "in 1h", "prompt": "check"})
+	out, err := newFollowupTool(Deps{}).Execute(context.Background(), args, env)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !out.IsError {
+		t.Error("expected an error outside a chat")
+	}
+}
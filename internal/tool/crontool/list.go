@@ -31,7 +31,9 @@ func (t *listTool) Schema() json.RawMessage {
 type listEntry struct {
 	Name        string `json:"name"`
 	Description string `json:"description,omitempty"`
-	Schedule    string `json:"schedule"`
+	Schedule    string `json:"schedule,omitempty"`
+	At          string `json:"at,omitempty"`
+	Timezone    string `json:"timezone,omitempty"`
 	Enabled     bool   `json:"enabled"`
 }
 
@@ -50,6 +52,8 @@ func (t *listTool) Execute(_ context.Context, _ json.RawMessage, env tool.Execut
 			Name:        d.Name,
 			Description: d.Description,
 			Schedule:    d.Schedule,
+			At:          d.At,
+			Timezone:    d.Timezone,
 			Enabled:     d.Enabled,
 		})
 	}
@@ -0,0 +1,126 @@
+package crontool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/flemzord/sclaw/internal/cron"
+	"github.com/flemzord/sclaw/internal/tool"
+)
+
+type reminderTool struct {
+	deps Deps
+	now  func() time.Time
+}
+
+func newReminderTool(deps Deps) tool.Tool { return &reminderTool{deps: deps, now: time.Now} }
+
+func (t *reminderTool) Name() string { return "reminder" }
+func (t *reminderTool) Description() string {
+	return "Remind the user of something at a given time. The reminder is sent to the current chat once, then disabled. " +
+		"Times are in the user's timezone."
+}
+func (t *reminderTool) Scopes() []tool.Scope { return []tool.Scope{tool.ScopeReadWrite} }
+func (t *reminderTool) DefaultPolicy() tool.ApprovalLevel {
+	return tool.ApprovalAllow
+}
+
+func (t *reminderTool) Schema() json.RawMessage {
+	return json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"when":     {"type": "string", "description": "When to remind: a delay ('in 20m', '2h', '1d'), a time ('18:30', '9am'), a day and time ('tomorrow 9am', 'friday 14:00'), or a date ('2026-03-14 09:00')."},
+			"text":     {"type": "string", "description": "What to remind the user of."},
+			"timezone": {"type": "string", "description": "Optional IANA time zone overriding the user's (e.g. 'America/New_York')."}
+		},
+		"required": ["when", "text"],
+		"additionalProperties": false
+	}`)
+}
+
+type reminderArgs struct {
+	When     string `json:"when"`
+	Text     string `json:"text"`
+	Timezone string `json:"timezone"`
+}
+
+func (t *reminderTool) Execute(_ context.Context, args json.RawMessage, env tool.ExecutionEnv) (tool.Output, error) {
+	var a reminderArgs
+	if err := json.Unmarshal(args, &a); err != nil {
+		return tool.Output{Content: fmt.Sprintf("invalid arguments: %v", err), IsError: true}, nil
+	}
+	a.Text = strings.TrimSpace(a.Text)
+	if a.Text == "" {
+		return tool.Output{Content: "text is required", IsError: true}, nil
+	}
+	if env.Origin.Channel == "" || env.Origin.ChatID == "" {
+		return tool.Output{Content: "reminders can only be set from a chat", IsError: true}, nil
+	}
+
+	tz := a.Timezone
+	if tz == "" {
+		tz = env.Timezone
+	}
+	loc, err := cron.LoadLocation(tz)
+	if err != nil {
+		return tool.Output{Content: fmt.Sprintf("invalid timezone %q: %v", tz, err), IsError: true}, nil
+	}
+
+	now := t.now().In(loc)
+	at, err := parseWhen(a.When, now)
+	if err != nil {
+		return tool.Output{Content: err.Error(), IsError: true}, nil
+	}
+	at = at.In(loc)
+	if !at.After(now) {
+		return tool.Output{Content: fmt.Sprintf("%s is in the past", at.Format(time.RFC3339)), IsError: true}, nil
+	}
+
+	dir := cron.CronsDir(env.DataDir)
+	def := cron.PromptCronDef{
+		Name:        oneShotName(dir, "reminder", at),
+		Description: "Reminder: " + a.Text,
+		At:          at.Format(time.RFC3339),
+		Timezone:    tz,
+		Enabled:     true,
+		CatchUp:     true, // deliver reminders missed while sclaw was down
+		Message:     "Reminder: " + a.Text,
+		Output: &cron.PromptCronOutput{
+			Channel: env.Origin.Channel,
+			ChatID:  env.Origin.ChatID,
+		},
+	}
+	if err := def.Validate(); err != nil {
+		return tool.Output{Content: fmt.Sprintf("validation error: %v", err), IsError: true}, nil
+	}
+	if err := cron.SavePromptCronDef(dir, def); err != nil {
+		return tool.Output{Content: fmt.Sprintf("failed to save reminder: %v", err), IsError: true}, nil
+	}
+
+	if t.deps.ReloadFn != nil {
+		if err := t.deps.ReloadFn(); err != nil {
+			return tool.Output{Content: fmt.Sprintf("reminder saved but reload failed: %v", err), IsError: true}, nil
+		}
+	}
+
+	return tool.Output{Content: fmt.Sprintf("reminder %q set for %s (in %s)",
+		def.Name, at.Format("Mon 2 Jan 2006 15:04 MST"), at.Sub(now).Round(time.Minute))}, nil
+}
+
+// oneShotName returns an unused cron name starting with prefix for a
+// one-shot cron firing at at.
+func oneShotName(dir, prefix string, at time.Time) string {
+	base := fmt.Sprintf("%s-%d", prefix, at.Unix())
+	name := base
+	for i := 2; ; i++ {
+		if _, err := os.Stat(filepath.Join(dir, name+".json")); os.IsNotExist(err) {
+			return name
+		}
+		name = fmt.Sprintf("%s-%d", base, i)
+	}
+}
@@ -0,0 +1,90 @@
+package crontool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/flemzord/sclaw/internal/cron"
+	"github.com/flemzord/sclaw/internal/tool"
+)
+
+func TestReminder_CreatesOneShotCron(t *testing.T) {
+	env, dataDir := testEnv(t)
+	env.Origin = tool.Origin{Channel: "channel.telegram", ChatID: "42"}
+	env.Timezone = "UTC"
+
+	reloadCalled := 0
+	rt := newReminderTool(Deps{ReloadFn: func() error { reloadCalled++; return nil }}).(*reminderTool)
+	rt.now = func() time.Time { return time.Date(2026, 3, 11, 10, 0, 0, 0, time.UTC) }
+
+	args, _ := json.Marshal(map[string]any{"when": "in 2h", "text": "call mom"})
+	out, err := rt.Execute(context.Background(), args, env)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.IsError {
+		t.Fatalf("unexpected tool error: %s", out.Content)
+	}
+	if reloadCalled != 1 {
+		t.Errorf("reload called %d times, want 1", reloadCalled)
+	}
+
+	want := time.Date(2026, 3, 11, 12, 0, 0, 0, time.UTC)
+	name := fmt.Sprintf("reminder-%d", want.Unix())
+	def, err := cron.LoadPromptCronDef(filepath.Join(cron.CronsDir(dataDir), name+".json"))
+	if err != nil {
+		t.Fatalf("loading reminder: %v", err)
+	}
+	if def.At != "2026-03-11T12:00:00Z" || def.Timezone != "UTC" || !def.Enabled {
+		t.Errorf("def = %+v", def)
+	}
+	if def.Message != "Reminder: call mom" || def.Prompt != "" {
+		t.Errorf("message = %q, prompt = %q", def.Message, def.Prompt)
+	}
+	if def.Output == nil || def.Output.Channel != "channel.telegram" || def.Output.ChatID != "42" {
+		t.Errorf("output = %+v", def.Output)
+	}
+
+	// A second reminder at the same time gets its own name.
+	if out, _ := rt.Execute(context.Background(), args, env); out.IsError {
+		t.Fatalf("second reminder: %s", out.Content)
+	}
+	if _, err := cron.LoadPromptCronDef(filepath.Join(cron.CronsDir(dataDir), name+"-2.json")); err != nil {
+		t.Errorf("second reminder: %v", err)
+	}
+}
+
+func TestReminder_Errors(t *testing.T) {
+	chat := tool.Origin{Channel: "channel.telegram", ChatID: "42"}
+	tests := []struct {
+		name   string
+		origin tool.Origin
+		args   map[string]any
+		want   string
+	}{
+		{"no chat", tool.Origin{}, map[string]any{"when": "in 1h", "text": "x"}, "from a chat"},
+		{"no text", chat, map[string]any{"when": "in 1h", "text": " "}, "text is required"},
+		{"bad when", chat, map[string]any{"when": "later", "text": "x"}, "unrecognized time"},
+		{"past", chat, map[string]any{"when": "2020-01-01 10:00", "text": "x"}, "in the past"},
+		{"bad timezone", chat, map[string]any{"when": "in 1h", "text": "x", "timezone": "Mars/Olympus"}, "invalid timezone"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env, _ := testEnv(t)
+			env.Origin = tt.origin
+			args, _ := json.Marshal(tt.args)
+			out, err := newReminderTool(Deps{}).Execute(context.Background(), args, env)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !out.IsError || !strings.Contains(out.Content, tt.want) {
+				t.Errorf("output = %+v, want error containing %q", out, tt.want)
+			}
+		})
+	}
+}
@@ -30,12 +30,17 @@ func (t *updateTool) Schema() json.RawMessage {
 		"properties": {
 			"name":        {"type": "string", "description": "Name of the cron to update (identifies the existing file)."},
 			"description": {"type": "string", "description": "New description."},
-			"schedule":    {"type": "string", "description": "New cron schedule."},
+			"schedule":    {"type": "string", "description": "New cron schedule (empty to remove)."},
+			"at":          {"type": "string", "description": "New one-shot run time (empty to remove)."},
+			"timezone":    {"type": "string", "description": "New IANA time zone (empty for the agent's timezone)."},
 			"enabled":     {"type": "boolean", "description": "New enabled state."},
-			"prompt":      {"type": "string", "description": "New prompt."},
+			"prompt":      {"type": "string", "description": "New prompt (empty to remove)."},
+			"message":     {"type": "string", "description": "New verbatim message (empty to remove)."},
 			"tools":       {"type": "array", "items": {"type": "string"}, "description": "New tool filter."},
 			"loop":        {"type": "object", "properties": {"max_iterations": {"type": "integer"}, "timeout": {"type": "string"}}, "description": "New loop config overrides."},
-			"output":      {"type": "object", "properties": {"channel": {"type": "string"}, "chat_id": {"type": "string"}}, "description": "New output destination (null to remove)."}
+			"retry":       {"type": "object", "properties": {"max_retries": {"type": "integer"}, "backoff": {"type": "string"}}, "description": "New retry config for transient provider errors."},
+			"catch_up":    {"type": "boolean", "description": "New catch-up setting."},
+			"output":      {"type": "object", "properties": {"channel": {"type": "string"}, "chat_id": {"type": "string"}, "notify": {"type": "string", "enum": ["always", "changed", "tool", "match"]}, "match": {"type": "object", "additionalProperties": {"type": "string"}}}, "description": "New output destination (null to remove)."}
 		},
 		"required": ["name"],
 		"additionalProperties": false
@@ -47,10 +52,15 @@ type updateArgs struct {
 	Name        string                 `json:"name"`
 	Description *string                `json:"description,omitempty"`
 	Schedule    *string                `json:"schedule,omitempty"`
+	At          *string                `json:"at,omitempty"`
+	Timezone    *string                `json:"timezone,omitempty"`
 	Enabled     *bool                  `json:"enabled,omitempty"`
 	Prompt      *string                `json:"prompt,omitempty"`
+	Message     *string                `json:"message,omitempty"`
 	Tools       *[]string              `json:"tools,omitempty"`
 	Loop        *cron.PromptCronLoop   `json:"loop,omitempty"`
+	Retry       *cron.PromptCronRetry  `json:"retry,omitempty"`
+	CatchUp     *bool                  `json:"catch_up,omitempty"`
 	Output      *cron.PromptCronOutput `json:"output,omitempty"`
 }
 
@@ -71,6 +81,11 @@ func (t *updateTool) Execute(_ context.Context, args json.RawMessage, env tool.E
 	if err != nil {
 		return tool.Output{Content: fmt.Sprintf("cron %q not found or invalid: %v", a.Name, err), IsError: true}, nil
 	}
+	// A follow-up speaks as the user of the conversation it was scheduled
+	// from; editing it from elsewhere would let the agent speak for them.
+	if def.Session != nil || rawHasKey(args, "session") {
+		return tool.Output{Content: fmt.Sprintf("cron %q is a follow-up bound to a conversation and cannot be updated: delete it and use schedule_followup", a.Name), IsError: true}, nil
+	}
 
 	// Apply updates selectively.
 	if a.Description != nil {
@@ -79,18 +94,33 @@ func (t *updateTool) Execute(_ context.Context, args json.RawMessage, env tool.E
 	if a.Schedule != nil {
 		def.Schedule = *a.Schedule
 	}
+	if a.At != nil {
+		def.At = *a.At
+	}
+	if a.Timezone != nil {
+		def.Timezone = *a.Timezone
+	}
 	if a.Enabled != nil {
 		def.Enabled = *a.Enabled
 	}
 	if a.Prompt != nil {
 		def.Prompt = *a.Prompt
 	}
+	if a.Message != nil {
+		def.Message = *a.Message
+	}
 	if a.Tools != nil {
 		def.Tools = *a.Tools
 	}
 	if a.Loop != nil {
 		def.Loop = *a.Loop
 	}
+	if a.Retry != nil {
+		def.Retry = *a.Retry
+	}
+	if a.CatchUp != nil {
+		def.CatchUp = *a.CatchUp
+	}
 	// Output can be explicitly set or removed (null removes it via omitempty).
 	// We check if the raw JSON contains the "output" key.
 	if rawHasKey(args, "output") {
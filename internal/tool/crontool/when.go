@@ -0,0 +1,136 @@
+package crontool
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/flemzord/sclaw/internal/cron"
+)
+
+// defaultReminderHour is the time of day used when only a day is given
+// ("tomorrow", "friday").
+const defaultReminderHour = 9
+
+// daysPattern matches a leading day count in a duration such as "2d3h".
+var daysPattern = regexp.MustCompile(`^(\d+)d`)
+
+// clockPattern matches a wall-clock time: "18:30", "9am", "9:30pm", "18h".
+var clockPattern = regexp.MustCompile(`^(\d{1,2})(?:[:h](\d{2})?)?\s*(am|pm)?$`)
+
+// parseWhen resolves a reminder time relative to now, in now's location.
+// It accepts a duration ("in 20m", "2h30m", "1d"), a wall-clock time
+// ("18:30", "9am"), optionally preceded by "today", "tomorrow" or a
+// weekday, and absolute times understood by cron.ParseAt.
+func parseWhen(value string, now time.Time) (time.Time, error) {
+	s := strings.ToLower(strings.Join(strings.Fields(value), " "))
+	if s == "" {
+		return time.Time{}, fmt.Errorf("empty time")
+	}
+
+	if d, ok := parseDuration(strings.TrimPrefix(s, "in ")); ok {
+		return now.Add(d), nil
+	}
+	if t, err := cron.ParseAt(value, now.Location()); err == nil {
+		return t, nil
+	}
+
+	day, clock, _ := strings.Cut(strings.TrimPrefix(s, "at "), " ")
+	offset, isDay := dayOffset(day, now)
+	if !isDay {
+		// No day given: the whole value is a time, today or else tomorrow.
+		clock = s
+	}
+	clock = strings.TrimPrefix(clock, "at ")
+
+	hour, minute := defaultReminderHour, 0
+	if clock != "" {
+		var ok bool
+		if hour, minute, ok = parseClock(clock); !ok {
+			return time.Time{}, fmt.Errorf("unrecognized time %q (try \"in 20m\", \"18:30\", \"tomorrow 9am\", \"friday 14:00\" or \"2026-03-14 09:00\")", value)
+		}
+	}
+
+	t := time.Date(now.Year(), now.Month(), now.Day()+offset, hour, minute, 0, 0, now.Location())
+	if !t.After(now) {
+		switch {
+		case !isDay:
+			t = t.AddDate(0, 0, 1)
+		case day != "today" && day != "tomorrow":
+			t = t.AddDate(0, 0, 7)
+		}
+	}
+	return t, nil
+}
+
+// parseDuration parses a Go duration, also accepting a leading day count.
+func parseDuration(s string) (time.Duration, bool) {
+	var days time.Duration
+	if m := daysPattern.FindStringSubmatch(s); m != nil {
+		n, err := strconv.Atoi(m[1])
+		if err != nil {
+			return 0, false
+		}
+		days = time.Duration(n) * 24 * time.Hour
+		s = s[len(m[0]):]
+		if s == "" {
+			return days, true
+		}
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d+days <= 0 {
+		return 0, false
+	}
+	return d + days, true
+}
+
+// dayOffset returns how many days ahead of now the named day is.
+func dayOffset(day string, now time.Time) (int, bool) {
+	switch day {
+	case "today":
+		return 0, true
+	case "tomorrow":
+		return 1, true
+	}
+	for wd := time.Sunday; wd <= time.Saturday; wd++ {
+		name := strings.ToLower(wd.String())
+		if day == name || day == name[:3] {
+			return (int(wd) - int(now.Weekday()) + 7) % 7, true
+		}
+	}
+	return 0, false
+}
+
+// parseClock parses a wall-clock time into hour and minute.
+func parseClock(s string) (hour, minute int, ok bool) {
+	m := clockPattern.FindStringSubmatch(s)
+	if m == nil {
+		return 0, 0, false
+	}
+	hour, _ = strconv.Atoi(m[1])
+	if m[2] != "" {
+		minute, _ = strconv.Atoi(m[2])
+	}
+	switch m[3] {
+	case "am":
+		if hour < 1 || hour > 12 {
+			return 0, 0, false
+		}
+		if hour == 12 {
+			hour = 0
+		}
+	case "pm":
+		if hour < 1 || hour > 12 {
+			return 0, 0, false
+		}
+		if hour != 12 {
+			hour += 12
+		}
+	}
+	if hour > 23 || minute > 59 {
+		return 0, 0, false
+	}
+	return hour, minute, true
+}
@@ -0,0 +1,61 @@
+package crontool
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseWhen(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Skipf("tzdata unavailable: %v", err)
+	}
+	// Wednesday 2026-03-11 10:00 in Paris.
+	now := time.Date(2026, 3, 11, 10, 0, 0, 0, paris)
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, 3, day, hour, minute, 0, 0, paris)
+	}
+
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"in 20m", now.Add(20 * time.Minute)},
+		{"2h30m", now.Add(150 * time.Minute)},
+		{"1d", now.Add(24 * time.Hour)},
+		{"1d2h", now.Add(26 * time.Hour)},
+		{"18:30", at(11, 18, 30)},
+		{"09:00", at(12, 9, 0)}, // passed today: tomorrow
+		{"at 6pm", at(11, 18, 0)},
+		{"9:30 pm", at(11, 21, 30)},
+		{"12am", at(12, 0, 0)},
+		{"tomorrow", at(12, 9, 0)},
+		{"Tomorrow at 7:15am", at(12, 7, 15)},
+		{"today 14h", at(11, 14, 0)},
+		{"friday 14:00", at(13, 14, 0)},
+		{"wed 9am", at(18, 9, 0)}, // passed this week: next week
+		{"wed 11am", at(11, 11, 0)},
+		{"2026-04-01 08:00", time.Date(2026, 4, 1, 8, 0, 0, 0, paris)},
+		{"2026-04-01T08:00:00Z", time.Date(2026, 4, 1, 8, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := parseWhen(tt.in, now)
+			if err != nil {
+				t.Fatalf("parseWhen(%q): %v", tt.in, err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("parseWhen(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseWhen_Invalid(t *testing.T) {
+	now := time.Date(2026, 3, 11, 10, 0, 0, 0, time.UTC)
+	for _, in := range []string{"", "soon", "25:00", "13pm", "tomorrow noonish", "-5m"} {
+		if got, err := parseWhen(in, now); err == nil {
+			t.Errorf("parseWhen(%q) = %v, want error", in, got)
+		}
+	}
+}
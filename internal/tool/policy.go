@@ -19,6 +19,32 @@ const (
 	ApprovalDeny ApprovalLevel = "deny"
 )
 
+// PolicyFloor is an optional interface for tools whose owner imposes a
+// minimum approval level, such as the tools of an MCP server configured
+// with "ask". The level a call runs at is never looser than the floor,
+// whatever the agent's policy, context default or elevated state.
+type PolicyFloor interface {
+	PolicyFloor() ApprovalLevel
+}
+
+// stricterLevel returns the stricter of two approval levels.
+func stricterLevel(a, b ApprovalLevel) ApprovalLevel {
+	rank := func(l ApprovalLevel) int {
+		switch l {
+		case ApprovalDeny:
+			return 2
+		case ApprovalAsk:
+			return 1
+		default:
+			return 0
+		}
+	}
+	if rank(b) > rank(a) {
+		return b
+	}
+	return a
+}
+
 // PolicyContext describes the context in which a tool is invoked.
 type PolicyContext string
 
@@ -31,30 +57,68 @@ const (
 // Policy defines the approval settings for a context.
 type Policy struct {
 	// Default is the fallback approval level for tools not explicitly listed.
-	Default ApprovalLevel
+	Default ApprovalLevel `yaml:"default"`
 
 	// Tools maps tool names to explicit approval levels.
-	Tools map[string]ApprovalLevel
+	Tools map[string]ApprovalLevel `yaml:"tools"`
 
 	// Allow lists tools that can execute without confirmation.
-	Allow []string
+	Allow []string `yaml:"allow"`
 
 	// Ask lists tools that require confirmation before execution.
-	Ask []string
+	Ask []string `yaml:"ask"`
 
 	// Deny lists tools that must never execute.
-	Deny []string
+	Deny []string `yaml:"deny"`
+
+	// Rules are argument- and environment-aware entries evaluated in order
+	// before the name-based settings above.
+	Rules []PolicyRule `yaml:"rules"`
 }
 
 // PolicyConfig holds policies for each context type.
 type PolicyConfig struct {
-	DM    Policy
-	Group Policy
+	DM    Policy `yaml:"dm"`
+	Group Policy `yaml:"group"`
 }
 
-// ResolvePolicy determines the effective approval level for a tool.
-// Resolution order: explicit tool mapping > context default > tool's DefaultPolicy.
-func ResolvePolicy(cfg PolicyConfig, ctx PolicyContext, t Tool) ApprovalLevel {
+// PolicyDecision is the outcome of ResolvePolicy for one tool call.
+type PolicyDecision struct {
+	// Level is the effective approval level.
+	Level ApprovalLevel
+
+	// Context is the policy context the decision was made in.
+	Context PolicyContext
+
+	// Rule is the 1-based index of the matching rule, or 0 when the level
+	// comes from the name-based settings or the tool default.
+	Rule int
+
+	// Reason is the matching rule's reason, if any.
+	Reason string
+
+	// Approvers restricts who may answer an approval prompt. Empty means
+	// any user of the conversation.
+	Approvers []string
+}
+
+// Explain describes which rule produced the decision, e.g.
+// "rule #3 (group): outside docs". It returns "" when no rule matched.
+func (d PolicyDecision) Explain() string {
+	if d.Rule == 0 {
+		return ""
+	}
+	s := fmt.Sprintf("rule #%d (%s)", d.Rule, d.Context)
+	if d.Reason != "" {
+		s += ": " + d.Reason
+	}
+	return s
+}
+
+// ResolvePolicy determines the effective approval decision for a tool call.
+// Resolution order: first matching rule > explicit tool mapping > context
+// default > tool's DefaultPolicy.
+func ResolvePolicy(cfg PolicyConfig, ctx PolicyContext, t Tool, call PolicyCall) PolicyDecision {
 	var policy Policy
 	switch ctx {
 	case PolicyContextDM:
@@ -62,22 +126,36 @@ func ResolvePolicy(cfg PolicyConfig, ctx PolicyContext, t Tool) ApprovalLevel {
 	case PolicyContextGroup:
 		policy = cfg.Group
 	default:
-		return t.DefaultPolicy()
+		return PolicyDecision{Level: t.DefaultPolicy(), Context: ctx}
 	}
 
-	// Check explicit tool mapping first.
 	toolName := strings.TrimSpace(t.Name())
+
+	// Rules come first: they are the most specific settings.
+	for i, rule := range policy.Rules {
+		if rule.matches(toolName, call) {
+			return PolicyDecision{
+				Level:     rule.Action,
+				Context:   ctx,
+				Rule:      i + 1,
+				Reason:    rule.Reason,
+				Approvers: rule.Approvers,
+			}
+		}
+	}
+
+	// Check explicit tool mapping next.
 	if level, ok := resolveExplicitLevel(policy, toolName); ok {
-		return level
+		return PolicyDecision{Level: level, Context: ctx}
 	}
 
 	// Fall back to context default if set.
 	if policy.Default != "" {
-		return policy.Default
+		return PolicyDecision{Level: policy.Default, Context: ctx}
 	}
 
 	// Fall back to the tool's own default.
-	return t.DefaultPolicy()
+	return PolicyDecision{Level: t.DefaultPolicy(), Context: ctx}
 }
 
 // ValidatePolicyConfig checks that no tool appears with conflicting assignments
@@ -120,6 +198,12 @@ func validatePolicy(policy Policy, ctx string) error {
 		return err
 	}
 
+	for i, rule := range policy.Rules {
+		if err := rule.validate(); err != nil {
+			return fmt.Errorf("policy %s: rule #%d: %w", ctx, i+1, err)
+		}
+	}
+
 	return nil
 }
 
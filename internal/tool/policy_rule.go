@@ -0,0 +1,269 @@
+package tool
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// PolicyRule is a policy entry that matches on tool arguments and on the
+// environment of the call. Rules are evaluated in order; the first rule
+// whose conditions all hold decides the approval level. Conditions left
+// empty always hold.
+//
+// Example (YAML):
+//
+//	rules:
+//	  - tools: [exec]
+//	    args: {command: '^git (status|log)( .*)?$'}
+//	    action: allow
+//	  - tools: [write_file]
+//	    args_not: {path: '^docs/'}
+//	    action: deny
+//	    reason: writes are limited to docs/
+type PolicyRule struct {
+	// Tools lists the tool names the rule applies to. '*' matches any
+	// sequence of characters ("mcp_*"). Empty matches every tool.
+	Tools []string `yaml:"tools"`
+
+	// Action is the approval level applied when the rule matches.
+	Action ApprovalLevel `yaml:"action"`
+
+	// Args maps top-level argument fields to regular expressions the field
+	// must match. A missing field never matches. In allow rules, a command
+	// or cmd field containing shell control characters never matches, so
+	// that "git status; rm -rf ~" is not allowed by a git rule.
+	Args map[string]string `yaml:"args"`
+
+	// ArgsNot maps top-level argument fields to regular expressions the
+	// field must not match. A missing field is matched as "".
+	ArgsNot map[string]string `yaml:"args_not"`
+
+	// Senders restricts the rule to calls triggered by these user IDs.
+	Senders []string `yaml:"senders"`
+
+	// Hours restricts the rule to a daily window "HH:MM-HH:MM". The end is
+	// exclusive and the window may wrap midnight ("22:00-07:00").
+	Hours string `yaml:"hours"`
+
+	// Timezone is the IANA time zone Hours are expressed in. Defaults to
+	// the local time zone.
+	Timezone string `yaml:"timezone"`
+
+	// Approvers restricts who may answer the approval prompt of an "ask"
+	// rule. Empty means any user of the conversation.
+	Approvers []string `yaml:"approvers"`
+
+	// Reason explains the rule in audit events and denial messages.
+	Reason string `yaml:"reason"`
+}
+
+// PolicyCall describes the tool call a policy is resolved for.
+type PolicyCall struct {
+	// Args are the raw JSON arguments of the call.
+	Args json.RawMessage
+
+	// SenderID identifies the user whose message triggered the call.
+	SenderID string
+
+	// Time is when the call happens. Zero means now.
+	Time time.Time
+}
+
+// matches reports whether the rule applies to a call of toolName.
+func (r PolicyRule) matches(toolName string, call PolicyCall) bool {
+	if len(r.Tools) > 0 && !slices.ContainsFunc(r.Tools, func(p string) bool {
+		return wildcardMatch(strings.TrimSpace(p), toolName)
+	}) {
+		return false
+	}
+	if len(r.Senders) > 0 && !slices.Contains(r.Senders, call.SenderID) {
+		return false
+	}
+	if r.Hours != "" && !r.inHours(call.Time) {
+		return false
+	}
+	if len(r.Args) == 0 && len(r.ArgsNot) == 0 {
+		return true
+	}
+
+	// An allow rule about arguments vouches for one command, never for a
+	// chain of them, whichever fields its patterns look at.
+	fields := policyArgFields(call.Args)
+	if r.Action == ApprovalAllow && hasChainedCommand(fields) {
+		return false
+	}
+	for field, pattern := range r.Args {
+		value, ok := fields[field]
+		if !ok {
+			return false
+		}
+		re, err := compileRulePattern(pattern)
+		if err != nil || !re.MatchString(value) {
+			return false
+		}
+	}
+	for field, pattern := range r.ArgsNot {
+		re, err := compileRulePattern(pattern)
+		if err != nil || re.MatchString(fields[field]) {
+			return false
+		}
+	}
+	return true
+}
+
+// inHours reports whether t falls within the rule's daily window.
+func (r PolicyRule) inHours(t time.Time) bool {
+	start, end, err := parseHours(r.Hours)
+	if err != nil {
+		return false
+	}
+	if t.IsZero() {
+		t = time.Now()
+	}
+	if r.Timezone != "" {
+		loc, err := time.LoadLocation(r.Timezone)
+		if err != nil {
+			return false
+		}
+		t = t.In(loc)
+	}
+	minute := t.Hour()*60 + t.Minute()
+	if start < end {
+		return minute >= start && minute < end
+	}
+	return minute >= start || minute < end
+}
+
+// validate checks the rule's action and that its patterns, hours and time
+// zone parse.
+func (r PolicyRule) validate() error {
+	if !isValidApprovalLevel(r.Action) {
+		return fmt.Errorf("invalid action %q", r.Action)
+	}
+	for _, name := range r.Tools {
+		if strings.TrimSpace(name) == "" {
+			return errors.New("tools contains an empty name")
+		}
+	}
+	for field, pattern := range r.Args {
+		if _, err := compileRulePattern(pattern); err != nil {
+			return fmt.Errorf("args %q: %w", field, err)
+		}
+	}
+	for field, pattern := range r.ArgsNot {
+		if _, err := compileRulePattern(pattern); err != nil {
+			return fmt.Errorf("args_not %q: %w", field, err)
+		}
+	}
+	if r.Hours != "" {
+		if _, _, err := parseHours(r.Hours); err != nil {
+			return err
+		}
+	}
+	if r.Timezone != "" {
+		if _, err := time.LoadLocation(r.Timezone); err != nil {
+			return fmt.Errorf("invalid timezone %q: %w", r.Timezone, err)
+		}
+	}
+	if len(r.Approvers) > 0 && r.Action != ApprovalAsk {
+		return errors.New(`approvers require action "ask"`)
+	}
+	return nil
+}
+
+// parseHours parses "HH:MM-HH:MM" into minutes since midnight.
+func parseHours(s string) (start, end int, err error) {
+	from, to, ok := strings.Cut(s, "-")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid hours %q (expected HH:MM-HH:MM)", s)
+	}
+	if start, err = parseClock(from); err != nil {
+		return 0, 0, fmt.Errorf("invalid hours %q: %w", s, err)
+	}
+	if end, err = parseClock(to); err != nil {
+		return 0, 0, fmt.Errorf("invalid hours %q: %w", s, err)
+	}
+	if start == end {
+		return 0, 0, fmt.Errorf("invalid hours %q: empty window", s)
+	}
+	return start, end, nil
+}
+
+// parseClock parses "HH:MM" (00:00 to 24:00) into minutes since midnight.
+func parseClock(s string) (int, error) {
+	var h, m int
+	if n, err := fmt.Sscanf(strings.TrimSpace(s), "%d:%d", &h, &m); err != nil || n != 2 {
+		return 0, fmt.Errorf("invalid time %q", s)
+	}
+	if h < 0 || m < 0 || m > 59 || h > 24 || (h == 24 && m != 0) {
+		return 0, fmt.Errorf("invalid time %q", s)
+	}
+	return h*60 + m, nil
+}
+
+// rulePatterns caches compiled rule patterns; policies are resolved on
+// every tool call.
+var rulePatterns sync.Map // string → *regexp.Regexp
+
+// compileRulePattern compiles pattern, caching the result.
+func compileRulePattern(pattern string) (*regexp.Regexp, error) {
+	if re, ok := rulePatterns.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	rulePatterns.Store(pattern, re)
+	return re, nil
+}
+
+// hasChainedCommand reports whether a command field of the call contains
+// shell control characters.
+func hasChainedCommand(fields map[string]string) bool {
+	for field, value := range fields {
+		if isCommandField(field) && hasShellControl(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// policyArgFields flattens the top-level argument fields into strings:
+// strings as-is, other values as compact JSON. Path-like fields are cleaned
+// so that "docs/../etc" cannot pass for a path under docs/.
+func policyArgFields(args json.RawMessage) map[string]string {
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(args, &obj); err != nil {
+		return nil
+	}
+	fields := make(map[string]string, len(obj))
+	for name, raw := range obj {
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			var buf bytes.Buffer
+			if json.Compact(&buf, raw) == nil {
+				s = buf.String()
+			} else {
+				s = string(raw)
+			}
+		} else if isPathField(name) && s != "" {
+			s = path.Clean(s)
+		}
+		fields[name] = s
+	}
+	return fields
+}
+
+// isPathField reports whether an argument field holds a file path.
+func isPathField(name string) bool {
+	return name == "path" || strings.HasSuffix(name, "_path")
+}
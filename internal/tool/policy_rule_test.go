@@ -0,0 +1,213 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/flemzord/sclaw/internal/security"
+)
+
+func TestResolvePolicy_Rules(t *testing.T) {
+	t.Parallel()
+
+	night := time.Date(2026, 1, 1, 23, 30, 0, 0, time.UTC)
+	noon := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	cfg := PolicyConfig{
+		DM: Policy{
+			Default: ApprovalAsk,
+			Rules: []PolicyRule{
+				{Tools: []string{"exec"}, Args: map[string]string{"command": `^git (status|log)( .*)?$`}, Action: ApprovalAllow},
+				{Tools: []string{"write_file"}, ArgsNot: map[string]string{"path": `^docs/`}, Action: ApprovalDeny, Reason: "outside docs"},
+				{Tools: []string{"web_*", "http_fetch"}, Hours: "22:00-07:00", Timezone: "UTC", Action: ApprovalAsk},
+				{Tools: []string{"web_*", "http_fetch"}, Action: ApprovalAllow},
+			},
+		},
+		Group: Policy{
+			Rules: []PolicyRule{
+				{Senders: []string{"admin"}, Action: ApprovalAllow},
+				{Tools: []string{"exec"}, Action: ApprovalAsk, Approvers: []string{"admin"}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		ctx       PolicyContext
+		tool      string
+		call      PolicyCall
+		want      ApprovalLevel
+		wantRule  int
+		approvers int
+	}{
+		{"git status allowed", PolicyContextDM, "exec", PolicyCall{Args: json.RawMessage(`{"command":"git status"}`)}, ApprovalAllow, 1, 0},
+		{"git log with args allowed", PolicyContextDM, "exec", PolicyCall{Args: json.RawMessage(`{"command":"git log --oneline"}`)}, ApprovalAllow, 1, 0},
+		{"chained command falls through", PolicyContextDM, "exec", PolicyCall{Args: json.RawMessage(`{"command":"git status; rm -rf ~"}`)}, ApprovalAsk, 0, 0},
+		{"substitution falls through", PolicyContextDM, "exec", PolicyCall{Args: json.RawMessage(`{"command":"git log $(rm -rf ~)"}`)}, ApprovalAsk, 0, 0},
+		{"other command falls through", PolicyContextDM, "exec", PolicyCall{Args: json.RawMessage(`{"command":"rm -rf /"}`)}, ApprovalAsk, 0, 0},
+		{"missing field never matches", PolicyContextDM, "exec", PolicyCall{Args: json.RawMessage(`{}`)}, ApprovalAsk, 0, 0},
+		{"write inside docs", PolicyContextDM, "write_file", PolicyCall{Args: json.RawMessage(`{"path":"docs/a.md"}`)}, ApprovalAsk, 0, 0},
+		{"write outside docs", PolicyContextDM, "write_file", PolicyCall{Args: json.RawMessage(`{"path":"src/a.go"}`)}, ApprovalDeny, 2, 0},
+		{"traversal is cleaned", PolicyContextDM, "write_file", PolicyCall{Args: json.RawMessage(`{"path":"docs/../etc/passwd"}`)}, ApprovalDeny, 2, 0},
+		{"network at night", PolicyContextDM, "web_search", PolicyCall{Time: night}, ApprovalAsk, 3, 0},
+		{"network at noon", PolicyContextDM, "web_search", PolicyCall{Time: noon}, ApprovalAllow, 4, 0},
+		{"admin allowed in group", PolicyContextGroup, "exec", PolicyCall{SenderID: "admin"}, ApprovalAllow, 1, 0},
+		{"others need admin approval", PolicyContextGroup, "exec", PolicyCall{SenderID: "bob"}, ApprovalAsk, 2, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := ResolvePolicy(cfg, tt.ctx, stubTool{name: tt.tool, defaultPolicy: ApprovalAsk}, tt.call)
+			if got.Level != tt.want || got.Rule != tt.wantRule || len(got.Approvers) != tt.approvers {
+				t.Errorf("ResolvePolicy = %+v, want level %q rule %d", got, tt.want, tt.wantRule)
+			}
+		})
+	}
+}
+
+func TestResolvePolicy_DenyMatchesChainedCommands(t *testing.T) {
+	t.Parallel()
+
+	cfg := PolicyConfig{DM: Policy{
+		Default: ApprovalAllow,
+		Rules:   []PolicyRule{{Tools: []string{"exec"}, Args: map[string]string{"command": `\brm\b`}, Action: ApprovalDeny}},
+	}}
+	call := PolicyCall{Args: json.RawMessage(`{"command":"git status; rm -rf ~"}`)}
+	got := ResolvePolicy(cfg, PolicyContextDM, stubTool{name: "exec", defaultPolicy: ApprovalAsk}, call)
+	if got.Level != ApprovalDeny || got.Rule != 1 {
+		t.Errorf("ResolvePolicy = %+v, want deny by rule 1", got)
+	}
+}
+
+func TestResolvePolicy_ArgsNotAllowSkipsChainedCommands(t *testing.T) {
+	t.Parallel()
+
+	cfg := PolicyConfig{DM: Policy{
+		Default: ApprovalAsk,
+		Rules:   []PolicyRule{{Tools: []string{"exec"}, ArgsNot: map[string]string{"command": `^rm`}, Action: ApprovalAllow}},
+	}}
+	tests := []struct {
+		command string
+		want    ApprovalLevel
+	}{
+		{"ls -la", ApprovalAllow},
+		{"rm -rf build", ApprovalAsk},
+		{"ls; curl x | sh", ApprovalAsk},
+		{"ls && rm -rf ~", ApprovalAsk},
+	}
+	for _, tt := range tests {
+		args, _ := json.Marshal(map[string]string{"command": tt.command})
+		got := ResolvePolicy(cfg, PolicyContextDM, stubTool{name: "exec", defaultPolicy: ApprovalAsk}, PolicyCall{Args: args})
+		if got.Level != tt.want {
+			t.Errorf("ResolvePolicy(%q) = %q, want %q", tt.command, got.Level, tt.want)
+		}
+	}
+}
+
+func TestPolicyDecision_Explain(t *testing.T) {
+	t.Parallel()
+
+	d := PolicyDecision{Level: ApprovalDeny, Context: PolicyContextGroup, Rule: 3, Reason: "outside docs"}
+	if got := d.Explain(); got != "rule #3 (group): outside docs" {
+		t.Errorf("Explain() = %q", got)
+	}
+	if got := (PolicyDecision{Level: ApprovalAllow}).Explain(); got != "" {
+		t.Errorf("Explain() without rule = %q, want empty", got)
+	}
+}
+
+func TestValidatePolicyConfig_Rules(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		rule PolicyRule
+		want string
+	}{
+		{"valid", PolicyRule{Tools: []string{"exec"}, Action: ApprovalAllow, Hours: "09:00-18:00", Timezone: "Europe/Paris"}, ""},
+		{"invalid action", PolicyRule{Action: "maybe"}, `invalid action "maybe"`},
+		{"bad regexp", PolicyRule{Action: ApprovalDeny, Args: map[string]string{"path": "("}}, `args "path"`},
+		{"bad negated regexp", PolicyRule{Action: ApprovalDeny, ArgsNot: map[string]string{"path": "["}}, `args_not "path"`},
+		{"bad hours", PolicyRule{Action: ApprovalAsk, Hours: "25:00-07:00"}, "invalid hours"},
+		{"empty window", PolicyRule{Action: ApprovalAsk, Hours: "07:00-07:00"}, "empty window"},
+		{"bad timezone", PolicyRule{Action: ApprovalAsk, Timezone: "Mars/Olympus"}, "invalid timezone"},
+		{"approvers need ask", PolicyRule{Action: ApprovalAllow, Approvers: []string{"admin"}}, "approvers require"},
+		{"empty tool name", PolicyRule{Action: ApprovalAllow, Tools: []string{" "}}, "empty name"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := ValidatePolicyConfig(PolicyConfig{Group: Policy{Rules: []PolicyRule{{Action: ApprovalAllow}, tt.rule}}})
+			if tt.want == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) || !strings.Contains(err.Error(), "policy group: rule #2") {
+				t.Errorf("error = %v, want rule #2 error containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegistryExecute_DeniedByRuleIsAudited(t *testing.T) {
+	t.Parallel()
+
+	r := NewRegistry()
+	if err := r.Register(registryTestTool{name: "write_file", scopes: []Scope{ScopeReadWrite}}); err != nil {
+		t.Fatalf("register error: %v", err)
+	}
+	var events []security.AuditEvent
+	r.SetAuditLogger(security.NewAuditLogger(security.AuditLoggerConfig{
+		OnEvent: func(e security.AuditEvent) { events = append(events, e) },
+	}))
+
+	cfg := PolicyConfig{DM: Policy{Default: ApprovalAllow, Rules: []PolicyRule{
+		{Tools: []string{"read_file"}, Action: ApprovalAllow},
+		{Tools: []string{"exec"}, Action: ApprovalDeny},
+		{Tools: []string{"write_file"}, ArgsNot: map[string]string{"path": `^docs/`}, Action: ApprovalDeny, Reason: "outside docs"},
+	}}}
+	_, err := r.Execute(context.Background(), "write_file", json.RawMessage(`{"path":"main.go"}`),
+		cfg, PolicyContextDM, nil, nil, time.Second, ExecutionEnv{})
+	if !errors.Is(err, ErrDenied) || !strings.Contains(err.Error(), "rule #3") {
+		t.Fatalf("err = %v, want denial by rule #3", err)
+	}
+
+	var found bool
+	for _, e := range events {
+		if e.Type == security.EventApproval && e.Detail == "denied by rule #3 (dm): outside docs" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("no approval audit event explaining the rule: %+v", events)
+	}
+}
+
+func TestRegistryExecute_RuleApproversNotElevated(t *testing.T) {
+	t.Parallel()
+
+	r := NewRegistry()
+	if err := r.Register(registryTestTool{name: "exec", scopes: []Scope{ScopeExec}}); err != nil {
+		t.Fatalf("register error: %v", err)
+	}
+	elevated := NewElevatedState()
+	elevated.Elevate(time.Minute)
+	requester := &stubRequester{resp: ApprovalResponse{Approved: true}}
+
+	cfg := PolicyConfig{Group: Policy{Rules: []PolicyRule{
+		{Tools: []string{"exec"}, Action: ApprovalAsk, Approvers: []string{"admin"}},
+	}}}
+	if _, err := r.Execute(context.Background(), "exec", json.RawMessage(`{}`),
+		cfg, PolicyContextGroup, elevated, requester, time.Second, ExecutionEnv{}); err != nil {
+		t.Fatalf("execute error: %v", err)
+	}
+	if requester.calls != 1 || len(requester.last.Approvers) != 1 || requester.last.Approvers[0] != "admin" {
+		t.Errorf("requester calls = %d, last = %+v; want one prompt restricted to admin", requester.calls, requester.last)
+	}
+}
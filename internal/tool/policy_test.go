@@ -33,7 +33,7 @@ func TestResolvePolicy_ExplicitToolMapping(t *testing.T) {
 	}
 	tool := stubTool{name: "read_file", defaultPolicy: ApprovalDeny}
 
-	got := ResolvePolicy(cfg, PolicyContextDM, tool)
+	got := ResolvePolicy(cfg, PolicyContextDM, tool, PolicyCall{}).Level
 	if got != ApprovalAllow {
 		t.Errorf("explicit mapping: got %q, want %q", got, ApprovalAllow)
 	}
@@ -50,7 +50,7 @@ func TestResolvePolicy_ExplicitToolMapping_TrimmedToolName(t *testing.T) {
 	}
 	tool := stubTool{name: " read_file ", defaultPolicy: ApprovalAllow}
 
-	got := ResolvePolicy(cfg, PolicyContextDM, tool)
+	got := ResolvePolicy(cfg, PolicyContextDM, tool, PolicyCall{}).Level
 	if got != ApprovalDeny {
 		t.Errorf("trimmed mapping: got %q, want %q", got, ApprovalDeny)
 	}
@@ -67,7 +67,7 @@ func TestResolvePolicy_ContextDefault(t *testing.T) {
 	}
 	tool := stubTool{name: "exec_cmd", defaultPolicy: ApprovalAllow}
 
-	got := ResolvePolicy(cfg, PolicyContextGroup, tool)
+	got := ResolvePolicy(cfg, PolicyContextGroup, tool, PolicyCall{}).Level
 	if got != ApprovalDeny {
 		t.Errorf("context default: got %q, want %q", got, ApprovalDeny)
 	}
@@ -84,7 +84,7 @@ func TestResolvePolicy_ExplicitListMapping(t *testing.T) {
 	}
 	tool := stubTool{name: "exec_cmd", defaultPolicy: ApprovalAllow}
 
-	got := ResolvePolicy(cfg, PolicyContextDM, tool)
+	got := ResolvePolicy(cfg, PolicyContextDM, tool, PolicyCall{}).Level
 	if got != ApprovalDeny {
 		t.Errorf("explicit list mapping: got %q, want %q", got, ApprovalDeny)
 	}
@@ -98,7 +98,7 @@ func TestResolvePolicy_ToolDefault(t *testing.T) {
 	}
 	tool := stubTool{name: "search", defaultPolicy: ApprovalAsk}
 
-	got := ResolvePolicy(cfg, PolicyContextDM, tool)
+	got := ResolvePolicy(cfg, PolicyContextDM, tool, PolicyCall{}).Level
 	if got != ApprovalAsk {
 		t.Errorf("tool default: got %q, want %q", got, ApprovalAsk)
 	}
@@ -113,7 +113,7 @@ func TestResolvePolicy_UnknownContext(t *testing.T) {
 	}
 	tool := stubTool{name: "test", defaultPolicy: ApprovalAllow}
 
-	got := ResolvePolicy(cfg, PolicyContext("unknown"), tool)
+	got := ResolvePolicy(cfg, PolicyContext("unknown"), tool, PolicyCall{}).Level
 	if got != ApprovalAllow {
 		t.Errorf("unknown context: got %q, want %q", got, ApprovalAllow)
 	}
@@ -165,7 +165,7 @@ func TestResolvePolicy_ResolutionOrder(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
-			got := ResolvePolicy(tt.cfg, tt.ctx, tt.tool)
+			got := ResolvePolicy(tt.cfg, tt.ctx, tt.tool, PolicyCall{}).Level
 			if got != tt.want {
 				t.Errorf("got %q, want %q", got, tt.want)
 			}
@@ -182,8 +182,8 @@ func TestResolvePolicy_DMvsGroup(t *testing.T) {
 	}
 	tool := stubTool{name: "exec", defaultPolicy: ApprovalAsk}
 
-	dmLevel := ResolvePolicy(cfg, PolicyContextDM, tool)
-	groupLevel := ResolvePolicy(cfg, PolicyContextGroup, tool)
+	dmLevel := ResolvePolicy(cfg, PolicyContextDM, tool, PolicyCall{}).Level
+	groupLevel := ResolvePolicy(cfg, PolicyContextGroup, tool, PolicyCall{}).Level
 
 	if dmLevel != ApprovalAllow {
 		t.Errorf("DM: got %q, want %q", dmLevel, ApprovalAllow)
@@ -29,6 +29,9 @@ type Registry struct {
 	tools       map[string]Tool
 	auditLogger *security.AuditLogger
 	rateLimiter *security.RateLimiter
+
+	sandboxPolicy security.SandboxPolicy
+	sandbox       security.Sandbox
 }
 
 // NewRegistry creates an empty tool registry.
@@ -52,6 +55,16 @@ func (r *Registry) SetRateLimiter(limiter *security.RateLimiter) {
 	r.rateLimiter = limiter
 }
 
+// SetSandbox configures which tools run their subprocesses in a sandbox.
+// When the policy covers one of a tool's scopes and sb is nil, the tool is
+// refused rather than run on the host.
+func (r *Registry) SetSandbox(policy security.SandboxPolicy, sb security.Sandbox) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sandboxPolicy = policy
+	r.sandbox = sb
+}
+
 // Register adds a tool to the registry.
 // It returns ErrNoScopes if the tool declares no scopes,
 // and ErrDuplicateTool if a tool with the same name is already registered.
@@ -126,16 +139,18 @@ func (r *Registry) ToolDefinitions() []provider.ToolDefinition {
 }
 
 // Clone returns a shallow copy of the registry with the same tools, audit logger,
-// and rate limiter. The returned registry is independent — registering new tools
+// rate limiter and sandbox. The returned registry is independent — registering new tools
 // on the clone does not affect the original.
 func (r *Registry) Clone() *Registry {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
 	cloned := &Registry{
-		tools:       make(map[string]Tool, len(r.tools)),
-		auditLogger: r.auditLogger,
-		rateLimiter: r.rateLimiter,
+		tools:         make(map[string]Tool, len(r.tools)),
+		auditLogger:   r.auditLogger,
+		rateLimiter:   r.rateLimiter,
+		sandboxPolicy: r.sandboxPolicy,
+		sandbox:       r.sandbox,
 	}
 	for name, t := range r.tools {
 		cloned.tools[name] = t
@@ -156,8 +171,8 @@ func (r *Registry) Names() []string {
 	return names
 }
 
-// Execute orchestrates tool execution: lookup → rate limit → policy resolution →
-// elevated adjustment → deny/allow/ask flow → audit.
+// Execute orchestrates tool execution: lookup → rate limit → sandbox → policy
+// resolution → elevated adjustment → deny/allow/ask flow → audit.
 func (r *Registry) Execute(
 	ctx context.Context,
 	name string,
@@ -179,6 +194,8 @@ func (r *Registry) Execute(
 	r.mu.RLock()
 	rl := r.rateLimiter
 	al := r.auditLogger
+	sandboxPolicy := r.sandboxPolicy
+	sb := r.sandbox
 	r.mu.RUnlock()
 
 	if rl != nil {
@@ -194,6 +211,19 @@ func (r *Registry) Execute(
 		}
 	}
 
+	if env.AuditLogger == nil {
+		env.AuditLogger = al
+	}
+
+	// Select the sandbox before anything runs: a tool the policy requires to
+	// be sandboxed never runs on the host.
+	if sandboxPolicy.ShouldSandbox(scopeStrings(t.Scopes())) {
+		if sb == nil {
+			return Output{}, fmt.Errorf("tool %s: %w", name, security.ErrSandboxUnavailable)
+		}
+		env.Sandbox = sb
+	}
+
 	// Emit tool_call audit event before execution.
 	// Truncate args to prevent audit log bloat from large payloads.
 	if al != nil {
@@ -205,21 +235,48 @@ func (r *Registry) Execute(
 	}
 
 	// Resolve the effective policy.
-	level := ResolvePolicy(policyCfg, policyCtx, t)
+	decision := ResolvePolicy(policyCfg, policyCtx, t, PolicyCall{Args: args, SenderID: env.SenderID})
+	level := decision.Level
+	explain := decision.Explain()
 
-	// Apply elevated state if provided.
-	if elevated != nil {
+	// Apply elevated state if provided. Rules naming approvers are not
+	// bypassed: elevation is granted by the session, not by an approver.
+	if elevated != nil && len(decision.Approvers) == 0 {
 		level = elevated.Apply(level)
 	}
 
+	// The tool owner's floor outranks both the policy and elevation.
+	if f, ok := t.(PolicyFloor); ok {
+		if floor := stricterLevel(level, f.PolicyFloor()); floor != level {
+			level, explain = floor, "tool policy"
+		}
+	}
+
 	var output Output
 
 	switch level {
 	case ApprovalDeny:
-		return Output{}, fmt.Errorf("%w: %s", ErrDenied, name)
+		if explain == "" {
+			return Output{}, fmt.Errorf("%w: %s", ErrDenied, name)
+		}
+		if al != nil {
+			al.Log(security.AuditEvent{
+				Type:     security.EventApproval,
+				ToolName: name,
+				Detail:   "denied by " + explain,
+			})
+		}
+		return Output{}, fmt.Errorf("%w: %s (%s)", ErrDenied, name, explain)
 
 	case ApprovalAllow:
-		output, err = t.Execute(ctx, args, env)
+		if explain != "" && al != nil {
+			al.Log(security.AuditEvent{
+				Type:     security.EventApproval,
+				ToolName: name,
+				Detail:   "allowed by " + explain,
+			})
+		}
+		output, err = executeTracked(ctx, t, args, env)
 
 	case ApprovalAsk:
 		if requester == nil {
@@ -233,6 +290,8 @@ func (r *Registry) Execute(
 			Description: t.Description(),
 			Arguments:   args,
 			Context:     policyCtx,
+			Timeout:     timeout,
+			Approvers:   decision.Approvers,
 		}, timeout)
 		if reqErr != nil {
 			return Output{}, reqErr
@@ -244,20 +303,26 @@ func (r *Registry) Execute(
 					Type:     security.EventApproval,
 					ToolName: name,
 					Detail:   "denied: " + resp.Reason,
+					Metadata: policyRuleMetadata(explain),
 				})
 			}
 			return Output{}, fmt.Errorf("%w: %s (user denied: %s)", ErrDenied, name, resp.Reason)
 		}
 
 		if al != nil {
+			detail := "approved"
+			if resp.Reason != "" {
+				detail += ": " + resp.Reason
+			}
 			al.Log(security.AuditEvent{
 				Type:     security.EventApproval,
 				ToolName: name,
-				Detail:   "approved",
+				Detail:   detail,
+				Metadata: policyRuleMetadata(explain),
 			})
 		}
 
-		output, err = t.Execute(ctx, args, env)
+		output, err = executeTracked(ctx, t, args, env)
 
 	default:
 		return Output{}, fmt.Errorf("%w: %s (unknown policy level: %s)", ErrDenied, name, level)
@@ -283,6 +348,47 @@ func (r *Registry) Execute(
 	return output, err
 }
 
+// executeTracked runs t, recording the workspace files it changes when
+// snapshots are enabled and the tool can modify files.
+func executeTracked(ctx context.Context, t Tool, args json.RawMessage, env ExecutionEnv) (Output, error) {
+	if env.Snapshots == nil || !modifiesFiles(t) {
+		return t.Execute(ctx, args, env)
+	}
+	var output Output
+	var err error
+	env.Snapshots.Track(t.Name(), func() {
+		output, err = t.Execute(ctx, args, env)
+	})
+	return output, err
+}
+
+// modifiesFiles reports whether t declares a scope that lets it change files.
+func modifiesFiles(t Tool) bool {
+	for _, s := range t.Scopes() {
+		if s == ScopeReadWrite || s == ScopeExec {
+			return true
+		}
+	}
+	return false
+}
+
+// scopeStrings converts scopes for the security package.
+func scopeStrings(scopes []Scope) []string {
+	out := make([]string, len(scopes))
+	for i, s := range scopes {
+		out[i] = string(s)
+	}
+	return out
+}
+
+// policyRuleMetadata records the policy rule that required an approval.
+func policyRuleMetadata(explain string) map[string]string {
+	if explain == "" {
+		return nil
+	}
+	return map[string]string{"policy_rule": explain}
+}
+
 // maxAuditDetailLen is the maximum length of audit detail strings.
 // Longer values are truncated to prevent log bloat from large tool outputs.
 const maxAuditDetailLen = 4096
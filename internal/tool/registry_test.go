@@ -4,10 +4,15 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 	"time"
 	"unicode/utf8"
+
+	"github.com/flemzord/sclaw/internal/snapshot"
 )
 
 type registryTestTool struct {
@@ -523,3 +528,145 @@ func TestRegistryClone_EmptyRegistry(t *testing.T) {
 		t.Fatal("cloned empty registry should have 0 tools")
 	}
 }
+
+// fileWritingTool writes "changed" to the file named by its arguments.
+type fileWritingTool struct {
+	scope Scope
+}
+
+func (t fileWritingTool) Name() string                 { return "writer_" + string(t.scope) }
+func (t fileWritingTool) Description() string          { return "writes a file" }
+func (t fileWritingTool) Schema() json.RawMessage      { return json.RawMessage(`{}`) }
+func (t fileWritingTool) Scopes() []Scope              { return []Scope{t.scope} }
+func (t fileWritingTool) DefaultPolicy() ApprovalLevel { return ApprovalAllow }
+func (t fileWritingTool) Execute(_ context.Context, args json.RawMessage, env ExecutionEnv) (Output, error) {
+	var name string
+	_ = json.Unmarshal(args, &name)
+	return Output{Content: "ok"}, os.WriteFile(filepath.Join(env.Workspace, name), []byte("changed"), 0o644)
+}
+
+func TestRegistryExecute_SnapshotsMutatingTools(t *testing.T) {
+	t.Parallel()
+
+	workspace := t.TempDir()
+	store, err := snapshot.New(snapshot.Config{Workspace: workspace, Dir: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewRegistry()
+	for _, scope := range []Scope{ScopeReadWrite, ScopeExec, ScopeReadOnly} {
+		if err := r.Register(fileWritingTool{scope: scope}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	env := ExecutionEnv{Workspace: workspace, Snapshots: store.NewRecorder("chat", "hi")}
+	for _, scope := range []Scope{ScopeReadWrite, ScopeExec, ScopeReadOnly} {
+		args, _ := json.Marshal(string(scope) + ".txt")
+		if _, err := r.Execute(context.Background(), "writer_"+string(scope), args,
+			PolicyConfig{}, PolicyContextDM, nil, nil, time.Second, env); err != nil {
+			t.Fatalf("execute %s: %v", scope, err)
+		}
+	}
+
+	turns, err := store.List("chat")
+	if err != nil || len(turns) != 1 {
+		t.Fatalf("turns = %+v, err = %v", turns, err)
+	}
+	var paths []string
+	for _, c := range turns[0].Changes {
+		paths = append(paths, c.Path)
+	}
+	if want := []string{"exec.txt", "read_write.txt"}; !slices.Equal(paths, want) {
+		t.Errorf("tracked paths = %v, want %v", paths, want)
+	}
+}
+
+// floorTestTool is a registryTestTool with a PolicyFloor.
+type floorTestTool struct {
+	registryTestTool
+	floor ApprovalLevel
+}
+
+func (t floorTestTool) PolicyFloor() ApprovalLevel { return t.floor }
+
+func TestRegistryExecute_PolicyFloor(t *testing.T) {
+	t.Parallel()
+
+	approve := &fakeRequester{
+		respondFunc: func(_ context.Context, _ ApprovalRequest) (ApprovalResponse, error) {
+			return ApprovalResponse{Approved: true}, nil
+		},
+	}
+	elevated := NewElevatedState()
+	elevated.Elevate(time.Minute)
+
+	tests := []struct {
+		name      string
+		floor     ApprovalLevel
+		policy    PolicyConfig
+		elevated  *ElevatedState
+		requester ApprovalRequester
+		wantCalls int
+	}{
+		{
+			name:   "ask floor outranks allow default",
+			floor:  ApprovalAsk,
+			policy: PolicyConfig{DM: Policy{Default: ApprovalAllow}},
+		},
+		{
+			name:     "ask floor outranks elevation",
+			floor:    ApprovalAsk,
+			policy:   PolicyConfig{DM: Policy{Tools: map[string]ApprovalLevel{"remote": ApprovalAsk}}},
+			elevated: elevated,
+		},
+		{
+			name:      "deny floor outranks explicit allow",
+			floor:     ApprovalDeny,
+			policy:    PolicyConfig{DM: Policy{Tools: map[string]ApprovalLevel{"remote": ApprovalAllow}}},
+			requester: approve,
+		},
+		{
+			name:      "ask floor approved",
+			floor:     ApprovalAsk,
+			policy:    PolicyConfig{DM: Policy{Default: ApprovalAllow}},
+			requester: approve,
+			wantCalls: 1,
+		},
+		{
+			name:      "allow floor keeps stricter policy",
+			floor:     ApprovalAllow,
+			policy:    PolicyConfig{DM: Policy{Tools: map[string]ApprovalLevel{"remote": ApprovalAsk}}},
+			requester: approve,
+			wantCalls: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := NewRegistry()
+			calls := 0
+			if err := r.Register(floorTestTool{
+				registryTestTool: registryTestTool{name: "remote", scopes: []Scope{ScopeNetwork}, executeCalls: &calls},
+				floor:            tt.floor,
+			}); err != nil {
+				t.Fatalf("register error: %v", err)
+			}
+
+			_, err := r.Execute(context.Background(), "remote", nil, tt.policy, PolicyContextDM,
+				tt.elevated, tt.requester, time.Second, ExecutionEnv{})
+			if tt.wantCalls == 0 && !errors.Is(err, ErrDenied) {
+				t.Fatalf("expected ErrDenied, got %v", err)
+			}
+			if tt.wantCalls > 0 && err != nil {
+				t.Fatalf("execute error: %v", err)
+			}
+			if calls != tt.wantCalls {
+				t.Fatalf("execute calls = %d, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
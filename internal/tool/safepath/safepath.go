@@ -138,6 +138,22 @@ func ViaFilter(path string, check func(string) error) (string, error) {
 	return resolved, nil
 }
 
+// ForModify resolves an existing path for modification: the workspace
+// first, then the PathFilter (RW only). Unlike ForWrite, it never creates
+// directories.
+func ForModify(workspace, path string, filter *security.PathFilter) (string, error) {
+	resolved, err := Resolve(workspace, path)
+	if err == nil {
+		return resolved, nil
+	}
+
+	if filter != nil {
+		return ViaFilter(path, filter.CheckWrite)
+	}
+
+	return "", fmt.Errorf("%w: %s", ErrPathTraversal, path)
+}
+
 // ForWriteWorkspace is like Resolve but creates parent directories
 // if needed. It resolves the parent best-effort and verifies workspace containment.
 func ForWriteWorkspace(workspace, path string) (string, error) {
@@ -298,3 +298,48 @@ func TestForWrite(t *testing.T) {
 		}
 	})
 }
+
+func TestForModify(t *testing.T) {
+	t.Parallel()
+
+	workspace := t.TempDir()
+	allowedRO := t.TempDir()
+	allowedRW := t.TempDir()
+
+	filter := security.NewPathFilter(security.PathFilterConfig{
+		AllowedDirs: []security.AllowedDir{
+			{Path: allowedRO, Mode: security.PathAccessRO},
+			{Path: allowedRW, Mode: security.PathAccessRW},
+		},
+	})
+
+	t.Run("workspace path", func(t *testing.T) {
+		t.Parallel()
+		if _, err := ForModify(workspace, "notes.md", filter); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("does not create directories", func(t *testing.T) {
+		t.Parallel()
+		_, _ = ForModify(workspace, "missing/dir/file.txt", filter)
+		if _, err := os.Stat(filepath.Join(workspace, "missing")); !os.IsNotExist(err) {
+			t.Errorf("ForModify created a directory: %v", err)
+		}
+	})
+
+	t.Run("RW dir allowed", func(t *testing.T) {
+		t.Parallel()
+		if _, err := ForModify(workspace, filepath.Join(allowedRW, "out.txt"), filter); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("RO dir rejected", func(t *testing.T) {
+		t.Parallel()
+		_, err := ForModify(workspace, filepath.Join(allowedRO, "out.txt"), filter)
+		if !errors.Is(err, ErrPathTraversal) {
+			t.Errorf("expected ErrPathTraversal, got %v", err)
+		}
+	})
+}
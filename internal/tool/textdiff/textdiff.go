@@ -0,0 +1,203 @@
+// Package textdiff renders line-based unified diffs, used by tools to show
+// the agent what a file change did.
+package textdiff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// contextLines is the number of unchanged lines shown around each change.
+const contextLines = 3
+
+// maxLCSCells bounds the LCS table. Larger changed regions are shown as a
+// single removal followed by a single addition.
+const maxLCSCells = 1 << 22
+
+// op is one line of an edit script: ' ' (kept), '-' (removed) or '+' (added).
+type op struct {
+	kind byte
+	line string
+}
+
+// Unified returns a unified diff turning oldText into newText, labelled
+// with oldName and newName. It returns "" when the texts are equal.
+func Unified(oldName, newName, oldText, newText string) string {
+	if oldText == newText {
+		return ""
+	}
+	ops := diffLines(splitLines(oldText), splitLines(newText))
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", oldName, newName)
+	for _, h := range hunks(ops) {
+		writeHunk(&sb, ops, h)
+	}
+	return sb.String()
+}
+
+// Stats returns the number of added and removed lines between two texts.
+func Stats(oldText, newText string) (added, removed int) {
+	for _, o := range diffLines(splitLines(oldText), splitLines(newText)) {
+		switch o.kind {
+		case '+':
+			added++
+		case '-':
+			removed++
+		}
+	}
+	return added, removed
+}
+
+// splitLines splits text into lines, keeping line terminators.
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// diffLines computes an edit script from a to b. Common leading and
+// trailing lines are matched first; the rest uses a longest common
+// subsequence when small enough.
+func diffLines(a, b []string) []op {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	ops := make([]op, 0, len(a)+len(b))
+	for _, l := range a[:prefix] {
+		ops = append(ops, op{' ', l})
+	}
+	ops = append(ops, diffMiddle(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, l := range a[len(a)-suffix:] {
+		ops = append(ops, op{' ', l})
+	}
+	return ops
+}
+
+func diffMiddle(a, b []string) []op {
+	ops := make([]op, 0, len(a)+len(b))
+	if len(a)*len(b) > maxLCSCells || len(a) == 0 || len(b) == 0 {
+		for _, l := range a {
+			ops = append(ops, op{'-', l})
+		}
+		for _, l := range b {
+			ops = append(ops, op{'+', l})
+		}
+		return ops
+	}
+
+	// lcs[i][j] is the LCS length of a[i:] and b[j:].
+	lcs := make([][]int32, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int32, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, op{' ', a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, op{'-', a[i]})
+			i++
+		default:
+			ops = append(ops, op{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		ops = append(ops, op{'-', a[i]})
+	}
+	for ; j < len(b); j++ {
+		ops = append(ops, op{'+', b[j]})
+	}
+	return ops
+}
+
+// hunk is a range of ops [start, end).
+type hunk struct{ start, end int }
+
+// hunks groups changes with their context, merging groups whose context
+// overlaps.
+func hunks(ops []op) []hunk {
+	var out []hunk
+	for i, o := range ops {
+		if o.kind == ' ' {
+			continue
+		}
+		start := max(0, i-contextLines)
+		end := min(len(ops), i+1+contextLines)
+		if n := len(out); n > 0 && start <= out[n-1].end {
+			out[n-1].end = end
+			continue
+		}
+		out = append(out, hunk{start, end})
+	}
+	return out
+}
+
+func writeHunk(sb *strings.Builder, ops []op, h hunk) {
+	// Line numbers are 1-based; the start of the hunk in each file is the
+	// number of lines of that file before it.
+	oldStart, newStart := 0, 0
+	for _, o := range ops[:h.start] {
+		if o.kind != '+' {
+			oldStart++
+		}
+		if o.kind != '-' {
+			newStart++
+		}
+	}
+	oldLen, newLen := 0, 0
+	for _, o := range ops[h.start:h.end] {
+		if o.kind != '+' {
+			oldLen++
+		}
+		if o.kind != '-' {
+			newLen++
+		}
+	}
+	fmt.Fprintf(sb, "@@ -%s +%s @@\n", hunkRange(oldStart, oldLen), hunkRange(newStart, newLen))
+	for _, o := range ops[h.start:h.end] {
+		sb.WriteByte(o.kind)
+		sb.WriteString(o.line)
+		if !strings.HasSuffix(o.line, "\n") {
+			sb.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+}
+
+// hunkRange formats a hunk range as GNU diff does: an empty range is
+// reported at the line before it.
+func hunkRange(start, length int) string {
+	switch length {
+	case 0:
+		return fmt.Sprintf("%d,0", start)
+	case 1:
+		return fmt.Sprintf("%d", start+1)
+	default:
+		return fmt.Sprintf("%d,%d", start+1, length)
+	}
+}
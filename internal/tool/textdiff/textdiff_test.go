@@ -0,0 +1,62 @@
+package textdiff
+
+import "testing"
+
+func TestUnified(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		old, new string
+		want     string
+	}{
+		{name: "equal", old: "a\n", new: "a\n", want: ""},
+		{
+			name: "single change with context",
+			old:  "1\n2\n3\n4\n5\n6\n7\n8\n9\n",
+			new:  "1\n2\n3\n4\nfive\n6\n7\n8\n9\n",
+			want: "--- a/f\n+++ b/f\n@@ -2,7 +2,7 @@\n 2\n 3\n 4\n-5\n+five\n 6\n 7\n 8\n",
+		},
+		{
+			name: "separate hunks",
+			old:  "a\n1\n2\n3\n4\n5\n6\n7\nb\n",
+			new:  "A\n1\n2\n3\n4\n5\n6\n7\nB\n",
+			want: "--- a/f\n+++ b/f\n@@ -1,4 +1,4 @@\n-a\n+A\n 1\n 2\n 3\n@@ -6,4 +6,4 @@\n 5\n 6\n 7\n-b\n+B\n",
+		},
+		{
+			name: "insertion into empty file",
+			old:  "",
+			new:  "x\ny\n",
+			want: "--- a/f\n+++ b/f\n@@ -0,0 +1,2 @@\n+x\n+y\n",
+		},
+		{
+			name: "deletion",
+			old:  "x\ny\nz\n",
+			new:  "x\nz\n",
+			want: "--- a/f\n+++ b/f\n@@ -1,3 +1,2 @@\n x\n-y\n z\n",
+		},
+		{
+			name: "missing final newline",
+			old:  "x\ny",
+			new:  "x\ny\n",
+			want: "--- a/f\n+++ b/f\n@@ -1,2 +1,2 @@\n x\n-y\n\\ No newline at end of file\n+y\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := Unified("a/f", "b/f", tt.old, tt.new); got != tt.want {
+				t.Errorf("Unified() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStats(t *testing.T) {
+	t.Parallel()
+
+	added, removed := Stats("a\nb\nc\n", "a\nB\nc\nd\n")
+	if added != 2 || removed != 1 {
+		t.Errorf("Stats() = +%d -%d, want +2 -1", added, removed)
+	}
+}
@@ -8,6 +8,8 @@ import (
 	"encoding/json"
 
 	"github.com/flemzord/sclaw/internal/security"
+	"github.com/flemzord/sclaw/internal/snapshot"
+	"github.com/flemzord/sclaw/pkg/message"
 )
 
 // Scope declares what kind of access a tool requires.
@@ -68,6 +70,66 @@ type ExecutionEnv struct {
 
 	// SessionID identifies the session for per-session rate limiting.
 	SessionID string
+
+	// SenderID identifies the user whose message triggered the call.
+	// Policy rules may match on it.
+	SenderID string
+
+	// Sandbox, if non-nil, must be used to start subprocesses (see
+	// ShellCommand). The registry sets it when the sandbox policy covers
+	// one of the tool's scopes.
+	Sandbox security.Sandbox
+
+	// AuditLogger, if non-nil, receives the file changes tools make (see
+	// RecordFileChange). The registry sets it from its own audit logger.
+	AuditLogger *security.AuditLogger
+
+	// Snapshots, if non-nil, records the workspace files changed during the
+	// current turn so they can be reverted. The registry tracks every call
+	// to a tool with the read_write or exec scope.
+	Snapshots *snapshot.Recorder
+
+	// Notifier, if non-nil, delivers messages to the chat the call came
+	// from. Tools use it to report work that outlives the call, such as a
+	// background job exiting.
+	Notifier Notifier
+
+	// Origin is the chat the call came from. It is zero for calls made
+	// outside a chat, such as by crons or MCP clients.
+	Origin Origin
+
+	// Timezone is the user's IANA time zone, for tools dealing with
+	// wall-clock times. Empty means the server's local zone.
+	Timezone string
+}
+
+// Origin identifies a chat.
+type Origin struct {
+	Channel  string // channel module ID, e.g. "channel.telegram"
+	ChatID   string
+	ThreadID string
+	ChatType message.ChatType
+}
+
+// Notifier sends a text message to a chat outside the normal reply flow.
+type Notifier interface {
+	Notify(ctx context.Context, text string) error
+}
+
+// RecordFileChange logs a file mutation made by a tool. action describes it
+// ("write", "edit", "delete"); path is the resolved path.
+func (env ExecutionEnv) RecordFileChange(toolName, action, path, detail string) {
+	if env.AuditLogger == nil {
+		return
+	}
+	env.AuditLogger.Log(security.AuditEvent{
+		Type:      security.EventFileChange,
+		SessionID: env.SessionID,
+		SenderID:  env.SenderID,
+		ToolName:  toolName,
+		Detail:    detail,
+		Metadata:  map[string]string{"action": action, "path": path},
+	})
 }
 
 // Output is the result of a tool execution.
@@ -77,4 +139,8 @@ type Output struct {
 
 	// IsError indicates whether the output represents an error condition.
 	IsError bool
+
+	// Blocks are media the tool produced for the user, such as a generated
+	// chart. They are delivered with the reply; the LLM only sees Content.
+	Blocks []message.ContentBlock
 }
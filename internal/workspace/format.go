@@ -38,6 +38,10 @@ func FormatSkillsForPrompt(skills []Skill) string {
 			b.WriteString("\n    <location>")
 			b.WriteString(skill.Path)
 			b.WriteString("</location>")
+		} else if skill.Tool != "" {
+			b.WriteString("\n    <tool>")
+			b.WriteString(skill.Tool)
+			b.WriteString("</tool>")
 		}
 		b.WriteString("\n  </skill>")
 	}
@@ -46,7 +50,8 @@ func FormatSkillsForPrompt(skills []Skill) string {
 	b.WriteString("The skill catalog above lists all your available skills. ")
 	b.WriteString("You can answer questions about which skills you have by reading this catalog directly. ")
 	b.WriteString("For skills with a <content> tag, the full skill is already available inline. ")
-	b.WriteString("For skills with a <location> tag, load the full content using the read_file tool with the listed path.")
+	b.WriteString("For skills with a <location> tag, load the full content using the read_file tool with the listed path. ")
+	b.WriteString("For skills with a <tool> tag, get the content by calling that tool with the skill name and its arguments.")
 
 	return b.String()
 }
@@ -221,6 +221,29 @@ func TestFormatSkillsForPrompt_MixedBuiltinAndFilesystem(t *testing.T) {
 	}
 }
 
+func TestFormatSkillsForPrompt_ToolSkill(t *testing.T) {
+	t.Parallel()
+
+	skills := []Skill{
+		{
+			Meta: SkillMeta{Name: "review", Description: "Review code."},
+			Tool: "mcp_github_get_prompt",
+		},
+	}
+
+	result := FormatSkillsForPrompt(skills)
+
+	if !strings.Contains(result, "<tool>mcp_github_get_prompt</tool>") {
+		t.Error("missing <tool> for tool skill")
+	}
+	if strings.Contains(result, "    <location>") || strings.Contains(result, "    <content>") {
+		t.Error("tool skill should have neither <location> nor <content>")
+	}
+	if !strings.Contains(result, "skills with a <tool> tag") {
+		t.Error("missing instruction text for tool skills")
+	}
+}
+
 func TestPruneSkillsToFit_ZeroBudgetNoAlways(t *testing.T) {
 	t.Parallel()
 
@@ -49,6 +49,7 @@ type Skill struct {
 	Meta SkillMeta
 	Body string // markdown content after frontmatter
 	Path string // source file path (for diagnostics)
+	Tool string // tool that renders the skill, for skills without a file (e.g. MCP prompts)
 }
 
 // ParseSkill parses a SKILL.md file content into a Skill.
@@ -0,0 +1,302 @@
+package telegram
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/flemzord/sclaw/internal/channel"
+	"github.com/flemzord/sclaw/pkg/message"
+)
+
+const (
+	// callbackPrefix marks callback data produced by approval buttons.
+	callbackPrefix = "apv"
+
+	// maxPromptAge bounds how long an unanswered prompt is tracked, so that
+	// prompts never closed (e.g. after a crash of the requester) are purged.
+	maxPromptAge = 24 * time.Hour
+
+	// maxPatternLabel caps the pattern shown on the "always allow" button.
+	maxPatternLabel = 32
+)
+
+// Approval button choices, encoded in callback data (limited to 64 bytes).
+const (
+	choiceOnce    = "o"
+	choiceSession = "s"
+	choiceAlways  = "a"
+	choiceDeny    = "d"
+)
+
+// approvalPrompt is a prompt message waiting for a button press.
+type approvalPrompt struct {
+	approvalID string
+	chatID     int64
+	messageID  int
+	text       string
+	approvers  []string
+	created    time.Time
+}
+
+// approvalStore maps the short tokens carried by approval buttons to the
+// prompts they belong to. The zero value is ready to use.
+type approvalStore struct {
+	mu      sync.Mutex
+	prompts map[string]*approvalPrompt // token → prompt
+}
+
+// add stores p under a new random token and returns the token.
+func (s *approvalStore) add(p *approvalPrompt) (string, error) {
+	var b [6]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("telegram: generate approval token: %w", err)
+	}
+	token := hex.EncodeToString(b[:])
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.prompts == nil {
+		s.prompts = make(map[string]*approvalPrompt)
+	}
+	for tok, old := range s.prompts {
+		if time.Since(old.created) > maxPromptAge {
+			delete(s.prompts, tok)
+		}
+	}
+	s.prompts[token] = p
+	return token, nil
+}
+
+// get returns a copy of the prompt for token.
+func (s *approvalStore) get(token string) (approvalPrompt, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.prompts[token]
+	if !ok {
+		return approvalPrompt{}, false
+	}
+	return *p, true
+}
+
+// take removes and returns the prompt for an approval ID.
+func (s *approvalStore) take(approvalID string) (approvalPrompt, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for tok, p := range s.prompts {
+		if p.approvalID == approvalID {
+			delete(s.prompts, tok)
+			return *p, true
+		}
+	}
+	return approvalPrompt{}, false
+}
+
+// setMessageID records the message a prompt was sent as.
+func (s *approvalStore) setMessageID(token string, messageID int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if p, ok := s.prompts[token]; ok {
+		p.messageID = messageID
+	}
+}
+
+// SendApprovalPrompt implements channel.ApprovalChannel. The prompt is sent
+// as a message with one inline button per available choice.
+func (t *Telegram) SendApprovalPrompt(ctx context.Context, msg message.OutboundMessage, prompt channel.ApprovalPrompt) error {
+	chatID, err := strconv.ParseInt(msg.Chat.ID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("telegram: invalid chat ID %q: %w", msg.Chat.ID, err)
+	}
+
+	p := &approvalPrompt{
+		approvalID: prompt.ID,
+		chatID:     chatID,
+		text:       approvalPromptText(prompt),
+		approvers:  prompt.Approvers,
+		created:    time.Now(),
+	}
+	token, err := t.approvals.add(p)
+	if err != nil {
+		return err
+	}
+
+	sent, err := t.client.SendMessage(ctx, SendMessageRequest{
+		ChatID:           chatID,
+		Text:             p.text,
+		MessageThreadID:  parseOptionalInt(msg.ThreadID, t.logger),
+		ReplyToMessageID: parseOptionalInt(msg.ReplyToID, t.logger),
+		ReplyMarkup:      approvalKeyboard(token, prompt),
+	})
+	if err != nil {
+		t.approvals.take(prompt.ID)
+		return err
+	}
+
+	t.approvals.setMessageID(token, sent.MessageID)
+	return nil
+}
+
+// CloseApprovalPrompt implements channel.ApprovalChannel. It replaces the
+// buttons with the outcome so the prompt cannot be answered twice.
+func (t *Telegram) CloseApprovalPrompt(ctx context.Context, id, outcome string) error {
+	p, ok := t.approvals.take(id)
+	if !ok || p.messageID == 0 {
+		return nil
+	}
+	// Omitting reply_markup removes the inline keyboard.
+	_, err := t.client.EditMessageText(ctx, EditMessageTextRequest{
+		ChatID:    p.chatID,
+		MessageID: p.messageID,
+		Text:      p.text + "\n\n" + outcome,
+	})
+	return err
+}
+
+// approvalPromptText renders the body of an approval prompt message.
+func approvalPromptText(p channel.ApprovalPrompt) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "🔐 Approval required: %s", p.ToolName)
+	if p.Description != "" {
+		fmt.Fprintf(&sb, "\n%s", p.Description)
+	}
+	if p.Arguments != "" {
+		fmt.Fprintf(&sb, "\n\n%s", p.Arguments)
+	}
+	if len(p.Approvers) > 0 {
+		fmt.Fprintf(&sb, "\n\nOnly %s can answer.", strings.Join(p.Approvers, ", "))
+	}
+	if p.Timeout > 0 {
+		fmt.Fprintf(&sb, "\n\nDenied automatically in %s.", p.Timeout)
+	}
+	return sb.String()
+}
+
+// approvalKeyboard builds the inline keyboard for a prompt.
+func approvalKeyboard(token string, p channel.ApprovalPrompt) *InlineKeyboardMarkup {
+	data := func(choice string) string {
+		return callbackPrefix + ":" + token + ":" + choice
+	}
+	rows := [][]InlineKeyboardButton{{
+		{Text: "✅ Allow once", CallbackData: data(choiceOnce)},
+		{Text: "❌ Deny", CallbackData: data(choiceDeny)},
+	}}
+	if p.OfferSession {
+		rows = append(rows, []InlineKeyboardButton{
+			{Text: "Allow for this session", CallbackData: data(choiceSession)},
+		})
+	}
+	if p.Pattern != "" {
+		label := p.Pattern
+		if len(label) > maxPatternLabel {
+			label = truncateUTF8(label, maxPatternLabel) + "…"
+		}
+		rows = append(rows, []InlineKeyboardButton{
+			{Text: fmt.Sprintf("Always allow %q", label), CallbackData: data(choiceAlways)},
+		})
+	}
+	return &InlineKeyboardMarkup{InlineKeyboard: rows}
+}
+
+// errNotApprovalCallback is returned for callback queries that do not come
+// from an approval button.
+var errNotApprovalCallback = errors.New("telegram: not an approval callback")
+
+// convertCallback turns an approval button press into an InboundMessage
+// whose Raw payload is a channel.ApprovalAnswer. It also returns the short
+// notification to show to the user who pressed the button.
+func convertCallback(cq *CallbackQuery, approvals *approvalStore, channelName string) (message.InboundMessage, string, error) {
+	prefix, rest, _ := strings.Cut(cq.Data, ":")
+	token, choice, ok := strings.Cut(rest, ":")
+	if prefix != callbackPrefix || !ok || cq.Message == nil {
+		return message.InboundMessage{}, "", errNotApprovalCallback
+	}
+
+	p, found := approvals.get(token)
+	if !found || p.chatID != cq.Message.Chat.ID || p.messageID != cq.Message.MessageID {
+		return message.InboundMessage{}, "This approval is no longer pending.", errNotApprovalCallback
+	}
+	if len(p.approvers) > 0 && (cq.From == nil || !slices.Contains(p.approvers, strconv.FormatInt(cq.From.ID, 10))) {
+		return message.InboundMessage{}, "Only the designated approvers can answer.", errNotApprovalCallback
+	}
+
+	answer := channel.ApprovalAnswer{ApprovalID: p.approvalID, Approved: true}
+	notice := "Allowed"
+	switch choice {
+	case choiceOnce:
+	case choiceSession:
+		answer.Scope = "session"
+		notice = "Allowed for this session"
+	case choiceAlways:
+		answer.Scope = "always"
+		notice = "Always allowed"
+	case choiceDeny:
+		answer.Approved = false
+		answer.Reason = "denied by user"
+		notice = "Denied"
+	default:
+		return message.InboundMessage{}, "", errNotApprovalCallback
+	}
+
+	raw, err := json.Marshal(answer)
+	if err != nil {
+		return message.InboundMessage{}, "", fmt.Errorf("telegram: marshal approval answer: %w", err)
+	}
+
+	inbound := message.InboundMessage{
+		ID:        cq.ID,
+		Timestamp: time.Now(),
+		Channel:   channelName,
+		Sender:    convertSender(cq.From),
+		Chat:      convertChat(cq.Message.Chat),
+		Raw:       raw,
+	}
+	if cq.Message.MessageThreadID != 0 {
+		inbound.ThreadID = strconv.Itoa(cq.Message.MessageThreadID)
+	}
+	return inbound, notice, nil
+}
+
+// handleCallbackQuery answers a button press and, for approval buttons
+// pressed by an allowed user, delivers the answer to the inbox.
+func handleCallbackQuery(
+	ctx context.Context,
+	client *Client,
+	approvals *approvalStore,
+	allowList *channel.AllowList,
+	inbox func(message.InboundMessage) error,
+	logger *slog.Logger,
+	cq *CallbackQuery,
+	channelName string,
+) error {
+	msg, notice, convErr := convertCallback(cq, approvals, channelName)
+	if convErr == nil && !allowList.IsAllowed(msg) {
+		logger.Debug("callback query denied by allow list",
+			"sender", msg.Sender.ID,
+			"chat", msg.Chat.ID,
+		)
+		notice, convErr = "You are not allowed to answer this approval.", errNotApprovalCallback
+	}
+
+	// Always answer so the client stops showing a progress indicator.
+	if client != nil {
+		if err := client.AnswerCallbackQuery(ctx, cq.ID, notice); err != nil {
+			logger.Warn("failed to answer callback query", "error", err)
+		}
+	}
+
+	if convErr != nil {
+		logger.Debug("skipping callback query", "reason", convErr)
+		return nil
+	}
+	return inbox(msg)
+}
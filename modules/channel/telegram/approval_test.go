@@ -0,0 +1,180 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/flemzord/sclaw/internal/channel"
+	"github.com/flemzord/sclaw/pkg/message"
+)
+
+func TestApprovalPromptFlow(t *testing.T) {
+	var mu sync.Mutex
+	var sent SendMessageRequest
+	var edited EditMessageTextRequest
+	var answered []string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/sendMessage"):
+			if err := json.Unmarshal(body, &sent); err != nil {
+				t.Errorf("unmarshal send request: %v", err)
+			}
+			writeJSON(t, w, APIResponse[Message]{OK: true, Result: Message{MessageID: 42, Chat: Chat{ID: 100}}})
+		case strings.HasSuffix(r.URL.Path, "/editMessageText"):
+			if err := json.Unmarshal(body, &edited); err != nil {
+				t.Errorf("unmarshal edit request: %v", err)
+			}
+			writeJSON(t, w, APIResponse[Message]{OK: true, Result: Message{MessageID: 42}})
+		case strings.HasSuffix(r.URL.Path, "/answerCallbackQuery"):
+			var req answerCallbackQueryRequest
+			_ = json.Unmarshal(body, &req)
+			answered = append(answered, req.Text)
+			writeJSON(t, w, APIResponse[bool]{OK: true, Result: true})
+		default:
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	tg := &Telegram{client: NewClient("TOKEN", srv.URL), logger: discardLogger()}
+	out := message.OutboundMessage{Chat: message.Chat{ID: "100", Type: message.ChatDM}, ReplyToID: "7"}
+	prompt := channel.ApprovalPrompt{
+		ID:           "approve-exec-1",
+		ToolName:     "exec",
+		Arguments:    `{"command": "ls -la"}`,
+		Timeout:      2 * time.Minute,
+		OfferSession: true,
+		Pattern:      "ls *",
+	}
+	if err := tg.SendApprovalPrompt(context.Background(), out, prompt); err != nil {
+		t.Fatalf("SendApprovalPrompt() error: %v", err)
+	}
+
+	if sent.ReplyMarkup == nil || len(sent.ReplyMarkup.InlineKeyboard) != 3 {
+		t.Fatalf("keyboard = %+v, want 3 rows", sent.ReplyMarkup)
+	}
+	if sent.ReplyToMessageID != 7 || !strings.Contains(sent.Text, "2m0s") {
+		t.Errorf("sent = %+v", sent)
+	}
+	always := sent.ReplyMarkup.InlineKeyboard[2][0]
+	if !strings.Contains(always.Text, "ls *") || len(always.CallbackData) > 64 {
+		t.Errorf("always button = %+v", always)
+	}
+
+	// Pressing "always allow" produces an approval answer.
+	var delivered []message.InboundMessage
+	wh := NewWebhookReceiver(tg.client, func(msg message.InboundMessage) error {
+		delivered = append(delivered, msg)
+		return nil
+	}, channel.NewAllowList([]string{"123"}, nil), discardLogger(), "testbot", "telegram", "")
+	wh.approvals = &tg.approvals
+
+	update := Update{UpdateID: 1, CallbackQuery: &CallbackQuery{
+		ID:      "cb-1",
+		From:    &User{ID: 123, FirstName: "Alice"},
+		Message: &Message{MessageID: 42, Chat: Chat{ID: 100, Type: "private"}},
+		Data:    always.CallbackData,
+	}}
+	body, _ := json.Marshal(update)
+	if err := wh.HandleWebhook(context.Background(), "telegram", body, http.Header{}); err != nil {
+		t.Fatalf("HandleWebhook() error: %v", err)
+	}
+	if len(delivered) != 1 {
+		t.Fatalf("delivered %d messages, want 1", len(delivered))
+	}
+	var answer channel.ApprovalAnswer
+	if err := json.Unmarshal(delivered[0].Raw, &answer); err != nil {
+		t.Fatalf("unmarshal answer: %v", err)
+	}
+	if answer.ApprovalID != "approve-exec-1" || !answer.Approved || answer.Scope != "always" {
+		t.Errorf("answer = %+v", answer)
+	}
+	if delivered[0].Chat.ID != "100" || delivered[0].Sender.ID != "123" {
+		t.Errorf("delivered = %+v", delivered[0])
+	}
+
+	// Closing the prompt removes the buttons and forgets the prompt.
+	if err := tg.CloseApprovalPrompt(context.Background(), prompt.ID, "✅ Always allowed"); err != nil {
+		t.Fatalf("CloseApprovalPrompt() error: %v", err)
+	}
+	if edited.MessageID != 42 || !strings.HasSuffix(edited.Text, "✅ Always allowed") {
+		t.Errorf("edited = %+v", edited)
+	}
+	if err := wh.HandleWebhook(context.Background(), "telegram", body, http.Header{}); err != nil {
+		t.Fatalf("HandleWebhook() error: %v", err)
+	}
+	if len(delivered) != 1 {
+		t.Error("a closed prompt must not deliver another answer")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(answered) != 2 || answered[1] != "This approval is no longer pending." {
+		t.Errorf("callback answers = %q", answered)
+	}
+}
+
+func TestConvertCallback(t *testing.T) {
+	var store approvalStore
+	token, err := store.add(&approvalPrompt{approvalID: "a1", chatID: 100, messageID: 42, created: time.Now()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	adminToken, err := store.add(&approvalPrompt{approvalID: "a2", chatID: 100, messageID: 42, approvers: []string{"7"}, created: time.Now()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := &Message{MessageID: 42, Chat: Chat{ID: 100, Type: "group"}, MessageThreadID: 9}
+
+	tests := []struct {
+		name     string
+		data     string
+		msg      *Message
+		wantErr  bool
+		approved bool
+		scope    string
+	}{
+		{name: "once", data: "apv:" + token + ":o", msg: msg, approved: true},
+		{name: "session", data: "apv:" + token + ":s", msg: msg, approved: true, scope: "session"},
+		{name: "deny", data: "apv:" + token + ":d", msg: msg},
+		{name: "unknown choice", data: "apv:" + token + ":x", msg: msg, wantErr: true},
+		{name: "unknown token", data: "apv:nope:o", msg: msg, wantErr: true},
+		{name: "other prefix", data: "menu:1", msg: msg, wantErr: true},
+		{name: "not an approver", data: "apv:" + adminToken + ":o", msg: msg, wantErr: true},
+		{name: "other message", data: "apv:" + token + ":o", msg: &Message{MessageID: 43, Chat: Chat{ID: 100}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cq := &CallbackQuery{ID: "cb", From: &User{ID: 1}, Message: tt.msg, Data: tt.data}
+			got, _, err := convertCallback(cq, &store, "telegram")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			var answer channel.ApprovalAnswer
+			if err := json.Unmarshal(got.Raw, &answer); err != nil {
+				t.Fatal(err)
+			}
+			if answer.Approved != tt.approved || answer.Scope != tt.scope {
+				t.Errorf("answer = %+v", answer)
+			}
+			if got.ThreadID != "9" {
+				t.Errorf("ThreadID = %q, want 9", got.ThreadID)
+			}
+		})
+	}
+}
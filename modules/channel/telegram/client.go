@@ -7,7 +7,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"mime/multipart"
 	"net/http"
+	"os"
+	"path/filepath"
 	"strings"
 	"time"
 )
@@ -47,26 +50,58 @@ func NewClient(token, baseURL string) *Client {
 // do sends a JSON POST request to the given Bot API method and decodes the response.
 // It handles 429 rate limiting with Retry-After (max 3 retries, exponential backoff).
 func do[T any](ctx context.Context, c *Client, method string, payload any) (*T, error) {
-	url := fmt.Sprintf("%s/bot%s/%s", c.baseURL, c.token, method)
+	if payload == nil {
+		return send[T](ctx, c, method, nil)
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("telegram: marshal %s request: %w", method, err)
+	}
+	return send[T](ctx, c, method, func() (io.Reader, string, error) {
+		return bytes.NewReader(data), "application/json", nil
+	})
+}
 
-	var body io.Reader
-	if payload != nil {
-		data, err := json.Marshal(payload)
-		if err != nil {
-			return nil, fmt.Errorf("telegram: marshal %s request: %w", method, err)
-		}
-		body = bytes.NewReader(data)
+// sendMedia is like do, but uploads the media referenced by ref in the
+// request field named field (e.g. "photo") when ref is a file:// URL.
+// Other references are URLs or file IDs that Telegram resolves itself.
+func sendMedia[T any](ctx context.Context, c *Client, method string, payload any, field, ref string) (*T, error) {
+	path, ok := localMediaPath(ref)
+	if !ok {
+		return do[T](ctx, c, method, payload)
+	}
+	body, contentType, err := multipartBody(payload, field, path)
+	if err != nil {
+		return nil, fmt.Errorf("telegram: %s: %w", method, err)
 	}
+	return send[T](ctx, c, method, func() (io.Reader, string, error) {
+		return bytes.NewReader(body), contentType, nil
+	})
+}
+
+// send posts the body built by encode (none when nil) to the given Bot API
+// method and decodes the response. encode is called again for each retry.
+// It handles 429 rate limiting with Retry-After (max 3 retries, exponential backoff).
+func send[T any](ctx context.Context, c *Client, method string, encode func() (io.Reader, string, error)) (*T, error) {
+	url := fmt.Sprintf("%s/bot%s/%s", c.baseURL, c.token, method)
 
 	backoff := initialBackoff
 
 	for attempt := range maxRetries {
+		var body io.Reader
+		var contentType string
+		if encode != nil {
+			var err error
+			if body, contentType, err = encode(); err != nil {
+				return nil, fmt.Errorf("telegram: encode %s request: %w", method, err)
+			}
+		}
 		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, body)
 		if err != nil {
 			return nil, fmt.Errorf("telegram: create %s request: %w", method, err)
 		}
-		if payload != nil {
-			req.Header.Set("Content-Type", "application/json")
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
 		}
 
 		resp, err := c.http.Do(req)
@@ -97,12 +132,6 @@ func do[T any](ctx context.Context, c *Client, method string, payload any) (*T,
 			case <-timer.C:
 			}
 			backoff *= 2
-
-			// Re-create body reader for retry.
-			if payload != nil {
-				data, _ := json.Marshal(payload)
-				body = bytes.NewReader(data)
-			}
 			continue
 		}
 
@@ -130,6 +159,71 @@ func do[T any](ctx context.Context, c *Client, method string, payload any) (*T,
 	return nil, fmt.Errorf("telegram: %s: max retries exceeded", method)
 }
 
+// maxUploadBytes is the Bot API limit for files uploaded with a request.
+const maxUploadBytes = 50 << 20 // 50 MiB
+
+// localMediaPath returns the path of a file:// media reference.
+func localMediaPath(ref string) (string, bool) {
+	path, ok := strings.CutPrefix(ref, "file://")
+	if !ok || !filepath.IsAbs(path) {
+		return "", false
+	}
+	return path, true
+}
+
+// multipartBody encodes payload as multipart form data, replacing the value
+// of field with the content of the file at path.
+func multipartBody(payload any, field, path string) (body []byte, contentType string, err error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, "", err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, "", err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, "", err
+	}
+	defer func() { _ = f.Close() }()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, "", err
+	}
+	if info.Size() > maxUploadBytes {
+		return nil, "", fmt.Errorf("%s is larger than %d MiB", filepath.Base(path), maxUploadBytes>>20)
+	}
+
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	for name, raw := range fields {
+		if name == field {
+			continue
+		}
+		value := string(raw)
+		var str string
+		if json.Unmarshal(raw, &str) == nil {
+			value = str
+		}
+		if err := w.WriteField(name, value); err != nil {
+			return nil, "", err
+		}
+	}
+	part, err := w.CreateFormFile(field, filepath.Base(path))
+	if err != nil {
+		return nil, "", err
+	}
+	if _, err := io.Copy(part, f); err != nil {
+		return nil, "", err
+	}
+	if err := w.Close(); err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), w.FormDataContentType(), nil
+}
+
 // GetMe returns the bot's user information.
 func (c *Client) GetMe(ctx context.Context) (*User, error) {
 	return do[User](ctx, c, "getMe", nil)
@@ -166,24 +260,26 @@ func (c *Client) EditMessageText(ctx context.Context, req EditMessageTextRequest
 	return do[Message](ctx, c, "editMessageText", req)
 }
 
-// SendPhoto sends a photo to the specified chat.
+// SendPhoto sends a photo to the specified chat. A file:// Photo is
+// uploaded from disk.
 func (c *Client) SendPhoto(ctx context.Context, req SendPhotoRequest) (*Message, error) {
-	return do[Message](ctx, c, "sendPhoto", req)
+	return sendMedia[Message](ctx, c, "sendPhoto", req, "photo", req.Photo)
 }
 
 // SendAudio sends an audio file to the specified chat.
 func (c *Client) SendAudio(ctx context.Context, req SendAudioRequest) (*Message, error) {
-	return do[Message](ctx, c, "sendAudio", req)
+	return sendMedia[Message](ctx, c, "sendAudio", req, "audio", req.Audio)
 }
 
 // SendVoice sends a voice message to the specified chat.
 func (c *Client) SendVoice(ctx context.Context, req SendVoiceRequest) (*Message, error) {
-	return do[Message](ctx, c, "sendVoice", req)
+	return sendMedia[Message](ctx, c, "sendVoice", req, "voice", req.Voice)
 }
 
-// SendDocument sends a document to the specified chat.
+// SendDocument sends a document to the specified chat. A file:// Document
+// is uploaded from disk.
 func (c *Client) SendDocument(ctx context.Context, req SendDocumentRequest) (*Message, error) {
-	return do[Message](ctx, c, "sendDocument", req)
+	return sendMedia[Message](ctx, c, "sendDocument", req, "document", req.Document)
 }
 
 // SendLocation sends a location to the specified chat.
@@ -200,6 +296,16 @@ func (c *Client) SendChatAction(ctx context.Context, chatID int64, action string
 	return err
 }
 
+// AnswerCallbackQuery acknowledges an inline keyboard button press, showing
+// text as a short notification to the user when non-empty.
+func (c *Client) AnswerCallbackQuery(ctx context.Context, queryID, text string) error {
+	_, err := do[bool](ctx, c, "answerCallbackQuery", answerCallbackQueryRequest{
+		CallbackQueryID: queryID,
+		Text:            text,
+	})
+	return err
+}
+
 // GetFile retrieves basic info about a file and prepares it for downloading.
 func (c *Client) GetFile(ctx context.Context, fileID string) (*File, error) {
 	return do[File](ctx, c, "getFile", getFileRequest{FileID: fileID})
@@ -7,6 +7,8 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"sync/atomic"
 	"testing"
@@ -101,6 +103,63 @@ func TestSendMessage(t *testing.T) {
 	}
 }
 
+func TestSendPhoto_UploadsLocalFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "chart.png")
+	if err := os.WriteFile(path, []byte("PNGDATA"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Fatalf("ParseMultipartForm: %v", err)
+		}
+		if got := r.FormValue("chat_id"); got != "42" {
+			t.Errorf("chat_id = %q, want 42", got)
+		}
+		if got := r.FormValue("caption"); got != "Spending" {
+			t.Errorf("caption = %q, want Spending", got)
+		}
+		f, hdr, err := r.FormFile("photo")
+		if err != nil {
+			t.Fatalf("FormFile: %v", err)
+		}
+		data, _ := io.ReadAll(f)
+		if hdr.Filename != "chart.png" || string(data) != "PNGDATA" {
+			t.Errorf("uploaded %q = %q", hdr.Filename, data)
+		}
+		writeJSON(t, w, APIResponse[Message]{OK: true, Result: Message{MessageID: 7}})
+	}))
+	defer srv.Close()
+
+	client := NewClient("TOKEN", srv.URL)
+	msg, err := client.SendPhoto(context.Background(), SendPhotoRequest{
+		ChatID:  42,
+		Photo:   "file://" + path,
+		Caption: "Spending",
+	})
+	if err != nil {
+		t.Fatalf("SendPhoto() error: %v", err)
+	}
+	if msg.MessageID != 7 {
+		t.Errorf("MessageID = %d, want 7", msg.MessageID)
+	}
+}
+
+func TestSendPhoto_RemoteURLUsesJSON(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Content-Type = %q, want application/json", ct)
+		}
+		writeJSON(t, w, APIResponse[Message]{OK: true, Result: Message{MessageID: 8}})
+	}))
+	defer srv.Close()
+
+	client := NewClient("TOKEN", srv.URL)
+	if _, err := client.SendPhoto(context.Background(), SendPhotoRequest{ChatID: 42, Photo: "https://example.com/a.png"}); err != nil {
+		t.Fatalf("SendPhoto() error: %v", err)
+	}
+}
+
 func TestGetUpdates(t *testing.T) {
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path != "/botTOKEN/getUpdates" {
@@ -252,8 +311,8 @@ func TestConfigDefaults(t *testing.T) {
 	if cfg.PollingTimeout != 30 {
 		t.Errorf("PollingTimeout = %d, want 30", cfg.PollingTimeout)
 	}
-	if len(cfg.AllowedUpdates) != 3 {
-		t.Errorf("len(AllowedUpdates) = %d, want 3", len(cfg.AllowedUpdates))
+	if len(cfg.AllowedUpdates) != 4 {
+		t.Errorf("len(AllowedUpdates) = %d, want 4", len(cfg.AllowedUpdates))
 	}
 	if cfg.MaxMessageLength != 4096 {
 		t.Errorf("MaxMessageLength = %d, want 4096", cfg.MaxMessageLength)
@@ -34,7 +34,7 @@ func (c *Config) defaults() {
 		c.PollingTimeout = 30
 	}
 	if c.AllowedUpdates == nil {
-		c.AllowedUpdates = []string{"message", "edited_message", "channel_post"}
+		c.AllowedUpdates = []string{"message", "edited_message", "channel_post", "callback_query"}
 	}
 	if c.MaxMessageLength == 0 {
 		c.MaxMessageLength = 4096
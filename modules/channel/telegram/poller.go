@@ -25,6 +25,9 @@ type Poller struct {
 	channelName string
 	config      Config
 
+	// approvals resolves approval button presses; nil ignores them.
+	approvals *approvalStore
+
 	ctx    context.Context
 	cancel context.CancelFunc
 	done   chan struct{}
@@ -138,6 +141,20 @@ func (p *Poller) loop() {
 func (p *Poller) handleUpdate(update *Update) {
 	p.logger.Debug("received update", "update_id", update.UpdateID)
 
+	if update.CallbackQuery != nil {
+		if p.approvals == nil {
+			return
+		}
+		if err := handleCallbackQuery(p.ctx, p.client, p.approvals, p.allowList, p.inbox,
+			p.logger, update.CallbackQuery, p.channelName); err != nil {
+			p.logger.Error("failed to deliver callback query to inbox",
+				"update_id", update.UpdateID,
+				"error", err,
+			)
+		}
+		return
+	}
+
 	msg, err := convertInbound(update, p.botUsername, p.channelName)
 	if err != nil {
 		p.logger.Debug("skipping update", "update_id", update.UpdateID, "reason", err)
@@ -24,6 +24,7 @@ var (
 	_ channel.Channel          = (*Telegram)(nil)
 	_ channel.StreamingChannel = (*Telegram)(nil)
 	_ channel.TypingChannel    = (*Telegram)(nil)
+	_ channel.ApprovalChannel  = (*Telegram)(nil)
 	_ core.Configurable        = (*Telegram)(nil)
 	_ core.Provisioner         = (*Telegram)(nil)
 	_ core.Validator           = (*Telegram)(nil)
@@ -49,6 +50,9 @@ type Telegram struct {
 	// Checked by SupportsStreaming() to dynamically disable streaming.
 	streamingDisabled atomic.Bool
 
+	// approvals tracks approval prompts waiting for a button press.
+	approvals approvalStore
+
 	// Set during Start() depending on mode.
 	poller          *Poller
 	webhookReceiver *WebhookReceiver
@@ -122,6 +126,7 @@ func (t *Telegram) Start() error {
 			t.client, t.inbox, t.allowList, t.logger,
 			user.Username, channelName, t.config,
 		)
+		t.poller.approvals = &t.approvals
 		t.poller.Start()
 		t.logger.Info("telegram polling started",
 			"timeout", t.config.PollingTimeout,
@@ -136,6 +141,7 @@ func (t *Telegram) Start() error {
 			t.client, t.inbox, t.allowList, t.logger,
 			user.Username, channelName, t.config.WebhookSecret,
 		)
+		t.webhookReceiver.approvals = &t.approvals
 
 		// Register webhook with the gateway's dispatcher.
 		if err := t.registerWebhook(); err != nil {
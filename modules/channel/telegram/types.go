@@ -4,10 +4,30 @@ import "fmt"
 
 // Update represents an incoming update from the Telegram Bot API.
 type Update struct {
-	UpdateID      int      `json:"update_id"`
-	Message       *Message `json:"message,omitempty"`
-	EditedMessage *Message `json:"edited_message,omitempty"`
-	ChannelPost   *Message `json:"channel_post,omitempty"`
+	UpdateID      int            `json:"update_id"`
+	Message       *Message       `json:"message,omitempty"`
+	EditedMessage *Message       `json:"edited_message,omitempty"`
+	ChannelPost   *Message       `json:"channel_post,omitempty"`
+	CallbackQuery *CallbackQuery `json:"callback_query,omitempty"`
+}
+
+// CallbackQuery represents a press on an inline keyboard button.
+type CallbackQuery struct {
+	ID      string   `json:"id"`
+	From    *User    `json:"from"`
+	Message *Message `json:"message,omitempty"`
+	Data    string   `json:"data,omitempty"`
+}
+
+// InlineKeyboardMarkup is an inline keyboard attached to a message.
+type InlineKeyboardMarkup struct {
+	InlineKeyboard [][]InlineKeyboardButton `json:"inline_keyboard"`
+}
+
+// InlineKeyboardButton is one button of an inline keyboard.
+type InlineKeyboardButton struct {
+	Text         string `json:"text"`
+	CallbackData string `json:"callback_data,omitempty"`
 }
 
 // Message represents a Telegram message.
@@ -166,6 +186,8 @@ type SendMessageRequest struct {
 	DisableNotification   bool   `json:"disable_notification,omitempty"`
 	ReplyToMessageID      int    `json:"reply_to_message_id,omitempty"`
 	MessageThreadID       int    `json:"message_thread_id,omitempty"`
+
+	ReplyMarkup *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
 }
 
 // EditMessageTextRequest is the request body for the editMessageText method.
@@ -241,6 +263,12 @@ type sendChatActionRequest struct {
 	Action string `json:"action"`
 }
 
+// answerCallbackQueryRequest is the request body for the answerCallbackQuery method.
+type answerCallbackQueryRequest struct {
+	CallbackQueryID string `json:"callback_query_id"`
+	Text            string `json:"text,omitempty"`
+}
+
 // getFileRequest is the request body for the getFile method.
 type getFileRequest struct {
 	FileID string `json:"file_id"`
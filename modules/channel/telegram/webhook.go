@@ -26,6 +26,9 @@ type WebhookReceiver struct {
 	botUsername string
 	channelName string
 	secret      string
+
+	// approvals resolves approval button presses; nil ignores them.
+	approvals *approvalStore
 }
 
 // NewWebhookReceiver creates a new WebhookReceiver.
@@ -60,6 +63,14 @@ func (w *WebhookReceiver) HandleWebhook(ctx context.Context, _ string, body []by
 
 	w.logger.Debug("received webhook update", "update_id", update.UpdateID)
 
+	if update.CallbackQuery != nil {
+		if w.approvals == nil {
+			return nil
+		}
+		return handleCallbackQuery(ctx, w.client, w.approvals, w.allowList, w.inbox,
+			w.logger, update.CallbackQuery, w.channelName)
+	}
+
 	msg, err := convertInbound(&update, w.botUsername, w.channelName)
 	if err != nil {
 		w.logger.Debug("skipping webhook update", "update_id", update.UpdateID, "reason", err)
@@ -19,9 +19,12 @@ type CostConfig struct {
 }
 
 // ModelPricing holds per-million-token pricing for a model.
+// CachedInput is the discounted price for prompt tokens served from the
+// provider's cache; when zero, cached tokens are billed at the Input rate.
 type ModelPricing struct {
-	Input  float64 `yaml:"input"`
-	Output float64 `yaml:"output"`
+	Input       float64 `yaml:"input"`
+	Output      float64 `yaml:"output"`
+	CachedInput float64 `yaml:"cached_input"`
 }
 
 func (c *Config) defaults() {
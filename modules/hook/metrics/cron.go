@@ -0,0 +1,83 @@
+package metrics
+
+import (
+	"github.com/flemzord/sclaw/internal/cron"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// jobStatuses reports the live state of scheduled jobs. Implemented by
+// *cron.Scheduler.
+type jobStatuses interface {
+	Statuses() []cron.JobStatus
+}
+
+// cronCollector exports the live state of scheduled jobs. It reads the
+// scheduler on each scrape, so jobs added or removed at runtime are
+// reflected without bookkeeping.
+type cronCollector struct {
+	scheduler jobStatuses
+
+	running      *prometheus.Desc
+	nextRun      *prometheus.Desc
+	runs         *prometheus.Desc
+	failures     *prometheus.Desc
+	skips        *prometheus.Desc
+	lastDuration *prometheus.Desc
+	avgDuration  *prometheus.Desc
+}
+
+// Compile-time interface check.
+var _ prometheus.Collector = (*cronCollector)(nil)
+
+func newCronCollector(scheduler jobStatuses) *cronCollector {
+	labels := []string{"job"}
+	return &cronCollector{
+		scheduler: scheduler,
+		running: prometheus.NewDesc("sclaw_cron_job_running",
+			"Whether the job is running (1) or not (0).", labels, nil),
+		nextRun: prometheus.NewDesc("sclaw_cron_job_next_run_timestamp_seconds",
+			"Unix time of the next scheduled run.", labels, nil),
+		runs: prometheus.NewDesc("sclaw_cron_job_runs_total",
+			"Total runs of the job since startup.", labels, nil),
+		failures: prometheus.NewDesc("sclaw_cron_job_failures_total",
+			"Total failed runs of the job since startup.", labels, nil),
+		skips: prometheus.NewDesc("sclaw_cron_job_skips_total",
+			"Total ticks skipped because the job was still running.", labels, nil),
+		lastDuration: prometheus.NewDesc("sclaw_cron_job_last_duration_seconds",
+			"Duration of the job's last run.", labels, nil),
+		avgDuration: prometheus.NewDesc("sclaw_cron_job_avg_duration_seconds",
+			"Average duration of the job's latest runs.", labels, nil),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *cronCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.running
+	ch <- c.nextRun
+	ch <- c.runs
+	ch <- c.failures
+	ch <- c.skips
+	ch <- c.lastDuration
+	ch <- c.avgDuration
+}
+
+// Collect implements prometheus.Collector.
+func (c *cronCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, s := range c.scheduler.Statuses() {
+		running := 0.0
+		if s.Running {
+			running = 1
+		}
+		ch <- prometheus.MustNewConstMetric(c.running, prometheus.GaugeValue, running, s.Name)
+		if s.NextRun != nil {
+			ch <- prometheus.MustNewConstMetric(c.nextRun, prometheus.GaugeValue, float64(s.NextRun.Unix()), s.Name)
+		}
+		ch <- prometheus.MustNewConstMetric(c.runs, prometheus.CounterValue, float64(s.Runs), s.Name)
+		ch <- prometheus.MustNewConstMetric(c.failures, prometheus.CounterValue, float64(s.Failures), s.Name)
+		ch <- prometheus.MustNewConstMetric(c.skips, prometheus.CounterValue, float64(s.Skips), s.Name)
+		if s.Durations.Count > 0 {
+			ch <- prometheus.MustNewConstMetric(c.lastDuration, prometheus.GaugeValue, float64(s.Durations.LastMs)/1000, s.Name)
+			ch <- prometheus.MustNewConstMetric(c.avgDuration, prometheus.GaugeValue, float64(s.Durations.AvgMs)/1000, s.Name)
+		}
+	}
+}
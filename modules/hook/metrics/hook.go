@@ -55,6 +55,9 @@ func (h *metricsHook) Execute(_ context.Context, hctx *hook.Context) (hook.Actio
 	if usage.CompletionTokens > 0 {
 		c.tokensUsed.WithLabelValues("output", prov, model).Add(float64(usage.CompletionTokens))
 	}
+	if usage.CachedTokens > 0 {
+		c.tokensUsed.WithLabelValues("cached", prov, model).Add(float64(usage.CachedTokens))
+	}
 
 	// Latency.
 	if startTime, ok := hctx.Metadata[metadataKeyStartTime].(time.Time); ok {
@@ -78,7 +81,13 @@ func (h *metricsHook) Execute(_ context.Context, hctx *hook.Context) (hook.Actio
 	// Cost tracking.
 	if h.config.Cost.Enabled {
 		if pricing, ok := h.config.Cost.Prices[model]; ok {
-			inputCost := float64(usage.PromptTokens) / 1_000_000 * pricing.Input
+			cachedPrice := pricing.Input
+			if pricing.CachedInput > 0 {
+				cachedPrice = pricing.CachedInput
+			}
+			uncached := usage.PromptTokens - usage.CachedTokens
+			inputCost := float64(uncached)/1_000_000*pricing.Input +
+				float64(usage.CachedTokens)/1_000_000*cachedPrice
 			outputCost := float64(usage.CompletionTokens) / 1_000_000 * pricing.Output
 			if total := inputCost + outputCost; total > 0 {
 				c.costDollars.WithLabelValues(prov, model).Add(total)
@@ -22,6 +22,7 @@ func init() {
 // Module is the hook.metrics module.
 type Module struct {
 	config     Config
+	appCtx     *core.AppContext
 	registry   *prometheus.Registry
 	collectors *collectors
 	handler    http.Handler
@@ -34,6 +35,7 @@ var (
 	_ core.Configurable = (*Module)(nil)
 	_ core.Provisioner  = (*Module)(nil)
 	_ core.Validator    = (*Module)(nil)
+	_ core.Starter      = (*Module)(nil)
 	_ hook.Provider     = (*Module)(nil)
 )
 
@@ -56,6 +58,7 @@ func (m *Module) Configure(node *yaml.Node) error {
 
 // Provision implements core.Provisioner.
 func (m *Module) Provision(ctx *core.AppContext) error {
+	m.appCtx = ctx
 	m.registry = prometheus.NewRegistry()
 	m.registry.MustRegister(promcollectors.NewGoCollector())
 	m.registry.MustRegister(promcollectors.NewProcessCollector(promcollectors.ProcessCollectorOpts{}))
@@ -89,6 +92,18 @@ func (m *Module) Validate() error {
 	return nil
 }
 
+// Start implements core.Starter. It exports the state of scheduled jobs
+// when the cron scheduler is wired; the scheduler is registered after
+// modules are provisioned, so it can only be resolved here.
+func (m *Module) Start() error {
+	if svc, ok := m.appCtx.GetService("cron.scheduler"); ok {
+		if s, ok := svc.(jobStatuses); ok {
+			m.registry.MustRegister(newCronCollector(s))
+		}
+	}
+	return nil
+}
+
 // Hooks implements hook.Provider.
 func (m *Module) Hooks() []hook.Hook {
 	return m.hooks
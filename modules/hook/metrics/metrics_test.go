@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/flemzord/sclaw/internal/agent"
+	"github.com/flemzord/sclaw/internal/cron"
 	"github.com/flemzord/sclaw/internal/hook"
 	"github.com/flemzord/sclaw/internal/provider"
 	"github.com/flemzord/sclaw/internal/tool"
@@ -239,6 +240,45 @@ func TestMetricsHook_CostTracking(t *testing.T) {
 	}
 }
 
+func TestMetricsHook_CachedTokens(t *testing.T) {
+	c, reg := newTestCollectors(t)
+	cfg := &Config{
+		Cost: CostConfig{
+			Enabled: true,
+			Prices: map[string]ModelPricing{
+				"gpt-4o": {Input: 2.0, Output: 10.0, CachedInput: 0.5},
+			},
+		},
+	}
+	h := &metricsHook{collectors: c, config: cfg}
+
+	hctx := &hook.Context{
+		Position: hook.AfterSend,
+		Inbound:  message.InboundMessage{Channel: "test"},
+		Response: &agent.Response{
+			Model:    "gpt-4o",
+			Provider: "openai",
+			TotalUsage: provider.TokenUsage{
+				PromptTokens: 1_000_000,
+				CachedTokens: 800_000,
+			},
+		},
+		Session:  &stubSession{id: "s1", channel: "test", chatID: "1"},
+		Metadata: make(map[string]any),
+	}
+
+	_, _ = h.Execute(context.Background(), hctx)
+
+	body := scrapeMetrics(t, reg)
+	if !strings.Contains(body, `sclaw_tokens_used{model="gpt-4o",provider="openai",type="cached"} 800000`) {
+		t.Errorf("missing cached token counter, got:\n%s", body)
+	}
+	// 200K uncached * $2/M = $0.4, 800K cached * $0.5/M = $0.4 → total $0.8
+	if !strings.Contains(body, `sclaw_cost_dollars{model="gpt-4o",provider="openai"} 0.8`) {
+		t.Errorf("unexpected cost metric, got:\n%s", body)
+	}
+}
+
 func TestMetricsHook_NilResponse(t *testing.T) {
 	c, _ := newTestCollectors(t)
 	h := &metricsHook{collectors: c, config: &Config{}}
@@ -296,3 +336,44 @@ func TestConfig_Defaults(t *testing.T) {
 		t.Errorf("expected /metrics, got %s", c.Prometheus.Path)
 	}
 }
+
+// stubScheduler implements jobStatuses for testing.
+type stubScheduler []cron.JobStatus
+
+func (s stubScheduler) Statuses() []cron.JobStatus { return s }
+
+func TestCronCollector(t *testing.T) {
+	next := time.Unix(1_800_000_000, 0)
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(newCronCollector(stubScheduler{
+		{
+			Name:      "prompt_cron:main:digest",
+			NextRun:   &next,
+			Running:   true,
+			Runs:      3,
+			Failures:  1,
+			Skips:     2,
+			Durations: cron.DurationStats{Count: 3, LastMs: 1500, AvgMs: 2000},
+		},
+		{Name: "memory_compaction"},
+	}))
+
+	body := scrapeMetrics(t, reg)
+	for _, want := range []string{
+		`sclaw_cron_job_running{job="prompt_cron:main:digest"} 1`,
+		`sclaw_cron_job_next_run_timestamp_seconds{job="prompt_cron:main:digest"} 1.8e+09`,
+		`sclaw_cron_job_runs_total{job="prompt_cron:main:digest"} 3`,
+		`sclaw_cron_job_failures_total{job="prompt_cron:main:digest"} 1`,
+		`sclaw_cron_job_skips_total{job="prompt_cron:main:digest"} 2`,
+		`sclaw_cron_job_last_duration_seconds{job="prompt_cron:main:digest"} 1.5`,
+		`sclaw_cron_job_avg_duration_seconds{job="prompt_cron:main:digest"} 2`,
+		`sclaw_cron_job_running{job="memory_compaction"} 0`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("missing %q in output", want)
+		}
+	}
+	if strings.Contains(body, `sclaw_cron_job_last_duration_seconds{job="memory_compaction"}`) {
+		t.Error("duration exported for a job that never ran")
+	}
+}
@@ -7,35 +7,26 @@ import (
 	"errors"
 	"fmt"
 	"slices"
+	"time"
 
+	"github.com/flemzord/sclaw/internal/memory"
 	"github.com/flemzord/sclaw/internal/provider"
 )
 
 // Append adds a message to the session's history.
 func (h *historyStore) Append(sessionID string, msg provider.LLMMessage) error {
-	var toolCallsJSON []byte
-	if len(msg.ToolCalls) > 0 {
-		var err error
-		toolCallsJSON, err = json.Marshal(msg.ToolCalls)
-		if err != nil {
-			return fmt.Errorf("sqlite: marshal tool_calls: %w", err)
-		}
-	} else {
-		toolCallsJSON = []byte("[]")
-	}
-
-	isError := 0
-	if msg.IsError {
-		isError = 1
+	toolCallsJSON, isError, err := encodeMessage(msg)
+	if err != nil {
+		return err
 	}
 
 	// HistoryStore interface does not carry context; use TODO as placeholder.
-	_, err := h.db.ExecContext(context.TODO(), `
+	_, err = h.db.ExecContext(context.TODO(), `
 		INSERT INTO messages (session_id, seq, role, content, name, tool_id, tool_calls, is_error)
 		VALUES (?, COALESCE((SELECT MAX(seq) FROM messages WHERE session_id = ?), 0) + 1,
 		        ?, ?, ?, ?, ?, ?)`,
 		sessionID, sessionID,
-		string(msg.Role), msg.Content, msg.Name, msg.ToolID, string(toolCallsJSON), isError,
+		string(msg.Role), msg.Content, msg.Name, msg.ToolID, toolCallsJSON, isError,
 	)
 	if err != nil {
 		return fmt.Errorf("sqlite: append message: %w", err)
@@ -44,6 +35,60 @@ func (h *historyStore) Append(sessionID string, msg provider.LLMMessage) error {
 	return nil
 }
 
+// Replace overwrites all messages of a session, keeping its summary.
+// Rewritten messages keep the timestamps of the rows they replace, so that
+// editing a conversation does not make it look recently active.
+func (h *historyStore) Replace(sessionID string, msgs []provider.LLMMessage) error {
+	tx, err := h.db.BeginTx(context.TODO(), nil)
+	if err != nil {
+		return fmt.Errorf("sqlite: begin replace tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	rows, err := tx.QueryContext(context.TODO(),
+		"SELECT created_at FROM messages WHERE session_id = ? ORDER BY seq ASC", sessionID)
+	if err != nil {
+		return fmt.Errorf("sqlite: read message times: %w", err)
+	}
+	var times []string
+	for rows.Next() {
+		var t string
+		if err := rows.Scan(&t); err != nil {
+			_ = rows.Close()
+			return fmt.Errorf("sqlite: scan message time: %w", err)
+		}
+		times = append(times, t)
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("sqlite: read message times: %w", err)
+	}
+
+	if _, err := tx.ExecContext(context.TODO(), "DELETE FROM messages WHERE session_id = ?", sessionID); err != nil {
+		return fmt.Errorf("sqlite: clear messages: %w", err)
+	}
+	for i, msg := range msgs {
+		toolCallsJSON, isError, err := encodeMessage(msg)
+		if err != nil {
+			return err
+		}
+		var createdAt any
+		if i < len(times) {
+			createdAt = times[i]
+		}
+		if _, err := tx.ExecContext(context.TODO(), `
+			INSERT INTO messages (session_id, seq, role, content, name, tool_id, tool_calls, is_error, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, COALESCE(?, strftime('%Y-%m-%dT%H:%M:%fZ','now')))`,
+			sessionID, i+1,
+			string(msg.Role), msg.Content, msg.Name, msg.ToolID, toolCallsJSON, isError, createdAt,
+		); err != nil {
+			return fmt.Errorf("sqlite: replace message: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
 // GetRecent returns the n most recent messages for a session.
 func (h *historyStore) GetRecent(sessionID string, n int) ([]provider.LLMMessage, error) {
 	if n <= 0 {
@@ -168,6 +213,50 @@ func (h *historyStore) Len(sessionID string) (int, error) {
 	return count, nil
 }
 
+// Sessions returns the sessions with stored messages, most recently updated
+// first.
+func (h *historyStore) Sessions() ([]memory.SessionInfo, error) {
+	rows, err := h.db.QueryContext(context.TODO(), `
+		SELECT session_id, COUNT(*), MAX(created_at) FROM messages
+		GROUP BY session_id ORDER BY MAX(created_at) DESC, session_id`)
+	if err != nil {
+		return nil, fmt.Errorf("sqlite: list sessions: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var sessions []memory.SessionInfo
+	for rows.Next() {
+		var (
+			info      memory.SessionInfo
+			updatedAt string
+		)
+		if err := rows.Scan(&info.ID, &info.Messages, &updatedAt); err != nil {
+			return nil, fmt.Errorf("sqlite: scan session: %w", err)
+		}
+		if t, err := time.Parse(time.RFC3339Nano, updatedAt); err == nil {
+			info.UpdatedAt = t
+		}
+		sessions = append(sessions, info)
+	}
+	return sessions, rows.Err()
+}
+
+// encodeMessage returns the tool_calls and is_error column values of msg.
+func encodeMessage(msg provider.LLMMessage) (toolCalls string, isError int, err error) {
+	toolCalls = "[]"
+	if len(msg.ToolCalls) > 0 {
+		data, err := json.Marshal(msg.ToolCalls)
+		if err != nil {
+			return "", 0, fmt.Errorf("sqlite: marshal tool_calls: %w", err)
+		}
+		toolCalls = string(data)
+	}
+	if msg.IsError {
+		isError = 1
+	}
+	return toolCalls, isError, nil
+}
+
 // scanner abstracts *sql.Row and *sql.Rows for shared scan logic.
 type scanner interface {
 	Scan(dest ...any) error
@@ -23,12 +23,14 @@ func init() {
 
 // Compile-time interface guards.
 var (
-	_ memory.HistoryStore = (*historyStore)(nil)
-	_ memory.Store        = (*factStore)(nil)
-	_ core.Configurable   = (*Module)(nil)
-	_ core.Provisioner    = (*Module)(nil)
-	_ core.Validator      = (*Module)(nil)
-	_ core.Stopper        = (*Module)(nil)
+	_ memory.HistoryStore  = (*historyStore)(nil)
+	_ memory.SessionLister = (*historyStore)(nil)
+	_ memory.HistoryEditor = (*historyStore)(nil)
+	_ memory.Store         = (*factStore)(nil)
+	_ core.Configurable    = (*Module)(nil)
+	_ core.Provisioner     = (*Module)(nil)
+	_ core.Validator       = (*Module)(nil)
+	_ core.Stopper         = (*Module)(nil)
 )
 
 // Module implements a SQLite-backed memory module providing both
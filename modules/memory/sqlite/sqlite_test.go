@@ -689,3 +689,89 @@ func TestMultipleSessions(t *testing.T) {
 		t.Errorf("after purge: s1=%d s2=%d, want 0 and 1", n1, n2)
 	}
 }
+
+func TestHistorySessions(t *testing.T) {
+	m := newTestModule(t)
+	h := m.history
+
+	sessions, err := h.Sessions()
+	if err != nil {
+		t.Fatalf("sessions: %v", err)
+	}
+	if len(sessions) != 0 {
+		t.Fatalf("sessions = %+v, want none", sessions)
+	}
+
+	for _, id := range []string{"old", "old", "new"} {
+		if err := h.Append(id, provider.LLMMessage{Role: provider.MessageRoleUser, Content: "x"}); err != nil {
+			t.Fatalf("append: %v", err)
+		}
+		time.Sleep(2 * time.Millisecond)
+	}
+
+	sessions, err = h.Sessions()
+	if err != nil {
+		t.Fatalf("sessions: %v", err)
+	}
+	if len(sessions) != 2 || sessions[0].ID != "new" || sessions[1].ID != "old" || sessions[1].Messages != 2 {
+		t.Fatalf("sessions = %+v", sessions)
+	}
+	if sessions[0].UpdatedAt.IsZero() {
+		t.Error("UpdatedAt not set")
+	}
+}
+
+func TestHistoryReplace(t *testing.T) {
+	m := newTestModule(t)
+	h := m.history
+
+	for _, c := range []string{"one", "secret", "three"} {
+		if err := h.Append("s1", provider.LLMMessage{Role: provider.MessageRoleUser, Content: c}); err != nil {
+			t.Fatalf("append: %v", err)
+		}
+		time.Sleep(2 * time.Millisecond)
+	}
+	if err := h.SetSummary("s1", "kept"); err != nil {
+		t.Fatalf("set summary: %v", err)
+	}
+	before, err := h.Sessions()
+	if err != nil {
+		t.Fatalf("sessions: %v", err)
+	}
+
+	err = h.Replace("s1", []provider.LLMMessage{
+		{Role: provider.MessageRoleUser, Content: "one"},
+		{Role: provider.MessageRoleAssistant, Content: "calling", ToolCalls: []provider.ToolCall{
+			{ID: "c1", Name: "exec", Arguments: json.RawMessage(`{}`)},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("replace: %v", err)
+	}
+
+	msgs, err := h.GetAll("s1")
+	if err != nil {
+		t.Fatalf("get all: %v", err)
+	}
+	if len(msgs) != 2 || msgs[1].Content != "calling" || len(msgs[1].ToolCalls) != 1 {
+		t.Fatalf("messages = %+v", msgs)
+	}
+
+	after, err := h.Sessions()
+	if err != nil {
+		t.Fatalf("sessions: %v", err)
+	}
+	if after[0].UpdatedAt.After(before[0].UpdatedAt) {
+		t.Errorf("UpdatedAt moved from %v to %v", before[0].UpdatedAt, after[0].UpdatedAt)
+	}
+
+	if err := h.Append("s1", provider.LLMMessage{Role: provider.MessageRoleUser, Content: "next"}); err != nil {
+		t.Fatalf("append after replace: %v", err)
+	}
+	if n, _ := h.Len("s1"); n != 3 {
+		t.Errorf("len = %d, want 3", n)
+	}
+	if summary, _ := h.GetSummary("s1"); summary != "kept" {
+		t.Errorf("summary = %q, want kept", summary)
+	}
+}
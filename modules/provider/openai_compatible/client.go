@@ -23,6 +23,9 @@ type oaiRequest struct {
 	Temperature   *float64            `json:"temperature,omitempty"`
 	TopP          *float64            `json:"top_p,omitempty"`
 	Stop          []string            `json:"stop,omitempty"`
+
+	// PromptCacheKey routes requests sharing a prefix to the same cache.
+	PromptCacheKey string `json:"prompt_cache_key,omitempty"`
 }
 
 // oaiStreamOptions controls streaming behavior.
@@ -43,9 +46,16 @@ type oaiRequestMessage struct {
 
 // oaiContentPart is a single element in a multimodal content array.
 type oaiContentPart struct {
-	Type     string       `json:"type"`
-	Text     string       `json:"text,omitempty"`
-	ImageURL *oaiImageURL `json:"image_url,omitempty"`
+	Type         string           `json:"type"`
+	Text         string           `json:"text,omitempty"`
+	ImageURL     *oaiImageURL     `json:"image_url,omitempty"`
+	CacheControl *oaiCacheControl `json:"cache_control,omitempty"`
+}
+
+// oaiCacheControl is an explicit prompt cache breakpoint, understood by
+// Anthropic-style backends exposed through OpenAI-compatible gateways.
+type oaiCacheControl struct {
+	Type string `json:"type"`
 }
 
 // oaiImageURL holds the URL and detail level for an image content part.
@@ -97,9 +107,28 @@ type oaiChoice struct {
 }
 
 type oaiUsage struct {
-	PromptTokens     int `json:"prompt_tokens"`
-	CompletionTokens int `json:"completion_tokens"`
-	TotalTokens      int `json:"total_tokens"`
+	PromptTokens        int                    `json:"prompt_tokens"`
+	CompletionTokens    int                    `json:"completion_tokens"`
+	TotalTokens         int                    `json:"total_tokens"`
+	PromptTokensDetails *oaiPromptTokenDetails `json:"prompt_tokens_details,omitempty"`
+}
+
+// oaiPromptTokenDetails breaks down prompt token usage.
+type oaiPromptTokenDetails struct {
+	CachedTokens int `json:"cached_tokens"`
+}
+
+// toTokenUsage converts wire usage into a provider.TokenUsage.
+func (u oaiUsage) toTokenUsage() provider.TokenUsage {
+	usage := provider.TokenUsage{
+		PromptTokens:     u.PromptTokens,
+		CompletionTokens: u.CompletionTokens,
+		TotalTokens:      u.TotalTokens,
+	}
+	if u.PromptTokensDetails != nil {
+		usage.CachedTokens = u.PromptTokensDetails.CachedTokens
+	}
+	return usage
 }
 
 // buildRequest converts a provider.CompletionRequest into an oaiRequest.
@@ -189,14 +218,33 @@ func buildRequest(model string, configMaxTokens int, req provider.CompletionRequ
 	return oai
 }
 
+// applyCacheControl attaches explicit cache breakpoints to the messages
+// carrying a provider.CacheControl hint. Text content is rewritten as a
+// single text part because breakpoints can only be set on content parts.
+func applyCacheControl(oai *oaiRequest, req provider.CompletionRequest) {
+	for i, m := range req.Messages {
+		if m.CacheControl == "" || i >= len(oai.Messages) {
+			continue
+		}
+		cc := &oaiCacheControl{Type: string(m.CacheControl)}
+		switch content := oai.Messages[i].Content.(type) {
+		case string:
+			if content == "" {
+				continue
+			}
+			oai.Messages[i].Content = []oaiContentPart{{Type: "text", Text: content, CacheControl: cc}}
+		case []oaiContentPart:
+			if len(content) > 0 {
+				content[len(content)-1].CacheControl = cc
+			}
+		}
+	}
+}
+
 // parseResponse converts an oaiResponse into a provider.CompletionResponse.
 func parseResponse(resp oaiResponse) provider.CompletionResponse {
 	var cr provider.CompletionResponse
-	cr.Usage = provider.TokenUsage{
-		PromptTokens:     resp.Usage.PromptTokens,
-		CompletionTokens: resp.Usage.CompletionTokens,
-		TotalTokens:      resp.Usage.TotalTokens,
-	}
+	cr.Usage = resp.Usage.toTokenUsage()
 
 	if len(resp.Choices) == 0 {
 		return cr
@@ -5,6 +5,8 @@ import (
 	"net/url"
 	"strings"
 	"time"
+
+	ctxengine "github.com/flemzord/sclaw/internal/context"
 )
 
 // Config holds the configuration for an OpenAI-compatible provider.
@@ -17,6 +19,20 @@ type Config struct {
 	MaxTokens     int               `yaml:"max_tokens"`
 	Headers       map[string]string `yaml:"headers"`
 	Timeout       time.Duration     `yaml:"timeout"`
+
+	// Tokenizer names the BPE encoding used for token estimation
+	// ("cl100k_base" or "o200k_base"). Empty infers it from the model name.
+	Tokenizer string `yaml:"tokenizer"`
+
+	// CacheControl emits explicit cache_control breakpoints on messages
+	// marked as cacheable. Enable it for Anthropic-style backends reached
+	// through an OpenAI-compatible gateway; OpenAI caches automatically.
+	CacheControl bool `yaml:"cache_control"`
+
+	// PromptCacheKey sends the request's cache key as prompt_cache_key so
+	// requests sharing a prefix hit the same cache. Off by default: strict
+	// OpenAI-compatible backends reject unknown fields.
+	PromptCacheKey bool `yaml:"prompt_cache_key"`
 }
 
 // defaults sets default values for unset fields.
@@ -53,6 +69,9 @@ func (c *Config) validate() error {
 	if c.ContextWindow < 0 {
 		return fmt.Errorf("provider.openai_compatible: context_window must not be negative")
 	}
+	if c.Tokenizer != "" && !ctxengine.IsKnownEncoding(c.Tokenizer) {
+		return fmt.Errorf("provider.openai_compatible: unknown tokenizer %q", c.Tokenizer)
+	}
 	if c.MaxTokens < 0 {
 		return fmt.Errorf("provider.openai_compatible: max_tokens must not be negative")
 	}
@@ -87,7 +87,7 @@ func (p *Provider) Validate() error {
 
 // Complete implements provider.Provider.
 func (p *Provider) Complete(ctx context.Context, req provider.CompletionRequest) (provider.CompletionResponse, error) {
-	oaiReq := buildRequest(p.config.Model, p.config.MaxTokens, req, false)
+	oaiReq := p.buildRequest(req, false)
 
 	resp, err := p.doRequest(ctx, oaiReq)
 	if err != nil {
@@ -107,9 +107,22 @@ func (p *Provider) Complete(ctx context.Context, req provider.CompletionRequest)
 	return parseResponse(oaiResp), nil
 }
 
+// buildRequest converts req to the wire format, applying the cache
+// options enabled in the config.
+func (p *Provider) buildRequest(req provider.CompletionRequest, stream bool) oaiRequest {
+	oaiReq := buildRequest(p.config.Model, p.config.MaxTokens, req, stream)
+	if p.config.CacheControl {
+		applyCacheControl(&oaiReq, req)
+	}
+	if p.config.PromptCacheKey {
+		oaiReq.PromptCacheKey = req.CacheKey
+	}
+	return oaiReq
+}
+
 // Stream implements provider.Provider.
 func (p *Provider) Stream(ctx context.Context, req provider.CompletionRequest) (<-chan provider.StreamChunk, error) {
-	oaiReq := buildRequest(p.config.Model, p.config.MaxTokens, req, true)
+	oaiReq := p.buildRequest(req, true)
 
 	resp, err := p.doRequest(ctx, oaiReq)
 	if err != nil {
@@ -155,6 +168,11 @@ func (p *Provider) ModelName() string {
 	return p.config.Model
 }
 
+// TokenizerName implements provider.TokenizerNamer.
+func (p *Provider) TokenizerName() string {
+	return p.config.Tokenizer
+}
+
 // HealthCheck implements provider.HealthChecker.
 // It probes the /models endpoint to check provider availability.
 func (p *Provider) HealthCheck(ctx context.Context) error {
@@ -190,10 +208,11 @@ func errMissingField(field string) error {
 
 // Compile-time interface assertions.
 var (
-	_ core.Module            = (*Provider)(nil)
-	_ core.Configurable      = (*Provider)(nil)
-	_ core.Provisioner       = (*Provider)(nil)
-	_ core.Validator         = (*Provider)(nil)
-	_ provider.Provider      = (*Provider)(nil)
-	_ provider.HealthChecker = (*Provider)(nil)
+	_ core.Module             = (*Provider)(nil)
+	_ core.Configurable       = (*Provider)(nil)
+	_ core.Provisioner        = (*Provider)(nil)
+	_ core.Validator          = (*Provider)(nil)
+	_ provider.Provider       = (*Provider)(nil)
+	_ provider.HealthChecker  = (*Provider)(nil)
+	_ provider.TokenizerNamer = (*Provider)(nil)
 )
@@ -887,6 +887,30 @@ func TestValidate_NegativeContextWindow(t *testing.T) {
 	}
 }
 
+func TestValidate_UnknownTokenizer(t *testing.T) {
+	p := &Provider{config: Config{
+		BaseURL:   "http://localhost",
+		APIKey:    "k",
+		Model:     "m",
+		Tokenizer: "p50k_base",
+	}}
+	err := p.Validate()
+	if err == nil {
+		t.Fatal("expected error for unknown tokenizer")
+	}
+	if !strings.Contains(err.Error(), "tokenizer") {
+		t.Errorf("error should mention tokenizer: %v", err)
+	}
+
+	p.config.Tokenizer = "o200k_base"
+	if err := p.Validate(); err != nil {
+		t.Errorf("unexpected error for known tokenizer: %v", err)
+	}
+	if p.TokenizerName() != "o200k_base" {
+		t.Errorf("TokenizerName = %q, want %q", p.TokenizerName(), "o200k_base")
+	}
+}
+
 func TestValidate_NegativeMaxTokens(t *testing.T) {
 	p := &Provider{config: Config{
 		BaseURL:   "http://localhost",
@@ -1039,3 +1063,103 @@ func TestBaseURL_TrailingSlashNormalized(t *testing.T) {
 		t.Errorf("BaseURL = %q, want trailing slash removed", c.BaseURL)
 	}
 }
+
+func TestComplete_CachedTokensAndCacheKey(t *testing.T) {
+	var gotBody map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&gotBody)
+		writeJSON(w, oaiResponse{
+			Choices: []oaiChoice{{Message: oaiMessage{Role: "assistant", Content: "ok"}, FinishReason: "stop"}},
+			Usage: oaiUsage{
+				PromptTokens: 100, CompletionTokens: 5, TotalTokens: 105,
+				PromptTokensDetails: &oaiPromptTokenDetails{CachedTokens: 64},
+			},
+		})
+	}))
+	defer srv.Close()
+
+	p := newTestProvider(srv.URL)
+	resp, err := p.Complete(context.Background(), provider.CompletionRequest{
+		Messages: []provider.LLMMessage{
+			{Role: provider.MessageRoleSystem, Content: "stable", CacheControl: provider.CacheControlEphemeral},
+			{Role: provider.MessageRoleUser, Content: "Hi"},
+		},
+		CacheKey: "bot",
+	})
+	if err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+
+	if resp.Usage.CachedTokens != 64 {
+		t.Errorf("CachedTokens = %d, want 64", resp.Usage.CachedTokens)
+	}
+	// Neither cache option is enabled: the key is not sent and the system
+	// message stays a plain string.
+	if v, ok := gotBody["prompt_cache_key"]; ok {
+		t.Errorf("prompt_cache_key = %v, want it omitted by default", v)
+	}
+	msgs := gotBody["messages"].([]any)
+	if _, ok := msgs[0].(map[string]any)["content"].(string); !ok {
+		t.Errorf("expected string content when cache_control is disabled, got %v", msgs[0])
+	}
+}
+
+func TestComplete_PromptCacheKeyOptIn(t *testing.T) {
+	var gotBody map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&gotBody)
+		writeJSON(w, oaiResponse{
+			Choices: []oaiChoice{{Message: oaiMessage{Role: "assistant", Content: "ok"}, FinishReason: "stop"}},
+		})
+	}))
+	defer srv.Close()
+
+	p := newTestProvider(srv.URL)
+	p.config.PromptCacheKey = true
+	_, err := p.Complete(context.Background(), provider.CompletionRequest{
+		Messages: []provider.LLMMessage{{Role: provider.MessageRoleUser, Content: "Hi"}},
+		CacheKey: "bot",
+	})
+	if err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	if gotBody["prompt_cache_key"] != "bot" {
+		t.Errorf("prompt_cache_key = %v, want %q", gotBody["prompt_cache_key"], "bot")
+	}
+}
+
+func TestComplete_CacheControlBreakpoints(t *testing.T) {
+	var gotBody map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&gotBody)
+		writeJSON(w, oaiResponse{
+			Choices: []oaiChoice{{Message: oaiMessage{Role: "assistant", Content: "ok"}, FinishReason: "stop"}},
+		})
+	}))
+	defer srv.Close()
+
+	p := newTestProvider(srv.URL)
+	p.config.CacheControl = true
+	_, err := p.Complete(context.Background(), provider.CompletionRequest{
+		Messages: []provider.LLMMessage{
+			{Role: provider.MessageRoleSystem, Content: "stable", CacheControl: provider.CacheControlEphemeral},
+			{Role: provider.MessageRoleUser, Content: "Hi"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+
+	msgs := gotBody["messages"].([]any)
+	parts, ok := msgs[0].(map[string]any)["content"].([]any)
+	if !ok || len(parts) != 1 {
+		t.Fatalf("expected system content as a single part, got %v", msgs[0])
+	}
+	cc, ok := parts[0].(map[string]any)["cache_control"].(map[string]any)
+	if !ok || cc["type"] != "ephemeral" {
+		t.Errorf("cache_control = %v, want ephemeral", parts[0])
+	}
+	if _, ok := msgs[1].(map[string]any)["content"].(string); !ok {
+		t.Errorf("unmarked message should keep string content, got %v", msgs[1])
+	}
+}
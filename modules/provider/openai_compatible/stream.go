@@ -144,11 +144,8 @@ func (p *Provider) parseSSEStream(ctx context.Context, scanner *bufio.Scanner) <
 			sc := provider.StreamChunk{}
 
 			if chunk.Usage != nil {
-				sc.Usage = &provider.TokenUsage{
-					PromptTokens:     chunk.Usage.PromptTokens,
-					CompletionTokens: chunk.Usage.CompletionTokens,
-					TotalTokens:      chunk.Usage.TotalTokens,
-				}
+				usage := chunk.Usage.toTokenUsage()
+				sc.Usage = &usage
 			}
 
 			if len(chunk.Choices) > 0 {
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"net/url"
 	"time"
+
+	ctxengine "github.com/flemzord/sclaw/internal/context"
 )
 
 // Config holds the configuration for the OpenAI Responses API provider.
@@ -19,6 +21,10 @@ type Config struct {
 	Headers       map[string]string `yaml:"headers"`
 	DialTimeout   time.Duration     `yaml:"dial_timeout"`
 	ConnMaxAge    time.Duration     `yaml:"conn_max_age"`
+
+	// Tokenizer names the BPE encoding used for token estimation
+	// ("cl100k_base" or "o200k_base"). Empty infers it from the model name.
+	Tokenizer string `yaml:"tokenizer"`
 }
 
 // defaults sets default values for unset fields.
@@ -55,6 +61,9 @@ func (c *Config) validate() error {
 	if c.ContextWindow < 0 {
 		return fmt.Errorf("provider.openai_responses: context_window must not be negative")
 	}
+	if c.Tokenizer != "" && !ctxengine.IsKnownEncoding(c.Tokenizer) {
+		return fmt.Errorf("provider.openai_responses: unknown tokenizer %q", c.Tokenizer)
+	}
 	if c.MaxTokens < 0 {
 		return fmt.Errorf("provider.openai_responses: max_output_tokens must not be negative")
 	}
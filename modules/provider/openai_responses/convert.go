@@ -19,6 +19,7 @@ func buildClientEvent(cfg Config, req provider.CompletionRequest) clientEvent {
 	}
 	event.MaxOutputTokens = maxTokens
 	event.Temperature = req.Temperature
+	event.PromptCacheKey = req.CacheKey
 
 	// Convert messages to input items.
 	// System messages are extracted into Instructions.
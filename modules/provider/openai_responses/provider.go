@@ -129,6 +129,11 @@ func (p *Provider) ModelName() string {
 	return p.config.Model
 }
 
+// TokenizerName implements provider.TokenizerNamer.
+func (p *Provider) TokenizerName() string {
+	return p.config.Tokenizer
+}
+
 // HealthCheck implements provider.HealthChecker.
 // It probes the /models REST endpoint (same pattern as openai_compatible).
 func (p *Provider) HealthCheck(ctx context.Context) error {
@@ -219,11 +224,12 @@ func wsToHTTP(wsURL string) string {
 
 // Compile-time interface assertions.
 var (
-	_ core.Module            = (*Provider)(nil)
-	_ core.Configurable      = (*Provider)(nil)
-	_ core.Provisioner       = (*Provider)(nil)
-	_ core.Validator         = (*Provider)(nil)
-	_ core.Stopper           = (*Provider)(nil)
-	_ provider.Provider      = (*Provider)(nil)
-	_ provider.HealthChecker = (*Provider)(nil)
+	_ core.Module             = (*Provider)(nil)
+	_ core.Configurable       = (*Provider)(nil)
+	_ core.Provisioner        = (*Provider)(nil)
+	_ core.Validator          = (*Provider)(nil)
+	_ core.Stopper            = (*Provider)(nil)
+	_ provider.Provider       = (*Provider)(nil)
+	_ provider.HealthChecker  = (*Provider)(nil)
+	_ provider.TokenizerNamer = (*Provider)(nil)
 )
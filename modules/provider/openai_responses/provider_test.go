@@ -94,9 +94,10 @@ func TestCompleteSimpleText(t *testing.T) {
 					},
 				},
 				Usage: &wireUsage{
-					InputTokens:  10,
-					OutputTokens: 5,
-					TotalTokens:  15,
+					InputTokens:        10,
+					OutputTokens:       5,
+					TotalTokens:        15,
+					InputTokensDetails: &wireInputDetails{CachedTokens: 8},
 				},
 			},
 		})
@@ -124,6 +125,9 @@ func TestCompleteSimpleText(t *testing.T) {
 	if resp.Usage.TotalTokens != 15 {
 		t.Errorf("total_tokens = %d, want %d", resp.Usage.TotalTokens, 15)
 	}
+	if resp.Usage.CachedTokens != 8 {
+		t.Errorf("cached_tokens = %d, want %d", resp.Usage.CachedTokens, 8)
+	}
 }
 
 func TestConnManager_GetConn_AllowsMultipleActiveConnections(t *testing.T) {
@@ -164,6 +168,17 @@ func TestBuildClientEvent_SkipsEmptyUserInput(t *testing.T) {
 	}
 }
 
+func TestBuildClientEvent_PromptCacheKey(t *testing.T) {
+	event := buildClientEvent(testConfig("ws://example.invalid/v1/responses"), provider.CompletionRequest{
+		Messages: []provider.LLMMessage{{Role: provider.MessageRoleUser, Content: "hi"}},
+		CacheKey: "bot",
+	})
+
+	if event.PromptCacheKey != "bot" {
+		t.Errorf("PromptCacheKey = %q, want %q", event.PromptCacheKey, "bot")
+	}
+}
+
 func TestBuildClientEvent_FiltersEmptyTextParts(t *testing.T) {
 	event := buildClientEvent(testConfig("ws://example.invalid/v1/responses"), provider.CompletionRequest{
 		Messages: []provider.LLMMessage{
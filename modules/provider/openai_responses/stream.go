@@ -143,6 +143,9 @@ func readLoop(ctx context.Context, conn *websocket.Conn) <-chan provider.StreamC
 						CompletionTokens: event.Response.Usage.OutputTokens,
 						TotalTokens:      event.Response.Usage.TotalTokens,
 					}
+					if details := event.Response.Usage.InputTokensDetails; details != nil {
+						final.Usage.CachedTokens = details.CachedTokens
+					}
 				}
 
 				emit(ctx, ch, final)
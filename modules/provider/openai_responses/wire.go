@@ -17,6 +17,7 @@ type clientEvent struct {
 	MaxOutputTokens int             `json:"max_output_tokens,omitempty"`
 	Store           *bool           `json:"store,omitempty"`
 	Metadata        json.RawMessage `json:"metadata,omitempty"`
+	PromptCacheKey  string          `json:"prompt_cache_key,omitempty"`
 }
 
 // inputItem is a polymorphic item in the conversation input.
@@ -118,9 +119,15 @@ type responseCompleted struct {
 
 // wireUsage carries token usage information.
 type wireUsage struct {
-	InputTokens  int `json:"input_tokens"`
-	OutputTokens int `json:"output_tokens"`
-	TotalTokens  int `json:"total_tokens"`
+	InputTokens        int               `json:"input_tokens"`
+	OutputTokens       int               `json:"output_tokens"`
+	TotalTokens        int               `json:"total_tokens"`
+	InputTokensDetails *wireInputDetails `json:"input_tokens_details,omitempty"`
+}
+
+// wireInputDetails breaks down input token usage.
+type wireInputDetails struct {
+	CachedTokens int `json:"cached_tokens"`
 }
 
 // serverError is the payload of an "error" event.
@@ -0,0 +1,110 @@
+package modelrouter
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/flemzord/sclaw/internal/provider"
+	"gopkg.in/yaml.v3"
+)
+
+// Config holds the configuration for the routing provider.
+type Config struct {
+	// Default names the target used when no rule or classifier label
+	// matches. Defaults to the first target.
+	Default    string            `yaml:"default"`
+	Targets    []TargetConfig    `yaml:"targets"`
+	Rules      []RuleConfig      `yaml:"rules"`
+	Classifier *ClassifierConfig `yaml:"classifier"`
+}
+
+// TargetConfig declares a provider the router can dispatch to. The provider
+// is instantiated from Module with Config, independently of any top-level
+// instance of the same module.
+type TargetConfig struct {
+	Name   string        `yaml:"name"`
+	Module string        `yaml:"module"`
+	Role   provider.Role `yaml:"role"`
+	Config yaml.Node     `yaml:"config"`
+}
+
+// RuleConfig is the YAML form of provider.RouteRule.
+type RuleConfig struct {
+	Name       string   `yaml:"name"`
+	Target     string   `yaml:"target"`
+	HasImages  *bool    `yaml:"has_images"`
+	MinTools   int      `yaml:"min_tools"`
+	MaxTools   *int     `yaml:"max_tools"`
+	MinChars   int      `yaml:"min_chars"`
+	MaxChars   *int     `yaml:"max_chars"`
+	Keywords   []string `yaml:"keywords"`
+	Agents     []string `yaml:"agents"`
+	TimeWindow string   `yaml:"time_window"`
+}
+
+// ClassifierConfig is the YAML form of provider.RouteClassifier.
+type ClassifierConfig struct {
+	Target  string            `yaml:"target"`
+	Labels  map[string]string `yaml:"labels"`
+	Prompt  string            `yaml:"prompt"`
+	Timeout time.Duration     `yaml:"timeout"`
+}
+
+// validate checks the parts of the configuration that do not require the
+// target providers to be instantiated.
+func (c *Config) validate() error {
+	if len(c.Targets) == 0 {
+		return fmt.Errorf("provider.router: at least one target is required")
+	}
+	for i, t := range c.Targets {
+		if t.Name == "" {
+			return fmt.Errorf("provider.router: target #%d: name is required", i+1)
+		}
+		if !strings.HasPrefix(t.Module, "provider.") || t.Module == moduleID {
+			return fmt.Errorf("provider.router: target %q: module must be a provider module other than %s, got %q", t.Name, moduleID, t.Module)
+		}
+		switch t.Role {
+		case "", provider.RolePrimary, provider.RoleInternal:
+		default:
+			return fmt.Errorf("provider.router: target %q: role must be primary or internal, got %q", t.Name, t.Role)
+		}
+	}
+	for i, r := range c.Rules {
+		if r.MinTools < 0 || r.MinChars < 0 || (r.MaxTools != nil && *r.MaxTools < 0) || (r.MaxChars != nil && *r.MaxChars < 0) {
+			return fmt.Errorf("provider.router: rule #%d: bounds must not be negative", i+1)
+		}
+	}
+	if c.Classifier != nil && c.Classifier.Timeout < 0 {
+		return fmt.Errorf("provider.router: classifier timeout must not be negative")
+	}
+	return nil
+}
+
+// routerConfig converts the YAML rules and classifier to provider types.
+func (c *Config) routerConfig() provider.RouterConfig {
+	cfg := provider.RouterConfig{Default: c.Default}
+	for _, r := range c.Rules {
+		cfg.Rules = append(cfg.Rules, provider.RouteRule{
+			Name:       r.Name,
+			Target:     r.Target,
+			HasImages:  r.HasImages,
+			MinTools:   r.MinTools,
+			MaxTools:   r.MaxTools,
+			MinChars:   r.MinChars,
+			MaxChars:   r.MaxChars,
+			Keywords:   r.Keywords,
+			Agents:     r.Agents,
+			TimeWindow: r.TimeWindow,
+		})
+	}
+	if cl := c.Classifier; cl != nil {
+		cfg.Classifier = &provider.RouteClassifier{
+			Target:  cl.Target,
+			Labels:  cl.Labels,
+			Prompt:  cl.Prompt,
+			Timeout: cl.Timeout,
+		}
+	}
+	return cfg
+}
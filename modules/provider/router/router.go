@@ -0,0 +1,167 @@
+// Package modelrouter provides a provider module that routes each request to
+// one of several underlying providers, based on declarative rules or a small
+// classifier call. Because it is itself a provider.Provider, it can be used
+// anywhere a single provider is expected.
+package modelrouter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/flemzord/sclaw/internal/core"
+	"github.com/flemzord/sclaw/internal/provider"
+	"gopkg.in/yaml.v3"
+)
+
+const moduleID = "provider.router"
+
+func init() {
+	core.RegisterModule(&Provider{})
+}
+
+// Provider is a routing provider module.
+type Provider struct {
+	config  Config
+	router  *provider.Router
+	modules []core.Module
+}
+
+// Compile-time interface checks.
+var (
+	_ core.Module             = (*Provider)(nil)
+	_ core.Configurable       = (*Provider)(nil)
+	_ core.Provisioner        = (*Provider)(nil)
+	_ core.Validator          = (*Provider)(nil)
+	_ core.Stopper            = (*Provider)(nil)
+	_ provider.Provider       = (*Provider)(nil)
+	_ provider.HealthChecker  = (*Provider)(nil)
+	_ provider.TokenizerNamer = (*Provider)(nil)
+)
+
+// ModuleInfo implements core.Module.
+func (p *Provider) ModuleInfo() core.ModuleInfo {
+	return core.ModuleInfo{
+		ID:  moduleID,
+		New: func() core.Module { return &Provider{} },
+	}
+}
+
+// Configure implements core.Configurable.
+func (p *Provider) Configure(node *yaml.Node) error {
+	return node.Decode(&p.config)
+}
+
+// Provision implements core.Provisioner. It instantiates every target
+// provider with its inline configuration and builds the router.
+func (p *Provider) Provision(ctx *core.AppContext) error {
+	if err := p.config.validate(); err != nil {
+		return err
+	}
+
+	cfg := p.config.routerConfig()
+	cfg.Logger = ctx.Logger
+	for _, t := range p.config.Targets {
+		target, mod, err := loadTarget(ctx, t)
+		if mod != nil {
+			p.modules = append(p.modules, mod)
+		}
+		if err != nil {
+			return err
+		}
+		cfg.Targets = append(cfg.Targets, target)
+	}
+
+	r, err := provider.NewRouter(cfg)
+	if err != nil {
+		return fmt.Errorf("provider.router: %w", err)
+	}
+	p.router = r
+
+	ctx.RegisterService(moduleID, p)
+	return nil
+}
+
+// loadTarget runs the module lifecycle for a target provider. Targets get a
+// private AppContext so that their service registrations do not collide
+// with top-level provider modules.
+func loadTarget(ctx *core.AppContext, t TargetConfig) (provider.RouteTarget, core.Module, error) {
+	info, ok := core.GetModule(t.Module)
+	if !ok {
+		return provider.RouteTarget{}, nil, fmt.Errorf("provider.router: target %q: unknown module %s", t.Name, t.Module)
+	}
+	mod := info.New()
+
+	if c, ok := mod.(core.Configurable); ok && t.Config.Kind != 0 {
+		if err := c.Configure(&t.Config); err != nil {
+			return provider.RouteTarget{}, mod, fmt.Errorf("provider.router: configuring target %q: %w", t.Name, err)
+		}
+	}
+	if pr, ok := mod.(core.Provisioner); ok {
+		targetCtx := core.NewAppContext(ctx.Logger.With("target", t.Name), ctx.DataDir, ctx.Workspace)
+		if err := pr.Provision(targetCtx); err != nil {
+			return provider.RouteTarget{}, mod, fmt.Errorf("provider.router: provisioning target %q: %w", t.Name, err)
+		}
+	}
+	if v, ok := mod.(core.Validator); ok {
+		if err := v.Validate(); err != nil {
+			return provider.RouteTarget{}, mod, fmt.Errorf("provider.router: validating target %q: %w", t.Name, err)
+		}
+	}
+
+	prov, ok := mod.(provider.Provider)
+	if !ok {
+		return provider.RouteTarget{}, mod, fmt.Errorf("provider.router: target %q: module %s is not a provider", t.Name, t.Module)
+	}
+	return provider.RouteTarget{Name: t.Name, Provider: prov, Role: t.Role}, mod, nil
+}
+
+// Validate implements core.Validator.
+func (p *Provider) Validate() error {
+	if p.router == nil {
+		return fmt.Errorf("provider.router: not provisioned")
+	}
+	return nil
+}
+
+// Stop implements core.Stopper. It stops the target providers.
+func (p *Provider) Stop(ctx context.Context) error {
+	var errs []error
+	for _, mod := range p.modules {
+		if s, ok := mod.(core.Stopper); ok {
+			errs = append(errs, s.Stop(ctx))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Complete implements provider.Provider.
+func (p *Provider) Complete(ctx context.Context, req provider.CompletionRequest) (provider.CompletionResponse, error) {
+	return p.router.Complete(ctx, req)
+}
+
+// Stream implements provider.Provider.
+func (p *Provider) Stream(ctx context.Context, req provider.CompletionRequest) (<-chan provider.StreamChunk, error) {
+	return p.router.Stream(ctx, req)
+}
+
+// ContextWindowSize implements provider.Provider.
+func (p *Provider) ContextWindowSize() int {
+	return p.router.ContextWindowSize()
+}
+
+// ModelName implements provider.Provider.
+func (p *Provider) ModelName() string {
+	return p.router.ModelName()
+}
+
+// HealthCheck implements provider.HealthChecker.
+func (p *Provider) HealthCheck(ctx context.Context) error {
+	return p.router.HealthCheck(ctx)
+}
+
+// TokenizerName implements provider.TokenizerNamer by reporting the
+// tokenizer of the default target.
+func (p *Provider) TokenizerName() string {
+	return p.router.TokenizerName()
+}
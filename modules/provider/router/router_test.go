@@ -0,0 +1,193 @@
+package modelrouter
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/flemzord/sclaw/internal/core"
+	"github.com/flemzord/sclaw/internal/provider"
+	"gopkg.in/yaml.v3"
+)
+
+// fakeProvider is a provider module used as a routing target in tests.
+type fakeProvider struct {
+	Model   string `yaml:"model"`
+	stopped bool
+}
+
+func (f *fakeProvider) ModuleInfo() core.ModuleInfo {
+	return core.ModuleInfo{
+		ID:  "provider.routertest",
+		New: func() core.Module { return &fakeProvider{} },
+	}
+}
+
+func (f *fakeProvider) Configure(node *yaml.Node) error { return node.Decode(f) }
+
+func (f *fakeProvider) Provision(ctx *core.AppContext) error {
+	ctx.RegisterService("provider.routertest", f)
+	return nil
+}
+
+func (f *fakeProvider) Stop(context.Context) error {
+	f.stopped = true
+	return nil
+}
+
+func (f *fakeProvider) Complete(context.Context, provider.CompletionRequest) (provider.CompletionResponse, error) {
+	return provider.CompletionResponse{Content: "served by " + f.Model}, nil
+}
+
+func (f *fakeProvider) Stream(context.Context, provider.CompletionRequest) (<-chan provider.StreamChunk, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (f *fakeProvider) ContextWindowSize() int { return 8192 }
+func (f *fakeProvider) ModelName() string      { return f.Model }
+
+func init() {
+	core.RegisterModule(&fakeProvider{})
+}
+
+func configure(t *testing.T, data string) *Provider {
+	t.Helper()
+	var node yaml.Node
+	if err := yaml.Unmarshal([]byte(data), &node); err != nil {
+		t.Fatalf("unmarshal yaml: %v", err)
+	}
+	p := &Provider{}
+	if err := p.Configure(node.Content[0]); err != nil {
+		t.Fatalf("Configure: %v", err)
+	}
+	return p
+}
+
+func newAppContext() *core.AppContext {
+	return core.NewAppContext(slog.New(slog.NewTextHandler(io.Discard, nil)), "", "")
+}
+
+func TestProvision_RoutesToTargets(t *testing.T) {
+	t.Parallel()
+
+	p := configure(t, `
+default: fast
+targets:
+  - name: fast
+    module: provider.routertest
+    config:
+      model: small-model
+  - name: smart
+    module: provider.routertest
+    config:
+      model: big-model
+rules:
+  - name: long
+    target: smart
+    min_chars: 20
+    max_tools: 5
+`)
+	appCtx := newAppContext()
+	if err := p.Provision(appCtx); err != nil {
+		t.Fatalf("Provision: %v", err)
+	}
+	if err := p.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+
+	// Targets register their services on a private context.
+	if _, ok := appCtx.GetService("provider.routertest"); ok {
+		t.Error("target service leaked into the application context")
+	}
+	if _, ok := appCtx.GetService(moduleID); !ok {
+		t.Error("router service not registered")
+	}
+
+	resp, err := p.Complete(context.Background(), provider.CompletionRequest{
+		Messages: []provider.LLMMessage{{Role: provider.MessageRoleUser, Content: strings.Repeat("x", 30)}},
+	})
+	if err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	if resp.Model != "big-model" || resp.Content != "served by big-model" {
+		t.Errorf("resp = %+v, want big-model", resp)
+	}
+	if p.ModelName() != "small-model" {
+		t.Errorf("ModelName = %q, want small-model", p.ModelName())
+	}
+
+	if err := p.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+	for _, mod := range p.modules {
+		if !mod.(*fakeProvider).stopped {
+			t.Error("target provider not stopped")
+		}
+	}
+}
+
+func TestProvision_Errors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		yaml    string
+		wantErr string
+	}{
+		{"no targets", `default: a`, "at least one target"},
+		{"missing name", `
+targets:
+  - module: provider.routertest
+`, "name is required"},
+		{"self reference", `
+targets:
+  - name: a
+    module: provider.router
+`, "must be a provider module"},
+		{"non provider module", `
+targets:
+  - name: a
+    module: channel.telegram
+`, "must be a provider module"},
+		{"unknown module", `
+targets:
+  - name: a
+    module: provider.nope
+`, "unknown module"},
+		{"bad role", `
+targets:
+  - name: a
+    module: provider.routertest
+    role: fallback
+`, "role must be"},
+		{"unknown rule target", `
+targets:
+  - name: a
+    module: provider.routertest
+rules:
+  - target: b
+`, "rule #1"},
+		{"negative bound", `
+targets:
+  - name: a
+    module: provider.routertest
+rules:
+  - target: a
+    max_chars: -1
+`, "must not be negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			p := configure(t, tt.yaml)
+			err := p.Provision(newAppContext())
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Provision error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package codeinterpreter
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/flemzord/sclaw/internal/core"
+	"github.com/flemzord/sclaw/internal/tool"
+	"gopkg.in/yaml.v3"
+)
+
+func init() {
+	core.RegisterModule(&Module{})
+}
+
+// Compile-time interface guards.
+var (
+	_ core.Configurable = (*Module)(nil)
+	_ core.Provisioner  = (*Module)(nil)
+	_ core.Validator    = (*Module)(nil)
+	_ core.Stopper      = (*Module)(nil)
+	_ tool.Provider     = (*Module)(nil)
+)
+
+// Module implements the code interpreter tool module.
+type Module struct {
+	config  Config
+	kernels *kernelManager
+	tool    *interpreterTool
+}
+
+// ModuleInfo implements core.Module.
+func (m *Module) ModuleInfo() core.ModuleInfo {
+	return core.ModuleInfo{
+		ID:  "tool.code_interpreter",
+		New: func() core.Module { return &Module{} },
+	}
+}
+
+// Configure implements core.Configurable.
+func (m *Module) Configure(node *yaml.Node) error {
+	if err := node.Decode(&m.config); err != nil {
+		return fmt.Errorf("code_interpreter: decode config: %w", err)
+	}
+	return nil
+}
+
+// Provision implements core.Provisioner.
+func (m *Module) Provision(ctx *core.AppContext) error {
+	m.config.defaults()
+
+	var logger *slog.Logger
+	if ctx != nil {
+		logger = ctx.Logger
+	}
+	m.kernels = newKernelManager(m.config, logger)
+	m.tool = &interpreterTool{
+		kernels:   m.kernels,
+		timeout:   m.config.timeoutDuration(),
+		maxOutput: m.config.MaxOutputSize,
+		outputDir: m.config.OutputDir,
+		policy:    tool.ApprovalLevel(m.config.DefaultPolicy),
+	}
+	return nil
+}
+
+// Validate implements core.Validator.
+func (m *Module) Validate() error {
+	return m.config.validate()
+}
+
+// Stop implements core.Stopper. It kills the running interpreters.
+func (m *Module) Stop(_ context.Context) error {
+	if m.kernels != nil {
+		m.kernels.stop()
+	}
+	return nil
+}
+
+// Tools implements tool.Provider.
+func (m *Module) Tools() []tool.Tool {
+	return []tool.Tool{m.tool}
+}
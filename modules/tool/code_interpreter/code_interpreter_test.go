@@ -0,0 +1,309 @@
+package codeinterpreter
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/flemzord/sclaw/internal/tool"
+)
+
+func newTestModule(t *testing.T, mutate func(*Config)) *Module {
+	t.Helper()
+	m := &Module{}
+	m.config.defaults()
+	m.config.Timeout = "10s"
+	if mutate != nil {
+		mutate(&m.config)
+	}
+	if err := m.Provision(nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = m.Stop(context.Background()) })
+	return m
+}
+
+func requireCommand(t *testing.T, name string) {
+	t.Helper()
+	if _, err := exec.LookPath(name); err != nil {
+		t.Skipf("%s not available", name)
+	}
+}
+
+func testEnv(t *testing.T, session string) tool.ExecutionEnv {
+	t.Helper()
+	return tool.ExecutionEnv{
+		Workspace: t.TempDir(),
+		SessionID: session,
+	}
+}
+
+func run(t *testing.T, m *Module, env tool.ExecutionEnv, args interpreterArgs) tool.Output {
+	t.Helper()
+	raw, err := json.Marshal(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := m.tool.Execute(context.Background(), raw, env)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return out
+}
+
+func TestModuleInfo(t *testing.T) {
+	t.Parallel()
+
+	m := &Module{}
+	if got := m.ModuleInfo().ID; got != "tool.code_interpreter" {
+		t.Errorf("ID = %q", got)
+	}
+	m.config.defaults()
+	_ = m.Provision(nil)
+	tools := m.Tools()
+	if len(tools) != 1 || tools[0].Name() != "code_interpreter" {
+		t.Fatalf("Tools() = %v", tools)
+	}
+	if scopes := tools[0].Scopes(); len(scopes) != 1 || scopes[0] != tool.ScopeExec {
+		t.Errorf("Scopes() = %v", scopes)
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		mutate func(*Config)
+	}{
+		{"bad timeout", func(c *Config) { c.Timeout = "soon" }},
+		{"zero idle timeout", func(c *Config) { c.IdleTimeout = "0s" }},
+		{"negative memory", func(c *Config) { c.MemoryMB = -1 }},
+		{"negative cpu shares", func(c *Config) { c.CPUShares = -1 }},
+		{"negative max kernels", func(c *Config) { c.MaxKernels = -1 }},
+		{"absolute output dir", func(c *Config) { c.OutputDir = "/tmp/out" }},
+		{"escaping output dir", func(c *Config) { c.OutputDir = "../out" }},
+		{"bad policy", func(c *Config) { c.DefaultPolicy = "maybe" }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var c Config
+			c.defaults()
+			tt.mutate(&c)
+			if err := c.validate(); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+
+	var c Config
+	c.defaults()
+	if err := c.validate(); err != nil {
+		t.Errorf("defaults: %v", err)
+	}
+}
+
+func TestExecute_InvalidArguments(t *testing.T) {
+	t.Parallel()
+
+	m := newTestModule(t, nil)
+	env := testEnv(t, "s1")
+	if out := run(t, m, env, interpreterArgs{Code: "  "}); !out.IsError {
+		t.Error("empty code should fail")
+	}
+	if out := run(t, m, env, interpreterArgs{Code: "1", Language: "ruby"}); !out.IsError {
+		t.Error("unknown language should fail")
+	}
+}
+
+func TestPython_KeepsStateBetweenCalls(t *testing.T) {
+	t.Parallel()
+	requireCommand(t, "python3")
+
+	m := newTestModule(t, nil)
+	env := testEnv(t, "s1")
+
+	out := run(t, m, env, interpreterArgs{Code: "import math\nx = 21\nprint('hello')"})
+	if out.IsError || out.Content != "[stdout]\nhello\n" {
+		t.Fatalf("first call = %+v", out)
+	}
+	out = run(t, m, env, interpreterArgs{Code: "x * 2"})
+	if out.IsError || out.Content != "[result]\n42\n" {
+		t.Fatalf("second call = %+v", out)
+	}
+
+	// Another session has its own interpreter.
+	other := testEnv(t, "s2")
+	out = run(t, m, other, interpreterArgs{Code: "x"})
+	if !out.IsError || !strings.Contains(out.Content, "NameError") {
+		t.Fatalf("other session = %+v", out)
+	}
+
+	// Reset discards the state.
+	out = run(t, m, env, interpreterArgs{Code: "x", Reset: true})
+	if !out.IsError || !strings.Contains(out.Content, "NameError") {
+		t.Fatalf("after reset = %+v", out)
+	}
+}
+
+func TestPython_ErrorKeepsInterpreter(t *testing.T) {
+	t.Parallel()
+	requireCommand(t, "python3")
+
+	m := newTestModule(t, nil)
+	env := testEnv(t, "s1")
+
+	out := run(t, m, env, interpreterArgs{Code: "y = 1\nprint('before')\n1/0"})
+	if !out.IsError {
+		t.Fatal("expected error")
+	}
+	for _, want := range []string{"[stdout]\nbefore", "[error]", "ZeroDivisionError"} {
+		if !strings.Contains(out.Content, want) {
+			t.Errorf("output missing %q:\n%s", want, out.Content)
+		}
+	}
+	if strings.Contains(out.Content, "kernel.py") {
+		t.Errorf("traceback leaks the driver:\n%s", out.Content)
+	}
+
+	out = run(t, m, env, interpreterArgs{Code: "y"})
+	if out.IsError || out.Content != "[result]\n1\n" {
+		t.Fatalf("state lost after error: %+v", out)
+	}
+}
+
+func TestPython_TimeoutRestartsInterpreter(t *testing.T) {
+	t.Parallel()
+	requireCommand(t, "python3")
+
+	m := newTestModule(t, nil)
+	env := testEnv(t, "s1")
+
+	run(t, m, env, interpreterArgs{Code: "z = 1"})
+	out := run(t, m, env, interpreterArgs{Code: "import time\ntime.sleep(30)", TimeoutSeconds: 1})
+	if !out.IsError || !strings.Contains(out.Content, "timed out after 1s") {
+		t.Fatalf("timeout = %+v", out)
+	}
+	out = run(t, m, env, interpreterArgs{Code: "z"})
+	if !out.IsError || !strings.Contains(out.Content, "NameError") {
+		t.Fatalf("state should be lost after timeout: %+v", out)
+	}
+}
+
+func TestPython_ExitIsReported(t *testing.T) {
+	t.Parallel()
+	requireCommand(t, "python3")
+
+	m := newTestModule(t, nil)
+	env := testEnv(t, "s1")
+
+	out := run(t, m, env, interpreterArgs{Code: "import os\nos._exit(3)"})
+	if !out.IsError || !strings.Contains(out.Content, "interpreter exited") {
+		t.Fatalf("exit = %+v", out)
+	}
+	out = run(t, m, env, interpreterArgs{Code: "1 + 1"})
+	if out.IsError || out.Content != "[result]\n2\n" {
+		t.Fatalf("after exit = %+v", out)
+	}
+}
+
+func TestPython_GeneratedFiles(t *testing.T) {
+	t.Parallel()
+	requireCommand(t, "python3")
+
+	m := newTestModule(t, nil)
+	env := testEnv(t, "s1")
+
+	code := `import os
+with open(os.path.join(OUTPUT_DIR, "chart.png"), "wb") as f:
+    f.write(b"\x89PNG\r\n\x1a\n")
+with open(os.path.join(OUTPUT_DIR, "data.csv"), "w") as f:
+    f.write("a,b\n1,2\n")`
+	out := run(t, m, env, interpreterArgs{Code: code})
+	if out.IsError {
+		t.Fatalf("unexpected error: %s", out.Content)
+	}
+	if !strings.Contains(out.Content, "[files]\noutputs/chart.png\noutputs/data.csv") {
+		t.Errorf("files not listed:\n%s", out.Content)
+	}
+	if len(out.Blocks) != 1 {
+		t.Fatalf("blocks = %d, want 1", len(out.Blocks))
+	}
+	want := "file://" + filepath.Join(env.Workspace, "outputs", "chart.png")
+	if out.Blocks[0].URL != want || out.Blocks[0].MIMEType != "image/png" {
+		t.Errorf("block = %+v, want %s", out.Blocks[0], want)
+	}
+
+	// Unchanged files are not reported again.
+	out = run(t, m, env, interpreterArgs{Code: "None"})
+	if out.Content != "(no output)" || len(out.Blocks) != 0 {
+		t.Errorf("second call = %+v", out)
+	}
+}
+
+func TestJavaScript_KeepsStateBetweenCalls(t *testing.T) {
+	t.Parallel()
+	requireCommand(t, "node")
+
+	m := newTestModule(t, nil)
+	env := testEnv(t, "s1")
+
+	out := run(t, m, env, interpreterArgs{Code: "var n = 20; console.log('hi')", Language: "javascript"})
+	if out.IsError || out.Content != "[stdout]\nhi\n" {
+		t.Fatalf("first call = %+v", out)
+	}
+	out = run(t, m, env, interpreterArgs{Code: "(async () => n + 22)()", Language: "javascript"})
+	if out.IsError || out.Content != "[result]\n42\n" {
+		t.Fatalf("second call = %+v", out)
+	}
+	out = run(t, m, env, interpreterArgs{Code: "missing()", Language: "js"})
+	if !out.IsError || !strings.Contains(out.Content, "ReferenceError") {
+		t.Fatalf("error = %+v", out)
+	}
+}
+
+func TestMaxKernels_EvictsIdle(t *testing.T) {
+	t.Parallel()
+	requireCommand(t, "python3")
+
+	m := newTestModule(t, func(c *Config) { c.MaxKernels = 1 })
+	a, b := testEnv(t, "a"), testEnv(t, "b")
+
+	run(t, m, a, interpreterArgs{Code: "v = 1"})
+	if out := run(t, m, b, interpreterArgs{Code: "2"}); out.IsError {
+		t.Fatalf("second session = %+v", out)
+	}
+	// The first session's interpreter was evicted to make room.
+	out := run(t, m, a, interpreterArgs{Code: "v"})
+	if !out.IsError || !strings.Contains(out.Content, "NameError") {
+		t.Fatalf("evicted session = %+v", out)
+	}
+}
+
+func TestStop_KillsInterpreters(t *testing.T) {
+	t.Parallel()
+	requireCommand(t, "python3")
+
+	m := newTestModule(t, nil)
+	env := testEnv(t, "s1")
+	out := run(t, m, env, interpreterArgs{Code: "import os\nos.getpid()"})
+	if out.IsError {
+		t.Fatal(out.Content)
+	}
+	_ = m.Stop(context.Background())
+	if len(m.kernels.kernels) != 0 {
+		t.Error("interpreters left after Stop")
+	}
+
+	if _, err := os.Stat(filepath.Join(env.Workspace, "outputs")); err != nil {
+		t.Errorf("output directory not created: %v", err)
+	}
+}
@@ -0,0 +1,130 @@
+// Package codeinterpreter implements a code execution tool backed by
+// persistent Python and JavaScript interpreters, one per session.
+package codeinterpreter
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+)
+
+const (
+	defaultPythonCommand = "python3"
+	defaultNodeCommand   = "node"
+	defaultTimeout       = 60 * time.Second
+	defaultIdleTimeout   = 30 * time.Minute
+	defaultMemoryMB      = 512
+	defaultMaxKernels    = 8
+	defaultMaxOutput     = 64 << 10 // 64 KiB
+	defaultOutputDir     = "outputs"
+)
+
+// Config holds the tool.code_interpreter module configuration.
+type Config struct {
+	// PythonCommand starts the Python interpreter. Defaults to "python3".
+	PythonCommand string `yaml:"python_command"`
+
+	// NodeCommand starts the JavaScript interpreter. Defaults to "node".
+	NodeCommand string `yaml:"node_command"`
+
+	// Timeout is the maximum duration of one execution (e.g. "60s").
+	// Defaults to 60s.
+	Timeout string `yaml:"timeout"`
+
+	// IdleTimeout is how long an unused interpreter keeps its state before
+	// it is stopped (e.g. "30m"). Defaults to 30m.
+	IdleTimeout string `yaml:"idle_timeout"`
+
+	// MemoryMB is the memory limit of each interpreter in megabytes.
+	// Defaults to 512.
+	MemoryMB int `yaml:"memory_mb"`
+
+	// CPUShares is the relative CPU weight of each interpreter. Only
+	// enforced by the sandbox; 0 keeps the sandbox value.
+	CPUShares int `yaml:"cpu_shares"`
+
+	// MaxKernels is the number of interpreters kept running across all
+	// sessions. Defaults to 8.
+	MaxKernels int `yaml:"max_kernels"`
+
+	// MaxOutputSize is the max output returned per execution in bytes.
+	// Defaults to 64 KiB.
+	MaxOutputSize int `yaml:"max_output_size"`
+
+	// OutputDir is the workspace directory where generated files are
+	// written, relative to the workspace. Defaults to "outputs".
+	OutputDir string `yaml:"output_dir"`
+
+	// DefaultPolicy is the default approval level: "allow", "ask", or "deny". Defaults to "allow".
+	DefaultPolicy string `yaml:"default_policy"`
+}
+
+func (c *Config) defaults() {
+	if c.PythonCommand == "" {
+		c.PythonCommand = defaultPythonCommand
+	}
+	if c.NodeCommand == "" {
+		c.NodeCommand = defaultNodeCommand
+	}
+	if c.Timeout == "" {
+		c.Timeout = defaultTimeout.String()
+	}
+	if c.IdleTimeout == "" {
+		c.IdleTimeout = defaultIdleTimeout.String()
+	}
+	if c.MemoryMB == 0 {
+		c.MemoryMB = defaultMemoryMB
+	}
+	if c.MaxKernels == 0 {
+		c.MaxKernels = defaultMaxKernels
+	}
+	if c.MaxOutputSize == 0 {
+		c.MaxOutputSize = defaultMaxOutput
+	}
+	if c.OutputDir == "" {
+		c.OutputDir = defaultOutputDir
+	}
+	if c.DefaultPolicy == "" {
+		c.DefaultPolicy = "allow"
+	}
+}
+
+func (c *Config) validate() error {
+	if d, err := time.ParseDuration(c.Timeout); err != nil || d <= 0 {
+		return fmt.Errorf("code_interpreter: invalid timeout %q", c.Timeout)
+	}
+	if d, err := time.ParseDuration(c.IdleTimeout); err != nil || d <= 0 {
+		return fmt.Errorf("code_interpreter: invalid idle_timeout %q", c.IdleTimeout)
+	}
+	if c.MemoryMB <= 0 {
+		return fmt.Errorf("code_interpreter: memory_mb must be positive, got %d", c.MemoryMB)
+	}
+	if c.CPUShares < 0 {
+		return fmt.Errorf("code_interpreter: cpu_shares must not be negative, got %d", c.CPUShares)
+	}
+	if c.MaxKernels <= 0 {
+		return fmt.Errorf("code_interpreter: max_kernels must be positive, got %d", c.MaxKernels)
+	}
+	if c.MaxOutputSize <= 0 {
+		return fmt.Errorf("code_interpreter: max_output_size must be positive, got %d", c.MaxOutputSize)
+	}
+	if !filepath.IsLocal(c.OutputDir) {
+		return fmt.Errorf("code_interpreter: output_dir %q must be a relative path inside the workspace", c.OutputDir)
+	}
+	switch c.DefaultPolicy {
+	case "allow", "ask", "deny":
+	default:
+		return fmt.Errorf("code_interpreter: invalid default_policy %q (must be allow, ask, or deny)", c.DefaultPolicy)
+	}
+	return nil
+}
+
+func (c *Config) timeoutDuration() time.Duration {
+	d, _ := time.ParseDuration(c.Timeout)
+	return d
+}
+
+func (c *Config) idleTimeoutDuration() time.Duration {
+	d, _ := time.ParseDuration(c.IdleTimeout)
+	return d
+}
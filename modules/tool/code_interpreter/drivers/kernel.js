@@ -0,0 +1,92 @@
+// Persistent JavaScript kernel for the sclaw code_interpreter tool.
+//
+// Reads one JSON request per line on stdin ({"code": "..."}) and answers each
+// with one line on stdout: the token from SCLAW_KERNEL_TOKEN followed by a
+// JSON object. Anything else written to stdout comes from user code.
+'use strict';
+
+const readline = require('readline');
+const util = require('util');
+const vm = require('vm');
+
+const TOKEN = process.env.SCLAW_KERNEL_TOKEN || '';
+const MAX_OUTPUT = parseInt(process.env.SCLAW_MAX_OUTPUT || '65536', 10);
+delete process.env.SCLAW_KERNEL_TOKEN;
+delete process.env.SCLAW_MAX_OUTPUT;
+
+let stdout = [];
+let stderr = [];
+const format = (args) => util.formatWithOptions({ colors: false }, ...args);
+const out = (lines, text) => {
+  lines.push(text);
+};
+const cellConsole = {
+  log: (...args) => out(stdout, format(args)),
+  info: (...args) => out(stdout, format(args)),
+  debug: (...args) => out(stdout, format(args)),
+  table: (data) => out(stdout, util.inspect(data, { depth: 2 })),
+  dir: (obj) => out(stdout, util.inspect(obj, { depth: 4 })),
+  warn: (...args) => out(stderr, format(args)),
+  error: (...args) => out(stderr, format(args)),
+};
+
+const context = vm.createContext({
+  console: cellConsole,
+  require,
+  process,
+  Buffer,
+  URL,
+  TextEncoder,
+  TextDecoder,
+  setTimeout,
+  clearTimeout,
+  setInterval,
+  clearInterval,
+  OUTPUT_DIR: process.env.OUTPUT_DIR || process.cwd(),
+});
+
+function clip(text) {
+  return text.length <= MAX_OUTPUT ? text : text.slice(0, MAX_OUTPUT) + '\n...(truncated)';
+}
+
+function joinLines(lines) {
+  return lines.length ? lines.join('\n') + '\n' : '';
+}
+
+async function run(code) {
+  stdout = [];
+  stderr = [];
+  let result = '';
+  let error = '';
+  try {
+    let value = vm.runInContext(code, context, { filename: 'cell.js' });
+    if (value && typeof value.then === 'function') {
+      value = await value;
+    }
+    if (value !== undefined) {
+      result = util.inspect(value, { depth: 4 });
+    }
+  } catch (err) {
+    error = err && err.stack ? String(err.stack) : String(err);
+  }
+  return {
+    stdout: clip(joinLines(stdout)),
+    stderr: clip(joinLines(stderr)),
+    result: clip(result),
+    error: clip(error),
+  };
+}
+
+(async () => {
+  const requests = readline.createInterface({ input: process.stdin, terminal: false });
+  for await (const line of requests) {
+    let request;
+    try {
+      request = JSON.parse(line);
+    } catch {
+      continue;
+    }
+    const response = await run(String(request.code || ''));
+    process.stdout.write(TOKEN + JSON.stringify(response) + '\n');
+  }
+})();
@@ -0,0 +1,78 @@
+# Persistent Python kernel for the sclaw code_interpreter tool.
+#
+# Reads one JSON request per line on stdin ({"code": "..."}) and answers each
+# with one line on stdout: the token from SCLAW_KERNEL_TOKEN followed by a
+# JSON object. Anything else written to stdout comes from user code.
+import ast
+import contextlib
+import io
+import json
+import os
+import sys
+import traceback
+
+TOKEN = os.environ.pop("SCLAW_KERNEL_TOKEN", "")
+MAX_OUTPUT = int(os.environ.pop("SCLAW_MAX_OUTPUT", "65536"))
+OUTPUT_DIR = os.environ.get("OUTPUT_DIR", os.getcwd())
+os.environ.setdefault("MPLBACKEND", "Agg")
+
+requests = sys.stdin
+sys.stdin = open(os.devnull)
+namespace = {"__name__": "__main__", "OUTPUT_DIR": OUTPUT_DIR}
+figure_count = 0
+
+
+def clip(text):
+    if len(text) <= MAX_OUTPUT:
+        return text
+    return text[:MAX_OUTPUT] + "\n...(truncated)"
+
+
+def save_figures():
+    """Save open matplotlib figures to OUTPUT_DIR, like an inline backend."""
+    global figure_count
+    plt = sys.modules.get("matplotlib.pyplot")
+    if plt is None:
+        return
+    for num in plt.get_fignums():
+        figure_count += 1
+        plt.figure(num).savefig(os.path.join(OUTPUT_DIR, "figure-%d.png" % figure_count), bbox_inches="tight")
+    plt.close("all")
+
+
+def run(code):
+    stdout, stderr = io.StringIO(), io.StringIO()
+    result = error = None
+    with contextlib.redirect_stdout(stdout), contextlib.redirect_stderr(stderr):
+        try:
+            tree = ast.parse(code, "<cell>", "exec")
+            last = None
+            if tree.body and isinstance(tree.body[-1], ast.Expr):
+                last = ast.Expression(tree.body.pop().value)
+            exec(compile(tree, "<cell>", "exec"), namespace)
+            if last is not None:
+                value = eval(compile(last, "<cell>", "eval"), namespace)
+                if value is not None:
+                    result = repr(value)
+        except BaseException as exc:  # noqa: BLE001 - report everything, keep the kernel alive
+            error = "".join(traceback.format_exception(type(exc), exc, exc.__traceback__.tb_next))
+        try:
+            save_figures()
+        except Exception:  # noqa: BLE001
+            error = (error or "") + traceback.format_exc()
+    return {
+        "stdout": clip(stdout.getvalue()),
+        "stderr": clip(stderr.getvalue()),
+        "result": clip(result) if result is not None else "",
+        "error": clip(error) if error else "",
+    }
+
+
+for line in requests:
+    try:
+        request = json.loads(line)
+    except ValueError:
+        continue
+    response = run(request.get("code", ""))
+    sys.__stdout__.write(TOKEN + json.dumps(response) + "\n")
+    sys.__stdout__.flush()
@@ -0,0 +1,437 @@
+package codeinterpreter
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	_ "embed"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/flemzord/sclaw/internal/security"
+	"github.com/flemzord/sclaw/internal/tool"
+)
+
+// Supported languages.
+const (
+	langPython     = "python"
+	langJavaScript = "javascript"
+)
+
+//go:embed drivers/kernel.py
+var pythonDriver string
+
+//go:embed drivers/kernel.js
+var nodeDriver string
+
+const (
+	// reapInterval is how often idle interpreters are looked for.
+	reapInterval = time.Minute
+
+	// maxStrayOutput caps what the interpreter process writes outside of
+	// replies (subprocesses writing to the inherited descriptors).
+	maxStrayOutput = 64 << 10
+
+	// killGrace bounds the wait for the output pipes once the interpreter
+	// has exited, when a process it spawned still holds them.
+	killGrace = 5 * time.Second
+)
+
+var (
+	// errKernelExited is returned when the interpreter process ends while
+	// running code.
+	errKernelExited = errors.New("the interpreter exited")
+
+	// errTooManyKernels is returned when every interpreter slot is busy.
+	errTooManyKernels = errors.New("too many interpreters running; try again later")
+)
+
+// reply is the result of one execution, as written by the drivers.
+type reply struct {
+	Stdout string `json:"stdout"`
+	Stderr string `json:"stderr"`
+	Result string `json:"result"`
+	Error  string `json:"error"`
+}
+
+// kernelKey identifies the interpreter of one session and language.
+type kernelKey struct {
+	session string
+	lang    string
+}
+
+// kernel is a running interpreter process driven over its stdin and stdout.
+type kernel struct {
+	key    kernelKey
+	token  string
+	stdin  io.WriteCloser
+	lines  chan string // reply and stray stdout lines; closed on exit
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	stderr strayBuffer
+	stray  strings.Builder // stdout lines that are not replies
+
+	run sync.Mutex // serializes executions
+
+	// Guarded by kernelManager.mu.
+	inUse    int
+	lastUsed time.Time
+}
+
+// execute runs code and waits for its reply. On timeout or cancellation the
+// caller must kill the kernel: its state is unknown.
+func (k *kernel) execute(ctx context.Context, code string, timeout time.Duration) (reply, error) {
+	k.run.Lock()
+	defer k.run.Unlock()
+
+	k.stray.Reset()
+	req, err := json.Marshal(map[string]string{"code": code})
+	if err != nil {
+		return reply{}, err
+	}
+	if _, err := k.stdin.Write(append(req, '\n')); err != nil {
+		return reply{}, errKernelExited
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	for {
+		select {
+		case line, ok := <-k.lines:
+			if !ok {
+				return reply{}, errKernelExited
+			}
+			payload, found := strings.CutPrefix(line, k.token)
+			if !found {
+				if k.stray.Len() < maxStrayOutput {
+					k.stray.WriteString(line + "\n")
+				}
+				continue
+			}
+			var r reply
+			if err := json.Unmarshal([]byte(payload), &r); err != nil {
+				return reply{}, fmt.Errorf("malformed reply from interpreter: %w", err)
+			}
+			r.Stdout = k.stray.String() + r.Stdout
+			r.Stderr = k.stderr.take() + r.Stderr
+			return r, nil
+		case <-timer.C:
+			return reply{}, context.DeadlineExceeded
+		case <-ctx.Done():
+			return reply{}, ctx.Err()
+		}
+	}
+}
+
+// output returns what the process wrote outside of replies, for reports on
+// a kernel that exited or timed out.
+func (k *kernel) output() string {
+	return k.stray.String() + k.stderr.take()
+}
+
+// kill stops the process and waits for it to exit.
+func (k *kernel) kill() {
+	_ = k.stdin.Close()
+	k.cancel()
+	<-k.done
+}
+
+// strayBuffer collects the interpreter's stderr up to maxStrayOutput.
+type strayBuffer struct {
+	mu  sync.Mutex
+	buf []byte
+}
+
+func (b *strayBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if room := maxStrayOutput - len(b.buf); room > 0 {
+		b.buf = append(b.buf, p[:min(len(p), room)]...)
+	}
+	return len(p), nil
+}
+
+// take returns the collected output and empties the buffer.
+func (b *strayBuffer) take() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	s := string(b.buf)
+	b.buf = b.buf[:0]
+	return s
+}
+
+// kernelManager starts interpreters on demand and keeps them per session
+// and language until they are idle for too long.
+type kernelManager struct {
+	pythonCommand string
+	nodeCommand   string
+	idleTimeout   time.Duration
+	maxKernels    int
+	maxOutput     int
+	limits        security.ResourceLimits
+	logger        *slog.Logger
+
+	mu      sync.Mutex
+	kernels map[kernelKey]*kernel
+	reaping bool
+	stopped chan struct{}
+}
+
+func newKernelManager(cfg Config, logger *slog.Logger) *kernelManager {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &kernelManager{
+		pythonCommand: cfg.PythonCommand,
+		nodeCommand:   cfg.NodeCommand,
+		idleTimeout:   cfg.idleTimeoutDuration(),
+		maxKernels:    cfg.MaxKernels,
+		maxOutput:     cfg.MaxOutputSize,
+		limits:        security.ResourceLimits{MemoryMB: cfg.MemoryMB, CPUShares: cfg.CPUShares},
+		logger:        logger,
+		kernels:       make(map[kernelKey]*kernel),
+		stopped:       make(chan struct{}),
+	}
+}
+
+// acquire returns the interpreter of the session of env for lang, starting
+// one if needed. The caller must call done when the execution ends.
+func (m *kernelManager) acquire(env tool.ExecutionEnv, lang, outputDir string) (*kernel, error) {
+	key := kernelKey{session: env.SessionID, lang: lang}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if k, ok := m.kernels[key]; ok {
+		select {
+		case <-k.done:
+			delete(m.kernels, key)
+		default:
+			k.inUse++
+			return k, nil
+		}
+	}
+	if len(m.kernels) >= m.maxKernels && !m.evictLocked() {
+		return nil, errTooManyKernels
+	}
+
+	k, err := m.start(env, key, outputDir)
+	if err != nil {
+		return nil, err
+	}
+	k.inUse++
+	m.kernels[key] = k
+	if !m.reaping {
+		m.reaping = true
+		go m.reap()
+	}
+	return k, nil
+}
+
+// done marks the end of an execution on k.
+func (m *kernelManager) done(k *kernel) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	k.inUse--
+	k.lastUsed = time.Now()
+}
+
+// start launches the interpreter process for key. Callers hold m.mu.
+func (m *kernelManager) start(env tool.ExecutionEnv, key kernelKey, outputDir string) (*kernel, error) {
+	var command, source string
+	switch key.lang {
+	case langPython:
+		command, source = m.pythonCommand+` -u -c "$SCLAW_KERNEL_SOURCE"`, pythonDriver
+	default:
+		command, source = m.nodeCommand+` -e "$SCLAW_KERNEL_SOURCE"`, nodeDriver
+	}
+	// The data limit stands in for the sandbox memory limit when commands
+	// run unsandboxed.
+	command = "ulimit -d " + strconv.Itoa(m.limits.MemoryMB*1024) + " 2>/dev/null; exec " + command
+
+	token, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+	vars := env.SanitizedEnv
+	if vars == nil {
+		vars = security.SanitizedEnv(nil)
+	}
+	env.SanitizedEnv = append(vars[:len(vars):len(vars)],
+		"SCLAW_KERNEL_SOURCE="+source,
+		"SCLAW_KERNEL_TOKEN="+token,
+		"SCLAW_MAX_OUTPUT="+strconv.Itoa(m.maxOutput),
+		"OUTPUT_DIR="+outputDir,
+		"MPLBACKEND=Agg",
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	limits := m.limits
+	cmd, release, err := tool.ShellCommandWith(ctx, env, command, tool.CommandOptions{Interactive: true, Limits: &limits})
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	k := &kernel{
+		key:      key,
+		token:    token,
+		lines:    make(chan string),
+		cancel:   cancel,
+		done:     make(chan struct{}),
+		lastUsed: time.Now(),
+	}
+	cmd.Stderr = &k.stderr
+	cmd.WaitDelay = killGrace
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		release()
+		cancel()
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		release()
+		cancel()
+		return nil, err
+	}
+	k.stdin = stdin
+	if err := cmd.Start(); err != nil {
+		release()
+		cancel()
+		return nil, fmt.Errorf("starting %s interpreter: %w", key.lang, err)
+	}
+
+	go func() {
+		// Wait must not run before stdout has been read to the end.
+		k.readLines(ctx, stdout, m.maxOutput)
+		_ = cmd.Wait()
+		release()
+		cancel()
+		close(k.done)
+	}()
+	return k, nil
+}
+
+// readLines forwards the process's stdout line by line until it ends or
+// the process is killed.
+func (k *kernel) readLines(ctx context.Context, stdout io.Reader, maxOutput int) {
+	defer close(k.lines)
+	scanner := bufio.NewScanner(stdout)
+	// A reply holds four fields each capped at maxOutput by the driver, and
+	// JSON escaping can grow them.
+	scanner.Buffer(make([]byte, 64<<10), 8*maxOutput+64<<10)
+	for scanner.Scan() {
+		select {
+		case k.lines <- scanner.Text():
+		case <-ctx.Done():
+			return
+		}
+	}
+	// Unreadable output (an oversized line) leaves the protocol out of
+	// sync: stop the process.
+	if scanner.Err() != nil {
+		k.cancel()
+	}
+}
+
+// evictLocked stops the least recently used idle interpreter. It reports
+// false when every interpreter is busy. Callers hold m.mu.
+func (m *kernelManager) evictLocked() bool {
+	var oldest *kernel
+	for _, k := range m.kernels {
+		if k.inUse == 0 && (oldest == nil || k.lastUsed.Before(oldest.lastUsed)) {
+			oldest = k
+		}
+	}
+	if oldest == nil {
+		return false
+	}
+	delete(m.kernels, oldest.key)
+	go oldest.kill()
+	return true
+}
+
+// discard stops the interpreter k, for timeouts, crashes and resets.
+func (m *kernelManager) discard(k *kernel) {
+	m.mu.Lock()
+	if m.kernels[k.key] == k {
+		delete(m.kernels, k.key)
+	}
+	m.mu.Unlock()
+	k.kill()
+}
+
+// reset stops the interpreter of session for lang, if any.
+func (m *kernelManager) reset(session, lang string) {
+	m.mu.Lock()
+	k, ok := m.kernels[kernelKey{session: session, lang: lang}]
+	m.mu.Unlock()
+	if ok {
+		m.discard(k)
+	}
+}
+
+// reap stops interpreters idle for longer than the idle timeout until the
+// manager is stopped.
+func (m *kernelManager) reap() {
+	ticker := time.NewTicker(min(reapInterval, m.idleTimeout))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-m.stopped:
+			return
+		case now := <-ticker.C:
+			m.mu.Lock()
+			var idle []*kernel
+			for key, k := range m.kernels {
+				if k.inUse == 0 && now.Sub(k.lastUsed) >= m.idleTimeout {
+					delete(m.kernels, key)
+					idle = append(idle, k)
+				}
+			}
+			m.mu.Unlock()
+			for _, k := range idle {
+				m.logger.Debug("code_interpreter: stopping idle interpreter", "session", k.key.session, "language", k.key.lang)
+				k.kill()
+			}
+		}
+	}
+}
+
+// stop kills every interpreter, for module shutdown.
+func (m *kernelManager) stop() {
+	m.mu.Lock()
+	kernels := make([]*kernel, 0, len(m.kernels))
+	for _, k := range m.kernels {
+		kernels = append(kernels, k)
+	}
+	clear(m.kernels)
+	select {
+	case <-m.stopped:
+	default:
+		close(m.stopped)
+	}
+	m.mu.Unlock()
+
+	for _, k := range kernels {
+		k.kill()
+	}
+}
+
+// newToken returns the random prefix that marks replies on stdout.
+func newToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "\x1esclaw:" + hex.EncodeToString(b) + ":", nil
+}
@@ -0,0 +1,242 @@
+package codeinterpreter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/flemzord/sclaw/internal/tool"
+	"github.com/flemzord/sclaw/pkg/message"
+)
+
+const (
+	// maxScannedFiles bounds the output directory scan that detects
+	// generated files.
+	maxScannedFiles = 1000
+
+	// maxImages caps the images attached to one reply.
+	maxImages = 10
+)
+
+// imageTypes are the generated files sent to the chat as images.
+var imageTypes = map[string]string{
+	".png":  "image/png",
+	".jpg":  "image/jpeg",
+	".jpeg": "image/jpeg",
+	".gif":  "image/gif",
+	".webp": "image/webp",
+}
+
+type interpreterTool struct {
+	kernels   *kernelManager
+	timeout   time.Duration
+	maxOutput int
+	outputDir string
+	policy    tool.ApprovalLevel
+}
+
+func (t *interpreterTool) Name() string { return "code_interpreter" }
+func (t *interpreterTool) Description() string {
+	return "Run Python or JavaScript code in a persistent interpreter: variables, imports and functions are kept " +
+		"between calls in the same conversation. The value of the last expression is returned. " +
+		"Files written to OUTPUT_DIR are reported, and images (e.g. matplotlib figures, saved automatically) are sent to the user."
+}
+func (t *interpreterTool) Scopes() []tool.Scope {
+	return []tool.Scope{tool.ScopeExec}
+}
+
+func (t *interpreterTool) DefaultPolicy() tool.ApprovalLevel {
+	return t.policy
+}
+
+func (t *interpreterTool) Schema() json.RawMessage {
+	return json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"code": {"type": "string", "description": "Code to run."},
+			"language": {"type": "string", "enum": ["python", "javascript"], "description": "Interpreter to use (default: python)."},
+			"reset": {"type": "boolean", "description": "Start from a fresh interpreter, discarding previous state."},
+			"timeout_seconds": {"type": "integer", "description": "Optional execution timeout in seconds (default and max: the configured timeout)."}
+		},
+		"required": ["code"]
+	}`)
+}
+
+type interpreterArgs struct {
+	Code           string `json:"code"`
+	Language       string `json:"language,omitempty"`
+	Reset          bool   `json:"reset,omitempty"`
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty"`
+}
+
+func (t *interpreterTool) Execute(ctx context.Context, args json.RawMessage, env tool.ExecutionEnv) (tool.Output, error) {
+	var a interpreterArgs
+	if err := json.Unmarshal(args, &a); err != nil {
+		return tool.Output{Content: fmt.Sprintf("invalid arguments: %v", err), IsError: true}, nil
+	}
+	if strings.TrimSpace(a.Code) == "" {
+		return tool.Output{Content: "code is empty", IsError: true}, nil
+	}
+	switch a.Language {
+	case "", langPython:
+		a.Language = langPython
+	case langJavaScript, "js", "node":
+		a.Language = langJavaScript
+	default:
+		return tool.Output{Content: fmt.Sprintf("unsupported language %q (must be python or javascript)", a.Language), IsError: true}, nil
+	}
+	if env.Workspace == "" {
+		return tool.Output{Content: "no workspace configured", IsError: true}, nil
+	}
+
+	timeout := t.timeout
+	if a.TimeoutSeconds > 0 {
+		timeout = min(time.Duration(a.TimeoutSeconds)*time.Second, t.timeout)
+	}
+
+	outputDir := filepath.Join(env.Workspace, t.outputDir)
+	if err := os.MkdirAll(outputDir, 0o755); err != nil {
+		return tool.Output{Content: fmt.Sprintf("cannot create output directory: %v", err), IsError: true}, nil
+	}
+
+	if a.Reset {
+		t.kernels.reset(env.SessionID, a.Language)
+	}
+	k, err := t.kernels.acquire(env, a.Language, outputDir)
+	if err != nil {
+		return tool.Output{Content: err.Error(), IsError: true}, nil
+	}
+	defer t.kernels.done(k)
+
+	before := scanFiles(outputDir)
+	r, err := k.execute(ctx, a.Code, timeout)
+	if err != nil {
+		t.kernels.discard(k)
+		return tool.Output{Content: t.failure(err, timeout, k.output()), IsError: true}, nil
+	}
+
+	files := changedFiles(before, scanFiles(outputDir))
+	out := tool.Output{
+		Content: t.format(r, env.Workspace, files),
+		IsError: r.Error != "",
+	}
+	for _, path := range files {
+		if len(out.Blocks) == maxImages {
+			break
+		}
+		if mimeType, ok := imageTypes[strings.ToLower(filepath.Ext(path))]; ok {
+			out.Blocks = append(out.Blocks, message.NewImageBlock("file://"+path, mimeType))
+		}
+	}
+	return out, nil
+}
+
+// failure explains an execution that did not complete. The interpreter has
+// been stopped, so its state is gone.
+func (t *interpreterTool) failure(err error, timeout time.Duration, output string) string {
+	var msg string
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		msg = fmt.Sprintf("execution timed out after %s; the interpreter was restarted and its state was lost", timeout)
+	case errors.Is(err, context.Canceled):
+		msg = "execution was cancelled; the interpreter was restarted and its state was lost"
+	case errors.Is(err, errKernelExited):
+		msg = "the interpreter exited (out of memory or killed?); its state was lost"
+	default:
+		msg = err.Error() + "; the interpreter was restarted and its state was lost"
+	}
+	if output = strings.TrimSpace(output); output != "" {
+		msg += "\n" + truncate(output, t.maxOutput)
+	}
+	return msg
+}
+
+// format renders a reply as labelled sections followed by generated files.
+func (t *interpreterTool) format(r reply, workspace string, files []string) string {
+	var b strings.Builder
+	section := func(name, text string) {
+		if text == "" {
+			return
+		}
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString("[" + name + "]\n" + strings.TrimRight(text, "\n") + "\n")
+	}
+	section("stdout", r.Stdout)
+	section("stderr", r.Stderr)
+	section("result", r.Result)
+	section("error", r.Error)
+
+	if len(files) > 0 {
+		var names []string
+		for _, path := range files {
+			rel, err := filepath.Rel(workspace, path)
+			if err != nil {
+				rel = path
+			}
+			names = append(names, rel)
+		}
+		section("files", strings.Join(names, "\n"))
+	}
+	if b.Len() == 0 {
+		return "(no output)"
+	}
+	return truncate(b.String(), t.maxOutput)
+}
+
+// fileState identifies a version of a file.
+type fileState struct {
+	size    int64
+	modTime time.Time
+}
+
+// scanFiles lists the regular files under dir.
+func scanFiles(dir string) map[string]fileState {
+	files := make(map[string]fileState)
+	_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		if len(files) >= maxScannedFiles {
+			return filepath.SkipAll
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		files[path] = fileState{size: info.Size(), modTime: info.ModTime()}
+		return nil
+	})
+	return files
+}
+
+// changedFiles returns the files of after that are new or changed since
+// before, sorted.
+func changedFiles(before, after map[string]fileState) []string {
+	var changed []string
+	for path, state := range after {
+		if prev, ok := before[path]; !ok || prev != state {
+			changed = append(changed, path)
+		}
+	}
+	slices.Sort(changed)
+	return changed
+}
+
+func truncate(s string, limit int) string {
+	if len(s) <= limit {
+		return s
+	}
+	return s[:limit] + "\n... (output truncated)"
+}
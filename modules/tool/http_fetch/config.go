@@ -0,0 +1,102 @@
+// Package httpfetch provides the http_fetch tool, which lets agents read web
+// pages and call HTTP APIs. Requests honor the security URL filter, refuse
+// private and link-local destinations after DNS resolution, and HTML
+// responses are converted to Markdown.
+package httpfetch
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	defaultTimeout       = 30 * time.Second
+	defaultMaxTimeout    = 2 * time.Minute
+	defaultMaxBodySize   = 5 << 20 // 5 MiB
+	defaultMaxOutputSize = 100_000 // bytes of text returned to the agent
+	defaultMaxRedirects  = 5
+	defaultUserAgent     = "sclaw-http-fetch/1.0"
+)
+
+// Config holds the tool.http_fetch module configuration.
+type Config struct {
+	// Timeout is the default request timeout (e.g. "30s"). Defaults to 30s.
+	Timeout string `yaml:"timeout"`
+
+	// MaxTimeout is the maximum allowed timeout (e.g. "2m"). Defaults to 2m.
+	MaxTimeout string `yaml:"max_timeout"`
+
+	// MaxBodySize is the maximum response body read in bytes. Defaults to 5 MiB.
+	MaxBodySize int `yaml:"max_body_size"`
+
+	// MaxOutputSize is the maximum text returned to the agent in bytes.
+	// Defaults to 100000.
+	MaxOutputSize int `yaml:"max_output_size"`
+
+	// MaxRedirects is the maximum number of redirects followed. Defaults to 5.
+	MaxRedirects int `yaml:"max_redirects"`
+
+	// UserAgent is sent with every request. Defaults to "sclaw-http-fetch/1.0".
+	UserAgent string `yaml:"user_agent"`
+
+	// DefaultPolicy is the default approval level: "allow", "ask", or "deny". Defaults to "ask".
+	DefaultPolicy string `yaml:"default_policy"`
+}
+
+func (c *Config) defaults() {
+	if c.Timeout == "" {
+		c.Timeout = defaultTimeout.String()
+	}
+	if c.MaxTimeout == "" {
+		c.MaxTimeout = defaultMaxTimeout.String()
+	}
+	if c.MaxBodySize == 0 {
+		c.MaxBodySize = defaultMaxBodySize
+	}
+	if c.MaxOutputSize == 0 {
+		c.MaxOutputSize = defaultMaxOutputSize
+	}
+	if c.MaxRedirects == 0 {
+		c.MaxRedirects = defaultMaxRedirects
+	}
+	if c.UserAgent == "" {
+		c.UserAgent = defaultUserAgent
+	}
+	if c.DefaultPolicy == "" {
+		c.DefaultPolicy = "ask"
+	}
+}
+
+func (c *Config) validate() error {
+	if _, err := time.ParseDuration(c.Timeout); err != nil {
+		return fmt.Errorf("http_fetch: invalid timeout %q: %w", c.Timeout, err)
+	}
+	if _, err := time.ParseDuration(c.MaxTimeout); err != nil {
+		return fmt.Errorf("http_fetch: invalid max_timeout %q: %w", c.MaxTimeout, err)
+	}
+	if c.MaxBodySize <= 0 {
+		return fmt.Errorf("http_fetch: max_body_size must be positive, got %d", c.MaxBodySize)
+	}
+	if c.MaxOutputSize <= 0 {
+		return fmt.Errorf("http_fetch: max_output_size must be positive, got %d", c.MaxOutputSize)
+	}
+	if c.MaxRedirects < 0 {
+		return fmt.Errorf("http_fetch: max_redirects must not be negative, got %d", c.MaxRedirects)
+	}
+	switch c.DefaultPolicy {
+	case "allow", "ask", "deny":
+	default:
+		return fmt.Errorf("http_fetch: invalid default_policy %q (must be allow, ask, or deny)", c.DefaultPolicy)
+	}
+	return nil
+}
+
+func (c *Config) timeoutDuration() time.Duration {
+	d, _ := time.ParseDuration(c.Timeout)
+	return d
+}
+
+func (c *Config) maxTimeoutDuration() time.Duration {
+	d, _ := time.ParseDuration(c.MaxTimeout)
+	return d
+}
@@ -0,0 +1,311 @@
+package httpfetch
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+	"unicode/utf8"
+
+	"github.com/flemzord/sclaw/internal/security"
+	"github.com/flemzord/sclaw/internal/tool"
+	"golang.org/x/net/html/charset"
+)
+
+// errBlockedAddr is returned when a destination resolves to an address that
+// agents must not reach.
+var errBlockedAddr = errors.New("http_fetch: destination address not allowed")
+
+// blockedPrefixes are special-purpose ranges not covered by the netip
+// predicates used in checkPublicAddr.
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),      // "this" network
+	netip.MustParsePrefix("100.64.0.0/10"),  // carrier-grade NAT
+	netip.MustParsePrefix("192.0.0.0/24"),   // IETF protocol assignments
+	netip.MustParsePrefix("198.18.0.0/15"),  // benchmarking
+	netip.MustParsePrefix("240.0.0.0/4"),    // reserved
+	netip.MustParsePrefix("64:ff9b::/96"),   // NAT64, may embed private IPv4
+	netip.MustParsePrefix("64:ff9b:1::/48"), // local-use NAT64
+	netip.MustParsePrefix("2001:db8::/32"),  // documentation
+	netip.MustParsePrefix("fec0::/10"),      // deprecated site-local
+	netip.MustParsePrefix("2002::/16"),      // 6to4, may embed private IPv4
+	netip.MustParsePrefix("2001::/32"),      // Teredo
+	netip.MustParsePrefix("100::/64"),       // discard-only
+}
+
+// checkPublicAddr rejects loopback, private, link-local, multicast and other
+// non-public addresses. It runs on the address actually dialed, after DNS
+// resolution, so a public name pointing to an internal address is refused.
+func checkPublicAddr(addr netip.Addr) error {
+	addr = addr.Unmap()
+	if addr.IsLoopback() || addr.IsPrivate() || addr.IsUnspecified() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() || addr.IsMulticast() {
+		return fmt.Errorf("%w: %s", errBlockedAddr, addr)
+	}
+	for _, p := range blockedPrefixes {
+		if p.Contains(addr) {
+			return fmt.Errorf("%w: %s", errBlockedAddr, addr)
+		}
+	}
+	return nil
+}
+
+type fetchTool struct {
+	cfg       Config
+	transport *http.Transport
+	limiter   *security.RateLimiter
+}
+
+// newFetchTool builds the tool. guard vets every dialed address; limiter,
+// if non-nil, enforces the per-session network rate limit.
+func newFetchTool(cfg Config, guard func(netip.Addr) error, limiter *security.RateLimiter) *fetchTool {
+	dialer := &net.Dialer{
+		Timeout: 10 * time.Second,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			ap, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return fmt.Errorf("%w: %s", errBlockedAddr, address)
+			}
+			return guard(ap.Addr())
+		},
+	}
+	return &fetchTool{
+		cfg:     cfg,
+		limiter: limiter,
+		transport: &http.Transport{
+			// Proxies from the environment would dial on our behalf and
+			// bypass the address guard.
+			Proxy:                 nil,
+			DialContext:           dialer.DialContext,
+			ForceAttemptHTTP2:     true,
+			MaxIdleConns:          16,
+			IdleConnTimeout:       90 * time.Second,
+			TLSHandshakeTimeout:   10 * time.Second,
+			ResponseHeaderTimeout: 30 * time.Second,
+		},
+	}
+}
+
+func (t *fetchTool) Name() string { return "http_fetch" }
+
+func (t *fetchTool) Description() string {
+	return "Fetch a URL over HTTP(S). HTML pages are returned as Markdown; other text content is returned as-is."
+}
+
+func (t *fetchTool) Scopes() []tool.Scope {
+	return []tool.Scope{tool.ScopeNetwork}
+}
+
+func (t *fetchTool) DefaultPolicy() tool.ApprovalLevel {
+	return tool.ApprovalLevel(t.cfg.DefaultPolicy)
+}
+
+func (t *fetchTool) Schema() json.RawMessage {
+	return json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"url": {"type": "string", "description": "Absolute http or https URL."},
+			"method": {"type": "string", "enum": ["GET", "POST"], "description": "HTTP method (default GET)."},
+			"headers": {"type": "object", "additionalProperties": {"type": "string"}, "description": "Optional request headers."},
+			"body": {"type": "string", "description": "Request body for POST. Sent as JSON when it is valid JSON, as plain text otherwise, unless a Content-Type header is given."},
+			"raw": {"type": "boolean", "description": "Return HTML as-is instead of converting it to Markdown."},
+			"timeout_seconds": {"type": "integer", "description": "Optional timeout in seconds."}
+		},
+		"required": ["url"]
+	}`)
+}
+
+type fetchArgs struct {
+	URL            string            `json:"url"`
+	Method         string            `json:"method,omitempty"`
+	Headers        map[string]string `json:"headers,omitempty"`
+	Body           string            `json:"body,omitempty"`
+	Raw            bool              `json:"raw,omitempty"`
+	TimeoutSeconds int               `json:"timeout_seconds,omitempty"`
+}
+
+func (t *fetchTool) Execute(ctx context.Context, args json.RawMessage, env tool.ExecutionEnv) (tool.Output, error) {
+	var a fetchArgs
+	if err := json.Unmarshal(args, &a); err != nil {
+		return tool.Output{Content: fmt.Sprintf("invalid arguments: %v", err), IsError: true}, nil
+	}
+
+	method := strings.ToUpper(cmp.Or(a.Method, http.MethodGet))
+	if method != http.MethodGet && method != http.MethodPost {
+		return tool.Output{Content: fmt.Sprintf("unsupported method %q (GET or POST)", a.Method), IsError: true}, nil
+	}
+	if err := checkURL(a.URL, env.URLFilter); err != nil {
+		return tool.Output{Content: err.Error(), IsError: true}, nil
+	}
+	if t.limiter != nil {
+		if err := t.limiter.Allow(env.SessionID, "network"); err != nil {
+			return tool.Output{Content: err.Error(), IsError: true}, nil
+		}
+	}
+
+	timeout := t.cfg.timeoutDuration()
+	if a.TimeoutSeconds > 0 {
+		timeout = min(time.Duration(a.TimeoutSeconds)*time.Second, t.cfg.maxTimeoutDuration())
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var body io.Reader
+	if a.Body != "" {
+		if method != http.MethodPost {
+			return tool.Output{Content: "body is only allowed with POST", IsError: true}, nil
+		}
+		body = strings.NewReader(a.Body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, a.URL, body)
+	if err != nil {
+		return tool.Output{Content: fmt.Sprintf("invalid request: %v", err), IsError: true}, nil
+	}
+	req.Header.Set("User-Agent", t.cfg.UserAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/json,text/plain;q=0.9,*/*;q=0.8")
+	if a.Body != "" {
+		if json.Valid([]byte(a.Body)) {
+			req.Header.Set("Content-Type", "application/json")
+		} else {
+			req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+		}
+	}
+	for k, v := range a.Headers {
+		req.Header.Set(k, v)
+	}
+
+	client := &http.Client{
+		Transport: t.transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) > t.cfg.MaxRedirects {
+				return fmt.Errorf("stopped after %d redirects", t.cfg.MaxRedirects)
+			}
+			return checkURL(req.URL.String(), env.URLFilter)
+		},
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return tool.Output{Content: fmt.Sprintf("request failed: %v", err), IsError: true}, nil
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, int64(t.cfg.MaxBodySize)+1))
+	if err != nil {
+		return tool.Output{Content: fmt.Sprintf("reading response: %v", err), IsError: true}, nil
+	}
+	truncatedBody := len(data) > t.cfg.MaxBodySize
+	if truncatedBody {
+		data = data[:t.cfg.MaxBodySize]
+	}
+
+	content := t.render(resp, data, a.Raw)
+	if truncatedBody {
+		content += fmt.Sprintf("\n\n[response truncated at %d bytes]", t.cfg.MaxBodySize)
+	}
+	return tool.Output{
+		Content: truncateOutput(content, t.cfg.MaxOutputSize),
+		IsError: resp.StatusCode >= 400,
+	}, nil
+}
+
+// checkURL validates the scheme and applies the URL filter when one is
+// configured.
+func checkURL(rawURL string, filter *security.URLFilter) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || !u.IsAbs() {
+		return fmt.Errorf("invalid URL %q: must be absolute", rawURL)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("scheme %q not allowed (only http and https)", u.Scheme)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("invalid URL %q: missing host", rawURL)
+	}
+	if u.User != nil {
+		return errors.New("URLs with credentials are not allowed; use an Authorization header")
+	}
+	if filter != nil {
+		return filter.Check(rawURL)
+	}
+	return nil
+}
+
+// render formats the response for the agent: a short header followed by the
+// body as Markdown (HTML), text, or a placeholder for binary content.
+func (t *fetchTool) render(resp *http.Response, data []byte, raw bool) string {
+	mediaType, params, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType == "" {
+		mediaType = http.DetectContentType(data)
+		mediaType, params, _ = mime.ParseMediaType(mediaType)
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "URL: %s\nStatus: %s\n", resp.Request.URL, resp.Status)
+
+	switch {
+	case (mediaType == "text/html" || mediaType == "application/xhtml+xml") && !raw:
+		text := decodeText(data, mediaType, params)
+		doc := htmlToMarkdown(text, resp.Request.URL)
+		if doc.Title != "" {
+			fmt.Fprintf(&sb, "Title: %s\n", doc.Title)
+		}
+		sb.WriteString("\n")
+		sb.WriteString(doc.Markdown)
+	case isTextual(mediaType):
+		fmt.Fprintf(&sb, "Content-Type: %s\n\n", mediaType)
+		sb.WriteString(decodeText(data, mediaType, params))
+	default:
+		fmt.Fprintf(&sb, "Content-Type: %s\n\n[binary content, %d bytes, not shown]", mediaType, len(data))
+	}
+	return sb.String()
+}
+
+// isTextual reports whether a media type can be shown as text.
+func isTextual(mediaType string) bool {
+	return strings.HasPrefix(mediaType, "text/") ||
+		mediaType == "application/json" || strings.HasSuffix(mediaType, "+json") ||
+		mediaType == "application/xml" || strings.HasSuffix(mediaType, "+xml") ||
+		mediaType == "application/javascript" || mediaType == "application/x-yaml" ||
+		mediaType == "application/yaml"
+}
+
+// decodeText converts data to UTF-8 using the declared or sniffed charset.
+func decodeText(data []byte, mediaType string, params map[string]string) string {
+	contentType := mediaType
+	if cs := params["charset"]; cs != "" {
+		contentType += "; charset=" + cs
+	}
+	r, err := charset.NewReader(bytes.NewReader(data), contentType)
+	if err != nil {
+		return strings.ToValidUTF8(string(data), "�")
+	}
+	decoded, err := io.ReadAll(r)
+	if err != nil {
+		return strings.ToValidUTF8(string(data), "�")
+	}
+	return string(decoded)
+}
+
+// truncateOutput cuts s to at most limit bytes on a rune boundary.
+func truncateOutput(s string, limit int) string {
+	if len(s) <= limit {
+		return s
+	}
+	i := limit
+	for i > 0 && !utf8.RuneStart(s[i]) {
+		i--
+	}
+	return s[:i] + fmt.Sprintf("\n\n[output truncated at %d bytes]", limit)
+}
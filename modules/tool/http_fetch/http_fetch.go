@@ -0,0 +1,68 @@
+package httpfetch
+
+import (
+	"fmt"
+
+	"github.com/flemzord/sclaw/internal/core"
+	"github.com/flemzord/sclaw/internal/security"
+	"github.com/flemzord/sclaw/internal/tool"
+	"gopkg.in/yaml.v3"
+)
+
+func init() {
+	core.RegisterModule(&Module{})
+}
+
+// Compile-time interface guards.
+var (
+	_ core.Configurable = (*Module)(nil)
+	_ core.Provisioner  = (*Module)(nil)
+	_ core.Validator    = (*Module)(nil)
+	_ tool.Provider     = (*Module)(nil)
+)
+
+// Module implements the http_fetch tool module.
+type Module struct {
+	config Config
+	tool   *fetchTool
+}
+
+// ModuleInfo implements core.Module.
+func (m *Module) ModuleInfo() core.ModuleInfo {
+	return core.ModuleInfo{
+		ID:  "tool.http_fetch",
+		New: func() core.Module { return &Module{} },
+	}
+}
+
+// Configure implements core.Configurable.
+func (m *Module) Configure(node *yaml.Node) error {
+	if err := node.Decode(&m.config); err != nil {
+		return fmt.Errorf("http_fetch: decode config: %w", err)
+	}
+	return nil
+}
+
+// Provision implements core.Provisioner.
+func (m *Module) Provision(ctx *core.AppContext) error {
+	m.config.defaults()
+
+	var limiter *security.RateLimiter
+	if ctx != nil {
+		if svc, ok := ctx.GetService("security.ratelimiter"); ok {
+			limiter, _ = svc.(*security.RateLimiter)
+		}
+	}
+	m.tool = newFetchTool(m.config, checkPublicAddr, limiter)
+	return nil
+}
+
+// Validate implements core.Validator.
+func (m *Module) Validate() error {
+	return m.config.validate()
+}
+
+// Tools implements tool.Provider.
+func (m *Module) Tools() []tool.Tool {
+	return []tool.Tool{m.tool}
+}
@@ -0,0 +1,360 @@
+package httpfetch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/flemzord/sclaw/internal/security"
+	"github.com/flemzord/sclaw/internal/tool"
+)
+
+func TestConfigDefaults(t *testing.T) {
+	t.Parallel()
+
+	var c Config
+	c.defaults()
+
+	if c.Timeout != "30s" {
+		t.Errorf("Timeout = %q, want %q", c.Timeout, "30s")
+	}
+	if c.MaxTimeout != "2m0s" {
+		t.Errorf("MaxTimeout = %q, want %q", c.MaxTimeout, "2m0s")
+	}
+	if c.MaxBodySize != 5<<20 {
+		t.Errorf("MaxBodySize = %d, want %d", c.MaxBodySize, 5<<20)
+	}
+	if c.MaxRedirects != 5 {
+		t.Errorf("MaxRedirects = %d, want 5", c.MaxRedirects)
+	}
+	if c.DefaultPolicy != "ask" {
+		t.Errorf("DefaultPolicy = %q, want %q", c.DefaultPolicy, "ask")
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	t.Parallel()
+
+	valid := func(mod func(*Config)) Config {
+		var c Config
+		c.defaults()
+		if mod != nil {
+			mod(&c)
+		}
+		return c
+	}
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{
+		{name: "valid defaults", cfg: valid(nil)},
+		{name: "invalid timeout", cfg: valid(func(c *Config) { c.Timeout = "soon" }), wantErr: true},
+		{name: "invalid max timeout", cfg: valid(func(c *Config) { c.MaxTimeout = "later" }), wantErr: true},
+		{name: "negative body size", cfg: valid(func(c *Config) { c.MaxBodySize = -1 }), wantErr: true},
+		{name: "negative redirects", cfg: valid(func(c *Config) { c.MaxRedirects = -1 }), wantErr: true},
+		{name: "invalid policy", cfg: valid(func(c *Config) { c.DefaultPolicy = "maybe" }), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := tt.cfg.validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestModuleToolProvider(t *testing.T) {
+	t.Parallel()
+
+	m := &Module{}
+	_ = m.Provision(nil)
+
+	var tp tool.Provider = m
+	tools := tp.Tools()
+	if len(tools) != 1 {
+		t.Fatalf("Tools() returned %d tools, want 1", len(tools))
+	}
+	if tools[0].Name() != "http_fetch" {
+		t.Errorf("tool name = %q, want %q", tools[0].Name(), "http_fetch")
+	}
+	if scopes := tools[0].Scopes(); len(scopes) != 1 || scopes[0] != tool.ScopeNetwork {
+		t.Errorf("scopes = %v, want [network]", scopes)
+	}
+}
+
+func TestCheckPublicAddr(t *testing.T) {
+	t.Parallel()
+
+	blocked := []string{
+		"127.0.0.1", "10.1.2.3", "172.16.0.1", "192.168.1.1", "169.254.169.254",
+		"100.64.0.1", "0.0.0.0", "224.0.0.1", "::1", "::", "fe80::1", "fc00::1",
+		"::ffff:10.0.0.1", "::ffff:127.0.0.1", "64:ff9b::a00:1",
+	}
+	for _, s := range blocked {
+		if err := checkPublicAddr(netip.MustParseAddr(s)); !errors.Is(err, errBlockedAddr) {
+			t.Errorf("checkPublicAddr(%s) = %v, want errBlockedAddr", s, err)
+		}
+	}
+	for _, s := range []string{"8.8.8.8", "1.1.1.1", "2606:4700:4700::1111"} {
+		if err := checkPublicAddr(netip.MustParseAddr(s)); err != nil {
+			t.Errorf("checkPublicAddr(%s) = %v, want nil", s, err)
+		}
+	}
+}
+
+// newTestTool returns a tool that may reach the local test server.
+func newTestTool(mod func(*Config)) *fetchTool {
+	var cfg Config
+	cfg.defaults()
+	if mod != nil {
+		mod(&cfg)
+	}
+	return newFetchTool(cfg, func(netip.Addr) error { return nil }, nil)
+}
+
+func fetch(t *testing.T, ft *fetchTool, args map[string]any, env tool.ExecutionEnv) tool.Output {
+	t.Helper()
+	raw, _ := json.Marshal(args)
+	out, err := ft.Execute(context.Background(), raw, env)
+	if err != nil {
+		t.Fatalf("Execute() error: %v", err)
+	}
+	return out
+}
+
+func TestFetch_HTMLToMarkdown(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = io.WriteString(w, `<html><head><title>Test Page</title><script>evil()</script></head>
+<body><h1>Hello</h1><p>Read the <a href="/docs">docs</a>.</p></body></html>`)
+	}))
+	defer srv.Close()
+
+	out := fetch(t, newTestTool(nil), map[string]any{"url": srv.URL}, tool.ExecutionEnv{})
+	if out.IsError {
+		t.Fatalf("unexpected error: %s", out.Content)
+	}
+	for _, want := range []string{"Status: 200 OK", "Title: Test Page", "# Hello", "[docs](" + srv.URL + "/docs)"} {
+		if !strings.Contains(out.Content, want) {
+			t.Errorf("output missing %q:\n%s", want, out.Content)
+		}
+	}
+	if strings.Contains(out.Content, "evil") {
+		t.Errorf("script content leaked:\n%s", out.Content)
+	}
+
+	raw := fetch(t, newTestTool(nil), map[string]any{"url": srv.URL, "raw": true}, tool.ExecutionEnv{})
+	if !strings.Contains(raw.Content, "<h1>Hello</h1>") {
+		t.Errorf("raw output not HTML:\n%s", raw.Content)
+	}
+}
+
+func TestFetch_PostJSON(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"method":"`+r.Method+`","type":"`+r.Header.Get("Content-Type")+`","auth":"`+r.Header.Get("Authorization")+`","body":`+string(body)+`}`)
+	}))
+	defer srv.Close()
+
+	out := fetch(t, newTestTool(nil), map[string]any{
+		"url":     srv.URL,
+		"method":  "post",
+		"headers": map[string]string{"Authorization": "Bearer x"},
+		"body":    `{"a":1}`,
+	}, tool.ExecutionEnv{})
+	want := `{"method":"POST","type":"application/json","auth":"Bearer x","body":{"a":1}}`
+	if out.IsError || !strings.Contains(out.Content, want) {
+		t.Errorf("output = %s, want %s", out.Content, want)
+	}
+}
+
+func TestFetch_Errors(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/missing":
+			http.NotFound(w, r)
+		case "/big":
+			_, _ = io.WriteString(w, strings.Repeat("a", 2048))
+		case "/loop":
+			http.Redirect(w, r, "/loop", http.StatusFound)
+		case "/binary":
+			w.Header().Set("Content-Type", "image/png")
+			_, _ = w.Write([]byte{0x89, 'P', 'N', 'G'})
+		}
+	}))
+	t.Cleanup(srv.Close)
+	ft := newTestTool(func(c *Config) {
+		c.MaxBodySize = 1024
+		c.MaxRedirects = 2
+	})
+
+	tests := []struct {
+		name    string
+		args    map[string]any
+		wantErr bool
+		want    string
+	}{
+		{name: "not found", args: map[string]any{"url": srv.URL + "/missing"}, wantErr: true, want: "404"},
+		{name: "body limit", args: map[string]any{"url": srv.URL + "/big"}, want: "[response truncated at 1024 bytes]"},
+		{name: "redirect limit", args: map[string]any{"url": srv.URL + "/loop"}, wantErr: true, want: "stopped after 2 redirects"},
+		{name: "binary", args: map[string]any{"url": srv.URL + "/binary"}, want: "[binary content, 4 bytes, not shown]"},
+		{name: "scheme", args: map[string]any{"url": "file:///etc/passwd"}, wantErr: true, want: "not allowed"},
+		{name: "credentials", args: map[string]any{"url": "http://user:pw@example.com/"}, wantErr: true, want: "credentials"},
+		{name: "method", args: map[string]any{"url": srv.URL, "method": "DELETE"}, wantErr: true, want: "unsupported method"},
+		{name: "body with GET", args: map[string]any{"url": srv.URL, "body": "x"}, wantErr: true, want: "only allowed with POST"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			out := fetch(t, ft, tt.args, tool.ExecutionEnv{})
+			if out.IsError != tt.wantErr || !strings.Contains(out.Content, tt.want) {
+				t.Errorf("IsError = %v, content = %q; want IsError %v containing %q", out.IsError, out.Content, tt.wantErr, tt.want)
+			}
+		})
+	}
+}
+
+func TestFetch_URLFilter(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/away" {
+			http.Redirect(w, r, "http://denied.example/", http.StatusFound)
+			return
+		}
+		_, _ = io.WriteString(w, "ok")
+	}))
+	defer srv.Close()
+
+	// The filter rejects IP literals, so address the server by name.
+	u, _ := url.Parse(srv.URL)
+	base := "http://localhost:" + u.Port()
+	env := tool.ExecutionEnv{URLFilter: security.NewURLFilter(security.URLFilterConfig{
+		AllowDomains: []string{"localhost"},
+		DenyDomains:  []string{"denied.example"},
+	})}
+	ft := newTestTool(nil)
+
+	if out := fetch(t, ft, map[string]any{"url": base + "/"}, env); out.IsError || !strings.Contains(out.Content, "ok") {
+		t.Errorf("allowed domain: %s", out.Content)
+	}
+	if out := fetch(t, ft, map[string]any{"url": "http://other.example/"}, env); !out.IsError || !strings.Contains(out.Content, "not in allow list") {
+		t.Errorf("unlisted domain: %s", out.Content)
+	}
+	if out := fetch(t, ft, map[string]any{"url": base + "/away"}, env); !out.IsError || !strings.Contains(out.Content, "denied") {
+		t.Errorf("redirect to denied domain: %s", out.Content)
+	}
+}
+
+func TestFetch_NetworkRateLimit(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, "ok")
+	}))
+	defer srv.Close()
+
+	var cfg Config
+	cfg.defaults()
+	ft := newFetchTool(cfg, func(netip.Addr) error { return nil }, security.NewRateLimiter(security.RateLimitConfig{NetworkPerMin: 1}))
+	env := tool.ExecutionEnv{SessionID: "s1"}
+	if out := fetch(t, ft, map[string]any{"url": srv.URL}, env); out.IsError {
+		t.Fatalf("first fetch: %s", out.Content)
+	}
+	if out := fetch(t, ft, map[string]any{"url": srv.URL}, env); !out.IsError || !strings.Contains(out.Content, "rate limit") {
+		t.Errorf("second fetch not rate limited: %s", out.Content)
+	}
+}
+
+func TestFetch_BlocksPrivateAddressAfterResolution(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, "internal")
+	}))
+	defer srv.Close()
+
+	// The default guard applies to every dial, whatever the host name.
+	u, _ := url.Parse(srv.URL)
+	var cfg Config
+	cfg.defaults()
+	ft := newFetchTool(cfg, checkPublicAddr, nil)
+	out := fetch(t, ft, map[string]any{"url": "http://localhost:" + u.Port() + "/"}, tool.ExecutionEnv{})
+	if !out.IsError || strings.Contains(out.Content, "internal") || !strings.Contains(out.Content, "not allowed") {
+		t.Errorf("loopback fetch not blocked: %s", out.Content)
+	}
+}
+
+func TestHTMLToMarkdown(t *testing.T) {
+	t.Parallel()
+
+	base, _ := url.Parse("https://example.com/blog/post")
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{
+			name: "paragraphs and inline",
+			html: `<p>Some<strong> bold </strong>and <em>italic</em>
+				text with <code>x := 1</code>.</p><p>Second<br>line</p>`,
+			want: "Some **bold** and _italic_ text with `x := 1`.\n\nSecond\nline\n",
+		},
+		{
+			name: "links and images",
+			html: `<p><a href="../about">About</a> <a href="javascript:alert(1)">bad</a> <img src="/a.png" alt="A"></p>`,
+			want: "[About](https://example.com/about) bad ![A](https://example.com/a.png)\n",
+		},
+		{
+			name: "nested lists",
+			html: `<ul><li>one</li><li>two<ol><li>a</li><li>b</li></ol></li></ul>`,
+			want: "- one\n- two\n\n  1. a\n  2. b\n",
+		},
+		{
+			name: "blockquote and pre",
+			html: "<blockquote><p>quoted</p><p>more</p></blockquote><pre><code>a\n  b</code></pre>",
+			want: "> quoted\n>\n> more\n\n```\na\n  b\n```\n",
+		},
+		{
+			name: "table",
+			html: `<table><tr><th>Name</th><th>Value</th></tr><tr><td>a|b</td><td>1</td></tr></table>`,
+			want: "| Name | Value |\n| --- | --- |\n| a\\|b | 1 |\n",
+		},
+		{
+			name: "skipped elements",
+			html: `<nav>Menu</nav><style>p{}</style><div hidden>secret</div><h2>Title</h2><form><input value="x"><button>Go</button></form>`,
+			want: "Menu\n\n## Title\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := htmlToMarkdown(tt.html, base).Markdown
+			if got != tt.want {
+				t.Errorf("htmlToMarkdown() =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+
+	if doc := htmlToMarkdown("<title> My\n Page </title><p>x</p>", base); doc.Title != "My Page" {
+		t.Errorf("Title = %q, want %q", doc.Title, "My Page")
+	}
+}
@@ -0,0 +1,453 @@
+package httpfetch
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// markdownDoc is an HTML page converted for the agent.
+type markdownDoc struct {
+	Title    string
+	Markdown string
+}
+
+// skippedElements hold no readable content.
+var skippedElements = map[atom.Atom]bool{
+	atom.Head: true, atom.Script: true, atom.Style: true, atom.Noscript: true,
+	atom.Template: true, atom.Svg: true, atom.Canvas: true, atom.Iframe: true,
+	atom.Object: true, atom.Embed: true, atom.Button: true, atom.Select: true,
+	atom.Input: true, atom.Textarea: true,
+}
+
+// blockElements start on their own line.
+var blockElements = map[atom.Atom]bool{
+	atom.Div: true, atom.Section: true, atom.Article: true, atom.Main: true,
+	atom.Header: true, atom.Footer: true, atom.Nav: true, atom.Aside: true,
+	atom.Figure: true, atom.Figcaption: true, atom.Form: true, atom.Dl: true,
+	atom.Dt: true, atom.Dd: true, atom.Address: true, atom.Details: true,
+	atom.Summary: true, atom.Caption: true,
+}
+
+// htmlToMarkdown converts an HTML document to Markdown. Links and images
+// are resolved against base.
+func htmlToMarkdown(src string, base *url.URL) markdownDoc {
+	root, err := html.Parse(strings.NewReader(src))
+	if err != nil {
+		return markdownDoc{Markdown: src}
+	}
+	c := &mdConverter{base: base}
+	c.title = findTitle(root)
+	c.walk(root)
+
+	lines := strings.Split(strings.TrimSpace(c.w.String()), "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimRight(l, " ")
+	}
+	return markdownDoc{Title: c.title, Markdown: strings.Join(lines, "\n") + "\n"}
+}
+
+// findTitle returns the text of the first <title> element.
+func findTitle(n *html.Node) string {
+	if n.Type == html.ElementNode && n.DataAtom == atom.Title {
+		return collapseSpace(textContent(n))
+	}
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if t := findTitle(child); t != "" {
+			return t
+		}
+	}
+	return ""
+}
+
+// textContent concatenates the text below n.
+func textContent(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	var sb strings.Builder
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		sb.WriteString(textContent(child))
+	}
+	return sb.String()
+}
+
+// collapseSpace replaces runs of whitespace with single spaces.
+func collapseSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// mdConverter walks the HTML tree and writes Markdown.
+type mdConverter struct {
+	base  *url.URL
+	title string
+	w     mdWriter
+	pre   int // depth of <pre> elements
+}
+
+func (c *mdConverter) walk(n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		if c.pre > 0 {
+			c.w.raw(n.Data)
+		} else {
+			c.w.text(n.Data)
+		}
+		return
+	case html.DocumentNode:
+		c.children(n)
+		return
+	case html.ElementNode:
+	default:
+		return
+	}
+	if skippedElements[n.DataAtom] || hasAttr(n, "hidden") || attr(n, "aria-hidden") == "true" {
+		return
+	}
+
+	switch n.DataAtom {
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		level := int(n.Data[1] - '0')
+		c.w.blank()
+		c.w.inline(strings.Repeat("#", level) + " ")
+		c.children(n)
+		c.w.blank()
+	case atom.P:
+		c.w.blank()
+		c.children(n)
+		c.w.blank()
+	case atom.Br:
+		c.w.newline()
+	case atom.Hr:
+		c.w.blank()
+		c.w.inline("---")
+		c.w.blank()
+	case atom.Pre:
+		c.w.blank()
+		c.w.inline("```")
+		c.w.newline()
+		c.pre++
+		c.children(n)
+		c.pre--
+		c.w.newline()
+		c.w.inline("```")
+		c.w.blank()
+	case atom.Code:
+		if c.pre > 0 {
+			c.children(n)
+			return
+		}
+		c.w.inline("`" + collapseSpace(textContent(n)) + "`")
+	case atom.Strong, atom.B:
+		c.wrap(n, "**")
+	case atom.Em, atom.I:
+		c.wrap(n, "_")
+	case atom.A:
+		c.link(n)
+	case atom.Img:
+		if src := c.resolve(attr(n, "src")); src != "" {
+			c.w.inline("![" + collapseSpace(attr(n, "alt")) + "](" + src + ")")
+		}
+	case atom.Blockquote:
+		c.w.blank()
+		c.w.push("> ", "> ")
+		c.children(n)
+		c.w.pop()
+		c.w.blank()
+	case atom.Ul, atom.Ol:
+		c.list(n)
+	case atom.Table:
+		c.table(n)
+	default:
+		if blockElements[n.DataAtom] {
+			c.w.line()
+			c.children(n)
+			c.w.line()
+			return
+		}
+		c.children(n)
+	}
+}
+
+func (c *mdConverter) children(n *html.Node) {
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		c.walk(child)
+	}
+}
+
+// wrap renders n's children between markers, unless they are empty.
+func (c *mdConverter) wrap(n *html.Node, marker string) {
+	if strings.TrimSpace(textContent(n)) == "" {
+		c.children(n)
+		return
+	}
+	c.w.open(marker, textContent(n))
+	c.children(n)
+	c.w.close(marker)
+}
+
+// link renders an anchor as [text](href).
+func (c *mdConverter) link(n *html.Node) {
+	href := c.resolve(attr(n, "href"))
+	if href == "" {
+		c.children(n)
+		return
+	}
+	c.w.open("[", textContent(n))
+	c.children(n)
+	c.w.close("](" + href + ")")
+}
+
+// list renders <ul> and <ol> items, nesting through line prefixes.
+func (c *mdConverter) list(n *html.Node) {
+	c.w.blank()
+	num := 1
+	if start, err := strconv.Atoi(attr(n, "start")); err == nil {
+		num = start
+	}
+	for li := n.FirstChild; li != nil; li = li.NextSibling {
+		if li.Type != html.ElementNode || li.DataAtom != atom.Li {
+			continue
+		}
+		marker := "- "
+		if n.DataAtom == atom.Ol {
+			marker = strconv.Itoa(num) + ". "
+			num++
+		}
+		c.w.line()
+		c.w.push(marker, strings.Repeat(" ", len(marker)))
+		c.children(li)
+		c.w.pop()
+	}
+	c.w.blank()
+}
+
+// table renders rows as a pipe table; the first row is the header.
+func (c *mdConverter) table(n *html.Node) {
+	c.w.blank()
+	first := true
+	var rows func(*html.Node)
+	rows = func(n *html.Node) {
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			if child.Type != html.ElementNode {
+				continue
+			}
+			if child.DataAtom != atom.Tr {
+				rows(child) // thead, tbody, tfoot
+				continue
+			}
+			cells := 0
+			c.w.line()
+			c.w.inline("|")
+			for cell := child.FirstChild; cell != nil; cell = cell.NextSibling {
+				if cell.Type != html.ElementNode || (cell.DataAtom != atom.Td && cell.DataAtom != atom.Th) {
+					continue
+				}
+				cells++
+				c.w.inline(" " + strings.ReplaceAll(collapseSpace(textContent(cell)), "|", `\|`) + " |")
+			}
+			if first && cells > 0 {
+				c.w.line()
+				c.w.inline("|" + strings.Repeat(" --- |", cells))
+				first = false
+			}
+		}
+	}
+	rows(n)
+	c.w.blank()
+}
+
+// resolve makes a link absolute. Script links and fragments yield "".
+func (c *mdConverter) resolve(ref string) string {
+	ref = strings.TrimSpace(ref)
+	if ref == "" || strings.HasPrefix(ref, "#") {
+		return ""
+	}
+	u, err := url.Parse(ref)
+	if err != nil {
+		return ""
+	}
+	if c.base != nil {
+		u = c.base.ResolveReference(u)
+	}
+	switch u.Scheme {
+	case "http", "https", "mailto":
+		return u.String()
+	default:
+		return ""
+	}
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func hasAttr(n *html.Node, key string) bool {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// mdWriter accumulates Markdown. Line breaks are requested rather than
+// written, so that consecutive blocks produce a single blank line, and every
+// line starts with the prefixes of the enclosing blockquotes and list items.
+type mdWriter struct {
+	sb        strings.Builder
+	prefixes  []mdPrefix
+	pending   int  // line breaks to write before the next content
+	lineStart bool // nothing written on the current line yet
+	space     bool // the next word needs no separating space
+	sep       bool // a space is owed before the next word
+}
+
+// mdPrefix is a line prefix. The first line uses first (a list marker),
+// following lines use rest (its indentation).
+type mdPrefix struct {
+	first, rest string
+	used        bool
+}
+
+func (w *mdWriter) String() string { return w.sb.String() }
+
+// push starts a nested block with its own line prefix.
+func (w *mdWriter) push(first, rest string) {
+	w.prefixes = append(w.prefixes, mdPrefix{first: first, rest: rest})
+}
+
+// pop ends the innermost nested block.
+func (w *mdWriter) pop() {
+	w.prefixes = w.prefixes[:len(w.prefixes)-1]
+}
+
+// fresh reports whether the innermost nested block has no content yet, in
+// which case its first line is already being started.
+func (w *mdWriter) fresh() bool {
+	return len(w.prefixes) > 0 && !w.prefixes[len(w.prefixes)-1].used
+}
+
+// line requests that the next content starts on a new line.
+func (w *mdWriter) line() {
+	if w.sb.Len() > 0 && !w.fresh() {
+		w.pending = max(w.pending, 1)
+	}
+}
+
+// blank requests a blank line before the next content.
+func (w *mdWriter) blank() {
+	if w.sb.Len() > 0 && !w.fresh() {
+		w.pending = 2
+	}
+}
+
+// newline writes a line break immediately (<br>, preformatted text).
+func (w *mdWriter) newline() {
+	w.flush()
+	w.sb.WriteString("\n")
+	w.lineStart = true
+	w.space = true
+	w.sep = false
+}
+
+// flush writes the pending line breaks.
+func (w *mdWriter) flush() {
+	for ; w.pending > 0; w.pending-- {
+		w.sb.WriteString("\n")
+		w.lineStart = true
+		w.space = true
+		w.sep = false
+		if w.pending > 1 {
+			w.sb.WriteString(strings.TrimRight(w.prefix(true), " "))
+		}
+	}
+}
+
+// prefix returns the prefix for a new line. Blank lines keep the
+// blockquote markers but never consume a list marker.
+func (w *mdWriter) prefix(blank bool) string {
+	var sb strings.Builder
+	for i := range w.prefixes {
+		p := &w.prefixes[i]
+		switch {
+		case p.used:
+			sb.WriteString(p.rest)
+		case blank:
+			sb.WriteString(strings.Repeat(" ", len(p.first)))
+		default:
+			sb.WriteString(p.first)
+			p.used = true
+		}
+	}
+	return sb.String()
+}
+
+// inline writes s verbatim on the current line.
+func (w *mdWriter) inline(s string) {
+	if s == "" {
+		return
+	}
+	w.flush()
+	if w.lineStart || w.sb.Len() == 0 {
+		w.sb.WriteString(w.prefix(false))
+		w.lineStart = false
+	} else if w.sep {
+		w.sb.WriteString(" ")
+	}
+	w.sep = false
+	w.sb.WriteString(s)
+	w.space = strings.HasSuffix(s, " ")
+}
+
+// open writes an opening marker for the given inner text. Whitespace at
+// the start of the text is moved before the marker, so that "a<b> x</b>"
+// becomes "a **x**".
+func (w *mdWriter) open(marker, inner string) {
+	w.text(inner[:len(inner)-len(strings.TrimLeft(inner, " \t\r\n"))])
+	w.inline(marker)
+	w.space = true
+}
+
+// close writes a closing marker. Whitespace right before it is moved
+// after it.
+func (w *mdWriter) close(marker string) {
+	w.sb.WriteString(marker)
+	w.space = false
+}
+
+// text writes flowing text, collapsing whitespace.
+func (w *mdWriter) text(s string) {
+	words := strings.Fields(s)
+	if strings.TrimLeft(s, " \t\r\n") != s && !w.space {
+		w.sep = true
+	}
+	if len(words) == 0 {
+		return
+	}
+	w.inline(strings.Join(words, " "))
+	if strings.TrimRight(s, " \t\r\n") != s {
+		w.sep = true
+	}
+}
+
+// raw writes preformatted text, preserving line breaks.
+func (w *mdWriter) raw(s string) {
+	lines := strings.Split(s, "\n")
+	for i, l := range lines {
+		if i > 0 {
+			w.newline()
+		}
+		if l != "" {
+			w.inline(l)
+		}
+	}
+}
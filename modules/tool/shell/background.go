@@ -0,0 +1,237 @@
+package shell
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/flemzord/sclaw/internal/tool"
+)
+
+const (
+	// defaultJobOutputLimit is how much output job_output returns by default.
+	defaultJobOutputLimit = 16 << 10 // 16 KiB
+
+	// maxJobOutputLimit caps the output job_output returns per call.
+	maxJobOutputLimit = 256 << 10 // 256 KiB
+)
+
+type backgroundTool struct {
+	jobs   *jobManager
+	policy tool.ApprovalLevel
+}
+
+func (t *backgroundTool) Name() string { return "shell_background" }
+func (t *backgroundTool) Description() string {
+	return "Start a long-running shell command in the background and return a job ID immediately. " +
+		"Use job_status, job_output and job_kill to follow it; the chat is notified when it exits."
+}
+func (t *backgroundTool) Scopes() []tool.Scope {
+	return []tool.Scope{tool.ScopeExec}
+}
+
+func (t *backgroundTool) DefaultPolicy() tool.ApprovalLevel {
+	return t.policy
+}
+
+func (t *backgroundTool) Schema() json.RawMessage {
+	return json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"command": {"type": "string", "description": "Shell command to run (passed to sh -c)."},
+			"timeout_seconds": {"type": "integer", "description": "Optional time after which the job is killed (default and max: the configured job runtime)."}
+		},
+		"required": ["command"]
+	}`)
+}
+
+func (t *backgroundTool) Execute(_ context.Context, args json.RawMessage, env tool.ExecutionEnv) (tool.Output, error) {
+	var a execArgs
+	if err := json.Unmarshal(args, &a); err != nil {
+		return tool.Output{Content: fmt.Sprintf("invalid arguments: %v", err), IsError: true}, nil
+	}
+	if a.Command == "" {
+		return tool.Output{Content: "command is empty", IsError: true}, nil
+	}
+
+	j, err := t.jobs.start(env, a.Command, time.Duration(a.TimeoutSeconds)*time.Second)
+	if err != nil {
+		return tool.Output{Content: fmt.Sprintf("cannot start job: %v", err), IsError: true}, nil
+	}
+	return tool.Output{Content: fmt.Sprintf("started %s", j.id)}, nil
+}
+
+type jobStatusTool struct {
+	jobs *jobManager
+}
+
+func (t *jobStatusTool) Name() string { return "job_status" }
+func (t *jobStatusTool) Description() string {
+	return "Show the state of a background job, or of every job in this session when no ID is given."
+}
+func (t *jobStatusTool) Scopes() []tool.Scope {
+	return []tool.Scope{tool.ScopeReadOnly}
+}
+
+func (t *jobStatusTool) DefaultPolicy() tool.ApprovalLevel {
+	return tool.ApprovalAllow
+}
+
+func (t *jobStatusTool) Schema() json.RawMessage {
+	return json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"id": {"type": "string", "description": "Job ID returned by shell_background (optional)."}
+		}
+	}`)
+}
+
+type jobArgs struct {
+	ID string `json:"id"`
+}
+
+func (t *jobStatusTool) Execute(_ context.Context, args json.RawMessage, env tool.ExecutionEnv) (tool.Output, error) {
+	var a jobArgs
+	if len(args) > 0 {
+		if err := json.Unmarshal(args, &a); err != nil {
+			return tool.Output{Content: fmt.Sprintf("invalid arguments: %v", err), IsError: true}, nil
+		}
+	}
+
+	if a.ID != "" {
+		j, err := t.jobs.get(env.SessionID, a.ID)
+		if err != nil {
+			return tool.Output{Content: err.Error(), IsError: true}, nil
+		}
+		return tool.Output{Content: formatJob(j.info())}, nil
+	}
+
+	jobs := t.jobs.list(env.SessionID)
+	if len(jobs) == 0 {
+		return tool.Output{Content: "no background jobs"}, nil
+	}
+	lines := make([]string, len(jobs))
+	for i, j := range jobs {
+		lines[i] = formatJob(j.info())
+	}
+	return tool.Output{Content: strings.Join(lines, "\n")}, nil
+}
+
+// formatJob renders one job as "job-1: running for 12s, 3200 bytes of output — $ make test".
+func formatJob(info jobInfo) string {
+	out := fmt.Sprintf("%s: %s, %d bytes of output", info.ID, describeEnd(info), info.Size)
+	if info.Truncated {
+		out += " (truncated)"
+	}
+	return out + " — $ " + truncateCommand(info.Command)
+}
+
+type jobOutputTool struct {
+	jobs *jobManager
+}
+
+func (t *jobOutputTool) Name() string { return "job_output" }
+func (t *jobOutputTool) Description() string {
+	return "Read the combined stdout and stderr of a background job. Without an offset, returns the end of the output; " +
+		"pass the returned next offset to read what was written since."
+}
+func (t *jobOutputTool) Scopes() []tool.Scope {
+	return []tool.Scope{tool.ScopeReadOnly}
+}
+
+func (t *jobOutputTool) DefaultPolicy() tool.ApprovalLevel {
+	return tool.ApprovalAllow
+}
+
+func (t *jobOutputTool) Schema() json.RawMessage {
+	return json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"id": {"type": "string", "description": "Job ID returned by shell_background."},
+			"offset": {"type": "integer", "description": "Byte offset to read from (optional, default: the last limit bytes)."},
+			"limit": {"type": "integer", "description": "Maximum bytes to return (default 16384, max 262144)."}
+		},
+		"required": ["id"]
+	}`)
+}
+
+type jobOutputArgs struct {
+	ID     string `json:"id"`
+	Offset *int64 `json:"offset,omitempty"`
+	Limit  int64  `json:"limit,omitempty"`
+}
+
+func (t *jobOutputTool) Execute(_ context.Context, args json.RawMessage, env tool.ExecutionEnv) (tool.Output, error) {
+	var a jobOutputArgs
+	if err := json.Unmarshal(args, &a); err != nil {
+		return tool.Output{Content: fmt.Sprintf("invalid arguments: %v", err), IsError: true}, nil
+	}
+	j, err := t.jobs.get(env.SessionID, a.ID)
+	if err != nil {
+		return tool.Output{Content: err.Error(), IsError: true}, nil
+	}
+
+	limit := a.Limit
+	if limit <= 0 {
+		limit = defaultJobOutputLimit
+	}
+	limit = min(limit, maxJobOutputLimit)
+	offset := int64(-1)
+	if a.Offset != nil {
+		if *a.Offset < 0 {
+			return tool.Output{Content: "offset must not be negative", IsError: true}, nil
+		}
+		offset = *a.Offset
+	}
+
+	text, next, err := readSpool(j.path, offset, limit)
+	if err != nil {
+		return tool.Output{Content: fmt.Sprintf("cannot read output: %v", err), IsError: true}, nil
+	}
+	info := j.info()
+	header := fmt.Sprintf("[%s %s; next offset %d of %d bytes", info.ID, describeEnd(info), next, info.Size)
+	if info.Truncated {
+		header += ", output truncated"
+	}
+	return tool.Output{Content: header + "]\n" + text}, nil
+}
+
+type jobKillTool struct {
+	jobs *jobManager
+}
+
+func (t *jobKillTool) Name() string { return "job_kill" }
+func (t *jobKillTool) Description() string {
+	return "Stop a running background job."
+}
+func (t *jobKillTool) Scopes() []tool.Scope {
+	return []tool.Scope{tool.ScopeExec}
+}
+
+func (t *jobKillTool) DefaultPolicy() tool.ApprovalLevel {
+	return tool.ApprovalAllow
+}
+
+func (t *jobKillTool) Schema() json.RawMessage {
+	return json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"id": {"type": "string", "description": "Job ID returned by shell_background."}
+		},
+		"required": ["id"]
+	}`)
+}
+
+func (t *jobKillTool) Execute(ctx context.Context, args json.RawMessage, env tool.ExecutionEnv) (tool.Output, error) {
+	var a jobArgs
+	if err := json.Unmarshal(args, &a); err != nil {
+		return tool.Output{Content: fmt.Sprintf("invalid arguments: %v", err), IsError: true}, nil
+	}
+	j, err := t.jobs.kill(ctx, env.SessionID, a.ID)
+	if err != nil {
+		return tool.Output{Content: err.Error(), IsError: true}, nil
+	}
+	return tool.Output{Content: formatJob(j.info())}, nil
+}
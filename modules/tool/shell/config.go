@@ -11,6 +11,10 @@ const (
 	defaultTimeout    = 30 * time.Second
 	defaultMaxTimeout = 10 * time.Minute
 	defaultMaxOutput  = 1 << 20 // 1 MiB
+
+	defaultMaxJobs       = 4
+	defaultMaxJobRuntime = time.Hour
+	defaultMaxJobOutput  = 16 << 20 // 16 MiB
 )
 
 // Config holds the tool.shell module configuration.
@@ -26,6 +30,18 @@ type Config struct {
 
 	// DefaultPolicy is the default approval level: "allow", "ask", or "deny". Defaults to "allow".
 	DefaultPolicy string `yaml:"default_policy"`
+
+	// MaxJobs is the number of background jobs a session may run at once.
+	// Defaults to 4.
+	MaxJobs int `yaml:"max_jobs"`
+
+	// MaxJobRuntime is the time after which a background job is killed
+	// (e.g. "1h"). Defaults to 1h.
+	MaxJobRuntime string `yaml:"max_job_runtime"`
+
+	// MaxJobOutput is the max output spooled per background job in bytes.
+	// Defaults to 16 MiB.
+	MaxJobOutput int64 `yaml:"max_job_output"`
 }
 
 func (c *Config) defaults() {
@@ -41,6 +57,15 @@ func (c *Config) defaults() {
 	if c.DefaultPolicy == "" {
 		c.DefaultPolicy = "allow"
 	}
+	if c.MaxJobs == 0 {
+		c.MaxJobs = defaultMaxJobs
+	}
+	if c.MaxJobRuntime == "" {
+		c.MaxJobRuntime = defaultMaxJobRuntime.String()
+	}
+	if c.MaxJobOutput == 0 {
+		c.MaxJobOutput = defaultMaxJobOutput
+	}
 }
 
 func (c *Config) validate() error {
@@ -53,6 +78,15 @@ func (c *Config) validate() error {
 	if c.MaxOutputSize <= 0 {
 		return fmt.Errorf("shell: max_output_size must be positive, got %d", c.MaxOutputSize)
 	}
+	if c.MaxJobs <= 0 {
+		return fmt.Errorf("shell: max_jobs must be positive, got %d", c.MaxJobs)
+	}
+	if d, err := time.ParseDuration(c.MaxJobRuntime); err != nil || d <= 0 {
+		return fmt.Errorf("shell: invalid max_job_runtime %q", c.MaxJobRuntime)
+	}
+	if c.MaxJobOutput <= 0 {
+		return fmt.Errorf("shell: max_job_output must be positive, got %d", c.MaxJobOutput)
+	}
 	switch c.DefaultPolicy {
 	case "allow", "ask", "deny":
 	default:
@@ -70,3 +104,8 @@ func (c *Config) maxTimeoutDuration() time.Duration {
 	d, _ := time.ParseDuration(c.MaxTimeout)
 	return d
 }
+
+func (c *Config) maxJobRuntimeDuration() time.Duration {
+	d, _ := time.ParseDuration(c.MaxJobRuntime)
+	return d
+}
@@ -0,0 +1,429 @@
+package shell
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/flemzord/sclaw/internal/tool"
+)
+
+// Job states reported by job_status.
+const (
+	jobRunning  = "running"
+	jobExited   = "exited"
+	jobFailed   = "failed"
+	jobKilled   = "killed"
+	jobTimedOut = "timed_out"
+)
+
+const (
+	// maxFinishedJobs is how many finished jobs a session keeps; older ones
+	// are forgotten and their output removed.
+	maxFinishedJobs = 20
+
+	// notifyTimeout bounds the delivery of a completion notification.
+	notifyTimeout = 30 * time.Second
+
+	// notifyTailSize is how much trailing output a completion notification
+	// includes.
+	notifyTailSize = 1024
+)
+
+// errJobNotFound is returned for unknown job IDs and for jobs of other
+// sessions.
+var errJobNotFound = errors.New("job not found")
+
+// job is a shell command running in the background.
+type job struct {
+	id      string
+	session string
+	command string
+	path    string // spooled stdout and stderr
+	started time.Time
+	cancel  context.CancelFunc
+	done    chan struct{}
+
+	mu        sync.Mutex
+	state     string
+	exitCode  int
+	errText   string
+	finished  time.Time
+	killed    bool
+	size      int64
+	truncated bool
+}
+
+// jobInfo is a snapshot of a job's state.
+type jobInfo struct {
+	ID        string
+	Command   string
+	State     string
+	ExitCode  int
+	Err       string
+	Started   time.Time
+	Finished  time.Time
+	Size      int64
+	Truncated bool
+}
+
+func (j *job) info() jobInfo {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return jobInfo{
+		ID:        j.id,
+		Command:   j.command,
+		State:     j.state,
+		ExitCode:  j.exitCode,
+		Err:       j.errText,
+		Started:   j.started,
+		Finished:  j.finished,
+		Size:      j.size,
+		Truncated: j.truncated,
+	}
+}
+
+// spoolWriter writes command output to the job's file, discarding it past
+// the limit so that a chatty process never fails on a full spool.
+type spoolWriter struct {
+	job *job
+	f   *os.File
+	max int64
+}
+
+func (w *spoolWriter) Write(p []byte) (int, error) {
+	w.job.mu.Lock()
+	defer w.job.mu.Unlock()
+	remaining := w.max - w.job.size
+	if remaining <= 0 {
+		w.job.truncated = true
+		return len(p), nil
+	}
+	n := len(p)
+	if int64(n) > remaining {
+		p = p[:remaining]
+		w.job.truncated = true
+	}
+	written, err := w.f.Write(p)
+	w.job.size += int64(written)
+	if err != nil {
+		return written, err
+	}
+	return n, nil
+}
+
+// jobManager runs background jobs and tracks them per session.
+type jobManager struct {
+	maxRunning int
+	maxRuntime time.Duration
+	maxOutput  int64
+	logger     *slog.Logger
+
+	mu     sync.Mutex
+	seq    int
+	jobs   map[string]*job
+	spools map[string]bool // spool directories cleaned of stale output
+}
+
+func newJobManager(maxRunning int, maxRuntime time.Duration, maxOutput int64, logger *slog.Logger) *jobManager {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &jobManager{
+		maxRunning: maxRunning,
+		maxRuntime: maxRuntime,
+		maxOutput:  maxOutput,
+		logger:     logger,
+		jobs:       make(map[string]*job),
+		spools:     make(map[string]bool),
+	}
+}
+
+// start runs command in the background for the session of env. The job is
+// killed after timeout, capped at the manager's maximum runtime.
+func (m *jobManager) start(env tool.ExecutionEnv, command string, timeout time.Duration) (*job, error) {
+	if timeout <= 0 || timeout > m.maxRuntime {
+		timeout = m.maxRuntime
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	running := 0
+	for _, j := range m.jobs {
+		if j.session == env.SessionID && j.info().State == jobRunning {
+			running++
+		}
+	}
+	if running >= m.maxRunning {
+		return nil, fmt.Errorf("too many running jobs (max %d); wait for one to finish or kill it", m.maxRunning)
+	}
+	m.seq++
+	id := "job-" + strconv.Itoa(m.seq)
+	dir, err := m.spoolDirLocked(env.DataDir)
+	if err != nil {
+		return nil, err
+	}
+
+	path := filepath.Join(dir, id+".log")
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("creating output file: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	cmd, release, err := tool.ShellCommand(ctx, env, command)
+	if err != nil {
+		cancel()
+		_ = f.Close()
+		_ = os.Remove(path)
+		return nil, err
+	}
+	j := &job{
+		id:      id,
+		session: env.SessionID,
+		command: command,
+		path:    path,
+		started: time.Now(),
+		cancel:  cancel,
+		done:    make(chan struct{}),
+		state:   jobRunning,
+	}
+	out := &spoolWriter{job: j, f: f, max: m.maxOutput}
+	cmd.Stdout = out
+	cmd.Stderr = out
+	if env.Sandbox == nil {
+		killProcessGroup(cmd)
+	}
+
+	if err := cmd.Start(); err != nil {
+		release()
+		cancel()
+		_ = f.Close()
+		_ = os.Remove(path)
+		return nil, err
+	}
+
+	m.jobs[id] = j
+	m.pruneLocked(env.SessionID)
+
+	go m.wait(ctx, j, cmd, release, f, env.Notifier)
+	return j, nil
+}
+
+// spoolDirLocked returns the directory holding job output for an agent data
+// directory. Output left by a previous run is removed on first use since
+// its jobs are gone. Callers hold m.mu.
+func (m *jobManager) spoolDirLocked(dataDir string) (string, error) {
+	if dataDir == "" {
+		dataDir = filepath.Join(os.TempDir(), "sclaw")
+	}
+	dir := filepath.Join(dataDir, "jobs")
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", fmt.Errorf("creating job directory: %w", err)
+	}
+	if !m.spools[dir] {
+		m.spools[dir] = true
+		stale, _ := filepath.Glob(filepath.Join(dir, "job-*.log"))
+		for _, path := range stale {
+			_ = os.Remove(path)
+		}
+	}
+	return dir, nil
+}
+
+// wait records how the job ended and notifies its chat.
+func (m *jobManager) wait(ctx context.Context, j *job, cmd *exec.Cmd, release func(), f *os.File, notifier tool.Notifier) {
+	err := cmd.Wait()
+	release()
+	_ = f.Close()
+
+	j.mu.Lock()
+	j.finished = time.Now()
+	var exitErr *exec.ExitError
+	switch {
+	case j.killed:
+		j.state = jobKilled
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		j.state = jobTimedOut
+	case err == nil:
+		j.state = jobExited
+	case errors.As(err, &exitErr):
+		j.state = jobExited
+		j.exitCode = exitErr.ExitCode()
+	default:
+		j.state = jobFailed
+		j.errText = err.Error()
+	}
+	j.mu.Unlock()
+	j.cancel()
+	close(j.done)
+
+	// Killed jobs were stopped by the agent or by shutdown: nobody is
+	// waiting to hear about them.
+	if notifier == nil || j.info().State == jobKilled {
+		return
+	}
+	nctx, cancel := context.WithTimeout(context.Background(), notifyTimeout)
+	defer cancel()
+	if err := notifier.Notify(nctx, m.completionMessage(j)); err != nil {
+		m.logger.Warn("shell: failed to notify job completion", "job", j.id, "error", err)
+	}
+}
+
+// completionMessage summarizes a finished job with the end of its output.
+func (m *jobManager) completionMessage(j *job) string {
+	info := j.info()
+	msg := fmt.Sprintf("Background job %s %s.\n$ %s", info.ID, describeEnd(info), truncateCommand(info.Command))
+	if tail, _, err := readSpool(j.path, -1, notifyTailSize); err == nil && tail != "" {
+		msg += "\n" + tail
+	}
+	return msg
+}
+
+// get returns the job with the given ID if it belongs to session.
+func (m *jobManager) get(session, id string) (*job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	j, ok := m.jobs[id]
+	if !ok || j.session != session {
+		return nil, fmt.Errorf("%w: %s", errJobNotFound, id)
+	}
+	return j, nil
+}
+
+// list returns the jobs of session, oldest first.
+func (m *jobManager) list(session string) []*job {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var jobs []*job
+	for _, j := range m.jobs {
+		if j.session == session {
+			jobs = append(jobs, j)
+		}
+	}
+	slices.SortFunc(jobs, func(a, b *job) int { return a.started.Compare(b.started) })
+	return jobs
+}
+
+// kill stops a running job and waits for it to exit.
+func (m *jobManager) kill(ctx context.Context, session, id string) (*job, error) {
+	j, err := m.get(session, id)
+	if err != nil {
+		return nil, err
+	}
+	j.mu.Lock()
+	if j.state != jobRunning {
+		j.mu.Unlock()
+		return j, nil
+	}
+	j.killed = true
+	j.mu.Unlock()
+	j.cancel()
+
+	select {
+	case <-j.done:
+		return j, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// pruneLocked forgets the oldest finished jobs of session beyond
+// maxFinishedJobs. Callers hold m.mu.
+func (m *jobManager) pruneLocked(session string) {
+	var finished []*job
+	for _, j := range m.jobs {
+		if j.session == session && j.info().State != jobRunning {
+			finished = append(finished, j)
+		}
+	}
+	if len(finished) <= maxFinishedJobs {
+		return
+	}
+	slices.SortFunc(finished, func(a, b *job) int { return a.started.Compare(b.started) })
+	for _, j := range finished[:len(finished)-maxFinishedJobs] {
+		delete(m.jobs, j.id)
+		_ = os.Remove(j.path)
+	}
+}
+
+// stop kills every running job, for module shutdown.
+func (m *jobManager) stop(ctx context.Context) {
+	m.mu.Lock()
+	jobs := make([]*job, 0, len(m.jobs))
+	for _, j := range m.jobs {
+		jobs = append(jobs, j)
+	}
+	m.mu.Unlock()
+
+	for _, j := range jobs {
+		if _, err := m.kill(ctx, j.session, j.id); err != nil {
+			m.logger.Warn("shell: failed to kill job", "job", j.id, "error", err)
+		}
+	}
+}
+
+// readSpool reads up to limit bytes of a job's output starting at offset.
+// A negative offset reads the last limit bytes. It returns the text and the
+// offset following it.
+func readSpool(path string, offset, limit int64) (string, int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", 0, err
+	}
+	defer func() { _ = f.Close() }()
+
+	info, err := f.Stat()
+	if err != nil {
+		return "", 0, err
+	}
+	size := info.Size()
+	if offset < 0 {
+		offset = max(size-limit, 0)
+	}
+	if offset >= size {
+		return "", size, nil
+	}
+	buf := make([]byte, min(limit, size-offset))
+	n, err := f.ReadAt(buf, offset)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", 0, err
+	}
+	return string(buf[:n]), offset + int64(n), nil
+}
+
+// describeEnd tells how a job ended, or that it is still running.
+func describeEnd(info jobInfo) string {
+	switch info.State {
+	case jobRunning:
+		return fmt.Sprintf("running for %s", time.Since(info.Started).Round(time.Second))
+	case jobExited:
+		return fmt.Sprintf("exited with code %d after %s", info.ExitCode, info.Finished.Sub(info.Started).Round(time.Second))
+	case jobKilled:
+		return "was killed"
+	case jobTimedOut:
+		return fmt.Sprintf("timed out after %s", info.Finished.Sub(info.Started).Round(time.Second))
+	default:
+		return "failed: " + info.Err
+	}
+}
+
+// maxCommandEcho caps the command echoed in job summaries.
+const maxCommandEcho = 200
+
+func truncateCommand(command string) string {
+	if len(command) <= maxCommandEcho {
+		return command
+	}
+	return command[:maxCommandEcho] + "..."
+}
@@ -0,0 +1,8 @@
+//go:build !unix
+
+package shell
+
+import "os/exec"
+
+// killProcessGroup is a no-op: cancelling cmd only kills the shell.
+func killProcessGroup(*exec.Cmd) {}
@@ -0,0 +1,183 @@
+package shell
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/flemzord/sclaw/internal/tool"
+)
+
+// recordingNotifier collects notifications.
+type recordingNotifier struct {
+	mu   sync.Mutex
+	msgs []string
+	sent chan struct{}
+}
+
+func newRecordingNotifier() *recordingNotifier {
+	return &recordingNotifier{sent: make(chan struct{}, 10)}
+}
+
+func (n *recordingNotifier) Notify(_ context.Context, text string) error {
+	n.mu.Lock()
+	n.msgs = append(n.msgs, text)
+	n.mu.Unlock()
+	n.sent <- struct{}{}
+	return nil
+}
+
+func newTestJobManager() *jobManager {
+	return newJobManager(2, time.Minute, 1<<20, nil)
+}
+
+func jobEnv(t *testing.T, session string, n tool.Notifier) tool.ExecutionEnv {
+	t.Helper()
+	return tool.ExecutionEnv{
+		Workspace: t.TempDir(),
+		DataDir:   t.TempDir(),
+		SessionID: session,
+		Notifier:  n,
+	}
+}
+
+func runTool(t *testing.T, tl tool.Tool, env tool.ExecutionEnv, args any) tool.Output {
+	t.Helper()
+	raw, err := json.Marshal(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := tl.Execute(context.Background(), raw, env)
+	if err != nil {
+		t.Fatalf("%s: unexpected error: %v", tl.Name(), err)
+	}
+	return out
+}
+
+func waitJob(t *testing.T, m *jobManager, session, id string) jobInfo {
+	t.Helper()
+	j, err := m.get(session, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-j.done:
+	case <-time.After(10 * time.Second):
+		t.Fatalf("job %s did not finish", id)
+	}
+	return j.info()
+}
+
+func TestBackgroundJob_CompletesAndNotifies(t *testing.T) {
+	t.Parallel()
+
+	m := newTestJobManager()
+	notifier := newRecordingNotifier()
+	env := jobEnv(t, "s1", notifier)
+
+	out := runTool(t, &backgroundTool{jobs: m}, env, map[string]any{"command": "echo first; echo second; exit 3"})
+	if out.IsError || out.Content != "started job-1" {
+		t.Fatalf("start = %+v", out)
+	}
+
+	info := waitJob(t, m, "s1", "job-1")
+	if info.State != jobExited || info.ExitCode != 3 {
+		t.Errorf("state = %s code %d, want exited code 3", info.State, info.ExitCode)
+	}
+
+	select {
+	case <-notifier.sent:
+	case <-time.After(10 * time.Second):
+		t.Fatal("no completion notification")
+	}
+	notifier.mu.Lock()
+	msg := notifier.msgs[0]
+	notifier.mu.Unlock()
+	if !strings.Contains(msg, "job-1 exited with code 3") || !strings.Contains(msg, "second") {
+		t.Errorf("notification = %q", msg)
+	}
+
+	out = runTool(t, &jobOutputTool{jobs: m}, env, map[string]any{"id": "job-1", "offset": 6})
+	if !strings.HasSuffix(out.Content, "]\nsecond\n") || !strings.Contains(out.Content, "next offset 13 of 13 bytes") {
+		t.Errorf("output = %q", out.Content)
+	}
+}
+
+func TestBackgroundJob_Kill(t *testing.T) {
+	t.Parallel()
+
+	m := newTestJobManager()
+	notifier := newRecordingNotifier()
+	env := jobEnv(t, "s1", notifier)
+
+	runTool(t, &backgroundTool{jobs: m}, env, map[string]any{"command": "sleep 30 & sleep 30"})
+
+	out := runTool(t, &jobStatusTool{jobs: m}, env, map[string]any{})
+	if !strings.Contains(out.Content, "job-1: running") {
+		t.Errorf("status = %q", out.Content)
+	}
+
+	out = runTool(t, &jobKillTool{jobs: m}, env, map[string]any{"id": "job-1"})
+	if out.IsError || !strings.Contains(out.Content, "was killed") {
+		t.Fatalf("kill = %+v", out)
+	}
+	select {
+	case <-notifier.sent:
+		t.Error("killed job notified the chat")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestBackgroundJob_Timeout(t *testing.T) {
+	t.Parallel()
+
+	m := newTestJobManager()
+	env := jobEnv(t, "s1", nil)
+
+	runTool(t, &backgroundTool{jobs: m}, env, map[string]any{"command": "sleep 30", "timeout_seconds": 1})
+	if info := waitJob(t, m, "s1", "job-1"); info.State != jobTimedOut {
+		t.Errorf("state = %s, want %s", info.State, jobTimedOut)
+	}
+}
+
+func TestBackgroundJob_SessionIsolationAndLimit(t *testing.T) {
+	t.Parallel()
+
+	m := newTestJobManager()
+	env := jobEnv(t, "s1", nil)
+	t.Cleanup(func() { m.stop(context.Background()) })
+
+	bg := &backgroundTool{jobs: m}
+	runTool(t, bg, env, map[string]any{"command": "sleep 30"})
+	runTool(t, bg, env, map[string]any{"command": "sleep 30"})
+	if out := runTool(t, bg, env, map[string]any{"command": "sleep 30"}); !out.IsError || !strings.Contains(out.Content, "too many running jobs") {
+		t.Errorf("third job = %+v", out)
+	}
+
+	other := jobEnv(t, "s2", nil)
+	if out := runTool(t, &jobOutputTool{jobs: m}, other, map[string]any{"id": "job-1"}); !out.IsError {
+		t.Errorf("other session read job-1: %+v", out)
+	}
+	if out := runTool(t, &jobKillTool{jobs: m}, other, map[string]any{"id": "job-1"}); !out.IsError {
+		t.Errorf("other session killed job-1: %+v", out)
+	}
+	if out := runTool(t, &jobStatusTool{jobs: m}, other, map[string]any{}); out.Content != "no background jobs" {
+		t.Errorf("other session status = %q", out.Content)
+	}
+}
+
+func TestSpoolWriter_Truncates(t *testing.T) {
+	t.Parallel()
+
+	m := newJobManager(1, time.Minute, 4, nil)
+	env := jobEnv(t, "s1", nil)
+
+	runTool(t, &backgroundTool{jobs: m}, env, map[string]any{"command": "echo 123456789"})
+	info := waitJob(t, m, "s1", "job-1")
+	if info.Size != 4 || !info.Truncated {
+		t.Errorf("size = %d truncated = %v, want 4 true", info.Size, info.Truncated)
+	}
+}
@@ -0,0 +1,21 @@
+//go:build unix
+
+package shell
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// killProcessGroup makes cancelling cmd kill the processes it spawned too,
+// so that killing a job does not leave its children running. It must not
+// be used on sandboxed commands: their cancellation already stops the whole
+// tree (the linux backend kills the PID namespace's init, the docker
+// backend kills the container), and replacing it would only kill the
+// docker CLI.
+func killProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+}
@@ -1,7 +1,9 @@
 package shell
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
 
 	"github.com/flemzord/sclaw/internal/core"
 	"github.com/flemzord/sclaw/internal/tool"
@@ -17,6 +19,7 @@ var (
 	_ core.Configurable = (*Module)(nil)
 	_ core.Provisioner  = (*Module)(nil)
 	_ core.Validator    = (*Module)(nil)
+	_ core.Stopper      = (*Module)(nil)
 	_ tool.Provider     = (*Module)(nil)
 )
 
@@ -24,6 +27,7 @@ var (
 type Module struct {
 	config Config
 	tool   *execTool
+	jobs   *jobManager
 }
 
 // ModuleInfo implements core.Module.
@@ -43,9 +47,15 @@ func (m *Module) Configure(node *yaml.Node) error {
 }
 
 // Provision implements core.Provisioner.
-func (m *Module) Provision(_ *core.AppContext) error {
+func (m *Module) Provision(ctx *core.AppContext) error {
 	m.config.defaults()
 
+	var logger *slog.Logger
+	if ctx != nil {
+		logger = ctx.Logger
+	}
+	m.jobs = newJobManager(m.config.MaxJobs, m.config.maxJobRuntimeDuration(), m.config.MaxJobOutput, logger)
+
 	m.tool = &execTool{
 		timeout:    m.config.timeoutDuration(),
 		maxTimeout: m.config.maxTimeoutDuration(),
@@ -60,7 +70,21 @@ func (m *Module) Validate() error {
 	return m.config.validate()
 }
 
+// Stop implements core.Stopper. It kills the running background jobs.
+func (m *Module) Stop(ctx context.Context) error {
+	if m.jobs != nil {
+		m.jobs.stop(ctx)
+	}
+	return nil
+}
+
 // Tools implements tool.Provider.
 func (m *Module) Tools() []tool.Tool {
-	return []tool.Tool{m.tool}
+	return []tool.Tool{
+		m.tool,
+		&backgroundTool{jobs: m.jobs, policy: m.tool.policy},
+		&jobStatusTool{jobs: m.jobs},
+		&jobOutputTool{jobs: m.jobs},
+		&jobKillTool{jobs: m.jobs},
+	}
 }
@@ -52,6 +52,16 @@ func TestConfigValidate(t *testing.T) {
 			cfg:     Config{Timeout: "30s", MaxTimeout: "10m", MaxOutputSize: 1024, DefaultPolicy: "invalid"},
 			wantErr: true,
 		},
+		{
+			name:    "zero max jobs",
+			cfg:     Config{Timeout: "30s", MaxTimeout: "10m", MaxOutputSize: 1024, DefaultPolicy: "allow", MaxJobs: -1, MaxJobRuntime: "1h", MaxJobOutput: 1024},
+			wantErr: true,
+		},
+		{
+			name:    "invalid max job runtime",
+			cfg:     Config{Timeout: "30s", MaxTimeout: "10m", MaxOutputSize: 1024, DefaultPolicy: "allow", MaxJobs: 1, MaxJobRuntime: "soon", MaxJobOutput: 1024},
+			wantErr: true,
+		},
 		{
 			name:    "negative max output",
 			cfg:     Config{Timeout: "30s", MaxTimeout: "10m", MaxOutputSize: -1, DefaultPolicy: "allow"},
@@ -79,10 +89,13 @@ func TestModuleToolProvider(t *testing.T) {
 
 	var tp tool.Provider = m
 	tools := tp.Tools()
-	if len(tools) != 1 {
-		t.Fatalf("Tools() returned %d tools, want 1", len(tools))
+	want := []string{"exec", "shell_background", "job_status", "job_output", "job_kill"}
+	if len(tools) != len(want) {
+		t.Fatalf("Tools() returned %d tools, want %d", len(tools), len(want))
 	}
-	if tools[0].Name() != "exec" {
-		t.Errorf("tool name = %q, want %q", tools[0].Name(), "exec")
+	for i, name := range want {
+		if tools[i].Name() != name {
+			t.Errorf("tools[%d] name = %q, want %q", i, tools[i].Name(), name)
+		}
 	}
 }
@@ -0,0 +1,207 @@
+package websearch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// result is a single search hit.
+type result struct {
+	Title   string
+	URL     string
+	Snippet string
+}
+
+// backend turns a query into an HTTP request and parses the response.
+type backend interface {
+	name() string
+	request(ctx context.Context, query string, count int) (*http.Request, error)
+	parse(body []byte) ([]result, error)
+}
+
+// newBackend builds the configured backend. apiKey is the resolved Brave key.
+func newBackend(cfg Config, apiKey string) backend {
+	switch cfg.Backend {
+	case backendBrave:
+		return &braveBackend{cfg: cfg.Brave, apiKey: apiKey}
+	case backendJSON:
+		return &jsonBackend{cfg: cfg.JSON}
+	default:
+		return &searxngBackend{cfg: cfg.SearXNG}
+	}
+}
+
+// --- SearXNG ---
+
+type searxngBackend struct {
+	cfg SearXNGConfig
+}
+
+func (b *searxngBackend) name() string { return backendSearXNG }
+
+func (b *searxngBackend) request(ctx context.Context, query string, _ int) (*http.Request, error) {
+	params := url.Values{"q": {query}, "format": {"json"}}
+	if b.cfg.Categories != "" {
+		params.Set("categories", b.cfg.Categories)
+	}
+	if b.cfg.Language != "" {
+		params.Set("language", b.cfg.Language)
+	}
+	endpoint := strings.TrimRight(b.cfg.URL, "/") + "/search?" + params.Encode()
+	return http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+}
+
+func (b *searxngBackend) parse(body []byte) ([]result, error) {
+	var resp struct {
+		Results []struct {
+			Title   string `json:"title"`
+			URL     string `json:"url"`
+			Content string `json:"content"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("decoding searxng response: %w", err)
+	}
+	results := make([]result, 0, len(resp.Results))
+	for _, r := range resp.Results {
+		results = append(results, result{Title: r.Title, URL: r.URL, Snippet: r.Content})
+	}
+	return results, nil
+}
+
+// --- Brave ---
+
+type braveBackend struct {
+	cfg    BraveConfig
+	apiKey string
+}
+
+func (b *braveBackend) name() string { return backendBrave }
+
+func (b *braveBackend) request(ctx context.Context, query string, count int) (*http.Request, error) {
+	params := url.Values{"q": {query}, "count": {strconv.Itoa(count)}}
+	if b.cfg.Country != "" {
+		params.Set("country", b.cfg.Country)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, b.cfg.URL+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Subscription-Token", b.apiKey)
+	return req, nil
+}
+
+func (b *braveBackend) parse(body []byte) ([]result, error) {
+	var resp struct {
+		Web struct {
+			Results []struct {
+				Title       string `json:"title"`
+				URL         string `json:"url"`
+				Description string `json:"description"`
+			} `json:"results"`
+		} `json:"web"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("decoding brave response: %w", err)
+	}
+	results := make([]result, 0, len(resp.Web.Results))
+	for _, r := range resp.Web.Results {
+		results = append(results, result{Title: r.Title, URL: r.URL, Snippet: r.Description})
+	}
+	return results, nil
+}
+
+// --- Generic JSON ---
+
+type jsonBackend struct {
+	cfg JSONConfig
+}
+
+func (b *jsonBackend) name() string { return backendJSON }
+
+func (b *jsonBackend) request(ctx context.Context, query string, count int) (*http.Request, error) {
+	n := strconv.Itoa(count)
+	endpoint := strings.NewReplacer("{query}", url.QueryEscape(query), "{count}", n).Replace(b.cfg.URL)
+
+	var req *http.Request
+	var err error
+	if b.cfg.Method == http.MethodPost {
+		quoted, _ := json.Marshal(query)
+		escaped := string(quoted[1 : len(quoted)-1])
+		body := strings.NewReplacer("{query}", escaped, "{count}", n).Replace(b.cfg.Body)
+		req, err = http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(body))
+		if err == nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+	} else {
+		req, err = http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	for k, v := range b.cfg.Headers {
+		req.Header.Set(k, v)
+	}
+	return req, nil
+}
+
+func (b *jsonBackend) parse(body []byte) ([]result, error) {
+	var doc any
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return nil, fmt.Errorf("decoding response: %w", err)
+	}
+	items, ok := lookupPath(doc, b.cfg.ResultsPath).([]any)
+	if !ok {
+		return nil, fmt.Errorf("no result array at %q", b.cfg.ResultsPath)
+	}
+	results := make([]result, 0, len(items))
+	for _, item := range items {
+		results = append(results, result{
+			Title:   stringAt(item, b.cfg.TitleField),
+			URL:     stringAt(item, b.cfg.URLField),
+			Snippet: stringAt(item, b.cfg.SnippetField),
+		})
+	}
+	return results, nil
+}
+
+// lookupPath follows a dot-separated path of object keys and array indexes.
+func lookupPath(v any, path string) any {
+	if path == "" {
+		return v
+	}
+	for key := range strings.SplitSeq(path, ".") {
+		switch node := v.(type) {
+		case map[string]any:
+			v = node[key]
+		case []any:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil
+			}
+			v = node[i]
+		default:
+			return nil
+		}
+	}
+	return v
+}
+
+// stringAt returns the value at path as a string, or "" if it is missing or
+// not a scalar.
+func stringAt(v any, path string) string {
+	switch s := lookupPath(v, path).(type) {
+	case string:
+		return s
+	case float64, bool:
+		return fmt.Sprint(s)
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,202 @@
+// Package websearch provides the web_search tool, which queries a search
+// backend (SearXNG, Brave, or any JSON API) and returns ranked results.
+// Results are cached per query, and every backend request goes through the
+// security URL filter and the per-session network rate limit.
+package websearch
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Supported backends.
+const (
+	backendSearXNG = "searxng"
+	backendBrave   = "brave"
+	backendJSON    = "json"
+)
+
+const (
+	defaultMaxResults   = 5
+	maxResultsLimit     = 20
+	defaultTimeout      = 15 * time.Second
+	defaultCacheTTL     = 10 * time.Minute
+	defaultBraveURL     = "https://api.search.brave.com/res/v1/web/search"
+	defaultMaxSnippet   = 300 // bytes of snippet kept per result
+	defaultMaxCacheSize = 256 // cached queries
+)
+
+// Config holds the tool.web_search module configuration.
+type Config struct {
+	// Backend selects the search backend: "searxng", "brave", or "json".
+	Backend string `yaml:"backend"`
+
+	// MaxResults is the default number of results returned. Defaults to 5;
+	// the agent may ask for up to 20.
+	MaxResults int `yaml:"max_results"`
+
+	// Timeout is the backend request timeout (e.g. "15s"). Defaults to 15s.
+	Timeout string `yaml:"timeout"`
+
+	// CacheTTL is how long results are cached per query (e.g. "10m").
+	// Defaults to 10m; "0s" disables the cache.
+	CacheTTL string `yaml:"cache_ttl"`
+
+	// DefaultPolicy is the default approval level: "allow", "ask", or "deny". Defaults to "allow".
+	DefaultPolicy string `yaml:"default_policy"`
+
+	SearXNG SearXNGConfig `yaml:"searxng"`
+	Brave   BraveConfig   `yaml:"brave"`
+	JSON    JSONConfig    `yaml:"json"`
+}
+
+// SearXNGConfig configures the SearXNG backend. The instance must have the
+// JSON output format enabled.
+type SearXNGConfig struct {
+	// URL is the base URL of the instance, e.g. "https://searx.example.org".
+	URL string `yaml:"url"`
+
+	// Categories is an optional comma-separated list, e.g. "general,news".
+	Categories string `yaml:"categories"`
+
+	// Language is an optional language code, e.g. "en".
+	Language string `yaml:"language"`
+}
+
+// BraveConfig configures the Brave Search API backend.
+type BraveConfig struct {
+	APIKey    string `yaml:"api_key"`
+	APIKeyEnv string `yaml:"api_key_env"`
+
+	// Country is an optional two-letter country code, e.g. "us".
+	Country string `yaml:"country"`
+
+	// URL overrides the API endpoint. Defaults to the public Brave API.
+	URL string `yaml:"url"`
+}
+
+// JSONConfig configures a generic JSON API backend through templates.
+type JSONConfig struct {
+	// URL is the request URL. "{query}" and "{count}" are replaced by the
+	// URL-encoded query and the number of results wanted.
+	URL string `yaml:"url"`
+
+	// Method is "GET" (default) or "POST".
+	Method string `yaml:"method"`
+
+	// Body is the POST body. "{query}" is replaced by the query escaped for
+	// use inside a JSON string, "{count}" by the number of results.
+	Body string `yaml:"body"`
+
+	// Headers are sent with every request, e.g. an API key.
+	Headers map[string]string `yaml:"headers"`
+
+	// ResultsPath is the dot-separated path to the result array in the
+	// response, e.g. "data.items". Empty means the response is the array.
+	ResultsPath string `yaml:"results_path"`
+
+	// TitleField, URLField and SnippetField are dot-separated paths within
+	// each result. They default to "title", "url" and "snippet".
+	TitleField   string `yaml:"title_field"`
+	URLField     string `yaml:"url_field"`
+	SnippetField string `yaml:"snippet_field"`
+}
+
+func (c *Config) defaults() {
+	if c.MaxResults == 0 {
+		c.MaxResults = defaultMaxResults
+	}
+	if c.Timeout == "" {
+		c.Timeout = defaultTimeout.String()
+	}
+	if c.CacheTTL == "" {
+		c.CacheTTL = defaultCacheTTL.String()
+	}
+	if c.DefaultPolicy == "" {
+		c.DefaultPolicy = "allow"
+	}
+	if c.Brave.URL == "" {
+		c.Brave.URL = defaultBraveURL
+	}
+	if c.JSON.Method == "" {
+		c.JSON.Method = http.MethodGet
+	}
+	c.JSON.Method = strings.ToUpper(c.JSON.Method)
+	if c.JSON.TitleField == "" {
+		c.JSON.TitleField = "title"
+	}
+	if c.JSON.URLField == "" {
+		c.JSON.URLField = "url"
+	}
+	if c.JSON.SnippetField == "" {
+		c.JSON.SnippetField = "snippet"
+	}
+}
+
+func (c *Config) validate() error {
+	switch c.Backend {
+	case backendSearXNG:
+		if err := validateURL("searxng.url", c.SearXNG.URL); err != nil {
+			return err
+		}
+	case backendBrave:
+		if c.Brave.APIKey == "" && c.Brave.APIKeyEnv == "" {
+			return fmt.Errorf("web_search: one of brave.api_key or brave.api_key_env is required")
+		}
+		if err := validateURL("brave.url", c.Brave.URL); err != nil {
+			return err
+		}
+	case backendJSON:
+		if err := validateURL("json.url", c.JSON.URL); err != nil {
+			return err
+		}
+		if c.JSON.Method != http.MethodGet && c.JSON.Method != http.MethodPost {
+			return fmt.Errorf("web_search: invalid json.method %q (must be GET or POST)", c.JSON.Method)
+		}
+	case "":
+		return fmt.Errorf("web_search: backend is required (searxng, brave, or json)")
+	default:
+		return fmt.Errorf("web_search: unknown backend %q (must be searxng, brave, or json)", c.Backend)
+	}
+	if c.MaxResults < 1 || c.MaxResults > maxResultsLimit {
+		return fmt.Errorf("web_search: max_results must be between 1 and %d, got %d", maxResultsLimit, c.MaxResults)
+	}
+	if _, err := time.ParseDuration(c.Timeout); err != nil {
+		return fmt.Errorf("web_search: invalid timeout %q: %w", c.Timeout, err)
+	}
+	if d, err := time.ParseDuration(c.CacheTTL); err != nil || d < 0 {
+		return fmt.Errorf("web_search: invalid cache_ttl %q", c.CacheTTL)
+	}
+	switch c.DefaultPolicy {
+	case "allow", "ask", "deny":
+	default:
+		return fmt.Errorf("web_search: invalid default_policy %q (must be allow, ask, or deny)", c.DefaultPolicy)
+	}
+	return nil
+}
+
+// validateURL checks that a configured endpoint is an absolute http(s) URL.
+// Template placeholders are allowed.
+func validateURL(field, raw string) error {
+	if raw == "" {
+		return fmt.Errorf("web_search: %s is required", field)
+	}
+	u, err := url.Parse(strings.NewReplacer("{query}", "q", "{count}", "1").Replace(raw))
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return fmt.Errorf("web_search: %s must be an absolute http or https URL, got %q", field, raw)
+	}
+	return nil
+}
+
+func (c *Config) timeoutDuration() time.Duration {
+	d, _ := time.ParseDuration(c.Timeout)
+	return d
+}
+
+func (c *Config) cacheTTLDuration() time.Duration {
+	d, _ := time.ParseDuration(c.CacheTTL)
+	return d
+}
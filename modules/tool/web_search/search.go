@@ -0,0 +1,263 @@
+package websearch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/flemzord/sclaw/internal/security"
+	"github.com/flemzord/sclaw/internal/tool"
+)
+
+// maxResponseSize bounds the backend response read.
+const maxResponseSize = 4 << 20 // 4 MiB
+
+type searchTool struct {
+	cfg     Config
+	backend backend
+	client  *http.Client
+	limiter *security.RateLimiter
+	cache   *resultCache
+}
+
+func newSearchTool(cfg Config, b backend, limiter *security.RateLimiter) *searchTool {
+	return &searchTool{
+		cfg:     cfg,
+		backend: b,
+		client:  &http.Client{Timeout: cfg.timeoutDuration()},
+		limiter: limiter,
+		cache:   newResultCache(cfg.cacheTTLDuration(), defaultMaxCacheSize),
+	}
+}
+
+func (t *searchTool) Name() string { return "web_search" }
+
+func (t *searchTool) Description() string {
+	return "Search the web. Returns ranked results with title, URL and snippet; use http_fetch to read a result."
+}
+
+func (t *searchTool) Scopes() []tool.Scope {
+	return []tool.Scope{tool.ScopeNetwork}
+}
+
+func (t *searchTool) DefaultPolicy() tool.ApprovalLevel {
+	return tool.ApprovalLevel(t.cfg.DefaultPolicy)
+}
+
+func (t *searchTool) Schema() json.RawMessage {
+	return json.RawMessage(fmt.Sprintf(`{
+		"type": "object",
+		"properties": {
+			"query": {"type": "string", "description": "The search query."},
+			"count": {"type": "integer", "minimum": 1, "maximum": %d, "description": "Number of results (default %d)."}
+		},
+		"required": ["query"]
+	}`, maxResultsLimit, t.cfg.MaxResults))
+}
+
+type searchArgs struct {
+	Query string `json:"query"`
+	Count int    `json:"count,omitempty"`
+}
+
+func (t *searchTool) Execute(ctx context.Context, args json.RawMessage, env tool.ExecutionEnv) (tool.Output, error) {
+	var a searchArgs
+	if err := json.Unmarshal(args, &a); err != nil {
+		return tool.Output{Content: fmt.Sprintf("invalid arguments: %v", err), IsError: true}, nil
+	}
+	query := strings.Join(strings.Fields(a.Query), " ")
+	if query == "" {
+		return tool.Output{Content: "query is required", IsError: true}, nil
+	}
+	count := t.cfg.MaxResults
+	if a.Count > 0 {
+		count = min(a.Count, maxResultsLimit)
+	}
+
+	key := cacheKey(t.backend.name(), query, count)
+	if results, ok := t.cache.get(key); ok {
+		return tool.Output{Content: formatResults(query, results)}, nil
+	}
+
+	results, err := t.search(ctx, query, count, env)
+	if err != nil {
+		return tool.Output{Content: fmt.Sprintf("search failed: %v", err), IsError: true}, nil
+	}
+	t.cache.put(key, results)
+	return tool.Output{Content: formatResults(query, results)}, nil
+}
+
+// search queries the backend. The request is checked against the URL filter
+// and counted against the session's network rate limit.
+func (t *searchTool) search(ctx context.Context, query string, count int, env tool.ExecutionEnv) ([]result, error) {
+	req, err := t.backend.request(ctx, query, count)
+	if err != nil {
+		return nil, err
+	}
+	if env.URLFilter != nil {
+		if err := env.URLFilter.Check(req.URL.String()); err != nil {
+			return nil, err
+		}
+	}
+	if t.limiter != nil {
+		if err := t.limiter.Allow(env.SessionID, "network"); err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return nil, fmt.Errorf("reading response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned HTTP %d: %s", t.backend.name(), resp.StatusCode, truncate(strings.TrimSpace(string(body)), 200))
+	}
+
+	results, err := t.backend.parse(body)
+	if err != nil {
+		return nil, err
+	}
+	return cleanResults(results, count), nil
+}
+
+var tagPattern = regexp.MustCompile(`<[^>]*>`)
+
+// cleanResults strips markup from titles and snippets, drops entries without
+// a URL, and keeps at most count results in backend order.
+func cleanResults(results []result, count int) []result {
+	out := make([]result, 0, min(len(results), count))
+	for _, r := range results {
+		if len(out) == count {
+			break
+		}
+		if r.URL == "" {
+			continue
+		}
+		out = append(out, result{
+			Title:   cleanText(r.Title),
+			URL:     r.URL,
+			Snippet: truncate(cleanText(r.Snippet), defaultMaxSnippet),
+		})
+	}
+	return out
+}
+
+func cleanText(s string) string {
+	s = html.UnescapeString(tagPattern.ReplaceAllString(s, ""))
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// truncate cuts s to at most limit bytes on a rune boundary.
+func truncate(s string, limit int) string {
+	if len(s) <= limit {
+		return s
+	}
+	i := limit
+	for i > 0 && !utf8.RuneStart(s[i]) {
+		i--
+	}
+	return s[:i] + "…"
+}
+
+// formatResults renders results as a compact numbered list.
+func formatResults(query string, results []result) string {
+	if len(results) == 0 {
+		return fmt.Sprintf("No results for %q.", query)
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Results for %q:\n", query)
+	for i, r := range results {
+		title := r.Title
+		if title == "" {
+			title = r.URL
+		}
+		fmt.Fprintf(&sb, "\n%d. %s\n   %s\n", i+1, title, r.URL)
+		if r.Snippet != "" {
+			fmt.Fprintf(&sb, "   %s\n", r.Snippet)
+		}
+	}
+	return sb.String()
+}
+
+// cacheKey identifies a query. Queries differing only in case share results.
+func cacheKey(backend, query string, count int) string {
+	return fmt.Sprintf("%s\x00%d\x00%s", backend, count, strings.ToLower(query))
+}
+
+// resultCache holds recent results for a fixed TTL. A zero TTL disables it.
+type resultCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	max     int
+	entries map[string]cacheEntry
+	now     func() time.Time
+}
+
+type cacheEntry struct {
+	results []result
+	expires time.Time
+}
+
+func newResultCache(ttl time.Duration, maxEntries int) *resultCache {
+	return &resultCache{
+		ttl:     ttl,
+		max:     maxEntries,
+		entries: make(map[string]cacheEntry),
+		now:     time.Now,
+	}
+}
+
+func (c *resultCache) get(key string) ([]result, bool) {
+	if c.ttl <= 0 {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if !c.now().Before(e.expires) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return e.results, true
+}
+
+func (c *resultCache) put(key string, results []result) {
+	if c.ttl <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	if len(c.entries) >= c.max {
+		// Drop expired entries, then the one closest to expiry.
+		var oldest string
+		for k, e := range c.entries {
+			if !now.Before(e.expires) {
+				delete(c.entries, k)
+			} else if oldest == "" || e.expires.Before(c.entries[oldest].expires) {
+				oldest = k
+			}
+		}
+		if len(c.entries) >= c.max {
+			delete(c.entries, oldest)
+		}
+	}
+	c.entries[key] = cacheEntry{results: results, expires: now.Add(c.ttl)}
+}
@@ -0,0 +1,80 @@
+package websearch
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/flemzord/sclaw/internal/core"
+	"github.com/flemzord/sclaw/internal/security"
+	"github.com/flemzord/sclaw/internal/tool"
+	"gopkg.in/yaml.v3"
+)
+
+func init() {
+	core.RegisterModule(&Module{})
+}
+
+// Compile-time interface guards.
+var (
+	_ core.Configurable = (*Module)(nil)
+	_ core.Provisioner  = (*Module)(nil)
+	_ core.Validator    = (*Module)(nil)
+	_ tool.Provider     = (*Module)(nil)
+)
+
+// Module implements the web_search tool module.
+type Module struct {
+	config Config
+	tool   *searchTool
+}
+
+// ModuleInfo implements core.Module.
+func (m *Module) ModuleInfo() core.ModuleInfo {
+	return core.ModuleInfo{
+		ID:  "tool.web_search",
+		New: func() core.Module { return &Module{} },
+	}
+}
+
+// Configure implements core.Configurable.
+func (m *Module) Configure(node *yaml.Node) error {
+	if err := node.Decode(&m.config); err != nil {
+		return fmt.Errorf("web_search: decode config: %w", err)
+	}
+	return nil
+}
+
+// Provision implements core.Provisioner.
+func (m *Module) Provision(ctx *core.AppContext) error {
+	m.config.defaults()
+
+	// api_key_env takes precedence over the literal api_key.
+	apiKey := m.config.Brave.APIKey
+	if m.config.Backend == backendBrave && m.config.Brave.APIKeyEnv != "" {
+		v, ok := os.LookupEnv(m.config.Brave.APIKeyEnv)
+		if !ok || v == "" {
+			return fmt.Errorf("web_search: env var %q is empty or unset", m.config.Brave.APIKeyEnv)
+		}
+		apiKey = v
+	}
+
+	var limiter *security.RateLimiter
+	if ctx != nil {
+		if svc, ok := ctx.GetService("security.ratelimiter"); ok {
+			limiter, _ = svc.(*security.RateLimiter)
+		}
+	}
+
+	m.tool = newSearchTool(m.config, newBackend(m.config, apiKey), limiter)
+	return nil
+}
+
+// Validate implements core.Validator.
+func (m *Module) Validate() error {
+	return m.config.validate()
+}
+
+// Tools implements tool.Provider.
+func (m *Module) Tools() []tool.Tool {
+	return []tool.Tool{m.tool}
+}
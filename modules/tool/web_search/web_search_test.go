@@ -0,0 +1,301 @@
+package websearch
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/flemzord/sclaw/internal/security"
+	"github.com/flemzord/sclaw/internal/tool"
+)
+
+func TestConfigDefaults(t *testing.T) {
+	t.Parallel()
+
+	var c Config
+	c.defaults()
+
+	if c.MaxResults != 5 {
+		t.Errorf("MaxResults = %d, want 5", c.MaxResults)
+	}
+	if c.Timeout != "15s" {
+		t.Errorf("Timeout = %q, want %q", c.Timeout, "15s")
+	}
+	if c.CacheTTL != "10m0s" {
+		t.Errorf("CacheTTL = %q, want %q", c.CacheTTL, "10m0s")
+	}
+	if c.DefaultPolicy != "allow" {
+		t.Errorf("DefaultPolicy = %q, want %q", c.DefaultPolicy, "allow")
+	}
+	if c.JSON.Method != "GET" || c.JSON.TitleField != "title" || c.JSON.URLField != "url" || c.JSON.SnippetField != "snippet" {
+		t.Errorf("JSON defaults = %+v", c.JSON)
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	t.Parallel()
+
+	with := func(mod func(*Config)) Config {
+		c := Config{Backend: "searxng", SearXNG: SearXNGConfig{URL: "https://searx.example.org"}}
+		mod(&c)
+		c.defaults()
+		return c
+	}
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{
+		{name: "searxng", cfg: with(func(*Config) {})},
+		{name: "missing backend", cfg: with(func(c *Config) { c.Backend = "" }), wantErr: true},
+		{name: "unknown backend", cfg: with(func(c *Config) { c.Backend = "altavista" }), wantErr: true},
+		{name: "searxng without url", cfg: with(func(c *Config) { c.SearXNG.URL = "" }), wantErr: true},
+		{name: "brave", cfg: with(func(c *Config) { c.Backend = "brave"; c.Brave.APIKeyEnv = "BRAVE_API_KEY" })},
+		{name: "brave without key", cfg: with(func(c *Config) { c.Backend = "brave" }), wantErr: true},
+		{name: "json template", cfg: with(func(c *Config) { c.Backend = "json"; c.JSON.URL = "https://api.example.com/s?q={query}&n={count}" })},
+		{name: "json bad method", cfg: with(func(c *Config) { c.Backend = "json"; c.JSON.URL = "https://api.example.com/"; c.JSON.Method = "PUT" }), wantErr: true},
+		{name: "json relative url", cfg: with(func(c *Config) { c.Backend = "json"; c.JSON.URL = "/search?q={query}" }), wantErr: true},
+		{name: "too many results", cfg: with(func(c *Config) { c.MaxResults = 50 }), wantErr: true},
+		{name: "invalid cache ttl", cfg: with(func(c *Config) { c.CacheTTL = "forever" }), wantErr: true},
+		{name: "cache disabled", cfg: with(func(c *Config) { c.CacheTTL = "0s" })},
+		{name: "invalid policy", cfg: with(func(c *Config) { c.DefaultPolicy = "maybe" }), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := tt.cfg.validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestModuleToolProvider(t *testing.T) {
+	t.Parallel()
+
+	m := &Module{config: Config{Backend: "searxng", SearXNG: SearXNGConfig{URL: "https://searx.example.org"}}}
+	_ = m.Provision(nil)
+
+	var tp tool.Provider = m
+	tools := tp.Tools()
+	if len(tools) != 1 {
+		t.Fatalf("Tools() returned %d tools, want 1", len(tools))
+	}
+	if tools[0].Name() != "web_search" {
+		t.Errorf("tool name = %q, want %q", tools[0].Name(), "web_search")
+	}
+	if scopes := tools[0].Scopes(); len(scopes) != 1 || scopes[0] != tool.ScopeNetwork {
+		t.Errorf("scopes = %v, want [network]", scopes)
+	}
+}
+
+// newTestTool points the configured backend at handler.
+func newTestTool(t *testing.T, cfg Config, handler http.HandlerFunc, limiter *security.RateLimiter) (*searchTool, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		handler(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	cfg.SearXNG.URL = srv.URL
+	cfg.Brave.URL = srv.URL + "/brave"
+	cfg.JSON.URL = strings.Replace(cfg.JSON.URL, "{server}", srv.URL, 1)
+	cfg.defaults()
+	if err := cfg.validate(); err != nil {
+		t.Fatalf("validate() error: %v", err)
+	}
+	return newSearchTool(cfg, newBackend(cfg, cfg.Brave.APIKey), limiter), &calls
+}
+
+func search(t *testing.T, st *searchTool, args map[string]any, env tool.ExecutionEnv) tool.Output {
+	t.Helper()
+	raw, _ := json.Marshal(args)
+	out, err := st.Execute(context.Background(), raw, env)
+	if err != nil {
+		t.Fatalf("Execute() error: %v", err)
+	}
+	return out
+}
+
+func TestSearch_SearXNG(t *testing.T) {
+	t.Parallel()
+
+	var got url.Values
+	st, _ := newTestTool(t, Config{Backend: "searxng", SearXNG: SearXNGConfig{Categories: "news"}}, func(w http.ResponseWriter, r *http.Request) {
+		got = r.URL.Query()
+		_, _ = io.WriteString(w, `{"results": [
+			{"title": "Go &amp; you", "url": "https://go.dev/", "content": "The <b>Go</b> programming language."},
+			{"title": "No URL", "url": ""},
+			{"title": "Second", "url": "https://example.com/2", "content": ""},
+			{"title": "Third", "url": "https://example.com/3", "content": "three"}
+		]}`)
+	}, nil)
+
+	out := search(t, st, map[string]any{"query": "  golang   tutorial ", "count": 2}, tool.ExecutionEnv{})
+	if out.IsError {
+		t.Fatalf("unexpected error: %s", out.Content)
+	}
+	want := "Results for \"golang tutorial\":\n\n" +
+		"1. Go & you\n   https://go.dev/\n   The Go programming language.\n\n" +
+		"2. Second\n   https://example.com/2\n"
+	if out.Content != want {
+		t.Errorf("output =\n%s\nwant\n%s", out.Content, want)
+	}
+	if got.Get("q") != "golang tutorial" || got.Get("format") != "json" || got.Get("categories") != "news" {
+		t.Errorf("query params = %v", got)
+	}
+}
+
+func TestSearch_Brave(t *testing.T) {
+	t.Parallel()
+
+	st, _ := newTestTool(t, Config{Backend: "brave", Brave: BraveConfig{APIKey: "secret"}}, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/brave" || r.Header.Get("X-Subscription-Token") != "secret" || r.URL.Query().Get("count") != "5" {
+			http.Error(w, "bad request", http.StatusUnauthorized)
+			return
+		}
+		_, _ = io.WriteString(w, `{"web": {"results": [{"title": "Brave", "url": "https://brave.com/", "description": "A <strong>browser</strong>."}]}}`)
+	}, nil)
+
+	out := search(t, st, map[string]any{"query": "brave"}, tool.ExecutionEnv{})
+	if out.IsError || !strings.Contains(out.Content, "1. Brave\n   https://brave.com/\n   A browser.") {
+		t.Errorf("output = %s", out.Content)
+	}
+}
+
+func TestSearch_JSONTemplate(t *testing.T) {
+	t.Parallel()
+
+	cfg := Config{Backend: "json", JSON: JSONConfig{
+		URL:          "{server}/v1/search?n={count}",
+		Method:       "post",
+		Body:         `{"q": "{query}"}`,
+		Headers:      map[string]string{"Authorization": "Bearer k"},
+		ResultsPath:  "data.items",
+		TitleField:   "name",
+		URLField:     "link.href",
+		SnippetField: "summary",
+	}}
+	st, _ := newTestTool(t, cfg, func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Q string `json:"q"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || r.Header.Get("Authorization") != "Bearer k" || r.URL.Query().Get("n") != "5" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		name, _ := json.Marshal(body.Q)
+		_, _ = io.WriteString(w, `{"data": {"items": [{"name": `+string(name)+`, "link": {"href": "https://example.com/"}, "summary": 42}]}}`)
+	}, nil)
+
+	out := search(t, st, map[string]any{"query": `say "hi"`}, tool.ExecutionEnv{})
+	if out.IsError || !strings.Contains(out.Content, "1. say \"hi\"\n   https://example.com/\n   42") {
+		t.Errorf("output = %s", out.Content)
+	}
+}
+
+func TestSearch_CachePerQuery(t *testing.T) {
+	t.Parallel()
+
+	st, calls := newTestTool(t, Config{Backend: "searxng"}, func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, `{"results": [{"title": "A", "url": "https://a.example/"}]}`)
+	}, nil)
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	st.cache.now = func() time.Time { return now }
+
+	search(t, st, map[string]any{"query": "Cats"}, tool.ExecutionEnv{})
+	search(t, st, map[string]any{"query": "cats"}, tool.ExecutionEnv{})
+	if n := calls.Load(); n != 1 {
+		t.Errorf("backend calls = %d, want 1 (cached)", n)
+	}
+	search(t, st, map[string]any{"query": "dogs"}, tool.ExecutionEnv{})
+	if n := calls.Load(); n != 2 {
+		t.Errorf("backend calls = %d, want 2 (new query)", n)
+	}
+
+	now = now.Add(11 * time.Minute)
+	search(t, st, map[string]any{"query": "cats"}, tool.ExecutionEnv{})
+	if n := calls.Load(); n != 3 {
+		t.Errorf("backend calls = %d, want 3 (expired)", n)
+	}
+}
+
+func TestSearch_Gates(t *testing.T) {
+	t.Parallel()
+
+	handler := func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, `{"results": []}`)
+	}
+
+	t.Run("url filter", func(t *testing.T) {
+		t.Parallel()
+		st, calls := newTestTool(t, Config{Backend: "searxng"}, handler, nil)
+		env := tool.ExecutionEnv{URLFilter: security.NewURLFilter(security.URLFilterConfig{AllowDomains: []string{"searx.example.org"}})}
+		out := search(t, st, map[string]any{"query": "x"}, env)
+		if !out.IsError || !strings.Contains(out.Content, "blocked") || calls.Load() != 0 {
+			t.Errorf("output = %s, calls = %d", out.Content, calls.Load())
+		}
+	})
+
+	t.Run("rate limit", func(t *testing.T) {
+		t.Parallel()
+		limiter := security.NewRateLimiter(security.RateLimitConfig{NetworkPerMin: 1})
+		st, _ := newTestTool(t, Config{Backend: "searxng"}, handler, limiter)
+		env := tool.ExecutionEnv{SessionID: "s1"}
+		if out := search(t, st, map[string]any{"query": "one"}, env); out.IsError || out.Content != `No results for "one".` {
+			t.Fatalf("first search: %s", out.Content)
+		}
+		// Cached queries do not count.
+		if out := search(t, st, map[string]any{"query": "one"}, env); out.IsError {
+			t.Fatalf("cached search: %s", out.Content)
+		}
+		if out := search(t, st, map[string]any{"query": "two"}, env); !out.IsError || !strings.Contains(out.Content, "rate limit") {
+			t.Errorf("second search not rate limited: %s", out.Content)
+		}
+	})
+
+	t.Run("backend error", func(t *testing.T) {
+		t.Parallel()
+		st, _ := newTestTool(t, Config{Backend: "searxng"}, func(w http.ResponseWriter, _ *http.Request) {
+			http.Error(w, "too many requests", http.StatusTooManyRequests)
+		}, nil)
+		out := search(t, st, map[string]any{"query": "x"}, tool.ExecutionEnv{})
+		if !out.IsError || !strings.Contains(out.Content, "HTTP 429: too many requests") {
+			t.Errorf("output = %s", out.Content)
+		}
+	})
+}
+
+func TestResultCache_Eviction(t *testing.T) {
+	t.Parallel()
+
+	c := newResultCache(time.Minute, 2)
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+
+	c.put("a", nil)
+	now = now.Add(time.Second)
+	c.put("b", nil)
+	now = now.Add(time.Second)
+	c.put("c", nil)
+
+	if _, ok := c.get("a"); ok {
+		t.Error("oldest entry was not evicted")
+	}
+	for _, k := range []string{"b", "c"} {
+		if _, ok := c.get(k); !ok {
+			t.Errorf("entry %q missing", k)
+		}
+	}
+}
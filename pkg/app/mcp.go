@@ -0,0 +1,40 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/flemzord/sclaw/internal/mcp"
+	"github.com/flemzord/sclaw/internal/security"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// ServeMCP publishes an agent as an MCP server on stdin/stdout until ctx is
+// cancelled or stdin is closed. Modules are loaded but not started, so no
+// channel receives messages: tools needing approval are refused. Logs go to
+// stderr.
+func ServeMCP(ctx context.Context, params RunParams, agentID string) error {
+	l, err := load(params)
+	if err != nil {
+		return err
+	}
+
+	// No module is started: credentials registered so far are all there is.
+	l.redactor.SyncCredentials(l.credStore)
+	l.appCtx.RegisterService("security.sanitized_env", security.SanitizedEnv(l.credStore))
+
+	factory, err := newFactory(l.app, l.appCtx, l.ids, l.logger, l.auditLogger, l.rateLimiter, nil, "")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = factory.Close() }()
+
+	s, err := mcp.NewPublisher(factory.ForMCP, l.logger).Server(agentID)
+	if err != nil {
+		return fmt.Errorf("mcp: serving agent %q: %w", agentID, err)
+	}
+
+	l.logger.Info("mcp: serving agent over stdio", "agent", agentID)
+	return server.NewStdioServer(s).Listen(ctx, os.Stdin, os.Stdout)
+}
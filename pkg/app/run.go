@@ -2,6 +2,7 @@
 package app
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"log/slog"
@@ -10,6 +11,7 @@ import (
 	"path/filepath"
 	"syscall"
 
+	"github.com/flemzord/sclaw/internal/activity"
 	"github.com/flemzord/sclaw/internal/bootstrap"
 	"github.com/flemzord/sclaw/internal/config"
 	"github.com/flemzord/sclaw/internal/core"
@@ -54,25 +56,41 @@ func Run(params RunParams) error {
 	return RunWithContext(ctx, params)
 }
 
-// RunWithContext is like Run but accepts an external context for shutdown.
-// When ctx is cancelled, the application shuts down gracefully. SIGHUP is
-// still handled for live configuration reload.
-func RunWithContext(ctx context.Context, params RunParams) error {
+// loaded is the state shared by RunWithContext and ServeMCP once modules
+// are loaded but not started.
+type loaded struct {
+	cfg         *config.Config
+	cfgPath     string
+	logger      *slog.Logger
+	credStore   *security.CredentialStore
+	redactor    *security.Redactor
+	auditLogger *security.AuditLogger
+	rateLimiter *security.RateLimiter
+	dataDir     string
+	workspace   string
+	appCtx      *core.AppContext
+	app         *core.App
+	ids         []string
+}
+
+// load reads the configuration, sets up logging and security services, and
+// loads every configured module.
+func load(params RunParams) (*loaded, error) {
 	cfgPath := params.ConfigPath
 	if cfgPath == "" {
 		resolved, err := ResolveConfigPath()
 		if err != nil {
-			return err
+			return nil, err
 		}
 		cfgPath = resolved
 	}
 
 	cfg, err := config.Load(cfgPath)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if err := config.Validate(cfg); err != nil {
-		return err
+		return nil, err
 	}
 
 	// Initialize credential store and redactor (security foundation).
@@ -100,6 +118,7 @@ func RunWithContext(ctx context.Context, params RunParams) error {
 			MessagesPerMin:  rl.MessagesPerMin,
 			ToolCallsPerMin: rl.ToolCallsPerMin,
 			TokensPerHour:   rl.TokensPerHour,
+			NetworkPerMin:   rl.NetworkPerMin,
 		})
 	} else {
 		rateLimiter = security.NewRateLimiter(security.RateLimitConfig{})
@@ -123,19 +142,23 @@ func RunWithContext(ctx context.Context, params RunParams) error {
 	appCtx.RegisterService("security.audit", auditLogger)
 	appCtx.RegisterService("security.ratelimiter", rateLimiter)
 
+	// Live activity events, streamed by the gateway. Registered before
+	// modules are provisioned so that providers can publish failovers.
+	appCtx.RegisterService("activity.bus", activity.NewBus())
+
 	// Parse and register multi-agent configuration if present.
 	if len(cfg.Agents) > 0 {
 		agents, order, err := multiagent.ParseAgents(cfg.Agents)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		multiagent.ResolveDefaults(agents, dataDir)
 		if err := multiagent.EnsureDirectories(agents); err != nil {
-			return err
+			return nil, err
 		}
 		registry, err := multiagent.NewRegistry(agents, order)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		appCtx.RegisterService("multiagent.registry", registry)
 		appCtx.RegisterService("multiagent.agents", agents)
@@ -145,8 +168,9 @@ func RunWithContext(ctx context.Context, params RunParams) error {
 	// Register the config path so modules (e.g. the gateway) can discover it.
 	appCtx.RegisterService("config.path", cfgPath)
 
-	// Build and register URL filter if configured.
-	if cfg.Security != nil && len(cfg.Security.URLFilter.AllowDomains) > 0 {
+	// Build and register URL filter if configured. A deny-only filter is
+	// still default-deny, as documented.
+	if cfg.Security != nil && (len(cfg.Security.URLFilter.AllowDomains) > 0 || len(cfg.Security.URLFilter.DenyDomains) > 0) {
 		urlFilter := security.NewURLFilter(security.URLFilterConfig{
 			AllowDomains: cfg.Security.URLFilter.AllowDomains,
 			DenyDomains:  cfg.Security.URLFilter.DenyDomains,
@@ -154,16 +178,68 @@ func RunWithContext(ctx context.Context, params RunParams) error {
 		appCtx.RegisterService("security.urlfilter", urlFilter)
 	}
 
+	// Build and register the tool sandbox if enabled.
+	if cfg.Security != nil && cfg.Security.Sandbox.Enabled {
+		sc := cfg.Security.Sandbox
+		policy := security.SandboxPolicy{Enabled: true, ScopesRequiringSandbox: sc.ScopesRequiringSandbox}
+		sandbox, err := security.NewSandbox(security.SandboxOptions{
+			Backend: sc.Backend,
+			Policy:  policy,
+			Limits: security.ResourceLimits{
+				CPUShares: sc.CPUShares,
+				MemoryMB:  sc.MemoryMB,
+				DiskMB:    sc.DiskMB,
+			},
+			Image:        sc.Image,
+			CgroupParent: sc.CgroupParent,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("security.sandbox: %w", err)
+		}
+		appCtx.RegisterService("security.sandbox_policy", policy)
+		appCtx.RegisterService("security.sandbox", sandbox)
+		logger.Info("tool sandbox enabled", "backend", cmp.Or(sc.Backend, security.SandboxBackendDocker))
+	}
+
 	application := core.NewApp(appCtx)
 	ids := config.Resolve(cfg)
 	if err := application.LoadModules(ids); err != nil {
+		return nil, err
+	}
+
+	return &loaded{
+		cfg:         cfg,
+		cfgPath:     cfgPath,
+		logger:      logger,
+		credStore:   credStore,
+		redactor:    redactor,
+		auditLogger: auditLogger,
+		rateLimiter: rateLimiter,
+		dataDir:     dataDir,
+		workspace:   workspace,
+		appCtx:      appCtx,
+		app:         application,
+		ids:         ids,
+	}, nil
+}
+
+// RunWithContext is like Run but accepts an external context for shutdown.
+// When ctx is cancelled, the application shuts down gracefully. SIGHUP is
+// still handled for live configuration reload.
+func RunWithContext(ctx context.Context, params RunParams) error {
+	l, err := load(params)
+	if err != nil {
 		return err
 	}
+	cfg, cfgPath, logger := l.cfg, l.cfgPath, l.logger
+	credStore, redactor := l.credStore, l.redactor
+	dataDir, workspace := l.dataDir, l.workspace
+	appCtx, application, ids := l.appCtx, l.app, l.ids
 
 	// Wire the router between LoadModules and Start: discover channels and
 	// providers, create the dispatcher and agent factory, call SetInbox on
 	// every channel, and append the router to the app lifecycle.
-	if err := wireRouter(application, appCtx, ids, logger, auditLogger, rateLimiter, cfg.Router); err != nil {
+	if err := wireRouter(application, appCtx, ids, logger, l.auditLogger, l.rateLimiter, cfg.Router); err != nil {
 		return err
 	}
 
@@ -7,11 +7,14 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/flemzord/sclaw/internal/activity"
 	"github.com/flemzord/sclaw/internal/channel"
 	"github.com/flemzord/sclaw/internal/config"
 	"github.com/flemzord/sclaw/internal/core"
 	"github.com/flemzord/sclaw/internal/cron"
+	"github.com/flemzord/sclaw/internal/gateway"
 	"github.com/flemzord/sclaw/internal/hook"
+	"github.com/flemzord/sclaw/internal/mcp"
 	"github.com/flemzord/sclaw/internal/memory"
 	"github.com/flemzord/sclaw/internal/multiagent"
 	"github.com/flemzord/sclaw/internal/provider"
@@ -150,6 +153,121 @@ func wireRouter(
 		return fmt.Errorf("router: at least one provider module is required")
 	}
 
+	factory, err := newFactory(app, appCtx, ids, logger, auditLogger, rateLimiter, defaultProvider, defaultProviderName)
+	if err != nil {
+		return err
+	}
+	globalTools := factory.GlobalTools()
+
+	// Create sub-agent manager and wire it into the factory.
+	loopFactory := multiagent.NewSubAgentLoopFactory(defaultProvider, globalTools)
+	subMgr := subagent.NewManager(subagent.ManagerConfig{
+		Logger:      logger,
+		LoopFactory: loopFactory,
+	})
+	factory.SetSubAgentManager(subMgr)
+
+	// Build group policy from config.
+	var groupPolicy router.GroupPolicy
+	if routerCfg != nil {
+		groupPolicy = router.GroupPolicy{
+			Mode:      router.GroupPolicyMode(routerCfg.GroupPolicy.Mode),
+			Allowlist: routerCfg.GroupPolicy.Allowlist,
+			Denylist:  routerCfg.GroupPolicy.Denylist,
+		}
+	}
+
+	// Create the router.
+	bus := activityBus(appCtx)
+	r, err := router.NewRouter(router.Config{
+		AgentFactory:     factory,
+		ResponseSender:   dispatcher,
+		ChannelLookup:    dispatcher,
+		StreamSender:     dispatcher,
+		GroupPolicy:      groupPolicy,
+		Logger:           logger,
+		RateLimiter:      rateLimiter,
+		HookPipeline:     hookPipeline,
+		HistoryResolver:  factory,
+		SoulResolver:     factory,
+		SkillResolver:    factory,
+		ContextResolver:  factory,
+		WorkspaceHistory: factory,
+		AuditLogger:      auditLogger,
+		OnSessionEnd:     factory.EndSession,
+		Activity:         bus,
+	})
+	if err != nil {
+		return fmt.Errorf("creating router: %w", err)
+	}
+
+	// Tool approvals are prompted in the chat that triggered the call.
+	factory.SetApprovalRequester(r.ApprovalRequester)
+	factory.SetNotifier(r.Notifier)
+
+	// Wire each channel's inbox to the router.
+	for _, ch := range channels {
+		ch.SetInbox(r.Submit)
+	}
+
+	// Append the router to the app lifecycle.
+	app.AppendModule("router", &routerModule{
+		router:      r,
+		factory:     factory,
+		subagentMgr: subMgr,
+		ctx:         context.Background(),
+		logger:      logger,
+		dataDir:     appCtx.DataDir,
+	})
+
+	// Register the session store for the gateway to discover.
+	appCtx.RegisterService("router.sessions", r.Sessions())
+
+	// Register the router so that follow-ups can be submitted to sessions.
+	appCtx.RegisterService("router.router", r)
+
+	// Register the default provider for use by cron jobs (e.g. fact extraction).
+	appCtx.RegisterService("provider.default", defaultProvider)
+
+	// Register factory and dispatcher for prompt cron wiring.
+	appCtx.RegisterService("multiagent.factory", factory)
+	appCtx.RegisterService("workspace.history", factory)
+	appCtx.RegisterService("channel.dispatcher", dispatcher)
+
+	// Publish agents over MCP for the gateway's /mcp endpoint.
+	appCtx.RegisterService("mcp.publisher", mcp.NewPublisher(factory.ForMCP, logger))
+
+	// Register cron CRUD tools for runtime cron management.
+	if err := crontool.RegisterAll(globalTools, crontool.Deps{
+		ReloadFn: func() error {
+			// The cron scheduler is wired after the router: resolve lazily.
+			if svc, ok := appCtx.GetService("cron.reconciler"); ok {
+				if r, ok := svc.(*cron.Reconciler); ok {
+					r.Reconcile()
+				}
+			}
+			return nil
+		},
+	}); err != nil {
+		return fmt.Errorf("registering cron tools: %w", err)
+	}
+
+	logger.Info("router: wired", "channels", len(channels))
+	return nil
+}
+
+// newFactory builds the global tool registry and the agent factory.
+// defaultProvider may be nil when no loop is run, as in ServeMCP.
+func newFactory(
+	app *core.App,
+	appCtx *core.AppContext,
+	ids []string,
+	logger *slog.Logger,
+	auditLogger *security.AuditLogger,
+	rateLimiter *security.RateLimiter,
+	defaultProvider provider.Provider,
+	defaultProviderName string,
+) (*multiagent.Factory, error) {
 	// Resolve multiagent registry; create a default one for single-agent setups.
 	var registry *multiagent.Registry
 	if svc, ok := appCtx.GetService("multiagent.registry"); ok {
@@ -164,12 +282,12 @@ func wireRouter(
 		}
 		multiagent.ResolveDefaults(agents, appCtx.DataDir)
 		if err := multiagent.EnsureDirectories(agents); err != nil {
-			return fmt.Errorf("creating default agent directories: %w", err)
+			return nil, fmt.Errorf("creating default agent directories: %w", err)
 		}
 		var err error
 		registry, err = multiagent.NewRegistry(agents, []string{"default"})
 		if err != nil {
-			return fmt.Errorf("creating default agent registry: %w", err)
+			return nil, fmt.Errorf("creating default agent registry: %w", err)
 		}
 	}
 
@@ -179,10 +297,21 @@ func wireRouter(
 		urlFilter, _ = svc.(*security.URLFilter)
 	}
 
+	// Resolve tool sandbox (optional).
+	var sandboxPolicy security.SandboxPolicy
+	var sandbox security.Sandbox
+	if svc, ok := appCtx.GetService("security.sandbox"); ok {
+		sandbox, _ = svc.(security.Sandbox)
+	}
+	if svc, ok := appCtx.GetService("security.sandbox_policy"); ok {
+		sandboxPolicy, _ = svc.(security.SandboxPolicy)
+	}
+
 	// Build the global tool registry.
 	// First, discover tools from ToolProvider modules (configurable replacements).
 	// Then, register built-in tools only for names not already covered by a module.
 	globalTools := tool.NewRegistry()
+	globalTools.SetSandbox(sandboxPolicy, sandbox)
 
 	coveredTools := make(map[string]bool)
 	for _, id := range ids {
@@ -193,7 +322,7 @@ func wireRouter(
 		if tp, ok := mod.(tool.Provider); ok {
 			for _, t := range tp.Tools() {
 				if err := globalTools.Register(t); err != nil {
-					return fmt.Errorf("registering tool from module %s: %w", id, err)
+					return nil, fmt.Errorf("registering tool from module %s: %w", id, err)
 				}
 				coveredTools[t.Name()] = true
 				logger.Info("router: registered tool from module", "tool", t.Name(), "module", id)
@@ -207,15 +336,12 @@ func wireRouter(
 			continue
 		}
 		if err := globalTools.Register(t); err != nil {
-			return fmt.Errorf("registering built-in tool %s: %w", t.Name(), err)
+			return nil, fmt.Errorf("registering built-in tool %s: %w", t.Name(), err)
 		}
 	}
 
 	// Register config tools (read/modify sclaw.yaml at runtime).
-	var cfgPath string
-	if svc, ok := appCtx.GetService("config.path"); ok {
-		cfgPath, _ = svc.(string)
-	}
+	cfgPath := configPath(appCtx)
 	var redactor *security.Redactor
 	if svc, ok := appCtx.GetService("security.redactor"); ok {
 		redactor, _ = svc.(*security.Redactor)
@@ -233,7 +359,7 @@ func wireRouter(
 				return fmt.Errorf("reload handler not available")
 			},
 		}); err != nil {
-			return fmt.Errorf("registering config tools: %w", err)
+			return nil, fmt.Errorf("registering config tools: %w", err)
 		}
 	}
 
@@ -246,7 +372,7 @@ func wireRouter(
 	// Build the agent factory.
 	globalSkillsDir := filepath.Join(appCtx.DataDir, "skills")
 
-	factory := multiagent.NewFactory(multiagent.FactoryConfig{
+	return multiagent.NewFactory(multiagent.FactoryConfig{
 		Registry:            registry,
 		DefaultProvider:     defaultProvider,
 		DefaultProviderName: defaultProviderName,
@@ -255,88 +381,31 @@ func wireRouter(
 		AuditLogger:         auditLogger,
 		RateLimiter:         rateLimiter,
 		URLFilter:           urlFilter,
+		SandboxPolicy:       sandboxPolicy,
+		Sandbox:             sandbox,
 		SanitizedEnv:        sanitizedEnv,
 		BuiltinSkillsFS:     skills.BuiltinFS,
 		GlobalSkillsDir:     globalSkillsDir,
-	})
-
-	// Create sub-agent manager and wire it into the factory.
-	loopFactory := multiagent.NewSubAgentLoopFactory(defaultProvider, factory.GlobalTools())
-	subMgr := subagent.NewManager(subagent.ManagerConfig{
-		Logger:      logger,
-		LoopFactory: loopFactory,
-	})
-	factory.SetSubAgentManager(subMgr)
-
-	// Build group policy from config.
-	var groupPolicy router.GroupPolicy
-	if routerCfg != nil {
-		groupPolicy = router.GroupPolicy{
-			Mode:      router.GroupPolicyMode(routerCfg.GroupPolicy.Mode),
-			Allowlist: routerCfg.GroupPolicy.Allowlist,
-			Denylist:  routerCfg.GroupPolicy.Denylist,
-		}
-	}
-
-	// Create the router.
-	r, err := router.NewRouter(router.Config{
-		AgentFactory:    factory,
-		ResponseSender:  dispatcher,
-		ChannelLookup:   dispatcher,
-		StreamSender:    dispatcher,
-		GroupPolicy:     groupPolicy,
-		Logger:          logger,
-		RateLimiter:     rateLimiter,
-		HookPipeline:    hookPipeline,
-		HistoryResolver: factory,
-		SoulResolver:    factory,
-		SkillResolver:   factory,
-	})
-	if err != nil {
-		return fmt.Errorf("creating router: %w", err)
-	}
+		Activity:            activityBus(appCtx),
+	}), nil
+}
 
-	// Wire each channel's inbox to the router.
-	for _, ch := range channels {
-		ch.SetInbox(r.Submit)
+// activityBus returns the activity bus registered by Run, or nil.
+func activityBus(appCtx *core.AppContext) *activity.Bus {
+	if svc, ok := appCtx.GetService("activity.bus"); ok {
+		bus, _ := svc.(*activity.Bus)
+		return bus
 	}
+	return nil
+}
 
-	// Append the router to the app lifecycle.
-	app.AppendModule("router", &routerModule{
-		router:      r,
-		factory:     factory,
-		subagentMgr: subMgr,
-		ctx:         context.Background(),
-		logger:      logger,
-		dataDir:     appCtx.DataDir,
-	})
-
-	// Register the session store for the gateway to discover.
-	appCtx.RegisterService("router.sessions", r.Sessions())
-
-	// Register the default provider for use by cron jobs (e.g. fact extraction).
-	appCtx.RegisterService("provider.default", defaultProvider)
-
-	// Register factory and dispatcher for prompt cron wiring.
-	appCtx.RegisterService("multiagent.factory", factory)
-	appCtx.RegisterService("channel.dispatcher", dispatcher)
-
-	// Register cron CRUD tools for runtime cron management.
-	if err := crontool.RegisterAll(globalTools, crontool.Deps{
-		ReloadFn: func() error {
-			if svc, ok := appCtx.GetService("reload.handler"); ok {
-				if h, ok := svc.(*reload.Handler); ok {
-					return h.HandleReload(context.Background(), cfgPath)
-				}
-			}
-			return nil
-		},
-	}); err != nil {
-		return fmt.Errorf("registering cron tools: %w", err)
+// configPath returns the configuration file path registered by Run.
+func configPath(appCtx *core.AppContext) string {
+	if svc, ok := appCtx.GetService("config.path"); ok {
+		path, _ := svc.(string)
+		return path
 	}
-
-	logger.Info("router: wired", "channels", len(channels))
-	return nil
+	return ""
 }
 
 // rangeableSessionStore is the subset of router.SessionStore needed to iterate
@@ -371,18 +440,50 @@ func (a *cronOutputAdapter) SendCronOutput(ctx context.Context, ch, chatID, text
 	})
 }
 
+// followupAdapter bridges router.Router to cron.SessionSubmitter. The
+// follow-up enters the router as a message from the user who scheduled it.
+type followupAdapter struct {
+	router *router.Router
+}
+
+func (a *followupAdapter) SubmitToSession(_ context.Context, s cron.PromptCronSession, id, text string) error {
+	chatType := message.ChatType(s.ChatType)
+	if chatType == "" {
+		chatType = message.ChatDM
+	}
+	msg := message.InboundMessage{
+		ID:        id,
+		Timestamp: time.Now(),
+		Channel:   s.Channel,
+		Sender:    message.Sender{ID: s.SenderID},
+		Chat:      message.Chat{ID: s.ChatID, Type: chatType},
+		ThreadID:  s.ThreadID,
+		Blocks:    []message.ContentBlock{message.NewTextBlock(text)},
+	}
+	if chatType == message.ChatGroup {
+		// Addressed to the agent, so group mention policies let it through.
+		msg.Mentions = &message.Mentions{IsMentioned: true}
+	}
+	return a.router.Submit(msg)
+}
+
 // schedulerModule wraps a *cron.Scheduler to satisfy core.Module, core.Starter,
 // core.Stopper, and core.Reloader, so the scheduler participates in the App lifecycle.
 type schedulerModule struct {
 	scheduler    *cron.Scheduler
+	prompts      *cron.Reconciler
+	events       *cron.EventRunner
+	webhooks     *gateway.WebhookDispatcher
 	logger       *slog.Logger
 	dataDir      string
 	sessionStore cron.SessionStore
 	ranger       cron.SessionRanger
 	factory      *multiagent.Factory
 	extractor    memory.FactExtractor
-	loopBuilder  cron.LoopBuilder
-	outputSender cron.OutputSender
+
+	// agentJobs are the names of the per-agent background jobs, replaced
+	// on reload. Prompt crons are tracked by prompts.
+	agentJobs []string
 }
 
 func (m *schedulerModule) ModuleInfo() core.ModuleInfo {
@@ -390,16 +491,28 @@ func (m *schedulerModule) ModuleInfo() core.ModuleInfo {
 }
 
 func (m *schedulerModule) Start() error {
-	return m.scheduler.Start()
+	if err := m.scheduler.Start(); err != nil {
+		return err
+	}
+	if m.prompts != nil {
+		m.prompts.Start(context.Background())
+	}
+	return nil
 }
 
 func (m *schedulerModule) Stop(ctx context.Context) error {
+	if m.prompts != nil {
+		m.prompts.Stop()
+	}
+	if m.events != nil {
+		m.events.Stop()
+	}
 	return m.scheduler.Stop(ctx)
 }
 
-// Reload stops the current scheduler, creates a new one with jobs derived
-// from the new agent configs, and starts it. If AgentConfigs is nil, reload
-// is a no-op.
+// Reload replaces the per-agent jobs with those derived from the new agent
+// configs and reconciles prompt crons, without restarting the scheduler.
+// If AgentConfigs is nil, reload is a no-op.
 func (m *schedulerModule) Reload(ctx *core.AppContext) error {
 	agents := ctx.AgentConfigs()
 	if agents == nil {
@@ -418,22 +531,41 @@ func (m *schedulerModule) Reload(ctx *core.AppContext) error {
 		return fmt.Errorf("cron: creating registry: %w", err)
 	}
 
-	// Stop old scheduler with a 10s timeout.
-	stopCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-	if err := m.scheduler.Stop(stopCtx); err != nil {
-		m.logger.Error("cron: error stopping scheduler during reload", "error", err)
+	for _, name := range m.agentJobs {
+		m.scheduler.RemoveJob(name)
+	}
+	m.agentJobs = nil
+
+	if err := m.registerAgentJobs(registry); err != nil {
+		return err
 	}
 
-	// Create and populate new scheduler.
-	newScheduler := cron.NewScheduler(m.logger)
+	m.logger.Info("cron: scheduler reloaded", "agents", len(parsed))
+	return nil
+}
 
+// registerAgentJobs registers the background jobs of every agent in the
+// registry, each using the agent's CronConfig, and reconciles their prompt
+// crons and webhook triggers.
+func (m *schedulerModule) registerAgentJobs(registry *multiagent.Registry) error {
+	var promptAgents []cron.PromptCronAgent
+	var eventTriggers []*cron.EventTrigger
+	webhookSecrets := make(map[string]string) // source → HMAC secret
 	for _, agentID := range registry.AgentIDs() {
 		cfg, _ := registry.AgentConfig(agentID)
 		cronCfg := cfg.Cron
 
+		for _, wh := range cfg.Webhooks {
+			trigger, err := buildEventTrigger(agentID, cfg.DataDir, wh)
+			if err != nil {
+				return fmt.Errorf("cron: webhook %q of agent %s: %w", wh.Source, agentID, err)
+			}
+			eventTriggers = append(eventTriggers, trigger)
+			webhookSecrets[wh.Source] = wh.Secret
+		}
+
 		if m.sessionStore != nil {
-			if err := newScheduler.RegisterJob(&cron.SessionCleanupJob{
+			if err := m.registerAgentJob(&cron.SessionCleanupJob{
 				Store:        m.sessionStore,
 				MaxIdle:      cronCfg.SessionCleanup.MaxIdleOrDefault(),
 				Logger:       m.logger,
@@ -451,7 +583,7 @@ func (m *schedulerModule) Reload(ctx *core.AppContext) error {
 			agentFactStore = m.factory.ResolveFactStore(agentID)
 		}
 
-		if err := newScheduler.RegisterJob(&cron.MemoryExtractionJob{
+		if err := m.registerAgentJob(&cron.MemoryExtractionJob{
 			Logger:       m.logger,
 			AgentID:      agentID,
 			ScheduleExpr: cronCfg.MemoryExtraction.ScheduleOrDefault(),
@@ -463,7 +595,7 @@ func (m *schedulerModule) Reload(ctx *core.AppContext) error {
 			return fmt.Errorf("cron: registering memory extraction for agent %s: %w", agentID, err)
 		}
 
-		if err := newScheduler.RegisterJob(&cron.MemoryCompactionJob{
+		if err := m.registerAgentJob(&cron.MemoryCompactionJob{
 			Logger:       m.logger,
 			AgentID:      agentID,
 			ScheduleExpr: cronCfg.MemoryCompaction.ScheduleOrDefault(),
@@ -471,35 +603,88 @@ func (m *schedulerModule) Reload(ctx *core.AppContext) error {
 			return fmt.Errorf("cron: registering memory compaction for agent %s: %w", agentID, err)
 		}
 
-		// Register prompt crons for this agent.
-		if m.loopBuilder != nil {
-			cronsDir := cron.CronsDir(cfg.DataDir)
-			defs, errs := cron.ScanPromptCrons(cronsDir)
-			for _, e := range errs {
-				m.logger.Error("cron: invalid prompt cron", "agent", agentID, "error", e)
-			}
-			for _, def := range defs {
-				if err := newScheduler.RegisterJob(&cron.PromptJob{
-					Def:     def,
-					AgentID: agentID,
-					Builder: m.loopBuilder,
-					Sender:  m.outputSender,
-					DataDir: cfg.DataDir,
-					Logger:  m.logger,
-				}); err != nil {
-					m.logger.Error("cron: registering prompt cron",
-						"agent", agentID, "cron", def.Name, "error", err)
-				}
+		promptAgent := cron.PromptCronAgent{
+			ID:       agentID,
+			DataDir:  cfg.DataDir,
+			Timezone: cfg.Timezone,
+		}
+		if alert := cronCfg.Alert; alert != nil {
+			promptAgent.Alert = &cron.FailureAlert{
+				Channel: alert.Channel,
+				ChatID:  alert.ChatID,
+				After:   alert.AfterOrDefault(),
 			}
 		}
+		promptAgents = append(promptAgents, promptAgent)
 	}
 
-	if err := newScheduler.Start(); err != nil {
-		return fmt.Errorf("cron: starting new scheduler: %w", err)
+	// Prompt crons are only scheduled when agent loops can be built.
+	if m.prompts != nil {
+		m.prompts.SetAgents(promptAgents)
 	}
+	m.setEventTriggers(eventTriggers, webhookSecrets)
+	return nil
+}
 
-	m.scheduler = newScheduler
-	m.logger.Info("cron: scheduler reloaded", "agents", len(parsed))
+// setEventTriggers replaces the webhook triggers and keeps the gateway
+// webhook sources in sync with them. A trigger without a secret is never
+// exposed: anyone could otherwise run the agent.
+func (m *schedulerModule) setEventTriggers(triggers []*cron.EventTrigger, secrets map[string]string) {
+	if m.events == nil {
+		return
+	}
+	if m.webhooks == nil {
+		if len(triggers) > 0 {
+			m.logger.Warn("cron: webhook triggers configured but the gateway is not loaded")
+		}
+		return
+	}
+
+	for _, source := range m.events.SetTriggers(triggers) {
+		m.webhooks.Unregister(source)
+	}
+	for _, t := range triggers {
+		if err := m.webhooks.RegisterSigned(t.Source, m.events, secrets[t.Source]); err != nil {
+			m.logger.Error("cron: webhook trigger not registered", "source", t.Source, "agent", t.AgentID, "error", err)
+			continue
+		}
+		m.logger.Info("cron: webhook trigger registered", "source", t.Source, "agent", t.AgentID)
+	}
+}
+
+// buildEventTrigger converts an agent's webhook config into a trigger.
+func buildEventTrigger(agentID, dataDir string, wh multiagent.WebhookTrigger) (*cron.EventTrigger, error) {
+	prompt, err := cron.ParseEventPrompt(wh.Source, wh.Prompt)
+	if err != nil {
+		return nil, fmt.Errorf("parsing prompt: %w", err)
+	}
+	trigger := &cron.EventTrigger{
+		Source:  wh.Source,
+		AgentID: agentID,
+		Prompt:  prompt,
+		Tools:   wh.Tools,
+		Loop:    cron.PromptCronLoop{Timeout: wh.Timeout},
+		DataDir: dataDir,
+	}
+	if out := wh.Output; out != nil {
+		trigger.Output = &cron.PromptCronOutput{
+			Channel: out.Channel,
+			ChatID:  out.ChatID,
+			Notify:  out.Notify,
+		}
+	}
+	if err := trigger.Validate(); err != nil {
+		return nil, err
+	}
+	return trigger, nil
+}
+
+// registerAgentJob registers a job to be replaced on reload.
+func (m *schedulerModule) registerAgentJob(job cron.Job) error {
+	if err := m.scheduler.RegisterJob(job); err != nil {
+		return err
+	}
+	m.agentJobs = append(m.agentJobs, job.Name())
 	return nil
 }
 
@@ -558,78 +743,53 @@ func wireCron(
 		}
 	}
 
+	var submitter cron.SessionSubmitter
+	if svc, ok := appCtx.GetService("router.router"); ok {
+		if r, ok := svc.(*router.Router); ok {
+			submitter = &followupAdapter{router: r}
+		}
+	}
+
 	// Create a CronTrigger so the gateway can list and fire prompt crons.
 	cronTrigger := cron.NewTrigger()
 
-	if registry != nil {
-		// Per-agent jobs: one set of jobs per registered agent.
-		for _, agentID := range registry.AgentIDs() {
-			cfg, _ := registry.AgentConfig(agentID)
-			cronCfg := cfg.Cron
-
-			if sessionStore != nil {
-				if err := s.RegisterJob(&cron.SessionCleanupJob{
-					Store:        sessionStore,
-					MaxIdle:      cronCfg.SessionCleanup.MaxIdleOrDefault(),
-					Logger:       logger,
-					AgentID:      agentID,
-					ScheduleExpr: cronCfg.SessionCleanup.ScheduleOrDefault(),
-				}); err != nil {
-					return fmt.Errorf("cron: registering session cleanup for agent %s: %w", agentID, err)
-				}
-			}
-
-			var agentHistory memory.HistoryStore
-			var agentFactStore memory.Store
-			if factory != nil {
-				agentHistory = factory.ResolveHistory(agentID)
-				agentFactStore = factory.ResolveFactStore(agentID)
-			}
-
-			if err := s.RegisterJob(&cron.MemoryExtractionJob{
-				Logger:       logger,
-				AgentID:      agentID,
-				ScheduleExpr: cronCfg.MemoryExtraction.ScheduleOrDefault(),
-				Sessions:     ranger,
-				History:      agentHistory,
-				Store:        agentFactStore,
-				Extractor:    extractor,
-			}); err != nil {
-				return fmt.Errorf("cron: registering memory extraction for agent %s: %w", agentID, err)
-			}
+	m := &schedulerModule{
+		scheduler:    s,
+		logger:       logger,
+		dataDir:      appCtx.DataDir,
+		sessionStore: sessionStore,
+		ranger:       ranger,
+		factory:      factory,
+		extractor:    extractor,
+	}
 
-			if err := s.RegisterJob(&cron.MemoryCompactionJob{
-				Logger:       logger,
-				AgentID:      agentID,
-				ScheduleExpr: cronCfg.MemoryCompaction.ScheduleOrDefault(),
-			}); err != nil {
-				return fmt.Errorf("cron: registering memory compaction for agent %s: %w", agentID, err)
-			}
+	// Prompt crons follow their definition files on disk.
+	if loopBuilder != nil {
+		m.prompts = cron.NewReconciler(cron.ReconcilerConfig{
+			Scheduler: s,
+			Trigger:   cronTrigger,
+			Builder:   loopBuilder,
+			Sender:    outputSender,
+			Submitter: submitter,
+			Logger:    logger,
+		})
+		appCtx.RegisterService("cron.reconciler", m.prompts)
+
+		// Webhook triggers run prompts like crons, on gateway events.
+		m.events = cron.NewEventRunner(cron.EventRunnerConfig{
+			Builder: loopBuilder,
+			Sender:  outputSender,
+			Logger:  logger,
+		})
+		if svc, ok := appCtx.GetService("gateway.webhook_dispatcher"); ok {
+			m.webhooks, _ = svc.(*gateway.WebhookDispatcher)
+		}
+	}
 
-			// Register prompt crons for this agent.
-			if loopBuilder != nil {
-				cronsDir := cron.CronsDir(cfg.DataDir)
-				defs, errs := cron.ScanPromptCrons(cronsDir)
-				for _, e := range errs {
-					logger.Error("cron: invalid prompt cron", "agent", agentID, "error", e)
-				}
-				for _, def := range defs {
-					job := &cron.PromptJob{
-						Def:     def,
-						AgentID: agentID,
-						Builder: loopBuilder,
-						Sender:  outputSender,
-						DataDir: cfg.DataDir,
-						Logger:  logger,
-					}
-					if err := s.RegisterJob(job); err != nil {
-						logger.Error("cron: registering prompt cron",
-							"agent", agentID, "cron", def.Name, "error", err)
-					} else {
-						cronTrigger.Register(job)
-					}
-				}
-			}
+	if registry != nil {
+		// Per-agent jobs: one set of jobs per registered agent.
+		if err := m.registerAgentJobs(registry); err != nil {
+			return err
 		}
 	} else {
 		// Single-agent fallback: global jobs with defaults.
@@ -666,19 +826,11 @@ func wireCron(
 	}
 
 	// Register CronTrigger as a service for the gateway to discover.
+	cronTrigger.SetScheduler(s)
 	appCtx.RegisterService("cron.trigger", cronTrigger)
+	appCtx.RegisterService("cron.scheduler", s)
 
-	app.AppendModule("cron", &schedulerModule{
-		scheduler:    s,
-		logger:       logger,
-		dataDir:      appCtx.DataDir,
-		sessionStore: sessionStore,
-		ranger:       ranger,
-		factory:      factory,
-		extractor:    extractor,
-		loopBuilder:  loopBuilder,
-		outputSender: outputSender,
-	})
+	app.AppendModule("cron", m)
 	logger.Info("cron: wired")
 	return nil
 }